@@ -8,6 +8,8 @@ import (
 	_ "github.com/ncw/rclone/backend/b2"
 	_ "github.com/ncw/rclone/backend/box"
 	_ "github.com/ncw/rclone/backend/cache"
+	_ "github.com/ncw/rclone/backend/chunker"
+	_ "github.com/ncw/rclone/backend/compress"
 	_ "github.com/ncw/rclone/backend/crypt"
 	_ "github.com/ncw/rclone/backend/drive"
 	_ "github.com/ncw/rclone/backend/dropbox"
@@ -150,14 +150,18 @@ type Fs struct {
 
 // Object describes a azure object
 type Object struct {
-	fs         *Fs                   // what this object is part of
-	remote     string                // The remote path
-	modTime    time.Time             // The modified time of the object if known
-	md5        string                // MD5 hash if known
-	size       int64                 // Size of the object
-	mimeType   string                // Content-Type of the object
-	accessTier azblob.AccessTierType // Blob Access Tier
-	meta       map[string]string     // blob metadata
+	fs                 *Fs                   // what this object is part of
+	remote             string                // The remote path
+	modTime            time.Time             // The modified time of the object if known
+	md5                string                // MD5 hash if known
+	size               int64                 // Size of the object
+	mimeType           string                // Content-Type of the object
+	cacheControl       string                // Cache-Control of the object
+	contentDisposition string                // Content-Disposition of the object
+	contentEncoding    string                // Content-Encoding of the object
+	contentLanguage    string                // Content-Language of the object
+	accessTier         azblob.AccessTierType // Blob Access Tier
+	meta               map[string]string     // blob metadata
 }
 
 // ------------------------------------------------------------
@@ -309,6 +313,7 @@ func (f *Fs) newPipeline(c azblob.Credential, o azblob.PipelineOptions) pipeline
 
 // NewFs contstructs an Fs from the path, container:path
 func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
+	ctx := context.Background()
 	// Parse config into Options struct
 	opt := new(Options)
 	err := configstruct.Set(m, opt)
@@ -349,11 +354,13 @@ func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
 		root:        directory,
 		pacer:       pacer.New().SetMinSleep(minSleep).SetMaxSleep(maxSleep).SetDecayConstant(decayConstant).SetPacer(pacer.S3Pacer),
 		uploadToken: pacer.NewTokenDispenser(fs.Config.Transfers),
-		client:      fshttp.NewClient(fs.Config),
+		client:      fshttp.NewClientForBackend(fs.Config, "azureblob"),
 	}
 	f.features = (&fs.Features{
 		ReadMimeType:  true,
 		WriteMimeType: true,
+		ReadMetadata:  true,
+		WriteMetadata: true,
 		BucketBased:   true,
 		SetTier:       true,
 		GetTier:       true,
@@ -415,7 +422,7 @@ func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
 		} else {
 			f.root += "/"
 		}
-		_, err := f.NewObject(remote)
+		_, err := f.NewObject(ctx, remote)
 		if err != nil {
 			if err == fs.ErrorObjectNotFound {
 				// File doesn't exist so return old f
@@ -433,7 +440,7 @@ func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
 // Return an Object from a path
 //
 // If it can't be found it returns the error fs.ErrorObjectNotFound.
-func (f *Fs) newObjectWithInfo(remote string, info *azblob.BlobItem) (fs.Object, error) {
+func (f *Fs) newObjectWithInfo(ctx context.Context, remote string, info *azblob.BlobItem) (fs.Object, error) {
 	o := &Object{
 		fs:     f,
 		remote: remote,
@@ -444,7 +451,7 @@ func (f *Fs) newObjectWithInfo(remote string, info *azblob.BlobItem) (fs.Object,
 			return nil, err
 		}
 	} else {
-		err := o.readMetaData() // reads info and headers, returning an error
+		err := o.readMetaData(ctx) // reads info and headers, returning an error
 		if err != nil {
 			return nil, err
 		}
@@ -454,8 +461,8 @@ func (f *Fs) newObjectWithInfo(remote string, info *azblob.BlobItem) (fs.Object,
 
 // NewObject finds the Object at remote.  If it can't be found
 // it returns the error fs.ErrorObjectNotFound.
-func (f *Fs) NewObject(remote string) (fs.Object, error) {
-	return f.newObjectWithInfo(remote, nil)
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	return f.newObjectWithInfo(ctx, remote, nil)
 }
 
 // getBlobReference creates an empty blob reference with no metadata
@@ -481,7 +488,7 @@ type listFn func(remote string, object *azblob.BlobItem, isDirectory bool) error
 // the container and root supplied
 //
 // dir is the starting directory, "" for root
-func (f *Fs) list(dir string, recurse bool, maxResults uint, fn listFn) error {
+func (f *Fs) list(ctx context.Context, dir string, recurse bool, maxResults uint, fn listFn) error {
 	f.containerOKMu.Lock()
 	deleted := f.containerDeleted
 	f.containerOKMu.Unlock()
@@ -508,7 +515,6 @@ func (f *Fs) list(dir string, recurse bool, maxResults uint, fn listFn) error {
 		Prefix:     root,
 		MaxResults: int32(maxResults),
 	}
-	ctx := context.Background()
 	for marker := (azblob.Marker{}); marker.NotDone(); {
 		var response *azblob.ListBlobsHierarchySegmentResponse
 		err := f.pacer.Call(func() (bool, error) {
@@ -568,12 +574,12 @@ func (f *Fs) list(dir string, recurse bool, maxResults uint, fn listFn) error {
 }
 
 // Convert a list item into a DirEntry
-func (f *Fs) itemToDirEntry(remote string, object *azblob.BlobItem, isDirectory bool) (fs.DirEntry, error) {
+func (f *Fs) itemToDirEntry(ctx context.Context, remote string, object *azblob.BlobItem, isDirectory bool) (fs.DirEntry, error) {
 	if isDirectory {
 		d := fs.NewDir(remote, time.Time{})
 		return d, nil
 	}
-	o, err := f.newObjectWithInfo(remote, object)
+	o, err := f.newObjectWithInfo(ctx, remote, object)
 	if err != nil {
 		return nil, err
 	}
@@ -591,9 +597,9 @@ func (f *Fs) markContainerOK() {
 }
 
 // listDir lists a single directory
-func (f *Fs) listDir(dir string) (entries fs.DirEntries, err error) {
-	err = f.list(dir, false, f.opt.ListChunkSize, func(remote string, object *azblob.BlobItem, isDirectory bool) error {
-		entry, err := f.itemToDirEntry(remote, object, isDirectory)
+func (f *Fs) listDir(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	err = f.list(ctx, dir, false, f.opt.ListChunkSize, func(remote string, object *azblob.BlobItem, isDirectory bool) error {
+		entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
 		if err != nil {
 			return err
 		}
@@ -611,11 +617,11 @@ func (f *Fs) listDir(dir string) (entries fs.DirEntries, err error) {
 }
 
 // listContainers returns all the containers to out
-func (f *Fs) listContainers(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) listContainers(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	if dir != "" {
 		return nil, fs.ErrorListBucketRequired
 	}
-	err = f.listContainersToFn(func(container *azblob.ContainerItem) error {
+	err = f.listContainersToFn(ctx, func(container *azblob.ContainerItem) error {
 		d := fs.NewDir(container.Name, container.Properties.LastModified)
 		entries = append(entries, d)
 		return nil
@@ -635,11 +641,11 @@ func (f *Fs) listContainers(dir string) (entries fs.DirEntries, err error) {
 //
 // This should return ErrDirNotFound if the directory isn't
 // found.
-func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	if f.container == "" {
-		return f.listContainers(dir)
+		return f.listContainers(ctx, dir)
 	}
-	return f.listDir(dir)
+	return f.listDir(ctx, dir)
 }
 
 // ListR lists the objects and directories of the Fs starting
@@ -658,13 +664,13 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 //
 // Don't implement this unless you have a more efficient way
 // of listing recursively that doing a directory traversal.
-func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
+func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
 	if f.container == "" {
 		return fs.ErrorListBucketRequired
 	}
 	list := walk.NewListRHelper(callback)
-	err = f.list(dir, true, f.opt.ListChunkSize, func(remote string, object *azblob.BlobItem, isDirectory bool) error {
-		entry, err := f.itemToDirEntry(remote, object, isDirectory)
+	err = f.list(ctx, dir, true, f.opt.ListChunkSize, func(remote string, object *azblob.BlobItem, isDirectory bool) error {
+		entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
 		if err != nil {
 			return err
 		}
@@ -682,11 +688,10 @@ func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
 type listContainerFn func(*azblob.ContainerItem) error
 
 // listContainersToFn lists the containers to the function supplied
-func (f *Fs) listContainersToFn(fn listContainerFn) error {
+func (f *Fs) listContainersToFn(ctx context.Context, fn listContainerFn) error {
 	params := azblob.ListContainersSegmentOptions{
 		MaxResults: int32(f.opt.ListChunkSize),
 	}
-	ctx := context.Background()
 	for marker := (azblob.Marker{}); marker.NotDone(); {
 		var response *azblob.ListContainersSegmentResponse
 		err := f.pacer.Call(func() (bool, error) {
@@ -715,17 +720,17 @@ func (f *Fs) listContainersToFn(fn listContainerFn) error {
 // Copy the reader in to the new object which is returned
 //
 // The new object may have been created if an error is returned
-func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
 	// Temporary Object under construction
 	fs := &Object{
 		fs:     f,
 		remote: src.Remote(),
 	}
-	return fs, fs.Update(in, src, options...)
+	return fs, fs.Update(ctx, in, src, options...)
 }
 
 // Mkdir creates the container if it doesn't exist
-func (f *Fs) Mkdir(dir string) error {
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
 	f.containerOKMu.Lock()
 	defer f.containerOKMu.Unlock()
 	if f.containerOK {
@@ -734,7 +739,6 @@ func (f *Fs) Mkdir(dir string) error {
 
 	// now try to create the container
 	err := f.pacer.Call(func() (bool, error) {
-		ctx := context.Background()
 		_, err := f.cntURL.Create(ctx, azblob.Metadata{}, azblob.PublicAccessNone)
 		if err != nil {
 			if storageErr, ok := err.(azblob.StorageError); ok {
@@ -763,9 +767,9 @@ func (f *Fs) Mkdir(dir string) error {
 }
 
 // isEmpty checks to see if a given directory is empty and returns an error if not
-func (f *Fs) isEmpty(dir string) (err error) {
+func (f *Fs) isEmpty(ctx context.Context, dir string) (err error) {
 	empty := true
-	err = f.list(dir, true, 1, func(remote string, object *azblob.BlobItem, isDirectory bool) error {
+	err = f.list(ctx, dir, true, 1, func(remote string, object *azblob.BlobItem, isDirectory bool) error {
 		empty = false
 		return nil
 	})
@@ -780,11 +784,10 @@ func (f *Fs) isEmpty(dir string) (err error) {
 
 // deleteContainer deletes the container.  It can delete a full
 // container so use isEmpty if you don't want that.
-func (f *Fs) deleteContainer() error {
+func (f *Fs) deleteContainer(ctx context.Context) error {
 	f.containerOKMu.Lock()
 	defer f.containerOKMu.Unlock()
 	options := azblob.ContainerAccessConditions{}
-	ctx := context.Background()
 	err := f.pacer.Call(func() (bool, error) {
 		_, err := f.cntURL.GetProperties(ctx, azblob.LeaseAccessConditions{})
 		if err == nil {
@@ -812,15 +815,15 @@ func (f *Fs) deleteContainer() error {
 // Rmdir deletes the container if the fs is at the root
 //
 // Returns an error if it isn't empty
-func (f *Fs) Rmdir(dir string) error {
-	err := f.isEmpty(dir)
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
+	err := f.isEmpty(ctx, dir)
 	if err != nil {
 		return err
 	}
 	if f.root != "" || dir != "" {
 		return nil
 	}
-	return f.deleteContainer()
+	return f.deleteContainer(ctx)
 }
 
 // Precision of the remote
@@ -834,13 +837,13 @@ func (f *Fs) Hashes() hash.Set {
 }
 
 // Purge deletes all the files and directories including the old versions.
-func (f *Fs) Purge() error {
+func (f *Fs) Purge(ctx context.Context) error {
 	dir := "" // forward compat!
 	if f.root != "" || dir != "" {
 		// Delegate to caller if not root container
 		return fs.ErrorCantPurge
 	}
-	return f.deleteContainer()
+	return f.deleteContainer(ctx)
 }
 
 // Copy src to this remote using server side copy operations.
@@ -852,8 +855,8 @@ func (f *Fs) Purge() error {
 // Will only be called if src.Fs().Name() == f.Name()
 //
 // If it isn't possible then return fs.ErrorCantCopy
-func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
-	err := f.Mkdir("")
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	err := f.Mkdir(ctx, "")
 	if err != nil {
 		return nil, err
 	}
@@ -871,7 +874,6 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 	}
 
 	options := azblob.BlobAccessConditions{}
-	ctx := context.Background()
 	var startCopy *azblob.BlobStartCopyFromURLResponse
 
 	err = f.pacer.Call(func() (bool, error) {
@@ -892,7 +894,7 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 		copyStatus = getMetadata.CopyStatus()
 	}
 
-	return f.NewObject(remote)
+	return f.NewObject(ctx, remote)
 }
 
 // ------------------------------------------------------------
@@ -916,7 +918,7 @@ func (o *Object) Remote() string {
 }
 
 // Hash returns the MD5 of an object returning a lowercase hex string
-func (o *Object) Hash(t hash.Type) (string, error) {
+func (o *Object) Hash(ctx context.Context, t hash.Type) (string, error) {
 	if t != hash.MD5 {
 		return "", hash.ErrUnsupported
 	}
@@ -964,6 +966,10 @@ func (o *Object) decodeMetaDataFromPropertiesResponse(info *azblob.BlobGetProper
 	// this as base64 encoded string.
 	o.md5 = base64.StdEncoding.EncodeToString(info.ContentMD5())
 	o.mimeType = info.ContentType()
+	o.cacheControl = info.CacheControl()
+	o.contentDisposition = info.ContentDisposition()
+	o.contentEncoding = info.ContentEncoding()
+	o.contentLanguage = info.ContentLanguage()
 	o.size = info.ContentLength()
 	o.modTime = time.Time(info.LastModified())
 	o.accessTier = azblob.AccessTierType(info.AccessTier())
@@ -977,6 +983,10 @@ func (o *Object) decodeMetaDataFromBlob(info *azblob.BlobItem) (err error) {
 	// this as base64 encoded string.
 	o.md5 = base64.StdEncoding.EncodeToString(info.Properties.ContentMD5)
 	o.mimeType = *info.Properties.ContentType
+	o.cacheControl = stringValue(info.Properties.CacheControl)
+	o.contentDisposition = stringValue(info.Properties.ContentDisposition)
+	o.contentEncoding = stringValue(info.Properties.ContentEncoding)
+	o.contentLanguage = stringValue(info.Properties.ContentLanguage)
 	o.size = *info.Properties.ContentLength
 	o.modTime = info.Properties.LastModified
 	o.accessTier = info.Properties.AccessTier
@@ -1001,7 +1011,7 @@ func (o *Object) clearMetaData() {
 //  o.modTime
 //  o.size
 //  o.md5
-func (o *Object) readMetaData() (err error) {
+func (o *Object) readMetaData(ctx context.Context) (err error) {
 	if !o.modTime.IsZero() {
 		return nil
 	}
@@ -1009,7 +1019,6 @@ func (o *Object) readMetaData() (err error) {
 
 	// Read metadata (this includes metadata)
 	options := azblob.BlobAccessConditions{}
-	ctx := context.Background()
 	var blobProperties *azblob.BlobGetPropertiesResponse
 	err = o.fs.pacer.Call(func() (bool, error) {
 		blobProperties, err = blob.GetProperties(ctx, options)
@@ -1054,12 +1063,12 @@ func (o *Object) parseTimeString(timeString string) (err error) {
 // LastModified returned in the http headers
 func (o *Object) ModTime() (result time.Time) {
 	// The error is logged in readMetaData
-	_ = o.readMetaData()
+	_ = o.readMetaData(context.TODO())
 	return o.modTime
 }
 
 // SetModTime sets the modification time of the local fs object
-func (o *Object) SetModTime(modTime time.Time) error {
+func (o *Object) SetModTime(ctx context.Context, modTime time.Time) error {
 	// Make sure o.meta is not nil
 	if o.meta == nil {
 		o.meta = make(map[string]string, 1)
@@ -1068,7 +1077,6 @@ func (o *Object) SetModTime(modTime time.Time) error {
 	o.meta[modTimeKey] = modTime.Format(timeFormatOut)
 
 	blob := o.getBlobReference()
-	ctx := context.Background()
 	err := o.fs.pacer.Call(func() (bool, error) {
 		_, err := blob.SetMetadata(ctx, o.meta, azblob.BlobAccessConditions{})
 		return o.fs.shouldRetry(err)
@@ -1086,7 +1094,7 @@ func (o *Object) Storable() bool {
 }
 
 // Open an object for read
-func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.ReadCloser, err error) {
 	// Offset and Count for range download
 	var offset int64
 	var count int64
@@ -1110,7 +1118,6 @@ func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
 		}
 	}
 	blob := o.getBlobReference()
-	ctx := context.Background()
 	ac := azblob.BlobAccessConditions{}
 	var dowloadResponse *azblob.DownloadResponse
 	err = o.fs.pacer.Call(func() (bool, error) {
@@ -1154,7 +1161,7 @@ type readSeeker struct {
 // uploadMultipart uploads a file using multipart upload
 //
 // Write a larger blob, using CreateBlockBlob, PutBlock, and PutBlockList.
-func (o *Object) uploadMultipart(in io.Reader, size int64, blob *azblob.BlobURL, httpHeaders *azblob.BlobHTTPHeaders) (err error) {
+func (o *Object) uploadMultipart(ctx context.Context, in io.Reader, size int64, blob *azblob.BlobURL, httpHeaders *azblob.BlobHTTPHeaders) (err error) {
 	// Calculate correct chunkSize
 	chunkSize := int64(o.fs.opt.ChunkSize)
 	var totalParts int64
@@ -1204,7 +1211,6 @@ func (o *Object) uploadMultipart(in io.Reader, size int64, blob *azblob.BlobURL,
 
 	// Get BlockBlobURL, we will use default pipeline here
 	blockBlobURL := blob.ToBlockBlobURL()
-	ctx := context.Background()
 	ac := azblob.LeaseAccessConditions{} // Use default lease access conditions
 
 	// unwrap the accounting from the input, we use wrap to put it
@@ -1299,8 +1305,8 @@ outer:
 // Update the object with the contents of the io.Reader, modTime and size
 //
 // The new object may have been created if an error is returned
-func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (err error) {
-	err = o.fs.Mkdir("")
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (err error) {
+	err = o.fs.Mkdir(ctx, "")
 	if err != nil {
 		return err
 	}
@@ -1314,10 +1320,30 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 	blob := o.getBlobReference()
 	httpHeaders := azblob.BlobHTTPHeaders{}
 	httpHeaders.ContentType = fs.MimeType(o)
+
+	// Set the user metadata and headers if passed in
+	for k, v := range fs.MetadataFromOptions(options) {
+		switch k {
+		case modTimeKey:
+			// set by rclone
+		case "content-type":
+			httpHeaders.ContentType = v
+		case "cache-control":
+			httpHeaders.CacheControl = v
+		case "content-disposition":
+			httpHeaders.ContentDisposition = v
+		case "content-encoding":
+			httpHeaders.ContentEncoding = v
+		case "content-language":
+			httpHeaders.ContentLanguage = v
+		default:
+			o.meta[k] = v
+		}
+	}
 	// Multipart upload doesn't support MD5 checksums at put block calls, hence calculate
 	// MD5 only for PutBlob requests
 	if size < int64(o.fs.opt.UploadCutoff) {
-		if sourceMD5, _ := src.Hash(hash.MD5); sourceMD5 != "" {
+		if sourceMD5, _ := src.Hash(ctx, hash.MD5); sourceMD5 != "" {
 			sourceMD5bytes, err := hex.DecodeString(sourceMD5)
 			if err == nil {
 				httpHeaders.ContentMD5 = sourceMD5bytes
@@ -1343,12 +1369,11 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 		fs.Debugf(o, "Setting multipart upload for file of chunk size (%d) to work around SDK bug", size)
 	}
 
-	ctx := context.Background()
 	// Don't retry, return a retry error instead
 	err = o.fs.pacer.CallNoRetry(func() (bool, error) {
 		if multipartUpload {
 			// If a large file upload in chunks
-			err = o.uploadMultipart(in, size, &blob, &httpHeaders)
+			err = o.uploadMultipart(ctx, in, size, &blob, &httpHeaders)
 		} else {
 			// Write a small blob in one transaction
 			blockBlobURL := blob.ToBlockBlobURL()
@@ -1361,7 +1386,7 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 	}
 	// Refresh metadata on object
 	o.clearMetaData()
-	err = o.readMetaData()
+	err = o.readMetaData(ctx)
 	if err != nil {
 		return err
 	}
@@ -1372,15 +1397,14 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 	}
 
 	// Now, set blob tier based on configured access tier
-	return o.SetTier(o.fs.opt.AccessTier)
+	return o.SetTier(ctx, o.fs.opt.AccessTier)
 }
 
 // Remove an object
-func (o *Object) Remove() error {
+func (o *Object) Remove(ctx context.Context) error {
 	blob := o.getBlobReference()
 	snapShotOptions := azblob.DeleteSnapshotsOptionNone
 	ac := azblob.BlobAccessConditions{}
-	ctx := context.Background()
 	return o.fs.pacer.Call(func() (bool, error) {
 		_, err := blob.Delete(ctx, snapShotOptions, ac)
 		return o.fs.shouldRetry(err)
@@ -1392,13 +1416,49 @@ func (o *Object) MimeType() string {
 	return o.mimeType
 }
 
+// Metadata returns metadata for an object
+//
+// This is the blob metadata, the modification time and any of the
+// standard headers which are set.
+func (o *Object) Metadata(ctx context.Context) (metadata fs.Metadata, err error) {
+	err = o.readMetaData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	metadata = make(fs.Metadata, len(o.meta)+6)
+	for k, v := range o.meta {
+		metadata[strings.ToLower(k)] = v
+	}
+	metadata[modTimeKey] = o.modTime.Format(time.RFC3339Nano)
+	for k, v := range map[string]string{
+		"content-type":        o.mimeType,
+		"cache-control":       o.cacheControl,
+		"content-disposition": o.contentDisposition,
+		"content-encoding":    o.contentEncoding,
+		"content-language":    o.contentLanguage,
+	} {
+		if v != "" {
+			metadata[k] = v
+		}
+	}
+	return metadata, nil
+}
+
+// stringValue returns the string pointed to by p or "" if it is nil
+func stringValue(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
+
 // AccessTier of an object, default is of type none
 func (o *Object) AccessTier() azblob.AccessTierType {
 	return o.accessTier
 }
 
 // SetTier performs changing object tier
-func (o *Object) SetTier(tier string) error {
+func (o *Object) SetTier(ctx context.Context, tier string) error {
 	if !validateAccessTier(tier) {
 		return errors.Errorf("Tier %s not supported by Azure Blob Storage", tier)
 	}
@@ -1409,7 +1469,6 @@ func (o *Object) SetTier(tier string) error {
 	}
 	desiredAccessTier := azblob.AccessTierType(tier)
 	blob := o.getBlobReference()
-	ctx := context.Background()
 	err := o.fs.pacer.Call(func() (bool, error) {
 		_, err := blob.SetTier(ctx, desiredAccessTier, azblob.LeaseAccessConditions{})
 		return o.fs.shouldRetry(err)
@@ -1434,10 +1493,11 @@ func (o *Object) GetTier() string {
 
 // Check the interfaces are satisfied
 var (
-	_ fs.Fs        = &Fs{}
-	_ fs.Copier    = &Fs{}
-	_ fs.Purger    = &Fs{}
-	_ fs.ListRer   = &Fs{}
-	_ fs.Object    = &Object{}
-	_ fs.MimeTyper = &Object{}
+	_ fs.Fs         = &Fs{}
+	_ fs.Copier     = &Fs{}
+	_ fs.Purger     = &Fs{}
+	_ fs.ListRer    = &Fs{}
+	_ fs.Object     = &Object{}
+	_ fs.MimeTyper  = &Object{}
+	_ fs.Metadataer = &Object{}
 )
@@ -4,6 +4,7 @@ package cache_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	goflag "flag"
 	"fmt"
@@ -105,6 +106,7 @@ func TestMain(m *testing.M) {
 }
 
 func TestInternalListRootAndInnerRemotes(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("tilrair%v", time.Now().Unix())
 	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, true, true, nil, nil)
 	defer runInstance.cleanupFs(t, rootFs, boltDb)
@@ -120,7 +122,7 @@ func TestInternalListRootAndInnerRemotes(t *testing.T) {
 	require.NoError(t, err)
 	listRootInner, err := runInstance.list(t, rootFs, innerFolder)
 	require.NoError(t, err)
-	listInner, err := rootFs2.List("")
+	listInner, err := rootFs2.List(ctx, "")
 	require.NoError(t, err)
 
 	require.Len(t, listRoot, 1)
@@ -262,11 +264,12 @@ func TestInternalObjWrapFsFound(t *testing.T) {
 }
 
 func TestInternalObjNotFound(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("tionf%v", time.Now().Unix())
 	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, false, true, nil, nil)
 	defer runInstance.cleanupFs(t, rootFs, boltDb)
 
-	obj, err := rootFs.NewObject("404")
+	obj, err := rootFs.NewObject(ctx, "404")
 	require.Error(t, err)
 	require.Nil(t, obj)
 }
@@ -428,6 +431,7 @@ func TestInternalLargeWrittenContentMatches(t *testing.T) {
 }
 
 func TestInternalWrappedFsChangeNotSeen(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("tiwfcns%v", time.Now().Unix())
 	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, false, true, nil, nil)
 	defer runInstance.cleanupFs(t, rootFs, boltDb)
@@ -445,7 +449,7 @@ func TestInternalWrappedFsChangeNotSeen(t *testing.T) {
 	require.NoError(t, err)
 	log.Printf("original size: %v", originalSize)
 
-	o, err := cfs.UnWrap().NewObject(runInstance.encryptRemoteIfNeeded(t, "data.bin"))
+	o, err := cfs.UnWrap().NewObject(ctx, runInstance.encryptRemoteIfNeeded(t, "data.bin"))
 	require.NoError(t, err)
 	expectedSize := int64(len([]byte("test content")))
 	var data2 []byte
@@ -457,7 +461,7 @@ func TestInternalWrappedFsChangeNotSeen(t *testing.T) {
 		data2 = []byte("test content")
 	}
 	objInfo := object.NewStaticObjectInfo(runInstance.encryptRemoteIfNeeded(t, "data.bin"), time.Now(), int64(len(data2)), true, nil, cfs.UnWrap())
-	err = o.Update(bytes.NewReader(data2), objInfo)
+	err = o.Update(ctx, bytes.NewReader(data2), objInfo)
 	require.NoError(t, err)
 	require.Equal(t, int64(len(data2)), o.Size())
 	log.Printf("updated size: %v", len(data2))
@@ -483,6 +487,7 @@ func TestInternalWrappedFsChangeNotSeen(t *testing.T) {
 }
 
 func TestInternalMoveWithNotify(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("timwn%v", time.Now().Unix())
 	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, false, true, nil, nil)
 	defer runInstance.cleanupFs(t, rootFs, boltDb)
@@ -503,9 +508,9 @@ func TestInternalMoveWithNotify(t *testing.T) {
 	} else {
 		testData = []byte("test content")
 	}
-	_ = cfs.UnWrap().Mkdir(runInstance.encryptRemoteIfNeeded(t, "test"))
-	_ = cfs.UnWrap().Mkdir(runInstance.encryptRemoteIfNeeded(t, "test/one"))
-	_ = cfs.UnWrap().Mkdir(runInstance.encryptRemoteIfNeeded(t, "test/second"))
+	_ = cfs.UnWrap().Mkdir(ctx, runInstance.encryptRemoteIfNeeded(t, "test"))
+	_ = cfs.UnWrap().Mkdir(ctx, runInstance.encryptRemoteIfNeeded(t, "test/one"))
+	_ = cfs.UnWrap().Mkdir(ctx, runInstance.encryptRemoteIfNeeded(t, "test/second"))
 	srcObj := runInstance.writeObjectBytes(t, cfs.UnWrap(), srcName, testData)
 
 	// list in mount
@@ -515,7 +520,7 @@ func TestInternalMoveWithNotify(t *testing.T) {
 	require.NoError(t, err)
 
 	// move file
-	_, err = cfs.UnWrap().Features().Move(srcObj, dstName)
+	_, err = cfs.UnWrap().Features().Move(ctx, srcObj, dstName)
 	require.NoError(t, err)
 
 	err = runInstance.retryBlock(func() error {
@@ -570,6 +575,7 @@ func TestInternalMoveWithNotify(t *testing.T) {
 }
 
 func TestInternalNotifyCreatesEmptyParts(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("tincep%v", time.Now().Unix())
 	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, false, true, nil, nil)
 	defer runInstance.cleanupFs(t, rootFs, boltDb)
@@ -589,9 +595,9 @@ func TestInternalNotifyCreatesEmptyParts(t *testing.T) {
 	} else {
 		testData = []byte("test content")
 	}
-	err = rootFs.Mkdir("test")
+	err = rootFs.Mkdir(ctx, "test")
 	require.NoError(t, err)
-	err = rootFs.Mkdir("test/one")
+	err = rootFs.Mkdir(ctx, "test/one")
 	require.NoError(t, err)
 	srcObj := runInstance.writeObjectBytes(t, cfs.UnWrap(), srcName, testData)
 
@@ -608,7 +614,7 @@ func TestInternalNotifyCreatesEmptyParts(t *testing.T) {
 	require.False(t, found)
 
 	// move file
-	_, err = cfs.UnWrap().Features().Move(srcObj, dstName)
+	_, err = cfs.UnWrap().Features().Move(ctx, srcObj, dstName)
 	require.NoError(t, err)
 
 	err = runInstance.retryBlock(func() error {
@@ -657,6 +663,7 @@ func TestInternalNotifyCreatesEmptyParts(t *testing.T) {
 }
 
 func TestInternalChangeSeenAfterDirCacheFlush(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("ticsadcf%v", time.Now().Unix())
 	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, false, true, nil, nil)
 	defer runInstance.cleanupFs(t, rootFs, boltDb)
@@ -670,26 +677,27 @@ func TestInternalChangeSeenAfterDirCacheFlush(t *testing.T) {
 	runInstance.writeRemoteBytes(t, rootFs, "data.bin", testData)
 
 	// update in the wrapped fs
-	o, err := cfs.UnWrap().NewObject(runInstance.encryptRemoteIfNeeded(t, "data.bin"))
+	o, err := cfs.UnWrap().NewObject(ctx, runInstance.encryptRemoteIfNeeded(t, "data.bin"))
 	require.NoError(t, err)
 	wrappedTime := time.Now().Add(-1 * time.Hour)
-	err = o.SetModTime(wrappedTime)
+	err = o.SetModTime(ctx, wrappedTime)
 	require.NoError(t, err)
 
 	// get a new instance from the cache
-	co, err := rootFs.NewObject("data.bin")
+	co, err := rootFs.NewObject(ctx, "data.bin")
 	require.NoError(t, err)
 	require.NotEqual(t, o.ModTime().String(), co.ModTime().String())
 
 	cfs.DirCacheFlush() // flush the cache
 
 	// get a new instance from the cache
-	co, err = rootFs.NewObject("data.bin")
+	co, err = rootFs.NewObject(ctx, "data.bin")
 	require.NoError(t, err)
 	require.Equal(t, wrappedTime.Unix(), co.ModTime().Unix())
 }
 
 func TestInternalChangeSeenAfterRc(t *testing.T) {
+	ctx := context.Background()
 	cacheExpire := rc.Calls.Get("cache/expire")
 	assert.NotNil(t, cacheExpire)
 
@@ -713,26 +721,26 @@ func TestInternalChangeSeenAfterRc(t *testing.T) {
 	runInstance.writeRemoteBytes(t, rootFs, "data.bin", testData)
 
 	// update in the wrapped fs
-	o, err := cfs.UnWrap().NewObject(runInstance.encryptRemoteIfNeeded(t, "data.bin"))
+	o, err := cfs.UnWrap().NewObject(ctx, runInstance.encryptRemoteIfNeeded(t, "data.bin"))
 	require.NoError(t, err)
 	wrappedTime := time.Now().Add(-1 * time.Hour)
-	err = o.SetModTime(wrappedTime)
+	err = o.SetModTime(ctx, wrappedTime)
 	require.NoError(t, err)
 
 	// get a new instance from the cache
-	co, err := rootFs.NewObject("data.bin")
+	co, err := rootFs.NewObject(ctx, "data.bin")
 	require.NoError(t, err)
 	require.NotEqual(t, o.ModTime().String(), co.ModTime().String())
 
 	// Call the rc function
-	m, err := cacheExpire.Fn(rc.Params{"remote": "data.bin"})
+	m, err := cacheExpire.Fn(ctx, rc.Params{"remote": "data.bin"})
 	require.Contains(t, m, "status")
 	require.Contains(t, m, "message")
 	require.Equal(t, "ok", m["status"])
 	require.Contains(t, m["message"], "cached file cleared")
 
 	// get a new instance from the cache
-	co, err = rootFs.NewObject("data.bin")
+	co, err = rootFs.NewObject(ctx, "data.bin")
 	require.NoError(t, err)
 	require.Equal(t, wrappedTime.Unix(), co.ModTime().Unix())
 	li1, err := runInstance.list(t, rootFs, "")
@@ -746,7 +754,7 @@ func TestInternalChangeSeenAfterRc(t *testing.T) {
 	require.Len(t, li1, 1)
 
 	// Call the rc function
-	m, err = cacheExpire.Fn(rc.Params{"remote": "/"})
+	m, err = cacheExpire.Fn(ctx, rc.Params{"remote": "/"})
 	require.Contains(t, m, "status")
 	require.Contains(t, m, "message")
 	require.Equal(t, "ok", m["status"])
@@ -777,6 +785,7 @@ func TestInternalCacheWrites(t *testing.T) {
 }
 
 func TestInternalMaxChunkSizeRespected(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("timcsr%v", time.Now().Unix())
 	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, false, true, nil, map[string]string{"workers": "1"})
 	defer runInstance.cleanupFs(t, rootFs, boltDb)
@@ -789,7 +798,7 @@ func TestInternalMaxChunkSizeRespected(t *testing.T) {
 	// create some rand test data
 	testData := randStringBytes(int(int64(totalChunks-1)*chunkSize + chunkSize/2))
 	runInstance.writeRemoteBytes(t, rootFs, "data.bin", testData)
-	o, err := cfs.NewObject(runInstance.encryptRemoteIfNeeded(t, "data.bin"))
+	o, err := cfs.NewObject(ctx, runInstance.encryptRemoteIfNeeded(t, "data.bin"))
 	require.NoError(t, err)
 	co, ok := o.(*cache.Object)
 	require.True(t, ok)
@@ -812,6 +821,7 @@ func TestInternalMaxChunkSizeRespected(t *testing.T) {
 }
 
 func TestInternalExpiredEntriesRemoved(t *testing.T) {
+	ctx := context.Background()
 	id := fmt.Sprintf("tieer%v", time.Now().Unix())
 	vfsflags.Opt.DirCacheTime = time.Second * 4 // needs to be lower than the defined
 	rootFs, boltDb := runInstance.newCacheFs(t, remoteName, id, true, true, map[string]string{"info_age": "5s"}, nil)
@@ -828,7 +838,7 @@ func TestInternalExpiredEntriesRemoved(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, l, 1)
 
-	err = cfs.UnWrap().Mkdir(runInstance.encryptRemoteIfNeeded(t, "test/third"))
+	err = cfs.UnWrap().Mkdir(ctx, runInstance.encryptRemoteIfNeeded(t, "test/third"))
 	require.NoError(t, err)
 
 	l, err = runInstance.list(t, rootFs, "test")
@@ -849,6 +859,7 @@ func TestInternalExpiredEntriesRemoved(t *testing.T) {
 }
 
 func TestInternalBug2117(t *testing.T) {
+	ctx := context.Background()
 	vfsflags.Opt.DirCacheTime = time.Second * 10
 
 	id := fmt.Sprintf("tib2117%v", time.Now().Unix())
@@ -863,14 +874,14 @@ func TestInternalBug2117(t *testing.T) {
 	cfs, err := runInstance.getCacheFs(rootFs)
 	require.NoError(t, err)
 
-	err = cfs.UnWrap().Mkdir("test")
+	err = cfs.UnWrap().Mkdir(ctx, "test")
 	require.NoError(t, err)
 	for i := 1; i <= 4; i++ {
-		err = cfs.UnWrap().Mkdir(fmt.Sprintf("test/dir%d", i))
+		err = cfs.UnWrap().Mkdir(ctx, fmt.Sprintf("test/dir%d", i))
 		require.NoError(t, err)
 
 		for j := 1; j <= 4; j++ {
-			err = cfs.UnWrap().Mkdir(fmt.Sprintf("test/dir%d/dir%d", i, j))
+			err = cfs.UnWrap().Mkdir(ctx, fmt.Sprintf("test/dir%d/dir%d", i, j))
 			require.NoError(t, err)
 
 			runInstance.writeObjectString(t, cfs.UnWrap(), fmt.Sprintf("test/dir%d/dir%d/test.txt", i, j), "test")
@@ -990,6 +1001,7 @@ func (r *run) encryptRemoteIfNeeded(t *testing.T, remote string) string {
 }
 
 func (r *run) newCacheFs(t *testing.T, remote, id string, needRemote, purge bool, cfg map[string]string, flags map[string]string) (fs.Fs, *cache.Persistent) {
+	ctx := context.Background()
 	fstest.Initialise()
 	remoteExists := false
 	for _, s := range config.FileSections() {
@@ -1075,10 +1087,10 @@ func (r *run) newCacheFs(t *testing.T, remote, id string, needRemote, purge bool
 	}
 
 	if purge {
-		_ = f.Features().Purge()
+		_ = f.Features().Purge(ctx)
 		require.NoError(t, err)
 	}
-	err = f.Mkdir("")
+	err = f.Mkdir(ctx, "")
 	require.NoError(t, err)
 	if r.useMount && !r.isMounted {
 		r.mountFs(t, f)
@@ -1088,11 +1100,12 @@ func (r *run) newCacheFs(t *testing.T, remote, id string, needRemote, purge bool
 }
 
 func (r *run) cleanupFs(t *testing.T, f fs.Fs, b *cache.Persistent) {
+	ctx := context.Background()
 	if r.useMount && r.isMounted {
 		r.unmountFs(t, f)
 	}
 
-	err := f.Features().Purge()
+	err := f.Features().Purge(ctx)
 	require.NoError(t, err)
 	cfs, err := r.getCacheFs(f)
 	require.NoError(t, err)
@@ -1192,18 +1205,20 @@ func (r *run) writeRemoteReader(t *testing.T, f fs.Fs, remote string, in io.Read
 }
 
 func (r *run) writeObjectBytes(t *testing.T, f fs.Fs, remote string, data []byte) fs.Object {
+	ctx := context.Background()
 	in := bytes.NewReader(data)
 	_ = r.writeObjectReader(t, f, remote, in)
-	o, err := f.NewObject(remote)
+	o, err := f.NewObject(ctx, remote)
 	require.NoError(t, err)
 	require.Equal(t, int64(len(data)), o.Size())
 	return o
 }
 
 func (r *run) writeObjectReader(t *testing.T, f fs.Fs, remote string, in io.Reader) fs.Object {
+	ctx := context.Background()
 	modTime := time.Now()
 	objInfo := object.NewStaticObjectInfo(remote, modTime, -1, true, nil, f)
-	obj, err := f.Put(in, objInfo)
+	obj, err := f.Put(ctx, in, objInfo)
 	require.NoError(t, err)
 	if r.useMount {
 		r.vfs.WaitForWriters(10 * time.Second)
@@ -1213,6 +1228,7 @@ func (r *run) writeObjectReader(t *testing.T, f fs.Fs, remote string, in io.Read
 }
 
 func (r *run) updateObjectRemote(t *testing.T, f fs.Fs, remote string, data1 []byte, data2 []byte) fs.Object {
+	ctx := context.Background()
 	var err error
 	var obj fs.Object
 
@@ -1223,18 +1239,18 @@ func (r *run) updateObjectRemote(t *testing.T, f fs.Fs, remote string, data1 []b
 		err = ioutil.WriteFile(path.Join(r.mntDir, remote), data2, 0600)
 		require.NoError(t, err)
 		r.vfs.WaitForWriters(10 * time.Second)
-		obj, err = f.NewObject(remote)
+		obj, err = f.NewObject(ctx, remote)
 	} else {
 		in1 := bytes.NewReader(data1)
 		in2 := bytes.NewReader(data2)
 		objInfo1 := object.NewStaticObjectInfo(remote, time.Now(), int64(len(data1)), true, nil, f)
 		objInfo2 := object.NewStaticObjectInfo(remote, time.Now(), int64(len(data2)), true, nil, f)
 
-		obj, err = f.Put(in1, objInfo1)
+		obj, err = f.Put(ctx, in1, objInfo1)
 		require.NoError(t, err)
-		obj, err = f.NewObject(remote)
+		obj, err = f.NewObject(ctx, remote)
 		require.NoError(t, err)
-		err = obj.Update(in2, objInfo2)
+		err = obj.Update(ctx, in2, objInfo2)
 	}
 	require.NoError(t, err)
 
@@ -1242,6 +1258,7 @@ func (r *run) updateObjectRemote(t *testing.T, f fs.Fs, remote string, data1 []b
 }
 
 func (r *run) readDataFromRemote(t *testing.T, f fs.Fs, remote string, offset, end int64, noLengthCheck bool) ([]byte, error) {
+	ctx := context.Background()
 	size := end - offset
 	checkSample := make([]byte, size)
 
@@ -1263,7 +1280,7 @@ func (r *run) readDataFromRemote(t *testing.T, f fs.Fs, remote string, offset, e
 			return checkSample, err
 		}
 	} else {
-		co, err := f.NewObject(remote)
+		co, err := f.NewObject(ctx, remote)
 		if err != nil {
 			return checkSample, err
 		}
@@ -1276,9 +1293,10 @@ func (r *run) readDataFromRemote(t *testing.T, f fs.Fs, remote string, offset, e
 }
 
 func (r *run) readDataFromObj(t *testing.T, o fs.Object, offset, end int64, noLengthCheck bool) []byte {
+	ctx := context.Background()
 	size := end - offset
 	checkSample := make([]byte, size)
-	reader, err := o.Open(&fs.SeekOption{Offset: offset})
+	reader, err := o.Open(ctx, &fs.SeekOption{Offset: offset})
 	require.NoError(t, err)
 	totalRead, err := io.ReadFull(reader, checkSample)
 	if (err == io.EOF || err == io.ErrUnexpectedEOF) && noLengthCheck {
@@ -1291,27 +1309,29 @@ func (r *run) readDataFromObj(t *testing.T, o fs.Object, offset, end int64, noLe
 }
 
 func (r *run) mkdir(t *testing.T, f fs.Fs, remote string) {
+	ctx := context.Background()
 	var err error
 	if r.useMount {
 		err = os.Mkdir(path.Join(r.mntDir, remote), 0700)
 	} else {
-		err = f.Mkdir(remote)
+		err = f.Mkdir(ctx, remote)
 	}
 	require.NoError(t, err)
 }
 
 func (r *run) rm(t *testing.T, f fs.Fs, remote string) error {
+	ctx := context.Background()
 	var err error
 
 	if r.useMount {
 		err = os.Remove(path.Join(r.mntDir, remote))
 	} else {
 		var obj fs.Object
-		obj, err = f.NewObject(remote)
+		obj, err = f.NewObject(ctx, remote)
 		if err != nil {
-			err = f.Rmdir(remote)
+			err = f.Rmdir(ctx, remote)
 		} else {
-			err = obj.Remove()
+			err = obj.Remove(ctx)
 		}
 	}
 
@@ -1319,6 +1339,7 @@ func (r *run) rm(t *testing.T, f fs.Fs, remote string) error {
 }
 
 func (r *run) list(t *testing.T, f fs.Fs, remote string) ([]interface{}, error) {
+	ctx := context.Background()
 	var err error
 	var l []interface{}
 	if r.useMount {
@@ -1329,7 +1350,7 @@ func (r *run) list(t *testing.T, f fs.Fs, remote string) ([]interface{}, error)
 		}
 	} else {
 		var list fs.DirEntries
-		list, err = f.List(remote)
+		list, err = f.List(ctx, remote)
 		for _, ll := range list {
 			l = append(l, ll)
 		}
@@ -1338,6 +1359,7 @@ func (r *run) list(t *testing.T, f fs.Fs, remote string) ([]interface{}, error)
 }
 
 func (r *run) listPath(t *testing.T, f fs.Fs, remote string) []string {
+	ctx := context.Background()
 	var err error
 	var l []string
 	if r.useMount {
@@ -1348,7 +1370,7 @@ func (r *run) listPath(t *testing.T, f fs.Fs, remote string) []string {
 		}
 	} else {
 		var list fs.DirEntries
-		list, err = f.List(remote)
+		list, err = f.List(ctx, remote)
 		for _, ll := range list {
 			l = append(l, ll.Remote())
 		}
@@ -1379,6 +1401,7 @@ func (r *run) copyFile(t *testing.T, f fs.Fs, src, dst string) error {
 }
 
 func (r *run) dirMove(t *testing.T, rootFs fs.Fs, src, dst string) error {
+	ctx := context.Background()
 	var err error
 
 	if runInstance.useMount {
@@ -1388,7 +1411,7 @@ func (r *run) dirMove(t *testing.T, rootFs fs.Fs, src, dst string) error {
 		}
 		r.vfs.WaitForWriters(10 * time.Second)
 	} else if rootFs.Features().DirMove != nil {
-		err = rootFs.Features().DirMove(rootFs, src, dst)
+		err = rootFs.Features().DirMove(ctx, rootFs, src, dst)
 		if err != nil {
 			return err
 		}
@@ -1401,6 +1424,7 @@ func (r *run) dirMove(t *testing.T, rootFs fs.Fs, src, dst string) error {
 }
 
 func (r *run) move(t *testing.T, rootFs fs.Fs, src, dst string) error {
+	ctx := context.Background()
 	var err error
 
 	if runInstance.useMount {
@@ -1410,11 +1434,11 @@ func (r *run) move(t *testing.T, rootFs fs.Fs, src, dst string) error {
 		}
 		r.vfs.WaitForWriters(10 * time.Second)
 	} else if rootFs.Features().Move != nil {
-		obj1, err := rootFs.NewObject(src)
+		obj1, err := rootFs.NewObject(ctx, src)
 		if err != nil {
 			return err
 		}
-		_, err = rootFs.Features().Move(obj1, dst)
+		_, err = rootFs.Features().Move(ctx, obj1, dst)
 		if err != nil {
 			return err
 		}
@@ -1427,6 +1451,7 @@ func (r *run) move(t *testing.T, rootFs fs.Fs, src, dst string) error {
 }
 
 func (r *run) copy(t *testing.T, rootFs fs.Fs, src, dst string) error {
+	ctx := context.Background()
 	var err error
 
 	if r.useMount {
@@ -1436,11 +1461,11 @@ func (r *run) copy(t *testing.T, rootFs fs.Fs, src, dst string) error {
 		}
 		r.vfs.WaitForWriters(10 * time.Second)
 	} else if rootFs.Features().Copy != nil {
-		obj, err := rootFs.NewObject(src)
+		obj, err := rootFs.NewObject(ctx, src)
 		if err != nil {
 			return err
 		}
-		_, err = rootFs.Features().Copy(obj, dst)
+		_, err = rootFs.Features().Copy(ctx, obj, dst)
 		if err != nil {
 			return err
 		}
@@ -1453,6 +1478,7 @@ func (r *run) copy(t *testing.T, rootFs fs.Fs, src, dst string) error {
 }
 
 func (r *run) modTime(t *testing.T, rootFs fs.Fs, src string) (time.Time, error) {
+	ctx := context.Background()
 	var err error
 
 	if r.useMount {
@@ -1462,7 +1488,7 @@ func (r *run) modTime(t *testing.T, rootFs fs.Fs, src string) (time.Time, error)
 		}
 		return fi.ModTime(), nil
 	}
-	obj1, err := rootFs.NewObject(src)
+	obj1, err := rootFs.NewObject(ctx, src)
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -1470,6 +1496,7 @@ func (r *run) modTime(t *testing.T, rootFs fs.Fs, src string) (time.Time, error)
 }
 
 func (r *run) size(t *testing.T, rootFs fs.Fs, src string) (int64, error) {
+	ctx := context.Background()
 	var err error
 
 	if r.useMount {
@@ -1479,7 +1506,7 @@ func (r *run) size(t *testing.T, rootFs fs.Fs, src string) (int64, error) {
 		}
 		return fi.Size(), nil
 	}
-	obj1, err := rootFs.NewObject(src)
+	obj1, err := rootFs.NewObject(ctx, src)
 	if err != nil {
 		return int64(0), err
 	}
@@ -1487,6 +1514,7 @@ func (r *run) size(t *testing.T, rootFs fs.Fs, src string) (int64, error) {
 }
 
 func (r *run) updateData(t *testing.T, rootFs fs.Fs, src, data, append string) error {
+	ctx := context.Background()
 	var err error
 
 	if r.useMount {
@@ -1500,14 +1528,14 @@ func (r *run) updateData(t *testing.T, rootFs fs.Fs, src, data, append string) e
 		}()
 		_, err = f.WriteString(data + append)
 	} else {
-		obj1, err := rootFs.NewObject(src)
+		obj1, err := rootFs.NewObject(ctx, src)
 		if err != nil {
 			return err
 		}
 		data1 := []byte(data + append)
 		r := bytes.NewReader(data1)
 		objInfo1 := object.NewStaticObjectInfo(src, time.Now(), int64(len(data1)), true, nil, rootFs)
-		err = obj1.Update(r, objInfo1)
+		err = obj1.Update(ctx, r, objInfo1)
 	}
 
 	return err
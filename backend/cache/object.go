@@ -3,6 +3,7 @@
 package cache
 
 import (
+	"context"
 	"io"
 	"path"
 	"sync"
@@ -131,19 +132,19 @@ func (o *Object) abs() string {
 
 // ModTime returns the cached ModTime
 func (o *Object) ModTime() time.Time {
-	_ = o.refresh()
+	_ = o.refresh(context.TODO())
 	return time.Unix(0, o.CacheModTime)
 }
 
 // Size returns the cached Size
 func (o *Object) Size() int64 {
-	_ = o.refresh()
+	_ = o.refresh(context.TODO())
 	return o.CacheSize
 }
 
 // Storable returns the cached Storable
 func (o *Object) Storable() bool {
-	_ = o.refresh()
+	_ = o.refresh(context.TODO())
 	return o.CacheStorable
 }
 
@@ -151,18 +152,18 @@ func (o *Object) Storable() bool {
 // all these conditions must be true to ignore a refresh
 // 1. cache ts didn't expire yet
 // 2. is not pending a notification from the wrapped fs
-func (o *Object) refresh() error {
+func (o *Object) refresh(ctx context.Context) error {
 	isNotified := o.CacheFs.isNotifiedRemote(o.Remote())
 	isExpired := time.Now().After(o.CacheTs.Add(time.Duration(o.CacheFs.opt.InfoAge)))
 	if !isExpired && !isNotified {
 		return nil
 	}
 
-	return o.refreshFromSource(true)
+	return o.refreshFromSource(ctx, true)
 }
 
 // refreshFromSource requests the original FS for the object in case it comes from a cached entry
-func (o *Object) refreshFromSource(force bool) error {
+func (o *Object) refreshFromSource(ctx context.Context, force bool) error {
 	o.refreshMutex.Lock()
 	defer o.refreshMutex.Unlock()
 	var err error
@@ -172,10 +173,10 @@ func (o *Object) refreshFromSource(force bool) error {
 		return nil
 	}
 	if o.isTempFile() {
-		liveObject, err = o.ParentFs.NewObject(o.Remote())
+		liveObject, err = o.ParentFs.NewObject(ctx, o.Remote())
 		err = errors.Wrapf(err, "in parent fs %v", o.ParentFs)
 	} else {
-		liveObject, err = o.CacheFs.Fs.NewObject(o.Remote())
+		liveObject, err = o.CacheFs.Fs.NewObject(ctx, o.Remote())
 		err = errors.Wrapf(err, "in cache fs %v", o.CacheFs.Fs)
 	}
 	if err != nil {
@@ -189,12 +190,12 @@ func (o *Object) refreshFromSource(force bool) error {
 }
 
 // SetModTime sets the ModTime of this object
-func (o *Object) SetModTime(t time.Time) error {
-	if err := o.refreshFromSource(false); err != nil {
+func (o *Object) SetModTime(ctx context.Context, t time.Time) error {
+	if err := o.refreshFromSource(ctx, false); err != nil {
 		return err
 	}
 
-	err := o.Object.SetModTime(t)
+	err := o.Object.SetModTime(ctx, t)
 	if err != nil {
 		return err
 	}
@@ -207,19 +208,19 @@ func (o *Object) SetModTime(t time.Time) error {
 }
 
 // Open is used to request a specific part of the file using fs.RangeOption
-func (o *Object) Open(options ...fs.OpenOption) (io.ReadCloser, error) {
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (io.ReadCloser, error) {
 	var err error
 
 	if o.Object == nil {
-		err = o.refreshFromSource(true)
+		err = o.refreshFromSource(ctx, true)
 	} else {
-		err = o.refresh()
+		err = o.refresh(ctx)
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	cacheReader := NewObjectHandle(o, o.CacheFs)
+	cacheReader := NewObjectHandle(ctx, o, o.CacheFs)
 	var offset, limit int64 = 0, -1
 	for _, option := range options {
 		switch x := option.(type) {
@@ -238,8 +239,8 @@ func (o *Object) Open(options ...fs.OpenOption) (io.ReadCloser, error) {
 }
 
 // Update will change the object data
-func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
-	if err := o.refreshFromSource(false); err != nil {
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	if err := o.refreshFromSource(ctx, false); err != nil {
 		return err
 	}
 	// pause background uploads if active
@@ -254,7 +255,7 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 	fs.Debugf(o, "updating object contents with size %v", src.Size())
 
 	// FIXME use reliable upload
-	err := o.Object.Update(in, src, options...)
+	err := o.Object.Update(ctx, in, src, options...)
 	if err != nil {
 		fs.Errorf(o, "error updating source: %v", err)
 		return err
@@ -275,8 +276,8 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 }
 
 // Remove deletes the object from both the cache and the source
-func (o *Object) Remove() error {
-	if err := o.refreshFromSource(false); err != nil {
+func (o *Object) Remove(ctx context.Context) error {
+	if err := o.refreshFromSource(ctx, false); err != nil {
 		return err
 	}
 	// pause background uploads if active
@@ -288,7 +289,7 @@ func (o *Object) Remove() error {
 			return errors.Errorf("%v is currently uploading, can't delete", o)
 		}
 	}
-	err := o.Object.Remove()
+	err := o.Object.Remove(ctx)
 	if err != nil {
 		return err
 	}
@@ -306,8 +307,8 @@ func (o *Object) Remove() error {
 
 // Hash requests a hash of the object and stores in the cache
 // since it might or might not be called, this is lazy loaded
-func (o *Object) Hash(ht hash.Type) (string, error) {
-	_ = o.refresh()
+func (o *Object) Hash(ctx context.Context, ht hash.Type) (string, error) {
+	_ = o.refresh(ctx)
 	if o.CacheHashes == nil {
 		o.CacheHashes = make(map[hash.Type]string)
 	}
@@ -316,10 +317,10 @@ func (o *Object) Hash(ht hash.Type) (string, error) {
 	if found {
 		return cachedHash, nil
 	}
-	if err := o.refreshFromSource(false); err != nil {
+	if err := o.refreshFromSource(ctx, false); err != nil {
 		return "", err
 	}
-	liveHash, err := o.Object.Hash(ht)
+	liveHash, err := o.Object.Hash(ctx, ht)
 	if err != nil {
 		return "", err
 	}
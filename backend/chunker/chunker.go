@@ -0,0 +1,901 @@
+// Package chunker provides wrappers for Fs and Object which split large files in chunks
+package chunker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/config/configmap"
+	"github.com/ncw/rclone/fs/config/configstruct"
+	"github.com/ncw/rclone/fs/fspath"
+	"github.com/ncw/rclone/fs/hash"
+	"github.com/ncw/rclone/fs/object"
+	"github.com/ncw/rclone/lib/readers"
+	"github.com/pkg/errors"
+)
+
+const (
+	// chunkSuffix separates the file name from the chunk number
+	chunkSuffix = ".rclone_chunk."
+	// maxMetadataSize is the largest object which may be metadata
+	maxMetadataSize = 255
+	// metadataVersion is the version of the metadata format written
+	metadataVersion = 1
+)
+
+// chunkRe matches a chunk name returning the file name, the chunk
+// number and the transaction ID
+var chunkRe = regexp.MustCompile(`^(.+)` + regexp.QuoteMeta(chunkSuffix) + `(\d{3,})_([0-9a-f]+)$`)
+
+// Register with Fs
+func init() {
+	fs.Register(&fs.RegInfo{
+		Name:        "chunker",
+		Description: "Transparently chunk/split large files",
+		NewFs:       NewFs,
+		Options: []fs.Option{{
+			Name:     "remote",
+			Help:     "Remote to chunk/unchunk.\nNormally should contain a ':' and a path, eg \"myremote:path/to/dir\",\n\"myremote:bucket\" or maybe \"myremote:\" (not recommended).",
+			Required: true,
+		}, {
+			Name:    "chunk_size",
+			Help:    "Files larger than chunk size will be split in chunks.",
+			Default: fs.SizeSuffix(2 * 1024 * 1024 * 1024),
+		}, {
+			Name: "hash_type",
+			Help: `Choose how chunker handles hash sums of the whole file.
+
+Chunked files store the hash of the whole file in their metadata.
+Files smaller than the chunk size are stored as is and use the hash
+of the wrapped remote if it supports this type.`,
+			Default:  "md5",
+			Advanced: true,
+			Examples: []fs.OptionExample{
+				{
+					Value: "none",
+					Help:  "Don't store or return hash sums.",
+				}, {
+					Value: "md5",
+					Help:  "Store and return MD5 sums.",
+				}, {
+					Value: "sha1",
+					Help:  "Store and return SHA1 sums.",
+				},
+			},
+		}},
+	})
+}
+
+// Options defines the configuration for this backend
+type Options struct {
+	Remote    string        `config:"remote"`
+	ChunkSize fs.SizeSuffix `config:"chunk_size"`
+	HashType  string        `config:"hash_type"`
+}
+
+// NewFs contstructs an Fs from the path, container:path
+func NewFs(name, rpath string, m configmap.Mapper) (fs.Fs, error) {
+	// Parse config into Options struct
+	opt := new(Options)
+	err := configstruct.Set(m, opt)
+	if err != nil {
+		return nil, err
+	}
+	if opt.ChunkSize <= 0 {
+		return nil, errors.New("chunk_size must be greater than 0")
+	}
+	hashType := hash.None
+	switch opt.HashType {
+	case "none", "":
+	case "md5":
+		hashType = hash.MD5
+	case "sha1":
+		hashType = hash.SHA1
+	default:
+		return nil, errors.Errorf("unknown hash_type %q", opt.HashType)
+	}
+	remote := opt.Remote
+	if strings.HasPrefix(remote, name+":") {
+		return nil, errors.New("can't point chunker remote at itself - check the value of the remote setting")
+	}
+	wInfo, wName, wPath, wConfig, err := fs.ConfigFs(remote)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse remote %q to wrap", remote)
+	}
+	remotePath := fspath.JoinRootPath(wPath, rpath)
+	wrappedFs, err := wInfo.NewFs(wName, remotePath, wConfig)
+	if err != fs.ErrorIsFile && err != nil {
+		return nil, errors.Wrapf(err, "failed to make remote %s:%q to wrap", wName, remotePath)
+	}
+	f := &Fs{
+		Fs:       wrappedFs,
+		name:     name,
+		root:     rpath,
+		opt:      *opt,
+		hashType: hashType,
+	}
+	// the features here are ones we could support, and they are
+	// ANDed with the ones from wrappedFs
+	f.features = (&fs.Features{
+		CaseInsensitive:         true,
+		DuplicateFiles:          true,
+		ReadMimeType:            false,
+		WriteMimeType:           false,
+		BucketBased:             true,
+		CanHaveEmptyDirectories: true,
+	}).Fill(f).Mask(wrappedFs).WrapsFs(f, wrappedFs)
+
+	doChangeNotify := wrappedFs.Features().ChangeNotify
+	if doChangeNotify != nil {
+		f.features.ChangeNotify = func(ctx context.Context, notifyFunc func(string, fs.EntryType), pollInterval <-chan time.Duration) {
+			wrappedNotifyFunc := func(path string, entryType fs.EntryType) {
+				// Notify about the file rather than its chunks
+				if base, _, _, ok := parseChunkName(path); ok {
+					path = base
+				}
+				notifyFunc(path, entryType)
+			}
+			doChangeNotify(ctx, wrappedNotifyFunc, pollInterval)
+		}
+	}
+
+	return f, err
+}
+
+// Fs represents a wrapped fs.Fs
+type Fs struct {
+	fs.Fs
+	name     string
+	root     string
+	opt      Options
+	features *fs.Features // optional features
+	hashType hash.Type    // hash stored in the metadata
+}
+
+// Name of the remote (as passed into NewFs)
+func (f *Fs) Name() string {
+	return f.name
+}
+
+// Root of the remote (as passed into NewFs)
+func (f *Fs) Root() string {
+	return f.root
+}
+
+// Features returns the optional features of this Fs
+func (f *Fs) Features() *fs.Features {
+	return f.features
+}
+
+// String returns a description of the FS
+func (f *Fs) String() string {
+	return fmt.Sprintf("Chunked '%s:%s'", f.name, f.root)
+}
+
+// Hashes returns the supported hash sets.
+func (f *Fs) Hashes() hash.Set {
+	return hash.Set(f.hashType)
+}
+
+// chunkName returns the name of chunk n (counting from 0) of remote
+// in transaction txn
+func chunkName(remote string, n int, txn string) string {
+	return fmt.Sprintf("%s%s%03d_%s", remote, chunkSuffix, n+1, txn)
+}
+
+// parseChunkName parses a chunk name returning the name of the file
+// it is part of, its number (counting from 0) and its transaction
+func parseChunkName(name string) (remote string, n int, txn string, ok bool) {
+	match := chunkRe.FindStringSubmatch(name)
+	if match == nil {
+		return "", 0, "", false
+	}
+	n, err := strconv.Atoi(match[2])
+	if err != nil || n < 1 {
+		return "", 0, "", false
+	}
+	return match[1], n - 1, match[3], true
+}
+
+// newTxn makes a random transaction ID for a chunked upload
+func newTxn() (string, error) {
+	var id [4]byte
+	_, err := io.ReadFull(rand.Reader, id[:])
+	if err != nil {
+		return "", errors.Wrap(err, "failed to make transaction ID")
+	}
+	return hex.EncodeToString(id[:]), nil
+}
+
+// metadata is stored in place of a chunked file
+type metadata struct {
+	Version int    `json:"ver"`
+	Size    int64  `json:"size"`    // size of the whole file
+	NChunks int    `json:"nchunks"` // number of chunks
+	Txn     string `json:"txn"`     // transaction ID of the chunks
+	MD5     string `json:"md5,omitempty"`
+	SHA1    string `json:"sha1,omitempty"`
+}
+
+// errNotMetadata is returned if an object isn't chunker metadata
+var errNotMetadata = errors.New("not chunker metadata")
+
+// readMetadata reads the metadata from o returning errNotMetadata if
+// it doesn't contain any
+func readMetadata(ctx context.Context, o fs.Object) (meta *metadata, err error) {
+	if o.Size() < 0 || o.Size() > maxMetadataSize {
+		return nil, errNotMetadata
+	}
+	in, err := o.Open(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open metadata")
+	}
+	defer fs.CheckClose(in, &err)
+	data, err := ioutil.ReadAll(io.LimitReader(in, maxMetadataSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read metadata")
+	}
+	meta = new(metadata)
+	err = json.Unmarshal(data, meta)
+	// Each chunk has at least one byte in so a chunk count larger
+	// than the size can't be right
+	if err != nil || meta.Version != metadataVersion || meta.NChunks < 1 || meta.Size < int64(meta.NChunks) || meta.Txn == "" {
+		return nil, errNotMetadata
+	}
+	return meta, nil
+}
+
+// chunk is a chunk found in a listing
+type chunk struct {
+	n   int
+	txn string
+	o   fs.Object
+}
+
+// newComposite makes a chunked Object from main and the chunks found
+// for it in a listing.
+//
+// If main isn't metadata for a chunked file or the chunks found don't
+// match it then main is returned as a normal file, as NewObject does.
+func (f *Fs) newComposite(ctx context.Context, main fs.Object, found []chunk) (*Object, error) {
+	meta, err := readMetadata(ctx, main)
+	if err == errNotMetadata {
+		fs.Debugf(main, "Ignoring %d chunks found with file which isn't chunked", len(found))
+		return f.newObject(main, nil, nil), nil
+	} else if err != nil {
+		return nil, err
+	}
+	byNumber := map[int]fs.Object{}
+	for _, c := range found {
+		if c.txn == meta.Txn && c.n < meta.NChunks {
+			byNumber[c.n] = c.o
+		}
+	}
+	objects := make([]fs.Object, 0, len(byNumber))
+	for i := 0; i < meta.NChunks; i++ {
+		o, ok := byNumber[i]
+		if !ok {
+			// Metadata without its chunks must be a normal file
+			fs.Debugf(main, "Chunk %d missing - treating as normal file", i+1)
+			return f.newObject(main, nil, nil), nil
+		}
+		objects = append(objects, o)
+	}
+	return f.newObject(main, objects, meta), nil
+}
+
+// processEntries hides the chunks in entries, replacing the files
+// they are part of with chunked Objects
+func (f *Fs) processEntries(ctx context.Context, entries fs.DirEntries) (newEntries fs.DirEntries, err error) {
+	chunks := map[string][]chunk{}
+	newEntries = make(fs.DirEntries, 0, len(entries))
+	for _, entry := range entries {
+		switch x := entry.(type) {
+		case fs.Object:
+			if remote, n, txn, ok := parseChunkName(x.Remote()); ok {
+				chunks[remote] = append(chunks[remote], chunk{n: n, txn: txn, o: x})
+			} else {
+				newEntries = append(newEntries, x)
+			}
+		case fs.Directory:
+			newEntries = append(newEntries, x)
+		default:
+			return nil, errors.Errorf("Unknown object type %T", entry)
+		}
+	}
+	for i, entry := range newEntries {
+		main, ok := entry.(fs.Object)
+		if !ok {
+			continue
+		}
+		found := chunks[main.Remote()]
+		if len(found) == 0 {
+			newEntries[i] = f.newObject(main, nil, nil)
+			continue
+		}
+		delete(chunks, main.Remote())
+		o, err := f.newComposite(ctx, main, found)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s: bad chunked file", main.Remote())
+		}
+		newEntries[i] = o
+	}
+	for remote, found := range chunks {
+		fs.Debugf(remote, "Ignoring %d chunks without metadata", len(found))
+	}
+	return newEntries, nil
+}
+
+// List the objects and directories in dir into entries.  The
+// entries can be returned in any order but should be for a
+// complete directory.
+//
+// dir should be "" to list the root, and should not have
+// trailing slashes.
+//
+// This should return ErrDirNotFound if the directory isn't
+// found.
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	entries, err = f.Fs.List(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	return f.processEntries(ctx, entries)
+}
+
+// NewObject finds the Object at remote.
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	if _, _, _, ok := parseChunkName(remote); ok {
+		return nil, fs.ErrorObjectNotFound
+	}
+	main, err := f.Fs.NewObject(ctx, remote)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := readMetadata(ctx, main)
+	if err == errNotMetadata {
+		return f.newObject(main, nil, nil), nil
+	} else if err != nil {
+		return nil, err
+	}
+	var chunks []fs.Object
+	for i := 0; i < meta.NChunks; i++ {
+		chunk, err := f.Fs.NewObject(ctx, chunkName(remote, i, meta.Txn))
+		if err == fs.ErrorObjectNotFound {
+			// Metadata without its chunks must be a normal file
+			fs.Debugf(main, "Chunk %d missing - treating as normal file", i+1)
+			return f.newObject(main, nil, nil), nil
+		} else if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, chunk)
+	}
+	return f.newObject(main, chunks, meta), nil
+}
+
+type putFn func(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error)
+
+// put uploads in as a normal file if it fits in a chunk, or as chunks
+// and metadata otherwise.
+//
+// It doesn't remove any chunks of a file it replaces.
+func (f *Fs) put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options []fs.OpenOption, put putFn) (*Object, error) {
+	size := src.Size()
+	if size >= 0 && size <= int64(f.opt.ChunkSize) {
+		main, err := put(ctx, in, src, options...)
+		if err != nil {
+			return nil, err
+		}
+		return f.newObject(main, nil, nil), nil
+	}
+
+	// Upload the chunks hashing the whole file as we go
+	remote := src.Remote()
+	if size < 0 && f.Fs.Features().PutStream == nil {
+		return nil, errors.New("can't upload files of unknown size")
+	}
+	txn, err := newTxn()
+	if err != nil {
+		return nil, err
+	}
+	hasher, err := hash.NewMultiHasherTypes(f.Hashes())
+	if err != nil {
+		return nil, err
+	}
+	buf := bufio.NewReader(io.TeeReader(in, hasher))
+	var (
+		chunks []fs.Object
+		total  int64
+	)
+	defer func() {
+		// Remove any chunks uploaded if there was an error
+		if err != nil {
+			for _, c := range chunks {
+				if removeErr := c.Remove(ctx); removeErr != nil {
+					fs.Errorf(c, "Failed to remove chunk after failed upload: %v", removeErr)
+				}
+			}
+		}
+	}()
+	if size < 0 {
+		// Store an empty stream as an empty file
+		if _, err = buf.Peek(1); err == io.EOF {
+			info := object.NewStaticObjectInfo(remote, src.ModTime(), 0, true, nil, f.Fs)
+			main, err := put(ctx, bytes.NewReader(nil), info, options...)
+			if err != nil {
+				return nil, err
+			}
+			return f.newObject(main, nil, nil), nil
+		}
+	}
+	for n := 0; ; n++ {
+		if size < 0 && n > 0 {
+			// Stop at the end of a stream of unknown size
+			if _, err = buf.Peek(1); err == io.EOF {
+				err = nil
+				break
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		chunkSize := int64(f.opt.ChunkSize)
+		if size >= 0 && size-total < chunkSize {
+			chunkSize = size - total
+		} else if size < 0 {
+			chunkSize = -1
+		}
+		counter := readers.NewCountingReader(io.LimitReader(buf, int64(f.opt.ChunkSize)))
+		info := object.NewStaticObjectInfo(chunkName(remote, n, txn), src.ModTime(), chunkSize, true, nil, f.Fs)
+		var c fs.Object
+		if size < 0 {
+			c, err = f.Fs.Features().PutStream(ctx, counter, info, options...)
+		} else {
+			c, err = f.Fs.Put(ctx, counter, info, options...)
+		}
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, c)
+		total += int64(counter.BytesRead())
+		if size >= 0 && total >= size {
+			break
+		}
+		if size >= 0 && int64(counter.BytesRead()) < chunkSize {
+			err = io.ErrUnexpectedEOF
+			return nil, err
+		}
+		if size < 0 && int64(counter.BytesRead()) < int64(f.opt.ChunkSize) {
+			break
+		}
+	}
+	if size >= 0 && total != size {
+		err = errors.Errorf("uploaded %d bytes but expecting %d", total, size)
+		return nil, err
+	}
+
+	// Then write the metadata in place of the file
+	meta := &metadata{
+		Version: metadataVersion,
+		Size:    total,
+		NChunks: len(chunks),
+		Txn:     txn,
+	}
+	sums := hasher.Sums()
+	meta.MD5 = sums[hash.MD5]
+	meta.SHA1 = sums[hash.SHA1]
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	info := object.NewStaticObjectInfo(remote, src.ModTime(), int64(len(data)), true, nil, f.Fs)
+	main, err := put(ctx, bytes.NewReader(data), info)
+	if err != nil {
+		return nil, err
+	}
+	return f.newObject(main, chunks, meta), nil
+}
+
+// removeOldChunks removes the chunks of old which aren't used by o
+func (f *Fs) removeOldChunks(ctx context.Context, old, o *Object) error {
+	inUse := map[string]struct{}{}
+	for _, c := range o.chunks {
+		inUse[c.Remote()] = struct{}{}
+	}
+	var err error
+	for _, c := range old.chunks {
+		if _, found := inUse[c.Remote()]; found {
+			continue
+		}
+		if removeErr := c.Remove(ctx); removeErr != nil {
+			fs.Errorf(c, "Failed to remove old chunk: %v", removeErr)
+			err = removeErr
+		}
+	}
+	return err
+}
+
+// Put in to the remote path with the modTime given of the given size
+//
+// May create the object even if it returns an error - if so
+// will return the object and the error, otherwise will return
+// nil and the error
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	existing, err := f.NewObject(ctx, src.Remote())
+	switch err {
+	case nil:
+		return existing, existing.Update(ctx, in, src, options...)
+	case fs.ErrorObjectNotFound:
+		return f.put(ctx, in, src, options, f.Fs.Put)
+	}
+	return nil, err
+}
+
+// PutStream uploads to the remote path with the modTime given of indeterminate size
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.Put(ctx, in, src, options...)
+}
+
+// Mkdir makes the directory (container, bucket)
+//
+// Shouldn't return an error if it already exists
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
+	return f.Fs.Mkdir(ctx, dir)
+}
+
+// Rmdir removes the directory (container, bucket) if empty
+//
+// Return an error if it doesn't exist or isn't empty
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
+	return f.Fs.Rmdir(ctx, dir)
+}
+
+// Purge all files in the root and the root directory
+//
+// Implement this if you have a way of deleting all the files
+// quicker than just running Remove() on the result of List()
+//
+// Return an error if it doesn't exist
+func (f *Fs) Purge(ctx context.Context) error {
+	do := f.Fs.Features().Purge
+	if do == nil {
+		return fs.ErrorCantPurge
+	}
+	return do(ctx)
+}
+
+type copyMoveFn func(ctx context.Context, src fs.Object, remote string) (fs.Object, error)
+
+// copyOrMove copies or moves src and its chunks to remote using do
+func (f *Fs) copyOrMove(ctx context.Context, src fs.Object, remote string, do copyMoveFn, cantErr error) (fs.Object, error) {
+	o, ok := src.(*Object)
+	if !ok {
+		return nil, cantErr
+	}
+	var existing *Object
+	if dst, err := f.NewObject(ctx, remote); err == nil {
+		existing = dst.(*Object)
+	}
+	var chunks []fs.Object
+	for i, c := range o.chunks {
+		newChunk, err := do(ctx, c, chunkName(remote, i, o.meta.Txn))
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, newChunk)
+	}
+	main, err := do(ctx, o.Object, remote)
+	if err != nil {
+		return nil, err
+	}
+	newObj := f.newObject(main, chunks, o.meta)
+	if existing != nil {
+		err = f.removeOldChunks(ctx, existing, newObj)
+	}
+	return newObj, err
+}
+
+// Copy src to this remote using server side copy operations.
+//
+// This is stored with the remote path given
+//
+// It returns the destination Object and a possible error
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantCopy
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	do := f.Fs.Features().Copy
+	if do == nil {
+		return nil, fs.ErrorCantCopy
+	}
+	return f.copyOrMove(ctx, src, remote, do, fs.ErrorCantCopy)
+}
+
+// Move src to this remote using server side move operations.
+//
+// This is stored with the remote path given
+//
+// It returns the destination Object and a possible error
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantMove
+func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	do := f.Fs.Features().Move
+	if do == nil {
+		return nil, fs.ErrorCantMove
+	}
+	return f.copyOrMove(ctx, src, remote, do, fs.ErrorCantMove)
+}
+
+// DirMove moves src, srcRemote to this remote at dstRemote
+// using server side move operations.
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantDirMove
+//
+// If destination exists then return fs.ErrorDirExists
+func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string) error {
+	do := f.Fs.Features().DirMove
+	if do == nil {
+		return fs.ErrorCantDirMove
+	}
+	srcFs, ok := src.(*Fs)
+	if !ok {
+		fs.Debugf(srcFs, "Can't move directory - not same remote type")
+		return fs.ErrorCantDirMove
+	}
+	return do(ctx, srcFs.Fs, srcRemote, dstRemote)
+}
+
+// CleanUp the trash in the Fs
+//
+// Implement this if you have a way of emptying the trash or
+// otherwise cleaning up old versions of files.
+func (f *Fs) CleanUp(ctx context.Context) error {
+	do := f.Fs.Features().CleanUp
+	if do == nil {
+		return errors.New("can't CleanUp")
+	}
+	return do(ctx)
+}
+
+// About gets quota information from the Fs
+func (f *Fs) About(ctx context.Context) (*fs.Usage, error) {
+	do := f.Fs.Features().About
+	if do == nil {
+		return nil, errors.New("About not supported")
+	}
+	return do(ctx)
+}
+
+// UnWrap returns the Fs that this Fs is wrapping
+func (f *Fs) UnWrap() fs.Fs {
+	return f.Fs
+}
+
+// Object describes a wrapped Object which may be split into chunks
+//
+// The embedded Object is the file itself if it isn't chunked or its
+// metadata if it is.
+type Object struct {
+	fs.Object
+	f      *Fs
+	chunks []fs.Object // nil if not chunked
+	meta   *metadata   // nil if not chunked
+}
+
+func (f *Fs) newObject(main fs.Object, chunks []fs.Object, meta *metadata) *Object {
+	return &Object{
+		Object: main,
+		f:      f,
+		chunks: chunks,
+		meta:   meta,
+	}
+}
+
+// Fs returns read only access to the Fs that this object is part of
+func (o *Object) Fs() fs.Info {
+	return o.f
+}
+
+// Return a string version
+func (o *Object) String() string {
+	if o == nil {
+		return "<nil>"
+	}
+	return o.Remote()
+}
+
+// Size returns the size of the file
+func (o *Object) Size() int64 {
+	if o.meta == nil {
+		return o.Object.Size()
+	}
+	return o.meta.Size
+}
+
+// Hash returns the selected checksum of the file
+// If no checksum is available it returns ""
+func (o *Object) Hash(ctx context.Context, ht hash.Type) (string, error) {
+	if ht == hash.None || ht != o.f.hashType {
+		return "", hash.ErrUnsupported
+	}
+	if o.meta == nil {
+		if !o.f.Fs.Hashes().Contains(ht) {
+			return "", nil
+		}
+		return o.Object.Hash(ctx, ht)
+	}
+	switch ht {
+	case hash.MD5:
+		return o.meta.MD5, nil
+	case hash.SHA1:
+		return o.meta.SHA1, nil
+	}
+	return "", nil
+}
+
+// Open opens the file for read.  Call Close() on the returned io.ReadCloser
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (rc io.ReadCloser, err error) {
+	if o.meta == nil {
+		return o.Object.Open(ctx, options...)
+	}
+	var openOptions []fs.OpenOption
+	var offset, limit int64 = 0, -1
+	for _, option := range options {
+		switch x := option.(type) {
+		case *fs.SeekOption:
+			offset = x.Offset
+		case *fs.RangeOption:
+			offset, limit = x.Decode(o.Size())
+		default:
+			// pass on Options to underlying open if appropriate
+			openOptions = append(openOptions, option)
+		}
+	}
+	if offset < 0 || offset > o.Size() {
+		return nil, errors.Errorf("invalid offset %d for file of size %d", offset, o.Size())
+	}
+	if limit < 0 || offset+limit > o.Size() {
+		limit = o.Size() - offset
+	}
+	return &chunkReader{
+		ctx:     ctx,
+		chunks:  o.chunks,
+		options: openOptions,
+		offset:  offset,
+		left:    limit,
+	}, nil
+}
+
+// chunkReader reads a range of a chunked file opening each chunk
+// as it is needed
+type chunkReader struct {
+	ctx     context.Context
+	chunks  []fs.Object
+	options []fs.OpenOption
+	offset  int64         // offset into the current chunk
+	left    int64         // bytes left to read
+	current io.ReadCloser // the open chunk or nil
+}
+
+// openNext opens the chunk containing the offset
+func (r *chunkReader) openNext() error {
+	// Skip chunks before the offset
+	for len(r.chunks) > 0 && r.offset >= r.chunks[0].Size() {
+		r.offset -= r.chunks[0].Size()
+		r.chunks = r.chunks[1:]
+	}
+	if len(r.chunks) == 0 {
+		return io.ErrUnexpectedEOF
+	}
+	c := r.chunks[0]
+	r.chunks = r.chunks[1:]
+	options := r.options
+	if r.offset > 0 || r.left < c.Size() {
+		end := int64(-1)
+		if r.offset+r.left < c.Size() {
+			end = r.offset + r.left - 1
+		}
+		options = append(options[:len(options):len(options)], &fs.RangeOption{Start: r.offset, End: end})
+	}
+	in, err := c.Open(r.ctx, options...)
+	if err != nil {
+		return err
+	}
+	r.current = in
+	r.offset = 0
+	return nil
+}
+
+// Read bytes from the chunks
+func (r *chunkReader) Read(p []byte) (n int, err error) {
+	for r.left > 0 {
+		if r.current == nil {
+			if err = r.openNext(); err != nil {
+				return 0, err
+			}
+		}
+		if int64(len(p)) > r.left {
+			p = p[:r.left]
+		}
+		n, err = r.current.Read(p)
+		r.left -= int64(n)
+		if err == io.EOF {
+			err = r.current.Close()
+			r.current = nil
+		}
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+	return 0, io.EOF
+}
+
+// Close the chunk being read
+func (r *chunkReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+	err := r.current.Close()
+	r.current = nil
+	return err
+}
+
+// Update in to the object with the modTime given of the given size
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	update := func(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+		return o.Object, o.Object.Update(ctx, in, src, options...)
+	}
+	newObj, err := o.f.put(ctx, in, src, options, update)
+	if err != nil {
+		return err
+	}
+	err = o.f.removeOldChunks(ctx, o, newObj)
+	*o = *newObj
+	return err
+}
+
+// Remove an object
+func (o *Object) Remove(ctx context.Context) error {
+	// Remove the metadata first so the file disappears at once
+	err := o.Object.Remove(ctx)
+	if err != nil {
+		return err
+	}
+	for _, c := range o.chunks {
+		if removeErr := c.Remove(ctx); removeErr != nil {
+			fs.Errorf(c, "Failed to remove chunk: %v", removeErr)
+			err = removeErr
+		}
+	}
+	return err
+}
+
+// Check the interfaces are satisfied
+var (
+	_ fs.Fs          = (*Fs)(nil)
+	_ fs.Purger      = (*Fs)(nil)
+	_ fs.Copier      = (*Fs)(nil)
+	_ fs.Mover       = (*Fs)(nil)
+	_ fs.DirMover    = (*Fs)(nil)
+	_ fs.PutStreamer = (*Fs)(nil)
+	_ fs.CleanUpper  = (*Fs)(nil)
+	_ fs.UnWrapper   = (*Fs)(nil)
+	_ fs.Abouter     = (*Fs)(nil)
+	_ fs.Object      = (*Object)(nil)
+)
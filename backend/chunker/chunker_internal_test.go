@@ -0,0 +1,280 @@
+package chunker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	_ "github.com/ncw/rclone/backend/local"
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/config/configmap"
+	"github.com/ncw/rclone/fs/hash"
+	"github.com/ncw/rclone/fs/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkName(t *testing.T) {
+	name := chunkName("dir/file.txt", 0, "0123abcd")
+	assert.Equal(t, "dir/file.txt.rclone_chunk.001_0123abcd", name)
+	remote, n, txn, ok := parseChunkName(name)
+	assert.True(t, ok)
+	assert.Equal(t, "dir/file.txt", remote)
+	assert.Equal(t, 0, n)
+	assert.Equal(t, "0123abcd", txn)
+
+	remote, n, _, ok = parseChunkName(chunkName("file", 1233, "ff"))
+	assert.True(t, ok)
+	assert.Equal(t, "file", remote)
+	assert.Equal(t, 1233, n)
+
+	for _, bad := range []string{
+		"file",
+		"file.rclone_chunk.001",
+		"file.rclone_chunk.01_abcd",
+		"file.rclone_chunk.000_abcd",
+		"file.rclone_chunk.001_ABCD",
+		".rclone_chunk.001_abcd",
+	} {
+		_, _, _, ok = parseChunkName(bad)
+		assert.False(t, ok, bad)
+	}
+}
+
+// newTestFs makes a chunker with chunkSize over a temporary
+// directory returning it, the wrapped Fs and a cleanup function
+func newTestFs(t *testing.T, chunkSize string) (*Fs, fs.Fs, func()) {
+	dir, err := ioutil.TempDir("", "rclone-chunker-test")
+	require.NoError(t, err)
+	f, err := NewFs("chunker", "", configmap.Simple{
+		"remote":     dir,
+		"chunk_size": chunkSize,
+		"hash_type":  "md5",
+	})
+	require.NoError(t, err)
+	return f.(*Fs), f.(*Fs).Fs, func() {
+		_ = os.RemoveAll(dir)
+	}
+}
+
+// put uploads contents as remote
+func put(t *testing.T, f fs.Fs, remote string, contents []byte, size int64) fs.Object {
+	ctx := context.Background()
+	src := object.NewStaticObjectInfo(remote, time.Now(), size, true, nil, nil)
+	var o fs.Object
+	var err error
+	if size < 0 {
+		o, err = f.Features().PutStream(ctx, bytes.NewReader(contents), src)
+	} else {
+		o, err = f.Put(ctx, bytes.NewReader(contents), src)
+	}
+	require.NoError(t, err)
+	return o
+}
+
+// wrappedNames lists the names in the root of the wrapped Fs
+func wrappedNames(t *testing.T, f fs.Fs) (names []string) {
+	entries, err := f.List(context.Background(), "")
+	require.NoError(t, err)
+	for _, entry := range entries {
+		names = append(names, entry.Remote())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// read reads o with options
+func read(t *testing.T, o fs.Object, options ...fs.OpenOption) string {
+	in, err := o.Open(context.Background(), options...)
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(in)
+	require.NoError(t, err)
+	require.NoError(t, in.Close())
+	return string(data)
+}
+
+func TestChunkedFile(t *testing.T) {
+	f, wrapped, cleanup := newTestFs(t, "10b")
+	defer cleanup()
+	ctx := context.Background()
+	contents := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+
+	// Small files are stored as is
+	put(t, f, "small", contents[:10], 10)
+	assert.Equal(t, []string{"small"}, wrappedNames(t, wrapped))
+
+	// Large files are split
+	o := put(t, f, "large", contents, int64(len(contents)))
+	names := wrappedNames(t, wrapped)
+	require.Len(t, names, 6)
+	assert.Equal(t, "large", names[0])
+	for i, name := range names[1:5] {
+		remote, n, _, ok := parseChunkName(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, "large", remote)
+		assert.Equal(t, i, n)
+	}
+	assert.Equal(t, "small", names[5])
+
+	// The chunks are hidden in listings
+	entries, err := f.List(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	sizes := map[string]int64{}
+	for _, entry := range entries {
+		sizes[entry.Remote()] = entry.(fs.Object).Size()
+	}
+	assert.Equal(t, map[string]int64{"large": int64(len(contents)), "small": 10}, sizes)
+
+	// Check the hash of the whole file is stored
+	md5, err := o.Hash(ctx, hash.MD5)
+	require.NoError(t, err)
+	assert.Equal(t, "e9b1713db620f1e3a14b6812de523f4b", md5)
+
+	// Check reading ranges across chunks
+	o, err = f.NewObject(ctx, "large")
+	require.NoError(t, err)
+	assert.Equal(t, string(contents), read(t, o))
+	assert.Equal(t, "89abcdefghijk", read(t, o, &fs.RangeOption{Start: 8, End: 20}))
+	assert.Equal(t, "uvwxyz", read(t, o, &fs.RangeOption{Start: -1, End: 6}))
+	assert.Equal(t, "abcdefghijklmnopqrstuvwxyz", read(t, o, &fs.SeekOption{Offset: 10}))
+	assert.Equal(t, "", read(t, o, &fs.SeekOption{Offset: int64(len(contents))}))
+
+	// Orphaned chunks are hidden
+	orphan := chunkName("orphan", 0, "abcd")
+	put(t, wrapped, orphan, []byte("potato"), 6)
+	entries, err = f.List(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+	_, err = f.NewObject(ctx, orphan)
+	assert.Equal(t, fs.ErrorObjectNotFound, err)
+
+	// Updating to a small file removes the chunks
+	require.NoError(t, o.Update(ctx, bytes.NewReader(contents[:5]), object.NewStaticObjectInfo("large", time.Now(), 5, true, nil, nil)))
+	assert.Equal(t, []string{"large", orphan, "small"}, wrappedNames(t, wrapped))
+	assert.Equal(t, "01234", read(t, o))
+
+	// Removing a chunked file removes the chunks
+	o = put(t, f, "large", contents, int64(len(contents)))
+	assert.Len(t, wrappedNames(t, wrapped), 7)
+	require.NoError(t, o.Remove(ctx))
+	assert.Equal(t, []string{orphan, "small"}, wrappedNames(t, wrapped))
+}
+
+func TestChunkedStream(t *testing.T) {
+	f, wrapped, cleanup := newTestFs(t, "10b")
+	defer cleanup()
+	ctx := context.Background()
+
+	// Streams of unknown size are chunked
+	contents := []byte("0123456789abcdefghij")
+	o := put(t, f, "stream", contents, -1)
+	assert.Equal(t, int64(20), o.Size())
+	assert.Len(t, wrappedNames(t, wrapped), 3)
+	o, err := f.NewObject(ctx, "stream")
+	require.NoError(t, err)
+	assert.Equal(t, string(contents), read(t, o))
+
+	// Empty streams are stored as empty files
+	o = put(t, f, "empty", nil, -1)
+	assert.Equal(t, int64(0), o.Size())
+	assert.Equal(t, []string{"empty"}, wrappedNames(t, wrapped)[:1])
+}
+
+// errorReader returns an error after reading N bytes
+type errorReader struct {
+	n int
+}
+
+func (r *errorReader) Read(p []byte) (int, error) {
+	if r.n <= 0 {
+		return 0, errors.New("potato")
+	}
+	if len(p) > r.n {
+		p = p[:r.n]
+	}
+	for i := range p {
+		p[i] = 'x'
+	}
+	r.n -= len(p)
+	return len(p), nil
+}
+
+func TestChunkedFailedUpdate(t *testing.T) {
+	f, wrapped, cleanup := newTestFs(t, "10b")
+	defer cleanup()
+	ctx := context.Background()
+	contents := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	o := put(t, f, "file", contents, int64(len(contents)))
+	before := wrappedNames(t, wrapped)
+
+	// A failed update leaves the old file intact and no new chunks
+	err := o.Update(ctx, &errorReader{n: 25}, object.NewStaticObjectInfo("file", time.Now(), 100, true, nil, nil))
+	require.Error(t, err)
+	assert.Equal(t, before, wrappedNames(t, wrapped))
+	o, err = f.NewObject(ctx, "file")
+	require.NoError(t, err)
+	assert.Equal(t, string(contents), read(t, o))
+}
+
+func TestChunkedInconsistent(t *testing.T) {
+	f, wrapped, cleanup := newTestFs(t, "10b")
+	defer cleanup()
+	ctx := context.Background()
+	contents := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	put(t, f, "good", contents, int64(len(contents)))
+
+	// Remove a chunk from a chunked file
+	o := put(t, f, "missing", contents, int64(len(contents)))
+	chunk, err := wrapped.NewObject(ctx, chunkName("missing", 1, o.(*Object).meta.Txn))
+	require.NoError(t, err)
+	require.NoError(t, chunk.Remove(ctx))
+
+	// Add a stray chunk with a number beyond the end
+	o = put(t, f, "stray", contents, int64(len(contents)))
+	put(t, wrapped, chunkName("stray", 7, o.(*Object).meta.Txn), []byte("potato"), 6)
+
+	// Metadata with an impossible chunk count
+	bad := []byte(`{"ver":1,"size":2,"nchunks":3,"txn":"abcd"}`)
+	put(t, wrapped, "bad", bad, int64(len(bad)))
+	put(t, wrapped, chunkName("bad", 0, "abcd"), []byte("x"), 1)
+
+	// The listing must still work, showing the broken files as
+	// normal files, and agree with NewObject
+	entries, err := f.List(ctx, "")
+	require.NoError(t, err)
+	sizes := map[string]int64{}
+	for _, entry := range entries {
+		sizes[entry.Remote()] = entry.(fs.Object).Size()
+		o, err := f.NewObject(ctx, entry.Remote())
+		require.NoError(t, err)
+		assert.Equal(t, o.Size(), entry.(fs.Object).Size(), entry.Remote())
+	}
+	assert.Equal(t, int64(len(contents)), sizes["good"])
+	assert.Equal(t, int64(len(contents)), sizes["stray"])
+	assert.NotEqual(t, int64(len(contents)), sizes["missing"])
+	assert.Equal(t, int64(len(bad)), sizes["bad"])
+	assert.Len(t, sizes, 4)
+}
+
+// Check the chunk files really contain the data
+func TestChunkContents(t *testing.T) {
+	f, wrapped, cleanup := newTestFs(t, "4b")
+	defer cleanup()
+	put(t, f, "file", []byte("abcdefghij"), 10)
+	var chunks []string
+	for _, name := range wrappedNames(t, wrapped) {
+		if _, _, _, ok := parseChunkName(name); ok {
+			data, err := ioutil.ReadFile(filepath.Join(wrapped.Root(), name))
+			require.NoError(t, err)
+			chunks = append(chunks, string(data))
+		}
+	}
+	assert.Equal(t, []string{"abcd", "efgh", "ij"}, chunks)
+}
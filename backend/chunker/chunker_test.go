@@ -0,0 +1,62 @@
+// Test the Chunker filesystem interface
+package chunker_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ncw/rclone/backend/chunker"
+	_ "github.com/ncw/rclone/backend/local"
+	"github.com/ncw/rclone/fstest"
+	"github.com/ncw/rclone/fstest/fstests"
+)
+
+// TestIntegration runs integration tests against the remote
+func TestIntegration(t *testing.T) {
+	if *fstest.RemoteName == "" {
+		t.Skip("Skipping as -remote not set")
+	}
+	fstests.Run(t, &fstests.Opt{
+		RemoteName: *fstest.RemoteName,
+		NilObject:  (*chunker.Object)(nil),
+	})
+}
+
+// TestStandard runs integration tests against a local remote using
+// small chunks so most files are split
+func TestStandard(t *testing.T) {
+	if *fstest.RemoteName != "" {
+		t.Skip("Skipping as -remote set")
+	}
+	tempdir := filepath.Join(os.TempDir(), "rclone-chunker-test-standard")
+	name := "TestChunker"
+	fstests.Run(t, &fstests.Opt{
+		RemoteName: name + ":",
+		NilObject:  (*chunker.Object)(nil),
+		ExtraConfig: []fstests.ExtraConfigItem{
+			{Name: name, Key: "type", Value: "chunker"},
+			{Name: name, Key: "remote", Value: tempdir},
+			{Name: name, Key: "chunk_size", Value: "30b"},
+		},
+	})
+}
+
+// TestSHA1 runs integration tests storing SHA1 hashes
+func TestSHA1(t *testing.T) {
+	if *fstest.RemoteName != "" {
+		t.Skip("Skipping as -remote set")
+	}
+	tempdir := filepath.Join(os.TempDir(), "rclone-chunker-test-sha1")
+	name := "TestChunker2"
+	fstests.Run(t, &fstests.Opt{
+		RemoteName: name + ":",
+		NilObject:  (*chunker.Object)(nil),
+		ExtraConfig: []fstests.ExtraConfigItem{
+			{Name: name, Key: "type", Value: "chunker"},
+			{Name: name, Key: "remote", Value: tempdir},
+			{Name: name, Key: "chunk_size", Value: "64b"},
+			{Name: name, Key: "hash_type", Value: "sha1"},
+		},
+	})
+}
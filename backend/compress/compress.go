@@ -0,0 +1,1205 @@
+// Package compress provides wrappers for Fs and Object which compress
+// the data stored
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/config/configmap"
+	"github.com/ncw/rclone/fs/config/configstruct"
+	"github.com/ncw/rclone/fs/fspath"
+	"github.com/ncw/rclone/fs/hash"
+	"github.com/ncw/rclone/fs/object"
+	"github.com/pkg/errors"
+)
+
+const (
+	// binSuffix is added to the names of files stored uncompressed
+	binSuffix = ".bin"
+	// trailerMagic ends every compressed object
+	trailerMagic = "rclcmpr1"
+	// trailerSize is the size of the fixed part of the trailer -
+	// the length of the metadata and the magic
+	trailerSize = 8 + len(trailerMagic)
+	// tailReadSize is how much of the end of a compressed object is
+	// read to find the metadata in one go
+	tailReadSize = 4096
+	// metadataVersion is the version of the metadata format written
+	metadataVersion = 1
+	// listingCacheTime is how long a listing of a directory in the
+	// wrapped remote is used to find compressed objects for
+	listingCacheTime = 10 * time.Second
+)
+
+// compressedRe matches the name of a compressed object returning the
+// file name, its uncompressed size in hex and the suffix
+var compressedRe = regexp.MustCompile(`^(.+)\.([0-9a-f]+)(\.[a-z]+)$`)
+
+// suffixes maps the suffix of a compressed object to its mode
+var suffixes = map[string]string{
+	".gz":  "gzip",
+	".zst": "zstd",
+}
+
+// Register with Fs
+func init() {
+	fs.Register(&fs.RegInfo{
+		Name:        "compress",
+		Description: "Compress a remote",
+		NewFs:       NewFs,
+		Options: []fs.Option{{
+			Name:     "remote",
+			Help:     "Remote to compress.\nNormally should contain a ':' and a path, eg \"myremote:path/to/dir\",\n\"myremote:bucket\" or maybe \"myremote:\" (not recommended).",
+			Required: true,
+		}, {
+			Name:    "mode",
+			Help:    "Compression mode.",
+			Default: "gzip",
+			Examples: []fs.OptionExample{
+				{
+					Value: "gzip",
+					Help:  "Standard gzip compression.",
+				}, {
+					Value: "zstd",
+					Help:  "Zstandard compression - faster with a better ratio.",
+				},
+			},
+		}, {
+			Name: "level",
+			Help: `Compression level.
+
+-1 uses the default level for the mode. gzip levels go from 1 (fastest)
+to 9 (best compression), zstd levels from 1 to 22.`,
+			Default:  -1,
+			Advanced: true,
+		}, {
+			Name: "block_size",
+			Help: `Size of the independently compressed blocks.
+
+Each block of this much uncompressed data is compressed separately so
+reads can start at the block containing the offset wanted.  Smaller
+blocks make seeking cheaper but compress less well.`,
+			Default:  fs.SizeSuffix(1024 * 1024),
+			Advanced: true,
+		}},
+	})
+}
+
+// Options defines the configuration for this backend
+type Options struct {
+	Remote    string        `config:"remote"`
+	Mode      string        `config:"mode"`
+	Level     int           `config:"level"`
+	BlockSize fs.SizeSuffix `config:"block_size"`
+}
+
+// NewFs contstructs an Fs from the path, container:path
+func NewFs(name, rpath string, m configmap.Mapper) (fs.Fs, error) {
+	// Parse config into Options struct
+	opt := new(Options)
+	err := configstruct.Set(m, opt)
+	if err != nil {
+		return nil, err
+	}
+	if opt.BlockSize <= 0 {
+		return nil, errors.New("block_size must be greater than 0")
+	}
+	if opt.Mode == "" {
+		opt.Mode = "gzip"
+	}
+	comp, ok := compressors[opt.Mode]
+	if !ok {
+		return nil, errors.Errorf("compression mode %q not supported", opt.Mode)
+	}
+	remote := opt.Remote
+	if strings.HasPrefix(remote, name+":") {
+		return nil, errors.New("can't point compress remote at itself - check the value of the remote setting")
+	}
+	wInfo, wName, wPath, wConfig, err := fs.ConfigFs(remote)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse remote %q to wrap", remote)
+	}
+	remotePath := fspath.JoinRootPath(wPath, rpath)
+	wrappedFs, err := wInfo.NewFs(wName, remotePath, wConfig)
+	if err != fs.ErrorIsFile && err != nil {
+		return nil, errors.Wrapf(err, "failed to make remote %s:%q to wrap", wName, remotePath)
+	}
+	f := &Fs{
+		Fs:       wrappedFs,
+		name:     name,
+		root:     rpath,
+		opt:      *opt,
+		comp:     comp,
+		listings: newListingCache(),
+	}
+	// The wrapped remote can't tell if rpath is a file as the names
+	// are different there, so check for it in the parent directory
+	if err == nil && rpath != "" {
+		parent, leaf := path.Dir(rpath), path.Base(rpath)
+		if parent == "." || parent == "/" {
+			parent = ""
+		}
+		parentFs, parentErr := wInfo.NewFs(wName, fspath.JoinRootPath(wPath, parent), wConfig)
+		if parentErr == nil {
+			pf := *f
+			pf.Fs = parentFs
+			pf.root = parent
+			pf.listings = newListingCache()
+			if _, parentErr = pf.NewObject(context.Background(), leaf); parentErr == nil {
+				f.Fs = parentFs
+				f.root = parent
+				wrappedFs = parentFs
+				err = fs.ErrorIsFile
+			}
+		}
+	}
+	// the features here are ones we could support, and they are
+	// ANDed with the ones from wrappedFs
+	f.features = (&fs.Features{
+		CaseInsensitive:         true,
+		DuplicateFiles:          true,
+		ReadMimeType:            false,
+		WriteMimeType:           false,
+		BucketBased:             true,
+		CanHaveEmptyDirectories: true,
+	}).Fill(f).Mask(wrappedFs).WrapsFs(f, wrappedFs)
+	// Streams are spooled if the wrapped remote can't take them
+	f.features.PutStream = f.PutStream
+
+	doChangeNotify := wrappedFs.Features().ChangeNotify
+	if doChangeNotify != nil {
+		f.features.ChangeNotify = func(ctx context.Context, notifyFunc func(string, fs.EntryType), pollInterval <-chan time.Duration) {
+			wrappedNotifyFunc := func(path string, entryType fs.EntryType) {
+				if entryType == fs.EntryObject {
+					f.listings.forget(wrappedDir(path))
+					if remote, _, _, ok := parseName(path); ok {
+						path = remote
+					}
+				}
+				notifyFunc(path, entryType)
+			}
+			doChangeNotify(ctx, wrappedNotifyFunc, pollInterval)
+		}
+	}
+
+	return f, err
+}
+
+// Fs represents a wrapped fs.Fs
+type Fs struct {
+	fs.Fs
+	name     string
+	root     string
+	opt      Options
+	features *fs.Features  // optional features
+	comp     *compressor   // compressor for uploads
+	listings *listingCache // recent listings of the wrapped remote
+}
+
+// Name of the remote (as passed into NewFs)
+func (f *Fs) Name() string {
+	return f.name
+}
+
+// Root of the remote (as passed into NewFs)
+func (f *Fs) Root() string {
+	return f.root
+}
+
+// Features returns the optional features of this Fs
+func (f *Fs) Features() *fs.Features {
+	return f.features
+}
+
+// String returns a description of the FS
+func (f *Fs) String() string {
+	return fmt.Sprintf("Compressed '%s:%s'", f.name, f.root)
+}
+
+// Hashes returns the supported hash sets.
+func (f *Fs) Hashes() hash.Set {
+	return hash.Set(hash.MD5 | hash.SHA1)
+}
+
+// resetWriteCloser is a compressing writer which can be reused
+type resetWriteCloser interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+// compressor describes a compression mode
+type compressor struct {
+	suffix    string
+	newWriter func(level int) (resetWriteCloser, error)
+	newReader func(in io.Reader) (io.ReadCloser, error)
+}
+
+// compressors are the supported compression modes by name
+var compressors = map[string]*compressor{
+	"gzip": {
+		suffix: ".gz",
+		newWriter: func(level int) (resetWriteCloser, error) {
+			return gzip.NewWriterLevel(ioutil.Discard, level)
+		},
+		newReader: func(in io.Reader) (io.ReadCloser, error) {
+			return gzip.NewReader(in)
+		},
+	},
+}
+
+// compressedName returns the name of remote compressed with suffix
+// when its uncompressed size is size
+func compressedName(remote string, size int64, suffix string) string {
+	return remote + "." + strconv.FormatInt(size, 16) + suffix
+}
+
+// parseName parses the name of an object in the wrapped remote
+// returning the file name, its size if compressed and whether it is
+// compressed.  ok is false if the name isn't one this backend makes.
+func parseName(name string) (remote string, size int64, compressed bool, ok bool) {
+	if strings.HasSuffix(name, binSuffix) && len(name) > len(binSuffix) {
+		return name[:len(name)-len(binSuffix)], -1, false, true
+	}
+	match := compressedRe.FindStringSubmatch(name)
+	if match == nil {
+		return "", 0, false, false
+	}
+	if _, known := suffixes[match[3]]; !known {
+		return "", 0, false, false
+	}
+	size, err := strconv.ParseInt(match[2], 16, 64)
+	if err != nil {
+		return "", 0, false, false
+	}
+	return match[1], size, true, true
+}
+
+// alreadyCompressed are MIME types which don't compress any further
+var alreadyCompressed = map[string]bool{
+	"application/gzip":                        true,
+	"application/x-gzip":                      true,
+	"application/zip":                         true,
+	"application/x-zip-compressed":            true,
+	"application/x-7z-compressed":             true,
+	"application/x-rar-compressed":            true,
+	"application/vnd.rar":                     true,
+	"application/x-bzip2":                     true,
+	"application/x-xz":                        true,
+	"application/x-lzma":                      true,
+	"application/zstd":                        true,
+	"application/x-zstd":                      true,
+	"application/x-compress":                  true,
+	"application/java-archive":                true,
+	"application/epub+zip":                    true,
+	"application/pdf":                         true,
+	"application/vnd.ms-cab-compressed":       true,
+	"application/vnd.android.package-archive": true,
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   true,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         true,
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": true,
+	"application/vnd.oasis.opendocument.text":                                   true,
+	"application/vnd.oasis.opendocument.spreadsheet":                            true,
+	"application/vnd.oasis.opendocument.presentation":                           true,
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// alreadyCompressedExt are file extensions which don't compress any
+// further - these are checked too as the MIME types of many of them
+// aren't known on all systems
+var alreadyCompressedExt = map[string]bool{
+	".gz": true, ".tgz": true, ".zip": true, ".7z": true, ".rar": true,
+	".bz2": true, ".xz": true, ".lzma": true, ".zst": true, ".lz4": true,
+	".z": true, ".jar": true, ".apk": true, ".epub": true, ".pdf": true,
+	".docx": true, ".xlsx": true, ".pptx": true, ".odt": true, ".ods": true,
+	".odp": true, ".jpg": true, ".jpeg": true, ".png": true, ".gif": true,
+	".webp": true, ".mp3": true, ".mp4": true, ".m4a": true, ".m4v": true,
+	".mkv": true, ".avi": true, ".mov": true, ".webm": true, ".ogg": true,
+	".flac": true, ".aac": true, ".opus": true,
+}
+
+// isCompressible returns whether src is worth compressing
+func isCompressible(src fs.ObjectInfo) bool {
+	if alreadyCompressedExt[strings.ToLower(path.Ext(src.Remote()))] {
+		return false
+	}
+	mimeType := fs.MimeType(src)
+	if i := strings.IndexByte(mimeType, ';'); i >= 0 {
+		mimeType = mimeType[:i]
+	}
+	mimeType = strings.TrimSpace(strings.ToLower(mimeType))
+	if strings.HasPrefix(mimeType, "video/") || strings.HasPrefix(mimeType, "audio/") {
+		return false
+	}
+	return !alreadyCompressed[mimeType]
+}
+
+// metadata is stored in the trailer of a compressed object
+type metadata struct {
+	Version   int     `json:"ver"`
+	Mode      string  `json:"mode"`
+	Size      int64   `json:"size"`      // uncompressed size
+	BlockSize int64   `json:"blocksize"` // uncompressed size of each block
+	Blocks    []int64 `json:"blocks"`    // offset of the start of each block in the compressed data
+	MD5       string  `json:"md5,omitempty"`
+	SHA1      string  `json:"sha1,omitempty"`
+	dataSize  int64   // size of the compressed data without the trailer
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write writes p to the underlying writer counting the bytes
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// compress reads all of in and writes it compressed to out followed
+// by the trailer, returning the metadata written.
+//
+// Each block of the input is compressed separately so the blocks can
+// be decompressed independently.
+func (f *Fs) compress(in io.Reader, out io.Writer) (*metadata, error) {
+	hasher, err := hash.NewMultiHasherTypes(f.Hashes())
+	if err != nil {
+		return nil, err
+	}
+	in = io.TeeReader(in, hasher)
+	w, err := f.comp.newWriter(f.opt.Level)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make compressor")
+	}
+	meta := &metadata{
+		Version:   metadataVersion,
+		Mode:      f.opt.Mode,
+		BlockSize: int64(f.opt.BlockSize),
+		Blocks:    []int64{},
+	}
+	cw := &countingWriter{w: out}
+	for {
+		start := cw.n
+		w.Reset(cw)
+		n, err := io.CopyN(w, in, meta.BlockSize)
+		if n > 0 {
+			if closeErr := w.Close(); closeErr != nil {
+				return nil, errors.Wrap(closeErr, "failed to compress")
+			}
+			meta.Blocks = append(meta.Blocks, start)
+			meta.Size += n
+		}
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	sums := hasher.Sums()
+	meta.MD5 = sums[hash.MD5]
+	meta.SHA1 = sums[hash.SHA1]
+	meta.dataSize = cw.n
+
+	// Write the trailer
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal metadata")
+	}
+	var fixed [trailerSize]byte
+	binary.BigEndian.PutUint64(fixed[:8], uint64(len(data)))
+	copy(fixed[8:], trailerMagic)
+	if _, err = cw.Write(data); err != nil {
+		return nil, err
+	}
+	if _, err = cw.Write(fixed[:]); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// readRange reads the bytes from start to end inclusive of o
+func readRange(ctx context.Context, o fs.Object, start, end int64) (data []byte, err error) {
+	in, err := o.Open(ctx, &fs.RangeOption{Start: start, End: end})
+	if err != nil {
+		return nil, err
+	}
+	defer fs.CheckClose(in, &err)
+	data, err = ioutil.ReadAll(io.LimitReader(in, end-start+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) != end-start+1 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return data, nil
+}
+
+// readMetadata reads the metadata from the trailer of the compressed
+// object o
+func readMetadata(ctx context.Context, o fs.Object) (*metadata, error) {
+	size := o.Size()
+	if size < int64(trailerSize) {
+		return nil, errors.New("compressed file too short")
+	}
+	tailSize := int64(tailReadSize)
+	if tailSize > size {
+		tailSize = size
+	}
+	tail, err := readRange(ctx, o, size-tailSize, size-1)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read metadata")
+	}
+	fixed := tail[len(tail)-trailerSize:]
+	if string(fixed[8:]) != trailerMagic {
+		return nil, errors.New("compressed file has bad trailer")
+	}
+	metaSize := int64(binary.BigEndian.Uint64(fixed[:8]))
+	if metaSize > size-int64(trailerSize) {
+		return nil, errors.New("compressed file has bad metadata size")
+	}
+	dataSize := size - int64(trailerSize) - metaSize
+	var data []byte
+	if metaSize <= tailSize-int64(trailerSize) {
+		data = tail[tailSize-int64(trailerSize)-metaSize : tailSize-int64(trailerSize)]
+	} else {
+		data, err = readRange(ctx, o, dataSize, dataSize+metaSize-1)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read metadata")
+		}
+	}
+	meta := new(metadata)
+	err = json.Unmarshal(data, meta)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode metadata")
+	}
+	if meta.Version != metadataVersion {
+		return nil, errors.Errorf("unknown metadata version %d", meta.Version)
+	}
+	if meta.BlockSize <= 0 || int64(len(meta.Blocks)) != (meta.Size+meta.BlockSize-1)/meta.BlockSize {
+		return nil, errors.New("compressed file has bad block index")
+	}
+	meta.dataSize = dataSize
+	return meta, nil
+}
+
+// List the objects and directories in dir into entries.  The
+// entries can be returned in any order but should be for a
+// complete directory.
+//
+// dir should be "" to list the root, and should not have
+// trailing slashes.
+//
+// This should return ErrDirNotFound if the directory isn't
+// found.
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	entries, err = f.Fs.List(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	f.listings.put(dir, compressedObjects(entries))
+	newEntries := entries[:0]
+	for _, entry := range entries {
+		switch x := entry.(type) {
+		case fs.Object:
+			o := f.newObject(x)
+			if o == nil {
+				fs.Debugf(x, "Skipping file not made by compress")
+				continue
+			}
+			newEntries = append(newEntries, o)
+		case fs.Directory:
+			newEntries = append(newEntries, x)
+		default:
+			return nil, errors.Errorf("Unknown object type %T", entry)
+		}
+	}
+	return newEntries, nil
+}
+
+// NewObject finds the Object at remote.
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	o, err := f.Fs.NewObject(ctx, remote+binSuffix)
+	if err == nil {
+		return f.newObject(o), nil
+	} else if err != fs.ErrorObjectNotFound {
+		return nil, err
+	}
+	// Compressed objects have the size in the name so look for
+	// them in the directory listing
+	x, listed := f.listings.find(remote)
+	if !listed {
+		dir := wrappedDir(remote)
+		entries, err := f.Fs.List(ctx, dir)
+		if err == fs.ErrorDirNotFound {
+			return nil, fs.ErrorObjectNotFound
+		} else if err != nil {
+			return nil, err
+		}
+		objects := compressedObjects(entries)
+		x = objects[remote]
+		f.listings.put(dir, objects)
+	}
+	if x == nil {
+		return nil, fs.ErrorObjectNotFound
+	}
+	return f.newObject(x), nil
+}
+
+// compressedObjects returns the compressed objects in entries by the
+// name they are listed as
+func compressedObjects(entries fs.DirEntries) map[string]fs.Object {
+	objects := make(map[string]fs.Object)
+	for _, entry := range entries {
+		x, ok := entry.(fs.Object)
+		if !ok {
+			continue
+		}
+		if remote, _, compressed, ok := parseName(x.Remote()); ok && compressed {
+			objects[remote] = x
+		}
+	}
+	return objects
+}
+
+// wrappedDir returns the directory in the wrapped remote remote is in
+func wrappedDir(remote string) string {
+	dir := path.Dir(remote)
+	if dir == "." || dir == "/" {
+		dir = ""
+	}
+	return dir
+}
+
+// listingCache holds recent listings of directories in the wrapped
+// remote so NewObject doesn't have to list the directory again for
+// each compressed object it looks for.
+//
+// Objects written or removed through the Fs are kept up to date in
+// it, but changes made elsewhere may not be seen until the listing
+// expires after listingCacheTime.
+type listingCache struct {
+	mu   sync.Mutex
+	dirs map[string]*dirListing
+}
+
+// dirListing is the compressed objects in a directory of the wrapped
+// remote by the name they are listed as
+type dirListing struct {
+	expires time.Time
+	objects map[string]fs.Object
+}
+
+// newListingCache makes an empty listingCache
+func newListingCache() *listingCache {
+	return &listingCache{
+		dirs: make(map[string]*dirListing),
+	}
+}
+
+// put stores objects, from compressedObjects, as the listing of dir
+func (c *listingCache) put(dir string, objects map[string]fs.Object) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name, listing := range c.dirs {
+		if now.After(listing.expires) {
+			delete(c.dirs, name)
+		}
+	}
+	c.dirs[dir] = &dirListing{
+		expires: now.Add(listingCacheTime),
+		objects: objects,
+	}
+}
+
+// find looks for the compressed object listed as remote returning
+// nil if it isn't there.  listed is false if there is no unexpired
+// listing of its directory.
+func (c *listingCache) find(remote string) (o fs.Object, listed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	listing, ok := c.dirs[wrappedDir(remote)]
+	if !ok || time.Now().After(listing.expires) {
+		return nil, false
+	}
+	return listing.objects[remote], true
+}
+
+// add records the wrapped object o in the listing of its directory
+// if there is one
+func (c *listingCache) add(o fs.Object) {
+	remote, _, compressed, ok := parseName(o.Remote())
+	if !ok || !compressed {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if listing, ok := c.dirs[wrappedDir(remote)]; ok {
+		listing.objects[remote] = o
+	}
+}
+
+// remove removes the wrapped object o from the listing of its
+// directory if it is there
+func (c *listingCache) remove(o fs.Object) {
+	remote, _, compressed, ok := parseName(o.Remote())
+	if !ok || !compressed {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	listing, ok := c.dirs[wrappedDir(remote)]
+	if !ok {
+		return
+	}
+	if x, ok := listing.objects[remote]; ok && x.Remote() == o.Remote() {
+		delete(listing.objects, remote)
+	}
+}
+
+// forget removes the listing of dir
+func (c *listingCache) forget(dir string) {
+	c.mu.Lock()
+	delete(c.dirs, dir)
+	c.mu.Unlock()
+}
+
+// flush removes all the listings
+func (c *listingCache) flush() {
+	c.mu.Lock()
+	c.dirs = make(map[string]*dirListing)
+	c.mu.Unlock()
+}
+
+// renamedObjectInfo is src stored under a different remote
+type renamedObjectInfo struct {
+	fs.ObjectInfo
+	remote string
+}
+
+// Remote returns the new remote
+func (o *renamedObjectInfo) Remote() string {
+	return o.remote
+}
+
+// put uploads in compressing it if it is compressible.  If existing
+// is set it is updated if it has the same name in the wrapped remote.
+//
+// It doesn't remove existing if the upload has a different name.
+func (f *Fs) put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options []fs.OpenOption, existing *Object) (*Object, error) {
+	remote := src.Remote()
+	upload := func(in io.Reader, info fs.ObjectInfo) (o fs.Object, err error) {
+		if existing != nil && existing.Object.Remote() == info.Remote() {
+			o, err = existing.Object, existing.Object.Update(ctx, in, info, options...)
+		} else if info.Size() < 0 {
+			o, err = f.Fs.Features().PutStream(ctx, in, info, options...)
+		} else {
+			o, err = f.Fs.Put(ctx, in, info, options...)
+		}
+		if err == nil {
+			f.listings.add(o)
+		}
+		return o, err
+	}
+
+	if !isCompressible(src) {
+		if src.Size() < 0 && f.Fs.Features().PutStream == nil {
+			return nil, errors.New("can't upload files of unknown size")
+		}
+		o, err := upload(in, &renamedObjectInfo{ObjectInfo: src, remote: remote + binSuffix})
+		if err != nil {
+			return nil, err
+		}
+		return f.newObject(o), nil
+	}
+
+	size := src.Size()
+	if size >= 0 && f.Fs.Features().PutStream != nil {
+		name := compressedName(remote, size, f.comp.suffix)
+		if existing == nil || existing.Object.Remote() != name {
+			return f.putStream(ctx, in, src, name, options)
+		}
+	}
+
+	// Otherwise compress into a temporary file first so the
+	// compressed size is known
+	tmp, err := ioutil.TempFile("", "rclone-compress")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make temporary file")
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+	meta, err := f.compress(in, tmp)
+	if err != nil {
+		return nil, err
+	}
+	if size >= 0 && meta.Size != size {
+		return nil, errors.Errorf("read %d bytes expecting %d", meta.Size, size)
+	}
+	compressedSize, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	info := object.NewStaticObjectInfo(compressedName(remote, meta.Size, f.comp.suffix), src.ModTime(), compressedSize, true, nil, f.Fs)
+	o, err := upload(tmp, info)
+	if err != nil {
+		return nil, err
+	}
+	newObj := f.newObject(o)
+	newObj.meta = meta
+	return newObj, nil
+}
+
+// putStream compresses in on the fly uploading it as name with
+// PutStream on the wrapped remote
+func (f *Fs) putStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, name string, options []fs.OpenOption) (*Object, error) {
+	pipeReader, pipeWriter := io.Pipe()
+	var (
+		meta        *metadata
+		compressErr error
+		wg          sync.WaitGroup
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		meta, compressErr = f.compress(in, pipeWriter)
+		_ = pipeWriter.CloseWithError(compressErr)
+	}()
+	info := object.NewStaticObjectInfo(name, src.ModTime(), -1, true, nil, f.Fs)
+	o, err := f.Fs.Features().PutStream(ctx, pipeReader, info, options...)
+	_ = pipeReader.CloseWithError(io.ErrClosedPipe)
+	wg.Wait()
+	if err == nil && compressErr == nil && meta.Size != src.Size() {
+		compressErr = errors.Errorf("read %d bytes expecting %d", meta.Size, src.Size())
+	}
+	if err == nil && compressErr != nil {
+		// Don't leave a broken object behind
+		if removeErr := o.Remove(ctx); removeErr != nil {
+			fs.Errorf(o, "Failed to remove after failed upload: %v", removeErr)
+		}
+		err = compressErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	f.listings.add(o)
+	newObj := f.newObject(o)
+	newObj.meta = meta
+	return newObj, nil
+}
+
+// Put in to the remote path with the modTime given of the given size
+//
+// May create the object even if it returns an error - if so
+// will return the object and the error, otherwise will return
+// nil and the error
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	o, err := f.NewObject(ctx, src.Remote())
+	switch err {
+	case nil:
+		return o, o.Update(ctx, in, src, options...)
+	case fs.ErrorObjectNotFound:
+		return f.put(ctx, in, src, options, nil)
+	default:
+		return nil, err
+	}
+}
+
+// PutStream uploads to the remote path with the modTime given of indeterminate size
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.Put(ctx, in, src, options...)
+}
+
+// Mkdir makes the directory (container, bucket)
+//
+// Shouldn't return an error if it already exists
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
+	return f.Fs.Mkdir(ctx, dir)
+}
+
+// Rmdir removes the directory (container, bucket) if empty
+//
+// Return an error if it doesn't exist or isn't empty
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
+	f.listings.forget(dir)
+	return f.Fs.Rmdir(ctx, dir)
+}
+
+// Purge all files in the root and the root directory
+//
+// Implement this if you have a way of deleting all the files
+// quicker than just running Remove() on the result of List()
+//
+// Return an error if it doesn't exist
+func (f *Fs) Purge(ctx context.Context) error {
+	do := f.Fs.Features().Purge
+	if do == nil {
+		return fs.ErrorCantPurge
+	}
+	f.listings.flush()
+	return do(ctx)
+}
+
+type copyMoveFn func(ctx context.Context, src fs.Object, remote string) (fs.Object, error)
+
+// copyOrMove copies or moves src to remote using do
+func (f *Fs) copyOrMove(ctx context.Context, src fs.Object, remote string, do copyMoveFn, cantErr error) (fs.Object, error) {
+	o, ok := src.(*Object)
+	if !ok {
+		return nil, cantErr
+	}
+	var existing *Object
+	if dst, err := f.NewObject(ctx, remote); err == nil {
+		existing = dst.(*Object)
+	}
+	name := remote + strings.TrimPrefix(o.Object.Remote(), o.remote)
+	wrapped, err := do(ctx, o.Object, name)
+	if err != nil {
+		return nil, err
+	}
+	f.listings.add(wrapped)
+	newObj := f.newObject(wrapped)
+	newObj.meta = o.meta
+	if existing != nil && existing.Object.Remote() != name {
+		err = existing.Object.Remove(ctx)
+		if err == nil {
+			f.listings.remove(existing.Object)
+		}
+	}
+	return newObj, err
+}
+
+// Copy src to this remote using server side copy operations.
+//
+// This is stored with the remote path given
+//
+// It returns the destination Object and a possible error
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantCopy
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	do := f.Fs.Features().Copy
+	if do == nil {
+		return nil, fs.ErrorCantCopy
+	}
+	return f.copyOrMove(ctx, src, remote, do, fs.ErrorCantCopy)
+}
+
+// Move src to this remote using server side move operations.
+//
+// This is stored with the remote path given
+//
+// It returns the destination Object and a possible error
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantMove
+func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	do := f.Fs.Features().Move
+	if do == nil {
+		return nil, fs.ErrorCantMove
+	}
+	dst, err := f.copyOrMove(ctx, src, remote, do, fs.ErrorCantMove)
+	if dst != nil {
+		o := src.(*Object)
+		o.f.listings.remove(o.Object)
+	}
+	return dst, err
+}
+
+// DirMove moves src, srcRemote to this remote at dstRemote
+// using server side move operations.
+//
+// Will only be called if src.Fs().Name() == f.Name()
+//
+// If it isn't possible then return fs.ErrorCantDirMove
+//
+// If destination exists then return fs.ErrorDirExists
+func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string) error {
+	do := f.Fs.Features().DirMove
+	if do == nil {
+		return fs.ErrorCantDirMove
+	}
+	srcFs, ok := src.(*Fs)
+	if !ok {
+		fs.Debugf(srcFs, "Can't move directory - not same remote type")
+		return fs.ErrorCantDirMove
+	}
+	srcFs.listings.flush()
+	f.listings.flush()
+	return do(ctx, srcFs.Fs, srcRemote, dstRemote)
+}
+
+// CleanUp the trash in the Fs
+//
+// Implement this if you have a way of emptying the trash or
+// otherwise cleaning up old versions of files.
+func (f *Fs) CleanUp(ctx context.Context) error {
+	do := f.Fs.Features().CleanUp
+	if do == nil {
+		return errors.New("can't CleanUp")
+	}
+	return do(ctx)
+}
+
+// About gets quota information from the Fs
+func (f *Fs) About(ctx context.Context) (*fs.Usage, error) {
+	do := f.Fs.Features().About
+	if do == nil {
+		return nil, errors.New("About not supported")
+	}
+	return do(ctx)
+}
+
+// UnWrap returns the Fs that this Fs is wrapping
+func (f *Fs) UnWrap() fs.Fs {
+	return f.Fs
+}
+
+// Object describes a wrapped Object which may be compressed
+type Object struct {
+	fs.Object
+	f          *Fs
+	remote     string
+	size       int64
+	compressed bool
+	mu         sync.Mutex
+	meta       *metadata // read on demand if compressed
+}
+
+// newObject makes an Object from the wrapped object o returning nil
+// if o wasn't made by this backend
+func (f *Fs) newObject(o fs.Object) *Object {
+	remote, size, compressed, ok := parseName(o.Remote())
+	if !ok {
+		return nil
+	}
+	if !compressed {
+		size = o.Size()
+	}
+	return &Object{
+		Object:     o,
+		f:          f,
+		remote:     remote,
+		size:       size,
+		compressed: compressed,
+	}
+}
+
+// Fs returns read only access to the Fs that this object is part of
+func (o *Object) Fs() fs.Info {
+	return o.f
+}
+
+// Return a string version
+func (o *Object) String() string {
+	if o == nil {
+		return "<nil>"
+	}
+	return o.remote
+}
+
+// Remote returns the remote path
+func (o *Object) Remote() string {
+	return o.remote
+}
+
+// Size returns the uncompressed size of the file
+func (o *Object) Size() int64 {
+	return o.size
+}
+
+// getMetadata returns the metadata of a compressed object reading it
+// if necessary
+func (o *Object) getMetadata(ctx context.Context) (*metadata, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.meta == nil {
+		meta, err := readMetadata(ctx, o.Object)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s", o.remote)
+		}
+		o.meta = meta
+	}
+	return o.meta, nil
+}
+
+// Hash returns the selected checksum of the file
+// If no checksum is available it returns ""
+func (o *Object) Hash(ctx context.Context, ht hash.Type) (string, error) {
+	if !o.f.Hashes().Contains(ht) {
+		return "", hash.ErrUnsupported
+	}
+	if !o.compressed {
+		if !o.f.Fs.Hashes().Contains(ht) {
+			return "", nil
+		}
+		return o.Object.Hash(ctx, ht)
+	}
+	meta, err := o.getMetadata(ctx)
+	if err != nil {
+		return "", err
+	}
+	switch ht {
+	case hash.MD5:
+		return meta.MD5, nil
+	case hash.SHA1:
+		return meta.SHA1, nil
+	}
+	return "", nil
+}
+
+// Open opens the file for read.  Call Close() on the returned io.ReadCloser
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (rc io.ReadCloser, err error) {
+	if !o.compressed {
+		return o.Object.Open(ctx, options...)
+	}
+	var openOptions []fs.OpenOption
+	var offset, limit int64 = 0, -1
+	for _, option := range options {
+		switch x := option.(type) {
+		case *fs.SeekOption:
+			offset = x.Offset
+		case *fs.RangeOption:
+			offset, limit = x.Decode(o.size)
+		default:
+			// pass on Options to underlying open if appropriate
+			openOptions = append(openOptions, option)
+		}
+	}
+	if offset < 0 || offset > o.size {
+		return nil, errors.Errorf("invalid offset %d for file of size %d", offset, o.size)
+	}
+	if limit < 0 || offset+limit > o.size {
+		limit = o.size - offset
+	}
+	if limit == 0 {
+		return ioutil.NopCloser(bytes.NewReader(nil)), nil
+	}
+	meta, err := o.getMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	comp, ok := compressors[meta.Mode]
+	if !ok {
+		return nil, errors.Errorf("%s: compression mode %q not supported", o.remote, meta.Mode)
+	}
+
+	// Start reading at the block containing the offset
+	block := offset / meta.BlockSize
+	openOptions = append(openOptions, &fs.RangeOption{Start: meta.Blocks[block], End: meta.dataSize - 1})
+	in, err := o.Object.Open(ctx, openOptions...)
+	if err != nil {
+		return nil, err
+	}
+	r, err := comp.newReader(in)
+	if err != nil {
+		_ = in.Close()
+		return nil, errors.Wrap(err, "failed to start decompressing")
+	}
+	rc = &decompressReader{Reader: io.LimitReader(r, limit), decompressor: r, in: in}
+	if skip := offset - block*meta.BlockSize; skip > 0 {
+		if _, err = io.CopyN(ioutil.Discard, r, skip); err != nil {
+			_ = rc.Close()
+			return nil, errors.Wrap(err, "failed to seek")
+		}
+	}
+	return rc, nil
+}
+
+// decompressReader reads the decompressed data closing the
+// decompressor and the underlying reader when done
+type decompressReader struct {
+	io.Reader
+	decompressor io.ReadCloser
+	in           io.ReadCloser
+}
+
+// Close the decompressor and the underlying reader
+func (r *decompressReader) Close() error {
+	err := r.decompressor.Close()
+	inErr := r.in.Close()
+	if err == nil {
+		err = inErr
+	}
+	return err
+}
+
+// Update in to the object with the modTime given of the given size
+//
+// If the new contents are stored under a different name in the
+// wrapped remote the old object is removed afterwards.
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	newObj, err := o.f.put(ctx, in, src, options, o)
+	if err != nil {
+		return err
+	}
+	if newObj.Object.Remote() != o.Object.Remote() {
+		if err = o.Object.Remove(ctx); err != nil {
+			return errors.Wrap(err, "failed to remove old version")
+		}
+		o.f.listings.remove(o.Object)
+	}
+	o.Object = newObj.Object
+	o.size = newObj.size
+	o.compressed = newObj.compressed
+	o.mu.Lock()
+	o.meta = newObj.meta
+	o.mu.Unlock()
+	return nil
+}
+
+// Remove an object
+func (o *Object) Remove(ctx context.Context) error {
+	err := o.Object.Remove(ctx)
+	if err == nil {
+		o.f.listings.remove(o.Object)
+	}
+	return err
+}
+
+// UnWrap returns the wrapped Object
+func (o *Object) UnWrap() fs.Object {
+	return o.Object
+}
+
+// Check the interfaces are satisfied
+var (
+	_ fs.Fs              = (*Fs)(nil)
+	_ fs.Purger          = (*Fs)(nil)
+	_ fs.Copier          = (*Fs)(nil)
+	_ fs.Mover           = (*Fs)(nil)
+	_ fs.DirMover        = (*Fs)(nil)
+	_ fs.PutStreamer     = (*Fs)(nil)
+	_ fs.CleanUpper      = (*Fs)(nil)
+	_ fs.UnWrapper       = (*Fs)(nil)
+	_ fs.Abouter         = (*Fs)(nil)
+	_ fs.Object          = (*Object)(nil)
+	_ fs.ObjectUnWrapper = (*Object)(nil)
+)
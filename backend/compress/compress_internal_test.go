@@ -0,0 +1,364 @@
+package compress
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/ncw/rclone/backend/local"
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/config/configmap"
+	"github.com/ncw/rclone/fs/hash"
+	"github.com/ncw/rclone/fs/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseName(t *testing.T) {
+	for _, test := range []struct {
+		in         string
+		remote     string
+		size       int64
+		compressed bool
+		ok         bool
+	}{
+		{"file.txt.bin", "file.txt", -1, false, true},
+		{"dir/file.txt.3e8.gz", "dir/file.txt", 1000, true, true},
+		{"file.0.zst", "file", 0, true, true},
+		{"file.bin.bin", "file.bin", -1, false, true},
+		{"file.txt.3e8.gz.bin", "file.txt.3e8.gz", -1, false, true},
+		{"file.txt", "", 0, false, false},
+		{"file.txt.gz", "", 0, false, false},
+		{"file.txt.3E8.gz", "", 0, false, false},
+		{"file.txt.3e8.bz2", "", 0, false, false},
+		{".bin", "", 0, false, false},
+	} {
+		remote, size, compressed, ok := parseName(test.in)
+		assert.Equal(t, test.ok, ok, test.in)
+		assert.Equal(t, test.remote, remote, test.in)
+		assert.Equal(t, test.size, size, test.in)
+		assert.Equal(t, test.compressed, compressed, test.in)
+	}
+	assert.Equal(t, "dir/file.txt.3e8.gz", compressedName("dir/file.txt", 1000, ".gz"))
+}
+
+func TestIsCompressible(t *testing.T) {
+	for _, test := range []struct {
+		remote string
+		want   bool
+	}{
+		{"file.txt", true},
+		{"file.log", true},
+		{"file", true},
+		{"file.html", true},
+		{"file.gz", false},
+		{"file.ZIP", false},
+		{"file.jpg", false},
+		{"file.mp4", false},
+		{"file.docx", false},
+	} {
+		src := object.NewStaticObjectInfo(test.remote, time.Now(), 0, true, nil, nil)
+		assert.Equal(t, test.want, isCompressible(src), test.remote)
+	}
+}
+
+// newTestFs makes a compress Fs with mode over a temporary directory
+// returning it, the wrapped Fs and a cleanup function
+func newTestFs(t *testing.T, mode string) (*Fs, fs.Fs, func()) {
+	dir, err := ioutil.TempDir("", "rclone-compress-test")
+	require.NoError(t, err)
+	f, err := NewFs("compress", "", configmap.Simple{
+		"remote":     dir,
+		"mode":       mode,
+		"level":      "-1",
+		"block_size": "1k",
+	})
+	require.NoError(t, err)
+	return f.(*Fs), f.(*Fs).Fs, func() {
+		_ = os.RemoveAll(dir)
+	}
+}
+
+// put uploads contents as remote
+func put(t *testing.T, f fs.Fs, remote string, contents []byte, size int64) fs.Object {
+	ctx := context.Background()
+	src := object.NewStaticObjectInfo(remote, time.Now(), size, true, nil, nil)
+	var o fs.Object
+	var err error
+	if size < 0 {
+		o, err = f.Features().PutStream(ctx, bytes.NewReader(contents), src)
+	} else {
+		o, err = f.Put(ctx, bytes.NewReader(contents), src)
+	}
+	require.NoError(t, err)
+	return o
+}
+
+// wrappedObjects lists the sizes of the objects in the root of the
+// wrapped Fs
+func wrappedObjects(t *testing.T, f fs.Fs) map[string]int64 {
+	entries, err := f.List(context.Background(), "")
+	require.NoError(t, err)
+	objects := map[string]int64{}
+	for _, entry := range entries {
+		if o, ok := entry.(fs.Object); ok {
+			objects[o.Remote()] = o.Size()
+		}
+	}
+	return objects
+}
+
+// wrappedNames lists the names in the root of the wrapped Fs
+func wrappedNames(t *testing.T, f fs.Fs) (names []string) {
+	for name := range wrappedObjects(t, f) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// read reads o with options
+func read(t *testing.T, o fs.Object, options ...fs.OpenOption) string {
+	in, err := o.Open(context.Background(), options...)
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(in)
+	require.NoError(t, err)
+	require.NoError(t, in.Close())
+	return string(data)
+}
+
+// testContents makes some compressible data of size bytes
+func testContents(size int) []byte {
+	var buf bytes.Buffer
+	for i := 0; buf.Len() < size; i++ {
+		buf.WriteString(strings.Repeat(string(rune('a'+i%26)), 7))
+	}
+	return buf.Bytes()[:size]
+}
+
+func testCompressedFile(t *testing.T, mode, suffix string) {
+	f, wrapped, cleanup := newTestFs(t, mode)
+	defer cleanup()
+	ctx := context.Background()
+	contents := testContents(5000)
+
+	// The file is compressed with its size in the name
+	o := put(t, f, "file.txt", contents, int64(len(contents)))
+	assert.Equal(t, "file.txt", o.Remote())
+	assert.Equal(t, int64(5000), o.Size())
+	objects := wrappedObjects(t, wrapped)
+	require.Len(t, objects, 1)
+	compressedSize, found := objects["file.txt.1388"+suffix]
+	require.True(t, found, "objects = %v", objects)
+	assert.True(t, compressedSize < int64(len(contents)), "compressed size %d", compressedSize)
+
+	// Size and hashes are of the uncompressed data
+	o, err := f.NewObject(ctx, "file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5000), o.Size())
+	md5sum := md5.Sum(contents)
+	sum, err := o.Hash(ctx, hash.MD5)
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(md5sum[:]), sum)
+	sha1sum := sha1.Sum(contents)
+	sum, err = o.Hash(ctx, hash.SHA1)
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(sha1sum[:]), sum)
+
+	// Reads can start in any block
+	assert.Equal(t, string(contents), read(t, o))
+	assert.Equal(t, string(contents[1500:4200]), read(t, o, &fs.RangeOption{Start: 1500, End: 4199}))
+	assert.Equal(t, string(contents[4990:]), read(t, o, &fs.RangeOption{Start: -1, End: 10}))
+	assert.Equal(t, string(contents[2048:]), read(t, o, &fs.SeekOption{Offset: 2048}))
+	assert.Equal(t, "", read(t, o, &fs.SeekOption{Offset: 5000}))
+
+	// Updating with the same size keeps the name
+	contents2 := testContents(5001)[1:]
+	require.NoError(t, o.Update(ctx, bytes.NewReader(contents2), object.NewStaticObjectInfo("file.txt", time.Now(), 5000, true, nil, nil)))
+	assert.Equal(t, []string{"file.txt.1388" + suffix}, wrappedNames(t, wrapped))
+	assert.Equal(t, string(contents2), read(t, o))
+
+	// Updating with a different size renames and removes the old one
+	require.NoError(t, o.Update(ctx, bytes.NewReader(contents[:10]), object.NewStaticObjectInfo("file.txt", time.Now(), 10, true, nil, nil)))
+	assert.Equal(t, []string{"file.txt.a" + suffix}, wrappedNames(t, wrapped))
+	assert.Equal(t, string(contents[:10]), read(t, o))
+	o, err = f.NewObject(ctx, "file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, string(contents[:10]), read(t, o))
+
+	require.NoError(t, o.Remove(ctx))
+	assert.Len(t, wrappedNames(t, wrapped), 0)
+}
+
+func TestCompressedFileGzip(t *testing.T) {
+	testCompressedFile(t, "gzip", ".gz")
+}
+
+func TestCompressedFileZstd(t *testing.T) {
+	testCompressedFile(t, "zstd", ".zst")
+}
+
+func TestUncompressedFile(t *testing.T) {
+	f, wrapped, cleanup := newTestFs(t, "gzip")
+	defer cleanup()
+	ctx := context.Background()
+	contents := testContents(500)
+
+	// Already compressed types are stored as is
+	src := object.NewStaticObjectInfo("archive", time.Now(), 500, true, nil, nil)
+	o, err := f.Put(ctx, bytes.NewReader(contents), &mimeObjectInfo{src, "application/zip"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"archive.bin": 500}, wrappedObjects(t, wrapped))
+	o, err = f.NewObject(ctx, "archive")
+	require.NoError(t, err)
+	assert.Equal(t, int64(500), o.Size())
+	assert.Equal(t, string(contents[100:200]), read(t, o, &fs.RangeOption{Start: 100, End: 199}))
+
+	// Updating to a compressible type changes how it is stored
+	require.NoError(t, o.Update(ctx, bytes.NewReader(contents), &mimeObjectInfo{src, "text/plain"}))
+	assert.Equal(t, []string{"archive.1f4.gz"}, wrappedNames(t, wrapped))
+	assert.Equal(t, string(contents), read(t, o))
+}
+
+// mimeObjectInfo has a MIME type
+type mimeObjectInfo struct {
+	fs.ObjectInfo
+	mimeType string
+}
+
+// MimeType returns the MIME type
+func (o *mimeObjectInfo) MimeType() string {
+	return o.mimeType
+}
+
+func TestStream(t *testing.T) {
+	f, wrapped, cleanup := newTestFs(t, "gzip")
+	defer cleanup()
+	ctx := context.Background()
+
+	contents := testContents(250)
+	o := put(t, f, "stream", contents, -1)
+	assert.Equal(t, int64(250), o.Size())
+	assert.Equal(t, []string{"stream.fa.gz"}, wrappedNames(t, wrapped))
+	o, err := f.NewObject(ctx, "stream")
+	require.NoError(t, err)
+	assert.Equal(t, string(contents), read(t, o))
+
+	o = put(t, f, "empty", nil, -1)
+	assert.Equal(t, int64(0), o.Size())
+	o, err = f.NewObject(ctx, "empty")
+	require.NoError(t, err)
+	assert.Equal(t, "", read(t, o))
+	sum, err := o.Hash(ctx, hash.MD5)
+	require.NoError(t, err)
+	assert.Equal(t, "d41d8cd98f00b204e9800998ecf8427e", sum)
+}
+
+func TestForeignFilesHidden(t *testing.T) {
+	f, wrapped, cleanup := newTestFs(t, "gzip")
+	defer cleanup()
+	ctx := context.Background()
+
+	put(t, wrapped, "potato", []byte("not compressed"), 14)
+	put(t, f, "file.txt", testContents(10), 10)
+	entries, err := f.List(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "file.txt", entries[0].Remote())
+	_, err = f.NewObject(ctx, "potato")
+	assert.Equal(t, fs.ErrorObjectNotFound, err)
+}
+
+func TestBadTrailer(t *testing.T) {
+	f, wrapped, cleanup := newTestFs(t, "gzip")
+	defer cleanup()
+	ctx := context.Background()
+
+	put(t, wrapped, "file.txt.a.gz", []byte("not compressed at all"), 21)
+	o, err := f.NewObject(ctx, "file.txt")
+	require.NoError(t, err)
+	_, err = o.Open(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad trailer")
+}
+
+// listCounter counts the directory listings of the Fs it wraps
+type listCounter struct {
+	fs.Fs
+	lists int
+}
+
+// List the objects and directories in dir into entries
+func (f *listCounter) List(ctx context.Context, dir string) (fs.DirEntries, error) {
+	f.lists++
+	return f.Fs.List(ctx, dir)
+}
+
+func TestNewObjectListingCache(t *testing.T) {
+	f, wrapped, cleanup := newTestFs(t, "gzip")
+	defer cleanup()
+	ctx := context.Background()
+	counter := &listCounter{Fs: wrapped}
+	f.Fs = counter
+
+	// Uploading and finding lots of files lists the directory once
+	const n = 20
+	for i := 0; i < n; i++ {
+		put(t, f, fmt.Sprintf("file%d.txt", i), testContents(100+i), int64(100+i))
+	}
+	for i := 0; i < n; i++ {
+		o, err := f.NewObject(ctx, fmt.Sprintf("file%d.txt", i))
+		require.NoError(t, err)
+		assert.Equal(t, int64(100+i), o.Size())
+	}
+	assert.Equal(t, 1, counter.lists)
+
+	// Changes made through the Fs are seen without listing again
+	o, err := f.NewObject(ctx, "file0.txt")
+	require.NoError(t, err)
+	require.NoError(t, o.Update(ctx, bytes.NewReader(testContents(50)), object.NewStaticObjectInfo("file0.txt", time.Now(), 50, true, nil, nil)))
+	o, err = f.NewObject(ctx, "file0.txt")
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), o.Size())
+	require.NoError(t, o.Remove(ctx))
+	_, err = f.NewObject(ctx, "file0.txt")
+	assert.Equal(t, fs.ErrorObjectNotFound, err)
+	moved, err := f.Move(ctx, mustNewObject(t, f, "file1.txt"), "moved.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "moved.txt", moved.Remote())
+	_, err = f.NewObject(ctx, "file1.txt")
+	assert.Equal(t, fs.ErrorObjectNotFound, err)
+	o, err = f.NewObject(ctx, "moved.txt")
+	require.NoError(t, err)
+	assert.Equal(t, int64(101), o.Size())
+	assert.Equal(t, 1, counter.lists)
+
+	// Changes made elsewhere are seen once the listing has expired
+	put(t, wrapped, "other.txt.a.gz", []byte("not compressed at all"), 21)
+	_, err = f.NewObject(ctx, "other.txt")
+	assert.Equal(t, fs.ErrorObjectNotFound, err)
+	f.listings.mu.Lock()
+	for _, listing := range f.listings.dirs {
+		listing.expires = time.Now().Add(-time.Second)
+	}
+	f.listings.mu.Unlock()
+	_, err = f.NewObject(ctx, "other.txt")
+	require.NoError(t, err)
+	assert.Equal(t, 2, counter.lists)
+}
+
+// mustNewObject finds remote in f
+func mustNewObject(t *testing.T, f fs.Fs, remote string) fs.Object {
+	o, err := f.NewObject(context.Background(), remote)
+	require.NoError(t, err)
+	return o
+}
@@ -0,0 +1,43 @@
+// Zstandard compression needs go1.9 or later
+
+// +build go1.9
+
+package compress
+
+import (
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstdReader closes the decoder when done
+type zstdReader struct {
+	*zstd.Decoder
+}
+
+// Close the decoder
+func (r zstdReader) Close() error {
+	r.Decoder.Close()
+	return nil
+}
+
+func init() {
+	compressors["zstd"] = &compressor{
+		suffix: ".zst",
+		newWriter: func(level int) (resetWriteCloser, error) {
+			var options []zstd.EOption
+			if level >= 0 {
+				options = append(options, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+			}
+			options = append(options, zstd.WithEncoderConcurrency(1))
+			return zstd.NewWriter(nil, options...)
+		},
+		newReader: func(in io.Reader) (io.ReadCloser, error) {
+			d, err := zstd.NewReader(in, zstd.WithDecoderConcurrency(1))
+			if err != nil {
+				return nil, err
+			}
+			return zstdReader{d}, nil
+		},
+	}
+}
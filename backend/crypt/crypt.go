@@ -2,6 +2,7 @@
 package crypt
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"strings"
@@ -58,6 +59,26 @@ func init() {
 					Help:  "Don't encrypt directory names, leave them intact.",
 				},
 			},
+		}, {
+			Name: "filename_encoding",
+			Help: `How to encode the encrypted file names as text.
+
+This only applies when filename_encryption is "standard".  Choose an
+encoding which makes the encrypted names short enough for the way your
+remote counts the length of file names and which it can store.`,
+			Default: "base32",
+			Examples: []fs.OptionExample{
+				{
+					Value: "base32",
+					Help:  "Encode using base32.  Suitable for all remotes.",
+				}, {
+					Value: "base64",
+					Help:  "Encode using URL safe base64.  Suitable for case sensitive remotes.",
+				}, {
+					Value: "base32768",
+					Help:  "Encode using base32768.  Suitable if your remote counts the\nlength of file names in characters rather than bytes, eg OneDrive.",
+				},
+			},
 		}, {
 			Name:       "password",
 			Help:       "Password or pass phrase for encryption.",
@@ -80,6 +101,22 @@ names, or for debugging purposes.`,
 			Default:  false,
 			Hide:     fs.OptionHideConfigurator,
 			Advanced: true,
+		}, {
+			Name: "max_name_length",
+			Help: `Store file names which encrypt longer than this in a sidecar.
+
+Many remotes can't store file names longer than 255 bytes.  If this is
+set above 0 then a file whose encrypted name is longer than this many
+bytes is stored under a short name made from a hash of the encrypted
+name with a ".rclonelong" suffix.  The encrypted name is stored in a
+small sidecar object next to it with an extra ".name" suffix which is
+read when the directory is listed.
+
+This only applies when filename_encryption is "standard" and only to
+file names, not directory names.  Files already stored with long names
+can be read whatever this is set to.`,
+			Default:  0,
+			Advanced: true,
 		}},
 	})
 }
@@ -90,6 +127,10 @@ func newCipherForConfig(opt *Options) (Cipher, error) {
 	if err != nil {
 		return nil, err
 	}
+	enc, err := NewNameEncoding(opt.FilenameEncoding)
+	if err != nil {
+		return nil, err
+	}
 	if opt.Password == "" {
 		return nil, errors.New("password not set in config file")
 	}
@@ -104,7 +145,7 @@ func newCipherForConfig(opt *Options) (Cipher, error) {
 			return nil, errors.Wrap(err, "failed to decrypt password2")
 		}
 	}
-	cipher, err := newCipher(mode, password, salt, opt.DirectoryNameEncryption)
+	cipher, err := newCipher(mode, password, salt, opt.DirectoryNameEncryption, enc)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to make cipher")
 	}
@@ -143,7 +184,11 @@ func NewFs(name, rpath string, m configmap.Mapper) (fs.Fs, error) {
 		return nil, errors.Wrapf(err, "failed to parse remote %q to wrap", remote)
 	}
 	// Look for a file first
-	remotePath := fspath.JoinRootPath(wPath, cipher.EncryptFileName(rpath))
+	encryptedPath := cipher.EncryptFileName(rpath)
+	if cipher.NameEncryptionMode() == NameEncryptionStandard {
+		encryptedPath, _ = shortenFileName(encryptedPath, opt.MaxNameLength)
+	}
+	remotePath := fspath.JoinRootPath(wPath, encryptedPath)
 	wrappedFs, err := wInfo.NewFs(wName, remotePath, wConfig)
 	// if that didn't produce a file, look for a directory
 	if err != fs.ErrorIsFile {
@@ -173,16 +218,19 @@ func NewFs(name, rpath string, m configmap.Mapper) (fs.Fs, error) {
 
 	doChangeNotify := wrappedFs.Features().ChangeNotify
 	if doChangeNotify != nil {
-		f.features.ChangeNotify = func(notifyFunc func(string, fs.EntryType), pollInterval <-chan time.Duration) {
+		f.features.ChangeNotify = func(ctx context.Context, notifyFunc func(string, fs.EntryType), pollInterval <-chan time.Duration) {
 			wrappedNotifyFunc := func(path string, entryType fs.EntryType) {
-				decrypted, err := f.DecryptFileName(path)
+				if f.longNames() && isLongNameSidecar(path) {
+					return
+				}
+				decrypted, err := f.DecryptStoredName(ctx, path)
 				if err != nil {
 					fs.Logf(f, "ChangeNotify was unable to decrypt %q: %s", path, err)
 					return
 				}
 				notifyFunc(decrypted, entryType)
 			}
-			doChangeNotify(wrappedNotifyFunc, pollInterval)
+			doChangeNotify(ctx, wrappedNotifyFunc, pollInterval)
 		}
 	}
 
@@ -194,9 +242,11 @@ type Options struct {
 	Remote                  string `config:"remote"`
 	FilenameEncryption      string `config:"filename_encryption"`
 	DirectoryNameEncryption bool   `config:"directory_name_encryption"`
+	FilenameEncoding        string `config:"filename_encoding"`
 	Password                string `config:"password"`
 	Password2               string `config:"password2"`
 	ShowMapping             bool   `config:"show_mapping"`
+	MaxNameLength           int    `config:"max_name_length"`
 }
 
 // Fs represents a wrapped fs.Fs
@@ -230,9 +280,24 @@ func (f *Fs) String() string {
 }
 
 // Encrypt an object file name to entries.
-func (f *Fs) add(entries *fs.DirEntries, obj fs.Object) {
+func (f *Fs) add(ctx context.Context, entries *fs.DirEntries, obj fs.Object) {
 	remote := obj.Remote()
-	decryptedRemote, err := f.cipher.DecryptFileName(remote)
+	var longName string
+	if f.longNames() {
+		if isLongNameSidecar(remote) {
+			return
+		}
+		if isLongName(remote) {
+			var err error
+			longName, err = f.readLongName(ctx, remote)
+			if err != nil {
+				fs.Debugf(remote, "Skipping unreadable long file name: %v", err)
+				return
+			}
+		}
+	}
+	o := f.newObject(obj, longName)
+	decryptedRemote, err := f.cipher.DecryptFileName(o.encryptedRemote())
 	if err != nil {
 		fs.Debugf(remote, "Skipping undecryptable file name: %v", err)
 		return
@@ -240,7 +305,7 @@ func (f *Fs) add(entries *fs.DirEntries, obj fs.Object) {
 	if f.opt.ShowMapping {
 		fs.Logf(decryptedRemote, "Encrypts to %q", remote)
 	}
-	*entries = append(*entries, f.newObject(obj))
+	*entries = append(*entries, o)
 }
 
 // Encrypt an directory file name to entries.
@@ -258,12 +323,12 @@ func (f *Fs) addDir(entries *fs.DirEntries, dir fs.Directory) {
 }
 
 // Encrypt some directory entries.  This alters entries returning it as newEntries.
-func (f *Fs) encryptEntries(entries fs.DirEntries) (newEntries fs.DirEntries, err error) {
+func (f *Fs) encryptEntries(ctx context.Context, entries fs.DirEntries) (newEntries fs.DirEntries, err error) {
 	newEntries = entries[:0] // in place filter
 	for _, entry := range entries {
 		switch x := entry.(type) {
 		case fs.Object:
-			f.add(&newEntries, x)
+			f.add(ctx, &newEntries, x)
 		case fs.Directory:
 			f.addDir(&newEntries, x)
 		default:
@@ -282,12 +347,12 @@ func (f *Fs) encryptEntries(entries fs.DirEntries) (newEntries fs.DirEntries, er
 //
 // This should return ErrDirNotFound if the directory isn't
 // found.
-func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
-	entries, err = f.Fs.List(f.cipher.EncryptDirName(dir))
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	entries, err = f.Fs.List(ctx, f.cipher.EncryptDirName(dir))
 	if err != nil {
 		return nil, err
 	}
-	return f.encryptEntries(entries)
+	return f.encryptEntries(ctx, entries)
 }
 
 // ListR lists the objects and directories of the Fs starting
@@ -306,9 +371,9 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 //
 // Don't implement this unless you have a more efficient way
 // of listing recursively that doing a directory traversal.
-func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
-	return f.Fs.Features().ListR(f.cipher.EncryptDirName(dir), func(entries fs.DirEntries) error {
-		newEntries, err := f.encryptEntries(entries)
+func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
+	return f.Fs.Features().ListR(ctx, f.cipher.EncryptDirName(dir), func(entries fs.DirEntries) error {
+		newEntries, err := f.encryptEntries(ctx, entries)
 		if err != nil {
 			return err
 		}
@@ -317,18 +382,45 @@ func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
 }
 
 // NewObject finds the Object at remote.
-func (f *Fs) NewObject(remote string) (fs.Object, error) {
-	o, err := f.Fs.NewObject(f.cipher.EncryptFileName(remote))
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	stored, longName := f.encryptFileName(remote)
+	o, err := f.Fs.NewObject(ctx, stored)
 	if err != nil {
 		return nil, err
 	}
-	return f.newObject(o), nil
+	return f.newObject(o, longName), nil
+}
+
+// finishPut writes the sidecar of o if it was stored under a short
+// name for longName, removing o if that fails, and returns it wrapped
+func (f *Fs) finishPut(ctx context.Context, o fs.Object, longName string) (*Object, error) {
+	if longName != "" {
+		err := f.writeLongName(ctx, o.Remote(), longName)
+		if err != nil {
+			if removeErr := o.Remove(ctx); removeErr != nil {
+				fs.Errorf(o, "Failed to remove object without long name: %v", removeErr)
+			}
+			return nil, err
+		}
+	}
+	return f.newObject(o, longName), nil
 }
 
-type putFn func(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error)
+type putFn func(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error)
 
 // put implements Put or PutStream
-func (f *Fs) put(in io.Reader, src fs.ObjectInfo, options []fs.OpenOption, put putFn) (fs.Object, error) {
+func (f *Fs) put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options []fs.OpenOption, put putFn) (fs.Object, error) {
+	o, err := f.putData(ctx, in, src, options, put)
+	if err != nil {
+		return nil, err
+	}
+	_, longName := f.encryptFileName(src.Remote())
+	return f.finishPut(ctx, o, longName)
+}
+
+// putData encrypts in and transfers it with put returning the object
+// on the wrapped remote
+func (f *Fs) putData(ctx context.Context, in io.Reader, src fs.ObjectInfo, options []fs.OpenOption, put putFn) (fs.Object, error) {
 	// Encrypt the data into wrappedIn
 	wrappedIn, err := f.cipher.EncryptData(in)
 	if err != nil {
@@ -354,7 +446,7 @@ func (f *Fs) put(in io.Reader, src fs.ObjectInfo, options []fs.OpenOption, put p
 	}
 
 	// Transfer the data
-	o, err := put(wrappedIn, f.newObjectInfo(src), options...)
+	o, err := put(ctx, wrappedIn, f.newObjectInfo(src), options...)
 	if err != nil {
 		return nil, err
 	}
@@ -363,13 +455,13 @@ func (f *Fs) put(in io.Reader, src fs.ObjectInfo, options []fs.OpenOption, put p
 	if ht != hash.None && hasher != nil {
 		srcHash := hasher.Sums()[ht]
 		var dstHash string
-		dstHash, err = o.Hash(ht)
+		dstHash, err = o.Hash(ctx, ht)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to read destination hash")
 		}
 		if srcHash != "" && dstHash != "" && srcHash != dstHash {
 			// remove object
-			err = o.Remove()
+			err = o.Remove(ctx)
 			if err != nil {
 				fs.Errorf(o, "Failed to remove corrupted object: %v", err)
 			}
@@ -377,7 +469,7 @@ func (f *Fs) put(in io.Reader, src fs.ObjectInfo, options []fs.OpenOption, put p
 		}
 	}
 
-	return f.newObject(o), nil
+	return o, nil
 }
 
 // Put in to the remote path with the modTime given of the given size
@@ -385,13 +477,13 @@ func (f *Fs) put(in io.Reader, src fs.ObjectInfo, options []fs.OpenOption, put p
 // May create the object even if it returns an error - if so
 // will return the object and the error, otherwise will return
 // nil and the error
-func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-	return f.put(in, src, options, f.Fs.Put)
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.put(ctx, in, src, options, f.Fs.Put)
 }
 
 // PutStream uploads to the remote path with the modTime given of indeterminate size
-func (f *Fs) PutStream(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-	return f.put(in, src, options, f.Fs.Features().PutStream)
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.put(ctx, in, src, options, f.Fs.Features().PutStream)
 }
 
 // Hashes returns the supported hash sets.
@@ -402,15 +494,15 @@ func (f *Fs) Hashes() hash.Set {
 // Mkdir makes the directory (container, bucket)
 //
 // Shouldn't return an error if it already exists
-func (f *Fs) Mkdir(dir string) error {
-	return f.Fs.Mkdir(f.cipher.EncryptDirName(dir))
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
+	return f.Fs.Mkdir(ctx, f.cipher.EncryptDirName(dir))
 }
 
 // Rmdir removes the directory (container, bucket) if empty
 //
 // Return an error if it doesn't exist or isn't empty
-func (f *Fs) Rmdir(dir string) error {
-	return f.Fs.Rmdir(f.cipher.EncryptDirName(dir))
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
+	return f.Fs.Rmdir(ctx, f.cipher.EncryptDirName(dir))
 }
 
 // Purge all files in the root and the root directory
@@ -419,12 +511,12 @@ func (f *Fs) Rmdir(dir string) error {
 // quicker than just running Remove() on the result of List()
 //
 // Return an error if it doesn't exist
-func (f *Fs) Purge() error {
+func (f *Fs) Purge(ctx context.Context) error {
 	do := f.Fs.Features().Purge
 	if do == nil {
 		return fs.ErrorCantPurge
 	}
-	return do()
+	return do(ctx)
 }
 
 // Copy src to this remote using server side copy operations.
@@ -436,7 +528,7 @@ func (f *Fs) Purge() error {
 // Will only be called if src.Fs().Name() == f.Name()
 //
 // If it isn't possible then return fs.ErrorCantCopy
-func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
 	do := f.Fs.Features().Copy
 	if do == nil {
 		return nil, fs.ErrorCantCopy
@@ -445,11 +537,12 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 	if !ok {
 		return nil, fs.ErrorCantCopy
 	}
-	oResult, err := do(o.Object, f.cipher.EncryptFileName(remote))
+	stored, longName := f.encryptFileName(remote)
+	oResult, err := do(ctx, o.Object, stored)
 	if err != nil {
 		return nil, err
 	}
-	return f.newObject(oResult), nil
+	return f.finishPut(ctx, oResult, longName)
 }
 
 // Move src to this remote using server side move operations.
@@ -461,7 +554,7 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 // Will only be called if src.Fs().Name() == f.Name()
 //
 // If it isn't possible then return fs.ErrorCantMove
-func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
+func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
 	do := f.Fs.Features().Move
 	if do == nil {
 		return nil, fs.ErrorCantMove
@@ -470,11 +563,19 @@ func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
 	if !ok {
 		return nil, fs.ErrorCantMove
 	}
-	oResult, err := do(o.Object, f.cipher.EncryptFileName(remote))
+	srcStored := o.Object.Remote()
+	stored, longName := f.encryptFileName(remote)
+	oResult, err := do(ctx, o.Object, stored)
 	if err != nil {
 		return nil, err
 	}
-	return f.newObject(oResult), nil
+	if o.longName != "" {
+		err = o.f.removeLongName(ctx, srcStored)
+		if err != nil {
+			fs.Errorf(o, "Failed to remove long name after move: %v", err)
+		}
+	}
+	return f.finishPut(ctx, oResult, longName)
 }
 
 // DirMove moves src, srcRemote to this remote at dstRemote
@@ -485,7 +586,7 @@ func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
 // If it isn't possible then return fs.ErrorCantDirMove
 //
 // If destination exists then return fs.ErrorDirExists
-func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
+func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string) error {
 	do := f.Fs.Features().DirMove
 	if do == nil {
 		return fs.ErrorCantDirMove
@@ -495,14 +596,14 @@ func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
 		fs.Debugf(srcFs, "Can't move directory - not same remote type")
 		return fs.ErrorCantDirMove
 	}
-	return do(srcFs.Fs, f.cipher.EncryptDirName(srcRemote), f.cipher.EncryptDirName(dstRemote))
+	return do(ctx, srcFs.Fs, f.cipher.EncryptDirName(srcRemote), f.cipher.EncryptDirName(dstRemote))
 }
 
 // PutUnchecked uploads the object
 //
 // This will create a duplicate if we upload a new file without
 // checking to see if there is one already - use Put() for that.
-func (f *Fs) PutUnchecked(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+func (f *Fs) PutUnchecked(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
 	do := f.Fs.Features().PutUnchecked
 	if do == nil {
 		return nil, errors.New("can't PutUnchecked")
@@ -511,32 +612,33 @@ func (f *Fs) PutUnchecked(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOpt
 	if err != nil {
 		return nil, err
 	}
-	o, err := do(wrappedIn, f.newObjectInfo(src))
+	o, err := do(ctx, wrappedIn, f.newObjectInfo(src))
 	if err != nil {
 		return nil, err
 	}
-	return f.newObject(o), nil
+	_, longName := f.encryptFileName(src.Remote())
+	return f.finishPut(ctx, o, longName)
 }
 
 // CleanUp the trash in the Fs
 //
 // Implement this if you have a way of emptying the trash or
 // otherwise cleaning up old versions of files.
-func (f *Fs) CleanUp() error {
+func (f *Fs) CleanUp(ctx context.Context) error {
 	do := f.Fs.Features().CleanUp
 	if do == nil {
 		return errors.New("can't CleanUp")
 	}
-	return do()
+	return do(ctx)
 }
 
 // About gets quota information from the Fs
-func (f *Fs) About() (*fs.Usage, error) {
+func (f *Fs) About(ctx context.Context) (*fs.Usage, error) {
 	do := f.Fs.Features().About
 	if do == nil {
 		return nil, errors.New("About not supported")
 	}
-	return do()
+	return do(ctx)
 }
 
 // UnWrap returns the Fs that this Fs is wrapping
@@ -558,10 +660,10 @@ func (f *Fs) DecryptFileName(encryptedFileName string) (string, error) {
 // src with it, and calcuates the hash given by HashType on the fly
 //
 // Note that we break lots of encapsulation in this function.
-func (f *Fs) ComputeHash(o *Object, src fs.Object, hashType hash.Type) (hashStr string, err error) {
+func (f *Fs) ComputeHash(ctx context.Context, o *Object, src fs.Object, hashType hash.Type) (hashStr string, err error) {
 	// Read the nonce - opening the file is sufficient to read the nonce in
 	// use a limited read so we only read the header
-	in, err := o.Object.Open(&fs.RangeOption{Start: 0, End: int64(fileHeaderSize) - 1})
+	in, err := o.Object.Open(ctx, &fs.RangeOption{Start: 0, End: int64(fileHeaderSize) - 1})
 	if err != nil {
 		return "", errors.Wrap(err, "failed to open object to read nonce")
 	}
@@ -591,7 +693,7 @@ func (f *Fs) ComputeHash(o *Object, src fs.Object, hashType hash.Type) (hashStr
 	}
 
 	// Open the src for input
-	in, err = src.Open()
+	in, err = src.Open(ctx)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to open src")
 	}
@@ -621,16 +723,29 @@ func (f *Fs) ComputeHash(o *Object, src fs.Object, hashType hash.Type) (hashStr
 // This decrypts the remote name and decrypts the data
 type Object struct {
 	fs.Object
-	f *Fs
+	f        *Fs
+	longName string // encrypted leaf name if stored under a short name
 }
 
-func (f *Fs) newObject(o fs.Object) *Object {
+func (f *Fs) newObject(o fs.Object, longName string) *Object {
 	return &Object{
-		Object: o,
-		f:      f,
+		Object:   o,
+		f:        f,
+		longName: longName,
 	}
 }
 
+// encryptedRemote returns the encrypted path of the object which is
+// its path on the wrapped remote unless it has a long name
+func (o *Object) encryptedRemote() string {
+	remote := o.Object.Remote()
+	if o.longName == "" {
+		return remote
+	}
+	dir, _ := splitLeaf(remote)
+	return dir + o.longName
+}
+
 // Fs returns read only access to the Fs that this object is part of
 func (o *Object) Fs() fs.Info {
 	return o.f
@@ -646,7 +761,7 @@ func (o *Object) String() string {
 
 // Remote returns the remote path
 func (o *Object) Remote() string {
-	remote := o.Object.Remote()
+	remote := o.encryptedRemote()
 	decryptedName, err := o.f.cipher.DecryptFileName(remote)
 	if err != nil {
 		fs.Debugf(remote, "Undecryptable file name: %v", err)
@@ -666,7 +781,7 @@ func (o *Object) Size() int64 {
 
 // Hash returns the selected checksum of the file
 // If no checksum is available it returns ""
-func (o *Object) Hash(ht hash.Type) (string, error) {
+func (o *Object) Hash(ctx context.Context, ht hash.Type) (string, error) {
 	return "", hash.ErrUnsupported
 }
 
@@ -676,7 +791,7 @@ func (o *Object) UnWrap() fs.Object {
 }
 
 // Open opens the file for read.  Call Close() on the returned io.ReadCloser
-func (o *Object) Open(options ...fs.OpenOption) (rc io.ReadCloser, err error) {
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (rc io.ReadCloser, err error) {
 	var openOptions []fs.OpenOption
 	var offset, limit int64 = 0, -1
 	for _, option := range options {
@@ -693,7 +808,7 @@ func (o *Object) Open(options ...fs.OpenOption) (rc io.ReadCloser, err error) {
 	rc, err = o.f.cipher.DecryptDataSeek(func(underlyingOffset, underlyingLimit int64) (io.ReadCloser, error) {
 		if underlyingOffset == 0 && underlyingLimit < 0 {
 			// Open with no seek
-			return o.Object.Open(openOptions...)
+			return o.Object.Open(ctx, openOptions...)
 		}
 		// Open stream with a range of underlyingOffset, underlyingLimit
 		end := int64(-1)
@@ -704,7 +819,7 @@ func (o *Object) Open(options ...fs.OpenOption) (rc io.ReadCloser, err error) {
 			}
 		}
 		newOpenOptions := append(openOptions, &fs.RangeOption{Start: underlyingOffset, End: end})
-		return o.Object.Open(newOpenOptions...)
+		return o.Object.Open(ctx, newOpenOptions...)
 	}, offset, limit)
 	if err != nil {
 		return nil, err
@@ -713,14 +828,24 @@ func (o *Object) Open(options ...fs.OpenOption) (rc io.ReadCloser, err error) {
 }
 
 // Update in to the object with the modTime given of the given size
-func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
-	update := func(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-		return o.Object, o.Object.Update(in, src, options...)
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	update := func(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+		return o.Object, o.Object.Update(ctx, in, src, options...)
 	}
-	_, err := o.f.put(in, src, options, update)
+	_, err := o.f.putData(ctx, in, src, options, update)
 	return err
 }
 
+// Remove an object and the sidecar holding its name if it has a
+// long name
+func (o *Object) Remove(ctx context.Context) error {
+	err := o.Object.Remove(ctx)
+	if err != nil || o.longName == "" {
+		return err
+	}
+	return o.f.removeLongName(ctx, o.Object.Remote())
+}
+
 // newDir returns a dir with the Name decrypted
 func (f *Fs) newDir(dir fs.Directory) fs.Directory {
 	newDir := fs.NewDirCopy(dir)
@@ -756,7 +881,7 @@ func (o *ObjectInfo) Fs() fs.Info {
 
 // Remote returns the remote path
 func (o *ObjectInfo) Remote() string {
-	return o.f.cipher.EncryptFileName(o.ObjectInfo.Remote())
+	return o.f.StoredName(o.ObjectInfo.Remote())
 }
 
 // Size returns the size of the file
@@ -770,7 +895,7 @@ func (o *ObjectInfo) Size() int64 {
 
 // Hash returns the selected checksum of the file
 // If no checksum is available it returns ""
-func (o *ObjectInfo) Hash(hash hash.Type) (string, error) {
+func (o *ObjectInfo) Hash(ctx context.Context, hash hash.Type) (string, error) {
 	return "", nil
 }
 
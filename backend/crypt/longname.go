@@ -0,0 +1,175 @@
+package crypt
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/object"
+	"github.com/pkg/errors"
+)
+
+// Long file names
+//
+// If max_name_length is set then a file whose encrypted leaf name is
+// longer than that is stored on the wrapped remote under a short name
+// made from a hash of the encrypted name.  The encrypted name itself
+// is stored in a sidecar object next to it which is read back when
+// the directory is listed.
+//
+// Neither suffix can appear in an encrypted name as none of the file
+// name encodings use "."
+const (
+	longNameSuffix        = ".rclonelong" // suffix of an object stored under a short name
+	longNameSidecarSuffix = ".name"       // added to the short name to make the sidecar
+	maxLongNameSize       = 64 * 1024     // largest sidecar we will read
+)
+
+// splitLeaf splits an encrypted path into the directory (with a
+// trailing "/" if it isn't empty) and the leaf name
+func splitLeaf(encrypted string) (dir, leaf string) {
+	i := strings.LastIndex(encrypted, "/")
+	return encrypted[:i+1], encrypted[i+1:]
+}
+
+// shortenFileName returns the path encrypted should be stored under
+// on the wrapped remote.
+//
+// If the leaf of encrypted is longer than maxLength bytes it is
+// replaced with a short name and the leaf is returned as longName,
+// otherwise encrypted is returned unchanged and longName is "".
+func shortenFileName(encrypted string, maxLength int) (stored, longName string) {
+	dir, leaf := splitLeaf(encrypted)
+	if maxLength <= 0 || len(leaf) <= maxLength {
+		return encrypted, ""
+	}
+	sum := sha256.Sum256([]byte(leaf))
+	return dir + encodeFileName(sum[:16]) + longNameSuffix, leaf
+}
+
+// isLongName returns whether stored is an object stored under a
+// short name
+func isLongName(stored string) bool {
+	return strings.HasSuffix(stored, longNameSuffix)
+}
+
+// isLongNameSidecar returns whether stored is the sidecar holding
+// the encrypted name of an object stored under a short name
+func isLongNameSidecar(stored string) bool {
+	return strings.HasSuffix(stored, longNameSuffix+longNameSidecarSuffix)
+}
+
+// longNames returns whether objects may be stored under short names
+//
+// This is true whatever max_name_length is set to so objects stored
+// with it set can still be read.
+func (f *Fs) longNames() bool {
+	return f.cipher.NameEncryptionMode() == NameEncryptionStandard
+}
+
+// encryptFileName encrypts remote returning the path it is stored
+// under on the wrapped remote and the encrypted leaf name if it had
+// to be shortened
+func (f *Fs) encryptFileName(remote string) (stored, longName string) {
+	encrypted := f.cipher.EncryptFileName(remote)
+	if !f.longNames() {
+		return encrypted, ""
+	}
+	return shortenFileName(encrypted, f.opt.MaxNameLength)
+}
+
+// readLongName reads the encrypted leaf name of the object stored
+// under the short name stored from its sidecar
+func (f *Fs) readLongName(ctx context.Context, stored string) (longName string, err error) {
+	o, err := f.Fs.NewObject(ctx, stored+longNameSidecarSuffix)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to find long name")
+	}
+	in, err := o.Open(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to open long name")
+	}
+	defer fs.CheckClose(in, &err)
+	buf, err := ioutil.ReadAll(io.LimitReader(in, maxLongNameSize+1))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read long name")
+	}
+	if len(buf) == 0 || len(buf) > maxLongNameSize || bytes.ContainsAny(buf, "/\x00") {
+		return "", errors.New("corrupted long name")
+	}
+	return string(buf), nil
+}
+
+// writeLongName writes longName into the sidecar of the object
+// stored under the short name stored
+func (f *Fs) writeLongName(ctx context.Context, stored, longName string) error {
+	src := object.NewStaticObjectInfo(stored+longNameSidecarSuffix, time.Now(), int64(len(longName)), true, nil, f.Fs)
+	_, err := f.Fs.Put(ctx, bytes.NewBufferString(longName), src)
+	if err != nil {
+		return errors.Wrap(err, "failed to write long name")
+	}
+	return nil
+}
+
+// removeLongName removes the sidecar of the object stored under the
+// short name stored
+func (f *Fs) removeLongName(ctx context.Context, stored string) error {
+	o, err := f.Fs.NewObject(ctx, stored+longNameSidecarSuffix)
+	if err == fs.ErrorObjectNotFound {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "failed to find long name")
+	}
+	err = o.Remove(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove long name")
+	}
+	return nil
+}
+
+// StoredName returns the path that remote is stored under on the
+// wrapped remote.  This is the encrypted name of remote unless it is
+// too long in which case it is the short name.
+func (f *Fs) StoredName(remote string) string {
+	return StoredFileName(f.cipher, f.opt.MaxNameLength, remote)
+}
+
+// StoredFileName returns the path that remote is stored under on the
+// wrapped remote of a crypt remote using cipher with max_name_length
+// set to maxNameLength.  Unlike Fs.StoredName it doesn't need the
+// remote to be made so it can be used offline.
+func StoredFileName(cipher Cipher, maxNameLength int, remote string) string {
+	encrypted := cipher.EncryptFileName(remote)
+	if cipher.NameEncryptionMode() != NameEncryptionStandard {
+		return encrypted
+	}
+	stored, _ := shortenFileName(encrypted, maxNameLength)
+	return stored
+}
+
+// HasLongName returns whether stored, a path on the wrapped remote of
+// a crypt remote using cipher, is a short name.  These can only be
+// decrypted with Fs.DecryptStoredName as the encrypted name is stored
+// in a sidecar on the remote.
+func HasLongName(cipher Cipher, stored string) bool {
+	return cipher.NameEncryptionMode() == NameEncryptionStandard && isLongName(stored)
+}
+
+// DecryptStoredName decrypts stored, a path on the wrapped remote,
+// reading the encrypted name from the sidecar if it is a short name.
+func (f *Fs) DecryptStoredName(ctx context.Context, stored string) (string, error) {
+	if HasLongName(f.cipher, stored) {
+		longName, err := f.readLongName(ctx, stored)
+		if err != nil {
+			return "", err
+		}
+		dir, _ := splitLeaf(stored)
+		stored = dir + longName
+	}
+	return f.cipher.DecryptFileName(stored)
+}
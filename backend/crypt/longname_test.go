@@ -0,0 +1,40 @@
+package crypt
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShortenFileName(t *testing.T) {
+	long := strings.Repeat("0", 64)
+	for _, test := range []struct {
+		in           string
+		maxLength    int
+		expected     string
+		expectedLong string
+	}{
+		{"", 32, "", ""},
+		{"dir/" + long, 0, "dir/" + long, ""},
+		{"dir/" + long, 64, "dir/" + long, ""},
+		{long + "/short", 32, long + "/short", ""},
+		{long, 32, "c3g5nkdhimniv50h5ujhiuish0.rclonelong", long},
+		{"dir/" + long, 32, "dir/c3g5nkdhimniv50h5ujhiuish0.rclonelong", long},
+	} {
+		actual, actualLong := shortenFileName(test.in, test.maxLength)
+		what := fmt.Sprintf("in=%q maxLength=%d", test.in, test.maxLength)
+		assert.Equal(t, test.expected, actual, what)
+		assert.Equal(t, test.expectedLong, actualLong, what)
+	}
+}
+
+func TestIsLongName(t *testing.T) {
+	assert.True(t, isLongName("dir/c3g5nkdhimniv50h5ujhiuish0.rclonelong"))
+	assert.False(t, isLongName("dir/c3g5nkdhimniv50h5ujhiuish0.rclonelong.name"))
+	assert.False(t, isLongName("c3g5nkdhimniv50h5ujhiuish0"))
+	assert.True(t, isLongNameSidecar("dir/c3g5nkdhimniv50h5ujhiuish0.rclonelong.name"))
+	assert.False(t, isLongNameSidecar("dir/c3g5nkdhimniv50h5ujhiuish0.rclonelong"))
+	assert.False(t, isLongNameSidecar("dir/file.name"))
+}
@@ -12,6 +12,7 @@ package drive
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -131,26 +132,13 @@ func init() {
 		Name:        "drive",
 		Description: "Google Drive",
 		NewFs:       NewFs,
+		CommandHelp: commandHelp,
 		Config: func(name string, m configmap.Mapper) {
-			// Parse config into Options struct
-			opt := new(Options)
-			err := configstruct.Set(m, opt)
+			opt, err := configScopes(m)
 			if err != nil {
 				fs.Errorf(nil, "Couldn't parse config into struct: %v", err)
 				return
 			}
-			// Fill in the scopes
-			if opt.Scope == "" {
-				opt.Scope = defaultScope
-			}
-			driveConfig.Scopes = nil
-			for _, scope := range strings.Split(opt.Scope, ",") {
-				driveConfig.Scopes = append(driveConfig.Scopes, scopePrefix+strings.TrimSpace(scope))
-				// Set the root_folder_id if using drive.appfolder
-				if scope == "drive.appfolder" {
-					m.Set("root_folder_id", "appDataFolder")
-				}
-			}
 			if opt.ServiceAccountFile == "" {
 				err = oauthutil.Config("drive", name, m, driveConfig)
 				if err != nil {
@@ -162,6 +150,16 @@ func init() {
 				log.Fatalf("Failed to configure team drive: %v", err)
 			}
 		},
+		ConfigState: func(name string, m configmap.Mapper, in fs.ConfigIn) (*fs.ConfigOut, error) {
+			opt, err := configScopes(m)
+			if err != nil {
+				return nil, errors.Wrap(err, "couldn't parse config into struct")
+			}
+			if opt.ServiceAccountFile != "" {
+				return nil, nil
+			}
+			return oauthutil.ConfigState("drive", name, m, in, driveConfig)
+		},
 		Options: []fs.Option{{
 			Name: config.ConfigClientID,
 			Help: "Google Application Client Id\nLeave blank normally.",
@@ -354,6 +352,30 @@ will download it anyway.`,
 	}
 }
 
+// configScopes parses the config in m and sets the scopes in
+// driveConfig from it
+func configScopes(m configmap.Mapper) (*Options, error) {
+	// Parse config into Options struct
+	opt := new(Options)
+	err := configstruct.Set(m, opt)
+	if err != nil {
+		return nil, err
+	}
+	// Fill in the scopes
+	if opt.Scope == "" {
+		opt.Scope = defaultScope
+	}
+	driveConfig.Scopes = nil
+	for _, scope := range strings.Split(opt.Scope, ",") {
+		driveConfig.Scopes = append(driveConfig.Scopes, scopePrefix+strings.TrimSpace(scope))
+		// Set the root_folder_id if using drive.appfolder
+		if scope == "drive.appfolder" {
+			m.Set("root_folder_id", "appDataFolder")
+		}
+	}
+	return opt, nil
+}
+
 // Options defines the configuration for this backend
 type Options struct {
 	Scope                     string        `config:"scope"`
@@ -497,7 +519,7 @@ func containsString(slice []string, s string) bool {
 // If the user fn ever returns true then it early exits with found = true
 //
 // Search params: https://developers.google.com/drive/search-parameters
-func (f *Fs) list(dirIDs []string, title string, directoriesOnly, filesOnly, includeAll bool, fn listFn) (found bool, err error) {
+func (f *Fs) list(ctx context.Context, dirIDs []string, title string, directoriesOnly, filesOnly, includeAll bool, fn listFn) (found bool, err error) {
 	var query []string
 	if !includeAll {
 		q := "trashed=" + strconv.FormatBool(f.opt.TrashedOnly)
@@ -588,7 +610,7 @@ OUTER:
 	for {
 		var files *drive.FileList
 		err = f.pacer.Call(func() (bool, error) {
-			files, err = list.Fields(googleapi.Field(fields)).Do()
+			files, err = list.Fields(googleapi.Field(fields)).Context(ctx).Do()
 			return shouldRetry(err)
 		})
 		if err != nil {
@@ -699,6 +721,7 @@ func parseExtensions(extensionsIn ...string) (extensions, mimeTypes []string, er
 
 // Figure out if the user wants to use a team drive
 func configTeamDrive(opt *Options, m configmap.Mapper, name string) error {
+	ctx := context.Background()
 	if opt.TeamDriveID == "" {
 		fmt.Printf("Configure this as a team drive?\n")
 	} else {
@@ -722,7 +745,7 @@ func configTeamDrive(opt *Options, m configmap.Mapper, name string) error {
 	for {
 		var teamDrives *drive.TeamDriveList
 		err = newPacer().Call(func() (bool, error) {
-			teamDrives, err = listTeamDrives.Do()
+			teamDrives, err = listTeamDrives.Context(ctx).Do()
 			return shouldRetry(err)
 		})
 		if err != nil {
@@ -763,7 +786,7 @@ func getServiceAccountClient(opt *Options, credentialsData []byte) (*http.Client
 	if opt.Impersonate != "" {
 		conf.Subject = opt.Impersonate
 	}
-	ctxWithSpecialClient := oauthutil.Context(fshttp.NewClient(fs.Config))
+	ctxWithSpecialClient := oauthutil.Context(fshttp.NewClientForBackend(fs.Config, "drive"))
 	return oauth2.NewClient(ctxWithSpecialClient, conf.TokenSource(ctxWithSpecialClient)), nil
 }
 
@@ -785,7 +808,7 @@ func createOAuthClient(opt *Options, name string, m configmap.Mapper) (*http.Cli
 			return nil, errors.Wrap(err, "failed to create oauth client from service account")
 		}
 	} else {
-		oAuthClient, _, err = oauthutil.NewClient(name, m, driveConfig)
+		oAuthClient, _, err = oauthutil.NewClientWithBaseClient(name, m, driveConfig, fshttp.NewClientForBackend(fs.Config, "drive"))
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create oauth client")
 		}
@@ -826,6 +849,7 @@ func (f *Fs) setUploadCutoff(cs fs.SizeSuffix) (old fs.SizeSuffix, err error) {
 
 // NewFs contstructs an Fs from the path, container:path
 func NewFs(name, path string, m configmap.Mapper) (fs.Fs, error) {
+	ctx := context.Background()
 	// Parse config into Options struct
 	opt := new(Options)
 	err := configstruct.Set(m, opt)
@@ -862,6 +886,8 @@ func NewFs(name, path string, m configmap.Mapper) (fs.Fs, error) {
 		DuplicateFiles:          true,
 		ReadMimeType:            true,
 		WriteMimeType:           true,
+		ReadMetadata:            true,
+		WriteMetadata:           true,
 		CanHaveEmptyDirectories: true,
 	}).Fill(f)
 
@@ -911,7 +937,7 @@ func NewFs(name, path string, m configmap.Mapper) (fs.Fs, error) {
 	}
 
 	// Find the current root
-	err = f.dirCache.FindRoot(false)
+	err = f.dirCache.FindRoot(ctx, false)
 	if err != nil {
 		// Assume it is a file
 		newRoot, remote := dircache.SplitPath(root)
@@ -919,12 +945,12 @@ func NewFs(name, path string, m configmap.Mapper) (fs.Fs, error) {
 		tempF.dirCache = dircache.New(newRoot, f.rootFolderID, &tempF)
 		tempF.root = newRoot
 		// Make new Fs which is the parent
-		err = tempF.dirCache.FindRoot(false)
+		err = tempF.dirCache.FindRoot(ctx, false)
 		if err != nil {
 			// No root so return old f
 			return f, nil
 		}
-		_, err := tempF.NewObject(remote)
+		_, err := tempF.NewObject(ctx, remote)
 		if err != nil {
 			// unable to list folder so return old f
 			return f, nil
@@ -1066,8 +1092,8 @@ func (f *Fs) newObjectWithExportInfo(
 
 // NewObject finds the Object at remote.  If it can't be found
 // it returns the error fs.ErrorObjectNotFound.
-func (f *Fs) NewObject(remote string) (fs.Object, error) {
-	info, extension, exportName, exportMimeType, isDocument, err := f.getRemoteInfoWithExport(remote)
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	info, extension, exportName, exportMimeType, isDocument, err := f.getRemoteInfoWithExport(ctx, remote)
 	if err != nil {
 		return nil, err
 	}
@@ -1085,9 +1111,9 @@ func (f *Fs) NewObject(remote string) (fs.Object, error) {
 }
 
 // FindLeaf finds a directory of name leaf in the folder with ID pathID
-func (f *Fs) FindLeaf(pathID, leaf string) (pathIDOut string, found bool, err error) {
+func (f *Fs) FindLeaf(ctx context.Context, pathID, leaf string) (pathIDOut string, found bool, err error) {
 	// Find the leaf in pathID
-	found, err = f.list([]string{pathID}, leaf, true, false, false, func(item *drive.File) bool {
+	found, err = f.list(ctx, []string{pathID}, leaf, true, false, false, func(item *drive.File) bool {
 		if !f.opt.SkipGdocs {
 			_, exportName, _, isDocument := f.findExportFormat(item)
 			if exportName == leaf {
@@ -1108,7 +1134,7 @@ func (f *Fs) FindLeaf(pathID, leaf string) (pathIDOut string, found bool, err er
 }
 
 // CreateDir makes a directory with pathID as parent and name leaf
-func (f *Fs) CreateDir(pathID, leaf string) (newID string, err error) {
+func (f *Fs) CreateDir(ctx context.Context, pathID, leaf string) (newID string, err error) {
 	// fmt.Println("Making", path)
 	// Define the metadata for the directory we are going to create.
 	createInfo := &drive.File{
@@ -1122,7 +1148,7 @@ func (f *Fs) CreateDir(pathID, leaf string) (newID string, err error) {
 		info, err = f.svc.Files.Create(createInfo).
 			Fields("id").
 			SupportsTeamDrives(f.isTeamDrive).
-			Do()
+			Context(ctx).Do()
 		return shouldRetry(err)
 	})
 	if err != nil {
@@ -1160,12 +1186,15 @@ func linkTemplate(mt string) *template.Template {
 }
 func (f *Fs) fetchFormats() {
 	fetchFormatsOnce.Do(func() {
+		// The formats are fetched once for all callers so this
+		// can't be cancelled by any one of them
+		ctx := context.Background()
 		var about *drive.About
 		var err error
 		err = f.pacer.Call(func() (bool, error) {
 			about, err = f.svc.About.Get().
 				Fields("exportFormats,importFormats").
-				Do()
+				Context(ctx).Do()
 			return shouldRetry(err)
 		})
 		if err != nil {
@@ -1270,18 +1299,18 @@ func (f *Fs) findImportFormat(mimeType string) string {
 //
 // This should return ErrDirNotFound if the directory isn't
 // found.
-func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
-	err = f.dirCache.FindRoot(false)
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	err = f.dirCache.FindRoot(ctx, false)
 	if err != nil {
 		return nil, err
 	}
-	directoryID, err := f.dirCache.FindDir(dir, false)
+	directoryID, err := f.dirCache.FindDir(ctx, dir, false)
 	if err != nil {
 		return nil, err
 	}
 
 	var iErr error
-	_, err = f.list([]string{directoryID}, "", false, false, false, func(item *drive.File) bool {
+	_, err = f.list(ctx, []string{directoryID}, "", false, false, false, func(item *drive.File) bool {
 		entry, err := f.itemToDirEntry(path.Join(dir, item.Name), item)
 		if err != nil {
 			iErr = err
@@ -1306,7 +1335,7 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 // In each cycle, will wait up to 10ms to read up to grouping entries from the in channel.
 // If an error occurs it will be send to the out channel and then return. Once the in channel is closed,
 // nil is send to the out channel and the function returns.
-func (f *Fs) listRRunner(wg *sync.WaitGroup, in <-chan string, out chan<- error, cb func(fs.DirEntry) error, grouping int) {
+func (f *Fs) listRRunner(ctx context.Context, wg *sync.WaitGroup, in <-chan string, out chan<- error, cb func(fs.DirEntry) error, grouping int) {
 	var dirs []string
 
 	for dir := range in {
@@ -1325,7 +1354,7 @@ func (f *Fs) listRRunner(wg *sync.WaitGroup, in <-chan string, out chan<- error,
 			}
 		}
 		var iErr error
-		_, err := f.list(dirs, "", false, false, false, func(item *drive.File) bool {
+		_, err := f.list(ctx, dirs, "", false, false, false, func(item *drive.File) bool {
 			parentPath := ""
 			if len(item.Parents) > 0 {
 				p, ok := f.dirCache.GetInv(item.Parents[0])
@@ -1380,17 +1409,17 @@ func (f *Fs) listRRunner(wg *sync.WaitGroup, in <-chan string, out chan<- error,
 //
 // Don't implement this unless you have a more efficient way
 // of listing recursively that doing a directory traversal.
-func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
+func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
 	const (
 		grouping    = 50
 		inputBuffer = 1000
 	)
 
-	err = f.dirCache.FindRoot(false)
+	err = f.dirCache.FindRoot(ctx, false)
 	if err != nil {
 		return err
 	}
-	directoryID, err := f.dirCache.FindDir(dir, false)
+	directoryID, err := f.dirCache.FindDir(ctx, dir, false)
 	if err != nil {
 		return err
 	}
@@ -1420,7 +1449,7 @@ func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
 	in <- directoryID
 
 	for i := 0; i < fs.Config.Checkers; i++ {
-		go f.listRRunner(&wg, in, out, cb, grouping)
+		go f.listRRunner(ctx, &wg, in, out, cb, grouping)
 	}
 	go func() {
 		// wait until the all directories are processed
@@ -1494,8 +1523,8 @@ func (f *Fs) itemToDirEntry(remote string, item *drive.File) (fs.DirEntry, error
 // Creates a drive.File info from the parameters passed in.
 //
 // Used to create new objects
-func (f *Fs) createFileInfo(remote string, modTime time.Time) (*drive.File, error) {
-	leaf, directoryID, err := f.dirCache.FindRootAndPath(remote, true)
+func (f *Fs) createFileInfo(ctx context.Context, remote string, modTime time.Time) (*drive.File, error) {
+	leaf, directoryID, err := f.dirCache.FindRootAndPath(ctx, remote, true)
 	if err != nil {
 		return nil, err
 	}
@@ -1516,29 +1545,29 @@ func (f *Fs) createFileInfo(remote string, modTime time.Time) (*drive.File, erro
 // Copy the reader in to the new object which is returned
 //
 // The new object may have been created if an error is returned
-func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-	exisitingObj, err := f.NewObject(src.Remote())
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	exisitingObj, err := f.NewObject(ctx, src.Remote())
 	switch err {
 	case nil:
-		return exisitingObj, exisitingObj.Update(in, src, options...)
+		return exisitingObj, exisitingObj.Update(ctx, in, src, options...)
 	case fs.ErrorObjectNotFound:
 		// Not found so create it
-		return f.PutUnchecked(in, src, options...)
+		return f.PutUnchecked(ctx, in, src, options...)
 	default:
 		return nil, err
 	}
 }
 
 // PutStream uploads to the remote path with the modTime given of indeterminate size
-func (f *Fs) PutStream(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-	return f.Put(in, src, options...)
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.Put(ctx, in, src, options...)
 }
 
 // PutUnchecked uploads the object
 //
 // This will create a duplicate if we upload a new file without
 // checking to see if there is one already - use Put() for that.
-func (f *Fs) PutUnchecked(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+func (f *Fs) PutUnchecked(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
 	remote := src.Remote()
 	size := src.Size()
 	modTime := src.ModTime()
@@ -1563,10 +1592,11 @@ func (f *Fs) PutUnchecked(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOpt
 		}
 	}
 
-	createInfo, err := f.createFileInfo(remote, modTime)
+	createInfo, err := f.createFileInfo(ctx, remote, modTime)
 	if err != nil {
 		return nil, err
 	}
+	createInfo.Properties = metadataToProperties(options)
 	if importMimeType != "" {
 		createInfo.MimeType = importMimeType
 	}
@@ -1581,7 +1611,7 @@ func (f *Fs) PutUnchecked(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOpt
 				Fields(partialFields).
 				SupportsTeamDrives(f.isTeamDrive).
 				KeepRevisionForever(f.opt.KeepRevisionForever).
-				Do()
+				Context(ctx).Do()
 			return shouldRetry(err)
 		})
 		if err != nil {
@@ -1589,7 +1619,7 @@ func (f *Fs) PutUnchecked(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOpt
 		}
 	} else {
 		// Upload the file in chunks
-		info, err = f.Upload(in, size, srcMimeType, "", remote, createInfo)
+		info, err = f.Upload(ctx, in, size, srcMimeType, "", remote, createInfo)
 		if err != nil {
 			return nil, err
 		}
@@ -1599,7 +1629,7 @@ func (f *Fs) PutUnchecked(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOpt
 
 // MergeDirs merges the contents of all the directories passed
 // in into the first one and rmdirs the other directories.
-func (f *Fs) MergeDirs(dirs []fs.Directory) error {
+func (f *Fs) MergeDirs(ctx context.Context, dirs []fs.Directory) error {
 	if len(dirs) < 2 {
 		return nil
 	}
@@ -1607,7 +1637,7 @@ func (f *Fs) MergeDirs(dirs []fs.Directory) error {
 	for _, srcDir := range dirs[1:] {
 		// list the the objects
 		infos := []*drive.File{}
-		_, err := f.list([]string{srcDir.ID()}, "", false, false, true, func(info *drive.File) bool {
+		_, err := f.list(ctx, []string{srcDir.ID()}, "", false, false, true, func(info *drive.File) bool {
 			infos = append(infos, info)
 			return false
 		})
@@ -1624,7 +1654,7 @@ func (f *Fs) MergeDirs(dirs []fs.Directory) error {
 					AddParents(dstDir.ID()).
 					Fields("").
 					SupportsTeamDrives(f.isTeamDrive).
-					Do()
+					Context(ctx).Do()
 				return shouldRetry(err)
 			})
 			if err != nil {
@@ -1633,7 +1663,7 @@ func (f *Fs) MergeDirs(dirs []fs.Directory) error {
 		}
 		// rmdir (into trash) the now empty source directory
 		fs.Infof(srcDir, "removing empty directory")
-		err = f.rmdir(srcDir.ID(), true)
+		err = f.rmdir(ctx, srcDir.ID(), true)
 		if err != nil {
 			return errors.Wrapf(err, "MergDirs move failed to rmdir %q", srcDir)
 		}
@@ -1642,19 +1672,19 @@ func (f *Fs) MergeDirs(dirs []fs.Directory) error {
 }
 
 // Mkdir creates the container if it doesn't exist
-func (f *Fs) Mkdir(dir string) error {
-	err := f.dirCache.FindRoot(true)
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
+	err := f.dirCache.FindRoot(ctx, true)
 	if err != nil {
 		return err
 	}
 	if dir != "" {
-		_, err = f.dirCache.FindDir(dir, true)
+		_, err = f.dirCache.FindDir(ctx, dir, true)
 	}
 	return err
 }
 
 // Rmdir deletes a directory unconditionally by ID
-func (f *Fs) rmdir(directoryID string, useTrash bool) error {
+func (f *Fs) rmdir(ctx context.Context, directoryID string, useTrash bool) error {
 	return f.pacer.Call(func() (bool, error) {
 		var err error
 		if useTrash {
@@ -1664,12 +1694,12 @@ func (f *Fs) rmdir(directoryID string, useTrash bool) error {
 			_, err = f.svc.Files.Update(directoryID, &info).
 				Fields("").
 				SupportsTeamDrives(f.isTeamDrive).
-				Do()
+				Context(ctx).Do()
 		} else {
 			err = f.svc.Files.Delete(directoryID).
 				Fields("").
 				SupportsTeamDrives(f.isTeamDrive).
-				Do()
+				Context(ctx).Do()
 		}
 		return shouldRetry(err)
 	})
@@ -1678,15 +1708,15 @@ func (f *Fs) rmdir(directoryID string, useTrash bool) error {
 // Rmdir deletes a directory
 //
 // Returns an error if it isn't empty
-func (f *Fs) Rmdir(dir string) error {
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
 	root := path.Join(f.root, dir)
 	dc := f.dirCache
-	directoryID, err := dc.FindDir(dir, false)
+	directoryID, err := dc.FindDir(ctx, dir, false)
 	if err != nil {
 		return err
 	}
 	var trashedFiles = false
-	found, err := f.list([]string{directoryID}, "", false, false, true, func(item *drive.File) bool {
+	found, err := f.list(ctx, []string{directoryID}, "", false, false, true, func(item *drive.File) bool {
 		if !item.Trashed {
 			fs.Debugf(dir, "Rmdir: contains file: %q", item.Name)
 			return true
@@ -1705,7 +1735,7 @@ func (f *Fs) Rmdir(dir string) error {
 		// trash the directory if it had trashed files
 		// in or the user wants to trash, otherwise
 		// delete it.
-		err = f.rmdir(directoryID, trashedFiles || f.opt.UseTrash)
+		err = f.rmdir(ctx, directoryID, trashedFiles || f.opt.UseTrash)
 		if err != nil {
 			return err
 		}
@@ -1731,7 +1761,7 @@ func (f *Fs) Precision() time.Duration {
 // Will only be called if src.Fs().Name() == f.Name()
 //
 // If it isn't possible then return fs.ErrorCantCopy
-func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
 	var srcObj *baseObject
 	ext := ""
 	switch src := src.(type) {
@@ -1754,7 +1784,7 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 		remote = remote[:len(remote)-len(ext)]
 	}
 
-	createInfo, err := f.createFileInfo(remote, src.ModTime())
+	createInfo, err := f.createFileInfo(ctx, remote, src.ModTime())
 	if err != nil {
 		return nil, err
 	}
@@ -1765,7 +1795,7 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 			Fields(partialFields).
 			SupportsTeamDrives(f.isTeamDrive).
 			KeepRevisionForever(f.opt.KeepRevisionForever).
-			Do()
+			Context(ctx).Do()
 		return shouldRetry(err)
 	})
 	if err != nil {
@@ -1779,11 +1809,11 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 // Optional interface: Only implement this if you have a way of
 // deleting all the files quicker than just running Remove() on the
 // result of List()
-func (f *Fs) Purge() error {
+func (f *Fs) Purge(ctx context.Context) error {
 	if f.root == "" {
 		return errors.New("can't purge root directory")
 	}
-	err := f.dirCache.FindRoot(false)
+	err := f.dirCache.FindRoot(ctx, false)
 	if err != nil {
 		return err
 	}
@@ -1795,12 +1825,12 @@ func (f *Fs) Purge() error {
 			_, err = f.svc.Files.Update(f.dirCache.RootID(), &info).
 				Fields("").
 				SupportsTeamDrives(f.isTeamDrive).
-				Do()
+				Context(ctx).Do()
 		} else {
 			err = f.svc.Files.Delete(f.dirCache.RootID()).
 				Fields("").
 				SupportsTeamDrives(f.isTeamDrive).
-				Do()
+				Context(ctx).Do()
 		}
 		return shouldRetry(err)
 	})
@@ -1812,9 +1842,9 @@ func (f *Fs) Purge() error {
 }
 
 // CleanUp empties the trash
-func (f *Fs) CleanUp() error {
+func (f *Fs) CleanUp(ctx context.Context) error {
 	err := f.pacer.Call(func() (bool, error) {
-		err := f.svc.Files.EmptyTrash().Do()
+		err := f.svc.Files.EmptyTrash().Context(ctx).Do()
 		return shouldRetry(err)
 	})
 
@@ -1825,7 +1855,7 @@ func (f *Fs) CleanUp() error {
 }
 
 // About gets quota information
-func (f *Fs) About() (*fs.Usage, error) {
+func (f *Fs) About(ctx context.Context) (*fs.Usage, error) {
 	if f.isTeamDrive {
 		// Teamdrives don't appear to have a usage API so just return empty
 		return &fs.Usage{}, nil
@@ -1833,7 +1863,7 @@ func (f *Fs) About() (*fs.Usage, error) {
 	var about *drive.About
 	var err error
 	err = f.pacer.Call(func() (bool, error) {
-		about, err = f.svc.About.Get().Fields("storageQuota").Do()
+		about, err = f.svc.About.Get().Fields("storageQuota").Context(ctx).Do()
 		return shouldRetry(err)
 	})
 	if err != nil {
@@ -1861,7 +1891,7 @@ func (f *Fs) About() (*fs.Usage, error) {
 // Will only be called if src.Fs().Name() == f.Name()
 //
 // If it isn't possible then return fs.ErrorCantMove
-func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
+func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
 	var srcObj *baseObject
 	ext := ""
 	switch src := src.(type) {
@@ -1884,13 +1914,13 @@ func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
 		remote = remote[:len(remote)-len(ext)]
 	}
 
-	_, srcParentID, err := srcObj.fs.dirCache.FindPath(src.Remote(), false)
+	_, srcParentID, err := srcObj.fs.dirCache.FindPath(ctx, src.Remote(), false)
 	if err != nil {
 		return nil, err
 	}
 
 	// Temporary Object under construction
-	dstInfo, err := f.createFileInfo(remote, src.ModTime())
+	dstInfo, err := f.createFileInfo(ctx, remote, src.ModTime())
 	if err != nil {
 		return nil, err
 	}
@@ -1905,7 +1935,7 @@ func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
 			AddParents(dstParents).
 			Fields(partialFields).
 			SupportsTeamDrives(f.isTeamDrive).
-			Do()
+			Context(ctx).Do()
 		return shouldRetry(err)
 	})
 	if err != nil {
@@ -1916,13 +1946,13 @@ func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
 }
 
 // PublicLink adds a "readable by anyone with link" permission on the given file or folder.
-func (f *Fs) PublicLink(remote string) (link string, err error) {
-	id, err := f.dirCache.FindDir(remote, false)
+func (f *Fs) PublicLink(ctx context.Context, remote string) (link string, err error) {
+	id, err := f.dirCache.FindDir(ctx, remote, false)
 	if err == nil {
 		fs.Debugf(f, "attempting to share directory '%s'", remote)
 	} else {
 		fs.Debugf(f, "attempting to share single file '%s'", remote)
-		o, err := f.NewObject(remote)
+		o, err := f.NewObject(ctx, remote)
 		if err != nil {
 			return "", err
 		}
@@ -1941,7 +1971,7 @@ func (f *Fs) PublicLink(remote string) (link string, err error) {
 		_, err = f.svc.Permissions.Create(id, permission).
 			Fields("").
 			SupportsTeamDrives(f.isTeamDrive).
-			Do()
+			Context(ctx).Do()
 		return shouldRetry(err)
 	})
 	if err != nil {
@@ -1958,7 +1988,7 @@ func (f *Fs) PublicLink(remote string) (link string, err error) {
 // If it isn't possible then return fs.ErrorCantDirMove
 //
 // If destination exists then return fs.ErrorDirExists
-func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
+func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string) error {
 	srcFs, ok := src.(*Fs)
 	if !ok {
 		fs.Debugf(srcFs, "Can't move directory - not same remote type")
@@ -1974,14 +2004,14 @@ func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
 	}
 
 	// find the root src directory
-	err := srcFs.dirCache.FindRoot(false)
+	err := srcFs.dirCache.FindRoot(ctx, false)
 	if err != nil {
 		return err
 	}
 
 	// find the root dst directory
 	if dstRemote != "" {
-		err = f.dirCache.FindRoot(true)
+		err = f.dirCache.FindRoot(ctx, true)
 		if err != nil {
 			return err
 		}
@@ -1997,14 +2027,14 @@ func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
 	if dstRemote == "" {
 		findPath = f.root
 	}
-	leaf, dstDirectoryID, err = f.dirCache.FindPath(findPath, true)
+	leaf, dstDirectoryID, err = f.dirCache.FindPath(ctx, findPath, true)
 	if err != nil {
 		return err
 	}
 
 	// Check destination does not exist
 	if dstRemote != "" {
-		_, err = f.dirCache.FindDir(dstRemote, false)
+		_, err = f.dirCache.FindDir(ctx, dstRemote, false)
 		if err == fs.ErrorDirNotFound {
 			// OK
 		} else if err != nil {
@@ -2019,14 +2049,14 @@ func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
 	if srcRemote == "" {
 		srcDirectoryID, err = srcFs.dirCache.RootParentID()
 	} else {
-		_, srcDirectoryID, err = srcFs.dirCache.FindPath(srcRemote, false)
+		_, srcDirectoryID, err = srcFs.dirCache.FindPath(ctx, srcRemote, false)
 	}
 	if err != nil {
 		return err
 	}
 
 	// Find ID of src
-	srcID, err := srcFs.dirCache.FindDir(srcRemote, false)
+	srcID, err := srcFs.dirCache.FindDir(ctx, srcRemote, false)
 	if err != nil {
 		return err
 	}
@@ -2041,7 +2071,7 @@ func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
 			AddParents(dstDirectoryID).
 			Fields("").
 			SupportsTeamDrives(f.isTeamDrive).
-			Do()
+			Context(ctx).Do()
 		return shouldRetry(err)
 	})
 	if err != nil {
@@ -2057,10 +2087,10 @@ func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
 // Automatically restarts itself in case of unexpected behaviour of the remote.
 //
 // Close the returned channel to stop being notified.
-func (f *Fs) ChangeNotify(notifyFunc func(string, fs.EntryType), pollIntervalChan <-chan time.Duration) {
+func (f *Fs) ChangeNotify(ctx context.Context, notifyFunc func(string, fs.EntryType), pollIntervalChan <-chan time.Duration) {
 	go func() {
 		// get the StartPageToken early so all changes from now on get processed
-		startPageToken, err := f.changeNotifyStartPageToken()
+		startPageToken, err := f.changeNotifyStartPageToken(ctx)
 		if err != nil {
 			fs.Infof(f, "Failed to get StartPageToken: %s", err)
 		}
@@ -2085,14 +2115,14 @@ func (f *Fs) ChangeNotify(notifyFunc func(string, fs.EntryType), pollIntervalCha
 				}
 			case <-tickerC:
 				if startPageToken == "" {
-					startPageToken, err = f.changeNotifyStartPageToken()
+					startPageToken, err = f.changeNotifyStartPageToken(ctx)
 					if err != nil {
 						fs.Infof(f, "Failed to get StartPageToken: %s", err)
 						continue
 					}
 				}
 				fs.Debugf(f, "Checking for changes on remote")
-				startPageToken, err = f.changeNotifyRunner(notifyFunc, startPageToken)
+				startPageToken, err = f.changeNotifyRunner(ctx, notifyFunc, startPageToken)
 				if err != nil {
 					fs.Infof(f, "Change notify listener failure: %s", err)
 				}
@@ -2100,12 +2130,12 @@ func (f *Fs) ChangeNotify(notifyFunc func(string, fs.EntryType), pollIntervalCha
 		}
 	}()
 }
-func (f *Fs) changeNotifyStartPageToken() (pageToken string, err error) {
+func (f *Fs) changeNotifyStartPageToken(ctx context.Context) (pageToken string, err error) {
 	var startPageToken *drive.StartPageToken
 	err = f.pacer.Call(func() (bool, error) {
 		startPageToken, err = f.svc.Changes.GetStartPageToken().
 			SupportsTeamDrives(f.isTeamDrive).
-			Do()
+			Context(ctx).Do()
 		return shouldRetry(err)
 	})
 	if err != nil {
@@ -2114,7 +2144,7 @@ func (f *Fs) changeNotifyStartPageToken() (pageToken string, err error) {
 	return startPageToken.StartPageToken, nil
 }
 
-func (f *Fs) changeNotifyRunner(notifyFunc func(string, fs.EntryType), startPageToken string) (newStartPageToken string, err error) {
+func (f *Fs) changeNotifyRunner(ctx context.Context, notifyFunc func(string, fs.EntryType), startPageToken string) (newStartPageToken string, err error) {
 	pageToken := startPageToken
 	for {
 		var changeList *drive.ChangeList
@@ -2130,7 +2160,7 @@ func (f *Fs) changeNotifyRunner(notifyFunc func(string, fs.EntryType), startPage
 				changesCall.SupportsTeamDrives(true)
 				changesCall.IncludeTeamDriveItems(true)
 			}
-			changeList, err = changesCall.Do()
+			changeList, err = changesCall.Context(ctx).Do()
 			return shouldRetry(err)
 		})
 		if err != nil {
@@ -2199,6 +2229,75 @@ func (f *Fs) DirCacheFlush() {
 	f.dirCache.ResetRoot()
 }
 
+var commandHelp = []fs.CommandHelp{{
+	Name:  "drives",
+	Short: "List the shared drives available to this account",
+	Long: `This command lists the shared drives (team drives) available to this
+account.
+
+Usage:
+
+    rclone backend drives drive:
+
+This will return a JSON list of objects like this
+
+    [
+        {
+            "id": "0ABCDEF-01234567890",
+            "kind": "drive#teamDrive",
+            "name": "My Drive"
+        },
+        {
+            "id": "0ABCDEFabcdefghijkl",
+            "kind": "drive#teamDrive",
+            "name": "Test Drive"
+        }
+    ]
+
+Use the id as the team_drive option to configure a remote for one
+of them.
+`,
+}}
+
+// listTeamDrives lists all the team drives
+func (f *Fs) listTeamDrives(ctx context.Context) (drives []*drive.TeamDrive, err error) {
+	listTeamDrives := f.svc.Teamdrives.List().PageSize(100)
+	for {
+		var teamDrives *drive.TeamDriveList
+		err = f.pacer.Call(func() (bool, error) {
+			teamDrives, err = listTeamDrives.Context(ctx).Do()
+			return shouldRetry(err)
+		})
+		if err != nil {
+			return drives, errors.Wrap(err, "listing team drives failed")
+		}
+		drives = append(drives, teamDrives.TeamDrives...)
+		if teamDrives.NextPageToken == "" {
+			break
+		}
+		listTeamDrives.PageToken(teamDrives.NextPageToken)
+	}
+	return drives, nil
+}
+
+// Command the backend to run a named command
+//
+// The command run is name
+// args may be used to read arguments from
+// opts may be used to read optional arguments from
+//
+// The result should be capable of being JSON encoded
+// If it is a string or a []string it will be shown to the user
+// otherwise it will be JSON encoded and shown to the user like that
+func (f *Fs) Command(ctx context.Context, name string, arg []string, opt map[string]string) (interface{}, error) {
+	switch name {
+	case "drives":
+		return f.listTeamDrives(ctx)
+	default:
+		return nil, fs.ErrorCommandNotFound
+	}
+}
+
 // Hashes returns the supported hash sets.
 func (f *Fs) Hashes() hash.Set {
 	return hash.Set(hash.MD5)
@@ -2230,13 +2329,13 @@ func (o *baseObject) Remote() string {
 }
 
 // Hash returns the Md5sum of an object returning a lowercase hex string
-func (o *Object) Hash(t hash.Type) (string, error) {
+func (o *Object) Hash(ctx context.Context, t hash.Type) (string, error) {
 	if t != hash.MD5 {
 		return "", hash.ErrUnsupported
 	}
 	return o.md5sum, nil
 }
-func (o *baseObject) Hash(t hash.Type) (string, error) {
+func (o *baseObject) Hash(ctx context.Context, t hash.Type) (string, error) {
 	if t != hash.MD5 {
 		return "", hash.ErrUnsupported
 	}
@@ -2249,15 +2348,15 @@ func (o *baseObject) Size() int64 {
 }
 
 // getRemoteInfo returns a drive.File for the remote
-func (f *Fs) getRemoteInfo(remote string) (info *drive.File, err error) {
-	info, _, _, _, _, err = f.getRemoteInfoWithExport(remote)
+func (f *Fs) getRemoteInfo(ctx context.Context, remote string) (info *drive.File, err error) {
+	info, _, _, _, _, err = f.getRemoteInfoWithExport(ctx, remote)
 	return
 }
 
 // getRemoteInfoWithExport returns a drive.File and the export settings for the remote
-func (f *Fs) getRemoteInfoWithExport(remote string) (
+func (f *Fs) getRemoteInfoWithExport(ctx context.Context, remote string) (
 	info *drive.File, extension, exportName, exportMimeType string, isDocument bool, err error) {
-	leaf, directoryID, err := f.dirCache.FindRootAndPath(remote, false)
+	leaf, directoryID, err := f.dirCache.FindRootAndPath(ctx, remote, false)
 	if err != nil {
 		if err == fs.ErrorDirNotFound {
 			return nil, "", "", "", false, fs.ErrorObjectNotFound
@@ -2265,7 +2364,7 @@ func (f *Fs) getRemoteInfoWithExport(remote string) (
 		return nil, "", "", "", false, err
 	}
 
-	found, err := f.list([]string{directoryID}, leaf, false, true, false, func(item *drive.File) bool {
+	found, err := f.list(ctx, []string{directoryID}, leaf, false, true, false, func(item *drive.File) bool {
 		if !f.opt.SkipGdocs {
 			extension, exportName, exportMimeType, isDocument = f.findExportFormat(item)
 			if exportName == leaf {
@@ -2306,7 +2405,7 @@ func (o *baseObject) ModTime() time.Time {
 }
 
 // SetModTime sets the modification time of the drive fs object
-func (o *baseObject) SetModTime(modTime time.Time) error {
+func (o *baseObject) SetModTime(ctx context.Context, modTime time.Time) error {
 	// New metadata
 	updateInfo := &drive.File{
 		ModifiedTime: modTime.Format(timeFormatOut),
@@ -2318,7 +2417,7 @@ func (o *baseObject) SetModTime(modTime time.Time) error {
 		info, err = o.fs.svc.Files.Update(o.id, updateInfo).
 			Fields(partialFields).
 			SupportsTeamDrives(o.fs.isTeamDrive).
-			Do()
+			Context(ctx).Do()
 		return shouldRetry(err)
 	})
 	if err != nil {
@@ -2336,11 +2435,11 @@ func (o *baseObject) Storable() bool {
 
 // httpResponse gets an http.Response object for the object
 // using the url and method passed in
-func (o *baseObject) httpResponse(url, method string, options []fs.OpenOption) (req *http.Request, res *http.Response, err error) {
+func (o *baseObject) httpResponse(ctx context.Context, url, method string, options []fs.OpenOption) (req *http.Request, res *http.Response, err error) {
 	if url == "" {
 		return nil, nil, errors.New("forbidden to download - check sharing permission")
 	}
-	req, err = http.NewRequest(method, url, nil)
+	req, err = http.NewRequestWithContext(ctx, method, url, nil)
 	if err != nil {
 		return req, nil, err
 	}
@@ -2410,8 +2509,8 @@ func isGoogleError(err error, what string) bool {
 }
 
 // open a url for reading
-func (o *baseObject) open(url string, options ...fs.OpenOption) (in io.ReadCloser, err error) {
-	_, res, err := o.httpResponse(url, "GET", options)
+func (o *baseObject) open(ctx context.Context, url string, options ...fs.OpenOption) (in io.ReadCloser, err error) {
+	_, res, err := o.httpResponse(ctx, url, "GET", options)
 	if err != nil {
 		if isGoogleError(err, "cannotDownloadAbusiveFile") {
 			if o.fs.opt.AcknowledgeAbuse {
@@ -2422,7 +2521,7 @@ func (o *baseObject) open(url string, options ...fs.OpenOption) (in io.ReadClose
 					url += "?"
 				}
 				url += "acknowledgeAbuse=true"
-				_, res, err = o.httpResponse(url, "GET", options)
+				_, res, err = o.httpResponse(ctx, url, "GET", options)
 			} else {
 				err = errors.Wrap(err, "Use the --drive-acknowledge-abuse flag to download this file")
 			}
@@ -2435,14 +2534,14 @@ func (o *baseObject) open(url string, options ...fs.OpenOption) (in io.ReadClose
 }
 
 // Open an object for read
-func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.ReadCloser, err error) {
 	if o.v2Download {
 		var v2File *drive_v2.File
 		err = o.fs.pacer.Call(func() (bool, error) {
 			v2File, err = o.fs.v2Svc.Files.Get(o.id).
 				Fields("downloadUrl").
 				SupportsTeamDrives(o.fs.isTeamDrive).
-				Do()
+				Context(ctx).Do()
 			return shouldRetry(err)
 		})
 		if err == nil {
@@ -2451,9 +2550,9 @@ func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
 			o.v2Download = false
 		}
 	}
-	return o.baseObject.open(o.url, options...)
+	return o.baseObject.open(ctx, o.url, options...)
 }
-func (o *documentObject) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
+func (o *documentObject) Open(ctx context.Context, options ...fs.OpenOption) (in io.ReadCloser, err error) {
 	// Update the size with what we are reading as it can change from
 	// the HEAD in the listing to this GET. This stops rclone marking
 	// the transfer as corrupted.
@@ -2463,13 +2562,13 @@ func (o *documentObject) Open(options ...fs.OpenOption) (in io.ReadCloser, err e
 			return nil, errors.New("partial downloads are not supported while exporting Google Documents")
 		}
 	}
-	in, err = o.baseObject.open(o.url, options...)
+	in, err = o.baseObject.open(ctx, o.url, options...)
 	if in != nil {
 		in = &openDocumentFile{o: o, in: in}
 	}
 	return
 }
-func (o *linkObject) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
+func (o *linkObject) Open(ctx context.Context, options ...fs.OpenOption) (in io.ReadCloser, err error) {
 	var offset, limit int64 = 0, -1
 	var data = o.content
 	for _, option := range options {
@@ -2495,7 +2594,7 @@ func (o *linkObject) Open(options ...fs.OpenOption) (in io.ReadCloser, err error
 	return ioutil.NopCloser(bytes.NewReader(data)), nil
 }
 
-func (o *baseObject) update(updateInfo *drive.File, uploadMimeType string, in io.Reader,
+func (o *baseObject) update(ctx context.Context, updateInfo *drive.File, uploadMimeType string, in io.Reader,
 	src fs.ObjectInfo) (info *drive.File, err error) {
 	// Make the API request to upload metadata and file data.
 	size := src.Size()
@@ -2507,13 +2606,13 @@ func (o *baseObject) update(updateInfo *drive.File, uploadMimeType string, in io
 				Fields(partialFields).
 				SupportsTeamDrives(o.fs.isTeamDrive).
 				KeepRevisionForever(o.fs.opt.KeepRevisionForever).
-				Do()
+				Context(ctx).Do()
 			return shouldRetry(err)
 		})
 		return
 	}
 	// Upload the file in chunks
-	return o.fs.Upload(in, size, uploadMimeType, o.id, o.remote, updateInfo)
+	return o.fs.Upload(ctx, in, size, uploadMimeType, o.id, o.remote, updateInfo)
 }
 
 // Update the already existing object
@@ -2521,13 +2620,14 @@ func (o *baseObject) update(updateInfo *drive.File, uploadMimeType string, in io
 // Copy the reader into the object updating modTime and size
 //
 // The new object may have been created if an error is returned
-func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
 	srcMimeType := fs.MimeType(src)
 	updateInfo := &drive.File{
 		MimeType:     srcMimeType,
 		ModifiedTime: src.ModTime().Format(timeFormatOut),
+		Properties:   metadataToProperties(options),
 	}
-	info, err := o.baseObject.update(updateInfo, srcMimeType, in, src)
+	info, err := o.baseObject.update(ctx, updateInfo, srcMimeType, in, src)
 	if err != nil {
 		return err
 	}
@@ -2541,12 +2641,13 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 
 	return nil
 }
-func (o *documentObject) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+func (o *documentObject) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
 	srcMimeType := fs.MimeType(src)
 	importMimeType := ""
 	updateInfo := &drive.File{
 		MimeType:     srcMimeType,
 		ModifiedTime: src.ModTime().Format(timeFormatOut),
+		Properties:   metadataToProperties(options),
 	}
 
 	if o.fs.importMimeTypes == nil || o.fs.opt.SkipGdocs {
@@ -2561,7 +2662,7 @@ func (o *documentObject) Update(in io.Reader, src fs.ObjectInfo, options ...fs.O
 	}
 	updateInfo.MimeType = importMimeType
 
-	info, err := o.baseObject.update(updateInfo, srcMimeType, in, src)
+	info, err := o.baseObject.update(ctx, updateInfo, srcMimeType, in, src)
 	if err != nil {
 		return err
 	}
@@ -2580,12 +2681,12 @@ func (o *documentObject) Update(in io.Reader, src fs.ObjectInfo, options ...fs.O
 	return nil
 }
 
-func (o *linkObject) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+func (o *linkObject) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
 	return errors.New("cannot update link files")
 }
 
 // Remove an object
-func (o *baseObject) Remove() error {
+func (o *baseObject) Remove(ctx context.Context) error {
 	var err error
 	err = o.fs.pacer.Call(func() (bool, error) {
 		if o.fs.opt.UseTrash {
@@ -2595,12 +2696,12 @@ func (o *baseObject) Remove() error {
 			_, err = o.fs.svc.Files.Update(o.id, &info).
 				Fields("").
 				SupportsTeamDrives(o.fs.isTeamDrive).
-				Do()
+				Context(ctx).Do()
 		} else {
 			err = o.fs.svc.Files.Delete(o.id).
 				Fields("").
 				SupportsTeamDrives(o.fs.isTeamDrive).
-				Do()
+				Context(ctx).Do()
 		}
 		return shouldRetry(err)
 	})
@@ -2612,6 +2713,52 @@ func (o *baseObject) MimeType() string {
 	return o.mimeType
 }
 
+// Metadata returns metadata for an object
+//
+// This is the custom properties of the file along with its
+// modification time and MIME type.  The properties aren't read when
+// listing so this needs an extra API call.
+func (o *baseObject) Metadata(ctx context.Context) (metadata fs.Metadata, err error) {
+	var info *drive.File
+	err = o.fs.pacer.Call(func() (bool, error) {
+		info, err = o.fs.svc.Files.Get(o.id).
+			Fields("properties").
+			SupportsTeamDrives(o.fs.isTeamDrive).
+			Context(ctx).Do()
+		return shouldRetry(err)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read properties")
+	}
+	metadata = make(fs.Metadata, len(info.Properties)+2)
+	for k, v := range info.Properties {
+		metadata[k] = v
+	}
+	metadata["mtime"] = o.ModTime().Format(time.RFC3339Nano)
+	if o.mimeType != "" {
+		metadata["content-type"] = o.mimeType
+	}
+	return metadata, nil
+}
+
+// metadataToProperties returns the custom properties to set from any
+// metadata passed in the options or nil if there isn't any.
+//
+// The modification time and MIME type are ignored as they are set
+// from the source object already.
+func metadataToProperties(options []fs.OpenOption) (properties map[string]string) {
+	for k, v := range fs.MetadataFromOptions(options) {
+		if k == "mtime" || k == "content-type" {
+			continue
+		}
+		if properties == nil {
+			properties = make(map[string]string)
+		}
+		properties[k] = v
+	}
+	return properties
+}
+
 // ID returns the ID of the Object if known, or "" if not
 func (o *baseObject) ID() string {
 	return o.id
@@ -2673,8 +2820,10 @@ var (
 	_ fs.ListRer         = (*Fs)(nil)
 	_ fs.MergeDirser     = (*Fs)(nil)
 	_ fs.Abouter         = (*Fs)(nil)
+	_ fs.Commander       = (*Fs)(nil)
 	_ fs.Object          = (*Object)(nil)
 	_ fs.MimeTyper       = (*Object)(nil)
+	_ fs.Metadataer      = (*Object)(nil)
 	_ fs.IDer            = (*Object)(nil)
 	_ fs.Object          = (*documentObject)(nil)
 	_ fs.MimeTyper       = (*documentObject)(nil)
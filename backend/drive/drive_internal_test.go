@@ -4,6 +4,7 @@ package drive
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"io/ioutil"
@@ -157,6 +158,7 @@ func TestExtensionsForImportFormats(t *testing.T) {
 }
 
 func (f *Fs) InternalTestDocumentImport(t *testing.T) {
+	ctx := context.Background()
 	oldAllow := f.opt.AllowImportNameChange
 	f.opt.AllowImportNameChange = true
 	defer func() {
@@ -172,11 +174,12 @@ func (f *Fs) InternalTestDocumentImport(t *testing.T) {
 	_, f.importMimeTypes, err = parseExtensions("odt,ods,doc")
 	require.NoError(t, err)
 
-	err = operations.CopyFile(f, testFilesFs, "example2.doc", "example2.doc")
+	err = operations.CopyFile(ctx, f, testFilesFs, "example2.doc", "example2.doc")
 	require.NoError(t, err)
 }
 
 func (f *Fs) InternalTestDocumentUpdate(t *testing.T) {
+	ctx := context.Background()
 	testFilesPath, err := filepath.Abs(filepath.FromSlash("test/files"))
 	require.NoError(t, err)
 
@@ -186,21 +189,22 @@ func (f *Fs) InternalTestDocumentUpdate(t *testing.T) {
 	_, f.importMimeTypes, err = parseExtensions("odt,ods,doc")
 	require.NoError(t, err)
 
-	err = operations.CopyFile(f, testFilesFs, "example2.xlsx", "example1.ods")
+	err = operations.CopyFile(ctx, f, testFilesFs, "example2.xlsx", "example1.ods")
 	require.NoError(t, err)
 }
 
 func (f *Fs) InternalTestDocumentExport(t *testing.T) {
+	ctx := context.Background()
 	var buf bytes.Buffer
 	var err error
 
 	f.exportExtensions, _, err = parseExtensions("txt")
 	require.NoError(t, err)
 
-	obj, err := f.NewObject("example2.txt")
+	obj, err := f.NewObject(ctx, "example2.txt")
 	require.NoError(t, err)
 
-	rc, err := obj.Open()
+	rc, err := obj.Open(ctx)
 	require.NoError(t, err)
 	defer func() { require.NoError(t, rc.Close()) }()
 
@@ -217,16 +221,17 @@ func (f *Fs) InternalTestDocumentExport(t *testing.T) {
 }
 
 func (f *Fs) InternalTestDocumentLink(t *testing.T) {
+	ctx := context.Background()
 	var buf bytes.Buffer
 	var err error
 
 	f.exportExtensions, _, err = parseExtensions("link.html")
 	require.NoError(t, err)
 
-	obj, err := f.NewObject("example2.link.html")
+	obj, err := f.NewObject(ctx, "example2.link.html")
 	require.NoError(t, err)
 
-	rc, err := obj.Open()
+	rc, err := obj.Open(ctx)
 	require.NoError(t, err)
 	defer func() { require.NoError(t, rc.Close()) }()
 
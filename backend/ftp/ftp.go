@@ -2,10 +2,13 @@
 package ftp
 
 import (
+	"context"
+	"crypto/tls"
 	"io"
 	"net/textproto"
 	"os"
 	"path"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,11 +17,18 @@ import (
 	"github.com/ncw/rclone/fs/config/configmap"
 	"github.com/ncw/rclone/fs/config/configstruct"
 	"github.com/ncw/rclone/fs/config/obscure"
+	"github.com/ncw/rclone/fs/encoder"
 	"github.com/ncw/rclone/fs/hash"
 	"github.com/ncw/rclone/lib/readers"
 	"github.com/pkg/errors"
 )
 
+// defaultEnc is the default encoding for FTP
+//
+// Control characters can't be sent over the control connection and
+// many servers strip trailing spaces from names.
+const defaultEnc = encoder.Display | encoder.EncodeRightSpace
+
 // Register with Fs
 func init() {
 	fs.Register(&fs.RegInfo{
@@ -39,12 +49,44 @@ func init() {
 				Help: "FTP username, leave blank for current username, " + os.Getenv("USER"),
 			}, {
 				Name: "port",
-				Help: "FTP port, leave blank to use default (21)",
+				Help: "FTP port, leave blank to use default (21, or 990 with implicit TLS)",
 			}, {
 				Name:       "pass",
 				Help:       "FTP password",
 				IsPassword: true,
 				Required:   true,
+			}, {
+				Name: "tls",
+				Help: `Use FTPS over TLS (Implicit)
+
+When using implicit FTP over TLS the client connects using TLS
+right from the start which breaks compatibility with
+non-TLS-aware servers. This is usually served over port 990 rather
+than port 21. Cannot be used in combination with explicit FTP.`,
+				Default: false,
+			}, {
+				Name: "explicit_tls",
+				Help: `Use FTP over TLS (Explicit)
+
+When using explicit FTP over TLS the client explicitly requests
+security from the server in order to upgrade a plain text connection
+to an encrypted one. Cannot be used in combination with implicit FTP.`,
+				Default: false,
+			}, {
+				Name:     "no_check_certificate",
+				Help:     "Do not verify the TLS certificate of the server",
+				Default:  false,
+				Advanced: true,
+			}, {
+				Name:     "disable_mlsd",
+				Help:     "Disable using MLSD even if server advertises support",
+				Default:  false,
+				Advanced: true,
+			}, {
+				Name:     "encoding",
+				Help:     encoder.OptionHelp,
+				Default:  defaultEnc,
+				Advanced: true,
 			},
 		},
 	})
@@ -52,10 +94,15 @@ func init() {
 
 // Options defines the configuration for this backend
 type Options struct {
-	Host string `config:"host"`
-	User string `config:"user"`
-	Pass string `config:"pass"`
-	Port string `config:"port"`
+	Host              string               `config:"host"`
+	User              string               `config:"user"`
+	Pass              string               `config:"pass"`
+	Port              string               `config:"port"`
+	TLS               bool                 `config:"tls"`
+	ExplicitTLS       bool                 `config:"explicit_tls"`
+	SkipVerifyTLSCert bool                 `config:"no_check_certificate"`
+	DisableMLSD       bool                 `config:"disable_mlsd"`
+	Enc               encoder.MultiEncoder `config:"encoding"`
 }
 
 // Fs represents a remote FTP server
@@ -68,6 +115,8 @@ type Fs struct {
 	user     string
 	pass     string
 	dialAddr string
+	tlsConf  *tls.Config // TLS config if using FTPS or nil
+	mlsd     bool        // set if the server lists directories with MLSD
 	poolMu   sync.Mutex
 	pool     []*ftp.ServerConn
 }
@@ -109,10 +158,62 @@ func (f *Fs) Features() *fs.Features {
 	return f.features
 }
 
+// mlstProbe is used as the debug output of a connection to see
+// whether the server advertises MLST in its reply to FEAT.  The
+// features in the reply are each on a line starting with a space.
+type mlstProbe struct {
+	mu    sync.Mutex
+	done  bool   // set when logged in so nothing more is looked at
+	line  []byte // start of the line being read
+	found bool   // set if MLST was seen
+}
+
+// Write looks through p for the MLST feature
+func (p *mlstProbe) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.done {
+		return len(b), nil
+	}
+	for _, c := range b {
+		if c != '\n' {
+			if len(p.line) < 16 {
+				p.line = append(p.line, c)
+			}
+			continue
+		}
+		feature := strings.Fields(string(p.line))
+		if len(p.line) > 0 && p.line[0] == ' ' && len(feature) > 0 && strings.EqualFold(feature[0], "MLST") {
+			p.found = true
+		}
+		p.line = p.line[:0]
+	}
+	return len(b), nil
+}
+
+// stop looking for MLST returning whether it was found
+func (p *mlstProbe) stop() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done = true
+	p.line = nil
+	return p.found
+}
+
 // Open a new connection to the FTP server.
-func (f *Fs) ftpConnection() (*ftp.ServerConn, error) {
+func (f *Fs) ftpConnection(options ...ftp.DialOption) (*ftp.ServerConn, error) {
 	fs.Debugf(f, "Connecting to FTP server")
-	c, err := ftp.DialTimeout(f.dialAddr, fs.Config.ConnectTimeout)
+	ftpConfig := []ftp.DialOption{
+		ftp.DialWithTimeout(fs.Config.ConnectTimeout),
+		ftp.DialWithDisabledMLSD(f.opt.DisableMLSD),
+	}
+	ftpConfig = append(ftpConfig, options...)
+	if f.opt.TLS {
+		ftpConfig = append(ftpConfig, ftp.DialWithTLS(f.tlsConf))
+	} else if f.opt.ExplicitTLS {
+		ftpConfig = append(ftpConfig, ftp.DialWithExplicitTLS(f.tlsConf))
+	}
+	c, err := ftp.Dial(f.dialAddr, ftpConfig...)
 	if err != nil {
 		fs.Errorf(f, "Error while Dialing %s: %s", f.dialAddr, err)
 		return nil, errors.Wrap(err, "ftpConnection Dial")
@@ -166,8 +267,35 @@ func (f *Fs) putFtpConnection(pc **ftp.ServerConn, err error) {
 	f.poolMu.Unlock()
 }
 
+// makeDialAddr returns the host:port to connect to
+func makeDialAddr(opt *Options) string {
+	port := opt.Port
+	if port == "" {
+		port = "21"
+		if opt.TLS {
+			port = "990"
+		}
+	}
+	return opt.Host + ":" + port
+}
+
+// makeTLSConfig returns the TLS config to use or nil if not using FTPS
+func makeTLSConfig(opt *Options) *tls.Config {
+	if !opt.TLS && !opt.ExplicitTLS {
+		return nil
+	}
+	return &tls.Config{
+		ServerName:         opt.Host,
+		InsecureSkipVerify: opt.SkipVerifyTLSCert,
+		// Many servers insist that the data connections
+		// reuse the TLS session of the control connection
+		ClientSessionCache: tls.NewLRUClientSessionCache(0),
+	}
+}
+
 // NewFs contstructs an Fs from the path, container:path
 func NewFs(name, root string, m configmap.Mapper) (ff fs.Fs, err error) {
+	ctx := context.Background()
 	// defer fs.Trace(nil, "name=%q, root=%q", name, root)("fs=%v, err=%v", &ff, &err)
 	// Parse config into Options struct
 	opt := new(Options)
@@ -183,13 +311,16 @@ func NewFs(name, root string, m configmap.Mapper) (ff fs.Fs, err error) {
 	if user == "" {
 		user = os.Getenv("USER")
 	}
-	port := opt.Port
-	if port == "" {
-		port = "21"
+	if opt.TLS && opt.ExplicitTLS {
+		return nil, errors.New("Implicit TLS and explicit TLS are mutually incompatible. Please revise your config")
 	}
-
-	dialAddr := opt.Host + ":" + port
-	u := "ftp://" + path.Join(dialAddr+"/", root)
+	dialAddr := makeDialAddr(opt)
+	protocol := "ftp://"
+	tlsConfig := makeTLSConfig(opt)
+	if tlsConfig != nil {
+		protocol = "ftps://"
+	}
+	u := protocol + path.Join(dialAddr+"/", root)
 	f := &Fs{
 		name:     name,
 		root:     root,
@@ -198,12 +329,16 @@ func NewFs(name, root string, m configmap.Mapper) (ff fs.Fs, err error) {
 		user:     user,
 		pass:     pass,
 		dialAddr: dialAddr,
+		tlsConf:  tlsConfig,
 	}
 	f.features = (&fs.Features{
 		CanHaveEmptyDirectories: true,
 	}).Fill(f)
-	// Make a connection and pool it to return errors early
-	c, err := f.getFtpConnection()
+	// Make a connection and pool it to return errors early,
+	// finding out whether the server lists with MLSD as we do
+	probe := new(mlstProbe)
+	c, err := f.ftpConnection(ftp.DialWithDebugOutput(probe))
+	f.mlsd = probe.stop() && !opt.DisableMLSD
 	if err != nil {
 		return nil, errors.Wrap(err, "NewFs")
 	}
@@ -215,7 +350,7 @@ func NewFs(name, root string, m configmap.Mapper) (ff fs.Fs, err error) {
 		if f.root == "." {
 			f.root = ""
 		}
-		_, err := f.NewObject(remote)
+		_, err := f.NewObject(ctx, remote)
 		if err != nil {
 			if err == fs.ErrorObjectNotFound || errors.Cause(err) == fs.ErrorNotAFile {
 				// File doesn't exist so return old f
@@ -254,10 +389,15 @@ func translateErrorDir(err error) error {
 	return err
 }
 
+// ftpPath returns the encoded path on the server for remote
+func (f *Fs) ftpPath(remote string) string {
+	return f.opt.Enc.FromStandardPath(path.Join(f.root, remote))
+}
+
 // findItem finds a directory entry for the name in its parent directory
 func (f *Fs) findItem(remote string) (entry *ftp.Entry, err error) {
 	// defer fs.Trace(remote, "")("o=%v, err=%v", &o, &err)
-	fullPath := path.Join(f.root, remote)
+	fullPath := f.ftpPath(remote)
 	dir := path.Dir(fullPath)
 	base := path.Base(fullPath)
 
@@ -280,7 +420,7 @@ func (f *Fs) findItem(remote string) (entry *ftp.Entry, err error) {
 
 // NewObject finds the Object at remote.  If it can't be found
 // it returns the error fs.ErrorObjectNotFound.
-func (f *Fs) NewObject(remote string) (o fs.Object, err error) {
+func (f *Fs) NewObject(ctx context.Context, remote string) (o fs.Object, err error) {
 	// defer fs.Trace(remote, "")("o=%v, err=%v", &o, &err)
 	entry, err := f.findItem(remote)
 	if err != nil {
@@ -324,13 +464,13 @@ func (f *Fs) dirExists(remote string) (exists bool, err error) {
 //
 // This should return ErrDirNotFound if the directory isn't
 // found.
-func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	// defer fs.Trace(dir, "curlevel=%d", curlevel)("")
 	c, err := f.getFtpConnection()
 	if err != nil {
 		return nil, errors.Wrap(err, "list")
 	}
-	files, err := c.List(path.Join(f.root, dir))
+	files, err := c.List(f.ftpPath(dir))
 	f.putFtpConnection(&c, err)
 	if err != nil {
 		return nil, translateErrorDir(err)
@@ -349,12 +489,12 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 	}
 	for i := range files {
 		object := files[i]
-		newremote := path.Join(dir, object.Name)
+		if object.Type == ftp.EntryTypeFolder && (object.Name == "." || object.Name == "..") {
+			continue
+		}
+		newremote := path.Join(dir, f.opt.Enc.ToStandardName(object.Name))
 		switch object.Type {
 		case ftp.EntryTypeFolder:
-			if object.Name == "." || object.Name == ".." {
-				continue
-			}
 			d := fs.NewDir(newremote, object.Time)
 			entries = append(entries, d)
 		default:
@@ -379,8 +519,13 @@ func (f *Fs) Hashes() hash.Set {
 	return 0
 }
 
-// Precision shows Modified Time not supported
+// Precision returns the precision of the modification times
+//
+// These are only known to the second if the server lists with MLSD
 func (f *Fs) Precision() time.Duration {
+	if f.mlsd {
+		return time.Second
+	}
 	return fs.ModTimeNotSupported
 }
 
@@ -389,7 +534,7 @@ func (f *Fs) Precision() time.Duration {
 // May create the object even if it returns an error - if so
 // will return the object and the error, otherwise will return
 // nil and the error
-func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
 	// fs.Debugf(f, "Trying to put file %s", src.Remote())
 	err := f.mkParentDir(src.Remote())
 	if err != nil {
@@ -399,13 +544,13 @@ func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.
 		fs:     f,
 		remote: src.Remote(),
 	}
-	err = o.Update(in, src, options...)
+	err = o.Update(ctx, in, src, options...)
 	return o, err
 }
 
 // PutStream uploads to the remote path with the modTime given of indeterminate size
-func (f *Fs) PutStream(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-	return f.Put(in, src, options...)
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.Put(ctx, in, src, options...)
 }
 
 // getInfo reads the FileInfo for a path
@@ -479,31 +624,31 @@ func (f *Fs) mkdir(abspath string) error {
 // directories above that
 func (f *Fs) mkParentDir(remote string) error {
 	parent := path.Dir(remote)
-	return f.mkdir(path.Join(f.root, parent))
+	return f.mkdir(f.ftpPath(parent))
 }
 
 // Mkdir creates the directory if it doesn't exist
-func (f *Fs) Mkdir(dir string) (err error) {
+func (f *Fs) Mkdir(ctx context.Context, dir string) (err error) {
 	// defer fs.Trace(dir, "")("err=%v", &err)
-	root := path.Join(f.root, dir)
+	root := f.ftpPath(dir)
 	return f.mkdir(root)
 }
 
 // Rmdir removes the directory (container, bucket) if empty
 //
 // Return an error if it doesn't exist or isn't empty
-func (f *Fs) Rmdir(dir string) error {
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
 	c, err := f.getFtpConnection()
 	if err != nil {
 		return errors.Wrap(translateErrorFile(err), "Rmdir")
 	}
-	err = c.RemoveDir(path.Join(f.root, dir))
+	err = c.RemoveDir(f.ftpPath(dir))
 	f.putFtpConnection(&c, err)
 	return translateErrorDir(err)
 }
 
 // Move renames a remote file object
-func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
+func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
 	srcObj, ok := src.(*Object)
 	if !ok {
 		fs.Debugf(src, "Can't move - not same remote type")
@@ -518,14 +663,14 @@ func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
 		return nil, errors.Wrap(err, "Move")
 	}
 	err = c.Rename(
-		path.Join(srcObj.fs.root, srcObj.remote),
-		path.Join(f.root, remote),
+		srcObj.fs.ftpPath(srcObj.remote),
+		f.ftpPath(remote),
 	)
 	f.putFtpConnection(&c, err)
 	if err != nil {
 		return nil, errors.Wrap(err, "Move Rename failed")
 	}
-	dstObj, err := f.NewObject(remote)
+	dstObj, err := f.NewObject(ctx, remote)
 	if err != nil {
 		return nil, errors.Wrap(err, "Move NewObject failed")
 	}
@@ -540,14 +685,14 @@ func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
 // If it isn't possible then return fs.ErrorCantDirMove
 //
 // If destination exists then return fs.ErrorDirExists
-func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
+func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string) error {
 	srcFs, ok := src.(*Fs)
 	if !ok {
 		fs.Debugf(srcFs, "Can't move directory - not same remote type")
 		return fs.ErrorCantDirMove
 	}
-	srcPath := path.Join(srcFs.root, srcRemote)
-	dstPath := path.Join(f.root, dstRemote)
+	srcPath := srcFs.ftpPath(srcRemote)
+	dstPath := f.ftpPath(dstRemote)
 
 	// Check if destination exists
 	fi, err := f.getInfo(dstPath)
@@ -603,7 +748,7 @@ func (o *Object) Remote() string {
 }
 
 // Hash returns the hash of an object returning a lowercase hex string
-func (o *Object) Hash(t hash.Type) (string, error) {
+func (o *Object) Hash(ctx context.Context, t hash.Type) (string, error) {
 	return "", hash.ErrUnsupported
 }
 
@@ -618,7 +763,7 @@ func (o *Object) ModTime() time.Time {
 }
 
 // SetModTime sets the modification time of the object
-func (o *Object) SetModTime(modTime time.Time) error {
+func (o *Object) SetModTime(ctx context.Context, modTime time.Time) error {
 	return nil
 }
 
@@ -665,9 +810,9 @@ func (f *ftpReadCloser) Close() error {
 }
 
 // Open an object for read
-func (o *Object) Open(options ...fs.OpenOption) (rc io.ReadCloser, err error) {
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (rc io.ReadCloser, err error) {
 	// defer fs.Trace(o, "")("rc=%v, err=%v", &rc, &err)
-	path := path.Join(o.fs.root, o.remote)
+	path := o.fs.ftpPath(o.remote)
 	var offset, limit int64 = 0, -1
 	for _, option := range options {
 		switch x := option.(type) {
@@ -699,9 +844,9 @@ func (o *Object) Open(options ...fs.OpenOption) (rc io.ReadCloser, err error) {
 // Copy the reader into the object updating modTime and size
 //
 // The new object may have been created if an error is returned
-func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (err error) {
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (err error) {
 	// defer fs.Trace(o, "src=%v", src)("err=%v", &err)
-	path := path.Join(o.fs.root, o.remote)
+	path := o.fs.ftpPath(o.remote)
 	// remove the file if upload failed
 	remove := func() {
 		// Give the FTP server a chance to get its internal state in order after the error.
@@ -709,7 +854,7 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 		// may still be dealing with it for a moment. A sleep isn't ideal but I haven't been
 		// able to think of a better method to find out if the server has finished - ncw
 		time.Sleep(1 * time.Second)
-		removeErr := o.Remove()
+		removeErr := o.Remove(ctx)
 		if removeErr != nil {
 			fs.Debugf(o, "Failed to remove: %v", removeErr)
 		} else {
@@ -735,16 +880,16 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 }
 
 // Remove an object
-func (o *Object) Remove() (err error) {
+func (o *Object) Remove(ctx context.Context) (err error) {
 	// defer fs.Trace(o, "")("err=%v", &err)
-	path := path.Join(o.fs.root, o.remote)
+	path := o.fs.ftpPath(o.remote)
 	// Check if it's a directory or a file
 	info, err := o.fs.getInfo(path)
 	if err != nil {
 		return err
 	}
 	if info.IsDir {
-		err = o.fs.Rmdir(o.remote)
+		err = o.fs.Rmdir(ctx, o.remote)
 	} else {
 		c, err := o.fs.getFtpConnection()
 		if err != nil {
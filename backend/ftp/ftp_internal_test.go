@@ -0,0 +1,259 @@
+package ftp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/config/configmap"
+	"github.com/ncw/rclone/fs/config/configstruct"
+	"github.com/ncw/rclone/fs/config/obscure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions(t *testing.T) {
+	for _, test := range []struct {
+		m    configmap.Simple
+		want Options
+	}{
+		{
+			m:    configmap.Simple{"host": "example.com"},
+			want: Options{Host: "example.com"},
+		},
+		{
+			m:    configmap.Simple{"host": "example.com", "tls": "true"},
+			want: Options{Host: "example.com", TLS: true},
+		},
+		{
+			m:    configmap.Simple{"host": "example.com", "explicit_tls": "true", "no_check_certificate": "true"},
+			want: Options{Host: "example.com", ExplicitTLS: true, SkipVerifyTLSCert: true},
+		},
+		{
+			m:    configmap.Simple{"host": "example.com", "disable_mlsd": "true"},
+			want: Options{Host: "example.com", DisableMLSD: true},
+		},
+	} {
+		opt := new(Options)
+		err := configstruct.Set(test.m, opt)
+		require.NoError(t, err)
+		assert.Equal(t, test.want, *opt, test.m)
+	}
+}
+
+func TestMakeDialAddr(t *testing.T) {
+	for _, test := range []struct {
+		opt  Options
+		want string
+	}{
+		{Options{Host: "example.com"}, "example.com:21"},
+		{Options{Host: "example.com", TLS: true}, "example.com:990"},
+		{Options{Host: "example.com", ExplicitTLS: true}, "example.com:21"},
+		{Options{Host: "example.com", Port: "2121", TLS: true}, "example.com:2121"},
+	} {
+		assert.Equal(t, test.want, makeDialAddr(&test.opt), test.opt)
+	}
+}
+
+func TestMakeTLSConfig(t *testing.T) {
+	assert.Nil(t, makeTLSConfig(&Options{Host: "example.com"}))
+
+	for _, opt := range []Options{
+		{Host: "example.com", TLS: true},
+		{Host: "example.com", ExplicitTLS: true},
+		{Host: "example.com", TLS: true, SkipVerifyTLSCert: true},
+	} {
+		tlsConfig := makeTLSConfig(&opt)
+		require.NotNil(t, tlsConfig, opt)
+		assert.Equal(t, "example.com", tlsConfig.ServerName)
+		assert.Equal(t, opt.SkipVerifyTLSCert, tlsConfig.InsecureSkipVerify)
+		assert.NotNil(t, tlsConfig.ClientSessionCache)
+	}
+}
+
+func TestNewFsTLSAndExplicitTLS(t *testing.T) {
+	m := configmap.Simple{
+		"host":         "example.com",
+		"pass":         obscure.MustObscure("potato"),
+		"tls":          "true",
+		"explicit_tls": "true",
+	}
+	_, err := NewFs("TestFTP", "", m)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mutually incompatible")
+}
+
+// fakeServer is a minimal FTP server which serves a directory with
+// one file in, listing it with MLSD if mlst is set
+type fakeServer struct {
+	listener net.Listener
+	mlst     bool
+}
+
+// newFakeServer starts a fakeServer returning it and a config for it
+func newFakeServer(t *testing.T, mlst bool) (*fakeServer, configmap.Simple) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeServer{listener: listener, mlst: mlst}
+	go s.serve()
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	return s, configmap.Simple{
+		"host": host,
+		"port": port,
+		"user": "rclone",
+		"pass": obscure.MustObscure("potato"),
+	}
+}
+
+// close stops the server
+func (s *fakeServer) close() {
+	_ = s.listener.Close()
+}
+
+// serve accepts connections until the server is closed
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+// handle serves the commands on one control connection
+func (s *fakeServer) handle(conn net.Conn) {
+	defer func() {
+		_ = conn.Close()
+	}()
+	reply := func(format string, args ...interface{}) {
+		_, _ = fmt.Fprintf(conn, format+"\r\n", args...)
+	}
+	var data net.Listener
+	reply("220 ready")
+	in := bufio.NewScanner(conn)
+	for in.Scan() {
+		command := strings.Fields(in.Text())
+		if len(command) == 0 {
+			continue
+		}
+		switch strings.ToUpper(command[0]) {
+		case "USER":
+			reply("331 password please")
+		case "PASS":
+			reply("230 logged in")
+		case "FEAT":
+			if s.mlst {
+				reply("211-Features:\r\n MLST type*;size*;modify*;\r\n211 End")
+			} else {
+				reply("211-Features:\r\n SIZE\r\n211 End")
+			}
+		case "EPSV":
+			var err error
+			data, err = net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				reply("425 can't open data connection")
+				continue
+			}
+			reply("229 Entering Extended Passive Mode (|||%d|)", data.Addr().(*net.TCPAddr).Port)
+		case "MLSD", "LIST":
+			if data == nil {
+				reply("425 use EPSV first")
+				continue
+			}
+			reply("150 here it comes")
+			dataConn, err := data.Accept()
+			_ = data.Close()
+			data = nil
+			if err != nil {
+				reply("425 data connection failed")
+				continue
+			}
+			if strings.ToUpper(command[0]) == "MLSD" {
+				_, _ = fmt.Fprintf(dataConn, "type=cdir;modify=20200102030405; .\r\n")
+				_, _ = fmt.Fprintf(dataConn, "type=file;size=6;modify=20200102030405; file.txt\r\n")
+			} else {
+				_, _ = fmt.Fprintf(dataConn, "-rw-r--r--   1 ftp ftp    6 Jan 02  2020 file.txt\r\n")
+			}
+			_ = dataConn.Close()
+			reply("226 done")
+		case "QUIT":
+			reply("221 bye")
+			return
+		case "TYPE", "NOOP":
+			reply("200 ok")
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestMLSDModTime(t *testing.T) {
+	ctx := context.Background()
+	modTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, test := range []struct {
+		name        string
+		mlst        bool
+		disableMLSD bool
+		precision   time.Duration
+	}{
+		{"MLSD", true, false, time.Second},
+		{"NoMLSD", false, false, fs.ModTimeNotSupported},
+		{"DisabledMLSD", true, true, fs.ModTimeNotSupported},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			s, m := newFakeServer(t, test.mlst)
+			defer s.close()
+			if test.disableMLSD {
+				m["disable_mlsd"] = "true"
+			}
+			f, err := NewFs("TestFTP", "", m)
+			require.NoError(t, err)
+			assert.Equal(t, test.precision, f.Precision())
+
+			entries, err := f.List(ctx, "")
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+			assert.Equal(t, "file.txt", entries[0].Remote())
+			assert.Equal(t, int64(6), entries[0].Size())
+
+			o, err := f.NewObject(ctx, "file.txt")
+			require.NoError(t, err)
+			if test.precision == time.Second {
+				assert.True(t, modTime.Equal(entries[0].ModTime()), entries[0].ModTime())
+				assert.True(t, modTime.Equal(o.ModTime()), o.ModTime())
+			} else {
+				// LIST only has the date
+				assert.Equal(t, modTime.Truncate(24*time.Hour), o.ModTime().UTC().Truncate(24*time.Hour))
+			}
+		})
+	}
+}
+
+func TestMLSTProbe(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want bool
+	}{
+		{"211-Features:\r\n MLST type*;size*;\r\n211 End\r\n", true},
+		{"211-Features:\r\n mlst\r\n211 End\r\n", true},
+		{"211-Features:\r\n MLSTX\r\n SIZE\r\n211 End\r\n", false},
+		{"211-Features:\r\n SIZE\r\n211 End\r\nMLST\r\n", false},
+	} {
+		p := new(mlstProbe)
+		// feed it a byte at a time to check lines are joined up
+		for i := range test.in {
+			_, err := p.Write([]byte{test.in[i]})
+			require.NoError(t, err)
+		}
+		assert.Equal(t, test.want, p.stop(), test.in)
+		_, _ = p.Write([]byte(" MLST\r\n"))
+		assert.Equal(t, test.want, p.found, "probe should stop after stop()")
+	}
+}
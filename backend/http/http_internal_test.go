@@ -3,6 +3,7 @@
 package http
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -66,7 +67,8 @@ func prepare(t *testing.T) (fs.Fs, func()) {
 }
 
 func testListRoot(t *testing.T, f fs.Fs) {
-	entries, err := f.List("")
+	ctx := context.Background()
+	entries, err := f.List(ctx, "")
 	require.NoError(t, err)
 
 	sort.Sort(entries)
@@ -105,10 +107,11 @@ func TestListRoot(t *testing.T) {
 }
 
 func TestListSubDir(t *testing.T) {
+	ctx := context.Background()
 	f, tidy := prepare(t)
 	defer tidy()
 
-	entries, err := f.List("three")
+	entries, err := f.List(ctx, "three")
 	require.NoError(t, err)
 
 	sort.Sort(entries)
@@ -123,10 +126,11 @@ func TestListSubDir(t *testing.T) {
 }
 
 func TestNewObject(t *testing.T) {
+	ctx := context.Background()
 	f, tidy := prepare(t)
 	defer tidy()
 
-	o, err := f.NewObject("four/under four.txt")
+	o, err := f.NewObject(ctx, "four/under four.txt")
 	require.NoError(t, err)
 
 	assert.Equal(t, "four/under four.txt", o.Remote())
@@ -147,14 +151,15 @@ func TestNewObject(t *testing.T) {
 }
 
 func TestOpen(t *testing.T) {
+	ctx := context.Background()
 	f, tidy := prepare(t)
 	defer tidy()
 
-	o, err := f.NewObject("four/under four.txt")
+	o, err := f.NewObject(ctx, "four/under four.txt")
 	require.NoError(t, err)
 
 	// Test normal read
-	fd, err := o.Open()
+	fd, err := o.Open(ctx)
 	require.NoError(t, err)
 	data, err := ioutil.ReadAll(fd)
 	require.NoError(t, err)
@@ -162,7 +167,7 @@ func TestOpen(t *testing.T) {
 	assert.Equal(t, "beetroot\n", string(data))
 
 	// Test with range request
-	fd, err = o.Open(&fs.RangeOption{Start: 1, End: 5})
+	fd, err = o.Open(ctx, &fs.RangeOption{Start: 1, End: 5})
 	require.NoError(t, err)
 	data, err = ioutil.ReadAll(fd)
 	require.NoError(t, err)
@@ -171,10 +176,11 @@ func TestOpen(t *testing.T) {
 }
 
 func TestMimeType(t *testing.T) {
+	ctx := context.Background()
 	f, tidy := prepare(t)
 	defer tidy()
 
-	o, err := f.NewObject("four/under four.txt")
+	o, err := f.NewObject(ctx, "four/under four.txt")
 	require.NoError(t, err)
 
 	do, ok := o.(fs.MimeTyper)
@@ -193,13 +199,14 @@ func TestIsAFileRoot(t *testing.T) {
 }
 
 func TestIsAFileSubDir(t *testing.T) {
+	ctx := context.Background()
 	m, tidy := prepareServer(t)
 	defer tidy()
 
 	f, err := NewFs(remoteName, "three/underthree.txt", m)
 	assert.Equal(t, err, fs.ErrorIsFile)
 
-	entries, err := f.List("")
+	entries, err := f.List(ctx, "")
 	require.NoError(t, err)
 
 	sort.Sort(entries)
@@ -60,6 +60,9 @@ func init() {
 				log.Fatalf("Failed to configure token: %v", err)
 			}
 		},
+		ConfigState: func(name string, m configmap.Mapper, in fs.ConfigIn) (*fs.ConfigOut, error) {
+			return oauthutil.ConfigState("hubic", name, m, in, oauthConfig)
+		},
 		Options: append([]fs.Option{{
 			Name: config.ConfigClientID,
 			Help: "Hubic Client Id\nLeave blank normally.",
@@ -148,7 +151,7 @@ func (f *Fs) getCredentials() (err error) {
 
 // NewFs constructs an Fs from the path, container:path
 func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
-	client, _, err := oauthutil.NewClient(name, m, oauthConfig)
+	client, _, err := oauthutil.NewClientWithBaseClient(name, m, oauthConfig, fshttp.NewClientForBackend(fs.Config, "hubic"))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to configure Hubic")
 	}
@@ -162,7 +165,7 @@ func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
 		Auth:           newAuth(f),
 		ConnectTimeout: 10 * fs.Config.ConnectTimeout, // Use the timeouts in the transport
 		Timeout:        10 * fs.Config.Timeout,        // Use the timeouts in the transport
-		Transport:      fshttp.NewTransport(fs.Config),
+		Transport:      fshttp.NewTransportForBackend(fs.Config, "hubic"),
 	}
 	err = c.Authenticate()
 	if err != nil {
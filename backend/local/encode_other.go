@@ -0,0 +1,10 @@
+//+build !windows
+
+package local
+
+import "github.com/ncw/rclone/fs/encoder"
+
+// defaultEnc is the default encoding for local file names
+//
+// Unix file names can contain anything other than / and NUL.
+const defaultEnc = encoder.Base
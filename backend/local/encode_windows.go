@@ -0,0 +1,18 @@
+//+build windows
+
+package local
+
+import "github.com/ncw/rclone/fs/encoder"
+
+// defaultEnc is the default encoding for local file names
+//
+// Windows can't use the characters in encoder.EncodeWin, control
+// characters or \ in file names, and strips trailing spaces and
+// periods.
+const defaultEnc = encoder.Base |
+	encoder.EncodeWin |
+	encoder.EncodeBackSlash |
+	encoder.EncodeCtl |
+	encoder.EncodeRightSpace |
+	encoder.EncodeRightPeriod |
+	encoder.EncodeInvalidUtf8
@@ -2,6 +2,7 @@
 package local
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -19,6 +20,7 @@ import (
 	"github.com/ncw/rclone/fs/accounting"
 	"github.com/ncw/rclone/fs/config/configmap"
 	"github.com/ncw/rclone/fs/config/configstruct"
+	"github.com/ncw/rclone/fs/encoder"
 	"github.com/ncw/rclone/fs/fserrors"
 	"github.com/ncw/rclone/fs/hash"
 	"github.com/ncw/rclone/lib/readers"
@@ -34,6 +36,7 @@ func init() {
 		Name:        "local",
 		Description: "Local Disk",
 		NewFs:       NewFs,
+		CommandHelp: commandHelp,
 		Options: []fs.Option{{
 			Name: "nounc",
 			Help: "Disable UNC (long path names) conversion on Windows",
@@ -85,6 +88,11 @@ check can be disabled with this flag.`,
 			NoPrefix: true,
 			ShortOpt: "x",
 			Advanced: true,
+		}, {
+			Name:     "encoding",
+			Help:     encoder.OptionHelp,
+			Default:  defaultEnc,
+			Advanced: true,
 		}},
 	}
 	fs.Register(fsi)
@@ -92,12 +100,13 @@ check can be disabled with this flag.`,
 
 // Options defines the configuration for this backend
 type Options struct {
-	FollowSymlinks bool `config:"copy_links"`
-	SkipSymlinks   bool `config:"skip_links"`
-	NoUTFNorm      bool `config:"no_unicode_normalization"`
-	NoCheckUpdated bool `config:"no_check_updated"`
-	NoUNC          bool `config:"nounc"`
-	OneFileSystem  bool `config:"one_file_system"`
+	FollowSymlinks bool                 `config:"copy_links"`
+	SkipSymlinks   bool                 `config:"skip_links"`
+	NoUTFNorm      bool                 `config:"no_unicode_normalization"`
+	NoCheckUpdated bool                 `config:"no_check_updated"`
+	NoUNC          bool                 `config:"nounc"`
+	OneFileSystem  bool                 `config:"one_file_system"`
+	Enc            encoder.MultiEncoder `config:"encoding"`
 }
 
 // Fs represents a local filesystem rooted at root
@@ -155,6 +164,8 @@ func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
 	f.features = (&fs.Features{
 		CaseInsensitive:         f.caseInsensitive(),
 		CanHaveEmptyDirectories: true,
+		ReadMetadata:            true,
+		WriteMetadata:           true,
 	}).Fill(f)
 	if opt.FollowSymlinks {
 		f.lstat = os.Stat
@@ -209,7 +220,7 @@ func (f *Fs) caseInsensitive() bool {
 // if dstPath is empty then it is made from remote
 func (f *Fs) newObject(remote, dstPath string) *Object {
 	if dstPath == "" {
-		dstPath = f.cleanPath(filepath.Join(f.root, remote))
+		dstPath = f.localPath(remote)
 	}
 	remote = f.cleanRemote(remote)
 	return &Object{
@@ -246,7 +257,7 @@ func (f *Fs) newObjectWithInfo(remote, dstPath string, info os.FileInfo) (fs.Obj
 
 // NewObject finds the Object at remote.  If it can't be found
 // it returns the error ErrorObjectNotFound.
-func (f *Fs) NewObject(remote string) (fs.Object, error) {
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
 	return f.newObjectWithInfo(remote, "", nil)
 }
 
@@ -259,10 +270,9 @@ func (f *Fs) NewObject(remote string) (fs.Object, error) {
 //
 // This should return ErrDirNotFound if the directory isn't
 // found.
-func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
-	dir = f.dirNames.Load(dir)
-	fsDirPath := f.cleanPath(filepath.Join(f.root, dir))
-	remote := f.cleanRemote(dir)
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	fsDirPath := f.localPath(dir)
+	_, dirMapped := f.dirNames.Load(dir)
 	_, err = os.Stat(fsDirPath)
 	if err != nil {
 		return nil, fs.ErrorDirNotFound
@@ -291,7 +301,11 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 		for _, fi := range fis {
 			name := fi.Name()
 			mode := fi.Mode()
-			newRemote := path.Join(remote, name)
+			leaf := f.cleanRemote(f.opt.Enc.ToStandardName(name))
+			newRemote := leaf
+			if dir != "" {
+				newRemote = dir + "/" + leaf
+			}
 			newPath := filepath.Join(fsDirPath, name)
 			// Follow symlinks if required
 			if f.opt.FollowSymlinks && (mode&os.ModeSymlink) != 0 {
@@ -312,7 +326,10 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 				// Ignore directories which are symlinks.  These are junction points under windows which
 				// are kind of a souped up symlink. Unix doesn't have directories which are symlinks.
 				if (mode&os.ModeSymlink) == 0 && f.dev == readDevice(fi, f.opt.OneFileSystem) {
-					d := fs.NewDir(f.dirNames.Save(newRemote, f.cleanRemote(newRemote)), fi.ModTime())
+					if dirMapped || f.opt.Enc.FromStandardName(leaf) != name {
+						f.dirNames.Save(newRemote, newPath)
+					}
+					d := fs.NewDir(newRemote, fi.ModTime())
 					entries = append(entries, d)
 				}
 			} else {
@@ -347,10 +364,24 @@ func (f *Fs) cleanRemote(name string) string {
 	return name
 }
 
-// mapper maps raw to cleaned directory names
+// localPath returns the OS path for the remote passed in
+//
+// The remote is encoded unless it is inside a directory whose OS name
+// couldn't be recreated from its remote name.
+func (f *Fs) localPath(remote string) string {
+	for dir := remote; dir != "" && dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if osPath, ok := f.dirNames.Load(dir); ok {
+			rest := strings.TrimPrefix(strings.TrimPrefix(remote, dir), "/")
+			return filepath.Join(osPath, f.opt.Enc.FromStandardPath(rest))
+		}
+	}
+	return f.cleanPath(filepath.Join(f.root, f.opt.Enc.FromStandardPath(remote)))
+}
+
+// mapper maps cleaned directory names to the OS paths they were read from
 type mapper struct {
 	mu sync.RWMutex      // mutex to protect the below
-	m  map[string]string // map of un-normalised directory names
+	m  map[string]string // map of remote to OS path
 }
 
 func newMapper() *mapper {
@@ -359,38 +390,33 @@ func newMapper() *mapper {
 	}
 }
 
-// Lookup a directory name to make a local name (reverses
-// cleanDirName)
+// Load looks up the OS path for a directory remote, returning false
+// if it wasn't recorded
 //
 // FIXME this is temporary before we make a proper Directory object
-func (m *mapper) Load(in string) string {
+func (m *mapper) Load(remote string) (osPath string, ok bool) {
 	m.mu.RLock()
-	out, ok := m.m[in]
+	osPath, ok = m.m[remote]
 	m.mu.RUnlock()
-	if ok {
-		return out
-	}
-	return in
+	return osPath, ok
 }
 
-// Cleans a directory name recording if it needed to be altered
+// Save records the OS path for a directory remote whose name was
+// altered by cleaning or decoding
 //
 // FIXME this is temporary before we make a proper Directory object
-func (m *mapper) Save(in, out string) string {
-	if in != out {
-		m.mu.Lock()
-		m.m[out] = in
-		m.mu.Unlock()
-	}
-	return out
+func (m *mapper) Save(remote, osPath string) {
+	m.mu.Lock()
+	m.m[remote] = osPath
+	m.mu.Unlock()
 }
 
 // Put the Object to the local filesystem
-func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
 	remote := src.Remote()
 	// Temporary Object under construction - info filled in by Update()
 	o := f.newObject(remote, "")
-	err := o.Update(in, src, options...)
+	err := o.Update(ctx, in, src, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -398,14 +424,14 @@ func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.
 }
 
 // PutStream uploads to the remote path with the modTime given of indeterminate size
-func (f *Fs) PutStream(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-	return f.Put(in, src, options...)
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.Put(ctx, in, src, options...)
 }
 
 // Mkdir creates the directory if it doesn't exist
-func (f *Fs) Mkdir(dir string) error {
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
 	// FIXME: https://github.com/syncthing/syncthing/blob/master/lib/osutil/mkdirall_windows.go
-	root := f.cleanPath(filepath.Join(f.root, dir))
+	root := f.localPath(dir)
 	err := os.MkdirAll(root, 0777)
 	if err != nil {
 		return err
@@ -423,8 +449,8 @@ func (f *Fs) Mkdir(dir string) error {
 // Rmdir removes the directory
 //
 // If it isn't empty it will return an error
-func (f *Fs) Rmdir(dir string) error {
-	root := f.cleanPath(filepath.Join(f.root, dir))
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
+	root := f.localPath(dir)
 	return os.Remove(root)
 }
 
@@ -493,7 +519,7 @@ func (f *Fs) readPrecision() (precision time.Duration) {
 // Optional interface: Only implement this if you have a way of
 // deleting all the files quicker than just running Remove() on the
 // result of List()
-func (f *Fs) Purge() error {
+func (f *Fs) Purge(ctx context.Context) error {
 	fi, err := f.lstat(f.root)
 	if err != nil {
 		return err
@@ -513,7 +539,7 @@ func (f *Fs) Purge() error {
 // Will only be called if src.Fs().Name() == f.Name()
 //
 // If it isn't possible then return fs.ErrorCantMove
-func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
+func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
 	srcObj, ok := src.(*Object)
 	if !ok {
 		fs.Debugf(src, "Can't move - not same remote type")
@@ -572,14 +598,14 @@ func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
 // If it isn't possible then return fs.ErrorCantDirMove
 //
 // If destination exists then return fs.ErrorDirExists
-func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
+func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string) error {
 	srcFs, ok := src.(*Fs)
 	if !ok {
 		fs.Debugf(srcFs, "Can't move directory - not same remote type")
 		return fs.ErrorCantDirMove
 	}
-	srcPath := f.cleanPath(filepath.Join(srcFs.root, srcRemote))
-	dstPath := f.cleanPath(filepath.Join(f.root, dstRemote))
+	srcPath := srcFs.localPath(srcRemote)
+	dstPath := f.localPath(dstRemote)
 
 	// Check if destination exists
 	_, err := os.Lstat(dstPath)
@@ -611,6 +637,50 @@ func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
 	return nil
 }
 
+var commandHelp = []fs.CommandHelp{
+	{
+		Name:  "noop",
+		Short: "A null operation for testing backend commands",
+		Long: `This is a test command which has some options
+you can try to change the output.`,
+		Opts: map[string]string{
+			"echo":  "echo the input arguments",
+			"error": "return an error based on option value",
+		},
+	},
+}
+
+// Command the backend to run a named command
+//
+// The command run is name
+// args may be used to read arguments from
+// opts may be used to read optional arguments from
+//
+// The result should be capable of being JSON encoded
+// If it is a string or a []string it will be shown to the user
+// otherwise it will be JSON encoded and shown to the user like that
+func (f *Fs) Command(ctx context.Context, name string, arg []string, opt map[string]string) (interface{}, error) {
+	switch name {
+	case "noop":
+		if txt, ok := opt["error"]; ok {
+			if txt == "" {
+				txt = "unspecified error"
+			}
+			return nil, errors.New(txt)
+		}
+		if _, ok := opt["echo"]; ok {
+			out := map[string]interface{}{}
+			out["name"] = name
+			out["arg"] = arg
+			out["opt"] = opt
+			return out, nil
+		}
+		return nil, nil
+	default:
+		return nil, fs.ErrorCommandNotFound
+	}
+}
+
 // Hashes returns the supported hash sets.
 func (f *Fs) Hashes() hash.Set {
 	return hash.Supported
@@ -637,7 +707,7 @@ func (o *Object) Remote() string {
 }
 
 // Hash returns the requested hash of a file as a lowercase hex string
-func (o *Object) Hash(r hash.Type) (string, error) {
+func (o *Object) Hash(ctx context.Context, r hash.Type) (string, error) {
 	// Check that the underlying file hasn't changed
 	oldtime := o.modTime
 	oldsize := o.size
@@ -681,7 +751,7 @@ func (o *Object) ModTime() time.Time {
 }
 
 // SetModTime sets the modification time of the local fs object
-func (o *Object) SetModTime(modTime time.Time) error {
+func (o *Object) SetModTime(ctx context.Context, modTime time.Time) error {
 	err := os.Chtimes(o.path, modTime, modTime)
 	if err != nil {
 		return err
@@ -762,7 +832,7 @@ func (file *localOpenFile) Close() (err error) {
 }
 
 // Open an object for read
-func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.ReadCloser, err error) {
 	var offset, limit int64 = 0, -1
 	hashes := hash.Supported
 	for _, option := range options {
@@ -812,7 +882,7 @@ func (o *Object) mkdirAll() error {
 }
 
 // Update the object from in with modTime and size
-func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
 	hashes := hash.Supported
 	for _, option := range options {
 		switch x := option.(type) {
@@ -863,15 +933,49 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 	o.fs.objectHashesMu.Unlock()
 
 	// Set the mtime
-	err = o.SetModTime(src.ModTime())
+	err = o.SetModTime(ctx, src.ModTime())
 	if err != nil {
 		return err
 	}
 
+	// Set the metadata if it was passed in
+	if metadata := fs.MetadataFromOptions(options); metadata != nil {
+		err = o.writeMetadata(metadata)
+		if err != nil {
+			return err
+		}
+	}
+
 	// ReRead info now that we have finished
 	return o.lstat()
 }
 
+// OpenWriterAt opens with a handle for random access writes
+//
+// Pass in the remote desired and the size if known.
+//
+// It truncates any existing object
+func (f *Fs) OpenWriterAt(ctx context.Context, remote string, size int64) (fs.WriterAtCloser, error) {
+	// Temporary Object under construction
+	o := f.newObject(remote, "")
+
+	err := o.mkdirAll()
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := os.OpenFile(o.path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return nil, err
+	}
+	// Pre-allocate the file for performance reasons
+	err = preAllocate(size, out)
+	if err != nil {
+		fs.Debugf(o, "Failed to pre-allocate: %v", err)
+	}
+	return out, nil
+}
+
 // setMetadata sets the file info from the os.FileInfo passed in
 func (o *Object) setMetadata(info os.FileInfo) {
 	// Don't overwrite the info if we don't need to
@@ -897,7 +1001,7 @@ func (o *Object) lstat() error {
 }
 
 // Remove an object
-func (o *Object) Remove() error {
+func (o *Object) Remove(ctx context.Context) error {
 	return remove(o.path)
 }
 
@@ -1013,10 +1117,13 @@ func cleanWindowsName(f *Fs, name string) string {
 
 // Check the interfaces are satisfied
 var (
-	_ fs.Fs          = &Fs{}
-	_ fs.Purger      = &Fs{}
-	_ fs.PutStreamer = &Fs{}
-	_ fs.Mover       = &Fs{}
-	_ fs.DirMover    = &Fs{}
-	_ fs.Object      = &Object{}
+	_ fs.Fs             = &Fs{}
+	_ fs.Purger         = &Fs{}
+	_ fs.PutStreamer    = &Fs{}
+	_ fs.Mover          = &Fs{}
+	_ fs.DirMover       = &Fs{}
+	_ fs.OpenWriterAter = &Fs{}
+	_ fs.Commander      = &Fs{}
+	_ fs.Object         = &Object{}
+	_ fs.Metadataer     = &Object{}
 )
@@ -1,12 +1,20 @@
 package local
 
 import (
+	"bytes"
+	"context"
+	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/encoder"
 	"github.com/ncw/rclone/fs/hash"
+	"github.com/ncw/rclone/fs/object"
 	"github.com/ncw/rclone/fstest"
 	"github.com/ncw/rclone/lib/readers"
 	"github.com/stretchr/testify/assert"
@@ -21,14 +29,43 @@ func TestMain(m *testing.M) {
 func TestMapper(t *testing.T) {
 	m := newMapper()
 	assert.Equal(t, m.m, map[string]string{})
-	assert.Equal(t, "potato", m.Save("potato", "potato"))
-	assert.Equal(t, m.m, map[string]string{})
-	assert.Equal(t, "-r'áö", m.Save("-r?'a´o¨", "-r'áö"))
+	_, ok := m.Load("potato")
+	assert.False(t, ok)
+	m.Save("-r'áö", "/tmp/-r?'a´o¨")
 	assert.Equal(t, m.m, map[string]string{
-		"-r'áö": "-r?'a´o¨",
+		"-r'áö": "/tmp/-r?'a´o¨",
 	})
-	assert.Equal(t, "potato", m.Load("potato"))
-	assert.Equal(t, "-r?'a´o¨", m.Load("-r'áö"))
+	osPath, ok := m.Load("-r'áö")
+	assert.True(t, ok)
+	assert.Equal(t, "/tmp/-r?'a´o¨", osPath)
+}
+
+// Test the file names are encoded and decoded
+func TestEncoding(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	f := r.Flocal.(*Fs)
+	f.opt.Enc = encoder.Base | encoder.EncodeColon | encoder.EncodeRightSpace
+
+	// A file made outside rclone is decoded
+	err := ioutil.WriteFile(filepath.Join(r.LocalName, "a：b␠"), []byte("content"), 0666)
+	require.NoError(t, err)
+	entries, err := f.List(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(entries))
+	assert.Equal(t, "a:b ", entries[0].Remote())
+	_, err = f.NewObject(ctx, "a:b ")
+	require.NoError(t, err)
+
+	// A file made by rclone is encoded
+	r.WriteObjectTo(f, "dir:/c:d ", "content", time.Now(), false)
+	_, err = os.Stat(filepath.Join(r.LocalName, "dir：", "c：d␠"))
+	require.NoError(t, err)
+	entries, err = f.List(ctx, "dir:")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(entries))
+	assert.Equal(t, "dir:/c:d ", entries[0].Remote())
 }
 
 // Test copy with source file that's updating
@@ -72,3 +109,61 @@ func TestUpdatingCheck(t *testing.T) {
 	require.NoError(t, err)
 
 }
+
+func TestMetadata(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	f := r.Flocal.(*Fs)
+	const content = "metadata file contents"
+	when := time.Now()
+
+	// Read the metadata from a file
+	r.WriteFile("file1", content, when)
+	err := os.Chmod(filepath.Join(r.LocalName, "file1"), 0640)
+	require.NoError(t, err)
+	o, err := f.NewObject(ctx, "file1")
+	require.NoError(t, err)
+	metadata, err := o.(fs.Metadataer).Metadata(ctx)
+	require.NoError(t, err)
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, "640", metadata["mode"])
+	}
+	assert.NotEqual(t, "", metadata["mtime"])
+
+	// Write the metadata to a file
+	src := object.NewStaticObjectInfo("file2", when, int64(len(content)), true, nil, nil)
+	in := bytes.NewBufferString(content)
+	o, err = f.Put(ctx, in, src, &fs.MetadataOption{Metadata: fs.Metadata{
+		"mode":   "600",
+		"potato": "jersey royal",
+	}})
+	require.NoError(t, err)
+	metadata, err = o.(fs.Metadataer).Metadata(ctx)
+	require.NoError(t, err)
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, "600", metadata["mode"])
+	}
+	if value, ok := metadata["potato"]; ok {
+		assert.Equal(t, "jersey royal", value)
+	} else {
+		t.Log("extended attributes not supported")
+	}
+}
+
+// Test the option values in a connection string don't appear in the
+// name of the Fs
+func TestConnectionStringName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-local-test")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	f, err := fs.NewFs(":local,no_check_updated=true,copy_links:" + dir)
+	require.NoError(t, err)
+	assert.NotContains(t, f.Name(), "true")
+	assert.NotContains(t, f.Name(), "copy_links")
+	assert.True(t, f.(*Fs).opt.NoCheckUpdated)
+	assert.True(t, f.(*Fs).opt.FollowSymlinks)
+}
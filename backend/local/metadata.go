@@ -0,0 +1,83 @@
+// Object metadata
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
+)
+
+// Metadata keys which are stored natively in the filesystem rather
+// than as extended attributes
+const (
+	metadataMode  = "mode"
+	metadataMtime = "mtime"
+	metadataUID   = "uid"
+	metadataGID   = "gid"
+)
+
+// isSystemMetadata returns true if k is one of the keys stored
+// natively in the filesystem
+func isSystemMetadata(k string) bool {
+	switch k {
+	case metadataMode, metadataMtime, metadataUID, metadataGID:
+		return true
+	}
+	return false
+}
+
+// Metadata returns metadata for an object
+//
+// This reads the permission bits and modification time, plus the
+// owner and the user extended attributes on platforms which support
+// them.
+func (o *Object) Metadata(ctx context.Context) (metadata fs.Metadata, err error) {
+	info, err := o.fs.lstat(o.path)
+	if err != nil {
+		return nil, err
+	}
+	metadata = fs.Metadata{
+		metadataMode:  fmt.Sprintf("%o", info.Mode().Perm()),
+		metadataMtime: info.ModTime().Format(time.RFC3339Nano),
+	}
+	readOwner(info, metadata)
+	err = o.readXattrs(metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read extended attributes")
+	}
+	return metadata, nil
+}
+
+// writeMetadata sets the metadata passed in on the file
+//
+// The modification time is ignored as it is set by SetModTime.  The
+// mode is set last as it may remove the permission to write the
+// extended attributes.
+func (o *Object) writeMetadata(metadata fs.Metadata) (err error) {
+	err = o.writeXattrs(metadata)
+	if err != nil {
+		return errors.Wrap(err, "failed to set extended attributes")
+	}
+	err = o.writeOwner(metadata)
+	if err != nil {
+		return errors.Wrap(err, "failed to set owner")
+	}
+	if mode, ok := metadata[metadataMode]; ok {
+		perm, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil {
+			fs.Debugf(o, "Ignoring invalid mode %q: %v", mode, err)
+			return nil
+		}
+		err = os.Chmod(o.path, os.FileMode(perm)&os.ModePerm)
+		if err != nil {
+			return errors.Wrap(err, "failed to set mode")
+		}
+	}
+	return nil
+}
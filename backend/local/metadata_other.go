@@ -0,0 +1,21 @@
+// Owner metadata
+
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package local
+
+import (
+	"os"
+
+	"github.com/ncw/rclone/fs"
+)
+
+// readOwner reads the uid and gid of the file into metadata
+func readOwner(info os.FileInfo, metadata fs.Metadata) {
+}
+
+// writeOwner sets the uid and gid of the file from metadata if both
+// are present
+func (o *Object) writeOwner(metadata fs.Metadata) error {
+	return nil
+}
@@ -0,0 +1,52 @@
+// Owner metadata
+
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package local
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+
+	"github.com/ncw/rclone/fs"
+)
+
+// readOwner reads the uid and gid of the file into metadata
+func readOwner(info os.FileInfo, metadata fs.Metadata) {
+	statT, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return
+	}
+	metadata[metadataUID] = strconv.FormatUint(uint64(statT.Uid), 10)
+	metadata[metadataGID] = strconv.FormatUint(uint64(statT.Gid), 10)
+}
+
+// writeOwner sets the uid and gid of the file from metadata if both
+// are present
+//
+// Not having permission to change the owner isn't an error as only
+// root can do this on most systems.
+func (o *Object) writeOwner(metadata fs.Metadata) error {
+	uidString, okUID := metadata[metadataUID]
+	gidString, okGID := metadata[metadataGID]
+	if !okUID || !okGID {
+		return nil
+	}
+	uid, err := strconv.Atoi(uidString)
+	if err != nil {
+		fs.Debugf(o, "Ignoring invalid uid %q: %v", uidString, err)
+		return nil
+	}
+	gid, err := strconv.Atoi(gidString)
+	if err != nil {
+		fs.Debugf(o, "Ignoring invalid gid %q: %v", gidString, err)
+		return nil
+	}
+	err = os.Lchown(o.path, uid, gid)
+	if os.IsPermission(err) {
+		fs.Debugf(o, "Not setting owner: %v", err)
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,82 @@
+// Extended attribute metadata
+
+//+build linux
+
+package local
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/ncw/rclone/fs"
+	"golang.org/x/sys/unix"
+)
+
+// Only extended attributes in this namespace are read and written
+const xattrPrefix = "user."
+
+// xattrIsNotSupported returns true if err shows the filesystem
+// doesn't support extended attributes
+func xattrIsNotSupported(err error) bool {
+	return err == unix.ENOTSUP || err == unix.EOPNOTSUPP
+}
+
+// readXattrs reads the user extended attributes of the file into
+// metadata with the "user." prefix removed
+func (o *Object) readXattrs(metadata fs.Metadata) error {
+	size, err := unix.Listxattr(o.path, nil)
+	if err != nil {
+		if xattrIsNotSupported(err) {
+			return nil
+		}
+		return err
+	}
+	if size == 0 {
+		return nil
+	}
+	buf := make([]byte, size)
+	size, err = unix.Listxattr(o.path, buf)
+	if err != nil {
+		return err
+	}
+	for _, name := range bytes.Split(buf[:size], []byte{0}) {
+		attr := string(name)
+		if !strings.HasPrefix(attr, xattrPrefix) {
+			continue
+		}
+		k := attr[len(xattrPrefix):]
+		if k == "" || isSystemMetadata(k) {
+			continue
+		}
+		valueSize, err := unix.Getxattr(o.path, attr, nil)
+		if err != nil {
+			return err
+		}
+		value := make([]byte, valueSize)
+		valueSize, err = unix.Getxattr(o.path, attr, value)
+		if err != nil {
+			return err
+		}
+		metadata[k] = string(value[:valueSize])
+	}
+	return nil
+}
+
+// writeXattrs stores every metadata key which isn't stored natively
+// as a user extended attribute
+func (o *Object) writeXattrs(metadata fs.Metadata) error {
+	for k, v := range metadata {
+		if isSystemMetadata(k) {
+			continue
+		}
+		err := unix.Setxattr(o.path, xattrPrefix+k, []byte(v), 0)
+		if err != nil {
+			if xattrIsNotSupported(err) {
+				fs.Debugf(o, "Not setting extended attributes: %v", err)
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
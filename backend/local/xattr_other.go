@@ -0,0 +1,18 @@
+// Extended attribute metadata
+
+//+build !linux
+
+package local
+
+import "github.com/ncw/rclone/fs"
+
+// readXattrs reads the user extended attributes of the file into
+// metadata
+func (o *Object) readXattrs(metadata fs.Metadata) error {
+	return nil
+}
+
+// writeXattrs stores the metadata as user extended attributes
+func (o *Object) writeXattrs(metadata fs.Metadata) error {
+	return nil
+}
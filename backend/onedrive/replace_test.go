@@ -18,13 +18,13 @@ func TestReplace(t *testing.T) {
 		{"~leading tilde/~leading tilde/~leading tilde", "～leading tilde/～leading tilde/～leading tilde"},
 		{"trailing dot./trailing dot./trailing dot.", "trailing dot．/trailing dot．/trailing dot．"},
 	} {
-		got := replaceReservedChars(test.in)
+		got := defaultEnc.FromStandardPath(test.in)
 		if got != test.out {
-			t.Errorf("replaceReservedChars(%q) want %q got %q", test.in, test.out, got)
+			t.Errorf("FromStandardPath(%q) want %q got %q", test.in, test.out, got)
 		}
-		got2 := restoreReservedChars(got)
+		got2 := defaultEnc.ToStandardPath(got)
 		if got2 != test.in {
-			t.Errorf("restoreReservedChars(%q) want %q got %q", got, test.in, got2)
+			t.Errorf("ToStandardPath(%q) want %q got %q", got, test.in, got2)
 		}
 	}
 }
@@ -14,6 +14,7 @@ What happens if you CTRL-C a multipart upload
 */
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
@@ -21,6 +22,7 @@ import (
 	"net/http"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -42,6 +44,7 @@ import (
 	"github.com/ncw/rclone/fs/fserrors"
 	"github.com/ncw/rclone/fs/fshttp"
 	"github.com/ncw/rclone/fs/hash"
+	"github.com/ncw/rclone/fs/operations"
 	"github.com/ncw/rclone/fs/walk"
 	"github.com/ncw/rclone/lib/pacer"
 	"github.com/ncw/rclone/lib/rest"
@@ -55,6 +58,7 @@ func init() {
 		Name:        "s3",
 		Description: "Amazon S3 Compliant Storage Providers (AWS, Ceph, Dreamhost, IBM COS, Minio)",
 		NewFs:       NewFs,
+		CommandHelp: commandHelp,
 		Options: []fs.Option{{
 			Name: fs.ConfigProvider,
 			Help: "Choose your S3 provider.",
@@ -669,15 +673,19 @@ type Fs struct {
 type Object struct {
 	// Will definitely have everything but meta which may be nil
 	//
-	// List will read everything but meta & mimeType - to fill
-	// that in you need to call readMetaData
-	fs           *Fs                // what this object is part of
-	remote       string             // The remote path
-	etag         string             // md5sum of the object
-	bytes        int64              // size of the object
-	lastModified time.Time          // Last modified
-	meta         map[string]*string // The object metadata if known - may be nil
-	mimeType     string             // MimeType of object - may be ""
+	// List will read everything but meta, mimeType & headers - to
+	// fill that in you need to call readMetaData
+	fs                 *Fs                // what this object is part of
+	remote             string             // The remote path
+	etag               string             // md5sum of the object
+	bytes              int64              // size of the object
+	lastModified       time.Time          // Last modified
+	meta               map[string]*string // The object metadata if known - may be nil
+	mimeType           string             // MimeType of object - may be ""
+	cacheControl       string             // Cache-Control of object - may be ""
+	contentDisposition string             // Content-Disposition of object - may be ""
+	contentEncoding    string             // Content-Encoding of object - may be ""
+	contentLanguage    string             // Content-Language of object - may be ""
 }
 
 // ------------------------------------------------------------
@@ -817,7 +825,7 @@ func s3Connection(opt *Options) (*s3.S3, *session.Session, error) {
 		WithMaxRetries(maxRetries).
 		WithCredentials(cred).
 		WithEndpoint(opt.Endpoint).
-		WithHTTPClient(fshttp.NewClient(fs.Config)).
+		WithHTTPClient(fshttp.NewClientForBackend(fs.Config, "s3")).
 		WithS3ForcePathStyle(opt.ForcePathStyle)
 	// awsConfig.WithLogLevel(aws.LogDebugWithSigning)
 	awsSessionOpts := session.Options{
@@ -913,11 +921,13 @@ func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
 		bucket: bucket,
 		ses:    ses,
 		pacer:  pacer.New().SetMinSleep(minSleep).SetPacer(pacer.S3Pacer),
-		srv:    fshttp.NewClient(fs.Config),
+		srv:    fshttp.NewClientForBackend(fs.Config, "s3"),
 	}
 	f.features = (&fs.Features{
 		ReadMimeType:  true,
 		WriteMimeType: true,
+		ReadMetadata:  true,
+		WriteMetadata: true,
 		BucketBased:   true,
 	}).Fill(f)
 	if f.root != "" {
@@ -949,7 +959,7 @@ func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
 // Return an Object from a path
 //
 //If it can't be found it returns the error ErrorObjectNotFound.
-func (f *Fs) newObjectWithInfo(remote string, info *s3.Object) (fs.Object, error) {
+func (f *Fs) newObjectWithInfo(ctx context.Context, remote string, info *s3.Object) (fs.Object, error) {
 	o := &Object{
 		fs:     f,
 		remote: remote,
@@ -965,7 +975,7 @@ func (f *Fs) newObjectWithInfo(remote string, info *s3.Object) (fs.Object, error
 		o.etag = aws.StringValue(info.ETag)
 		o.bytes = aws.Int64Value(info.Size)
 	} else {
-		err := o.readMetaData() // reads info and meta, returning an error
+		err := o.readMetaData(ctx) // reads info and meta, returning an error
 		if err != nil {
 			return nil, err
 		}
@@ -975,8 +985,8 @@ func (f *Fs) newObjectWithInfo(remote string, info *s3.Object) (fs.Object, error
 
 // NewObject finds the Object at remote.  If it can't be found
 // it returns the error fs.ErrorObjectNotFound.
-func (f *Fs) NewObject(remote string) (fs.Object, error) {
-	return f.newObjectWithInfo(remote, nil)
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	return f.newObjectWithInfo(ctx, remote, nil)
 }
 
 // listFn is called from list to handle an object.
@@ -987,7 +997,7 @@ type listFn func(remote string, object *s3.Object, isDirectory bool) error
 // dir is the starting directory, "" for root
 //
 // Set recurse to read sub directories
-func (f *Fs) list(dir string, recurse bool, fn listFn) error {
+func (f *Fs) list(ctx context.Context, dir string, recurse bool, fn listFn) error {
 	root := f.root
 	if dir != "" {
 		root += dir + "/"
@@ -1010,7 +1020,7 @@ func (f *Fs) list(dir string, recurse bool, fn listFn) error {
 		var resp *s3.ListObjectsOutput
 		var err error
 		err = f.pacer.Call(func() (bool, error) {
-			resp, err = f.c.ListObjects(&req)
+			resp, err = f.c.ListObjectsWithContext(ctx, &req)
 			return shouldRetry(err)
 		})
 		if err != nil {
@@ -1084,7 +1094,7 @@ func (f *Fs) list(dir string, recurse bool, fn listFn) error {
 }
 
 // Convert a list item into a DirEntry
-func (f *Fs) itemToDirEntry(remote string, object *s3.Object, isDirectory bool) (fs.DirEntry, error) {
+func (f *Fs) itemToDirEntry(ctx context.Context, remote string, object *s3.Object, isDirectory bool) (fs.DirEntry, error) {
 	if isDirectory {
 		size := int64(0)
 		if object.Size != nil {
@@ -1093,7 +1103,7 @@ func (f *Fs) itemToDirEntry(remote string, object *s3.Object, isDirectory bool)
 		d := fs.NewDir(remote, time.Time{}).SetSize(size)
 		return d, nil
 	}
-	o, err := f.newObjectWithInfo(remote, object)
+	o, err := f.newObjectWithInfo(ctx, remote, object)
 	if err != nil {
 		return nil, err
 	}
@@ -1111,10 +1121,10 @@ func (f *Fs) markBucketOK() {
 }
 
 // listDir lists files and directories to out
-func (f *Fs) listDir(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) listDir(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	// List the objects and directories
-	err = f.list(dir, false, func(remote string, object *s3.Object, isDirectory bool) error {
-		entry, err := f.itemToDirEntry(remote, object, isDirectory)
+	err = f.list(ctx, dir, false, func(remote string, object *s3.Object, isDirectory bool) error {
+		entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
 		if err != nil {
 			return err
 		}
@@ -1132,14 +1142,14 @@ func (f *Fs) listDir(dir string) (entries fs.DirEntries, err error) {
 }
 
 // listBuckets lists the buckets to out
-func (f *Fs) listBuckets(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) listBuckets(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	if dir != "" {
 		return nil, fs.ErrorListBucketRequired
 	}
 	req := s3.ListBucketsInput{}
 	var resp *s3.ListBucketsOutput
 	err = f.pacer.Call(func() (bool, error) {
-		resp, err = f.c.ListBuckets(&req)
+		resp, err = f.c.ListBucketsWithContext(ctx, &req)
 		return shouldRetry(err)
 	})
 	if err != nil {
@@ -1161,11 +1171,11 @@ func (f *Fs) listBuckets(dir string) (entries fs.DirEntries, err error) {
 //
 // This should return ErrDirNotFound if the directory isn't
 // found.
-func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
 	if f.bucket == "" {
-		return f.listBuckets(dir)
+		return f.listBuckets(ctx, dir)
 	}
-	return f.listDir(dir)
+	return f.listDir(ctx, dir)
 }
 
 // ListR lists the objects and directories of the Fs starting
@@ -1184,13 +1194,13 @@ func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
 //
 // Don't implement this unless you have a more efficient way
 // of listing recursively that doing a directory traversal.
-func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
+func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
 	if f.bucket == "" {
 		return fs.ErrorListBucketRequired
 	}
 	list := walk.NewListRHelper(callback)
-	err = f.list(dir, true, func(remote string, object *s3.Object, isDirectory bool) error {
-		entry, err := f.itemToDirEntry(remote, object, isDirectory)
+	err = f.list(ctx, dir, true, func(remote string, object *s3.Object, isDirectory bool) error {
+		entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
 		if err != nil {
 			return err
 		}
@@ -1205,29 +1215,29 @@ func (f *Fs) ListR(dir string, callback fs.ListRCallback) (err error) {
 }
 
 // Put the Object into the bucket
-func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
 	// Temporary Object under construction
 	fs := &Object{
 		fs:     f,
 		remote: src.Remote(),
 	}
-	return fs, fs.Update(in, src, options...)
+	return fs, fs.Update(ctx, in, src, options...)
 }
 
 // PutStream uploads to the remote path with the modTime given of indeterminate size
-func (f *Fs) PutStream(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-	return f.Put(in, src, options...)
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return f.Put(ctx, in, src, options...)
 }
 
 // Check if the bucket exists
 //
 // NB this can return incorrect results if called immediately after bucket deletion
-func (f *Fs) dirExists() (bool, error) {
+func (f *Fs) dirExists(ctx context.Context) (bool, error) {
 	req := s3.HeadBucketInput{
 		Bucket: &f.bucket,
 	}
 	err := f.pacer.Call(func() (bool, error) {
-		_, err := f.c.HeadBucket(&req)
+		_, err := f.c.HeadBucketWithContext(ctx, &req)
 		return shouldRetry(err)
 	})
 	if err == nil {
@@ -1242,14 +1252,14 @@ func (f *Fs) dirExists() (bool, error) {
 }
 
 // Mkdir creates the bucket if it doesn't exist
-func (f *Fs) Mkdir(dir string) error {
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
 	f.bucketOKMu.Lock()
 	defer f.bucketOKMu.Unlock()
 	if f.bucketOK {
 		return nil
 	}
 	if !f.bucketDeleted {
-		exists, err := f.dirExists()
+		exists, err := f.dirExists(ctx)
 		if err == nil {
 			f.bucketOK = exists
 		}
@@ -1267,7 +1277,7 @@ func (f *Fs) Mkdir(dir string) error {
 		}
 	}
 	err := f.pacer.Call(func() (bool, error) {
-		_, err := f.c.CreateBucket(&req)
+		_, err := f.c.CreateBucketWithContext(ctx, &req)
 		return shouldRetry(err)
 	})
 	if err, ok := err.(awserr.Error); ok {
@@ -1285,7 +1295,7 @@ func (f *Fs) Mkdir(dir string) error {
 // Rmdir deletes the bucket if the fs is at the root
 //
 // Returns an error if it isn't empty
-func (f *Fs) Rmdir(dir string) error {
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
 	f.bucketOKMu.Lock()
 	defer f.bucketOKMu.Unlock()
 	if f.root != "" || dir != "" {
@@ -1295,7 +1305,7 @@ func (f *Fs) Rmdir(dir string) error {
 		Bucket: &f.bucket,
 	}
 	err := f.pacer.Call(func() (bool, error) {
-		_, err := f.c.DeleteBucket(&req)
+		_, err := f.c.DeleteBucketWithContext(ctx, &req)
 		return shouldRetry(err)
 	})
 	if err == nil {
@@ -1325,8 +1335,8 @@ func pathEscape(s string) string {
 // Will only be called if src.Fs().Name() == f.Name()
 //
 // If it isn't possible then return fs.ErrorCantCopy
-func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
-	err := f.Mkdir("")
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	err := f.Mkdir(ctx, "")
 	if err != nil {
 		return nil, err
 	}
@@ -1355,13 +1365,133 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 		req.StorageClass = &f.opt.StorageClass
 	}
 	err = f.pacer.Call(func() (bool, error) {
-		_, err = f.c.CopyObject(&req)
+		_, err = f.c.CopyObjectWithContext(ctx, &req)
 		return shouldRetry(err)
 	})
 	if err != nil {
 		return nil, err
 	}
-	return f.NewObject(remote)
+	return f.NewObject(ctx, remote)
+}
+
+var commandHelp = []fs.CommandHelp{{
+	Name:  "restore",
+	Short: "Restore objects from GLACIER to normal storage",
+	Long: `This command can be used to restore one or more objects from GLACIER
+to normal storage.
+
+Usage Examples:
+
+    rclone backend restore s3:bucket/path/to/object [-o priority=PRIORITY] [-o lifetime=DAYS]
+    rclone backend restore s3:bucket/path/to/directory [-o priority=PRIORITY] [-o lifetime=DAYS]
+    rclone backend restore s3:bucket [-o priority=PRIORITY] [-o lifetime=DAYS]
+
+This command also obeys the filters. Test first with the --dry-run flag
+
+    rclone --dry-run backend restore --include "*.txt" s3:bucket/path -o priority=Standard
+
+All the objects shown will be marked for restore, then
+
+    rclone backend restore --include "*.txt" s3:bucket/path -o priority=Standard
+
+It returns a list of status dictionaries with Remote and Status
+keys. The Status will be OK if it was successful or an error message
+if not.
+
+    [
+        {
+            "Status": "OK",
+            "Remote": "test.txt"
+        },
+        {
+            "Status": "OK",
+            "Remote": "test/file4.txt"
+        }
+    ]
+`,
+	Opts: map[string]string{
+		"priority":    "Priority of restore: Standard|Expedited|Bulk",
+		"lifetime":    "Lifetime of the active copy in days",
+		"description": "The optional description for the job.",
+	},
+}}
+
+// restoreStatus is the result of restoring a single object
+type restoreStatus struct {
+	Status string
+	Remote string
+}
+
+// Command the backend to run a named command
+//
+// The command run is name
+// args may be used to read arguments from
+// opts may be used to read optional arguments from
+//
+// The result should be capable of being JSON encoded
+// If it is a string or a []string it will be shown to the user
+// otherwise it will be JSON encoded and shown to the user like that
+func (f *Fs) Command(ctx context.Context, name string, arg []string, opt map[string]string) (interface{}, error) {
+	switch name {
+	case "restore":
+		req := s3.RestoreObjectInput{
+			Bucket:         &f.bucket,
+			RestoreRequest: &s3.RestoreRequest{},
+		}
+		if lifetime := opt["lifetime"]; lifetime != "" {
+			days, err := strconv.ParseInt(lifetime, 10, 64)
+			if err != nil {
+				return nil, errors.Wrap(err, "bad lifetime")
+			}
+			req.RestoreRequest.Days = &days
+		}
+		if priority := opt["priority"]; priority != "" {
+			req.RestoreRequest.GlacierJobParameters = &s3.GlacierJobParameters{
+				Tier: &priority,
+			}
+		}
+		if description := opt["description"]; description != "" {
+			req.RestoreRequest.Description = &description
+		}
+		var (
+			outMu sync.Mutex
+			out   = []restoreStatus{}
+		)
+		err := operations.ListFn(ctx, f, func(obj fs.Object) {
+			st := restoreStatus{Status: "OK", Remote: obj.Remote()}
+			defer func() {
+				outMu.Lock()
+				out = append(out, st)
+				outMu.Unlock()
+			}()
+			if fs.Config.DryRun {
+				fs.Logf(obj, "Not restoring as --dry-run")
+				st.Status = "Not restored as --dry-run"
+				return
+			}
+			o, ok := obj.(*Object)
+			if !ok {
+				st.Status = "Not an S3 object"
+				return
+			}
+			key := f.root + o.remote
+			reqCopy := req
+			reqCopy.Key = &key
+			err := f.pacer.Call(func() (bool, error) {
+				_, err := f.c.RestoreObjectWithContext(ctx, &reqCopy)
+				return shouldRetry(err)
+			})
+			if err != nil {
+				st.Status = err.Error()
+			}
+		})
+		if err != nil {
+			return out, err
+		}
+		return out, nil
+	default:
+		return nil, fs.ErrorCommandNotFound
+	}
 }
 
 // Hashes returns the supported hash sets.
@@ -1392,14 +1522,14 @@ func (o *Object) Remote() string {
 var matchMd5 = regexp.MustCompile(`^[0-9a-f]{32}$`)
 
 // Hash returns the Md5sum of an object returning a lowercase hex string
-func (o *Object) Hash(t hash.Type) (string, error) {
+func (o *Object) Hash(ctx context.Context, t hash.Type) (string, error) {
 	if t != hash.MD5 {
 		return "", hash.ErrUnsupported
 	}
 	hash := strings.Trim(strings.ToLower(o.etag), `"`)
 	// Check the etag is a valid md5sum
 	if !matchMd5.MatchString(hash) {
-		err := o.readMetaData()
+		err := o.readMetaData(ctx)
 		if err != nil {
 			return "", err
 		}
@@ -1425,7 +1555,7 @@ func (o *Object) Size() int64 {
 // readMetaData gets the metadata if it hasn't already been fetched
 //
 // it also sets the info
-func (o *Object) readMetaData() (err error) {
+func (o *Object) readMetaData(ctx context.Context) (err error) {
 	if o.meta != nil {
 		return nil
 	}
@@ -1437,7 +1567,7 @@ func (o *Object) readMetaData() (err error) {
 	var resp *s3.HeadObjectOutput
 	err = o.fs.pacer.Call(func() (bool, error) {
 		var err error
-		resp, err = o.fs.c.HeadObject(&req)
+		resp, err = o.fs.c.HeadObjectWithContext(ctx, &req)
 		return shouldRetry(err)
 	})
 	if err != nil {
@@ -1464,6 +1594,10 @@ func (o *Object) readMetaData() (err error) {
 		o.lastModified = *resp.LastModified
 	}
 	o.mimeType = aws.StringValue(resp.ContentType)
+	o.cacheControl = aws.StringValue(resp.CacheControl)
+	o.contentDisposition = aws.StringValue(resp.ContentDisposition)
+	o.contentEncoding = aws.StringValue(resp.ContentEncoding)
+	o.contentLanguage = aws.StringValue(resp.ContentLanguage)
 	return nil
 }
 
@@ -1475,7 +1609,7 @@ func (o *Object) ModTime() time.Time {
 	if fs.Config.UseServerModTime {
 		return o.lastModified
 	}
-	err := o.readMetaData()
+	err := o.readMetaData(context.TODO())
 	if err != nil {
 		fs.Logf(o, "Failed to read metadata: %v", err)
 		return time.Now()
@@ -1495,8 +1629,8 @@ func (o *Object) ModTime() time.Time {
 }
 
 // SetModTime sets the modification time of the local fs object
-func (o *Object) SetModTime(modTime time.Time) error {
-	err := o.readMetaData()
+func (o *Object) SetModTime(ctx context.Context, modTime time.Time) error {
+	err := o.readMetaData(ctx)
 	if err != nil {
 		return err
 	}
@@ -1533,7 +1667,7 @@ func (o *Object) SetModTime(modTime time.Time) error {
 		req.StorageClass = &o.fs.opt.StorageClass
 	}
 	err = o.fs.pacer.Call(func() (bool, error) {
-		_, err := o.fs.c.CopyObject(&req)
+		_, err := o.fs.c.CopyObjectWithContext(ctx, &req)
 		return shouldRetry(err)
 	})
 	return err
@@ -1545,7 +1679,7 @@ func (o *Object) Storable() bool {
 }
 
 // Open an object for read
-func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
+func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.ReadCloser, err error) {
 	key := o.fs.root + o.remote
 	req := s3.GetObjectInput{
 		Bucket: &o.fs.bucket,
@@ -1565,7 +1699,7 @@ func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
 	var resp *s3.GetObjectOutput
 	err = o.fs.pacer.Call(func() (bool, error) {
 		var err error
-		resp, err = o.fs.c.GetObject(&req)
+		resp, err = o.fs.c.GetObjectWithContext(ctx, &req)
 		return shouldRetry(err)
 	})
 	if err, ok := err.(awserr.RequestFailure); ok {
@@ -1580,8 +1714,8 @@ func (o *Object) Open(options ...fs.OpenOption) (in io.ReadCloser, err error) {
 }
 
 // Update the Object from in with modTime and size
-func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
-	err := o.fs.Mkdir("")
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	err := o.fs.Mkdir(ctx, "")
 	if err != nil {
 		return err
 	}
@@ -1616,11 +1750,35 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 		metaMtime: aws.String(swift.TimeToFloatString(modTime)),
 	}
 
+	// Guess the content type
+	mimeType := fs.MimeType(src)
+
+	// Set the user metadata and headers if passed in
+	var cacheControl, contentDisposition, contentEncoding, contentLanguage *string
+	for k, v := range fs.MetadataFromOptions(options) {
+		switch k {
+		case "mtime", strings.ToLower(metaMtime), strings.ToLower(metaMD5Hash):
+			// set by rclone
+		case "content-type":
+			mimeType = v
+		case "cache-control":
+			cacheControl = aws.String(v)
+		case "content-disposition":
+			contentDisposition = aws.String(v)
+		case "content-encoding":
+			contentEncoding = aws.String(v)
+		case "content-language":
+			contentLanguage = aws.String(v)
+		default:
+			metadata[k] = aws.String(v)
+		}
+	}
+
 	// read the md5sum if available for non multpart and if
 	// disable checksum isn't present.
 	var md5sum string
 	if !multipart || !o.fs.opt.DisableChecksum {
-		hash, err := src.Hash(hash.MD5)
+		hash, err := src.Hash(ctx, hash.MD5)
 		if err == nil && matchMd5.MatchString(hash) {
 			hashBytes, err := hex.DecodeString(hash)
 			if err == nil {
@@ -1632,18 +1790,19 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 		}
 	}
 
-	// Guess the content type
-	mimeType := fs.MimeType(src)
-
 	key := o.fs.root + o.remote
 	if multipart {
 		req := s3manager.UploadInput{
-			Bucket:      &o.fs.bucket,
-			ACL:         &o.fs.opt.ACL,
-			Key:         &key,
-			Body:        in,
-			ContentType: &mimeType,
-			Metadata:    metadata,
+			Bucket:             &o.fs.bucket,
+			ACL:                &o.fs.opt.ACL,
+			Key:                &key,
+			Body:               in,
+			ContentType:        &mimeType,
+			CacheControl:       cacheControl,
+			ContentDisposition: contentDisposition,
+			ContentEncoding:    contentEncoding,
+			ContentLanguage:    contentLanguage,
+			Metadata:           metadata,
 			//ContentLength: &size,
 		}
 		if o.fs.opt.ServerSideEncryption != "" {
@@ -1656,7 +1815,7 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 			req.StorageClass = &o.fs.opt.StorageClass
 		}
 		err = o.fs.pacer.CallNoRetry(func() (bool, error) {
-			_, err = uploader.Upload(&req)
+			_, err = uploader.UploadWithContext(ctx, &req)
 			return shouldRetry(err)
 		})
 		if err != nil {
@@ -1664,11 +1823,15 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 		}
 	} else {
 		req := s3.PutObjectInput{
-			Bucket:      &o.fs.bucket,
-			ACL:         &o.fs.opt.ACL,
-			Key:         &key,
-			ContentType: &mimeType,
-			Metadata:    metadata,
+			Bucket:             &o.fs.bucket,
+			ACL:                &o.fs.opt.ACL,
+			Key:                &key,
+			ContentType:        &mimeType,
+			CacheControl:       cacheControl,
+			ContentDisposition: contentDisposition,
+			ContentEncoding:    contentEncoding,
+			ContentLanguage:    contentLanguage,
+			Metadata:           metadata,
 		}
 		if md5sum != "" {
 			req.ContentMD5 = &md5sum
@@ -1701,7 +1864,7 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 		}
 
 		// create the vanilla http request
-		httpReq, err := http.NewRequest("PUT", url, in)
+		httpReq, err := http.NewRequestWithContext(ctx, "PUT", url, in)
 		if err != nil {
 			return errors.Wrap(err, "s3 upload: new request")
 		}
@@ -1732,19 +1895,19 @@ func (o *Object) Update(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOptio
 
 	// Read the metadata from the newly created object
 	o.meta = nil // wipe old metadata
-	err = o.readMetaData()
+	err = o.readMetaData(ctx)
 	return err
 }
 
 // Remove an object
-func (o *Object) Remove() error {
+func (o *Object) Remove(ctx context.Context) error {
 	key := o.fs.root + o.remote
 	req := s3.DeleteObjectInput{
 		Bucket: &o.fs.bucket,
 		Key:    &key,
 	}
 	err := o.fs.pacer.Call(func() (bool, error) {
-		_, err := o.fs.c.DeleteObject(&req)
+		_, err := o.fs.c.DeleteObjectWithContext(ctx, &req)
 		return shouldRetry(err)
 	})
 	return err
@@ -1752,7 +1915,7 @@ func (o *Object) Remove() error {
 
 // MimeType of an Object if known, "" otherwise
 func (o *Object) MimeType() string {
-	err := o.readMetaData()
+	err := o.readMetaData(context.TODO())
 	if err != nil {
 		fs.Logf(o, "Failed to read metadata: %v", err)
 		return ""
@@ -1760,12 +1923,46 @@ func (o *Object) MimeType() string {
 	return o.mimeType
 }
 
+// Metadata returns metadata for an object
+//
+// This is the user metadata with the keys in lower case, the
+// modification time and any of the standard headers which are set.
+func (o *Object) Metadata(ctx context.Context) (metadata fs.Metadata, err error) {
+	err = o.readMetaData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	metadata = make(fs.Metadata, len(o.meta)+6)
+	for k, v := range o.meta {
+		k = strings.ToLower(k)
+		if k == strings.ToLower(metaMtime) || k == strings.ToLower(metaMD5Hash) {
+			continue
+		}
+		metadata[k] = aws.StringValue(v)
+	}
+	metadata["mtime"] = o.ModTime().Format(time.RFC3339Nano)
+	for k, v := range map[string]string{
+		"content-type":        o.mimeType,
+		"cache-control":       o.cacheControl,
+		"content-disposition": o.contentDisposition,
+		"content-encoding":    o.contentEncoding,
+		"content-language":    o.contentLanguage,
+	} {
+		if v != "" {
+			metadata[k] = v
+		}
+	}
+	return metadata, nil
+}
+
 // Check the interfaces are satisfied
 var (
 	_ fs.Fs          = &Fs{}
 	_ fs.Copier      = &Fs{}
 	_ fs.PutStreamer = &Fs{}
 	_ fs.ListRer     = &Fs{}
+	_ fs.Commander   = &Fs{}
 	_ fs.Object      = &Object{}
+	_ fs.Metadataer  = &Object{}
 	_ fs.MimeTyper   = &Object{}
 )
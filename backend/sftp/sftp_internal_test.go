@@ -3,10 +3,27 @@
 package sftp
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 
+	"github.com/ncw/rclone/fs/config/configmap"
+	"github.com/ncw/rclone/fs/config/obscure"
+	"github.com/pkg/sftp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
 )
 
 func TestShellEscape(t *testing.T) {
@@ -35,3 +52,230 @@ func TestParseHash(t *testing.T) {
 		assert.Equal(t, test.checksum, got, fmt.Sprintf("Test %d sshOutput = %q", i, test.sshOutput))
 	}
 }
+
+const testPassword = "potato"
+
+// newTestKey makes a new RSA key for testing
+func newTestKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+// newTestSigner makes an ssh.Signer from key
+func newTestSigner(t *testing.T, key *rsa.PrivateKey) ssh.Signer {
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+	return signer
+}
+
+// startTestServer starts an in process SFTP server with the host key
+// passed in which accepts testPassword and the user key passed in.
+//
+// It returns the address of the server and a function to stop it.
+func startTestServer(t *testing.T, hostKey ssh.Signer, userKey ssh.PublicKey) (host, port string, stop func()) {
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if string(pass) == testPassword {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("bad password for %q", c.User())
+		},
+		PublicKeyCallback: func(c ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if userKey != nil && string(key.Marshal()) == string(userKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("unknown public key for %q", c.User())
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestConn(conn, config)
+		}
+	}()
+	host, port, err = net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	return host, port, func() { _ = listener.Close() }
+}
+
+// serveTestConn serves the sftp subsystem on a single connection
+func serveTestConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range requests {
+				isSftp := req.Type == "subsystem" && len(req.Payload) > 4 && string(req.Payload[4:]) == "sftp"
+				_ = req.Reply(isSftp, nil)
+				if isSftp {
+					server, err := sftp.NewServer(channel)
+					if err == nil {
+						_ = server.Serve()
+					}
+					_ = channel.Close()
+				}
+			}
+		}()
+	}
+}
+
+// makeTempDir makes a temporary directory returning it and a function
+// to remove it
+func makeTempDir(t *testing.T) (dir string, remove func()) {
+	dir, err := ioutil.TempDir("", "rclone-sftp-test")
+	require.NoError(t, err)
+	return dir, func() { _ = os.RemoveAll(dir) }
+}
+
+// writeKnownHosts writes a known_hosts file in dir containing key for
+// the host and port
+func writeKnownHosts(t *testing.T, dir, host, port string, key ssh.PublicKey) string {
+	line := knownhosts.Line([]string{knownhosts.Normalize(net.JoinHostPort(host, port))}, key)
+	knownHostsFile := filepath.Join(dir, "known_hosts")
+	err := ioutil.WriteFile(knownHostsFile, []byte(line+"\n"), 0600)
+	require.NoError(t, err)
+	return knownHostsFile
+}
+
+func TestKnownHosts(t *testing.T) {
+	hostKey := newTestSigner(t, newTestKey(t))
+	host, port, stop := startTestServer(t, hostKey, nil)
+	defer stop()
+	dir, remove := makeTempDir(t)
+	defer remove()
+	m := configmap.Simple{
+		"host": host,
+		"port": port,
+		"user": "test",
+		"pass": obscure.MustObscure(testPassword),
+	}
+
+	// No known_hosts file means any host key is accepted
+	_, err := NewFs("test", "", m)
+	require.NoError(t, err)
+
+	// The right host key is accepted
+	m["known_hosts_file"] = writeKnownHosts(t, dir, host, port, hostKey.PublicKey())
+	_, err = NewFs("test", "", m)
+	require.NoError(t, err)
+
+	// A different host key is rejected
+	otherKey := newTestSigner(t, newTestKey(t))
+	m["known_hosts_file"] = writeKnownHosts(t, dir, host, port, otherKey.PublicKey())
+	_, err = NewFs("test", "", m)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "host key mismatch")
+
+	// An unknown host is rejected
+	m["known_hosts_file"] = writeKnownHosts(t, dir, "example.com", "22", hostKey.PublicKey())
+	_, err = NewFs("test", "", m)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no host key")
+}
+
+func TestKeyPem(t *testing.T) {
+	hostKey := newTestSigner(t, newTestKey(t))
+	userKey := newTestKey(t)
+	host, port, stop := startTestServer(t, hostKey, newTestSigner(t, userKey).PublicKey())
+	defer stop()
+	dir, remove := makeTempDir(t)
+	defer remove()
+
+	// Unencrypted key with newlines escaped
+	keyPem := string(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(userKey),
+	}))
+	m := configmap.Simple{
+		"host":    host,
+		"port":    port,
+		"user":    "test",
+		"key_pem": strings.Replace(keyPem, "\n", `\n`, -1),
+	}
+	_, err := NewFs("test", "", m)
+	require.NoError(t, err)
+
+	// Encrypted key in a file
+	block, err := x509.EncryptPEMBlock(rand.Reader, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(userKey), []byte("secret"), x509.PEMCipherAES256)
+	require.NoError(t, err)
+	keyFile := filepath.Join(dir, "id_rsa")
+	err = ioutil.WriteFile(keyFile, pem.EncodeToMemory(block), 0600)
+	require.NoError(t, err)
+	m = configmap.Simple{
+		"host":          host,
+		"port":          port,
+		"user":          "test",
+		"key_file":      keyFile,
+		"key_file_pass": obscure.MustObscure("secret"),
+	}
+	_, err = NewFs("test", "", m)
+	require.NoError(t, err)
+
+	// Wrong passphrase
+	m["key_file_pass"] = obscure.MustObscure("wrong")
+	_, err = NewFs("test", "", m)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse private key file")
+}
+
+func TestAgent(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("ssh-agent test needs unix sockets")
+	}
+	hostKey := newTestSigner(t, newTestKey(t))
+	userKey := newTestKey(t)
+	host, port, stop := startTestServer(t, hostKey, newTestSigner(t, userKey).PublicKey())
+	defer stop()
+	dir, remove := makeTempDir(t)
+	defer remove()
+
+	// Start an ssh agent holding the user key
+	keyring := agent.NewKeyring()
+	require.NoError(t, keyring.Add(agent.AddedKey{PrivateKey: userKey}))
+	socket := filepath.Join(dir, "agent.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_ = agent.ServeAgent(keyring, conn)
+				_ = conn.Close()
+			}()
+		}
+	}()
+	oldSocket := os.Getenv("SSH_AUTH_SOCK")
+	defer func() { _ = os.Setenv("SSH_AUTH_SOCK", oldSocket) }()
+	require.NoError(t, os.Setenv("SSH_AUTH_SOCK", socket))
+
+	m := configmap.Simple{
+		"host": host,
+		"port": port,
+		"user": "test",
+	}
+	_, err = NewFs("test", "", m)
+	require.NoError(t, err)
+}
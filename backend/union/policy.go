@@ -0,0 +1,190 @@
+package union
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
+)
+
+// picker chooses from the candidate upstreams for an operation.  It
+// is never called with no candidates.
+type picker func(ctx context.Context, upstreams []*upstream) []*upstream
+
+// policy decides which upstreams an operation applies to.  These
+// are modelled on the policies of mergerfs.
+//
+// Operations which change files (action) or read them (search) only
+// consider the upstreams the file is in.  Operations which make new
+// files (create) consider all the upstreams, or only those with the
+// parent directory already if the policy is path preserving.
+type policy struct {
+	pathPreserving bool   // create only where the parent directory exists
+	pick           picker // choose from the candidates
+}
+
+// policies by name
+var policies = map[string]*policy{
+	"all":    {false, pickAll},
+	"epall":  {true, pickAll},
+	"ff":     {false, pickFirst},
+	"epff":   {true, pickFirst},
+	"mfs":    {false, pickMostFree},
+	"epmfs":  {true, pickMostFree},
+	"lfs":    {false, pickLeastFree},
+	"eplfs":  {true, pickLeastFree},
+	"lus":    {false, pickLeastUsed},
+	"eplus":  {true, pickLeastUsed},
+	"rand":   {false, pickRandom},
+	"eprand": {true, pickRandom},
+}
+
+// getPolicy returns the policy called name
+func getPolicy(name string) (*policy, error) {
+	p := policies[strings.ToLower(name)]
+	if p == nil {
+		var names []string
+		for name := range policies {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, errors.Errorf("unknown policy %q - must be one of %s", name, strings.Join(names, ", "))
+	}
+	return p, nil
+}
+
+// pickAll chooses all the upstreams
+func pickAll(ctx context.Context, upstreams []*upstream) []*upstream {
+	return upstreams
+}
+
+// pickFirst chooses the first upstream
+func pickFirst(ctx context.Context, upstreams []*upstream) []*upstream {
+	return upstreams[:1]
+}
+
+// pickRandom chooses an upstream at random
+func pickRandom(ctx context.Context, upstreams []*upstream) []*upstream {
+	i := rand.Intn(len(upstreams))
+	return upstreams[i : i+1]
+}
+
+// pickUsage chooses the upstream whose value read by get is best
+// according to better.  Upstreams which can't report the value are
+// ignored and if none of them can the first is chosen.
+func pickUsage(ctx context.Context, upstreams []*upstream, get func(*upstream, context.Context) (int64, error), better func(a, b int64) bool) []*upstream {
+	var (
+		best      *upstream
+		bestValue int64
+	)
+	for _, u := range upstreams {
+		value, err := get(u, ctx)
+		if err != nil {
+			fs.Debugf(u, "Ignoring for policy: %v", err)
+			continue
+		}
+		if best == nil || better(value, bestValue) {
+			best, bestValue = u, value
+		}
+	}
+	if best == nil {
+		return upstreams[:1]
+	}
+	return []*upstream{best}
+}
+
+// pickMostFree chooses the upstream with the most free space
+func pickMostFree(ctx context.Context, upstreams []*upstream) []*upstream {
+	return pickUsage(ctx, upstreams, (*upstream).freeSpace, func(a, b int64) bool { return a > b })
+}
+
+// pickLeastFree chooses the upstream with the least free space
+func pickLeastFree(ctx context.Context, upstreams []*upstream) []*upstream {
+	return pickUsage(ctx, upstreams, (*upstream).freeSpace, func(a, b int64) bool { return a < b })
+}
+
+// pickLeastUsed chooses the upstream with the least used space
+func pickLeastUsed(ctx context.Context, upstreams []*upstream) []*upstream {
+	return pickUsage(ctx, upstreams, (*upstream).usedSpace, func(a, b int64) bool { return a < b })
+}
+
+// filter returns the upstreams for which keep is true
+func filter(upstreams []*upstream, keep func(*upstream) bool) (out []*upstream) {
+	for _, u := range upstreams {
+		if keep(u) {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// findUpstreams returns the upstreams which have a file or directory
+// at remote in the same order
+func findUpstreams(ctx context.Context, upstreams []*upstream, remote string) []*upstream {
+	found := make([]bool, len(upstreams))
+	var wg sync.WaitGroup
+	for i, u := range upstreams {
+		wg.Add(1)
+		go func(i int, u *upstream) {
+			defer wg.Done()
+			found[i] = u.exists(ctx, remote)
+		}(i, u)
+	}
+	wg.Wait()
+	var out []*upstream
+	for i, u := range upstreams {
+		if found[i] {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// action returns the upstreams to change or delete remote in
+func (p *policy) action(ctx context.Context, upstreams []*upstream, remote string) ([]*upstream, error) {
+	return p.actionFound(ctx, findUpstreams(ctx, upstreams, remote))
+}
+
+// actionFound returns which of the upstreams a file was found in to
+// change or delete it in
+func (p *policy) actionFound(ctx context.Context, found []*upstream) ([]*upstream, error) {
+	if len(found) == 0 {
+		return nil, fs.ErrorObjectNotFound
+	}
+	found = filter(found, func(u *upstream) bool { return u.writable })
+	if len(found) == 0 {
+		return nil, fs.ErrorPermissionDenied
+	}
+	return p.pick(ctx, found), nil
+}
+
+// create returns the upstreams to create remote in
+//
+// If the policy is path preserving and no upstream has the parent
+// directory of remote then it returns fs.ErrorObjectNotFound.
+func (p *policy) create(ctx context.Context, upstreams []*upstream, remote string) ([]*upstream, error) {
+	upstreams = filter(upstreams, func(u *upstream) bool { return u.creatable })
+	if len(upstreams) == 0 {
+		return nil, fs.ErrorPermissionDenied
+	}
+	if p.pathPreserving {
+		upstreams = findUpstreams(ctx, upstreams, parentDir(remote))
+		if len(upstreams) == 0 {
+			return nil, fs.ErrorObjectNotFound
+		}
+	}
+	return p.pick(ctx, upstreams), nil
+}
+
+// searchFound returns which of the upstreams a file was found in to
+// read it from
+func (p *policy) searchFound(ctx context.Context, found []*upstream) (*upstream, error) {
+	if len(found) == 0 {
+		return nil, fs.ErrorObjectNotFound
+	}
+	return p.pick(ctx, found)[0], nil
+}
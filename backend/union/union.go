@@ -1,11 +1,12 @@
 package union
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"path"
-	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ncw/rclone/fs"
@@ -22,9 +23,34 @@ func init() {
 		Description: "A stackable unification remote, which can appear to merge the contents of several remotes",
 		NewFs:       NewFs,
 		Options: []fs.Option{{
-			Name:     "remotes",
-			Help:     "List of space separated remotes.\nCan be 'remotea:test/dir remoteb:', '\"remotea:test/space dir\" remoteb:', etc.\nThe last remote is used to write to.",
-			Required: true,
+			Name: "upstreams",
+			Help: `List of space separated upstreams.
+Can be 'upstreama:test/dir upstreamb:', '"upstreama:test/space dir" upstreamb:', etc.
+Add ':ro' to the end of an upstream to make it read only or ':nc' to
+stop new files being created in it, eg 'upstreama: upstreamb::ro'.`,
+		}, {
+			Name:    "action_policy",
+			Help:    "Policy to choose upstream(s) on ACTION category - when changing or deleting files.",
+			Default: "epall",
+		}, {
+			Name:    "create_policy",
+			Help:    "Policy to choose upstream(s) on CREATE category - when making files and directories.",
+			Default: "epmfs",
+		}, {
+			Name:    "search_policy",
+			Help:    "Policy to choose upstream on SEARCH category - when reading files.",
+			Default: "ff",
+		}, {
+			Name:    "cache_time",
+			Help:    "Cache time of usage and free space (in seconds).",
+			Default: 120,
+		}, {
+			Name: "remotes",
+			Help: `List of space separated remotes - deprecated, use upstreams instead.
+
+If this is set instead of upstreams then the last remote is used to
+write to and read from first and the others are read only.`,
+			Advanced: true,
 		}},
 	}
 	fs.Register(fsi)
@@ -32,34 +58,64 @@ func init() {
 
 // Options defines the configuration for this backend
 type Options struct {
-	Remotes fs.SpaceSepList `config:"remotes"`
+	Upstreams    fs.SpaceSepList `config:"upstreams"`
+	ActionPolicy string          `config:"action_policy"`
+	CreatePolicy string          `config:"create_policy"`
+	SearchPolicy string          `config:"search_policy"`
+	CacheTime    int             `config:"cache_time"`
+	Remotes      fs.SpaceSepList `config:"remotes"`
 }
 
-// Fs represents a union of remotes
+// Fs represents a union of upstreams
 type Fs struct {
-	name     string       // name of this remote
-	features *fs.Features // optional features
-	opt      Options      // options for this Fs
-	root     string       // the path we are working on
-	remotes  []fs.Fs      // slice of remotes
-	wr       fs.Fs        // writable remote
-	hashSet  hash.Set     // intersection of hash types
+	name         string       // name of this remote
+	features     *fs.Features // optional features
+	opt          Options      // options for this Fs
+	root         string       // the path we are working on
+	upstreams    []*upstream  // the upstreams in config order
+	actionPolicy *policy      // chooses upstreams to change files in
+	createPolicy *policy      // chooses upstreams to make files in
+	searchPolicy *policy      // chooses the upstream to read files from
+	hashSet      hash.Set     // intersection of hash types
+}
+
+// candidate is a file in one of the upstreams
+type candidate struct {
+	o fs.Object // the file
+	u *upstream // the upstream it is in
 }
 
 // Object describes a union Object
 //
-// This is a wrapped object which returns the Union Fs as its parent
+// This is a wrapped object from the upstream chosen by the search
+// policy which returns the Union Fs as its parent.  Changes are made
+// to the copies in the upstreams chosen by the action policy.
 type Object struct {
 	fs.Object
-	fs *Fs // what this object is part of
+	fs         *Fs         // what this object is part of
+	candidates []candidate // the copies of the file in the upstreams
 }
 
-// Wrap an existing object in the union Object
-func (f *Fs) wrapObject(o fs.Object) *Object {
-	return &Object{
-		Object: o,
-		fs:     f,
+// newObject makes an Object from the copies of a file in the upstreams
+func (f *Fs) newObject(ctx context.Context, candidates []candidate) (*Object, error) {
+	found := make([]*upstream, len(candidates))
+	for i := range candidates {
+		found[i] = candidates[i].u
+	}
+	u, err := f.searchPolicy.searchFound(ctx, found)
+	if err != nil {
+		return nil, err
+	}
+	o := &Object{
+		fs:         f,
+		candidates: candidates,
 	}
+	for _, c := range candidates {
+		if c.u == u {
+			o.Object = c.o
+		}
+	}
+	return o, nil
 }
 
 // Fs returns the union Fs as the parent
@@ -67,6 +123,150 @@ func (o *Object) Fs() fs.Info {
 	return o.fs
 }
 
+// UnWrap returns the Object that this Object is wrapping
+func (o *Object) UnWrap() fs.Object {
+	return o.Object
+}
+
+// targets returns the copies of the file chosen by the action policy
+func (o *Object) targets(ctx context.Context) ([]candidate, error) {
+	found := make([]*upstream, len(o.candidates))
+	for i := range o.candidates {
+		found[i] = o.candidates[i].u
+	}
+	us, err := o.fs.actionPolicy.actionFound(ctx, found)
+	if err != nil {
+		return nil, err
+	}
+	var targets []candidate
+	for _, u := range us {
+		for _, c := range o.candidates {
+			if c.u == u {
+				targets = append(targets, c)
+			}
+		}
+	}
+	return targets, nil
+}
+
+// Update in to the object with the modTime given of the given size
+//
+// The copies chosen by the action policy are updated.  If all the
+// copies are in read only upstreams then a new copy is made in the
+// upstreams chosen by the create policy instead.
+func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	targets, err := o.targets(ctx)
+	if err == fs.ErrorPermissionDenied {
+		newObj, err := o.fs.put(ctx, in, src, false, options...)
+		if err != nil {
+			return err
+		}
+		o.Object = newObj.Object
+		o.candidates = append(newObj.candidates, o.candidates...)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	objs, err := multiUpload(in, len(targets), func(i int, in io.Reader) (fs.Object, error) {
+		err := targets[i].o.Update(ctx, in, src, options...)
+		return targets[i].o, err
+	})
+	if err != nil {
+		return err
+	}
+	o.Object = objs[0]
+	return nil
+}
+
+// Remove the copies of the object chosen by the action policy
+func (o *Object) Remove(ctx context.Context) error {
+	targets, err := o.targets(ctx)
+	if err != nil {
+		return err
+	}
+	return multithread(len(targets), func(i int) error {
+		return targets[i].o.Remove(ctx)
+	})
+}
+
+// SetModTime sets the modification time of the copies of the object
+// chosen by the action policy
+func (o *Object) SetModTime(ctx context.Context, t time.Time) error {
+	targets, err := o.targets(ctx)
+	if err != nil {
+		return err
+	}
+	return multithread(len(targets), func(i int) error {
+		return targets[i].o.SetModTime(ctx, t)
+	})
+}
+
+// multithread runs fn for each i in [0, n) in parallel returning the
+// first error
+func multithread(n int, fn func(i int) error) error {
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// multiUpload calls fn n times in parallel giving each a reader which
+// reads the data from in, returning the objects made.
+func multiUpload(in io.Reader, n int, fn func(i int, in io.Reader) (fs.Object, error)) ([]fs.Object, error) {
+	if n == 1 {
+		o, err := fn(0, in)
+		return []fs.Object{o}, err
+	}
+	readers := make([]*io.PipeReader, n)
+	writers := make([]*io.PipeWriter, n)
+	ws := make([]io.Writer, n)
+	for i := range readers {
+		readers[i], writers[i] = io.Pipe()
+		ws[i] = writers[i]
+	}
+	objs := make([]fs.Object, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range readers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			objs[i], errs[i] = fn(i, readers[i])
+			if errs[i] != nil {
+				// stop the other uploads
+				_ = readers[i].CloseWithError(errs[i])
+			}
+		}(i)
+	}
+	_, err := io.Copy(io.MultiWriter(ws...), in)
+	for _, w := range writers {
+		_ = w.CloseWithError(err)
+	}
+	wg.Wait()
+	for _, uploadErr := range errs {
+		if uploadErr != nil {
+			return nil, uploadErr
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return objs, nil
+}
+
 // Name of the remote (as passed into NewFs)
 func (f *Fs) Name() string {
 	return f.name
@@ -87,33 +287,90 @@ func (f *Fs) Features() *fs.Features {
 	return f.features
 }
 
-// Rmdir removes the root directory of the Fs object
-func (f *Fs) Rmdir(dir string) error {
-	return f.wr.Rmdir(dir)
+// Rmdir removes the directory from the upstreams chosen by the action
+// policy
+func (f *Fs) Rmdir(ctx context.Context, dir string) error {
+	us, err := f.actionPolicy.action(ctx, f.upstreams, dir)
+	if err == fs.ErrorObjectNotFound {
+		return fs.ErrorDirNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return multithread(len(us), func(i int) error {
+		return us[i].Rmdir(ctx, dir)
+	})
 }
 
-// Hashes returns hash.HashNone to indicate remote hashing is unavailable
+// Hashes returns the hash types supported by all the upstreams
 func (f *Fs) Hashes() hash.Set {
 	return f.hashSet
 }
 
-// Mkdir makes the root directory of the Fs object
-func (f *Fs) Mkdir(dir string) error {
-	return f.wr.Mkdir(dir)
+// Mkdir makes the directory in the upstreams chosen by the create
+// policy, making its parents first if necessary
+func (f *Fs) Mkdir(ctx context.Context, dir string) error {
+	us, err := f.createPolicy.create(ctx, f.upstreams, dir)
+	if err == fs.ErrorObjectNotFound {
+		if dir == "" {
+			// No upstream has the root so make it in all of them
+			us = filter(f.upstreams, func(u *upstream) bool { return u.creatable })
+			err = nil
+		} else {
+			err = f.Mkdir(ctx, parentDir(dir))
+			if err != nil {
+				return err
+			}
+			us, err = f.createPolicy.create(ctx, f.upstreams, dir)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return multithread(len(us), func(i int) error {
+		return us[i].Mkdir(ctx, dir)
+	})
 }
 
-// Purge all files in the root and the root directory
+// Purge all files in the root and the root directory of the
+// upstreams chosen by the action policy
 //
 // Implement this if you have a way of deleting all the files
 // quicker than just running Remove() on the result of List()
 //
 // Return an error if it doesn't exist
-func (f *Fs) Purge() error {
-	return f.wr.Features().Purge()
+func (f *Fs) Purge(ctx context.Context) error {
+	us, err := f.actionPolicy.action(ctx, f.upstreams, "")
+	if err == fs.ErrorObjectNotFound {
+		return fs.ErrorDirNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return multithread(len(us), func(i int) error {
+		return us[i].Features().Purge(ctx)
+	})
+}
+
+// findUpstream returns the upstream of f in the same place in the
+// config as u in src, or nil if the remotes aren't the same
+func (f *Fs) findUpstream(src *Fs, u *upstream) *upstream {
+	if src.name != f.name || len(src.upstreams) != len(f.upstreams) {
+		return nil
+	}
+	for i := range src.upstreams {
+		if src.upstreams[i] == u {
+			return f.upstreams[i]
+		}
+	}
+	return nil
 }
 
 // Copy src to this remote using server side copy operations.
 //
+// The copies are made in the upstreams chosen by the create policy
+// which must each have a copy of src.
+//
 // This is stored with the remote path given
 //
 // It returns the destination Object and a possible error
@@ -121,20 +378,48 @@ func (f *Fs) Purge() error {
 // Will only be called if src.Fs().Name() == f.Name()
 //
 // If it isn't possible then return fs.ErrorCantCopy
-func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
-	if src.Fs() != f.wr {
+func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	srcObj, ok := src.(*Object)
+	if !ok {
 		fs.Debugf(src, "Can't copy - not same remote type")
 		return nil, fs.ErrorCantCopy
 	}
-	o, err := f.wr.Features().Copy(src, remote)
+	us, err := f.create(ctx, remote)
 	if err != nil {
 		return nil, err
 	}
-	return f.wrapObject(o), nil
+	var sources []candidate
+	for _, u := range us {
+		found := false
+		for _, c := range srcObj.candidates {
+			if f.findUpstream(srcObj.fs, c.u) == u {
+				sources = append(sources, candidate{o: c.o, u: u})
+				found = true
+				break
+			}
+		}
+		if !found {
+			fs.Debugf(src, "Can't copy - not in upstream %v", u)
+			return nil, fs.ErrorCantCopy
+		}
+	}
+	candidates := make([]candidate, len(sources))
+	err = multithread(len(sources), func(i int) error {
+		o, err := sources[i].u.Features().Copy(ctx, sources[i].o, remote)
+		candidates[i] = candidate{o: o, u: sources[i].u}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f.newObject(ctx, candidates)
 }
 
 // Move src to this remote using server side move operations.
 //
+// The copies of src in the upstreams chosen by the action policy
+// are moved.
+//
 // This is stored with the remote path given
 //
 // It returns the destination Object and a possible error
@@ -142,33 +427,72 @@ func (f *Fs) Copy(src fs.Object, remote string) (fs.Object, error) {
 // Will only be called if src.Fs().Name() == f.Name()
 //
 // If it isn't possible then return fs.ErrorCantMove
-func (f *Fs) Move(src fs.Object, remote string) (fs.Object, error) {
-	if src.Fs() != f.wr {
+func (f *Fs) Move(ctx context.Context, src fs.Object, remote string) (fs.Object, error) {
+	srcObj, ok := src.(*Object)
+	if !ok {
 		fs.Debugf(src, "Can't move - not same remote type")
 		return nil, fs.ErrorCantMove
 	}
-	o, err := f.wr.Features().Move(src, remote)
+	targets, err := srcObj.targets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var sources []candidate
+	for _, c := range targets {
+		u := f.findUpstream(srcObj.fs, c.u)
+		if u == nil {
+			fs.Debugf(src, "Can't move - not same remote")
+			return nil, fs.ErrorCantMove
+		}
+		sources = append(sources, candidate{o: c.o, u: u})
+	}
+	candidates := make([]candidate, len(sources))
+	err = multithread(len(sources), func(i int) error {
+		o, err := sources[i].u.Features().Move(ctx, sources[i].o, remote)
+		candidates[i] = candidate{o: o, u: sources[i].u}
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	return f.wrapObject(o), err
+	return f.newObject(ctx, candidates)
 }
 
 // DirMove moves src, srcRemote to this remote at dstRemote
 // using server side move operations.
 //
+// The directory is moved in the upstreams chosen by the action
+// policy.
+//
 // Will only be called if src.Fs().Name() == f.Name()
 //
 // If it isn't possible then return fs.ErrorCantDirMove
 //
 // If destination exists then return fs.ErrorDirExists
-func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
+func (f *Fs) DirMove(ctx context.Context, src fs.Fs, srcRemote, dstRemote string) error {
 	srcFs, ok := src.(*Fs)
 	if !ok {
 		fs.Debugf(srcFs, "Can't move directory - not same remote type")
 		return fs.ErrorCantDirMove
 	}
-	return f.wr.Features().DirMove(srcFs.wr, srcRemote, dstRemote)
+	us, err := srcFs.actionPolicy.action(ctx, srcFs.upstreams, srcRemote)
+	if err == fs.ErrorObjectNotFound {
+		return fs.ErrorDirNotFound
+	}
+	if err != nil {
+		return err
+	}
+	dsts := make([]*upstream, len(us))
+	for i, u := range us {
+		dsts[i] = f.findUpstream(srcFs, u)
+		if dsts[i] == nil {
+			fs.Debugf(srcFs, "Can't move directory - not same remote")
+			return fs.ErrorCantDirMove
+		}
+	}
+	return multithread(len(us), func(i int) error {
+		return dsts[i].Features().DirMove(ctx, us[i].Fs, srcRemote, dstRemote)
+	})
 }
 
 // ChangeNotify calls the passed function with a path
@@ -180,14 +504,14 @@ func (f *Fs) DirMove(src fs.Fs, srcRemote, dstRemote string) error {
 // The ChangeNotify implemantion must empty the channel
 // regulary. When the channel gets closed, the implemantion
 // should stop polling and release resources.
-func (f *Fs) ChangeNotify(fn func(string, fs.EntryType), ch <-chan time.Duration) {
+func (f *Fs) ChangeNotify(ctx context.Context, fn func(string, fs.EntryType), ch <-chan time.Duration) {
 	var remoteChans []chan time.Duration
 
-	for _, remote := range f.remotes {
-		if ChangeNotify := remote.Features().ChangeNotify; ChangeNotify != nil {
+	for _, u := range f.upstreams {
+		if ChangeNotify := u.Features().ChangeNotify; ChangeNotify != nil {
 			ch := make(chan time.Duration)
 			remoteChans = append(remoteChans, ch)
-			ChangeNotify(fn, ch)
+			ChangeNotify(ctx, fn, ch)
 		}
 	}
 
@@ -206,42 +530,118 @@ func (f *Fs) ChangeNotify(fn func(string, fs.EntryType), ch <-chan time.Duration
 // DirCacheFlush resets the directory cache - used in testing
 // as an optional interface
 func (f *Fs) DirCacheFlush() {
-	for _, remote := range f.remotes {
-		if DirCacheFlush := remote.Features().DirCacheFlush; DirCacheFlush != nil {
+	for _, u := range f.upstreams {
+		if DirCacheFlush := u.Features().DirCacheFlush; DirCacheFlush != nil {
 			DirCacheFlush()
 		}
 	}
 }
 
+// create returns the upstreams chosen by the create policy to make
+// remote in, making its parent directory first if necessary
+func (f *Fs) create(ctx context.Context, remote string) ([]*upstream, error) {
+	us, err := f.createPolicy.create(ctx, f.upstreams, remote)
+	if err == fs.ErrorObjectNotFound {
+		err = f.Mkdir(ctx, parentDir(remote))
+		if err != nil {
+			return nil, err
+		}
+		us, err = f.createPolicy.create(ctx, f.upstreams, remote)
+	}
+	return us, err
+}
+
+// put uploads in to the upstreams chosen by the create policy
+func (f *Fs) put(ctx context.Context, in io.Reader, src fs.ObjectInfo, stream bool, options ...fs.OpenOption) (*Object, error) {
+	us, err := f.create(ctx, src.Remote())
+	if err != nil {
+		return nil, err
+	}
+	objs, err := multiUpload(in, len(us), func(i int, in io.Reader) (fs.Object, error) {
+		if stream {
+			return us[i].Features().PutStream(ctx, in, src, options...)
+		}
+		return us[i].Put(ctx, in, src, options...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]candidate, len(us))
+	for i := range us {
+		candidates[i] = candidate{o: objs[i], u: us[i]}
+	}
+	return f.newObject(ctx, candidates)
+}
+
 // PutStream uploads to the remote path with the modTime given of indeterminate size
 //
 // May create the object even if it returns an error - if so
 // will return the object and the error, otherwise will return
 // nil and the error
-func (f *Fs) PutStream(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-	o, err := f.wr.Features().PutStream(in, src, options...)
-	if err != nil {
-		return nil, err
+func (f *Fs) PutStream(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	o, err := f.NewObject(ctx, src.Remote())
+	switch err {
+	case nil:
+		return o, o.Update(ctx, in, src, options...)
+	case fs.ErrorObjectNotFound:
+		return f.put(ctx, in, src, true, options...)
 	}
-	return f.wrapObject(o), err
+	return nil, err
 }
 
-// About gets quota information from the Fs
-func (f *Fs) About() (*fs.Usage, error) {
-	return f.wr.Features().About()
+// About gets quota information from the Fs by adding up the usage of
+// the upstreams which can report it
+func (f *Fs) About(ctx context.Context) (*fs.Usage, error) {
+	usage := &fs.Usage{}
+	add := func(total **int64, value *int64) {
+		if value == nil {
+			return
+		}
+		if *total == nil {
+			*total = new(int64)
+		}
+		**total += *value
+	}
+	var lastErr error
+	found := false
+	for _, u := range f.upstreams {
+		uUsage, err := u.getUsage(ctx)
+		if err != nil {
+			fs.Debugf(u, "Ignoring for About: %v", err)
+			lastErr = err
+			continue
+		}
+		found = true
+		add(&usage.Total, uUsage.Total)
+		add(&usage.Used, uUsage.Used)
+		add(&usage.Trashed, uUsage.Trashed)
+		add(&usage.Other, uUsage.Other)
+		add(&usage.Free, uUsage.Free)
+		add(&usage.Objects, uUsage.Objects)
+	}
+	if !found {
+		return nil, lastErr
+	}
+	return usage, nil
 }
 
 // Put in to the remote path with the modTime given of the given size
 //
+// If the file exists already it is updated, otherwise it is made in
+// the upstreams chosen by the create policy.
+//
 // May create the object even if it returns an error - if so
 // will return the object and the error, otherwise will return
 // nil and the error
-func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
-	o, err := f.wr.Put(in, src, options...)
-	if err != nil {
-		return nil, err
+func (f *Fs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	o, err := f.NewObject(ctx, src.Remote())
+	switch err {
+	case nil:
+		return o, o.Update(ctx, in, src, options...)
+	case fs.ErrorObjectNotFound:
+		return f.put(ctx, in, src, false, options...)
 	}
-	return f.wrapObject(o), err
+	return nil, err
 }
 
 // List the objects and directories in dir into entries.  The
@@ -253,56 +653,110 @@ func (f *Fs) Put(in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.
 //
 // This should return ErrDirNotFound if the directory isn't
 // found.
-func (f *Fs) List(dir string) (entries fs.DirEntries, err error) {
-	set := make(map[string]fs.DirEntry)
-	found := false
-	for _, remote := range f.remotes {
-		var remoteEntries, err = remote.List(dir)
+func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	upstreamEntries := make([]fs.DirEntries, len(f.upstreams))
+	found := make([]bool, len(f.upstreams))
+	err = multithread(len(f.upstreams), func(i int) error {
+		u := f.upstreams[i]
+		entries, err := u.List(ctx, dir)
 		if err == fs.ErrorDirNotFound {
-			continue
+			return nil
 		}
 		if err != nil {
-			return nil, errors.Wrapf(err, "List failed on %v", remote)
-		}
-		found = true
-		for _, remoteEntry := range remoteEntries {
-			set[remoteEntry.Remote()] = remoteEntry
+			return errors.Wrapf(err, "List failed on %v", u)
 		}
+		upstreamEntries[i], found[i] = entries, true
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	if !found {
+	anyFound := false
+	for i := range found {
+		anyFound = anyFound || found[i]
+	}
+	if !anyFound {
 		return nil, fs.ErrorDirNotFound
 	}
-	for _, entry := range set {
-		if o, ok := entry.(fs.Object); ok {
-			entry = f.wrapObject(o)
+	var (
+		dirs       = make(map[string]struct{})
+		candidates = make(map[string][]candidate)
+		order      []string
+	)
+	for i, u := range f.upstreams {
+		if !found[i] {
+			continue
+		}
+		for _, entry := range upstreamEntries[i] {
+			remote := entry.Remote()
+			switch x := entry.(type) {
+			case fs.Directory:
+				if _, ok := dirs[remote]; ok {
+					continue
+				}
+				if _, ok := candidates[remote]; ok {
+					continue
+				}
+				dirs[remote] = struct{}{}
+				entries = append(entries, x)
+			case fs.Object:
+				if _, ok := dirs[remote]; ok {
+					continue
+				}
+				if _, ok := candidates[remote]; !ok {
+					order = append(order, remote)
+				}
+				candidates[remote] = append(candidates[remote], candidate{o: x, u: u})
+			}
 		}
-		entries = append(entries, entry)
+	}
+	for _, remote := range order {
+		o, err := f.newObject(ctx, candidates[remote])
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, o)
 	}
 	return entries, nil
 }
 
-// NewObject creates a new remote union file object based on the first Object it finds (reverse remote order)
-func (f *Fs) NewObject(path string) (fs.Object, error) {
-	for i := range f.remotes {
-		var remote = f.remotes[len(f.remotes)-i-1]
-		var obj, err = remote.NewObject(path)
+// NewObject creates a new remote union file object from the copies
+// of the file in the upstreams
+func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	objs := make([]fs.Object, len(f.upstreams))
+	err := multithread(len(f.upstreams), func(i int) error {
+		u := f.upstreams[i]
+		o, err := u.NewObject(ctx, remote)
 		if err == fs.ErrorObjectNotFound {
-			continue
+			return nil
 		}
 		if err != nil {
-			return nil, errors.Wrapf(err, "NewObject failed on %v", remote)
+			return errors.Wrapf(err, "NewObject failed on %v", u)
+		}
+		objs[i] = o
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var candidates []candidate
+	for i, o := range objs {
+		if o != nil {
+			candidates = append(candidates, candidate{o: o, u: f.upstreams[i]})
 		}
-		return f.wrapObject(obj), nil
 	}
-	return nil, fs.ErrorObjectNotFound
+	if len(candidates) == 0 {
+		return nil, fs.ErrorObjectNotFound
+	}
+	return f.newObject(ctx, candidates)
 }
 
-// Precision is the greatest Precision of all remotes
+// Precision is the greatest Precision of all upstreams
 func (f *Fs) Precision() time.Duration {
 	var greatestPrecision time.Duration
-	for _, remote := range f.remotes {
-		if remote.Precision() > greatestPrecision {
-			greatestPrecision = remote.Precision()
+	for _, u := range f.upstreams {
+		if u.Precision() > greatestPrecision {
+			greatestPrecision = u.Precision()
 		}
 	}
 	return greatestPrecision
@@ -318,52 +772,78 @@ func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
 	if err != nil {
 		return nil, err
 	}
-	if len(opt.Remotes) == 0 {
-		return nil, errors.New("union can't point to an empty remote - check the value of the remotes setting")
+	if len(opt.Upstreams) == 0 && len(opt.Remotes) != 0 {
+		// Emulate the old behaviour where the last remote was
+		// written to and read from first
+		for i := len(opt.Remotes) - 1; i >= 0; i-- {
+			remote := opt.Remotes[i]
+			if i != len(opt.Remotes)-1 {
+				remote += ":ro"
+			}
+			opt.Upstreams = append(opt.Upstreams, remote)
+		}
+		opt.ActionPolicy, opt.CreatePolicy, opt.SearchPolicy = "ff", "ff", "ff"
+	}
+	if len(opt.Upstreams) == 0 {
+		return nil, errors.New("union can't point to an empty upstream - check the value of the upstreams setting")
 	}
-	if len(opt.Remotes) == 1 {
-		return nil, errors.New("union can't point to a single remote - check the value of the remotes setting")
+	if len(opt.Upstreams) == 1 {
+		return nil, errors.New("union can't point to a single upstream - check the value of the upstreams setting")
 	}
-	for _, remote := range opt.Remotes {
-		if strings.HasPrefix(remote, name+":") {
-			return nil, errors.New("can't point union remote at itself - check the value of the remote setting")
+	for _, u := range opt.Upstreams {
+		if strings.HasPrefix(u, name+":") {
+			return nil, errors.New("can't point union remote at itself - check the value of the upstreams setting")
 		}
 	}
 
-	var remotes []fs.Fs
-	for i := range opt.Remotes {
-		// Last remote first so we return the correct (last) matching fs in case of fs.ErrorIsFile
-		var remote = opt.Remotes[len(opt.Remotes)-i-1]
-		_, configName, fsPath, err := fs.ParseRemote(remote)
-		if err != nil {
+	f := &Fs{
+		name: name,
+		root: root,
+		opt:  *opt,
+	}
+	if f.actionPolicy, err = getPolicy(opt.ActionPolicy); err != nil {
+		return nil, errors.Wrap(err, "bad action_policy")
+	}
+	if f.createPolicy, err = getPolicy(opt.CreatePolicy); err != nil {
+		return nil, errors.Wrap(err, "bad create_policy")
+	}
+	if f.searchPolicy, err = getPolicy(opt.SearchPolicy); err != nil {
+		return nil, errors.Wrap(err, "bad search_policy")
+	}
+
+	upstreams := make([]*upstream, len(opt.Upstreams))
+	errs := make([]error, len(opt.Upstreams))
+	cacheTime := time.Duration(opt.CacheTime) * time.Second
+	_ = multithread(len(opt.Upstreams), func(i int) error {
+		upstreams[i], errs[i] = newUpstream(opt.Upstreams[i], root, cacheTime)
+		return nil
+	})
+	var fsErr error
+	for _, err := range errs {
+		if err != nil && err != fs.ErrorIsFile {
 			return nil, err
 		}
-		var rootString = path.Join(fsPath, filepath.ToSlash(root))
-		if configName != "local" {
-			rootString = configName + ":" + rootString
+		if err == fs.ErrorIsFile {
+			fsErr = err
 		}
-		myFs, err := fs.NewFs(rootString)
-		if err != nil {
-			if err == fs.ErrorIsFile {
-				return myFs, err
+	}
+	if fsErr == fs.ErrorIsFile {
+		// Only use the upstreams in which root is a file, which
+		// are now rooted at its parent
+		var used []*upstream
+		for i := range upstreams {
+			if errs[i] == fs.ErrorIsFile {
+				used = append(used, upstreams[i])
 			}
-			return nil, err
 		}
-		remotes = append(remotes, myFs)
-	}
-
-	// Reverse the remotes again so they are in the order as before
-	for i, j := 0, len(remotes)-1; i < j; i, j = i+1, j-1 {
-		remotes[i], remotes[j] = remotes[j], remotes[i]
+		upstreams = used
+		f.root = path.Dir(root)
+		if f.root == "." || f.root == "/" {
+			f.root = ""
+		}
 	}
+	f.upstreams = upstreams
 
-	f := &Fs{
-		name:    name,
-		root:    root,
-		opt:     *opt,
-		remotes: remotes,
-		wr:      remotes[len(remotes)-1],
-	}
 	var features = (&fs.Features{
 		CaseInsensitive:         true,
 		DuplicateFiles:          false,
@@ -374,39 +854,45 @@ func NewFs(name, root string, m configmap.Mapper) (fs.Fs, error) {
 		SetTier:                 true,
 		GetTier:                 true,
 	}).Fill(f)
-	features = features.Mask(f.wr) // mask the features just on the writable fs
-
-	// FIXME maybe should be masking the bools here?
-
-	// Clear ChangeNotify and DirCacheFlush if all are nil
-	clearChangeNotify := true
-	clearDirCacheFlush := true
-	for _, remote := range f.remotes {
-		remoteFeatures := remote.Features()
-		if remoteFeatures.ChangeNotify != nil {
+	// Keep ChangeNotify, DirCacheFlush and About if any upstream
+	// supports them, otherwise only keep the features supported
+	// by all of the upstreams
+	changeNotify, dirCacheFlush, about := features.ChangeNotify, features.DirCacheFlush, features.About
+	clearChangeNotify, clearDirCacheFlush, clearAbout := true, true, true
+	for _, u := range f.upstreams {
+		features = features.Mask(u)
+		uFeatures := u.Features()
+		if uFeatures.ChangeNotify != nil {
 			clearChangeNotify = false
 		}
-		if remoteFeatures.DirCacheFlush != nil {
+		if uFeatures.DirCacheFlush != nil {
 			clearDirCacheFlush = false
 		}
+		if uFeatures.About != nil {
+			clearAbout = false
+		}
 	}
+	features.ChangeNotify, features.DirCacheFlush, features.About = changeNotify, dirCacheFlush, about
 	if clearChangeNotify {
 		features.ChangeNotify = nil
 	}
 	if clearDirCacheFlush {
 		features.DirCacheFlush = nil
 	}
+	if clearAbout {
+		features.About = nil
+	}
 
 	f.features = features
 
 	// Get common intersection of hashes
-	hashSet := f.remotes[0].Hashes()
-	for _, remote := range f.remotes[1:] {
-		hashSet = hashSet.Overlap(remote.Hashes())
+	hashSet := f.upstreams[0].Hashes()
+	for _, u := range f.upstreams[1:] {
+		hashSet = hashSet.Overlap(u.Hashes())
 	}
 	f.hashSet = hashSet
 
-	return f, nil
+	return f, fsErr
 }
 
 // Check the interfaces are satisfied
@@ -420,4 +906,6 @@ var (
 	_ fs.DirCacheFlusher = (*Fs)(nil)
 	_ fs.ChangeNotifier  = (*Fs)(nil)
 	_ fs.Abouter         = (*Fs)(nil)
+	_ fs.Object          = (*Object)(nil)
+	_ fs.ObjectUnWrapper = (*Object)(nil)
 )
@@ -2,9 +2,13 @@
 package union_test
 
 import (
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
 
 	_ "github.com/ncw/rclone/backend/local"
+	"github.com/ncw/rclone/fstest"
 	"github.com/ncw/rclone/fstest/fstests"
 )
 
@@ -16,3 +20,58 @@ func TestIntegration(t *testing.T) {
 		SkipFsMatch: true,
 	})
 }
+
+// runPolicies runs the integration tests against a union of three
+// local directories with the upstream suffixes and policies given
+func runPolicies(t *testing.T, name string, suffixes [3]string, action, create, search string) {
+	if *fstest.RemoteName != "" {
+		t.Skip("Skipping as -remote set")
+	}
+	upstreams := ""
+	for i, suffix := range suffixes {
+		dir := filepath.Join(os.TempDir(), "rclone-union-test-"+name, strconv.Itoa(i))
+		upstreams += `"` + dir + suffix + `" `
+	}
+	fstests.Run(t, &fstests.Opt{
+		RemoteName:  name + ":",
+		NilObject:   nil,
+		SkipFsMatch: true,
+		ExtraConfig: []fstests.ExtraConfigItem{
+			{Name: name, Key: "type", Value: "union"},
+			{Name: name, Key: "upstreams", Value: upstreams},
+			{Name: name, Key: "action_policy", Value: action},
+			{Name: name, Key: "create_policy", Value: create},
+			{Name: name, Key: "search_policy", Value: search},
+		},
+	})
+}
+
+// TestStandard runs the integration tests with the default policies
+func TestStandard(t *testing.T) {
+	runPolicies(t, "TestUnionStandard", [3]string{}, "epall", "epmfs", "ff")
+}
+
+// TestRO runs the integration tests with a read only upstream
+func TestRO(t *testing.T) {
+	runPolicies(t, "TestUnionRO", [3]string{"", ":ro", ""}, "epall", "epmfs", "ff")
+}
+
+// TestNC runs the integration tests with a no create upstream
+func TestNC(t *testing.T) {
+	runPolicies(t, "TestUnionNC", [3]string{":nc", "", ""}, "epall", "epmfs", "ff")
+}
+
+// TestPolicyAll runs the integration tests writing to all the upstreams
+func TestPolicyAll(t *testing.T) {
+	runPolicies(t, "TestUnionAll", [3]string{}, "all", "all", "all")
+}
+
+// TestPolicyLus runs the integration tests with the lus create policy
+func TestPolicyLus(t *testing.T) {
+	runPolicies(t, "TestUnionLus", [3]string{}, "all", "lus", "ff")
+}
+
+// TestPolicyRand runs the integration tests with the rand create policy
+func TestPolicyRand(t *testing.T) {
+	runPolicies(t, "TestUnionRand", [3]string{}, "all", "rand", "ff")
+}
@@ -0,0 +1,152 @@
+package union
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
+)
+
+// errUsageNotSupported is returned if an upstream can't report the
+// usage needed by a policy
+var errUsageNotSupported = errors.New("upstream doesn't support reading its usage")
+
+// upstream is one of the remotes making up the union
+type upstream struct {
+	fs.Fs
+	writable  bool          // set if files can be changed or deleted
+	creatable bool          // set if files can be created
+	cacheTime time.Duration // how long to cache the usage for
+
+	mu     sync.Mutex // protects the following
+	usage  *fs.Usage  // the cached usage - nil if not read yet
+	expiry time.Time  // when the cached usage needs reading again
+}
+
+// newUpstream makes an upstream from a remote from the config, which
+// may end with ":ro" to make it read only or ":nc" to stop files
+// being created in it, rooted at root.
+//
+// If root is a file it returns the upstream rooted at the parent and
+// fs.ErrorIsFile.
+func newUpstream(remote, root string, cacheTime time.Duration) (*upstream, error) {
+	u := &upstream{
+		writable:  true,
+		creatable: true,
+		cacheTime: cacheTime,
+	}
+	if strings.HasSuffix(remote, ":ro") {
+		remote = remote[:len(remote)-3]
+		u.writable = false
+		u.creatable = false
+	} else if strings.HasSuffix(remote, ":nc") {
+		remote = remote[:len(remote)-3]
+		u.creatable = false
+	}
+	_, configName, fsPath, err := fs.ParseRemote(remote)
+	if err != nil {
+		return nil, err
+	}
+	rootString := path.Join(fsPath, filepath.ToSlash(root))
+	if configName != "local" {
+		rootString = configName + ":" + rootString
+	}
+	f, err := fs.NewFs(rootString)
+	if err != nil && err != fs.ErrorIsFile {
+		return nil, err
+	}
+	u.Fs = f
+	return u, err
+}
+
+// String returns a description of the upstream
+func (u *upstream) String() string {
+	s := u.Fs.String()
+	if !u.writable {
+		s += " (read only)"
+	} else if !u.creatable {
+		s += " (no create)"
+	}
+	return s
+}
+
+// getUsage returns the usage of the upstream, reading it at most
+// once every cacheTime
+func (u *upstream) getUsage(ctx context.Context) (*fs.Usage, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.usage != nil && time.Now().Before(u.expiry) {
+		return u.usage, nil
+	}
+	do := u.Features().About
+	if do == nil {
+		return nil, errUsageNotSupported
+	}
+	usage, err := do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u.usage = usage
+	u.expiry = time.Now().Add(u.cacheTime)
+	return usage, nil
+}
+
+// freeSpace returns the free space in the upstream
+func (u *upstream) freeSpace(ctx context.Context) (int64, error) {
+	usage, err := u.getUsage(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if usage.Free == nil {
+		return 0, errUsageNotSupported
+	}
+	return *usage.Free, nil
+}
+
+// usedSpace returns the space used in the upstream
+func (u *upstream) usedSpace(ctx context.Context) (int64, error) {
+	usage, err := u.getUsage(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if usage.Used == nil {
+		return 0, errUsageNotSupported
+	}
+	return *usage.Used, nil
+}
+
+// exists returns whether there is a file or directory at remote in
+// the upstream
+func (u *upstream) exists(ctx context.Context, remote string) bool {
+	if remote == "" {
+		_, err := u.List(ctx, "")
+		return err == nil
+	}
+	if _, err := u.NewObject(ctx, remote); err == nil {
+		return true
+	}
+	entries, err := u.List(ctx, parentDir(remote))
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if entry.Remote() == remote {
+			return true
+		}
+	}
+	return false
+}
+
+// parentDir returns the parent directory of remote, or "" for the root
+func parentDir(remote string) string {
+	parent := path.Dir(remote)
+	if parent == "." || parent == "/" {
+		parent = ""
+	}
+	return parent
+}
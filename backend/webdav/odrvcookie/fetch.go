@@ -161,7 +161,7 @@ func (ca *CookieAuth) getSPToken() (conf *SuccessResponse, err error) {
 		return nil, err
 	}
 
-	client := fshttp.NewClient(fs.Config)
+	client := fshttp.NewClientForBackend(fs.Config, "webdav")
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error while logging in to endpoint")
@@ -28,6 +28,8 @@ docs = [
     "b2.md",
     "box.md",
     "cache.md",
+    "chunker.md",
+    "compress.md",
     "crypt.md",
     "dropbox.md",
     "ftp.md",
@@ -6,9 +6,12 @@ import (
 	_ "github.com/ncw/rclone/cmd"
 	_ "github.com/ncw/rclone/cmd/about"
 	_ "github.com/ncw/rclone/cmd/authorize"
+	_ "github.com/ncw/rclone/cmd/backend"
+	_ "github.com/ncw/rclone/cmd/bisync"
 	_ "github.com/ncw/rclone/cmd/cachestats"
 	_ "github.com/ncw/rclone/cmd/cat"
 	_ "github.com/ncw/rclone/cmd/check"
+	_ "github.com/ncw/rclone/cmd/checksum"
 	_ "github.com/ncw/rclone/cmd/cleanup"
 	_ "github.com/ncw/rclone/cmd/cmount"
 	_ "github.com/ncw/rclone/cmd/config"
@@ -44,6 +47,7 @@ import (
 	_ "github.com/ncw/rclone/cmd/rc"
 	_ "github.com/ncw/rclone/cmd/rcat"
 	_ "github.com/ncw/rclone/cmd/rcd"
+	_ "github.com/ncw/rclone/cmd/restore"
 	_ "github.com/ncw/rclone/cmd/reveal"
 	_ "github.com/ncw/rclone/cmd/rmdir"
 	_ "github.com/ncw/rclone/cmd/rmdirs"
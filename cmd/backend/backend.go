@@ -0,0 +1,129 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	options []string
+	useJSON bool
+)
+
+func init() {
+	cmd.Root.AddCommand(commandDefinition)
+	commandDefinition.Flags().StringArrayVarP(&options, "option", "o", options, "Option in the form name=value or name.")
+	commandDefinition.Flags().BoolVarP(&useJSON, "json", "", useJSON, "Always output in JSON format.")
+}
+
+var commandDefinition = &cobra.Command{
+	Use:   "backend <command> remote:path [opts] <args>",
+	Short: `Run a backend specific command.`,
+	Long: `
+This runs a backend specific command. The commands themselves (except
+for "help") are defined by the backends and you should see the backend
+docs for definitions.
+
+You can discover what commands a backend implements by using
+
+    rclone backend help remote:
+    rclone backend help <backendname>
+
+Pass options to the backend command with -o. This should be key=value
+or key, eg:
+
+    rclone backend restore s3:bucket/path -o priority=Bulk -o lifetime=2
+
+Pass arguments to the backend by placing them on the end of the line
+
+    rclone backend hide b2:bucket file1 file2 file3
+
+If the result is a string or a list of strings it is printed one per
+line, otherwise it is printed as JSON.  Use --json to always print
+JSON.
+
+Note to run these commands on a running backend then see
+[backend/command](/rc/#backend/command) in the rc docs.
+`,
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(2, 1e6, command, args)
+		name, remote := args[0], args[1]
+		cmd.Run(false, false, command, func() error {
+			// show help if remote is a backend name
+			if name == "help" {
+				fsInfo, err := fs.Find(remote)
+				if err == nil {
+					cmd.ShowBackendCommands(fsInfo)
+					return nil
+				}
+				fsInfo, _, _, err = fs.ParseRemote(remote)
+				if err != nil {
+					return err
+				}
+				cmd.ShowBackendCommands(fsInfo)
+				return nil
+			}
+			f := cmd.NewFsSrc(args[1:])
+			doCommand := f.Features().Command
+			if doCommand == nil {
+				return errors.Errorf("%v: doesn't support backend commands", f)
+			}
+			out, err := doCommand(context.Background(), name, args[2:], parseOptions(options))
+			if err == fs.ErrorCommandNotFound {
+				return errors.Errorf("%q is not a backend command. Use \"rclone backend help %s\" for a list of commands", name, remote)
+			} else if err != nil {
+				return errors.Wrapf(err, "command %q failed", name)
+			}
+			// Output the result
+			if useJSON {
+				return writeJSON(out)
+			}
+			switch x := out.(type) {
+			case nil:
+			case string:
+				fmt.Println(x)
+			case []string:
+				for _, line := range x {
+					fmt.Println(line)
+				}
+			default:
+				return writeJSON(out)
+			}
+			return nil
+		})
+	},
+}
+
+// parseOptions turns the -o options into a map - an option without
+// an "=" is set to ""
+func parseOptions(options []string) map[string]string {
+	opt := make(map[string]string, len(options))
+	for _, option := range options {
+		equals := strings.IndexRune(option, '=')
+		key, value := option, ""
+		if equals >= 0 {
+			key, value = option[:equals], option[equals+1:]
+		}
+		opt[key] = value
+	}
+	return opt
+}
+
+// writeJSON writes out as indented JSON to stdout
+func writeJSON(out interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "\t")
+	err := enc.Encode(out)
+	if err != nil {
+		return errors.Wrap(err, "failed to write JSON")
+	}
+	return nil
+}
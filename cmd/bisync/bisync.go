@@ -0,0 +1,511 @@
+// Package bisync implements bidirectional sync between two paths
+package bisync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/accounting"
+	"github.com/ncw/rclone/fs/config"
+	"github.com/ncw/rclone/fs/config/flags"
+	"github.com/ncw/rclone/fs/march"
+	"github.com/ncw/rclone/fs/operations"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Options controls the bisync
+type Options struct {
+	Resync           bool   // copy in both directions to make the first listings
+	MaxDeletePercent int    // abort if more than this percentage of files on either side would be deleted
+	Force            bool   // bypass the MaxDeletePercent check
+	Workdir          string // directory to store the listings in
+}
+
+// DefaultOpt is the default values used for Options
+var DefaultOpt = Options{
+	MaxDeletePercent: 50,
+	Workdir:          filepath.Join(config.CacheDir, "bisync"),
+}
+
+// Opt is options set by command line flags
+var Opt = DefaultOpt
+
+func init() {
+	cmd.Root.AddCommand(commandDefinition)
+	flagSet := commandDefinition.Flags()
+	flags.BoolVarP(flagSet, &Opt.Resync, "resync", "", Opt.Resync, "Copy in both directions to make the first listings, path1 winning any differences.")
+	flags.IntVarP(flagSet, &Opt.MaxDeletePercent, "max-delete-percent", "", Opt.MaxDeletePercent, "Abort if more than this percentage of files on either path would be deleted.")
+	flags.BoolVarP(flagSet, &Opt.Force, "force", "", Opt.Force, "Bypass the --max-delete-percent safety check.")
+	flags.StringVarP(flagSet, &Opt.Workdir, "workdir", "", Opt.Workdir, "Directory to store the listings in.")
+}
+
+var commandDefinition = &cobra.Command{
+	Use:   "bisync path1:path path2:path",
+	Short: `Bidirectional sync between two paths.`,
+	Long: `
+Bidirectional sync between two paths, propagating new, changed and
+deleted files in both directions.
+
+bisync remembers a listing of each path from the last successful run
+and uses these to work out what has changed on each side since.
+
+  - a file new or changed on one side is copied to the other
+  - a file deleted on one side is deleted on the other, unless it was
+    changed there in which case the changed file is copied back
+  - a file new or changed on both sides which is now identical is left
+    alone
+  - a file new or changed on both sides which differs is a conflict.
+    The newer file wins and the other is renamed with a ".conflict1"
+    or ".conflict2" suffix depending on which path it came from, with
+    "-2", "-3" etc added if that name is already in use.  Both files
+    end up on both paths so nothing is lost.
+
+The first run for a pair of paths must use ` + "`--resync`" + `.  This
+copies any files missing from either path to the other and copies
+path1's version of any files which differ to path2 and then makes the
+listings.  Use ` + "`--resync`" + ` again to start over if the listings
+are lost or the paths have been changed by something else in a way
+you want to discard.
+
+As a safety check bisync will abort without changing anything if more
+than ` + "`--max-delete-percent`" + ` (default 50) of the files on
+either path would be deleted.  This protects against an accidentally
+emptied path wiping out the other.  Use ` + "`--force`" + ` to go ahead
+anyway.
+
+The listings are stored in ` + "`--workdir`" + ` which defaults to a
+bisync directory in rclone's cache directory.  The listings are only
+updated if the run completes without errors so a failed run can just
+be run again.  A lock file stops two runs on the same paths at once.
+
+Use ` + "`--dry-run`" + ` to see what would be done without changing
+anything.  Filters may be used but should be kept the same between
+runs, otherwise files newly excluded will look deleted.
+
+Only files are synced.  Empty directories are not created or removed.
+`,
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(2, 2, command, args)
+		f1, f2 := cmd.NewFsSrcDst(args)
+		cmd.Run(false, true, command, func() error {
+			return Bisync(context.Background(), f1, f2, &Opt)
+		})
+	},
+}
+
+// state is the state of a file on one path compared to the listing
+type state int
+
+// The possible states
+const (
+	stateAbsent    state = iota // not in the listing and not present
+	stateUnchanged              // in the listing and not changed
+	stateNew                    // not in the listing but present
+	stateChanged                // in the listing but changed
+	stateDeleted                // in the listing but not present
+)
+
+// newOrChanged returns true if the file was created or modified
+func (s state) newOrChanged() bool {
+	return s == stateNew || s == stateChanged
+}
+
+// path is one side of the bisync
+type path struct {
+	f         fs.Fs
+	name      string  // path1 or path2 for logging
+	suffix    string  // conflict suffix for files from this path
+	prev      listing // listing from last run
+	next      listing // listing to save after this run
+	precision time.Duration
+}
+
+// stateOf returns the state of the file remote which is o on this
+// path (o may be nil)
+func (p *path) stateOf(remote string, o fs.Object) state {
+	fi, found := p.prev[remote]
+	switch {
+	case !found && o == nil:
+		return stateAbsent
+	case !found:
+		return stateNew
+	case o == nil:
+		return stateDeleted
+	case fi.changed(o, p.precision):
+		return stateChanged
+	}
+	return stateUnchanged
+}
+
+// pair is a file on path1 and or path2
+type pair struct {
+	remote string
+	o1, o2 fs.Object // either of these may be nil
+}
+
+// bisync holds the state of a bisync run
+type bisync struct {
+	ctx     context.Context
+	opt     *Options
+	path1   *path
+	path2   *path
+	mu      sync.Mutex // protects below
+	pairs   []pair
+	names   map[string]struct{} // names of all the files found and conflicts made
+	errors  int
+	deletes [2]int // deletes planned on path1 and path2
+}
+
+// record an error
+func (b *bisync) error(err error) {
+	fs.CountError(b.ctx, err)
+	b.mu.Lock()
+	b.errors++
+	b.mu.Unlock()
+}
+
+// add a pair found by the march
+func (b *bisync) add(remote string, o1, o2 fs.Object) {
+	b.mu.Lock()
+	b.pairs = append(b.pairs, pair{remote: remote, o1: o1, o2: o2})
+	b.names[remote] = struct{}{}
+	b.mu.Unlock()
+}
+
+// SrcOnly is called for a DirEntry found only on path1
+func (b *bisync) SrcOnly(src fs.DirEntry) (recurse bool) {
+	switch x := src.(type) {
+	case fs.Object:
+		b.add(x.Remote(), x, nil)
+	case fs.Directory:
+		return true
+	}
+	return false
+}
+
+// DstOnly is called for a DirEntry found only on path2
+func (b *bisync) DstOnly(dst fs.DirEntry) (recurse bool) {
+	switch x := dst.(type) {
+	case fs.Object:
+		b.add(x.Remote(), nil, x)
+	case fs.Directory:
+		return true
+	}
+	return false
+}
+
+// Match is called for a DirEntry found on both paths
+func (b *bisync) Match(ctx context.Context, dst, src fs.DirEntry) (recurse bool) {
+	switch srcX := src.(type) {
+	case fs.Object:
+		if dstX, ok := dst.(fs.Object); ok {
+			b.add(srcX.Remote(), srcX, dstX)
+			return false
+		}
+	case fs.Directory:
+		if _, ok := dst.(fs.Directory); ok {
+			return true
+		}
+	}
+	err := errors.New("file on one path is a directory on the other")
+	fs.Errorf(src, "%v", err)
+	b.error(err)
+	return false
+}
+
+// loadListings reads the listings from the last run
+func (b *bisync) loadListings(listing1, listing2 string) (err error) {
+	b.path1.prev, err = loadListing(listing1)
+	if err == nil {
+		b.path2.prev, err = loadListing(listing2)
+	}
+	if os.IsNotExist(err) {
+		return errors.New("no listings from a previous run found - run with --resync first")
+	}
+	return err
+}
+
+// copyFile copies o from path src to remote on path dst replacing
+// existing (which may be nil) and records it in dst's listing
+func (b *bisync) copyFile(src, dst *path, remote string, o, existing fs.Object) fs.Object {
+	stats := accounting.GetStats(b.ctx)
+	stats.Transferring(remote)
+	newDst, err := operations.Copy(b.ctx, dst.f, existing, remote, o)
+	stats.DoneTransferring(remote, err == nil)
+	if err != nil {
+		b.error(err)
+		return nil
+	}
+	info := newFileInfo(o)
+	if newDst != nil {
+		info = newFileInfo(newDst)
+	}
+	b.mu.Lock()
+	dst.next[remote] = info
+	b.mu.Unlock()
+	return newDst
+}
+
+// deleteFile deletes o on path p and removes it from p's listing
+func (b *bisync) deleteFile(p *path, remote string, o fs.Object) {
+	err := operations.DeleteFile(b.ctx, o)
+	if err != nil {
+		b.error(err)
+		return
+	}
+	b.mu.Lock()
+	delete(p.next, remote)
+	b.mu.Unlock()
+}
+
+// conflictName returns the name to rename the losing version of
+// remote to.  This is remote with loser's conflict suffix, with "-2",
+// "-3" etc added if that is in use on either path so conflicts left
+// from earlier runs aren't overwritten.
+func (b *bisync) conflictName(winner, loser *path, remote string) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := 1; ; i++ {
+		name := remote + loser.suffix
+		if i > 1 {
+			name += fmt.Sprintf("-%d", i)
+		}
+		if _, found := b.names[name]; found {
+			continue
+		}
+		free := true
+		for _, p := range []*path{loser, winner} {
+			_, err := p.f.NewObject(b.ctx, name)
+			if err == fs.ErrorObjectNotFound {
+				continue
+			}
+			if err != nil && errors.Cause(err) != fs.ErrorNotAFile {
+				return "", errors.Wrapf(err, "failed to check conflict name %q", name)
+			}
+			free = false
+		}
+		b.names[name] = struct{}{}
+		if free {
+			return name, nil
+		}
+	}
+}
+
+// conflict resolves a conflict between different new or changed
+// files on both paths.  The newer file wins and the loser is renamed
+// on its path then copied to the other.
+func (b *bisync) conflict(pr pair) {
+	winner, loser := b.path1, b.path2
+	winnerObj, loserObj := pr.o1, pr.o2
+	if pr.o2.ModTime().After(pr.o1.ModTime()) {
+		winner, loser = loser, winner
+		winnerObj, loserObj = loserObj, winnerObj
+	}
+	conflictRemote, err := b.conflictName(winner, loser, pr.remote)
+	if err != nil {
+		b.error(err)
+		return
+	}
+	fs.Logf(pr.remote, "Conflict: changed on both paths - keeping %s version and renaming %s version to %q", winner.name, loser.name, conflictRemote)
+
+	// Rename the loser out of the way on its own path
+	moved, err := operations.Move(b.ctx, loser.f, nil, conflictRemote, loserObj)
+	if err != nil {
+		b.error(err)
+		return
+	}
+	b.mu.Lock()
+	delete(loser.next, pr.remote)
+	if moved != nil {
+		loser.next[conflictRemote] = newFileInfo(moved)
+	} else {
+		loser.next[conflictRemote] = newFileInfo(loserObj)
+	}
+	b.mu.Unlock()
+
+	// Copy the loser and the winner to the other paths
+	if moved != nil {
+		b.copyFile(loser, winner, conflictRemote, moved, nil)
+	}
+	b.copyFile(winner, loser, pr.remote, winnerObj, nil)
+}
+
+// decide what to do with pr returning false if nothing needs doing
+func (b *bisync) decide(pr pair) (action func(), needed bool) {
+	if b.opt.Resync {
+		switch {
+		case pr.o2 == nil:
+			return func() { b.copyFile(b.path1, b.path2, pr.remote, pr.o1, nil) }, true
+		case pr.o1 == nil:
+			return func() { b.copyFile(b.path2, b.path1, pr.remote, pr.o2, nil) }, true
+		case !operations.Equal(b.ctx, pr.o1, pr.o2):
+			return func() { b.copyFile(b.path1, b.path2, pr.remote, pr.o1, pr.o2) }, true
+		}
+		return nil, false
+	}
+	s1 := b.path1.stateOf(pr.remote, pr.o1)
+	s2 := b.path2.stateOf(pr.remote, pr.o2)
+	switch {
+	case s1.newOrChanged() && s2.newOrChanged():
+		if operations.Equal(b.ctx, pr.o1, pr.o2) {
+			fs.Debugf(pr.remote, "New or changed on both paths but identical")
+			return nil, false
+		}
+		return func() { b.conflict(pr) }, true
+	case s1.newOrChanged():
+		return func() { b.copyFile(b.path1, b.path2, pr.remote, pr.o1, pr.o2) }, true
+	case s2.newOrChanged():
+		return func() { b.copyFile(b.path2, b.path1, pr.remote, pr.o2, pr.o1) }, true
+	case s1 == stateUnchanged && s2 == stateAbsent:
+		// Only in path1's listing so copy it over
+		return func() { b.copyFile(b.path1, b.path2, pr.remote, pr.o1, nil) }, true
+	case s2 == stateUnchanged && s1 == stateAbsent:
+		return func() { b.copyFile(b.path2, b.path1, pr.remote, pr.o2, nil) }, true
+	case s1 == stateDeleted && s2 == stateUnchanged:
+		b.deletes[1]++
+		return func() { b.deleteFile(b.path2, pr.remote, pr.o2) }, true
+	case s2 == stateDeleted && s1 == stateUnchanged:
+		b.deletes[0]++
+		return func() { b.deleteFile(b.path1, pr.remote, pr.o1) }, true
+	}
+	return nil, false
+}
+
+// checkDeletes checks the proportion of files to be deleted on each
+// path is within limits
+func (b *bisync) checkDeletes() error {
+	if b.opt.Force || b.opt.Resync {
+		return nil
+	}
+	for i, p := range []*path{b.path1, b.path2} {
+		total := len(p.prev)
+		if total == 0 || b.deletes[i] == 0 {
+			continue
+		}
+		if b.deletes[i]*100 > b.opt.MaxDeletePercent*total {
+			return errors.Errorf("too many deletes on %s (%d of %d files is over --max-delete-percent %d%%) - use --force to go ahead", p.name, b.deletes[i], total, b.opt.MaxDeletePercent)
+		}
+	}
+	return nil
+}
+
+// run the actions using --transfers workers
+func (b *bisync) run(actions []func()) {
+	in := make(chan func(), len(actions))
+	for _, action := range actions {
+		in <- action
+	}
+	close(in)
+	var wg sync.WaitGroup
+	wg.Add(fs.Config.Transfers)
+	for i := 0; i < fs.Config.Transfers; i++ {
+		go func() {
+			defer wg.Done()
+			for action := range in {
+				action()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Bisync syncs f1 and f2 in both directions using the listings from
+// the last run to work out what has changed on each side.
+func Bisync(ctx context.Context, f1, f2 fs.Fs, opt *Options) (err error) {
+	b := &bisync{
+		ctx:   ctx,
+		opt:   opt,
+		names: map[string]struct{}{},
+		path1: &path{f: f1, name: "path1", suffix: ".conflict1", precision: f1.Precision()},
+		path2: &path{f: f2, name: "path2", suffix: ".conflict2", precision: f2.Precision()},
+	}
+
+	err = os.MkdirAll(opt.Workdir, 0700)
+	if err != nil {
+		return errors.Wrap(err, "failed to make bisync workdir")
+	}
+	listing1, listing2, lockFile := listingPaths(opt.Workdir, f1, f2)
+
+	// Stop two runs on the same paths at once
+	lock, err := os.OpenFile(lockFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			return errors.Errorf("bisync already running on these paths - remove %q if it isn't", lockFile)
+		}
+		return errors.Wrap(err, "failed to make bisync lock file")
+	}
+	_ = lock.Close()
+	defer func() {
+		removeErr := os.Remove(lockFile)
+		if removeErr != nil && err == nil {
+			err = errors.Wrap(removeErr, "failed to remove bisync lock file")
+		}
+	}()
+
+	if !opt.Resync {
+		err = b.loadListings(listing1, listing2)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Find the files on both paths
+	m := &march.March{
+		Ctx:      ctx,
+		Fsrc:     f1,
+		Fdst:     f2,
+		Callback: b,
+	}
+	m.Run()
+	if b.errors > 0 {
+		return errors.Errorf("bisync failed with %d errors while listing - listings not updated", b.errors)
+	}
+
+	// Start the new listings from what is there now
+	b.path1.next, b.path2.next = listing{}, listing{}
+	sort.Slice(b.pairs, func(i, j int) bool { return b.pairs[i].remote < b.pairs[j].remote })
+	for _, pr := range b.pairs {
+		if pr.o1 != nil {
+			b.path1.next[pr.remote] = newFileInfo(pr.o1)
+		}
+		if pr.o2 != nil {
+			b.path2.next[pr.remote] = newFileInfo(pr.o2)
+		}
+	}
+
+	// Work out what to do
+	var actions []func()
+	for _, pr := range b.pairs {
+		if action, needed := b.decide(pr); needed {
+			actions = append(actions, action)
+		}
+	}
+	if err = b.checkDeletes(); err != nil {
+		return err
+	}
+	if len(actions) == 0 {
+		fs.Infof(nil, "Bisync: no changes found")
+	}
+	b.run(actions)
+
+	if b.errors > 0 {
+		return errors.Errorf("bisync failed with %d errors - listings not updated so run again to retry", b.errors)
+	}
+	if fs.Config.DryRun {
+		fs.Logf(nil, "Not updating listings as --dry-run")
+		return nil
+	}
+	err = b.path1.next.save(listing1)
+	if err == nil {
+		err = b.path2.next.save(listing2)
+	}
+	return err
+}
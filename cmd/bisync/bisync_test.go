@@ -0,0 +1,280 @@
+package bisync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/ncw/rclone/backend/all"
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Some times used in the tests
+var (
+	t1 = fstest.Time("2001-02-03T04:05:06.499999999Z")
+	t2 = fstest.Time("2011-12-25T12:59:59.123456789Z")
+	t3 = fstest.Time("2011-12-30T12:59:59.000000000Z")
+)
+
+func TestMain(m *testing.M) {
+	fstest.TestMain(m)
+}
+
+// newOpt makes options with a temporary workdir returning a function
+// to tidy it up
+func newOpt(t *testing.T) (*Options, func()) {
+	dir, err := ioutil.TempDir("", "rclone-bisync-test")
+	require.NoError(t, err)
+	opt := DefaultOpt
+	opt.Workdir = dir
+	return &opt, func() {
+		_ = os.RemoveAll(dir)
+	}
+}
+
+// deleteObject removes remote from f
+func deleteObject(t *testing.T, f fs.Fs, remote string) {
+	o, err := f.NewObject(context.Background(), remote)
+	require.NoError(t, err)
+	require.NoError(t, o.Remove(context.Background()))
+}
+
+func TestBisyncNeedsResync(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	opt, cleanup := newOpt(t)
+	defer cleanup()
+	r.Mkdir(r.Fremote)
+
+	err := Bisync(context.Background(), r.Flocal, r.Fremote, opt)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--resync")
+}
+
+func TestBisync(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	opt, cleanup := newOpt(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Resync copies files both ways with path1 winning differences
+	file1 := r.WriteFile("file1", "path1 only", t1)
+	file2 := r.WriteObject("dir/file2", "path2 only", t1)
+	file3 := r.WriteFile("file3", "path1 version", t1)
+	r.WriteObject("file3", "path2 version is different", t2)
+	opt.Resync = true
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	fstest.CheckItems(t, r.Flocal, file1, file2, file3)
+	fstest.CheckItems(t, r.Fremote, file1, file2, file3)
+
+	// A run with no changes does nothing
+	opt.Resync = false
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	fstest.CheckItems(t, r.Flocal, file1, file2, file3)
+	fstest.CheckItems(t, r.Fremote, file1, file2, file3)
+
+	// Changes, deletes and new files propagate both ways
+	file1 = r.WriteFile("file1", "path1 changed", t2)
+	deleteObject(t, r.Fremote, "dir/file2")
+	file4 := r.WriteObject("file4", "new on path2", t2)
+	file5 := r.WriteFile("dir/file5", "new on path1", t2)
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	fstest.CheckItems(t, r.Flocal, file1, file3, file4, file5)
+	fstest.CheckItems(t, r.Fremote, file1, file3, file4, file5)
+
+	// A change wins over a delete
+	require.NoError(t, os.Remove(filepath.Join(r.LocalName, "file4")))
+	file4 = r.WriteObject("file4", "changed on path2", t3)
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	fstest.CheckItems(t, r.Flocal, file1, file3, file4, file5)
+	fstest.CheckItems(t, r.Fremote, file1, file3, file4, file5)
+
+	// Identical changes on both sides aren't a conflict
+	file3 = r.WriteFile("file3", "same change", t3)
+	r.WriteObject("file3", "same change", t3)
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	fstest.CheckItems(t, r.Flocal, file1, file3, file4, file5)
+	fstest.CheckItems(t, r.Fremote, file1, file3, file4, file5)
+}
+
+func TestBisyncConflict(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	opt, cleanup := newOpt(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	file1 := r.WriteFile("file1", "original", t1)
+	opt.Resync = true
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	fstest.CheckItems(t, r.Fremote, file1)
+
+	// Change both sides - path2 is newer so path1's file loses
+	r.WriteFile("file1", "path1 change", t2)
+	file1 = r.WriteObject("file1", "path2 change is newer", t3)
+	conflict1 := fstest.NewItem("file1.conflict1", "path1 change", t2)
+	opt.Resync = false
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	fstest.CheckItems(t, r.Flocal, file1, conflict1)
+	fstest.CheckItems(t, r.Fremote, file1, conflict1)
+
+	// Check the listings were updated so the next run is clean
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	fstest.CheckItems(t, r.Flocal, file1, conflict1)
+	fstest.CheckItems(t, r.Fremote, file1, conflict1)
+}
+
+func TestBisyncConflictTwice(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	opt, cleanup := newOpt(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	file1 := r.WriteFile("file1", "original", t1)
+	opt.Resync = true
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	opt.Resync = false
+
+	// First conflict makes file1.conflict1
+	r.WriteFile("file1", "path1 change", t2)
+	r.WriteObject("file1", "path2 change is newer", t3)
+	conflict1 := fstest.NewItem("file1.conflict1", "path1 change", t2)
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+
+	// Second conflict mustn't overwrite it
+	r.WriteFile("file1", "path1 change again", t1)
+	file1 = r.WriteObject("file1", "path2 change again is newer", t2)
+	conflict2 := fstest.NewItem("file1.conflict1-2", "path1 change again", t1)
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	fstest.CheckItems(t, r.Flocal, file1, conflict1, conflict2)
+	fstest.CheckItems(t, r.Fremote, file1, conflict1, conflict2)
+}
+
+func TestBisyncMaxDelete(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	opt, cleanup := newOpt(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	file1 := r.WriteFile("file1", "one", t1)
+	file2 := r.WriteFile("file2", "two", t1)
+	file3 := r.WriteFile("file3", "three", t1)
+	opt.Resync = true
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	opt.Resync = false
+
+	// Deleting 2 of 3 files is over the limit so nothing happens
+	require.NoError(t, os.Remove(filepath.Join(r.LocalName, "file1")))
+	require.NoError(t, os.Remove(filepath.Join(r.LocalName, "file2")))
+	err := Bisync(ctx, r.Flocal, r.Fremote, opt)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "too many deletes")
+	fstest.CheckItems(t, r.Fremote, file1, file2, file3)
+
+	// Unless forced
+	opt.Force = true
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	fstest.CheckItems(t, r.Fremote, file3)
+}
+
+func TestBisyncDryRun(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	opt, cleanup := newOpt(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	file1 := r.WriteFile("file1", "one", t1)
+	opt.Resync = true
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	opt.Resync = false
+
+	file2 := r.WriteFile("file2", "two", t1)
+	fs.Config.DryRun = true
+	err := Bisync(ctx, r.Flocal, r.Fremote, opt)
+	fs.Config.DryRun = false
+	require.NoError(t, err)
+	fstest.CheckItems(t, r.Fremote, file1)
+
+	// The listings weren't updated so a real run copies the file
+	require.NoError(t, Bisync(ctx, r.Flocal, r.Fremote, opt))
+	fstest.CheckItems(t, r.Fremote, file1, file2)
+}
+
+func TestBisyncLocked(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	opt, cleanup := newOpt(t)
+	defer cleanup()
+
+	_, _, lockFile := listingPaths(opt.Workdir, r.Flocal, r.Fremote)
+	require.NoError(t, ioutil.WriteFile(lockFile, nil, 0600))
+	opt.Resync = true
+	err := Bisync(context.Background(), r.Flocal, r.Fremote, opt)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already running")
+}
+
+func TestListingPaths(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-bisync-test")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	newFs := func(name string) fs.Fs {
+		f, err := fs.NewFs(filepath.Join(dir, name))
+		require.NoError(t, err)
+		return f
+	}
+
+	// Paths which are the same once made safe for a file name
+	// must still get different listings
+	f1, f2, f3 := newFs("a b"), newFs("a_b"), newFs("c")
+	path1, path2, lock := listingPaths(dir, f1, f3)
+	otherPath1, otherPath2, otherLock := listingPaths(dir, f2, f3)
+	assert.NotEqual(t, path1, otherPath1)
+	assert.NotEqual(t, path2, otherPath2)
+	assert.NotEqual(t, lock, otherLock)
+
+	// The same paths always get the same listings
+	againPath1, _, _ := listingPaths(dir, newFs("a b"), f3)
+	assert.Equal(t, path1, againPath1)
+}
+
+func TestListing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-bisync-test")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	name := filepath.Join(dir, "test.lst")
+
+	_, err = loadListing(name)
+	assert.True(t, os.IsNotExist(err))
+
+	l := listing{
+		"file":              {size: 1, modTime: t1},
+		"dir/with space":    {size: 0, modTime: t2},
+		"quote\" newline\n": {size: 123456789, modTime: t3},
+	}
+	require.NoError(t, l.save(name))
+	got, err := loadListing(name)
+	require.NoError(t, err)
+	require.Len(t, got, len(l))
+	for remote, fi := range l {
+		assert.Equal(t, fi.size, got[remote].size, remote)
+		assert.True(t, fi.modTime.Equal(got[remote].modTime), remote)
+	}
+
+	require.NoError(t, ioutil.WriteFile(name, []byte("potato\n"), 0600))
+	_, err = loadListing(name)
+	assert.Error(t, err)
+}
@@ -0,0 +1,164 @@
+package bisync
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
+)
+
+// listingHeader is the first line of a listing file
+const listingHeader = "# rclone bisync listing v1"
+
+// fileInfo is what is remembered about each file between runs
+type fileInfo struct {
+	size    int64
+	modTime time.Time
+}
+
+// newFileInfo makes a fileInfo from o
+func newFileInfo(o fs.ObjectInfo) fileInfo {
+	return fileInfo{
+		size:    o.Size(),
+		modTime: o.ModTime(),
+	}
+}
+
+// changed returns true if o is different from the fileInfo, comparing
+// modification times to within precision
+func (fi fileInfo) changed(o fs.ObjectInfo, precision time.Duration) bool {
+	if fi.size != o.Size() {
+		return true
+	}
+	if precision == fs.ModTimeNotSupported {
+		return false
+	}
+	dt := o.ModTime().Sub(fi.modTime)
+	return dt >= precision || dt <= -precision
+}
+
+// listing is the state of one side of the sync keyed by path
+type listing map[string]fileInfo
+
+// listingPaths returns the file names used to store the listings for
+// the path pair f1, f2 in workdir and the lock file
+//
+// The names start with the paths made safe for a file name so they
+// can be found easily, but as different paths can come out the same
+// they end with a hash of the actual paths.
+func listingPaths(workdir string, f1, f2 fs.Fs) (path1, path2, lock string) {
+	root1 := f1.Name() + ":" + f1.Root()
+	root2 := f2.Name() + ":" + f2.Root()
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-':
+			return r
+		}
+		return '_'
+	}, root1+".."+root2)
+	sum := sha256.Sum256([]byte(root1 + "\x00" + root2))
+	base := filepath.Join(workdir, fmt.Sprintf("%s-%x", name, sum[:8]))
+	return base + ".path1.lst", base + ".path2.lst", base + ".lck"
+}
+
+// loadListing reads the listing from the file name
+//
+// It returns an error satisfying os.IsNotExist if there is no listing
+func loadListing(name string) (l listing, err error) {
+	in, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer fs.CheckClose(in, &err)
+	l = listing{}
+	scanner := bufio.NewScanner(in)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if lineNumber == 1 {
+			if line != listingHeader {
+				return nil, errors.Errorf("%s: not a bisync listing file", name)
+			}
+			continue
+		}
+		// Each line is: size modtime "path"
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) != 3 {
+			return nil, errors.Errorf("%s:%d: bad line", name, lineNumber)
+		}
+		size, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s:%d: bad size", name, lineNumber)
+		}
+		modTime, err := time.Parse(time.RFC3339Nano, fields[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s:%d: bad modification time", name, lineNumber)
+		}
+		remote, err := strconv.Unquote(fields[2])
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s:%d: bad path", name, lineNumber)
+		}
+		l[remote] = fileInfo{size: size, modTime: modTime}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "%s: failed to read listing", name)
+	}
+	if lineNumber == 0 {
+		return nil, errors.Errorf("%s: empty listing file", name)
+	}
+	return l, nil
+}
+
+// save writes the listing to the file name
+//
+// The listing is written to a temporary file first then renamed so
+// an interrupted save doesn't leave a partial listing
+func (l listing) save(name string) (err error) {
+	remotes := make([]string, 0, len(l))
+	for remote := range l {
+		remotes = append(remotes, remote)
+	}
+	sort.Strings(remotes)
+	out, err := ioutil.TempFile(filepath.Dir(name), filepath.Base(name))
+	if err != nil {
+		return errors.Wrap(err, "failed to save listing")
+	}
+	defer func() {
+		if err != nil {
+			_ = out.Close()
+			_ = os.Remove(out.Name())
+		}
+	}()
+	w := bufio.NewWriter(out)
+	_, err = fmt.Fprintln(w, listingHeader)
+	if err != nil {
+		return errors.Wrap(err, "failed to save listing")
+	}
+	for _, remote := range remotes {
+		fi := l[remote]
+		_, err = fmt.Fprintf(w, "%d %s %s\n", fi.size, fi.modTime.Format(time.RFC3339Nano), strconv.Quote(remote))
+		if err != nil {
+			return errors.Wrap(err, "failed to save listing")
+		}
+	}
+	if err = w.Flush(); err != nil {
+		return errors.Wrap(err, "failed to save listing")
+	}
+	if err = out.Close(); err != nil {
+		return errors.Wrap(err, "failed to save listing")
+	}
+	if err = os.Rename(out.Name(), name); err != nil {
+		return errors.Wrap(err, "failed to save listing")
+	}
+	return nil
+}
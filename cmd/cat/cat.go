@@ -1,6 +1,7 @@
 package cat
 
 import (
+	"context"
 	"io"
 	"io/ioutil"
 	"log"
@@ -74,7 +75,7 @@ Note that if offset is negative it will count from the end, so
 			w = ioutil.Discard
 		}
 		cmd.Run(false, false, command, func() error {
-			return operations.Cat(fsrc, w, offset, count)
+			return operations.Cat(context.Background(), fsrc, w, offset, count)
 		})
 	},
 }
@@ -1,6 +1,7 @@
 package check
 
 import (
+	"context"
 	"github.com/ncw/rclone/cmd"
 	"github.com/ncw/rclone/fs/operations"
 	"github.com/spf13/cobra"
@@ -16,6 +17,7 @@ func init() {
 	cmd.Root.AddCommand(commandDefintion)
 	commandDefintion.Flags().BoolVarP(&download, "download", "", download, "Check by downloading rather than with hash.")
 	commandDefintion.Flags().BoolVarP(&oneway, "one-way", "", oneway, "Check one way only, source files must exist on remote")
+	AddReportFlags(commandDefintion.Flags())
 }
 
 var commandDefintion = &cobra.Command{
@@ -37,15 +39,25 @@ to check all the data.
 If you supply the --one-way flag, it will only check that files in source
 match the files in destination, not the other way around. Meaning extra files in
 destination that are not in the source will not trigger an error.
-`,
+` + ReportsHelp,
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(2, 2, command, args)
 		fsrc, fdst := cmd.NewFsSrcDst(args)
-		cmd.Run(false, false, command, func() error {
+		cmd.Run(false, false, command, func() (err error) {
+			ctx, closeReports, err := OpenReports(context.Background())
+			if err != nil {
+				return err
+			}
+			defer func() {
+				closeErr := closeReports()
+				if err == nil {
+					err = closeErr
+				}
+			}()
 			if download {
-				return operations.CheckDownload(fdst, fsrc, oneway)
+				return operations.CheckDownload(ctx, fdst, fsrc, oneway)
 			}
-			return operations.Check(fdst, fsrc, oneway)
+			return operations.Check(ctx, fdst, fsrc, oneway)
 		})
 	},
 }
@@ -0,0 +1,103 @@
+package check
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/operations"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+// Report file names set by the flags
+var (
+	combinedReport     = ""
+	missingOnSrcReport = ""
+	missingOnDstReport = ""
+	matchReport        = ""
+	differReport       = ""
+	errorReport        = ""
+)
+
+// AddReportFlags adds the flags for the difference reports to flags
+func AddReportFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&combinedReport, "combined", "", combinedReport, "Make a combined report of changes to this file")
+	flags.StringVarP(&missingOnSrcReport, "missing-on-src", "", missingOnSrcReport, "Report all files missing from the source to this file")
+	flags.StringVarP(&missingOnDstReport, "missing-on-dst", "", missingOnDstReport, "Report all files missing from the destination to this file")
+	flags.StringVarP(&matchReport, "match", "", matchReport, "Report all matching files to this file")
+	flags.StringVarP(&differReport, "differ", "", differReport, "Report all non-matching files to this file")
+	flags.StringVarP(&errorReport, "error", "", errorReport, "Report all files with errors (hashing or reading) to this file")
+}
+
+// ReportsHelp describes the report flags for the command help
+const ReportsHelp = `
+If you supply the --combined flag, it will write a file (or stdout if
+this is "-") containing all the files with a symbol and a space in
+front of each to show what happened to them:
+
+- ` + "`= path`" + ` means path was found in source and destination and was identical
+- ` + "`- path`" + ` means path was missing on the source, so only in the destination
+- ` + "`+ path`" + ` means path was missing on the destination, so only in the source
+- ` + "`* path`" + ` means path was present in source and destination but different.
+- ` + "`! path`" + ` means there was an error reading, hashing or transferring path.
+
+The --match, --differ, --missing-on-src, --missing-on-dst and --error
+flags write the paths, one per line, of just those files to the file
+named (or stdout if it is "-").  These can be the same file.
+`
+
+// OpenReports opens the report files set by the flags, returning ctx
+// with them set for operations.GetReports and a function to close
+// them.  The close function returns the first error writing or
+// closing the reports.
+func OpenReports(ctx context.Context) (_ context.Context, closeReports func() error, err error) {
+	var (
+		reports operations.Reports
+		files   []*os.File
+		byName  = map[string]io.Writer{}
+	)
+	closeReports = func() (err error) {
+		err = reports.Err()
+		for _, file := range files {
+			fs.CheckClose(file, &err)
+		}
+		return err
+	}
+	for _, report := range []struct {
+		name string
+		w    *io.Writer
+	}{
+		{combinedReport, &reports.Combined},
+		{missingOnSrcReport, &reports.MissingOnSrc},
+		{missingOnDstReport, &reports.MissingOnDst},
+		{matchReport, &reports.Match},
+		{differReport, &reports.Differ},
+		{errorReport, &reports.Error},
+	} {
+		if report.name == "" {
+			continue
+		}
+		w, found := byName[report.name]
+		if !found {
+			if report.name == "-" {
+				w = os.Stdout
+			} else {
+				file, err := os.Create(report.name)
+				if err != nil {
+					_ = closeReports()
+					return ctx, nil, errors.Wrap(err, "failed to create report file")
+				}
+				files = append(files, file)
+				w = file
+			}
+			byName[report.name] = w
+		}
+		*report.w = w
+	}
+	if len(byName) == 0 {
+		return ctx, closeReports, nil
+	}
+	return operations.WithReports(ctx, &reports), closeReports, nil
+}
@@ -0,0 +1,80 @@
+package checksum
+
+import (
+	"context"
+
+	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/cmd/check"
+	"github.com/ncw/rclone/fs/hash"
+	"github.com/ncw/rclone/fs/operations"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Globals
+var (
+	download = false
+	oneway   = false
+	hashType = hash.None
+)
+
+func init() {
+	cmd.Root.AddCommand(commandDefintion)
+	flags := commandDefintion.Flags()
+	flags.BoolVarP(&download, "download", "", download, "Check by hashing the contents of the files rather than asking the remote.")
+	flags.BoolVarP(&oneway, "one-way", "", oneway, "Check one way only, files on the remote needn't be in the SUM file")
+	flags.VarP(&hashType, "hash", "", "Hash type of the SUM file, eg MD5 or SHA-1 (default guess from the hash length)")
+	check.AddReportFlags(flags)
+}
+
+var commandDefintion = &cobra.Command{
+	Use:   "checksum SUMFILE remote:path",
+	Short: `Checks the files in the remote against a SUM file.`,
+	Long: `
+Checks that the hashes of the files in remote:path match those in
+SUMFILE, a file in the format produced by md5sum, sha1sum or rclone
+hashsum.  SUMFILE may be a local file or on any remote.  It logs a
+report of files which don't match and doesn't alter anything.
+
+The hash type is worked out from the length of the hashes in SUMFILE
+(MD5 or SHA-1) unless you give it with --hash, eg
+
+    rclone checksum --hash QuickXorHash QXSUMS remote:path
+
+If the remote doesn't support the hash type, or you supply the
+--download flag, rclone will download the files and calculate the
+hashes from their contents.
+
+Files in SUMFILE which aren't in remote:path are reported as missing.
+Files in remote:path which aren't in SUMFILE are reported too unless
+you supply the --one-way flag.  SUMFILE itself is ignored if it is
+inside remote:path.
+
+The filtering flags (eg --include and --exclude) apply to the files in
+remote:path and to the names in SUMFILE.
+
+SUMFILE is treated as the source for the report flags below.
+` + check.ReportsHelp,
+	RunE: func(command *cobra.Command, args []string) error {
+		cmd.CheckArgs(2, 2, command, args)
+		fsum, sumFile := cmd.NewFsFile(args[0])
+		if sumFile == "" {
+			return errors.Errorf("SUMFILE %q must be a file", args[0])
+		}
+		fsrc := cmd.NewFsSrc(args[1:])
+		cmd.Run(false, false, command, func() (err error) {
+			ctx, closeReports, err := check.OpenReports(context.Background())
+			if err != nil {
+				return err
+			}
+			defer func() {
+				closeErr := closeReports()
+				if err == nil {
+					err = closeErr
+				}
+			}()
+			return operations.CheckSum(ctx, fsrc, fsum, sumFile, hashType, oneway, download)
+		})
+		return nil
+	},
+}
@@ -1,11 +1,14 @@
 package config
 
 import (
-	"errors"
+	"encoding/json"
+	"os"
 
 	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/fs"
 	"github.com/ncw/rclone/fs/config"
 	"github.com/ncw/rclone/fs/rc"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -20,8 +23,69 @@ func init() {
 	configCommand.AddCommand(configUpdateCommand)
 	configCommand.AddCommand(configDeleteCommand)
 	configCommand.AddCommand(configPasswordCommand)
+	for _, command := range []*cobra.Command{configCreateCommand, configUpdateCommand} {
+		flags := command.Flags()
+		flags.BoolVarP(&updateRemoteOpt.NonInteractive, "non-interactive", "", false, "Don't interact with the user, print the next question as JSON instead.")
+		flags.StringVarP(&updateRemoteOpt.State, "state", "", "", "State to continue the non-interactive config from.")
+		flags.StringVarP(&updateRemoteOpt.Result, "result", "", "", "Answer to the last question in the non-interactive config.")
+	}
+}
+
+// Options for create and update set by the flags
+var updateRemoteOpt config.UpdateRemoteOpt
+
+// nonInteractive returns true if the non-interactive config is in use
+func nonInteractive() bool {
+	return updateRemoteOpt.NonInteractive || updateRemoteOpt.State != ""
+}
+
+// showConfigOut shows the remote when the config is done, or the next
+// question as JSON when doing the non-interactive config
+func showConfigOut(name string, out *fs.ConfigOut) error {
+	if !nonInteractive() {
+		config.ShowRemote(name)
+		return nil
+	}
+	if out == nil {
+		out = &fs.ConfigOut{}
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "    ")
+	err := enc.Encode(out)
+	if err != nil {
+		return errors.Wrap(err, "failed to write config question")
+	}
+	return nil
 }
 
+const nonInteractiveHelp = `
+If --non-interactive is set then rclone doesn't ask the questions
+needed to finish the config of backends which need them (eg to get an
+OAuth token).  Instead it prints the next question as JSON like this:
+
+    {
+        "state": "oauth_token",
+        "option": {
+            "Name": "token",
+            "Help": "Visit the following link...",
+            ...
+        },
+        "authURL": "https://accounts.google.com/o/oauth2/auth?..."
+    }
+
+Answer it by running the same command again with the state passed
+with --state and the answer with --result, eg
+
+    rclone config update myremote --state oauth_token --result CODE
+
+The config is complete when the state printed is empty.  The "authURL"
+is set if the question needs a web browser to authorize rclone and
+"error" is set if the last answer wasn't accepted.  The answer to an
+OAuth question can be the verification code from the authURL or the
+result of "rclone authorize" run on a machine with a web browser.
+`
+
 var configCommand = &cobra.Command{
 	Use:   "config",
 	Short: `Enter an interactive configuration session.`,
@@ -93,19 +157,18 @@ For example to make a swift remote of name myremote using auto config
 you would do:
 
     rclone config create myremote swift env_auth true
-`,
+` + nonInteractiveHelp,
 	RunE: func(command *cobra.Command, args []string) error {
 		cmd.CheckArgs(2, 256, command, args)
 		in, err := argsToMap(args[2:])
 		if err != nil {
 			return err
 		}
-		err = config.CreateRemote(args[0], args[1], in)
+		out, err := config.CreateRemote(args[0], args[1], in, updateRemoteOpt)
 		if err != nil {
 			return err
 		}
-		config.ShowRemote(args[0])
-		return nil
+		return showConfigOut(args[0], out)
 	},
 }
 
@@ -119,19 +182,22 @@ in pairs of <key> <value>.
 For example to update the env_auth field of a remote of name myremote you would do:
 
     rclone config update myremote swift env_auth true
-`,
+` + nonInteractiveHelp,
 	RunE: func(command *cobra.Command, args []string) error {
-		cmd.CheckArgs(3, 256, command, args)
+		if nonInteractive() {
+			cmd.CheckArgs(1, 256, command, args)
+		} else {
+			cmd.CheckArgs(3, 256, command, args)
+		}
 		in, err := argsToMap(args[1:])
 		if err != nil {
 			return err
 		}
-		err = config.UpdateRemote(args[0], in)
+		out, err := config.UpdateRemote(args[0], in, updateRemoteOpt)
 		if err != nil {
 			return err
 		}
-		config.ShowRemote(args[0])
-		return nil
+		return showConfigOut(args[0], out)
 	},
 }
 
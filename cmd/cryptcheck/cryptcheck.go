@@ -1,6 +1,7 @@
 package cryptcheck
 
 import (
+	"context"
 	"github.com/ncw/rclone/backend/crypt"
 	"github.com/ncw/rclone/cmd"
 	"github.com/ncw/rclone/fs"
@@ -36,6 +37,11 @@ using that to encrypt each file on the remote:.  It then checks the
 checksum of the underlying file on the cryptedremote: against the
 checksum of the file it has just encrypted.
 
+It works whatever filename_encoding the cryptedremote: uses and with
+files stored under a short name because their encrypted name is longer
+than max_name_length.  The sidecars holding the long names aren't
+checked themselves.
+
 Use it like this
 
     rclone cryptcheck /path/to/files encryptedremote:path
@@ -55,13 +61,13 @@ destination that are not in the source will not trigger an error.
 		cmd.CheckArgs(2, 2, command, args)
 		fsrc, fdst := cmd.NewFsSrcDst(args)
 		cmd.Run(false, true, command, func() error {
-			return cryptCheck(fdst, fsrc)
+			return cryptCheck(context.Background(), fdst, fsrc)
 		})
 	},
 }
 
 // cryptCheck checks the integrity of a crypted remote
-func cryptCheck(fdst, fsrc fs.Fs) error {
+func cryptCheck(ctx context.Context, fdst, fsrc fs.Fs) error {
 	// Check to see fcrypt is a crypt
 	fcrypt, ok := fdst.(*crypt.Fs)
 	if !ok {
@@ -79,36 +85,37 @@ func cryptCheck(fdst, fsrc fs.Fs) error {
 	//
 	// it returns true if differences were found
 	// it also returns whether it couldn't be hashed
-	checkIdentical := func(dst, src fs.Object) (differ bool, noHash bool) {
+	// and any error checking which has already been logged and counted
+	checkIdentical := func(ctx context.Context, dst, src fs.Object) (differ bool, noHash bool, err error) {
 		cryptDst := dst.(*crypt.Object)
 		underlyingDst := cryptDst.UnWrap()
-		underlyingHash, err := underlyingDst.Hash(hashType)
+		underlyingHash, err := underlyingDst.Hash(ctx, hashType)
 		if err != nil {
-			fs.CountError(err)
+			fs.CountError(ctx, err)
 			fs.Errorf(dst, "Error reading hash from underlying %v: %v", underlyingDst, err)
-			return true, false
+			return true, false, err
 		}
 		if underlyingHash == "" {
-			return false, true
+			return false, true, nil
 		}
-		cryptHash, err := fcrypt.ComputeHash(cryptDst, src, hashType)
+		cryptHash, err := fcrypt.ComputeHash(ctx, cryptDst, src, hashType)
 		if err != nil {
-			fs.CountError(err)
+			fs.CountError(ctx, err)
 			fs.Errorf(dst, "Error computing hash: %v", err)
-			return true, false
+			return true, false, err
 		}
 		if cryptHash == "" {
-			return false, true
+			return false, true, nil
 		}
 		if cryptHash != underlyingHash {
 			err = errors.Errorf("hashes differ (%s:%s) %q vs (%s:%s) %q", fdst.Name(), fdst.Root(), cryptHash, fsrc.Name(), fsrc.Root(), underlyingHash)
-			fs.CountError(err)
-			fs.Errorf(src, err.Error())
-			return true, false
+			fs.CountError(ctx, err)
+			fs.Errorf(src, "%v", err)
+			return true, false, nil
 		}
 		fs.Debugf(src, "OK")
-		return false, false
+		return false, false, nil
 	}
 
-	return operations.CheckFn(fcrypt, fsrc, checkIdentical, oneway)
+	return operations.CheckFn(ctx, fcrypt, fsrc, checkIdentical, oneway)
 }
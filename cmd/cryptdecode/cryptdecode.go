@@ -1,12 +1,14 @@
 package cryptdecode
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	"github.com/ncw/rclone/backend/crypt"
 	"github.com/ncw/rclone/cmd"
 	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/config/configstruct"
 	"github.com/ncw/rclone/fs/config/flags"
 	"github.com/spf13/cobra"
 )
@@ -31,6 +33,12 @@ a list of encrypted file names. List limit is 10 items.
 
 If you supply the --reverse flag, it will return encrypted file names.
 
+It understands all the filename_encoding settings of the remote.  If
+max_name_length is set then encrypted names which are too long are
+shown as the short name they are stored under.  Short names ending in
+".rclonelong" are decrypted by reading their sidecar from the remote -
+this is the only time the remote is accessed.
+
 use it like this
 
 	rclone cryptdecode encryptedremote: encryptedfilename1 encryptedfilename2
@@ -47,24 +55,45 @@ use it like this
 			if fsInfo.Name != "crypt" {
 				return errors.New("The remote needs to be of type \"crypt\"")
 			}
+			opt := new(crypt.Options)
+			err = configstruct.Set(config, opt)
+			if err != nil {
+				return err
+			}
 			cipher, err := crypt.NewCipher(config)
 			if err != nil {
 				return err
 			}
 			if Reverse {
-				return cryptEncode(cipher, args[1:])
+				return cryptEncode(cipher, opt.MaxNameLength, args[1:])
 			}
-			return cryptDecode(cipher, args[1:])
+			return cryptDecode(context.Background(), cipher, args[0], args[1:])
 		})
 	},
 }
 
 // cryptDecode returns the unencrypted file name
-func cryptDecode(cipher crypt.Cipher, args []string) error {
+//
+// The crypt remote is only made if a short name needs its encrypted
+// name read from the remote.
+func cryptDecode(ctx context.Context, cipher crypt.Cipher, remote string, args []string) error {
 	output := ""
 
+	var fcrypt *crypt.Fs
 	for _, encryptedFileName := range args {
-		fileName, err := cipher.DecryptFileName(encryptedFileName)
+		var fileName string
+		var err error
+		if crypt.HasLongName(cipher, encryptedFileName) {
+			if fcrypt == nil {
+				fcrypt, err = newCryptFs(remote)
+				if err != nil {
+					return err
+				}
+			}
+			fileName, err = fcrypt.DecryptStoredName(ctx, encryptedFileName)
+		} else {
+			fileName, err = cipher.DecryptFileName(encryptedFileName)
+		}
 		if err != nil {
 			output += fmt.Sprintln(encryptedFileName, "\t", "Failed to decrypt")
 		} else {
@@ -72,21 +101,35 @@ func cryptDecode(cipher crypt.Cipher, args []string) error {
 		}
 	}
 
-	fmt.Printf(output)
+	fmt.Print(output)
 
 	return nil
 }
 
+// newCryptFs makes the crypt remote to read the encrypted names of
+// short names from
+func newCryptFs(remote string) (*crypt.Fs, error) {
+	f, err := fs.NewFs(remote)
+	if err != nil {
+		return nil, err
+	}
+	fcrypt, ok := f.(*crypt.Fs)
+	if !ok {
+		return nil, errors.New("The remote needs to be of type \"crypt\"")
+	}
+	return fcrypt, nil
+}
+
 // cryptEncode returns the encrypted file name
-func cryptEncode(cipher crypt.Cipher, args []string) error {
+func cryptEncode(cipher crypt.Cipher, maxNameLength int, args []string) error {
 	output := ""
 
 	for _, fileName := range args {
-		encryptedFileName := cipher.EncryptFileName(fileName)
+		encryptedFileName := crypt.StoredFileName(cipher, maxNameLength, fileName)
 		output += fmt.Sprintln(fileName, "\t", encryptedFileName)
 	}
 
-	fmt.Printf(output)
+	fmt.Print(output)
 
 	return nil
 }
@@ -1,6 +1,7 @@
 package delete
 
 import (
+	"context"
 	"github.com/ncw/rclone/cmd"
 	"github.com/ncw/rclone/fs/operations"
 	"github.com/spf13/cobra"
@@ -39,7 +40,7 @@ delete all files bigger than 100MBytes.
 		cmd.CheckArgs(1, 1, command, args)
 		fsrc := cmd.NewFsSrc(args)
 		cmd.Run(true, false, command, func() error {
-			return operations.Delete(fsrc)
+			return operations.Delete(context.Background(), fsrc)
 		})
 	},
 }
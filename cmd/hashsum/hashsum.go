@@ -1,18 +1,30 @@
 package hashsum
 
 import (
-	"errors"
+	"context"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/fs"
 	"github.com/ncw/rclone/fs/hash"
 	"github.com/ncw/rclone/fs/operations"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
+// Globals
+var (
+	download       = false
+	outputFileName = ""
+)
+
 func init() {
 	cmd.Root.AddCommand(commandDefinition)
+	flags := commandDefinition.Flags()
+	flags.BoolVarP(&download, "download", "", download, "Calculate the hashes from the contents of the files rather than asking the remote.")
+	flags.StringVarP(&outputFileName, "output-file", "", outputFileName, "Output hashsums to a file rather than the terminal.")
 }
 
 var commandDefinition = &cobra.Command{
@@ -35,6 +47,13 @@ Run without a hash to see the list of supported hashes, eg
 Then
 
     $ rclone hashsum MD5 remote:path
+
+If you supply the --download flag, rclone will download the files
+and calculate the hashes from their contents.  Use this if the remote
+doesn't support the hash or you don't trust the hashes it stores.
+
+Use --output-file to write the hashes to a file, eg to check them
+later with rclone checksum.
 `,
 	RunE: func(command *cobra.Command, args []string) error {
 		cmd.CheckArgs(0, 2, command, args)
@@ -54,8 +73,48 @@ Then
 		}
 		fsrc := cmd.NewFsSrc(args[1:])
 		cmd.Run(false, false, command, func() error {
-			return operations.HashLister(ht, fsrc, os.Stdout)
+			return HashSum(context.Background(), ht, fsrc, outputFileName)
 		})
 		return nil
 	},
 }
+
+// errorWriter wraps an io.Writer remembering the first error
+type errorWriter struct {
+	w   io.Writer
+	err error
+}
+
+// Write implements io.Writer
+func (ew *errorWriter) Write(p []byte) (n int, err error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+	n, err = ew.w.Write(p)
+	ew.err = err
+	return n, err
+}
+
+// HashSum writes the hashes of type ht of the objects in fsrc to the
+// file outputFileName, or to stdout if it is empty.
+func HashSum(ctx context.Context, ht hash.Type, fsrc fs.Fs, outputFileName string) (err error) {
+	var out io.Writer = os.Stdout
+	if outputFileName != "" {
+		var outFile *os.File
+		outFile, err = os.Create(outputFileName)
+		if err != nil {
+			return errors.Wrap(err, "failed to create output file")
+		}
+		defer fs.CheckClose(outFile, &err)
+		out = outFile
+	}
+	ew := &errorWriter{w: out}
+	err = operations.HashLister(ctx, ht, download, fsrc, ew)
+	if err != nil {
+		return err
+	}
+	if ew.err != nil {
+		return errors.Wrap(ew.err, "failed to write hashes")
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package hashsum
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/ncw/rclone/backend/local"
+	"github.com/ncw/rclone/fs/hash"
+	"github.com/ncw/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	t1 = fstest.Time("2017-02-03T04:05:06.499999999Z")
+)
+
+// TestMain drives the tests
+func TestMain(m *testing.M) {
+	fstest.TestMain(m)
+}
+
+func TestHashSumOutputFile(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	file1 := r.WriteObject("potato", "hello world", t1)
+	fstest.CheckItems(t, r.Fremote, file1)
+
+	dir, err := ioutil.TempDir("", "rclone-hashsum-test")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	outputFile := filepath.Join(dir, "MD5SUMS")
+
+	err = HashSum(ctx, hash.MD5, r.Fremote, outputFile)
+	require.NoError(t, err)
+	data, err := ioutil.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Equal(t, "5eb63bbbe01eeed093cb22bb8f5acdc3  potato\n", string(data))
+}
+
+func TestHashSumOutputFileWriteError(t *testing.T) {
+	if _, err := os.Stat("/dev/full"); err != nil {
+		t.Skip("need /dev/full to test write errors")
+	}
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	file1 := r.WriteObject("potato", "hello world", t1)
+	fstest.CheckItems(t, r.Fremote, file1)
+
+	err := HashSum(ctx, hash.MD5, r.Fremote, "/dev/full")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to write hashes")
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/ncw/rclone/fs"
@@ -247,4 +248,49 @@ func showBackend(name string) {
 			fmt.Printf("\n")
 		}
 	}
+	ShowBackendCommands(backend)
+}
+
+// ShowBackendCommands shows the help for the commands of the backend
+// in markdown format if it has any
+func ShowBackendCommands(backend *fs.RegInfo) {
+	if len(backend.CommandHelp) == 0 {
+		return
+	}
+	fmt.Printf("### Backend commands\n\n")
+	fmt.Printf(`Here are the commands specific to the %s backend.
+
+Run them with
+
+    rclone backend COMMAND remote:
+
+The help below will explain what arguments each command takes.
+
+See [the "rclone backend" command](/commands/rclone_backend/) for more
+info on how to pass options and arguments.
+
+These can be run on a running backend using the rc command
+[backend/command](/rc/#backend/command).
+
+`, backend.Name)
+	for _, cmd := range backend.CommandHelp {
+		fmt.Printf("#### %s\n\n", cmd.Name)
+		fmt.Printf("%s\n\n", cmd.Short)
+		fmt.Printf("    rclone backend %s remote: [options] [<arguments>+]\n\n", cmd.Name)
+		if cmd.Long != "" {
+			fmt.Printf("%s\n\n", strings.TrimSpace(cmd.Long))
+		}
+		if len(cmd.Opts) != 0 {
+			fmt.Printf("Options:\n\n")
+			keys := make([]string, 0, len(cmd.Opts))
+			for key := range cmd.Opts {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				fmt.Printf("- %q: %s\n", key, cmd.Opts[key])
+			}
+			fmt.Printf("\n")
+		}
+	}
 }
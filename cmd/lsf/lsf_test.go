@@ -2,6 +2,7 @@ package lsf
 
 import (
 	"bytes"
+	"context"
 	"testing"
 
 	_ "github.com/ncw/rclone/backend/local"
@@ -13,13 +14,14 @@ import (
 )
 
 func TestDefaultLsf(t *testing.T) {
+	ctx := context.Background()
 	fstest.Initialise()
 	buf := new(bytes.Buffer)
 
 	f, err := fs.NewFs("testfiles")
 	require.NoError(t, err)
 
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `file1
 file2
@@ -29,6 +31,7 @@ subdir/
 }
 
 func TestRecurseFlag(t *testing.T) {
+	ctx := context.Background()
 	fstest.Initialise()
 	buf := new(bytes.Buffer)
 
@@ -36,7 +39,7 @@ func TestRecurseFlag(t *testing.T) {
 	require.NoError(t, err)
 
 	recurse = true
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `file1
 file2
@@ -50,6 +53,7 @@ subdir/file3
 }
 
 func TestDirSlashFlag(t *testing.T) {
+	ctx := context.Background()
 	fstest.Initialise()
 	buf := new(bytes.Buffer)
 
@@ -58,7 +62,7 @@ func TestDirSlashFlag(t *testing.T) {
 
 	dirSlash = true
 	format = "p"
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `file1
 file2
@@ -68,7 +72,7 @@ subdir/
 
 	buf = new(bytes.Buffer)
 	dirSlash = false
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `file1
 file2
@@ -78,13 +82,14 @@ subdir
 }
 
 func TestFormat(t *testing.T) {
+	ctx := context.Background()
 	fstest.Initialise()
 	f, err := fs.NewFs("testfiles")
 	require.NoError(t, err)
 
 	buf := new(bytes.Buffer)
 	format = "p"
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `file1
 file2
@@ -94,7 +99,7 @@ subdir
 
 	buf = new(bytes.Buffer)
 	format = "s"
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `0
 321
@@ -104,7 +109,7 @@ subdir
 
 	buf = new(bytes.Buffer)
 	format = "hp"
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `d41d8cd98f00b204e9800998ecf8427e;file1
 409d6c19451dd39d4a94e42d2ff2c834;file2
@@ -115,7 +120,7 @@ subdir
 	buf = new(bytes.Buffer)
 	format = "p"
 	filesOnly = true
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `file1
 file2
@@ -126,7 +131,7 @@ file3
 	buf = new(bytes.Buffer)
 	format = "p"
 	dirsOnly = true
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `subdir
 `, buf.String())
@@ -134,10 +139,10 @@ file3
 
 	buf = new(bytes.Buffer)
 	format = "t"
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 
-	items, _ := list.DirSorted(f, true, "")
+	items, _ := list.DirSorted(ctx, f, true, "")
 	var expectedOutput string
 	for _, item := range items {
 		expectedOutput += item.ModTime().Format("2006-01-02 15:04:05") + "\n"
@@ -147,7 +152,7 @@ file3
 
 	buf = new(bytes.Buffer)
 	format = "sp"
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `0;file1
 321;file2
@@ -158,13 +163,14 @@ file3
 }
 
 func TestSeparator(t *testing.T) {
+	ctx := context.Background()
 	fstest.Initialise()
 	f, err := fs.NewFs("testfiles")
 	require.NoError(t, err)
 	format = "ps"
 
 	buf := new(bytes.Buffer)
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `file1;0
 file2;321
@@ -174,7 +180,7 @@ subdir;-1
 
 	separator = "__SEP__"
 	buf = new(bytes.Buffer)
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 	assert.Equal(t, `file1__SEP__0
 file2__SEP__321
@@ -186,6 +192,7 @@ subdir__SEP__-1
 }
 
 func TestWholeLsf(t *testing.T) {
+	ctx := context.Background()
 	fstest.Initialise()
 	f, err := fs.NewFs("testfiles")
 	require.NoError(t, err)
@@ -195,11 +202,11 @@ func TestWholeLsf(t *testing.T) {
 	dirSlash = true
 
 	buf := new(bytes.Buffer)
-	err = Lsf(f, buf)
+	err = Lsf(ctx, f, buf)
 	require.NoError(t, err)
 
-	items, _ := list.DirSorted(f, true, "")
-	itemsInSubdir, _ := list.DirSorted(f, true, "subdir")
+	items, _ := list.DirSorted(ctx, f, true, "")
+	itemsInSubdir, _ := list.DirSorted(ctx, f, true, "subdir")
 	var expectedOutput []string
 	for _, item := range items {
 		expectedOutput = append(expectedOutput, item.ModTime().Format("2006-01-02 15:04:05"))
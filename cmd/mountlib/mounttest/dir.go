@@ -1,6 +1,7 @@
 package mounttest
 
 import (
+	"context"
 	"os"
 	"testing"
 	"time"
@@ -158,6 +159,7 @@ func TestDirModTime(t *testing.T) {
 
 // TestDirCacheFlush tests fluching the dir cache
 func TestDirCacheFlush(t *testing.T) {
+	ctx := context.Background()
 	run.skipIfNoFUSE(t)
 
 	run.checkDir(t, "")
@@ -172,7 +174,7 @@ func TestDirCacheFlush(t *testing.T) {
 	run.readLocal(t, localDm, "")
 	assert.Equal(t, dm, localDm, "expected vs fuse mount")
 
-	err := run.fremote.Mkdir("dir/subdir")
+	err := run.fremote.Mkdir(ctx, "dir/subdir")
 	require.NoError(t, err)
 
 	root, err := run.vfs.Root()
@@ -198,6 +200,7 @@ func TestDirCacheFlush(t *testing.T) {
 
 // TestDirCacheFlushOnDirRename tests flushing the dir cache on rename
 func TestDirCacheFlushOnDirRename(t *testing.T) {
+	ctx := context.Background()
 	run.skipIfNoFUSE(t)
 	run.mkdir(t, "dir")
 	run.createFile(t, "dir/file", "1")
@@ -208,7 +211,7 @@ func TestDirCacheFlushOnDirRename(t *testing.T) {
 	assert.Equal(t, dm, localDm, "expected vs fuse mount")
 
 	// expect remotely created directory to not show up
-	err := run.fremote.Mkdir("dir/subdir")
+	err := run.fremote.Mkdir(ctx, "dir/subdir")
 	require.NoError(t, err)
 	run.readLocal(t, localDm, "")
 	assert.Equal(t, dm, localDm, "expected vs fuse mount")
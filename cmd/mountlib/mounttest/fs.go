@@ -3,6 +3,7 @@
 package mounttest
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io/ioutil"
@@ -107,6 +108,7 @@ var run *Run
 //
 // Finalise() will tidy them away when done.
 func newRun() *Run {
+	ctx := context.Background()
 	r := &Run{
 		umountResult: make(chan error, 1),
 	}
@@ -119,7 +121,7 @@ func newRun() *Run {
 		log.Fatalf("Failed to open remote %q: %v", *fstest.RemoteName, err)
 	}
 
-	err = r.fremote.Mkdir("")
+	err = r.fremote.Mkdir(ctx, "")
 	if err != nil {
 		log.Fatalf("Failed to open mkdir %q: %v", *fstest.RemoteName, err)
 	}
@@ -203,6 +205,7 @@ func (r *Run) umount() {
 
 // cacheMode flushes the VFS and changes the CacheMode
 func (r *Run) cacheMode(cacheMode vfs.CacheMode) {
+	ctx := context.Background()
 	if r.skip {
 		log.Printf("FUSE not found so skipping cacheMode")
 		return
@@ -211,7 +214,7 @@ func (r *Run) cacheMode(cacheMode vfs.CacheMode) {
 	r.vfs.WaitForWriters(30 * time.Second)
 	// Empty and remake the remote
 	r.cleanRemote()
-	err := r.fremote.Mkdir("")
+	err := r.fremote.Mkdir(ctx, "")
 	if err != nil {
 		log.Fatalf("Failed to open mkdir %q: %v", *fstest.RemoteName, err)
 	}
@@ -296,7 +299,8 @@ func (r *Run) readLocal(t *testing.T, dir dirMap, filePath string) {
 
 // reads the remote tree into dir
 func (r *Run) readRemote(t *testing.T, dir dirMap, filepath string) {
-	objs, dirs, err := walk.GetAll(r.fremote, filepath, true, 1)
+	ctx := context.Background()
+	objs, dirs, err := walk.GetAll(ctx, r.fremote, filepath, true, 1)
 	if err == fs.ErrorDirNotFound {
 		return
 	}
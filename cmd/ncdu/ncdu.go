@@ -5,6 +5,7 @@
 package ncdu
 
 import (
+	"context"
 	"fmt"
 	"path"
 	"sort"
@@ -53,7 +54,7 @@ UI won't respond in the meantime since the deletion is done synchronously.
 		cmd.CheckArgs(1, 1, command, args)
 		fsrc := cmd.NewFsSrc(args)
 		cmd.Run(false, false, command, func() error {
-			return NewUI(fsrc).Show()
+			return NewUI(fsrc).Show(context.Background())
 		})
 	},
 }
@@ -421,7 +422,7 @@ func (u *UI) removeEntry(pos int) {
 }
 
 // delete the entry at the current position
-func (u *UI) delete() {
+func (u *UI) delete(ctx context.Context) {
 	dirPos := u.sortPerm[u.dirPosMap[u.path].entry]
 	entry := u.entries[dirPos]
 	u.boxMenu = []string{"cancel", "confirm"}
@@ -430,7 +431,7 @@ func (u *UI) delete() {
 			if o != 1 {
 				return "Aborted!", nil
 			}
-			err := operations.DeleteFile(obj)
+			err := operations.DeleteFile(ctx, obj)
 			if err != nil {
 				return "", err
 			}
@@ -445,7 +446,7 @@ func (u *UI) delete() {
 			if o != 1 {
 				return "Aborted!", nil
 			}
-			err := operations.Purge(f, entry.String())
+			err := operations.Purge(ctx, f, entry.String())
 			if err != nil {
 				return "", err
 			}
@@ -626,7 +627,7 @@ func NewUI(f fs.Fs) *UI {
 }
 
 // Show shows the user interface
-func (u *UI) Show() error {
+func (u *UI) Show(ctx context.Context) error {
 	err := termbox.Init()
 	if err != nil {
 		return errors.Wrap(err, "termbox init")
@@ -635,7 +636,7 @@ func (u *UI) Show() error {
 
 	// scan the disk in the background
 	u.listing = true
-	rootChan, errChan, updated := scan.Scan(u.f)
+	rootChan, errChan, updated := scan.Scan(ctx, u.f)
 
 	// Poll the events into a channel
 	events := make(chan termbox.Event)
@@ -716,7 +717,7 @@ outer:
 				case 'C':
 					u.toggleSort(&u.sortByCount)
 				case 'd':
-					u.delete()
+					u.delete(ctx)
 				case '?':
 					u.togglePopupBox(helpText)
 
@@ -24,6 +24,8 @@ var (
 	jsonInput = ""
 	authUser  = ""
 	authPass  = ""
+	options   []string
+	arguments []string
 )
 
 func init() {
@@ -33,6 +35,8 @@ func init() {
 	commandDefintion.Flags().StringVarP(&jsonInput, "json", "", jsonInput, "Input JSON - use instead of key=value args.")
 	commandDefintion.Flags().StringVarP(&authUser, "user", "", "", "Username to use to rclone remote control.")
 	commandDefintion.Flags().StringVarP(&authPass, "pass", "", "", "Password to use to connect to rclone remote control.")
+	commandDefintion.Flags().StringArrayVarP(&options, "opt", "o", options, "Option in the form name=value or name placed in the \"opt\" array.")
+	commandDefintion.Flags().StringArrayVarP(&arguments, "arg", "a", arguments, "Argument placed in the \"arg\" array.")
 }
 
 var commandDefintion = &cobra.Command{
@@ -58,6 +62,28 @@ The --json parameter can be used to pass in a JSON blob as an input
 instead of key=value arguments.  This is the only way of passing in
 more complicated values.
 
+The -o/--opt option can be used to set a key "opt" with key, value
+options in the form "-o key=value" or "-o key". It can be repeated as
+many times as required. This is useful for rc commands which take the
+"opt" parameter which by convention is a dictionary of strings.
+
+    -o key=value -o key2
+
+Will place this in the "opt" value
+
+    {"key":"value", "key2":""}
+
+The -a/--arg option can be used to set strings in the "arg" value. It
+can be repeated as many times as required. This is useful for rc
+commands which take the "arg" parameter which by convention is a list
+of strings.
+
+    -a value -a value2
+
+Will place this in the "arg" value
+
+    ["value", "value2"]
+
 Use "rclone rc" to see a list of all possible commands.`,
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(0, 1E9, command, args)
@@ -179,6 +205,21 @@ func run(args []string) (err error) {
 			return errors.Wrap(err, "bad --json input")
 		}
 	}
+	if len(options) > 0 {
+		opt := rc.Params{}
+		for _, option := range options {
+			equals := strings.IndexRune(option, '=')
+			key, value := option, ""
+			if equals >= 0 {
+				key, value = option[:equals], option[equals+1:]
+			}
+			opt[key] = value
+		}
+		in["opt"] = opt
+	}
+	if len(arguments) > 0 {
+		in["arg"] = arguments
+	}
 
 	// Do the call
 	out, callErr := doCall(path, in)
@@ -0,0 +1,85 @@
+package restore
+
+import (
+	"context"
+	"time"
+
+	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/operations"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	at = ""
+)
+
+func init() {
+	cmd.Root.AddCommand(commandDefintion)
+	commandDefintion.Flags().StringVarP(&at, "at", "", at, "Time to restore to - a --backup-dir snapshot name or an age like 7d.")
+}
+
+var commandDefintion = &cobra.Command{
+	Use:   "restore source:path dest:path",
+	Short: `Restore a sync destination as it was at a time from its --backup-dir snapshots.`,
+	Long: `
+Copies source:path, the destination of syncs made with
+` + "`--backup-dir`" + ` and ` + "`--backup-dir-format`" + `, into dest:path as it was at
+the time given with ` + "`--at`" + `.  You must pass the same ` + "`--backup-dir`" + `,
+` + "`--backup-dir-format`" + `, ` + "`--suffix`" + ` and ` + "`--suffix-keep-extension`" + ` flags as the
+syncs used.
+
+` + "`--at`" + ` is either the name of a snapshot or a time in the
+` + "`--backup-dir-format`" + `, or an age like ` + "`7d`" + ` or ` + "`12h`" + `.  It defaults
+to the time now.
+
+Each file is restored from the earliest snapshot made at or after
+that time which has it.  These are the versions which were replaced
+or deleted by the first sync after the time.  Other files are copied
+from source:path.  This means files created after the time are
+restored too as the snapshots don't record when files were created.
+
+For example if you run a nightly backup like this
+
+    rclone sync /path/to/local remote:current --backup-dir remote:old --backup-dir-format 2006-01-02-150405
+
+then this will restore it as it was before the sync on the 1st of
+December 2018 into remote:restored
+
+    rclone restore remote:current remote:restored --backup-dir remote:old --backup-dir-format 2006-01-02-150405 --at 2018-12-01-000000
+
+Unchanged files already in dest:path aren't copied again and files
+in dest:path which aren't in the restore aren't deleted.
+`,
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(2, 2, command, args)
+		fsrc, fdst := cmd.NewFsSrcDst(args)
+		cmd.Run(true, true, command, func() error {
+			when, err := parseAt(at, time.Now())
+			if err != nil {
+				return err
+			}
+			fs.Infof(fsrc, "Restoring as at %v", when)
+			return operations.Restore(context.Background(), fdst, fsrc, when)
+		})
+	},
+}
+
+// parseAt parses the --at flag as a snapshot time or an age before now
+func parseAt(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return now, nil
+	}
+	if fs.Config.BackupDirFormat != "" {
+		when, err := time.ParseInLocation(fs.Config.BackupDirFormat, s, time.Local)
+		if err == nil {
+			return when, nil
+		}
+	}
+	age, err := fs.ParseDuration(s)
+	if err != nil {
+		return time.Time{}, errors.Errorf("couldn't parse --at %q as a time in --backup-dir-format or an age", s)
+	}
+	return now.Add(-age), nil
+}
@@ -8,6 +8,7 @@
 package ftp
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -29,6 +30,7 @@ const (
 // TestFTP runs the ftp server then runs the unit tests for the
 // ftp remote against it.
 func TestFTP(t *testing.T) {
+	ctx := context.Background()
 	opt := ftpopt.DefaultOpt
 	opt.ListenAddr = testHOST + ":" + testPORT
 	opt.PassivePorts = testPASSIVEPORTRANGE
@@ -41,7 +43,7 @@ func TestFTP(t *testing.T) {
 	assert.NoError(t, err)
 	defer clean()
 
-	err = fremote.Mkdir("")
+	err = fremote.Mkdir(ctx, "")
 	assert.NoError(t, err)
 
 	// Start the server
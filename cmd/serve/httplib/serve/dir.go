@@ -65,7 +65,7 @@ func (d *Directory) AddEntry(remote string, isDir bool) {
 
 // Error returns an http.StatusInternalServerError and logs the error
 func Error(what interface{}, w http.ResponseWriter, text string, err error) {
-	fs.CountError(err)
+	accounting.Stats.Error(err)
 	fs.Errorf(what, "%s: %v", text, err)
 	http.Error(w, text+".", http.StatusInternalServerError)
 }
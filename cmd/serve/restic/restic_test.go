@@ -6,6 +6,7 @@
 package restic
 
 import (
+	"context"
 	"os"
 	"os/exec"
 	"testing"
@@ -25,6 +26,7 @@ const (
 // TestRestic runs the restic server then runs the unit tests for the
 // restic remote against it.
 func TestRestic(t *testing.T) {
+	ctx := context.Background()
 	_, err := os.Stat(resticSource)
 	if err != nil {
 		t.Skipf("Skipping test as restic source not found: %v", err)
@@ -39,7 +41,7 @@ func TestRestic(t *testing.T) {
 	assert.NoError(t, err)
 	defer clean()
 
-	err = fremote.Mkdir("")
+	err = fremote.Mkdir(ctx, "")
 	assert.NoError(t, err)
 
 	// Start the server
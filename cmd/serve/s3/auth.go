@@ -0,0 +1,267 @@
+package s3
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Constants for AWS signature version 4
+const (
+	signV4Algorithm = "AWS4-HMAC-SHA256"
+	amzDateFormat   = "20060102T150405Z"
+	yyyymmdd        = "20060102"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	maxSkew         = 15 * time.Minute
+	maxPresignAge   = 7 * 24 * time.Hour
+)
+
+// Errors returned when authenticating
+var (
+	errMissingAuth       = errors.New("missing authentication")
+	errBadAuthHeader     = errors.New("malformed Authorization header")
+	errUnknownAccessKey  = errors.New("the access key ID provided does not exist")
+	errSignatureMismatch = errors.New("the request signature does not match")
+	errRequestTooSkewed  = errors.New("the difference between the request time and the current time is too large")
+	errRequestExpired    = errors.New("the presigned request has expired")
+	errBadDate           = errors.New("malformed or missing X-Amz-Date")
+	errStreamingPayload  = errors.New("streaming signed payloads are not supported")
+)
+
+// signature contains the parsed parts of a V4 signature, either from
+// the Authorization header or from a presigned URL
+type signature struct {
+	accessKey     string
+	scope         string   // date/region/service/aws4_request
+	date          string   // date part of scope
+	region        string   // region part of scope
+	service       string   // service part of scope
+	signedHeaders []string // lower case, sorted
+	signature     string   // hex encoded signature
+	amzDate       string   // time the request was signed
+	payloadHash   string   // hash of the payload or unsignedPayload
+	presigned     bool     // set if from a presigned URL
+	expires       time.Duration
+}
+
+// parseCredential parses "AKID/20130524/us-east-1/s3/aws4_request"
+func (sig *signature) parseCredential(credential string) error {
+	parts := strings.Split(credential, "/")
+	if len(parts) != 5 || parts[4] != "aws4_request" {
+		return errBadAuthHeader
+	}
+	sig.accessKey = parts[0]
+	sig.date, sig.region, sig.service = parts[1], parts[2], parts[3]
+	sig.scope = strings.Join(parts[1:], "/")
+	return nil
+}
+
+// parseSignature reads the signature from the request, returning
+// errMissingAuth if the request isn't signed.
+func parseSignature(r *http.Request) (sig *signature, err error) {
+	sig = new(signature)
+	query := r.URL.Query()
+	if query.Get("X-Amz-Algorithm") != "" {
+		// Presigned URL
+		if query.Get("X-Amz-Algorithm") != signV4Algorithm {
+			return nil, errBadAuthHeader
+		}
+		err = sig.parseCredential(query.Get("X-Amz-Credential"))
+		if err != nil {
+			return nil, err
+		}
+		sig.signedHeaders = strings.Split(query.Get("X-Amz-SignedHeaders"), ";")
+		sig.signature = query.Get("X-Amz-Signature")
+		sig.amzDate = query.Get("X-Amz-Date")
+		expires, err := strconv.Atoi(query.Get("X-Amz-Expires"))
+		if err != nil || expires < 0 {
+			return nil, errBadAuthHeader
+		}
+		sig.expires = time.Duration(expires) * time.Second
+		sig.payloadHash = unsignedPayload
+		sig.presigned = true
+		return sig, nil
+	}
+
+	auth := r.Header.Get("Authorization")
+	if auth == "" {
+		return nil, errMissingAuth
+	}
+	if !strings.HasPrefix(auth, signV4Algorithm+" ") {
+		return nil, errBadAuthHeader
+	}
+	for _, field := range strings.Split(auth[len(signV4Algorithm)+1:], ",") {
+		field = strings.TrimSpace(field)
+		equals := strings.IndexRune(field, '=')
+		if equals < 0 {
+			return nil, errBadAuthHeader
+		}
+		key, value := field[:equals], field[equals+1:]
+		switch key {
+		case "Credential":
+			err = sig.parseCredential(value)
+			if err != nil {
+				return nil, err
+			}
+		case "SignedHeaders":
+			sig.signedHeaders = strings.Split(value, ";")
+		case "Signature":
+			sig.signature = value
+		}
+	}
+	if sig.accessKey == "" || len(sig.signedHeaders) == 0 || sig.signature == "" {
+		return nil, errBadAuthHeader
+	}
+	sig.amzDate = r.Header.Get("X-Amz-Date")
+	sig.payloadHash = r.Header.Get("X-Amz-Content-Sha256")
+	if sig.payloadHash == "" {
+		sig.payloadHash = unsignedPayload
+	}
+	if strings.HasPrefix(sig.payloadHash, "STREAMING-") {
+		return nil, errStreamingPayload
+	}
+	return sig, nil
+}
+
+// uriEncode encodes s as specified by the AWS signature docs,
+// leaving only the unreserved characters alone.
+func uriEncode(s string, encodeSlash bool) string {
+	var out bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !encodeSlash) {
+			out.WriteByte(c)
+		} else {
+			out.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{c})))
+		}
+	}
+	return out.String()
+}
+
+// canonicalQuery returns the sorted encoded query string leaving out
+// the signature itself
+func canonicalQuery(query url.Values) string {
+	var params []string
+	for key, values := range query {
+		if key == "X-Amz-Signature" {
+			continue
+		}
+		for _, value := range values {
+			params = append(params, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+// canonicalHeaders returns the signed headers in canonical form
+func canonicalHeaders(r *http.Request, signedHeaders []string) string {
+	var out bytes.Buffer
+	for _, name := range signedHeaders {
+		var value string
+		switch name {
+		case "host":
+			value = r.Host
+		case "content-length":
+			value = strconv.FormatInt(r.ContentLength, 10)
+		default:
+			var values []string
+			for _, v := range r.Header[http.CanonicalHeaderKey(name)] {
+				values = append(values, strings.Join(strings.Fields(v), " "))
+			}
+			value = strings.Join(values, ",")
+		}
+		out.WriteString(name + ":" + value + "\n")
+	}
+	return out.String()
+}
+
+// canonicalURI returns the path of the request as sent by the client
+func canonicalURI(r *http.Request) string {
+	uri := r.RequestURI
+	if i := strings.IndexRune(uri, '?'); i >= 0 {
+		uri = uri[:i]
+	}
+	if uri == "" || strings.Contains(uri, "://") {
+		uri = r.URL.EscapedPath()
+	}
+	return uri
+}
+
+// stringToSign makes the string which the client should have signed
+func (sig *signature) stringToSign(r *http.Request) string {
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		canonicalURI(r),
+		canonicalQuery(r.URL.Query()),
+		canonicalHeaders(r, sig.signedHeaders),
+		strings.Join(sig.signedHeaders, ";"),
+		sig.payloadHash,
+	}, "\n")
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	return strings.Join([]string{
+		signV4Algorithm,
+		sig.amzDate,
+		sig.scope,
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data using key
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// sign returns the hex encoded signature of stringToSign
+func (sig *signature) sign(secretKey, stringToSign string) string {
+	key := hmacSHA256([]byte("AWS4"+secretKey), sig.date)
+	key = hmacSHA256(key, sig.region)
+	key = hmacSHA256(key, sig.service)
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// verify checks the signature of the request using the secret key
+// for the access key it was signed with in keys.  It returns the
+// signature so the caller can check the payload hash.
+func verify(r *http.Request, keys map[string]string, now time.Time) (*signature, error) {
+	sig, err := parseSignature(r)
+	if err != nil {
+		return nil, err
+	}
+	secretKey, ok := keys[sig.accessKey]
+	if !ok {
+		return nil, errUnknownAccessKey
+	}
+	signedAt, err := time.Parse(amzDateFormat, sig.amzDate)
+	if err != nil || signedAt.Format(yyyymmdd) != sig.date {
+		return nil, errBadDate
+	}
+	if sig.presigned {
+		if sig.expires > maxPresignAge {
+			return nil, errBadAuthHeader
+		}
+		if now.After(signedAt.Add(sig.expires)) {
+			return nil, errRequestExpired
+		}
+	} else if skew := now.Sub(signedAt); skew > maxSkew || skew < -maxSkew {
+		return nil, errRequestTooSkewed
+	}
+	expected := sig.sign(secretKey, sig.stringToSign(r))
+	if !hmac.Equal([]byte(expected), []byte(sig.signature)) {
+		return nil, errSignatureMismatch
+	}
+	return sig, nil
+}
@@ -0,0 +1,223 @@
+package s3
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/walk"
+)
+
+// maxKeys is the maximum number of keys returned in a listing
+const maxKeys = 1000
+
+// listEntry is an object or common prefix found in a listing
+type listEntry struct {
+	key string
+	o   fs.Object // nil if this is a common prefix
+}
+
+// listEntries finds all the objects in bucketName starting with
+// prefix, rolling keys containing delimiter after the prefix up into
+// common prefixes.  The result is sorted by key.
+func (s *server) listEntries(ctx context.Context, bucketName, prefix, delimiter string) (result []listEntry, err error) {
+	dir := ""
+	if slash := strings.LastIndex(prefix, "/"); slash >= 0 {
+		dir = prefix[:slash]
+	}
+	root := path.Join(bucketName, dir)
+	bucketPrefix := bucketName + "/"
+
+	if delimiter == "/" {
+		// If the delimiter is / we only need to list one directory
+		entries, err := s.f.List(ctx, root)
+		if err == fs.ErrorDirNotFound {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			key := strings.TrimPrefix(entry.Remote(), bucketPrefix)
+			switch x := entry.(type) {
+			case fs.Object:
+				if strings.HasPrefix(key, prefix) {
+					result = append(result, listEntry{key: key, o: x})
+				}
+			case fs.Directory:
+				key += "/"
+				if strings.HasPrefix(key, prefix) {
+					result = append(result, listEntry{key: key})
+				}
+			}
+		}
+	} else {
+		// Otherwise walk everything under the prefix
+		seen := map[string]struct{}{}
+		err = walk.Walk(ctx, s.f, root, true, -1, func(dirPath string, entries fs.DirEntries, err error) error {
+			if err != nil {
+				return err
+			}
+			for _, entry := range entries {
+				o, ok := entry.(fs.Object)
+				if !ok {
+					continue
+				}
+				key := strings.TrimPrefix(o.Remote(), bucketPrefix)
+				if !strings.HasPrefix(key, prefix) {
+					continue
+				}
+				if delimiter != "" {
+					if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+						commonPrefix := key[:len(prefix)+i+len(delimiter)]
+						if _, found := seen[commonPrefix]; !found {
+							seen[commonPrefix] = struct{}{}
+							result = append(result, listEntry{key: commonPrefix})
+						}
+						continue
+					}
+				}
+				result = append(result, listEntry{key: key, o: o})
+			}
+			return nil
+		})
+		if err == fs.ErrorDirNotFound {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].key < result[j].key
+	})
+	return result, nil
+}
+
+// pageEntries returns up to max entries with keys after marker and
+// whether the listing was truncated
+func pageEntries(entries []listEntry, marker string, max int) (page []listEntry, truncated bool) {
+	start := sort.Search(len(entries), func(i int) bool {
+		return entries[i].key > marker
+	})
+	entries = entries[start:]
+	if len(entries) > max {
+		return entries[:max], true
+	}
+	return entries, false
+}
+
+// encodeKey encodes key if the client asked for url encoding
+func encodeKey(key, encodingType string) string {
+	if encodingType == "url" {
+		return uriEncode(key, false)
+	}
+	return key
+}
+
+// makeContents converts the page into objects and common prefixes
+func makeContents(ctx context.Context, page []listEntry, encodingType string) (contents []object, prefixes []commonPrefix) {
+	for _, entry := range page {
+		key := encodeKey(entry.key, encodingType)
+		if entry.o == nil {
+			prefixes = append(prefixes, commonPrefix{Prefix: key})
+			continue
+		}
+		contents = append(contents, object{
+			Key:          key,
+			LastModified: formatTime(entry.o.ModTime()),
+			ETag:         objectETag(ctx, entry.o),
+			Size:         entry.o.Size(),
+			StorageClass: "STANDARD",
+			Owner:        &defaultOwner,
+		})
+	}
+	return contents, prefixes
+}
+
+// listObjects serves ListObjects and ListObjectsV2
+func (s *server) listObjects(w http.ResponseWriter, r *http.Request, bucketName string) *apiError {
+	ctx := r.Context()
+	if err := s.checkBucket(ctx, bucketName); err != nil {
+		return err
+	}
+	query := r.URL.Query()
+	prefix := query.Get("prefix")
+	if slash := strings.LastIndex(prefix, "/"); slash >= 0 && !validName(prefix[:slash]) {
+		return errInvalidArgument
+	}
+	delimiter := query.Get("delimiter")
+	encodingType := query.Get("encoding-type")
+	max, apiErr := queryInt(query, "max-keys", maxKeys)
+	if apiErr != nil {
+		return apiErr
+	}
+	if max > maxKeys {
+		max = maxKeys
+	}
+	v2 := query.Get("list-type") == "2"
+
+	var marker, continuationToken string
+	if v2 {
+		marker = query.Get("start-after")
+		continuationToken = query.Get("continuation-token")
+		if continuationToken != "" {
+			decoded, err := base64.StdEncoding.DecodeString(continuationToken)
+			if err != nil {
+				return errInvalidArgument
+			}
+			marker = string(decoded)
+		}
+	} else {
+		marker = query.Get("marker")
+	}
+
+	entries, err := s.listEntries(ctx, bucketName, prefix, delimiter)
+	if err != nil {
+		return internalError(bucketName, err)
+	}
+	page, truncated := pageEntries(entries, marker, max)
+	contents, prefixes := makeContents(ctx, page, encodingType)
+	nextMarker := ""
+	if truncated && len(page) > 0 {
+		nextMarker = page[len(page)-1].key
+	}
+
+	if v2 {
+		result := listBucketResultV2{
+			Xmlns:             xmlNamespace,
+			Name:              bucketName,
+			Prefix:            encodeKey(prefix, encodingType),
+			StartAfter:        encodeKey(query.Get("start-after"), encodingType),
+			ContinuationToken: continuationToken,
+			KeyCount:          len(page),
+			MaxKeys:           max,
+			Delimiter:         encodeKey(delimiter, encodingType),
+			EncodingType:      encodingType,
+			IsTruncated:       truncated,
+			Contents:          contents,
+			CommonPrefixes:    prefixes,
+		}
+		if nextMarker != "" {
+			result.NextContinuationToken = base64.StdEncoding.EncodeToString([]byte(nextMarker))
+		}
+		writeXML(w, http.StatusOK, result)
+		return nil
+	}
+	writeXML(w, http.StatusOK, listBucketResult{
+		Xmlns:          xmlNamespace,
+		Name:           bucketName,
+		Prefix:         encodeKey(prefix, encodingType),
+		Marker:         encodeKey(marker, encodingType),
+		NextMarker:     encodeKey(nextMarker, encodingType),
+		MaxKeys:        max,
+		Delimiter:      encodeKey(delimiter, encodingType),
+		EncodingType:   encodingType,
+		IsTruncated:    truncated,
+		Contents:       contents,
+		CommonPrefixes: prefixes,
+	})
+	return nil
+}
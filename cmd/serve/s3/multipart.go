@@ -0,0 +1,290 @@
+package s3
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/operations"
+	"github.com/pkg/errors"
+)
+
+// maxPartNumber is the largest part number S3 allows
+const maxPartNumber = 10000
+
+// part is an uploaded part of a multipart upload
+type part struct {
+	path string // where the part is stored
+	md5  string // hex MD5 of the part
+	size int64
+}
+
+// upload is a multipart upload in progress
+//
+// The parts are stored in a temporary directory until the upload is
+// completed
+type upload struct {
+	mu      sync.Mutex
+	bucket  string
+	key     string
+	dir     string
+	modTime time.Time
+	parts   map[int]part
+}
+
+// uploads holds the multipart uploads in progress
+type uploads struct {
+	mu      sync.Mutex
+	uploads map[string]*upload
+}
+
+func newUploads() *uploads {
+	return &uploads{
+		uploads: make(map[string]*upload),
+	}
+}
+
+// newID makes a random upload ID
+func newID() (string, error) {
+	var id [16]byte
+	_, err := io.ReadFull(rand.Reader, id[:])
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id[:]), nil
+}
+
+// add a new upload returning its ID
+func (us *uploads) add(bucketName, key string, modTime time.Time) (id string, err error) {
+	id, err = newID()
+	if err != nil {
+		return "", err
+	}
+	dir, err := ioutil.TempDir("", "rclone-serve-s3-")
+	if err != nil {
+		return "", err
+	}
+	us.mu.Lock()
+	us.uploads[id] = &upload{
+		bucket:  bucketName,
+		key:     key,
+		dir:     dir,
+		modTime: modTime,
+		parts:   make(map[int]part),
+	}
+	us.mu.Unlock()
+	return id, nil
+}
+
+// get the upload with id for bucketName and key
+func (us *uploads) get(bucketName, key, id string) (*upload, *apiError) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	u, ok := us.uploads[id]
+	if !ok || u.bucket != bucketName || u.key != key {
+		return nil, errNoSuchUpload
+	}
+	return u, nil
+}
+
+// remove the upload with id and its temporary files
+func (us *uploads) remove(id string) {
+	us.mu.Lock()
+	u, ok := us.uploads[id]
+	delete(us.uploads, id)
+	us.mu.Unlock()
+	if ok {
+		u.cleanup()
+	}
+}
+
+// removeAll removes all the uploads in progress
+func (us *uploads) removeAll() {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	for id, u := range us.uploads {
+		delete(us.uploads, id)
+		u.cleanup()
+	}
+}
+
+// cleanup removes the temporary files for the upload
+func (u *upload) cleanup() {
+	err := os.RemoveAll(u.dir)
+	if err != nil {
+		fs.Errorf(nil, "Failed to remove multipart upload directory: %v", err)
+	}
+}
+
+// createMultipartUpload starts a new multipart upload
+func (s *server) createMultipartUpload(w http.ResponseWriter, r *http.Request, bucketName, key string) *apiError {
+	if err := s.checkBucket(r.Context(), bucketName); err != nil {
+		return err
+	}
+	id, err := s.uploads.add(bucketName, key, requestModTime(r))
+	if err != nil {
+		return internalError(key, err)
+	}
+	writeXML(w, http.StatusOK, initiateMultipartUploadResult{
+		Xmlns:    xmlNamespace,
+		Bucket:   bucketName,
+		Key:      key,
+		UploadID: id,
+	})
+	return nil
+}
+
+// uploadPart stores a part of a multipart upload
+func (s *server) uploadPart(w http.ResponseWriter, r *http.Request, bucketName, key, id string) *apiError {
+	u, apiErr := s.uploads.get(bucketName, key, id)
+	if apiErr != nil {
+		return apiErr
+	}
+	partNumber, err := strconv.Atoi(r.URL.Query().Get("partNumber"))
+	if err != nil || partNumber < 1 || partNumber > maxPartNumber {
+		return errInvalidArgument
+	}
+
+	// Write the part to a temporary file
+	out, err := ioutil.TempFile(u.dir, "part")
+	if err != nil {
+		return internalError(key, err)
+	}
+	hasher := md5.New()
+	size, err := io.Copy(io.MultiWriter(out, hasher), r.Body)
+	closeErr := out.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(out.Name())
+		return requestError(key, err)
+	}
+	newPart := part{
+		path: out.Name(),
+		md5:  hex.EncodeToString(hasher.Sum(nil)),
+		size: size,
+	}
+
+	// Replace any previous part with the same number
+	u.mu.Lock()
+	oldPart, found := u.parts[partNumber]
+	u.parts[partNumber] = newPart
+	u.mu.Unlock()
+	if found {
+		_ = os.Remove(oldPart.path)
+	}
+
+	w.Header().Set("ETag", `"`+newPart.md5+`"`)
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// multiReadCloser reads from files in turn closing them all at the end
+type multiReadCloser struct {
+	io.Reader
+	files []*os.File
+}
+
+// Close all the files
+func (m *multiReadCloser) Close() (err error) {
+	for _, file := range m.files {
+		closeErr := file.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// completeMultipartUpload joins the parts together into the final object
+func (s *server) completeMultipartUpload(w http.ResponseWriter, r *http.Request, bucketName, key, id string) *apiError {
+	u, apiErr := s.uploads.get(bucketName, key, id)
+	if apiErr != nil {
+		return apiErr
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return requestError(key, err)
+	}
+	var req completeMultipartUpload
+	if err = xml.Unmarshal(body, &req); err != nil || len(req.Parts) == 0 {
+		return errMalformedXML
+	}
+
+	// Check the parts and open them in order
+	in := &multiReadCloser{}
+	defer func() {
+		_ = in.Close()
+	}()
+	var (
+		readers []io.Reader
+		size    int64
+		md5s    []byte
+	)
+	u.mu.Lock()
+	for i, completed := range req.Parts {
+		if i > 0 && completed.PartNumber <= req.Parts[i-1].PartNumber {
+			u.mu.Unlock()
+			return errInvalidPartOrder
+		}
+		p, ok := u.parts[completed.PartNumber]
+		if !ok || strings.Trim(completed.ETag, `"`) != p.md5 {
+			u.mu.Unlock()
+			return errInvalidPart
+		}
+		file, err := os.Open(p.path)
+		if err != nil {
+			u.mu.Unlock()
+			return internalError(key, err)
+		}
+		in.files = append(in.files, file)
+		readers = append(readers, file)
+		size += p.size
+		partMD5, _ := hex.DecodeString(p.md5)
+		md5s = append(md5s, partMD5...)
+	}
+	u.mu.Unlock()
+	in.Reader = io.MultiReader(readers...)
+
+	remote := path.Join(bucketName, key)
+	_, err = operations.RcatSize(r.Context(), s.f, remote, in, size, u.modTime)
+	if err != nil {
+		return internalError(remote, errors.Wrap(err, "failed to complete multipart upload"))
+	}
+	_ = in.Close()
+	s.uploads.remove(id)
+
+	// The ETag of a multipart upload is the MD5 of the MD5s of the parts
+	etag := md5.Sum(md5s)
+	writeXML(w, http.StatusOK, completeMultipartUploadResult{
+		Xmlns:    xmlNamespace,
+		Location: "/" + remote,
+		Bucket:   bucketName,
+		Key:      key,
+		ETag:     fmt.Sprintf(`"%s-%d"`, hex.EncodeToString(etag[:]), len(req.Parts)),
+	})
+	return nil
+}
+
+// abortMultipartUpload cancels a multipart upload
+func (s *server) abortMultipartUpload(w http.ResponseWriter, r *http.Request, bucketName, key, id string) *apiError {
+	if _, apiErr := s.uploads.get(bucketName, key, id); apiErr != nil {
+		return apiErr
+	}
+	s.uploads.remove(id)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
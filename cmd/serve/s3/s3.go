@@ -0,0 +1,93 @@
+// Package s3 implements an S3 compatible server for rclone
+package s3
+
+import (
+	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/cmd/serve/httplib"
+	"github.com/ncw/rclone/cmd/serve/httplib/httpflags"
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/config/flags"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Options set by command line flags
+var (
+	authKeys []string
+)
+
+func init() {
+	httpflags.AddFlags(Command.Flags())
+	flags.StringArrayVarP(Command.Flags(), &authKeys, "auth-key", "", authKeys, "Set key pair for v4 authorization, split by comma (Can be multi-valued)")
+}
+
+// Command definition for cobra
+var Command = &cobra.Command{
+	Use:   "s3 remote:path",
+	Short: `Serve remote:path over s3.`,
+	Long: `
+rclone serve s3 implements a basic S3 server that serves the remote
+over the S3 protocol.  This lets tools which only speak S3 read and
+write any rclone remote.
+
+The top level directories of remote:path are presented as buckets and
+the files within them as objects.  Creating a bucket makes a new top
+level directory.
+
+The server supports ListBuckets, CreateBucket, DeleteBucket,
+HeadBucket, ListObjects (both V1 and V2), GetObject (including ranged
+requests), HeadObject, PutObject, CopyObject, DeleteObject,
+DeleteObjects and multipart uploads.
+
+Clients should be configured to use path style requests, eg
+"http://localhost:8080/bucket/key", rather than virtual host style
+requests.  For an rclone s3 remote this means setting
+"force_path_style = true" and "provider = Other" with the endpoint set
+to the address of the server.
+
+If the remote supports MD5 hashes these are used as the ETags of the
+objects.  Modification times are stored and returned in the
+"X-Amz-Meta-Mtime" metadata which the rclone s3 backend uses.
+
+#### Authentication
+
+Use --auth-key accessKey,secretKey to set a key pair which clients
+must use to sign their requests with AWS signature version 4.  This
+may be repeated to allow more than one key pair.  Presigned URLs are
+also supported.
+
+The --user and --pass flags may be used instead to set a single key
+pair, with --user being the access key and --pass the secret key.
+--htpasswd is not supported since the secret keys must be known to
+check signatures.
+
+If no keys are set then the server won't check signatures and any
+client may read and write the remote.
+` + httplib.Help,
+	RunE: func(command *cobra.Command, args []string) error {
+		cmd.CheckArgs(1, 1, command, args)
+		f := cmd.NewFsSrc(args)
+		keys, err := parseAuthKeys(authKeys)
+		if err != nil {
+			return err
+		}
+		opt := httpflags.Opt
+		if opt.HtPasswd != "" {
+			return errors.New("--htpasswd is not supported with serve s3 - use --auth-key")
+		}
+		if opt.BasicUser != "" {
+			keys[opt.BasicUser] = opt.BasicPass
+		}
+		// Authentication is done with signatures not basic auth
+		opt.BasicUser, opt.BasicPass = "", ""
+		if len(keys) == 0 {
+			fs.Logf(nil, "No --auth-key set so serving without authentication")
+		}
+		cmd.Run(false, false, command, func() error {
+			s := newServer(f, keys, &opt)
+			defer s.uploads.removeAll()
+			return s.serve()
+		})
+		return nil
+	},
+}
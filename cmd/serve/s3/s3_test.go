@@ -0,0 +1,444 @@
+// Serve s3 tests set up a server and run the integration tests
+// for the s3 remote against it, and test it with the aws-sdk-go
+// client directly.
+//
+// We skip tests on platforms with troublesome character mappings
+
+//+build !windows,!darwin
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	_ "github.com/ncw/rclone/backend/local"
+	"github.com/ncw/rclone/cmd/serve/httplib"
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testBindAddress = "localhost:0"
+	testAccessKey   = "ACCESSKEY"
+	testSecretKey   = "SECRETKEY"
+)
+
+// startServer starts a server serving f returning it and a function
+// to stop it
+func startServer(t *testing.T, f fs.Fs) (*server, func()) {
+	opt := httplib.DefaultOpt
+	opt.ListenAddr = testBindAddress
+	s := newServer(f, map[string]string{testAccessKey: testSecretKey}, &opt)
+	require.NoError(t, s.Serve())
+	return s, func() {
+		s.Close()
+		s.uploads.removeAll()
+	}
+}
+
+// TestS3 runs the s3 server then runs the unit tests for the
+// s3 remote against it.
+func TestS3(t *testing.T) {
+	fstest.Initialise()
+
+	fremote, _, clean, err := fstest.RandomRemote(*fstest.RemoteName, *fstest.SubDir)
+	assert.NoError(t, err)
+	defer clean()
+
+	err = fremote.Mkdir(context.Background(), "")
+	assert.NoError(t, err)
+
+	s, stop := startServer(t, fremote)
+	defer stop()
+
+	// Change directory to run the tests
+	err = os.Chdir("../../../backend/s3")
+	assert.NoError(t, err, "failed to cd to s3 backend")
+
+	// Run the s3 tests with an on the fly remote
+	args := []string{"test"}
+	if testing.Verbose() {
+		args = append(args, "-v")
+	}
+	if *fstest.Verbose {
+		args = append(args, "-verbose")
+	}
+	args = append(args, "-list-retries", fmt.Sprint(*fstest.ListRetries))
+	args = append(args, "-remote", "s3test:")
+	cmd := exec.Command("go", args...)
+	cmd.Env = append(os.Environ(),
+		"RCLONE_CONFIG_S3TEST_TYPE=s3",
+		"RCLONE_CONFIG_S3TEST_PROVIDER=Other",
+		"RCLONE_CONFIG_S3TEST_ACCESS_KEY_ID="+testAccessKey,
+		"RCLONE_CONFIG_S3TEST_SECRET_ACCESS_KEY="+testSecretKey,
+		"RCLONE_CONFIG_S3TEST_ENDPOINT="+s.URL(),
+		"RCLONE_CONFIG_S3TEST_FORCE_PATH_STYLE=true",
+	)
+	out, err := cmd.CombinedOutput()
+	if len(out) != 0 {
+		t.Logf("\n----------\n%s----------\n", string(out))
+	}
+	assert.NoError(t, err, "Running s3 integration tests")
+}
+
+// newClient makes an aws-sdk-go client for the server
+func newClient(t *testing.T, s *server, accessKey, secretKey string) *s3.S3 {
+	ses, err := session.NewSession(aws.NewConfig().
+		WithRegion("us-east-1").
+		WithCredentials(credentials.NewStaticCredentials(accessKey, secretKey, "")).
+		WithEndpoint(s.URL()).
+		WithS3ForcePathStyle(true).
+		WithMaxRetries(0))
+	require.NoError(t, err)
+	return s3.New(ses)
+}
+
+// errorCode returns the S3 error code from err
+func errorCode(err error) string {
+	if awsErr, ok := err.(awserr.Error); ok {
+		return awsErr.Code()
+	}
+	return ""
+}
+
+func TestAWSClient(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-serve-s3-test")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	f, err := fs.NewFs(dir)
+	require.NoError(t, err)
+
+	s, stop := startServer(t, f)
+	defer stop()
+	c := newClient(t, s, testAccessKey, testSecretKey)
+
+	// Buckets
+	_, err = c.CreateBucket(&s3.CreateBucketInput{Bucket: aws.String("bucket")})
+	require.NoError(t, err)
+	_, err = c.CreateBucket(&s3.CreateBucketInput{Bucket: aws.String("bucket")})
+	assert.Equal(t, "BucketAlreadyOwnedByYou", errorCode(err))
+	buckets, err := c.ListBuckets(&s3.ListBucketsInput{})
+	require.NoError(t, err)
+	require.Len(t, buckets.Buckets, 1)
+	assert.Equal(t, "bucket", *buckets.Buckets[0].Name)
+	_, err = c.HeadBucket(&s3.HeadBucketInput{Bucket: aws.String("bucket")})
+	assert.NoError(t, err)
+	_, err = c.HeadBucket(&s3.HeadBucketInput{Bucket: aws.String("potato")})
+	assert.Error(t, err)
+
+	// Put and get objects
+	for _, key := range []string{"file.txt", "dir/a.txt", "dir/b.txt", "dir/sub/c.txt", "odd name+?.txt"} {
+		_, err = c.PutObject(&s3.PutObjectInput{
+			Bucket: aws.String("bucket"),
+			Key:    aws.String(key),
+			Body:   bytes.NewReader([]byte("hello " + key)),
+		})
+		require.NoError(t, err, key)
+	}
+	_, err = c.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String("potato"),
+		Key:    aws.String("file.txt"),
+		Body:   bytes.NewReader([]byte("hello")),
+	})
+	assert.Equal(t, "NoSuchBucket", errorCode(err))
+
+	get, err := c.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("odd name+?.txt"),
+	})
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(get.Body)
+	require.NoError(t, err)
+	require.NoError(t, get.Body.Close())
+	assert.Equal(t, "hello odd name+?.txt", string(data))
+	assert.Equal(t, `"1da212cf4ee8eb7960e58bd36f1f442f"`, *get.ETag)
+
+	get, err = c.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("file.txt"),
+		Range:  aws.String("bytes=6-9"),
+	})
+	require.NoError(t, err)
+	data, err = ioutil.ReadAll(get.Body)
+	require.NoError(t, err)
+	require.NoError(t, get.Body.Close())
+	assert.Equal(t, "file", string(data))
+	assert.Equal(t, "bytes 6-9/14", *get.ContentRange)
+
+	_, err = c.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("potato"),
+	})
+	assert.Equal(t, "NoSuchKey", errorCode(err))
+
+	head, err := c.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("dir/a.txt"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(15), *head.ContentLength)
+
+	// Listings
+	list, err := c.ListObjectsV2(&s3.ListObjectsV2Input{
+		Bucket:    aws.String("bucket"),
+		Delimiter: aws.String("/"),
+	})
+	require.NoError(t, err)
+	var keys []string
+	for _, o := range list.Contents {
+		keys = append(keys, *o.Key)
+	}
+	for _, p := range list.CommonPrefixes {
+		keys = append(keys, *p.Prefix)
+	}
+	assert.Equal(t, []string{"file.txt", "odd name+?.txt", "dir/"}, keys)
+
+	list, err = c.ListObjectsV2(&s3.ListObjectsV2Input{
+		Bucket: aws.String("bucket"),
+		Prefix: aws.String("dir/"),
+	})
+	require.NoError(t, err)
+	keys = nil
+	for _, o := range list.Contents {
+		keys = append(keys, *o.Key)
+	}
+	assert.Equal(t, []string{"dir/a.txt", "dir/b.txt", "dir/sub/c.txt"}, keys)
+
+	// Paging
+	keys = nil
+	err = c.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket:  aws.String("bucket"),
+		MaxKeys: aws.Int64(2),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		assert.True(t, len(page.Contents) <= 2)
+		for _, o := range page.Contents {
+			keys = append(keys, *o.Key)
+		}
+		return true
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dir/a.txt", "dir/b.txt", "dir/sub/c.txt", "file.txt", "odd name+?.txt"}, keys)
+
+	keys = nil
+	err = c.ListObjectsPages(&s3.ListObjectsInput{
+		Bucket:  aws.String("bucket"),
+		MaxKeys: aws.Int64(1),
+		Prefix:  aws.String("dir/"),
+	}, func(page *s3.ListObjectsOutput, lastPage bool) bool {
+		for _, o := range page.Contents {
+			keys = append(keys, *o.Key)
+		}
+		return true
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dir/a.txt", "dir/b.txt", "dir/sub/c.txt"}, keys)
+
+	// Multipart upload
+	create, err := c.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("multi.txt"),
+	})
+	require.NoError(t, err)
+	var parts []*s3.CompletedPart
+	for i, content := range []string{"one ", "two ", "three"} {
+		part, err := c.UploadPart(&s3.UploadPartInput{
+			Bucket:     aws.String("bucket"),
+			Key:        aws.String("multi.txt"),
+			UploadId:   create.UploadId,
+			PartNumber: aws.Int64(int64(i + 1)),
+			Body:       strings.NewReader(content),
+		})
+		require.NoError(t, err)
+		parts = append(parts, &s3.CompletedPart{ETag: part.ETag, PartNumber: aws.Int64(int64(i + 1))})
+	}
+	_, err = c.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String("bucket"),
+		Key:             aws.String("multi.txt"),
+		UploadId:        create.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	})
+	require.NoError(t, err)
+	get, err = c.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("multi.txt"),
+	})
+	require.NoError(t, err)
+	data, err = ioutil.ReadAll(get.Body)
+	require.NoError(t, err)
+	require.NoError(t, get.Body.Close())
+	assert.Equal(t, "one two three", string(data))
+
+	// Abort a multipart upload
+	create, err = c.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("aborted.txt"),
+	})
+	require.NoError(t, err)
+	_, err = c.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+		Bucket:   aws.String("bucket"),
+		Key:      aws.String("aborted.txt"),
+		UploadId: create.UploadId,
+	})
+	require.NoError(t, err)
+	_, err = c.UploadPart(&s3.UploadPartInput{
+		Bucket:     aws.String("bucket"),
+		Key:        aws.String("aborted.txt"),
+		UploadId:   create.UploadId,
+		PartNumber: aws.Int64(1),
+		Body:       strings.NewReader("potato"),
+	})
+	assert.Equal(t, "NoSuchUpload", errorCode(err))
+
+	// Copy
+	_, err = c.CopyObject(&s3.CopyObjectInput{
+		Bucket:     aws.String("bucket"),
+		Key:        aws.String("copy.txt"),
+		CopySource: aws.String("bucket/file.txt"),
+	})
+	require.NoError(t, err)
+	_, err = c.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("copy.txt"),
+	})
+	require.NoError(t, err)
+
+	// Bad credentials
+	bad := newClient(t, s, testAccessKey, "potato")
+	_, err = bad.ListBuckets(&s3.ListBucketsInput{})
+	assert.Equal(t, "SignatureDoesNotMatch", errorCode(err))
+	bad = newClient(t, s, "potato", testSecretKey)
+	_, err = bad.ListBuckets(&s3.ListBucketsInput{})
+	assert.Equal(t, "AccessDenied", errorCode(err))
+
+	// Delete
+	_, err = c.DeleteBucket(&s3.DeleteBucketInput{Bucket: aws.String("bucket")})
+	assert.Equal(t, "BucketNotEmpty", errorCode(err))
+	_, err = c.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("file.txt"),
+	})
+	require.NoError(t, err)
+	_, err = c.DeleteObjects(&s3.DeleteObjectsInput{
+		Bucket: aws.String("bucket"),
+		Delete: &s3.Delete{
+			Objects: []*s3.ObjectIdentifier{
+				{Key: aws.String("dir/a.txt")},
+				{Key: aws.String("dir/b.txt")},
+				{Key: aws.String("dir/sub/c.txt")},
+				{Key: aws.String("odd name+?.txt")},
+				{Key: aws.String("multi.txt")},
+				{Key: aws.String("copy.txt")},
+			},
+		},
+	})
+	require.NoError(t, err)
+	list, err = c.ListObjectsV2(&s3.ListObjectsV2Input{Bucket: aws.String("bucket")})
+	require.NoError(t, err)
+	assert.Len(t, list.Contents, 0)
+}
+
+func TestPathTraversal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-serve-s3-test")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	root := filepath.Join(dir, "root")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "bucket"), 0777))
+	outside := filepath.Join(dir, "file")
+	require.NoError(t, ioutil.WriteFile(outside, []byte("secret"), 0666))
+	f, err := fs.NewFs(root)
+	require.NoError(t, err)
+
+	opt := httplib.DefaultOpt
+	opt.ListenAddr = testBindAddress
+	s := newServer(f, nil, &opt)
+	require.NoError(t, s.Serve())
+	defer s.Close()
+
+	for _, method := range []string{"GET", "DELETE"} {
+		req, err := http.NewRequest(method, s.URL()+"bucket/../../file", nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, method)
+		assert.Contains(t, string(body), "InvalidArgument", method)
+		assert.NotContains(t, string(body), "secret", method)
+	}
+	_, err = os.Stat(outside)
+	assert.NoError(t, err, "file outside the root was removed")
+}
+
+func TestDirectoryMarker(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-serve-s3-test")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "bucket"), 0777))
+	f, err := fs.NewFs(dir)
+	require.NoError(t, err)
+
+	opt := httplib.DefaultOpt
+	opt.ListenAddr = testBindAddress
+	s := newServer(f, nil, &opt)
+	require.NoError(t, s.Serve())
+	defer s.Close()
+
+	req, err := http.NewRequest("PUT", s.URL()+"bucket/dir/sub/", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	fi, err := os.Stat(filepath.Join(dir, "bucket", "dir", "sub"))
+	require.NoError(t, err)
+	assert.True(t, fi.IsDir())
+}
+
+func TestValidName(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		want bool
+	}{
+		{"bucket", true},
+		{"dir/file.txt", true},
+		{"dir/..file", true},
+		{"", false},
+		{".", false},
+		{"..", false},
+		{"/file", false},
+		{"dir/", true},
+		{"dir/sub/", true},
+		{"/", false},
+		{"dir//", false},
+		{"../", false},
+		{"dir//file", false},
+		{"dir/./file", false},
+		{"../file", false},
+		{"dir/../../file", false},
+	} {
+		assert.Equal(t, test.want, validName(test.name), test.name)
+	}
+}
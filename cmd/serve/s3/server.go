@@ -0,0 +1,537 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ncw/rclone/cmd/serve/httplib"
+	"github.com/ncw/rclone/cmd/serve/httplib/serve"
+	"github.com/ncw/rclone/fs"
+	rhash "github.com/ncw/rclone/fs/hash"
+	"github.com/ncw/rclone/fs/operations"
+	"github.com/ncw/swift"
+	"github.com/pkg/errors"
+)
+
+// metaMtime is the header the rclone s3 backend stores mtime in
+const metaMtime = "X-Amz-Meta-Mtime"
+
+// server contains everything to run the server
+type server struct {
+	*httplib.Server
+	f       fs.Fs
+	keys    map[string]string // access key to secret key
+	uploads *uploads          // multipart uploads in progress
+}
+
+// newServer makes a new S3 server to serve f
+//
+// If keys is empty then requests won't be authenticated
+func newServer(f fs.Fs, keys map[string]string, opt *httplib.Options) *server {
+	s := &server{
+		f:       f,
+		keys:    keys,
+		uploads: newUploads(),
+	}
+	s.Server = httplib.NewServer(http.HandlerFunc(s.handler), opt)
+	return s
+}
+
+// parseAuthKeys parses "accessKey,secretKey" pairs into a map
+func parseAuthKeys(authKeys []string) (map[string]string, error) {
+	keys := make(map[string]string, len(authKeys))
+	for _, authKey := range authKeys {
+		comma := strings.IndexRune(authKey, ',')
+		if comma <= 0 || comma == len(authKey)-1 {
+			return nil, errors.Errorf("bad --auth-key %q: must be accessKey,secretKey", authKey)
+		}
+		keys[authKey[:comma]] = authKey[comma+1:]
+	}
+	return keys, nil
+}
+
+// serve runs the S3 server
+func (s *server) serve() error {
+	err := s.Serve()
+	if err != nil {
+		return err
+	}
+	fs.Logf(s.f, "Serving S3 on %s", s.URL())
+	s.Wait()
+	return nil
+}
+
+// handler reads incoming requests and dispatches them
+func (s *server) handler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Server", "rclone/"+fs.Version)
+
+	var sig *signature
+	if len(s.keys) > 0 {
+		var err error
+		sig, err = verify(r, s.keys, time.Now())
+		if err != nil {
+			fs.Infof(r.RemoteAddr, "%s %s: authentication failed: %v", r.Method, r.URL.Path, err)
+			switch err {
+			case errSignatureMismatch:
+				writeError(w, r, errSignatureDoesNotMatch)
+			case errStreamingPayload:
+				writeError(w, r, errNotImplemented)
+			default:
+				writeError(w, r, &apiError{errAccessDenied.Code, err.Error(), errAccessDenied.StatusCode})
+			}
+			return
+		}
+	}
+
+	// Check the payload hash as we read the body if it was signed
+	if sig != nil && sig.payloadHash != unsignedPayload && r.Body != nil {
+		r.Body = newHashCheckReader(r.Body, sig.payloadHash)
+	}
+
+	urlPath := strings.TrimPrefix(r.URL.Path, "/")
+	bucketName, key := urlPath, ""
+	if slash := strings.IndexRune(urlPath, '/'); slash >= 0 {
+		bucketName, key = urlPath[:slash], urlPath[slash+1:]
+	}
+	fs.Debugf(r.RemoteAddr, "%s bucket=%q key=%q query=%q", r.Method, bucketName, key, r.URL.RawQuery)
+	if urlPath != "" && (!validName(bucketName) || (key != "" && !validName(key))) {
+		writeError(w, r, errInvalidArgument)
+		return
+	}
+
+	var err *apiError
+	switch {
+	case bucketName == "":
+		err = s.serveRoot(w, r)
+	case key == "":
+		err = s.serveBucket(w, r, bucketName)
+	default:
+		err = s.serveObject(w, r, bucketName, key)
+	}
+	if err != nil {
+		writeError(w, r, err)
+	}
+}
+
+// validName returns whether name, a bucket name or key, is safe to
+// join onto the root of the remote.  Names with empty, "." or ".."
+// segments (which includes names with a leading "/") are rejected as
+// they could refer to something outside the bucket.  A single
+// trailing "/" is allowed for directory markers.
+func validName(name string) bool {
+	for _, segment := range strings.Split(strings.TrimSuffix(name, "/"), "/") {
+		switch segment {
+		case "", ".", "..":
+			return false
+		}
+	}
+	return true
+}
+
+// serveRoot serves requests for the service itself
+func (s *server) serveRoot(w http.ResponseWriter, r *http.Request) *apiError {
+	if r.Method != "GET" {
+		return errMethodNotAllowed
+	}
+	return s.listBuckets(w, r)
+}
+
+// serveBucket serves requests for a bucket
+func (s *server) serveBucket(w http.ResponseWriter, r *http.Request, bucketName string) *apiError {
+	query := r.URL.Query()
+	switch r.Method {
+	case "GET":
+		if _, ok := query["location"]; ok {
+			if err := s.checkBucket(r.Context(), bucketName); err != nil {
+				return err
+			}
+			writeXML(w, http.StatusOK, locationConstraint{Xmlns: xmlNamespace})
+			return nil
+		}
+		if _, ok := query["uploads"]; ok {
+			return errNotImplemented
+		}
+		return s.listObjects(w, r, bucketName)
+	case "HEAD":
+		return s.checkBucket(r.Context(), bucketName)
+	case "PUT":
+		return s.createBucket(w, r, bucketName)
+	case "DELETE":
+		return s.deleteBucket(w, r, bucketName)
+	case "POST":
+		if _, ok := query["delete"]; ok {
+			return s.deleteObjects(w, r, bucketName)
+		}
+	}
+	return errMethodNotAllowed
+}
+
+// serveObject serves requests for an object
+func (s *server) serveObject(w http.ResponseWriter, r *http.Request, bucketName, key string) *apiError {
+	query := r.URL.Query()
+	uploadID := query.Get("uploadId")
+	switch r.Method {
+	case "GET", "HEAD":
+		return s.getObject(w, r, bucketName, key)
+	case "PUT":
+		if uploadID != "" {
+			return s.uploadPart(w, r, bucketName, key, uploadID)
+		}
+		if r.Header.Get("X-Amz-Copy-Source") != "" {
+			return s.copyObject(w, r, bucketName, key)
+		}
+		return s.putObject(w, r, bucketName, key)
+	case "DELETE":
+		if uploadID != "" {
+			return s.abortMultipartUpload(w, r, bucketName, key, uploadID)
+		}
+		return s.deleteObject(w, r, bucketName, key)
+	case "POST":
+		if _, ok := query["uploads"]; ok {
+			return s.createMultipartUpload(w, r, bucketName, key)
+		}
+		if uploadID != "" {
+			return s.completeMultipartUpload(w, r, bucketName, key, uploadID)
+		}
+	}
+	return errMethodNotAllowed
+}
+
+// internalError logs err and returns errInternalError
+func internalError(what string, err error) *apiError {
+	fs.Errorf(what, "S3 request failed: %v", err)
+	return errInternalError
+}
+
+// listBuckets lists the top level directories as buckets
+func (s *server) listBuckets(w http.ResponseWriter, r *http.Request) *apiError {
+	entries, err := s.f.List(r.Context(), "")
+	if err != nil {
+		return internalError("", err)
+	}
+	result := listAllMyBucketsResult{
+		Xmlns: xmlNamespace,
+		Owner: defaultOwner,
+	}
+	for _, entry := range entries {
+		if dir, ok := entry.(fs.Directory); ok {
+			result.Buckets = append(result.Buckets, bucket{
+				Name:         dir.Remote(),
+				CreationDate: formatTime(dir.ModTime()),
+			})
+		}
+	}
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+// checkBucket returns errNoSuchBucket if the bucket doesn't exist
+func (s *server) checkBucket(ctx context.Context, bucketName string) *apiError {
+	_, err := s.f.List(ctx, bucketName)
+	if err == fs.ErrorDirNotFound {
+		return errNoSuchBucket
+	} else if err != nil {
+		return internalError(bucketName, err)
+	}
+	return nil
+}
+
+// createBucket makes a new top level directory
+func (s *server) createBucket(w http.ResponseWriter, r *http.Request, bucketName string) *apiError {
+	if s.checkBucket(r.Context(), bucketName) == nil {
+		return errBucketAlreadyExists
+	}
+	err := s.f.Mkdir(r.Context(), bucketName)
+	if err != nil {
+		return internalError(bucketName, err)
+	}
+	w.Header().Set("Location", "/"+bucketName)
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// deleteBucket removes an empty top level directory
+func (s *server) deleteBucket(w http.ResponseWriter, r *http.Request, bucketName string) *apiError {
+	if err := s.checkBucket(r.Context(), bucketName); err != nil {
+		return err
+	}
+	// The bucket is empty if it has no objects in - any empty
+	// directories left behind are removed with it
+	entries, err := s.listEntries(r.Context(), bucketName, "", "")
+	if err != nil {
+		return internalError(bucketName, err)
+	}
+	if len(entries) > 0 {
+		return errBucketNotEmpty
+	}
+	err = operations.Rmdirs(r.Context(), s.f, bucketName, false)
+	if err != nil {
+		return internalError(bucketName, err)
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// objectETag returns the ETag for o - the MD5 if available
+func objectETag(ctx context.Context, o fs.Object) string {
+	if md5, err := o.Hash(ctx, rhash.MD5); err == nil && md5 != "" {
+		return `"` + md5 + `"`
+	}
+	// Make an ETag which can't be confused with an MD5
+	return fmt.Sprintf(`"%x-%x"`, o.ModTime().UnixNano(), o.Size())
+}
+
+// getObject serves GET and HEAD requests for an object
+func (s *server) getObject(w http.ResponseWriter, r *http.Request, bucketName, key string) *apiError {
+	o, err := s.f.NewObject(r.Context(), path.Join(bucketName, key))
+	if err == fs.ErrorObjectNotFound || err == fs.ErrorNotAFile {
+		return errNoSuchKey
+	} else if err != nil {
+		return internalError(key, err)
+	}
+	if rangeRequest := r.Header.Get("Range"); rangeRequest != "" {
+		option, err := fs.ParseRangeOption(rangeRequest)
+		if err != nil {
+			return errInvalidArgument
+		}
+		if offset, _ := option.Decode(o.Size()); offset >= o.Size() && o.Size() > 0 {
+			return errInvalidRange
+		}
+	}
+	modTime := o.ModTime()
+	w.Header().Set("ETag", objectETag(r.Context(), o))
+	w.Header().Set("Last-Modified", modTime.UTC().Format(http.TimeFormat))
+	w.Header().Set(metaMtime, swift.TimeToFloatString(modTime))
+	serve.Object(w, r, o)
+	return nil
+}
+
+// hashCheckReader checks the body of a request matches the hash it
+// was signed with, returning an error at EOF if not
+type hashCheckReader struct {
+	in       io.ReadCloser
+	hash     hash.Hash
+	expected string
+}
+
+func newHashCheckReader(in io.ReadCloser, expected string) *hashCheckReader {
+	return &hashCheckReader{
+		in:       in,
+		hash:     sha256.New(),
+		expected: expected,
+	}
+}
+
+// Read bytes from the body checking the hash at the end
+func (h *hashCheckReader) Read(p []byte) (n int, err error) {
+	n, err = h.in.Read(p)
+	_, _ = h.hash.Write(p[:n])
+	if err == io.EOF && hex.EncodeToString(h.hash.Sum(nil)) != h.expected {
+		err = errBadDigest
+	}
+	return n, err
+}
+
+// Close the body
+func (h *hashCheckReader) Close() error {
+	return h.in.Close()
+}
+
+// requestError converts an error from reading the request into an
+// apiError
+func requestError(what string, err error) *apiError {
+	if apiErr, ok := errors.Cause(err).(*apiError); ok {
+		return apiErr
+	}
+	return internalError(what, err)
+}
+
+// requestModTime returns the modification time from the metadata
+// in the request or the current time
+func requestModTime(r *http.Request) time.Time {
+	if mtime := r.Header.Get(metaMtime); mtime != "" {
+		modTime, err := swift.FloatStringToTime(mtime)
+		if err == nil {
+			return modTime
+		}
+	}
+	return time.Now()
+}
+
+// putObject uploads an object
+func (s *server) putObject(w http.ResponseWriter, r *http.Request, bucketName, key string) *apiError {
+	if err := s.checkBucket(r.Context(), bucketName); err != nil {
+		return err
+	}
+	remote := path.Join(bucketName, key)
+
+	// Keys ending in / with no content are directory markers
+	if strings.HasSuffix(key, "/") && r.ContentLength == 0 {
+		err := s.f.Mkdir(r.Context(), remote)
+		if err != nil {
+			return internalError(remote, err)
+		}
+		w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+
+	// Read small objects into memory first so that a client which
+	// aborts the upload doesn't leave a partial object behind
+	in := r.Body
+	if r.ContentLength >= 0 && r.ContentLength <= int64(fs.Config.StreamingUploadCutoff) {
+		buf, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return requestError(remote, err)
+		}
+		in = ioutil.NopCloser(bytes.NewReader(buf))
+	}
+
+	o, err := operations.RcatSize(r.Context(), s.f, remote, in, r.ContentLength, requestModTime(r))
+	if err != nil {
+		return requestError(remote, err)
+	}
+	w.Header().Set("ETag", objectETag(r.Context(), o))
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// copyObject does a server side copy of an object
+func (s *server) copyObject(w http.ResponseWriter, r *http.Request, bucketName, key string) *apiError {
+	if err := s.checkBucket(r.Context(), bucketName); err != nil {
+		return err
+	}
+	source := r.Header.Get("X-Amz-Copy-Source")
+	if i := strings.IndexRune(source, '?'); i >= 0 {
+		// Ignore any versionId
+		source = source[:i]
+	}
+	source, err := url.PathUnescape(source)
+	if err != nil {
+		return errInvalidArgument
+	}
+	srcRemote := strings.TrimPrefix(source, "/")
+	if !strings.Contains(srcRemote, "/") || !validName(srcRemote) {
+		return errInvalidArgument
+	}
+	dstRemote := path.Join(bucketName, key)
+	src, err := s.f.NewObject(r.Context(), srcRemote)
+	if err == fs.ErrorObjectNotFound || err == fs.ErrorNotAFile {
+		return errNoSuchKey
+	} else if err != nil {
+		return internalError(srcRemote, err)
+	}
+
+	var dst fs.Object
+	if srcRemote != dstRemote {
+		dst, err = operations.Copy(r.Context(), s.f, nil, dstRemote, src)
+		if err != nil {
+			return internalError(dstRemote, err)
+		}
+	} else {
+		dst = src
+	}
+
+	// Replace the modification time if requested
+	if r.Header.Get("X-Amz-Metadata-Directive") == "REPLACE" && r.Header.Get(metaMtime) != "" {
+		err = dst.SetModTime(r.Context(), requestModTime(r))
+		if err != nil && err != fs.ErrorCantSetModTime && err != fs.ErrorCantSetModTimeWithoutDelete {
+			return internalError(dstRemote, err)
+		}
+	}
+
+	writeXML(w, http.StatusOK, copyObjectResult{
+		Xmlns:        xmlNamespace,
+		LastModified: formatTime(dst.ModTime()),
+		ETag:         objectETag(r.Context(), dst),
+	})
+	return nil
+}
+
+// deleteObject removes an object - it isn't an error if it doesn't exist
+func (s *server) deleteObject(w http.ResponseWriter, r *http.Request, bucketName, key string) *apiError {
+	err := s.remove(r.Context(), path.Join(bucketName, key))
+	if err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// remove removes the object at remote ignoring it if it doesn't exist
+func (s *server) remove(ctx context.Context, remote string) *apiError {
+	o, err := s.f.NewObject(ctx, remote)
+	if err == fs.ErrorObjectNotFound || err == fs.ErrorNotAFile {
+		return nil
+	} else if err != nil {
+		return internalError(remote, err)
+	}
+	err = o.Remove(ctx)
+	if err != nil {
+		return internalError(remote, err)
+	}
+	// Remove any parent directories left empty as S3 has no
+	// directories, stopping at the bucket
+	for dir := path.Dir(remote); strings.Contains(dir, "/"); dir = path.Dir(dir) {
+		if s.f.Rmdir(ctx, dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// deleteObjects removes multiple objects
+func (s *server) deleteObjects(w http.ResponseWriter, r *http.Request, bucketName string) *apiError {
+	var req deleteRequest
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return requestError(bucketName, err)
+	}
+	if err = xml.Unmarshal(body, &req); err != nil {
+		return errMalformedXML
+	}
+	result := deleteResult{Xmlns: xmlNamespace}
+	for _, obj := range req.Objects {
+		apiErr := errInvalidArgument
+		if validName(obj.Key) {
+			apiErr = s.remove(r.Context(), path.Join(bucketName, obj.Key))
+		}
+		if apiErr != nil {
+			result.Errors = append(result.Errors, deleteError{
+				Key:     obj.Key,
+				Code:    apiErr.Code,
+				Message: apiErr.Message,
+			})
+		} else if !req.Quiet {
+			result.Deleted = append(result.Deleted, deletedObject{Key: obj.Key})
+		}
+	}
+	writeXML(w, http.StatusOK, result)
+	return nil
+}
+
+// queryInt reads an integer from the query returning def if not set
+func queryInt(query url.Values, key string, def int) (int, *apiError) {
+	value := query.Get(key)
+	if value == "" {
+		return def, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil || i < 0 {
+		return 0, errInvalidArgument
+	}
+	return i, nil
+}
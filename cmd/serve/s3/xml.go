@@ -0,0 +1,241 @@
+package s3
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+)
+
+// The XML namespace for S3 responses
+const xmlNamespace = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+// The format S3 uses for times in XML
+const timeFormat = "2006-01-02T15:04:05.000Z"
+
+// formatTime formats t as S3 does in XML
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
+
+// owner is the owner of buckets and objects - always the same
+type owner struct {
+	ID          string
+	DisplayName string
+}
+
+var defaultOwner = owner{ID: "rclone", DisplayName: "rclone"}
+
+// bucket describes a bucket in a listAllMyBucketsResult
+type bucket struct {
+	Name         string
+	CreationDate string
+}
+
+// listAllMyBucketsResult is returned from ListBuckets
+type listAllMyBucketsResult struct {
+	XMLName xml.Name `xml:"ListAllMyBucketsResult"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Owner   owner
+	Buckets []bucket `xml:"Buckets>Bucket"`
+}
+
+// object describes an object in a listing
+type object struct {
+	Key          string
+	LastModified string
+	ETag         string
+	Size         int64
+	StorageClass string
+	Owner        *owner `xml:",omitempty"`
+}
+
+// commonPrefix describes a common prefix in a listing
+type commonPrefix struct {
+	Prefix string
+}
+
+// listBucketResult is returned from ListObjects
+type listBucketResult struct {
+	XMLName        xml.Name `xml:"ListBucketResult"`
+	Xmlns          string   `xml:"xmlns,attr"`
+	Name           string
+	Prefix         string
+	Marker         string
+	NextMarker     string `xml:",omitempty"`
+	MaxKeys        int
+	Delimiter      string `xml:",omitempty"`
+	EncodingType   string `xml:",omitempty"`
+	IsTruncated    bool
+	Contents       []object
+	CommonPrefixes []commonPrefix
+}
+
+// listBucketResultV2 is returned from ListObjectsV2
+type listBucketResultV2 struct {
+	XMLName               xml.Name `xml:"ListBucketResult"`
+	Xmlns                 string   `xml:"xmlns,attr"`
+	Name                  string
+	Prefix                string
+	StartAfter            string `xml:",omitempty"`
+	ContinuationToken     string `xml:",omitempty"`
+	NextContinuationToken string `xml:",omitempty"`
+	KeyCount              int
+	MaxKeys               int
+	Delimiter             string `xml:",omitempty"`
+	EncodingType          string `xml:",omitempty"`
+	IsTruncated           bool
+	Contents              []object
+	CommonPrefixes        []commonPrefix
+}
+
+// locationConstraint is returned from GetBucketLocation
+type locationConstraint struct {
+	XMLName  xml.Name `xml:"LocationConstraint"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Location string   `xml:",chardata"`
+}
+
+// copyObjectResult is returned from CopyObject
+type copyObjectResult struct {
+	XMLName      xml.Name `xml:"CopyObjectResult"`
+	Xmlns        string   `xml:"xmlns,attr"`
+	LastModified string
+	ETag         string
+}
+
+// initiateMultipartUploadResult is returned from CreateMultipartUpload
+type initiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Bucket   string
+	Key      string
+	UploadID string `xml:"UploadId"`
+}
+
+// completedPart is a part in a completeMultipartUpload request
+type completedPart struct {
+	PartNumber int
+	ETag       string
+}
+
+// completeMultipartUpload is the body of a CompleteMultipartUpload
+type completeMultipartUpload struct {
+	Parts []completedPart `xml:"Part"`
+}
+
+// completeMultipartUploadResult is returned from CompleteMultipartUpload
+type completeMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"CompleteMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Location string
+	Bucket   string
+	Key      string
+	ETag     string
+}
+
+// objectIdentifier names an object to delete in a deleteRequest
+type objectIdentifier struct {
+	Key string
+}
+
+// deleteRequest is the body of a DeleteObjects request
+type deleteRequest struct {
+	Objects []objectIdentifier `xml:"Object"`
+	Quiet   bool
+}
+
+// deletedObject is an object successfully deleted in a deleteResult
+type deletedObject struct {
+	Key string
+}
+
+// deleteError is an object which failed to delete in a deleteResult
+type deleteError struct {
+	Key     string
+	Code    string
+	Message string
+}
+
+// deleteResult is returned from DeleteObjects
+type deleteResult struct {
+	XMLName xml.Name `xml:"DeleteResult"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Deleted []deletedObject
+	Errors  []deleteError `xml:"Error"`
+}
+
+// errorResponse is the body returned with errors
+type errorResponse struct {
+	XMLName   xml.Name `xml:"Error"`
+	Code      string
+	Message   string
+	Resource  string
+	RequestID string `xml:"RequestId"`
+}
+
+// apiError is an error which can be returned to the client
+type apiError struct {
+	Code       string
+	Message    string
+	StatusCode int
+}
+
+// Error satisfies the error interface
+func (e *apiError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// S3 errors returned by the server
+var (
+	errAccessDenied          = &apiError{"AccessDenied", "Access Denied", http.StatusForbidden}
+	errBadDigest             = &apiError{"XAmzContentSHA256Mismatch", "The provided 'x-amz-content-sha256' header does not match what was computed.", http.StatusBadRequest}
+	errBucketAlreadyExists   = &apiError{"BucketAlreadyOwnedByYou", "Your previous request to create the named bucket succeeded and you already own it.", http.StatusConflict}
+	errBucketNotEmpty        = &apiError{"BucketNotEmpty", "The bucket you tried to delete is not empty", http.StatusConflict}
+	errInternalError         = &apiError{"InternalError", "We encountered an internal error, please try again.", http.StatusInternalServerError}
+	errInvalidArgument       = &apiError{"InvalidArgument", "Invalid Argument", http.StatusBadRequest}
+	errInvalidPart           = &apiError{"InvalidPart", "One or more of the specified parts could not be found.", http.StatusBadRequest}
+	errInvalidPartOrder      = &apiError{"InvalidPartOrder", "The list of parts was not in ascending order.", http.StatusBadRequest}
+	errInvalidRange          = &apiError{"InvalidRange", "The requested range is not satisfiable", http.StatusRequestedRangeNotSatisfiable}
+	errMalformedXML          = &apiError{"MalformedXML", "The XML you provided was not well-formed or did not validate against our published schema.", http.StatusBadRequest}
+	errMethodNotAllowed      = &apiError{"MethodNotAllowed", "The specified method is not allowed against this resource.", http.StatusMethodNotAllowed}
+	errNoSuchBucket          = &apiError{"NoSuchBucket", "The specified bucket does not exist", http.StatusNotFound}
+	errNoSuchKey             = &apiError{"NoSuchKey", "The specified key does not exist.", http.StatusNotFound}
+	errNoSuchUpload          = &apiError{"NoSuchUpload", "The specified multipart upload does not exist.", http.StatusNotFound}
+	errNotImplemented        = &apiError{"NotImplemented", "A header or query you provided implies functionality that is not implemented.", http.StatusNotImplemented}
+	errSignatureDoesNotMatch = &apiError{"SignatureDoesNotMatch", "The request signature we calculated does not match the signature you provided.", http.StatusForbidden}
+)
+
+// writeXML writes v as the XML body of the response
+func writeXML(w http.ResponseWriter, status int, v interface{}) {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		fs.Errorf(nil, "Failed to marshal XML response: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, err = w.Write([]byte(xml.Header))
+	if err == nil {
+		_, err = w.Write(data)
+	}
+	if err != nil {
+		fs.Errorf(nil, "Failed to write XML response: %v", err)
+	}
+}
+
+// writeError writes e as the response to r
+func writeError(w http.ResponseWriter, r *http.Request, e *apiError) {
+	if r.Method == "HEAD" {
+		// HEAD responses can't have a body
+		w.WriteHeader(e.StatusCode)
+		return
+	}
+	writeXML(w, e.StatusCode, errorResponse{
+		Code:     e.Code,
+		Message:  e.Message,
+		Resource: r.URL.Path,
+	})
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/ncw/rclone/cmd/serve/ftp"
 	"github.com/ncw/rclone/cmd/serve/http"
 	"github.com/ncw/rclone/cmd/serve/restic"
+	"github.com/ncw/rclone/cmd/serve/s3"
+	"github.com/ncw/rclone/cmd/serve/sftp"
 	"github.com/ncw/rclone/cmd/serve/webdav"
 	"github.com/spf13/cobra"
 )
@@ -22,6 +24,10 @@ func init() {
 	if ftp.Command != nil {
 		Command.AddCommand(ftp.Command)
 	}
+	if sftp.Command != nil {
+		Command.AddCommand(sftp.Command)
+	}
+	Command.AddCommand(s3.Command)
 	cmd.Root.AddCommand(Command)
 }
 
@@ -0,0 +1,175 @@
+// +build !plan9
+
+package sftp
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/vfs"
+	"github.com/pkg/sftp"
+)
+
+// vfsHandler converts the VFS to be served by SFTP
+type vfsHandler struct {
+	*vfs.VFS
+}
+
+// newVFSHandler returns the sftp.Handlers which serve the VFS
+func newVFSHandler(VFS *vfs.VFS) sftp.Handlers {
+	v := vfsHandler{VFS: VFS}
+	return sftp.Handlers{
+		FileGet:  v,
+		FilePut:  v,
+		FileCmd:  v,
+		FileList: v,
+	}
+}
+
+// Fileread opens the file for reading
+func (v vfsHandler) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	fs.Debugf(r.Filepath, "SFTP Fileread")
+	file, err := v.OpenFile(r.Filepath, os.O_RDONLY, 0777)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Filewrite opens the file for writing with the flags the client
+// opened it with
+func (v vfsHandler) Filewrite(r *sftp.Request) (io.WriterAt, error) {
+	fs.Debugf(r.Filepath, "SFTP Filewrite")
+	file, err := v.OpenFile(r.Filepath, openFlags(r.Pflags()), 0777)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// openFlags converts the SFTP open flags into os.OpenFile flags
+// suitable for writing
+func openFlags(pflags sftp.FileOpenFlags) (flags int) {
+	if pflags.Read {
+		flags |= os.O_RDWR
+	} else {
+		flags |= os.O_WRONLY
+	}
+	if pflags.Append {
+		flags |= os.O_APPEND
+	}
+	if pflags.Creat {
+		flags |= os.O_CREATE
+	}
+	if pflags.Trunc {
+		flags |= os.O_TRUNC
+	}
+	if pflags.Excl {
+		flags |= os.O_EXCL
+	}
+	return flags
+}
+
+// Filecmd runs the commands which don't return any data
+func (v vfsHandler) Filecmd(r *sftp.Request) error {
+	fs.Debugf(r.Filepath, "SFTP Filecmd %s", r.Method)
+	switch r.Method {
+	case "Setstat":
+		node, err := v.Stat(r.Filepath)
+		if err != nil {
+			return err
+		}
+		attr := r.Attributes()
+		if r.AttrFlags().Acmodtime {
+			modTime := time.Unix(int64(attr.Mtime), 0)
+			err = node.SetModTime(modTime)
+			if err != nil {
+				return err
+			}
+		}
+		if r.AttrFlags().Size {
+			err = node.Truncate(int64(attr.Size))
+			if err != nil {
+				return err
+			}
+		}
+		// UID, GID and permissions are silently ignored
+		return nil
+	case "Rename":
+		return v.Rename(r.Filepath, r.Target)
+	case "Rmdir":
+		node, err := v.Stat(r.Filepath)
+		if err != nil {
+			return err
+		}
+		if !node.IsDir() {
+			return &os.PathError{Op: "rmdir", Path: r.Filepath, Err: syscall.ENOTDIR}
+		}
+		return node.Remove()
+	case "Mkdir":
+		dir, leaf, err := v.StatParent(r.Filepath)
+		if err != nil {
+			return err
+		}
+		_, err = dir.Mkdir(leaf)
+		return err
+	case "Remove":
+		node, err := v.Stat(r.Filepath)
+		if err != nil {
+			return err
+		}
+		return node.Remove()
+	}
+	return sftp.ErrSshFxOpUnsupported
+}
+
+// Filelist lists directories and stats files
+func (v vfsHandler) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	fs.Debugf(r.Filepath, "SFTP Filelist %s", r.Method)
+	switch r.Method {
+	case "List":
+		node, err := v.Stat(r.Filepath)
+		if err != nil {
+			return nil, err
+		}
+		if !node.IsDir() {
+			return nil, &os.PathError{Op: "readdir", Path: r.Filepath, Err: syscall.ENOTDIR}
+		}
+		dir := node.(*vfs.Dir)
+		items, err := dir.ReadDirAll()
+		if err != nil {
+			return nil, err
+		}
+		infos := make(listerat, len(items))
+		for i, item := range items {
+			infos[i] = item
+		}
+		return infos, nil
+	case "Stat":
+		node, err := v.Stat(r.Filepath)
+		if err != nil {
+			return nil, err
+		}
+		return listerat{node}, nil
+	}
+	return nil, sftp.ErrSshFxOpUnsupported
+}
+
+// listerat implements sftp.ListerAt for a slice of os.FileInfo
+type listerat []os.FileInfo
+
+// ListAt copies the entries starting at offset into ls returning
+// io.EOF when there are none left
+func (f listerat) ListAt(ls []os.FileInfo, offset int64) (int, error) {
+	if offset >= int64(len(f)) {
+		return 0, io.EOF
+	}
+	n := copy(ls, f[offset:])
+	if n < len(ls) {
+		return n, io.EOF
+	}
+	return n, nil
+}
@@ -0,0 +1,312 @@
+// +build !plan9
+
+package sftp
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/config"
+	"github.com/ncw/rclone/lib/env"
+	"github.com/ncw/rclone/vfs"
+	"github.com/ncw/rclone/vfs/vfsflags"
+	"github.com/pkg/errors"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// server contains everything to run the server
+type server struct {
+	f        fs.Fs
+	opt      Options
+	vfs      *vfs.VFS
+	config   *ssh.ServerConfig
+	listener net.Listener
+	waitChan chan struct{} // for waiting on the listener to close
+}
+
+func newServer(f fs.Fs, opt *Options) *server {
+	s := &server{
+		f:        f,
+		vfs:      vfs.New(f, &vfsflags.Opt),
+		opt:      *opt,
+		waitChan: make(chan struct{}),
+	}
+	return s
+}
+
+// describeConn returns a string description of the connection for
+// logging
+func describeConn(c ssh.ConnMetadata) string {
+	return fmt.Sprintf("serve sftp %s (%s)", c.RemoteAddr(), c.User())
+}
+
+// Serve SFTP until the listener is closed
+func (s *server) acceptConnections() {
+	for {
+		nConn, err := s.listener.Accept()
+		if err != nil {
+			if strings.Contains(err.Error(), "use of closed network connection") {
+				return
+			}
+			fs.Errorf(nil, "Failed to accept incoming connection: %v", err)
+			continue
+		}
+		what := fmt.Sprintf("serve sftp %s", nConn.RemoteAddr())
+		go s.acceptConnection(nConn, what)
+	}
+}
+
+// acceptConnection does the ssh handshake on nConn then serves its
+// channels
+func (s *server) acceptConnection(nConn net.Conn, what string) {
+	// Before use, a handshake must be performed on the incoming net.Conn.
+	sconn, chans, reqs, err := ssh.NewServerConn(nConn, s.config)
+	if err != nil {
+		fs.Errorf(what, "SSH login failed: %v", err)
+		return
+	}
+
+	fs.Infof(describeConn(sconn), "SSH login from %s using %s", sconn.User(), sconn.ClientVersion())
+
+	// Discard all global out-of-band Requests
+	go ssh.DiscardRequests(reqs)
+
+	// Accept all channels
+	go s.acceptChannels(chans)
+}
+
+// Accept all the channels on the connection
+func (s *server) acceptChannels(chans <-chan ssh.NewChannel) {
+	for newChannel := range chans {
+		// Channels have a type, depending on the application level
+		// protocol intended. In the case of an SFTP session, this is "subsystem"
+		// with a payload string of "<length=4>sftp"
+		if newChannel.ChannelType() != "session" {
+			err := newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			if err != nil {
+				fs.Errorf(nil, "Failed to reject unknown channel: %v", err)
+			}
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			fs.Errorf(nil, "could not accept channel: %v", err)
+			continue
+		}
+
+		// Sessions have out-of-band requests such as "shell",
+		// "pty-req" and "env".  Here we handle only the
+		// "subsystem" request.
+		go func(in <-chan *ssh.Request) {
+			for req := range in {
+				ok := false
+				switch req.Type {
+				case "subsystem":
+					if len(req.Payload) >= 4 && string(req.Payload[4:]) == "sftp" {
+						ok = true
+						go s.serveChannel(channel)
+					}
+				}
+				fs.Debugf(nil, "SFTP subsystem request accepted: %v", ok)
+				err := req.Reply(ok, nil)
+				if err != nil {
+					fs.Errorf(nil, "Failed to Reply to request: %v", err)
+					return
+				}
+			}
+		}(requests)
+	}
+}
+
+// serveChannel serves the VFS over SFTP on the channel
+func (s *server) serveChannel(channel ssh.Channel) {
+	defer func() {
+		err := channel.Close()
+		if err != nil && err != io.EOF {
+			fs.Debugf(nil, "Failed to close channel: %v", err)
+		}
+	}()
+	server := sftp.NewRequestServer(channel, newVFSHandler(s.vfs))
+	err := server.Serve()
+	if err != nil && err != io.EOF {
+		fs.Errorf(nil, "SFTP server exited with error: %v", err)
+		return
+	}
+	fs.Debugf(nil, "SFTP session closed")
+}
+
+// loadAuthorizedKeys reads the public keys from the authorized keys
+// file into a map keyed on the marshalled key
+func loadAuthorizedKeys(authorizedKeysPath string) (map[string]struct{}, error) {
+	authorizedKeysBytes, err := ioutil.ReadFile(authorizedKeysPath)
+	if err != nil {
+		return nil, err
+	}
+	authorizedKeysMap := map[string]struct{}{}
+	for len(authorizedKeysBytes) > 0 {
+		pubKey, _, _, rest, err := ssh.ParseAuthorizedKey(authorizedKeysBytes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse authorized keys %q", authorizedKeysPath)
+		}
+		authorizedKeysMap[string(pubKey.Marshal())] = struct{}{}
+		authorizedKeysBytes = bytes.TrimSpace(rest)
+	}
+	return authorizedKeysMap, nil
+}
+
+// makeConfig makes the ssh.ServerConfig from the options
+func (s *server) makeConfig() error {
+	s.config = &ssh.ServerConfig{
+		ServerVersion: "SSH-2.0-" + fs.Config.UserAgent,
+		NoClientAuth:  s.opt.NoAuth,
+	}
+
+	// Password authentication
+	if s.opt.User != "" && s.opt.Pass != "" {
+		s.config.PasswordCallback = func(c ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			fs.Debugf(describeConn(c), "Password login attempt for %s", c.User())
+			if c.User() == s.opt.User && subtle.ConstantTimeCompare(pass, []byte(s.opt.Pass)) == 1 {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("password rejected for %q", c.User())
+		}
+	}
+
+	// Public key authentication
+	if s.opt.AuthorizedKeys != "" && !s.opt.NoAuth {
+		authorizedKeysPath := env.ExpandHome(s.opt.AuthorizedKeys)
+		authorizedKeysMap, err := loadAuthorizedKeys(authorizedKeysPath)
+		if err == nil {
+			s.config.PublicKeyCallback = func(c ssh.ConnMetadata, pubKey ssh.PublicKey) (*ssh.Permissions, error) {
+				fs.Debugf(describeConn(c), "Public key login attempt for %s", c.User())
+				if s.opt.User != "" && c.User() != s.opt.User {
+					return nil, fmt.Errorf("unknown user %q", c.User())
+				}
+				if _, ok := authorizedKeysMap[string(pubKey.Marshal())]; ok {
+					return &ssh.Permissions{
+						// Record the public key used for authentication.
+						Extensions: map[string]string{
+							"pubkey-fp": ssh.FingerprintSHA256(pubKey),
+						},
+					}, nil
+				}
+				return nil, fmt.Errorf("unknown public key for %q", c.User())
+			}
+		} else if !(os.IsNotExist(err) && s.opt.AuthorizedKeys == DefaultOpt.AuthorizedKeys) {
+			// Only complain about a missing file if it isn't the default
+			return errors.Wrap(err, "failed to load authorized keys")
+		}
+	}
+
+	if !s.opt.NoAuth && s.config.PasswordCallback == nil && s.config.PublicKeyCallback == nil {
+		return errors.New("no authorization found, use --user/--pass or --authorized-keys or --no-auth")
+	}
+
+	// Load the private key, from the cache if not explicitly configured
+	keyPaths := s.opt.HostKeys
+	if len(keyPaths) == 0 {
+		cachePath := filepath.Join(config.CacheDir, "serve-sftp")
+		keyPath := filepath.Join(cachePath, "id_rsa")
+		if err := makeRSAKey(keyPath); err != nil {
+			return err
+		}
+		keyPaths = append(keyPaths, keyPath)
+	}
+
+	for _, keyPath := range keyPaths {
+		fs.Debugf(nil, "Loading private key from %q", keyPath)
+		privateBytes, err := ioutil.ReadFile(env.ExpandHome(keyPath))
+		if err != nil {
+			return errors.Wrap(err, "failed to load private key")
+		}
+		private, err := ssh.ParsePrivateKey(privateBytes)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse private key")
+		}
+		s.config.AddHostKey(private)
+	}
+	return nil
+}
+
+// makeRSAKey makes a new RSA host key in keyPath if it doesn't
+// already exist
+func makeRSAKey(keyPath string) error {
+	if _, err := os.Stat(keyPath); err == nil {
+		return nil
+	}
+	fs.Logf(nil, "Generating 2048 bit key pair at %q", keyPath)
+	err := os.MkdirAll(filepath.Dir(keyPath), 0700)
+	if err != nil {
+		return errors.Wrap(err, "failed to create host key directory")
+	}
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate host key")
+	}
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	})
+	err = ioutil.WriteFile(keyPath, privateKeyPEM, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to save host key")
+	}
+	return nil
+}
+
+// Serve SFTP on the address in the options
+//
+// This is non blocking - use Wait to wait for the server to finish
+func (s *server) Serve() (err error) {
+	err = s.makeConfig()
+	if err != nil {
+		return err
+	}
+
+	// Once a ServerConfig has been configured, connections can be
+	// accepted.
+	s.listener, err = net.Listen("tcp", s.opt.ListenAddr)
+	if err != nil {
+		return errors.Wrap(err, "failed to listen for connection")
+	}
+	fs.Logf(nil, "SFTP server listening on %v", s.listener.Addr())
+
+	go func() {
+		s.acceptConnections()
+		close(s.waitChan)
+	}()
+	return nil
+}
+
+// Addr returns the address the server is listening on
+func (s *server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Wait blocks while the listener is open.
+func (s *server) Wait() {
+	<-s.waitChan
+}
+
+// Close shuts the running server down
+func (s *server) Close() {
+	err := s.listener.Close()
+	if err != nil {
+		fs.Errorf(nil, "Error on closing SFTP server: %v", err)
+		return
+	}
+	s.Wait()
+}
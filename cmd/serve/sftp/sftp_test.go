@@ -0,0 +1,106 @@
+// Serve sftp tests set up a server and run the integration tests
+// for the sftp remote against it.
+//
+// We skip tests on platforms with troublesome character mappings
+
+//+build !windows,!darwin,!plan9
+
+package sftp
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "github.com/ncw/rclone/backend/local"
+	"github.com/ncw/rclone/fs/config/obscure"
+	"github.com/ncw/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testBindAddress = "localhost:0"
+	testUser        = "testuser"
+	testPass        = "testpass"
+)
+
+// TestSftp runs the sftp server then runs the unit tests for the
+// sftp remote against it.
+func TestSftp(t *testing.T) {
+	fstest.Initialise()
+
+	fremote, _, clean, err := fstest.RandomRemote(*fstest.RemoteName, *fstest.SubDir)
+	assert.NoError(t, err)
+	defer clean()
+
+	err = fremote.Mkdir(context.Background(), "")
+	assert.NoError(t, err)
+
+	// Make a host key
+	keyDir, err := ioutil.TempDir("", "rclone-serve-sftp")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(keyDir)
+	}()
+	keyPath := filepath.Join(keyDir, "sub", "id_rsa")
+	require.NoError(t, makeRSAKey(keyPath))
+
+	opt := DefaultOpt
+	opt.ListenAddr = testBindAddress
+	opt.HostKeys = []string{keyPath}
+	opt.User = testUser
+	opt.Pass = testPass
+
+	// Start the server
+	w := newServer(fremote, &opt)
+	assert.NoError(t, w.Serve())
+	defer w.Close()
+
+	// Change directory to run the tests
+	err = os.Chdir("../../../backend/sftp")
+	assert.NoError(t, err, "failed to cd to sftp backend")
+
+	// Run the sftp tests with an on the fly remote
+	args := []string{"test"}
+	if testing.Verbose() {
+		args = append(args, "-v")
+	}
+	if *fstest.Verbose {
+		args = append(args, "-verbose")
+	}
+	args = append(args, "-list-retries", fmt.Sprint(*fstest.ListRetries))
+	args = append(args, "-remote", "sftptest:")
+	addr := w.Addr()
+	colon := strings.LastIndex(addr, ":")
+	cmd := exec.Command("go", args...)
+	cmd.Env = append(os.Environ(),
+		"RCLONE_CONFIG_SFTPTEST_TYPE=sftp",
+		"RCLONE_CONFIG_SFTPTEST_HOST="+addr[:colon],
+		"RCLONE_CONFIG_SFTPTEST_PORT="+addr[colon+1:],
+		"RCLONE_CONFIG_SFTPTEST_USER="+testUser,
+		"RCLONE_CONFIG_SFTPTEST_PASS="+obscure.MustObscure(testPass),
+	)
+	out, err := cmd.CombinedOutput()
+	if len(out) != 0 {
+		t.Logf("\n----------\n%s----------\n", string(out))
+	}
+	assert.NoError(t, err, "Running sftp integration tests")
+}
+
+// TestNoAuth checks the server refuses to start without some form
+// of authentication
+func TestNoAuth(t *testing.T) {
+	opt := DefaultOpt
+	opt.ListenAddr = testBindAddress
+	opt.AuthorizedKeys = ""
+	w := &server{opt: opt}
+	err := w.makeConfig()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no authorization found")
+}
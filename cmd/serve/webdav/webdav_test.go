@@ -8,6 +8,7 @@
 package webdav
 
 import (
+	"context"
 	"os"
 	"os/exec"
 	"testing"
@@ -34,6 +35,7 @@ var (
 // TestWebDav runs the webdav server then runs the unit tests for the
 // webdav remote against it.
 func TestWebDav(t *testing.T) {
+	ctx := context.Background()
 	opt := httplib.DefaultOpt
 	opt.ListenAddr = testBindAddress
 
@@ -43,7 +45,7 @@ func TestWebDav(t *testing.T) {
 	assert.NoError(t, err)
 	defer clean()
 
-	err = fremote.Mkdir("")
+	err = fremote.Mkdir(ctx, "")
 	assert.NoError(t, err)
 
 	// Start the server
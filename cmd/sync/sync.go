@@ -1,13 +1,17 @@
 package sync
 
 import (
+	"context"
+
 	"github.com/ncw/rclone/cmd"
+	"github.com/ncw/rclone/cmd/check"
 	"github.com/ncw/rclone/fs/sync"
 	"github.com/spf13/cobra"
 )
 
 func init() {
 	cmd.Root.AddCommand(commandDefintion)
+	check.AddReportFlags(commandDefintion.Flags())
 }
 
 var commandDefintion = &cobra.Command{
@@ -34,12 +38,27 @@ If dest:path doesn't exist, it is created and the source:path contents
 go there.
 
 **Note**: Use the ` + "`-P`" + `/` + "`--progress`" + ` flag to view real-time transfer statistics
-`,
+
+The report flags below write the files sync looked at according to
+what it did with them.  Identical files are reported as matching,
+updated files as different, new files as missing on the destination
+and deleted files as missing on the source.
+` + check.ReportsHelp,
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(2, 2, command, args)
 		fsrc, fdst := cmd.NewFsSrcDst(args)
-		cmd.Run(true, true, command, func() error {
-			return sync.Sync(fdst, fsrc)
+		cmd.Run(true, true, command, func() (err error) {
+			ctx, closeReports, err := check.OpenReports(context.Background())
+			if err != nil {
+				return err
+			}
+			defer func() {
+				closeErr := closeReports()
+				if err == nil {
+					err = closeErr
+				}
+			}()
+			return sync.Sync(ctx, fdst, fsrc)
 		})
 	},
 }
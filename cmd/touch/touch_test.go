@@ -1,6 +1,7 @@
 package touch
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -31,50 +32,55 @@ func TestMain(m *testing.M) {
 }
 
 func TestTouchOneFile(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
-	err := Touch(r.Fremote, "newFile")
+	err := Touch(ctx, r.Fremote, "newFile")
 	require.NoError(t, err)
-	_, err = r.Fremote.NewObject("newFile")
+	_, err = r.Fremote.NewObject(ctx, "newFile")
 	require.NoError(t, err)
 }
 
 func TestTouchWithNoCreateFlag(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
 	notCreateNewFile = true
-	err := Touch(r.Fremote, "newFile")
+	err := Touch(ctx, r.Fremote, "newFile")
 	require.NoError(t, err)
-	_, err = r.Fremote.NewObject("newFile")
+	_, err = r.Fremote.NewObject(ctx, "newFile")
 	require.Error(t, err)
 	notCreateNewFile = false
 }
 
 func TestTouchWithTimestamp(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
 	timeAsArgument = "060102"
 	srcFileName := "oldFile"
-	err := Touch(r.Fremote, srcFileName)
+	err := Touch(ctx, r.Fremote, srcFileName)
 	require.NoError(t, err)
 	checkFile(t, r.Fremote, srcFileName, "")
 }
 
 func TestTouchWithLognerTimestamp(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
 	timeAsArgument = "2006-01-02T15:04:05"
 	srcFileName := "oldFile"
-	err := Touch(r.Fremote, srcFileName)
+	err := Touch(ctx, r.Fremote, srcFileName)
 	require.NoError(t, err)
 	checkFile(t, r.Fremote, srcFileName, "")
 }
 
 func TestTouchUpdateTimestamp(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
@@ -84,12 +90,13 @@ func TestTouchUpdateTimestamp(t *testing.T) {
 	fstest.CheckItems(t, r.Fremote, file1)
 
 	timeAsArgument = "121212"
-	err := Touch(r.Fremote, "a")
+	err := Touch(ctx, r.Fremote, "a")
 	require.NoError(t, err)
 	checkFile(t, r.Fremote, srcFileName, content)
 }
 
 func TestTouchUpdateTimestampWithCFlag(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
@@ -100,18 +107,19 @@ func TestTouchUpdateTimestampWithCFlag(t *testing.T) {
 
 	notCreateNewFile = true
 	timeAsArgument = "121212"
-	err := Touch(r.Fremote, "a")
+	err := Touch(ctx, r.Fremote, "a")
 	require.NoError(t, err)
 	checkFile(t, r.Fremote, srcFileName, content)
 	notCreateNewFile = false
 }
 
 func TestTouchCreateMultipleDirAndFile(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
 	longPath := "a/b/c.txt"
-	err := Touch(r.Fremote, longPath)
+	err := Touch(ctx, r.Fremote, longPath)
 	require.NoError(t, err)
 	file1 := fstest.NewItem("a/b/c.txt", "", t1)
 	fstest.CheckListingWithPrecision(t, r.Fremote, []fstest.Item{file1}, []string{"a", "a/b"}, fs.ModTimeNotSupported)
@@ -2,6 +2,7 @@ package tree
 
 import (
 	"bytes"
+	"context"
 	"testing"
 
 	"github.com/a8m/tree"
@@ -13,13 +14,14 @@ import (
 )
 
 func TestTree(t *testing.T) {
+	ctx := context.Background()
 	fstest.Initialise()
 
 	buf := new(bytes.Buffer)
 
 	f, err := fs.NewFs("testfiles")
 	require.NoError(t, err)
-	err = Tree(f, buf, new(tree.Options))
+	err = Tree(ctx, f, buf, new(tree.Options))
 	require.NoError(t, err)
 	assert.Equal(t, `/
 ├── file1
@@ -25,6 +25,7 @@ type Account struct {
 	// CancelRequest so this race can happen when it apparently
 	// shouldn't.
 	mu      sync.Mutex
+	stats   *StatsInfo // the stats this transfer is accounted in
 	in      io.Reader
 	origIn  io.ReadCloser
 	close   io.Closer
@@ -47,7 +48,19 @@ const averagePeriod = 16 // period to do exponentially weighted averages over
 // NewAccountSizeName makes a Account reader for an io.ReadCloser of
 // the given size and name
 func NewAccountSizeName(in io.ReadCloser, size int64, name string) *Account {
+	return Stats.NewAccountSizeName(in, size, name)
+}
+
+// NewAccount makes a Account reader for an object
+func NewAccount(in io.ReadCloser, obj fs.Object) *Account {
+	return Stats.NewAccount(in, obj)
+}
+
+// NewAccountSizeName makes a Account reader for an io.ReadCloser of
+// the given size and name which is accounted in s
+func (s *StatsInfo) NewAccountSizeName(in io.ReadCloser, size int64, name string) *Account {
 	acc := &Account{
+		stats:  s,
 		in:     in,
 		close:  in,
 		origIn: in,
@@ -59,13 +72,14 @@ func NewAccountSizeName(in io.ReadCloser, size int64, name string) *Account {
 		max:    int64(fs.Config.MaxTransfer),
 	}
 	go acc.averageLoop()
-	Stats.inProgress.set(acc.name, acc)
+	s.setInProgress(acc)
 	return acc
 }
 
-// NewAccount makes a Account reader for an object
-func NewAccount(in io.ReadCloser, obj fs.Object) *Account {
-	return NewAccountSizeName(in, obj.Size(), obj.Remote())
+// NewAccount makes a Account reader for an object which is accounted
+// in s
+func (s *StatsInfo) NewAccount(in io.ReadCloser, obj fs.Object) *Account {
+	return s.NewAccountSizeName(in, obj.Size(), obj.Remote())
 }
 
 // WithBuffer - If the file is above a certain size it adds an Async reader
@@ -153,31 +167,58 @@ func (acc *Account) averageLoop() {
 	}
 }
 
-// read bytes from the io.Reader passed in and account them
-func (acc *Account) read(in io.Reader, p []byte) (n int, err error) {
+// checkRead checks the transfer limit before a read and starts the
+// timer on the first one
+func (acc *Account) checkRead() error {
 	acc.statmu.Lock()
+	defer acc.statmu.Unlock()
 	if acc.max >= 0 && Stats.GetBytes() >= acc.max {
-		acc.statmu.Unlock()
-		return 0, ErrorMaxTransferLimitReached
+		return ErrorMaxTransferLimitReached
 	}
 	// Set start time.
 	if acc.start.IsZero() {
 		acc.start = time.Now()
 	}
-	acc.statmu.Unlock()
-
-	n, err = in.Read(p)
+	return nil
+}
 
+// accountRead updates the stats and limits the bandwidth after n
+// bytes have been read
+func (acc *Account) accountRead(n int) {
 	// Update Stats
 	acc.statmu.Lock()
 	acc.lpBytes += n
 	acc.bytes += int64(n)
 	acc.statmu.Unlock()
 
-	Stats.Bytes(int64(n))
+	acc.stats.Bytes(int64(n))
 
 	limitBandwidth(n)
-	return
+}
+
+// read bytes from the io.Reader passed in and account them
+func (acc *Account) read(in io.Reader, p []byte) (n int, err error) {
+	err = acc.checkRead()
+	if err != nil {
+		return 0, err
+	}
+	n, err = in.Read(p)
+	acc.accountRead(n)
+	return n, err
+}
+
+// AccountRead accounts for n bytes read by the caller rather than
+// through the Account.  This is for transfers which read from more
+// than one stream at once.
+//
+// It may be called concurrently.
+func (acc *Account) AccountRead(n int) error {
+	err := acc.checkRead()
+	if err != nil {
+		return err
+	}
+	acc.accountRead(n)
+	return nil
 }
 
 // Read bytes from the object - see io.Reader
@@ -196,7 +237,10 @@ func (acc *Account) Close() error {
 	}
 	acc.closed = true
 	close(acc.exit)
-	Stats.inProgress.clear(acc.name)
+	acc.stats.doneInProgress(acc)
+	if acc.close == nil {
+		return nil
+	}
 	return acc.close.Close()
 }
 
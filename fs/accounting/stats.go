@@ -2,6 +2,7 @@ package accounting
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 	"sync"
@@ -19,17 +20,22 @@ var (
 
 func init() {
 	// Set the function pointer up in fs
-	fs.CountError = Stats.Error
+	fs.CountError = func(ctx context.Context, err error) { GetStats(ctx).Error(err) }
 
 	rc.Add(rc.Call{
 		Path:  "core/stats",
-		Fn:    Stats.RemoteStats,
+		Fn:    rcStats,
 		Title: "Returns stats about current transfers.",
 		Help: `
 This returns all available stats
 
 	rclone rc core/stats
 
+If group is not provided then the stats for all the transfers are
+returned, otherwise only those for the stats group, eg
+
+	rclone rc core/stats group=job/42
+
 Returns the following values:
 
 ` + "```" + `
@@ -87,6 +93,10 @@ type StatsInfo struct {
 	deletes           int64
 	start             time.Time
 	inProgress        *inProgress
+	started           map[transferKey]*Transfer // the transfers in progress
+	transferred       []Transfer                // the last maxCompletedTransfers completed transfers
+	group             string                    // name of the stats group or "" for the global Stats
+	parent            *StatsInfo                // if set all the updates are made to this too
 }
 
 // NewStats cretates an initialised StatsInfo
@@ -96,11 +106,12 @@ func NewStats() *StatsInfo {
 		transferring: newStringSet(fs.Config.Transfers),
 		start:        time.Now(),
 		inProgress:   newInProgress(),
+		started:      make(map[transferKey]*Transfer),
 	}
 }
 
 // RemoteStats returns stats for rc
-func (s *StatsInfo) RemoteStats(in rc.Params) (out rc.Params, err error) {
+func (s *StatsInfo) RemoteStats(ctx context.Context, in rc.Params) (out rc.Params, err error) {
 	out = make(rc.Params)
 	s.mu.RLock()
 	dt := time.Now().Sub(s.start)
@@ -281,12 +292,26 @@ Elapsed time:  %10v
 }
 
 // Log outputs the StatsInfo to the log
+//
+// With --use-json-log the stats are in the "stats" field of the
+// entry in the same format as the core/stats remote control call.
 func (s *StatsInfo) Log() {
+	if fs.Config.UseJSONLog {
+		out, _ := s.RemoteStats(context.Background(), nil)
+		if err, ok := out["lastError"].(error); ok {
+			out["lastError"] = err.Error()
+		}
+		fs.LogLevelPrintf(fs.Config.StatsLogLevel, nil, "Transfer stats%v", fs.LogValueHide("stats", out))
+		return
+	}
 	fs.LogLevelPrintf(fs.Config.StatsLogLevel, nil, "%v\n", s)
 }
 
 // Bytes updates the stats for bytes bytes
 func (s *StatsInfo) Bytes(bytes int64) {
+	if s.parent != nil {
+		s.parent.Bytes(bytes)
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.bytes += bytes
@@ -301,6 +326,9 @@ func (s *StatsInfo) GetBytes() int64 {
 
 // Errors updates the stats for errors
 func (s *StatsInfo) Errors(errors int64) {
+	if s.parent != nil {
+		s.parent.Errors(errors)
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.errors += errors
@@ -322,6 +350,9 @@ func (s *StatsInfo) GetLastError() error {
 
 // FatalError sets the fatalError flag
 func (s *StatsInfo) FatalError() {
+	if s.parent != nil {
+		s.parent.FatalError()
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.fatalError = true
@@ -336,6 +367,9 @@ func (s *StatsInfo) HadFatalError() bool {
 
 // RetryError sets the retryError flag
 func (s *StatsInfo) RetryError() {
+	if s.parent != nil {
+		s.parent.RetryError()
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.retryError = true
@@ -350,13 +384,16 @@ func (s *StatsInfo) HadRetryError() bool {
 
 // Deletes updates the stats for deletes
 func (s *StatsInfo) Deletes(deletes int64) int64 {
+	if s.parent != nil {
+		s.parent.Deletes(deletes)
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.deletes += deletes
 	return s.deletes
 }
 
-// ResetCounters sets the counters (bytes, checks, errors, transfers, deletes) to 0 and resets lastError, fatalError and retryError
+// ResetCounters sets the counters (bytes, checks, errors, transfers, deletes) to 0, resets lastError, fatalError and retryError and forgets the completed transfers
 func (s *StatsInfo) ResetCounters() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -368,6 +405,7 @@ func (s *StatsInfo) ResetCounters() {
 	s.checks = 0
 	s.transfers = 0
 	s.deletes = 0
+	s.transferred = nil
 }
 
 // ResetErrors sets the errors count to 0 and resets lastError, fatalError and retryError
@@ -389,6 +427,9 @@ func (s *StatsInfo) Errored() bool {
 
 // Error adds a single error into the stats, assigns lastError and eventually sets fatalError or retryError
 func (s *StatsInfo) Error(err error) {
+	if s.parent != nil {
+		s.parent.Error(err)
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.errors++
@@ -403,11 +444,17 @@ func (s *StatsInfo) Error(err error) {
 
 // Checking adds a check into the stats
 func (s *StatsInfo) Checking(remote string) {
+	if s.parent != nil {
+		s.parent.Checking(remote)
+	}
 	s.checking.add(remote)
 }
 
 // DoneChecking removes a check from the stats
 func (s *StatsInfo) DoneChecking(remote string) {
+	if s.parent != nil {
+		s.parent.DoneChecking(remote)
+	}
 	s.checking.del(remote)
 	s.mu.Lock()
 	s.checks++
@@ -421,25 +468,129 @@ func (s *StatsInfo) GetTransfers() int64 {
 	return s.transfers
 }
 
+// GetChecks reads the number of checks
+func (s *StatsInfo) GetChecks() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.checks
+}
+
+// GetDeletes reads the number of deletes
+func (s *StatsInfo) GetDeletes() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.deletes
+}
+
+// CurrentSpeed returns the sum of the moving average speeds of the
+// transfers in progress in bytes/s
+func (s *StatsInfo) CurrentSpeed() (speed float64) {
+	s.transferring.mu.RLock()
+	defer s.transferring.mu.RUnlock()
+	for name := range s.transferring.items {
+		_, current := s.inProgress.get(name).speed()
+		speed += current
+	}
+	return speed
+}
+
+// transferKey identifies a transfer in progress.  The group is
+// needed as transfers of the same remote in different groups are all
+// accounted in the global Stats.
+type transferKey struct {
+	group  string
+	remote string
+}
+
 // Transferring adds a transfer into the stats
 func (s *StatsInfo) Transferring(remote string) {
+	s.startTransfer(remote, s.group)
+}
+
+// startTransfer adds a transfer for group into the stats and its
+// parents
+func (s *StatsInfo) startTransfer(remote string, group string) {
+	if s.parent != nil {
+		s.parent.startTransfer(remote, group)
+	}
 	s.transferring.add(remote)
+	s.mu.Lock()
+	s.started[transferKey{group, remote}] = &Transfer{
+		Name:      remote,
+		Size:      -1,
+		Group:     group,
+		StartedAt: time.Now(),
+	}
+	s.mu.Unlock()
 }
 
-// DoneTransferring removes a transfer from the stats
+// DoneTransferring removes a transfer from the stats and adds it to
+// the completed transfers
 //
 // if ok is true then it increments the transfers count
 func (s *StatsInfo) DoneTransferring(remote string, ok bool) {
+	s.doneTransfer(remote, s.group, ok)
+}
+
+// doneTransfer removes a transfer for group from the stats and its
+// parents
+func (s *StatsInfo) doneTransfer(remote string, group string, ok bool) {
+	if s.parent != nil {
+		s.parent.doneTransfer(remote, group, ok)
+	}
 	s.transferring.del(remote)
+	acc := s.inProgress.get(remote)
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if ok {
-		s.mu.Lock()
 		s.transfers++
-		s.mu.Unlock()
 	}
+	key := transferKey{group, remote}
+	transfer := s.started[key]
+	if transfer == nil {
+		return
+	}
+	delete(s.started, key)
+	if acc != nil {
+		transfer.Bytes, transfer.Size = acc.progress()
+	}
+	transfer.CompletedAt = time.Now()
+	transfer.Success = ok
+	if len(s.transferred) >= maxCompletedTransfers {
+		s.transferred = append(s.transferred[:0], s.transferred[1:]...)
+	}
+	s.transferred = append(s.transferred, *transfer)
+}
+
+// setInProgress registers acc as in progress in s and its parents
+func (s *StatsInfo) setInProgress(acc *Account) {
+	if s.parent != nil {
+		s.parent.setInProgress(acc)
+	}
+	s.inProgress.set(acc.name, acc)
+}
+
+// doneInProgress is called when acc is closed to remove it from the
+// transfers in progress in s and its parents, noting how much of the
+// transfer it did.
+func (s *StatsInfo) doneInProgress(acc *Account) {
+	if s.parent != nil {
+		s.parent.doneInProgress(acc)
+	}
+	s.inProgress.clear(acc.name)
+	bytes, size := acc.progress()
+	s.mu.Lock()
+	if transfer := s.started[transferKey{acc.stats.group, acc.name}]; transfer != nil {
+		transfer.Bytes, transfer.Size = bytes, size
+	}
+	s.mu.Unlock()
 }
 
 // SetCheckQueue sets the number of queued checks
 func (s *StatsInfo) SetCheckQueue(n int, size int64) {
+	if s.parent != nil {
+		s.parent.SetCheckQueue(n, size)
+	}
 	s.mu.Lock()
 	s.checkQueue = n
 	s.checkQueueSize = size
@@ -448,6 +599,9 @@ func (s *StatsInfo) SetCheckQueue(n int, size int64) {
 
 // SetTransferQueue sets the number of queued transfers
 func (s *StatsInfo) SetTransferQueue(n int, size int64) {
+	if s.parent != nil {
+		s.parent.SetTransferQueue(n, size)
+	}
 	s.mu.Lock()
 	s.transferQueue = n
 	s.transferQueueSize = size
@@ -456,6 +610,9 @@ func (s *StatsInfo) SetTransferQueue(n int, size int64) {
 
 // SetRenameQueue sets the number of queued transfers
 func (s *StatsInfo) SetRenameQueue(n int, size int64) {
+	if s.parent != nil {
+		s.parent.SetRenameQueue(n, size)
+	}
 	s.mu.Lock()
 	s.renameQueue = n
 	s.renameQueueSize = size
@@ -0,0 +1,199 @@
+package accounting
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ncw/rclone/fs/rc"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxStatsGroups is the number of stats groups kept - the
+	// oldest is forgotten when a new one is made after this
+	maxStatsGroups = 1000
+	// maxCompletedTransfers is the number of completed transfers
+	// each StatsInfo remembers for core/transferred
+	maxCompletedTransfers = 100
+)
+
+// Transfer describes a completed transfer for core/transferred
+type Transfer struct {
+	Name        string    `json:"name"`
+	Size        int64     `json:"size"`
+	Bytes       int64     `json:"bytes"`
+	Group       string    `json:"group"`
+	StartedAt   time.Time `json:"startedAt"`
+	CompletedAt time.Time `json:"completedAt"`
+	Success     bool      `json:"success"`
+}
+
+// statsGroups holds the named stats groups in the order they were
+// made
+type statsGroups struct {
+	mu    sync.Mutex
+	m     map[string]*StatsInfo
+	order []string
+}
+
+var groups = &statsGroups{
+	m: make(map[string]*StatsInfo),
+}
+
+// get returns the stats for group making it if necessary
+func (sg *statsGroups) get(group string) *StatsInfo {
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	if stats, ok := sg.m[group]; ok {
+		return stats
+	}
+	if len(sg.order) >= maxStatsGroups {
+		delete(sg.m, sg.order[0])
+		sg.order = sg.order[1:]
+	}
+	stats := NewStats()
+	stats.group = group
+	stats.parent = Stats
+	sg.m[group] = stats
+	sg.order = append(sg.order, group)
+	return stats
+}
+
+// find returns the stats for group or nil if it doesn't exist
+func (sg *statsGroups) find(group string) *StatsInfo {
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	return sg.m[group]
+}
+
+// statsKey is the context key for the StatsInfo
+type statsKey struct{}
+
+// WithStatsGroup returns a copy of ctx which makes the transfers
+// made with it be accounted in the stats group called group as well
+// as in the global Stats.
+func WithStatsGroup(ctx context.Context, group string) context.Context {
+	return context.WithValue(ctx, statsKey{}, groups.get(group))
+}
+
+// GetStats returns the stats for the group set in ctx with
+// WithStatsGroup or the global Stats if there isn't one.
+func GetStats(ctx context.Context) *StatsInfo {
+	if stats, ok := ctx.Value(statsKey{}).(*StatsInfo); ok {
+		return stats
+	}
+	return Stats
+}
+
+// StatsGroup returns the stats for group or nil if it doesn't exist.
+// The group "" is the global Stats.
+func StatsGroup(group string) *StatsInfo {
+	if group == "" {
+		return Stats
+	}
+	return groups.find(group)
+}
+
+// Transferred returns the most recently completed transfers, oldest
+// first
+func (s *StatsInfo) Transferred() []Transfer {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Transfer(nil), s.transferred...)
+}
+
+func init() {
+	// Set the function pointer up in rc
+	rc.GroupContext = WithStatsGroup
+
+	rc.Add(rc.Call{
+		Path:  "core/stats-reset",
+		Fn:    rcStatsReset,
+		Title: "Reset stats.",
+		Help: `
+This clears counters, errors and the completed transfers for all
+the stats or for a specific stats group if group is provided.
+
+Parameters
+- group - name of the stats group (string)
+`,
+	})
+	rc.Add(rc.Call{
+		Path:  "core/transferred",
+		Fn:    rcTransferred,
+		Title: "Returns stats about completed transfers.",
+		Help: `
+This returns stats about the last 100 completed transfers
+
+	rclone rc core/transferred
+
+If group is not provided then the completed transfers for all the
+groups are returned, otherwise only those for the stats group, eg
+
+	rclone rc core/transferred group=job/42
+
+Returns the following values:
+` + "```" + `
+{
+	"transferred": an array of completed transfers (including failed ones):
+		[
+			{
+				"name": name of the file,
+				"size": size of the file in bytes or -1 if unknown,
+				"bytes": total transferred bytes for this file,
+				"group": stats group this transfer belonged to,
+				"startedAt": time the transfer was started at (eg "2018-10-26T18:50:20.528336039+01:00"),
+				"completedAt": time the transfer was completed at (eg "2018-10-26T18:50:20.528746884+01:00"),
+				"success": boolean - true for success false otherwise
+			}
+		]
+}
+` + "```" + `
+`,
+	})
+}
+
+// getGroup returns the stats for the group parameter in in or the
+// global Stats if it isn't set
+func getGroup(in rc.Params) (*StatsInfo, error) {
+	group, err := in.GetString("group")
+	if rc.NotErrParamNotFound(err) {
+		return nil, err
+	}
+	stats := StatsGroup(group)
+	if stats == nil {
+		return nil, errors.Errorf("stats group %q not found", group)
+	}
+	return stats, nil
+}
+
+// rcStats returns the stats for core/stats
+func rcStats(ctx context.Context, in rc.Params) (out rc.Params, err error) {
+	stats, err := getGroup(in)
+	if err != nil {
+		return nil, err
+	}
+	return stats.RemoteStats(ctx, in)
+}
+
+// rcStatsReset resets the stats for core/stats-reset
+func rcStatsReset(ctx context.Context, in rc.Params) (out rc.Params, err error) {
+	stats, err := getGroup(in)
+	if err != nil {
+		return nil, err
+	}
+	stats.ResetCounters()
+	return nil, nil
+}
+
+// rcTransferred returns the completed transfers for core/transferred
+func rcTransferred(ctx context.Context, in rc.Params) (out rc.Params, err error) {
+	stats, err := getGroup(in)
+	if err != nil {
+		return nil, err
+	}
+	out = make(rc.Params)
+	out["transferred"] = stats.Transferred()
+	return out, nil
+}
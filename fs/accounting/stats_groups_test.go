@@ -0,0 +1,150 @@
+package accounting
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/ncw/rclone/fs/rc"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsGroups(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, Stats, GetStats(ctx))
+	assert.Equal(t, Stats, StatsGroup(""))
+	assert.Nil(t, StatsGroup("test/groups"))
+
+	groupCtx := WithStatsGroup(ctx, "test/groups")
+	stats := GetStats(groupCtx)
+	assert.NotEqual(t, Stats, stats)
+	assert.Equal(t, stats, StatsGroup("test/groups"))
+	assert.Equal(t, stats, GetStats(WithStatsGroup(ctx, "test/groups")))
+
+	// Updates go to the group and the global stats
+	oldBytes, oldErrors := Stats.GetBytes(), Stats.GetErrors()
+	stats.Bytes(10)
+	stats.Error(errors.New("potato"))
+	assert.Equal(t, int64(10), stats.GetBytes())
+	assert.Equal(t, int64(1), stats.GetErrors())
+	assert.Equal(t, oldBytes+10, Stats.GetBytes())
+	assert.Equal(t, oldErrors+1, Stats.GetErrors())
+}
+
+func TestStatsGroupsExpire(t *testing.T) {
+	sg := &statsGroups{m: make(map[string]*StatsInfo)}
+	first := sg.get("first")
+	for i := 1; i < maxStatsGroups; i++ {
+		sg.get(string(rune('a' + i)))
+	}
+	assert.Equal(t, first, sg.find("first"))
+	sg.get("one too many")
+	assert.Nil(t, sg.find("first"))
+	assert.Equal(t, maxStatsGroups, len(sg.m))
+}
+
+func TestStatsTransferred(t *testing.T) {
+	stats := GetStats(WithStatsGroup(context.Background(), "test/transferred"))
+
+	stats.Transferring("file1")
+	acc := stats.NewAccountSizeName(ioutil.NopCloser(bytes.NewBufferString("hello")), 5, "file1")
+	_, err := ioutil.ReadAll(acc)
+	require.NoError(t, err)
+	require.NoError(t, acc.Close())
+	stats.DoneTransferring("file1", true)
+
+	stats.Transferring("file2")
+	stats.DoneTransferring("file2", false)
+
+	transferred := stats.Transferred()
+	require.Equal(t, 2, len(transferred))
+	assert.Equal(t, "file1", transferred[0].Name)
+	assert.Equal(t, int64(5), transferred[0].Size)
+	assert.Equal(t, int64(5), transferred[0].Bytes)
+	assert.Equal(t, "test/transferred", transferred[0].Group)
+	assert.True(t, transferred[0].Success)
+	assert.False(t, transferred[0].CompletedAt.Before(transferred[0].StartedAt))
+	assert.Equal(t, "file2", transferred[1].Name)
+	assert.Equal(t, int64(-1), transferred[1].Size)
+	assert.False(t, transferred[1].Success)
+	assert.Equal(t, int64(1), stats.GetTransfers())
+
+	// The global stats know the group of the transfer
+	global := Stats.Transferred()
+	require.NotEqual(t, 0, len(global))
+	assert.Equal(t, "test/transferred", global[len(global)-1].Group)
+
+	for i := 0; i < maxCompletedTransfers+10; i++ {
+		stats.Transferring("file")
+		stats.DoneTransferring("file", true)
+	}
+	assert.Equal(t, maxCompletedTransfers, len(stats.Transferred()))
+
+	stats.ResetCounters()
+	assert.Equal(t, 0, len(stats.Transferred()))
+	assert.Equal(t, int64(0), stats.GetTransfers())
+}
+
+func TestStatsTransferredSameRemote(t *testing.T) {
+	ctx := context.Background()
+	stats1 := GetStats(WithStatsGroup(ctx, "test/same1"))
+	stats2 := GetStats(WithStatsGroup(ctx, "test/same2"))
+
+	// Transfer the same remote in two groups at once
+	stats1.Transferring("same")
+	stats2.Transferring("same")
+	acc := stats2.NewAccountSizeName(ioutil.NopCloser(bytes.NewBufferString("hello")), 5, "same")
+	_, err := ioutil.ReadAll(acc)
+	require.NoError(t, err)
+	require.NoError(t, acc.Close())
+	stats1.DoneTransferring("same", false)
+	stats2.DoneTransferring("same", true)
+
+	// The global stats have both transfers with the right group
+	global := Stats.Transferred()
+	require.True(t, len(global) >= 2)
+	first, second := global[len(global)-2], global[len(global)-1]
+	assert.Equal(t, "test/same1", first.Group)
+	assert.False(t, first.Success)
+	assert.Equal(t, int64(-1), first.Size)
+	assert.Equal(t, "test/same2", second.Group)
+	assert.True(t, second.Success)
+	assert.Equal(t, int64(5), second.Size)
+}
+
+func TestRcStatsGroups(t *testing.T) {
+	ctx := context.Background()
+	stats := GetStats(WithStatsGroup(ctx, "test/rc"))
+	stats.Bytes(42)
+	stats.Transferring("file")
+	stats.DoneTransferring("file", true)
+
+	call := rc.Calls.Get("core/stats")
+	require.NotNil(t, call)
+	out, err := call.Fn(ctx, rc.Params{"group": "test/rc"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), out["bytes"])
+
+	_, err = call.Fn(ctx, rc.Params{"group": "potato"})
+	require.Error(t, err)
+	assert.Equal(t, `stats group "potato" not found`, err.Error())
+
+	call = rc.Calls.Get("core/transferred")
+	require.NotNil(t, call)
+	out, err = call.Fn(ctx, rc.Params{"group": "test/rc"})
+	require.NoError(t, err)
+	transferred, ok := out["transferred"].([]Transfer)
+	require.True(t, ok)
+	require.Equal(t, 1, len(transferred))
+	assert.Equal(t, "file", transferred[0].Name)
+
+	call = rc.Calls.Get("core/stats-reset")
+	require.NotNil(t, call)
+	_, err = call.Fn(ctx, rc.Params{"group": "test/rc"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stats.GetBytes())
+	assert.Equal(t, 0, len(stats.Transferred()))
+}
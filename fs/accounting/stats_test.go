@@ -1,11 +1,15 @@
 package accounting
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestETA(t *testing.T) {
@@ -49,3 +53,51 @@ func TestPercentage(t *testing.T) {
 	assert.Equal(t, percent(-100, 100), "-")
 	assert.Equal(t, percent(-100, -100), "-")
 }
+
+func TestStatsLogJSON(t *testing.T) {
+	oldLogPrint, oldUseJSONLog, oldStatsLogLevel := fs.LogPrint, fs.Config.UseJSONLog, fs.Config.StatsLogLevel
+	defer func() {
+		fs.LogPrint, fs.Config.UseJSONLog, fs.Config.StatsLogLevel = oldLogPrint, oldUseJSONLog, oldStatsLogLevel
+	}()
+	var gotText string
+	fs.LogPrint = func(level fs.LogLevel, text string) {
+		gotText = text
+	}
+	fs.Config.UseJSONLog = true
+	fs.Config.StatsLogLevel = fs.LogLevelError
+
+	s := NewStats()
+	s.Bytes(42)
+	s.Error(errors.New("potato"))
+	s.Log()
+
+	var entry struct {
+		Msg   string
+		Stats struct {
+			Bytes     int64
+			Errors    int64
+			LastError string
+		}
+	}
+	require.NoError(t, json.Unmarshal([]byte(gotText), &entry), gotText)
+	assert.Equal(t, "Transfer stats", entry.Msg)
+	assert.Equal(t, int64(42), entry.Stats.Bytes)
+	assert.Equal(t, int64(1), entry.Stats.Errors)
+	assert.Equal(t, "potato", entry.Stats.LastError)
+}
+
+func TestStatsCurrentSpeed(t *testing.T) {
+	s := NewStats()
+	assert.Equal(t, 0.0, s.CurrentSpeed())
+
+	s.inProgress.set("a", &Account{bytes: 1, avg: 100, start: time.Now()})
+	s.inProgress.set("b", &Account{bytes: 1, avg: 50, start: time.Now()})
+	s.Transferring("a")
+	s.Transferring("b")
+	s.Transferring("not in progress")
+	assert.Equal(t, 150.0, s.CurrentSpeed())
+
+	s.DoneTransferring("a", true)
+	assert.Equal(t, 50.0, s.CurrentSpeed())
+	assert.Equal(t, int64(1), s.GetTransfers())
+}
@@ -1,6 +1,7 @@
 package chunkedreader
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"math/rand"
@@ -20,6 +21,7 @@ func TestChunkedReader(t *testing.T) {
 }
 
 func testRead(content []byte, mode mockobject.SeekMode) func(*testing.T) {
+	ctx := context.Background()
 	return func(t *testing.T) {
 		chunkSizes := []int64{-1, 0, 1, 15, 16, 17, 1023, 1024, 1025, 2000}
 		offsets := []int64{0, 1, 2, 3, 4, 5, 7, 8, 9, 15, 16, 17, 31, 32, 33,
@@ -38,7 +40,7 @@ func testRead(content []byte, mode mockobject.SeekMode) func(*testing.T) {
 				}
 
 				t.Run(fmt.Sprintf("Chunksize_%d_%d", cs, csMax), func(t *testing.T) {
-					cr := New(o, cs, csMax)
+					cr := New(ctx, o, cs, csMax)
 
 					for _, offset := range offsets {
 						for _, limit := range limits {
@@ -74,29 +76,30 @@ func testRead(content []byte, mode mockobject.SeekMode) func(*testing.T) {
 }
 
 func TestErrorAfterClose(t *testing.T) {
+	ctx := context.Background()
 	content := makeContent(t, 1024)
 	o := mockobject.New("test.bin").WithContent(content, mockobject.SeekModeNone)
 
 	// Close
-	cr := New(o, 0, 0)
+	cr := New(ctx, o, 0, 0)
 	require.NoError(t, cr.Close())
 	require.Error(t, cr.Close())
 
 	// Read
-	cr = New(o, 0, 0)
+	cr = New(ctx, o, 0, 0)
 	require.NoError(t, cr.Close())
 	var buf [1]byte
 	_, err := cr.Read(buf[:])
 	require.Error(t, err)
 
 	// Seek
-	cr = New(o, 0, 0)
+	cr = New(ctx, o, 0, 0)
 	require.NoError(t, cr.Close())
 	_, err = cr.Seek(1, io.SeekCurrent)
 	require.Error(t, err)
 
 	// RangeSeek
-	cr = New(o, 0, 0)
+	cr = New(ctx, o, 0, 0)
 	require.NoError(t, cr.Close())
 	_, err = cr.RangeSeek(1, io.SeekCurrent, 0)
 	require.Error(t, err)
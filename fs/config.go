@@ -1,6 +1,7 @@
 package fs
 
 import (
+	"context"
 	"net"
 	"strings"
 	"time"
@@ -25,12 +26,13 @@ var (
 		Errorf(nil, "No config handler to set %q = %q in section %q of the config file", key, value, section)
 	}
 
-	// CountError counts an error.  If any errors have been
-	// counted then it will exit with a non zero error code.
+	// CountError counts an error in the stats for ctx.  If any
+	// errors have been counted then it will exit with a non zero
+	// error code.
 	//
 	// This is a function pointer to decouple the config
 	// implementation from the fs
-	CountError = func(err error) {}
+	CountError = func(ctx context.Context, err error) {}
 
 	// ConfigProvider is the config key used for provider options
 	ConfigProvider = "provider"
@@ -64,9 +66,15 @@ type ConfigInfo struct {
 	IgnoreChecksum        bool
 	NoTraverse            bool
 	NoUpdateModTime       bool
+	Metadata              bool // Preserve object metadata on copy
 	DataRateUnit          string
 	BackupDir             string
+	BackupDirFormat       string   // time format to name a snapshot directory in BackupDir for each run
+	BackupDirMaxAge       Duration // remove snapshots in BackupDir older than this
 	Suffix                string
+	SuffixKeepExtension   bool     // put Suffix before the extension
+	CompareDest           []string // directories to compare files with before transferring
+	CopyDest              []string // directories to server side copy files from before transferring
 	UseListR              bool
 	BufferSize            SizeSuffix
 	BwLimit               BwTimetable
@@ -84,7 +92,10 @@ type ConfigInfo struct {
 	MaxTransfer           SizeSuffix
 	MaxBacklog            int
 	StatsOneLine          bool
+	MultiThreadCutoff     SizeSuffix // use multiple streams to download files bigger than this
+	MultiThreadStreams    int        // max number of streams to download with
 	Progress              bool
+	UseJSONLog            bool // log as a JSON object per line
 }
 
 // NewConfig creates a new config with everything set to the default
@@ -102,6 +113,9 @@ func NewConfig() *ConfigInfo {
 	c.ConnectTimeout = 60 * time.Second
 	c.Timeout = 5 * 60 * time.Second
 	c.DeleteMode = DeleteModeDefault
+	c.BackupDirMaxAge = DurationOff
+	c.MultiThreadCutoff = SizeSuffix(250 * 1024 * 1024)
+	c.MultiThreadStreams = 4
 	c.MaxDelete = -1
 	c.LowLevelRetries = 10
 	c.MaxDepth = -1
@@ -782,6 +782,24 @@ func RemoteConfig(name string) {
 	}
 }
 
+// RemoteConfigState runs a step of the non-interactive config for the
+// remote passing in the state and answer in in.  It returns the next
+// question to ask or nil if the config is complete.
+func RemoteConfigState(name string, in fs.ConfigIn) (*fs.ConfigOut, error) {
+	fsType := FileGet(name, "type")
+	if fsType == "" {
+		return nil, errors.Errorf("couldn't find type of fs for %q", name)
+	}
+	ri, err := fs.Find(fsType)
+	if err != nil {
+		return nil, err
+	}
+	if ri.ConfigState == nil {
+		return nil, nil
+	}
+	return ri.ConfigState(name, fs.ConfigMap(ri, name), in)
+}
+
 // matchProvider returns true if provider matches the providerConfig string.
 //
 // The providerConfig string can either be a list of providers to
@@ -911,31 +929,59 @@ func suppressConfirm() func() {
 	}
 }
 
+// UpdateRemoteOpt configures how UpdateRemote and CreateRemote run
+// the config for the backend
+type UpdateRemoteOpt struct {
+	// NonInteractive runs the config a step at a time returning
+	// the next question rather than asking it
+	NonInteractive bool `json:"nonInteractive"`
+	// State to continue the config from - this is the State in
+	// the last fs.ConfigOut and implies NonInteractive
+	State string `json:"state"`
+	// Result is the answer to the question in the last fs.ConfigOut
+	Result string `json:"result"`
+}
+
 // UpdateRemote adds the keyValues passed in to the remote of name.
 // keyValues should be key, value pairs.
-func UpdateRemote(name string, keyValues rc.Params) error {
+//
+// If opt.NonInteractive or opt.State are set then it returns the next
+// question to ask or nil if the config is complete.
+func UpdateRemote(name string, keyValues rc.Params, opt UpdateRemoteOpt) (out *fs.ConfigOut, err error) {
 	defer suppressConfirm()()
 	// Set the config
 	for k, v := range keyValues {
 		getConfigData().SetValue(name, k, fmt.Sprint(v))
 	}
-	RemoteConfig(name)
+	if !opt.NonInteractive && opt.State == "" {
+		RemoteConfig(name)
+		SaveConfig()
+		return nil, nil
+	}
+	out, err = RemoteConfigState(name, fs.ConfigIn{
+		State:  opt.State,
+		Result: opt.Result,
+	})
 	SaveConfig()
-	return nil
+	return out, err
 }
 
 // CreateRemote creates a new remote with name, provider and a list of
-// parameters which are key, value pairs.  If update is set then it
-// adds the new keys rather than replacing all of them.
-func CreateRemote(name string, provider string, keyValues rc.Params) error {
-	// Delete the old config if it exists
-	getConfigData().DeleteSection(name)
-	// Set the type
-	getConfigData().SetValue(name, "type", provider)
-	// Show this is automatically configured
-	getConfigData().SetValue(name, ConfigAutomatic, "yes")
+// parameters which are key, value pairs.
+//
+// If opt.State is set then this continues the non-interactive config
+// of the remote rather than making it again.
+func CreateRemote(name string, provider string, keyValues rc.Params, opt UpdateRemoteOpt) (out *fs.ConfigOut, err error) {
+	if opt.State == "" {
+		// Delete the old config if it exists
+		getConfigData().DeleteSection(name)
+		// Set the type
+		getConfigData().SetValue(name, "type", provider)
+		// Show this is automatically configured
+		getConfigData().SetValue(name, ConfigAutomatic, "yes")
+	}
 	// Set the remaining values
-	return UpdateRemote(name, keyValues)
+	return UpdateRemote(name, keyValues, opt)
 }
 
 // PasswordRemote adds the keyValues passed in to the remote of name.
@@ -945,7 +991,8 @@ func PasswordRemote(name string, keyValues rc.Params) error {
 	for k, v := range keyValues {
 		keyValues[k] = obscure.MustObscure(fmt.Sprint(v))
 	}
-	return UpdateRemote(name, keyValues)
+	_, err := UpdateRemote(name, keyValues, UpdateRemoteOpt{})
+	return err
 }
 
 // JSONListProviders prints all the providers and options in JSON format
@@ -66,8 +66,14 @@ func AddFlags(flagSet *pflag.FlagSet) {
 	flags.BoolVarP(flagSet, &fs.Config.IgnoreChecksum, "ignore-checksum", "", fs.Config.IgnoreChecksum, "Skip post copy check of checksums.")
 	flags.BoolVarP(flagSet, &fs.Config.NoTraverse, "no-traverse", "", fs.Config.NoTraverse, "Don't traverse destination file system on copy.")
 	flags.BoolVarP(flagSet, &fs.Config.NoUpdateModTime, "no-update-modtime", "", fs.Config.NoUpdateModTime, "Don't update destination mod-time if files identical.")
+	flags.BoolVarP(flagSet, &fs.Config.Metadata, "metadata", "M", fs.Config.Metadata, "If set, preserve metadata when copying objects.")
 	flags.StringVarP(flagSet, &fs.Config.BackupDir, "backup-dir", "", fs.Config.BackupDir, "Make backups into hierarchy based in DIR.")
+	flags.StringVarP(flagSet, &fs.Config.BackupDirFormat, "backup-dir-format", "", fs.Config.BackupDirFormat, "Put backups in a subdirectory of --backup-dir named with this time format for each run, eg 2006-01-02-150405.")
+	flags.FVarP(flagSet, &fs.Config.BackupDirMaxAge, "backup-dir-max-age", "", "Remove --backup-dir-format snapshots older than this in s or suffix ms|s|m|h|d|w|M|y.")
 	flags.StringVarP(flagSet, &fs.Config.Suffix, "suffix", "", fs.Config.Suffix, "Suffix for use with --backup-dir.")
+	flags.BoolVarP(flagSet, &fs.Config.SuffixKeepExtension, "suffix-keep-extension", "", fs.Config.SuffixKeepExtension, "Preserve the extension when using --suffix.")
+	flags.StringArrayVarP(flagSet, &fs.Config.CompareDest, "compare-dest", "", nil, "Include additional server-side path DIR during comparison. Can be repeated.")
+	flags.StringArrayVarP(flagSet, &fs.Config.CopyDest, "copy-dest", "", nil, "Implies --compare-dest but also server side copies files from DIR into destination. Can be repeated.")
 	flags.BoolVarP(flagSet, &fs.Config.UseListR, "fast-list", "", fs.Config.UseListR, "Use recursive list if available. Uses more memory but fewer transactions.")
 	flags.Float64VarP(flagSet, &fs.Config.TPSLimit, "tpslimit", "", fs.Config.TPSLimit, "Limit HTTP transactions per second to this.")
 	flags.IntVarP(flagSet, &fs.Config.TPSLimitBurst, "tpslimit-burst", "", fs.Config.TPSLimitBurst, "Max burst of transactions for --tpslimit.")
@@ -87,6 +93,9 @@ func AddFlags(flagSet *pflag.FlagSet) {
 	flags.IntVarP(flagSet, &fs.Config.MaxBacklog, "max-backlog", "", fs.Config.MaxBacklog, "Maximum number of objects in sync or check backlog.")
 	flags.BoolVarP(flagSet, &fs.Config.StatsOneLine, "stats-one-line", "", fs.Config.StatsOneLine, "Make the stats fit on one line.")
 	flags.BoolVarP(flagSet, &fs.Config.Progress, "progress", "P", fs.Config.Progress, "Show progress during transfer.")
+	flags.FVarP(flagSet, &fs.Config.MultiThreadCutoff, "multi-thread-cutoff", "", "Use multi-thread downloads for files above this size.")
+	flags.IntVarP(flagSet, &fs.Config.MultiThreadStreams, "multi-thread-streams", "", fs.Config.MultiThreadStreams, "Max number of streams to use for multi-thread downloads.")
+	flags.BoolVarP(flagSet, &fs.Config.UseJSONLog, "use-json-log", "", fs.Config.UseJSONLog, "Use json log format.")
 }
 
 // SetFlags converts any flags into config which weren't straight foward
@@ -143,6 +152,22 @@ func SetFlags() {
 		log.Fatalf(`Can only use --suffix with --backup-dir.`)
 	}
 
+	if fs.Config.BackupDirFormat != "" && fs.Config.BackupDir == "" {
+		log.Fatalf(`Can only use --backup-dir-format with --backup-dir.`)
+	}
+
+	if strings.Contains(fs.Config.BackupDirFormat, "/") {
+		log.Fatalf(`--backup-dir-format mustn't contain "/".`)
+	}
+
+	if fs.Config.BackupDirMaxAge.IsSet() && fs.Config.BackupDirFormat == "" {
+		log.Fatalf(`Can only use --backup-dir-max-age with --backup-dir-format.`)
+	}
+
+	if len(fs.Config.CompareDest) > 0 && len(fs.Config.CopyDest) > 0 {
+		log.Fatalf(`Can't use --compare-dest with --copy-dest.`)
+	}
+
 	if bindAddr != "" {
 		addrs, err := net.LookupIP(bindAddr)
 		if err != nil {
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"github.com/ncw/rclone/fs"
 	"github.com/ncw/rclone/fs/rc"
 )
@@ -23,7 +24,7 @@ See the [config dump command](/commands/rclone_config_dump/) command for more in
 }
 
 // Return the config file dump
-func rcDump(in rc.Params) (out rc.Params, err error) {
+func rcDump(ctx context.Context, in rc.Params) (out rc.Params, err error) {
 	return DumpRcBlob(), nil
 }
 
@@ -43,7 +44,7 @@ See the [config dump command](/commands/rclone_config_dump/) command for more in
 }
 
 // Return the config file get
-func rcGet(in rc.Params) (out rc.Params, err error) {
+func rcGet(ctx context.Context, in rc.Params) (out rc.Params, err error) {
 	name, err := in.GetString("name")
 	if err != nil {
 		return nil, err
@@ -67,7 +68,7 @@ See the [listremotes command](/commands/rclone_listremotes/) command for more in
 }
 
 // Return the a list of remotes in the config file
-func rcListRemotes(in rc.Params) (out rc.Params, err error) {
+func rcListRemotes(ctx context.Context, in rc.Params) (out rc.Params, err error) {
 	var remotes = []string{}
 	for _, remote := range getConfigData().GetSectionList() {
 		remotes = append(remotes, remote)
@@ -94,7 +95,7 @@ See the [config providers command](/commands/rclone_config_providers/) command f
 }
 
 // Return the config file providers
-func rcProviders(in rc.Params) (out rc.Params, err error) {
+func rcProviders(ctx context.Context, in rc.Params) (out rc.Params, err error) {
 	out = rc.Params{
 		"providers": fs.Registry,
 	}
@@ -108,10 +109,31 @@ func init() {
 		if name == "create" {
 			extraHelp = "- type - type of the new remote\n"
 		}
+		if name != "password" {
+			extraHelp += `- parameters - a map of { "key": "value" } pairs to set - optional when continuing with state
+- nonInteractive - set to true to run the config a step at a time
+- state - state to continue the config from - implies nonInteractive
+- result - the answer to the last question asked
+
+If nonInteractive or state are set then instead of asking the user
+questions the config returns the next question as
+` + "```" + `
+{
+	"state": state to pass back in the next call,
+	"option": the question as a config option like those in config/providers,
+	"authURL": if set the URL to visit to authorize rclone,
+	"error": if set why the last answer wasn't accepted
+}
+` + "```" + `
+Answer the question by calling config/` + name + ` again with the same
+name and the state and result parameters set.  The state is empty
+when the config is complete.
+`
+		}
 		rc.Add(rc.Call{
 			Path:         "config/" + name,
 			AuthRequired: true,
-			Fn: func(in rc.Params) (rc.Params, error) {
+			Fn: func(ctx context.Context, in rc.Params) (rc.Params, error) {
 				return rcConfig(in, name)
 			},
 			Title: name + " the config for a remote.",
@@ -134,22 +156,56 @@ func rcConfig(in rc.Params, what string) (out rc.Params, err error) {
 	}
 	parameters := rc.Params{}
 	err = in.GetStruct("parameters", &parameters)
-	if err != nil {
+	if rc.NotErrParamNotFound(err) {
+		return nil, err
+	}
+	if what == "password" {
+		return nil, PasswordRemote(name, parameters)
+	}
+	var opt UpdateRemoteOpt
+	opt.NonInteractive, err = in.GetBool("nonInteractive")
+	if rc.NotErrParamNotFound(err) {
+		return nil, err
+	}
+	opt.State, err = in.GetString("state")
+	if rc.NotErrParamNotFound(err) {
 		return nil, err
 	}
+	opt.Result, err = in.GetString("result")
+	if rc.NotErrParamNotFound(err) {
+		return nil, err
+	}
+	var configOut *fs.ConfigOut
 	switch what {
 	case "create":
 		remoteType, err := in.GetString("type")
 		if err != nil {
 			return nil, err
 		}
-		return nil, CreateRemote(name, remoteType, parameters)
+		configOut, err = CreateRemote(name, remoteType, parameters, opt)
+		if err != nil {
+			return nil, err
+		}
 	case "update":
-		return nil, UpdateRemote(name, parameters)
-	case "password":
-		return nil, PasswordRemote(name, parameters)
+		configOut, err = UpdateRemote(name, parameters, opt)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		panic("unknown rcConfig type")
 	}
-	panic("unknown rcConfig type")
+	if !opt.NonInteractive && opt.State == "" {
+		return nil, nil
+	}
+	if configOut == nil {
+		configOut = &fs.ConfigOut{}
+	}
+	out = make(rc.Params)
+	err = rc.Reshape(&out, configOut)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
 func init() {
@@ -168,7 +224,7 @@ See the [config delete command](/commands/rclone_config_delete/) command for mor
 }
 
 // Return the config file delete
-func rcDelete(in rc.Params) (out rc.Params, err error) {
+func rcDelete(ctx context.Context, in rc.Params) (out rc.Params, err error) {
 	name, err := in.GetString("name")
 	if err != nil {
 		return nil, err
@@ -1,10 +1,12 @@
 package config
 
 import (
+	"context"
 	"testing"
 
 	_ "github.com/ncw/rclone/backend/local"
 	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/config/configmap"
 	"github.com/ncw/rclone/fs/config/obscure"
 	"github.com/ncw/rclone/fs/rc"
 	"github.com/stretchr/testify/assert"
@@ -14,6 +16,7 @@ import (
 const testName = "configTestNameForRc"
 
 func TestRc(t *testing.T) {
+	ctx := context.Background()
 	// Create the test remote
 	call := rc.Calls.Get("config/create")
 	assert.NotNil(t, call)
@@ -24,7 +27,7 @@ func TestRc(t *testing.T) {
 			"test_key": "sausage",
 		},
 	}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	require.Nil(t, out)
 	assert.Equal(t, "local", FileGet(testName, "type"))
@@ -37,7 +40,7 @@ func TestRc(t *testing.T) {
 		call := rc.Calls.Get("config/dump")
 		assert.NotNil(t, call)
 		in := rc.Params{}
-		out, err := call.Fn(in)
+		out, err := call.Fn(ctx, in)
 		require.NoError(t, err)
 		require.NotNil(t, out)
 
@@ -54,7 +57,7 @@ func TestRc(t *testing.T) {
 		in := rc.Params{
 			"name": testName,
 		}
-		out, err := call.Fn(in)
+		out, err := call.Fn(ctx, in)
 		require.NoError(t, err)
 		require.NotNil(t, out)
 
@@ -66,7 +69,7 @@ func TestRc(t *testing.T) {
 		call := rc.Calls.Get("config/listremotes")
 		assert.NotNil(t, call)
 		in := rc.Params{}
-		out, err := call.Fn(in)
+		out, err := call.Fn(ctx, in)
 		require.NoError(t, err)
 		require.NotNil(t, out)
 
@@ -87,7 +90,7 @@ func TestRc(t *testing.T) {
 				"test_key2": "cabbage",
 			},
 		}
-		out, err := call.Fn(in)
+		out, err := call.Fn(ctx, in)
 		require.NoError(t, err)
 		assert.Nil(t, out)
 
@@ -106,7 +109,7 @@ func TestRc(t *testing.T) {
 				"test_key2": "cabbage",
 			},
 		}
-		out, err := call.Fn(in)
+		out, err := call.Fn(ctx, in)
 		require.NoError(t, err)
 		assert.Nil(t, out)
 
@@ -121,7 +124,7 @@ func TestRc(t *testing.T) {
 	in = rc.Params{
 		"name": testName,
 	}
-	out, err = call.Fn(in)
+	out, err = call.Fn(ctx, in)
 	require.NoError(t, err)
 	assert.Nil(t, out)
 	assert.Equal(t, "", FileGet(testName, "type"))
@@ -129,10 +132,11 @@ func TestRc(t *testing.T) {
 }
 
 func TestRcProviders(t *testing.T) {
+	ctx := context.Background()
 	call := rc.Calls.Get("config/providers")
 	assert.NotNil(t, call)
 	in := rc.Params{}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	require.NotNil(t, out)
 	var registry []*fs.RegInfo
@@ -147,3 +151,67 @@ func TestRcProviders(t *testing.T) {
 	}
 	assert.True(t, foundLocal, "didn't find local provider")
 }
+
+func TestRcNonInteractive(t *testing.T) {
+	ctx := context.Background()
+	// Fake a remote which asks a single question
+	fs.Register(&fs.RegInfo{
+		Name: "config_test_state",
+		ConfigState: func(name string, m configmap.Mapper, in fs.ConfigIn) (*fs.ConfigOut, error) {
+			if in.State == "answer" {
+				m.Set("answer", in.Result)
+				return nil, nil
+			}
+			return &fs.ConfigOut{
+				State:  "answer",
+				Option: &fs.Option{Name: "answer", Help: "What is the answer?"},
+			}, nil
+		},
+	})
+	defer DeleteRemote(testName)
+
+	call := rc.Calls.Get("config/create")
+	require.NotNil(t, call)
+	out, err := call.Fn(ctx, rc.Params{
+		"name":           testName,
+		"type":           "config_test_state",
+		"nonInteractive": true,
+		"parameters": rc.Params{
+			"test_key": "sausage",
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	assert.Equal(t, "answer", out["state"])
+	var option fs.Option
+	require.NoError(t, out.GetStruct("option", &option))
+	assert.Equal(t, "What is the answer?", option.Help)
+	assert.Equal(t, "sausage", FileGet(testName, "test_key"))
+
+	call = rc.Calls.Get("config/update")
+	require.NotNil(t, call)
+	out, err = call.Fn(ctx, rc.Params{
+		"name":   testName,
+		"state":  "answer",
+		"result": "42",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	assert.Equal(t, "", out["state"])
+	assert.Nil(t, out["option"])
+	assert.Equal(t, "42", FileGet(testName, "answer"))
+	assert.Equal(t, "sausage", FileGet(testName, "test_key"))
+
+	// Continuing with config/create doesn't remake the remote
+	call = rc.Calls.Get("config/create")
+	out, err = call.Fn(ctx, rc.Params{
+		"name":   testName,
+		"type":   "config_test_state",
+		"state":  "answer",
+		"result": "43",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "", out["state"])
+	assert.Equal(t, "43", FileGet(testName, "answer"))
+	assert.Equal(t, "sausage", FileGet(testName, "test_key"))
+}
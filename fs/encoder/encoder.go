@@ -0,0 +1,484 @@
+/*
+Package encoder translates file names to and from the restricted
+character sets used by some storage systems.
+
+Characters a backend can't store are mapped to a unicode equivalent,
+mostly the FULLWIDTH variant for printable ASCII and the SYMBOL FOR
+variant for control characters.  For example
+
+	\ : * ? " < > |  =>  ＼ ： ＊ ？ ＂ ＜ ＞ ｜
+	NUL SOH ... DEL   =>  ␀ ␁ ... ␡
+	leading space     =>  ␠
+
+If a name already contains a replacement character which would be
+decoded, it is escaped with ‛ (SINGLE HIGH-REVERSED-9 QUOTATION MARK)
+so that every name survives a round trip through Encode and Decode.
+
+Invalid UTF-8 bytes may also be encoded.  They are written as ‛
+followed by the byte as two upper case hex digits.
+*/
+package encoder
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	quoteRune      = '‛'       // SINGLE HIGH-REVERSED-9 QUOTATION MARK - escapes literal replacements
+	symbolOffset   = '␀'       // SYMBOL FOR NULL - start of the control character replacements
+	symbolDel      = '␡'       // SYMBOL FOR DELETE
+	symbolSpace    = '␠'       // SYMBOL FOR SPACE
+	fullOffset     = '！' - '!' // offset from printable ASCII to FULLWIDTH
+	fullFirst      = '！'       // FULLWIDTH EXCLAMATION MARK - first FULLWIDTH replacement
+	fullLast       = '～'       // FULLWIDTH TILDE - last FULLWIDTH replacement
+	upperHexDigits = "0123456789ABCDEF"
+	encodedDot     = "．" // FULLWIDTH FULL STOP
+	encodedDotDot  = encodedDot + encodedDot
+	quotedDot      = string(quoteRune) + encodedDot
+	quotedDotDot   = quotedDot + quotedDot
+)
+
+// OptionHelp is the help for the "encoding" option of a backend
+const OptionHelp = `This sets the encoding for the backend.
+
+This is a comma separated list of the characters to encode, or "None"
+to disable encoding.  See the [encoding section in the
+overview](/overview/#encoding) for more info.`
+
+// MultiEncoder is a configurable set of character replacements.
+//
+// Each bit enables the encoding of one class of characters.
+type MultiEncoder uint
+
+// Possible flags for the MultiEncoder
+const (
+	EncodeZero          MultiEncoder = 1 << iota // NUL(0x00)
+	EncodeLtGt                                   // <>
+	EncodeDoubleQuote                            // "
+	EncodeSingleQuote                            // '
+	EncodeBackQuote                              // `
+	EncodeDollar                                 // $
+	EncodeColon                                  // :
+	EncodeQuestion                               // ?
+	EncodeAsterisk                               // *
+	EncodePipe                                   // |
+	EncodeHash                                   // #
+	EncodePercent                                // %
+	EncodeBackSlash                              // \
+	EncodeCrLf                                   // CR(0x0D), LF(0x0A)
+	EncodeDel                                    // DEL(0x7F)
+	EncodeCtl                                    // CTRL(0x01-0x1F)
+	EncodeLeftSpace                              // Leading SPACE
+	EncodeLeftPeriod                             // Leading .
+	EncodeLeftTilde                              // Leading ~
+	EncodeLeftCrLfHtVt                           // Leading CR LF HT VT
+	EncodeRightSpace                             // Trailing SPACE
+	EncodeRightPeriod                            // Trailing .
+	EncodeRightCrLfHtVt                          // Trailing CR LF HT VT
+	EncodeInvalidUtf8                            // Invalid UTF-8 bytes
+	EncodeDot                                    // . and .. names
+
+	// EncodeRaw disables all encoding
+	EncodeRaw MultiEncoder = 0
+
+	// EncodeWin is the set of characters Windows can't use in names
+	EncodeWin = EncodeColon | EncodeQuestion | EncodeDoubleQuote | EncodeAsterisk | EncodeLtGt | EncodePipe
+
+	// Base is the minimum encoding that all backends need
+	Base = EncodeZero | EncodeDot
+
+	// Display is the encoding used for names shown to the user
+	Display = Base | EncodeCtl | EncodeDel
+)
+
+// names of the flags in the order they are displayed
+var maskNames = []struct {
+	mask MultiEncoder
+	name string
+}{
+	{EncodeZero, "Zero"},
+	{EncodeLtGt, "LtGt"},
+	{EncodeDoubleQuote, "DoubleQuote"},
+	{EncodeSingleQuote, "SingleQuote"},
+	{EncodeBackQuote, "BackQuote"},
+	{EncodeDollar, "Dollar"},
+	{EncodeColon, "Colon"},
+	{EncodeQuestion, "Question"},
+	{EncodeAsterisk, "Asterisk"},
+	{EncodePipe, "Pipe"},
+	{EncodeHash, "Hash"},
+	{EncodePercent, "Percent"},
+	{EncodeBackSlash, "BackSlash"},
+	{EncodeCrLf, "CrLf"},
+	{EncodeDel, "Del"},
+	{EncodeCtl, "Ctl"},
+	{EncodeLeftSpace, "LeftSpace"},
+	{EncodeLeftPeriod, "LeftPeriod"},
+	{EncodeLeftTilde, "LeftTilde"},
+	{EncodeLeftCrLfHtVt, "LeftCrLfHtVt"},
+	{EncodeRightSpace, "RightSpace"},
+	{EncodeRightPeriod, "RightPeriod"},
+	{EncodeRightCrLfHtVt, "RightCrLfHtVt"},
+	{EncodeInvalidUtf8, "InvalidUtf8"},
+	{EncodeDot, "Dot"},
+}
+
+// Has returns true if flag is contained in mask
+func (mask MultiEncoder) Has(flag MultiEncoder) bool {
+	return mask&flag != 0
+}
+
+// String turns the mask into a comma separated list of flag names
+func (mask MultiEncoder) String() string {
+	if mask == EncodeRaw {
+		return "None"
+	}
+	var out []string
+	left := mask
+	for _, item := range maskNames {
+		if mask.Has(item.mask) {
+			out = append(out, item.name)
+			left &^= item.mask
+		}
+	}
+	if left != 0 {
+		out = append(out, fmt.Sprintf("0x%X", uint(left)))
+	}
+	return strings.Join(out, ",")
+}
+
+// Set the mask from a comma separated list of flag names
+func (mask *MultiEncoder) Set(in string) error {
+	var out MultiEncoder
+	for _, part := range strings.Split(in, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" || strings.EqualFold(part, "None") {
+			continue
+		}
+		found := false
+		for _, item := range maskNames {
+			if strings.EqualFold(part, item.name) {
+				out |= item.mask
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.Errorf("unknown encoding %q", part)
+		}
+	}
+	*mask = out
+	return nil
+}
+
+// Type of the value
+func (mask *MultiEncoder) Type() string {
+	return "Encoding"
+}
+
+// Scan implements the fmt.Scanner interface
+func (mask *MultiEncoder) Scan(s fmt.ScanState, ch rune) error {
+	token, err := s.Token(true, nil)
+	if err != nil {
+		return err
+	}
+	return mask.Set(string(token))
+}
+
+// isCrLfHtVt returns true for the white space control characters
+// which some backends strip from the ends of names
+func isCrLfHtVt(r rune) bool {
+	return r == '\r' || r == '\n' || r == '\t' || r == '\v'
+}
+
+// encodeChar returns true if r should be replaced wherever it is
+func (mask MultiEncoder) encodeChar(r rune) bool {
+	switch {
+	case r == 0:
+		return mask.Has(EncodeZero)
+	case r == '\r' || r == '\n':
+		return mask.Has(EncodeCrLf | EncodeCtl)
+	case r < 0x20:
+		return mask.Has(EncodeCtl)
+	case r == 0x7F:
+		return mask.Has(EncodeDel)
+	}
+	switch r {
+	case '<', '>':
+		return mask.Has(EncodeLtGt)
+	case '"':
+		return mask.Has(EncodeDoubleQuote)
+	case '\'':
+		return mask.Has(EncodeSingleQuote)
+	case '`':
+		return mask.Has(EncodeBackQuote)
+	case '$':
+		return mask.Has(EncodeDollar)
+	case ':':
+		return mask.Has(EncodeColon)
+	case '?':
+		return mask.Has(EncodeQuestion)
+	case '*':
+		return mask.Has(EncodeAsterisk)
+	case '|':
+		return mask.Has(EncodePipe)
+	case '#':
+		return mask.Has(EncodeHash)
+	case '%':
+		return mask.Has(EncodePercent)
+	case '\\':
+		return mask.Has(EncodeBackSlash)
+	}
+	return false
+}
+
+// encodeLeft returns true if r should be replaced at the start of a name
+func (mask MultiEncoder) encodeLeft(r rune) bool {
+	switch {
+	case r == ' ':
+		return mask.Has(EncodeLeftSpace)
+	case r == '.':
+		return mask.Has(EncodeLeftPeriod)
+	case r == '~':
+		return mask.Has(EncodeLeftTilde)
+	case isCrLfHtVt(r):
+		return mask.Has(EncodeLeftCrLfHtVt)
+	}
+	return false
+}
+
+// encodeRight returns true if r should be replaced at the end of a name
+func (mask MultiEncoder) encodeRight(r rune) bool {
+	switch {
+	case r == ' ':
+		return mask.Has(EncodeRightSpace)
+	case r == '.':
+		return mask.Has(EncodeRightPeriod)
+	case isCrLfHtVt(r):
+		return mask.Has(EncodeRightCrLfHtVt)
+	}
+	return false
+}
+
+// encodeAt returns true if r should be replaced at the position given
+func (mask MultiEncoder) encodeAt(r rune, first, last bool) bool {
+	return mask.encodeChar(r) || (first && mask.encodeLeft(r)) || (last && mask.encodeRight(r))
+}
+
+// toReplacement returns the replacement for r
+//
+// It should only be called for characters which have one.
+func toReplacement(r rune) rune {
+	switch {
+	case r < 0x20:
+		return r + symbolOffset
+	case r == 0x7F:
+		return symbolDel
+	case r == ' ':
+		return symbolSpace
+	}
+	return r + fullOffset
+}
+
+// fromReplacement returns the character replaced by r and whether r
+// is a replacement character at all
+func fromReplacement(r rune) (rune, bool) {
+	switch {
+	case r >= symbolOffset && r < symbolOffset+0x20:
+		return r - symbolOffset, true
+	case r == symbolDel:
+		return 0x7F, true
+	case r == symbolSpace:
+		return ' ', true
+	case r >= fullFirst && r <= fullLast:
+		return r - fullOffset, true
+	}
+	return 0, false
+}
+
+// decodeAt returns the original character if r is a replacement that
+// this mask would have made at the position given
+func (mask MultiEncoder) decodeAt(r rune, first, last bool) (rune, bool) {
+	orig, ok := fromReplacement(r)
+	if !ok || !mask.encodeAt(orig, first, last) {
+		return 0, false
+	}
+	return orig, true
+}
+
+// isHexPair returns true if s starts with two upper case hex digits
+func isHexPair(s string) bool {
+	return len(s) >= 2 && strings.IndexByte(upperHexDigits, s[0]) >= 0 && strings.IndexByte(upperHexDigits, s[1]) >= 0
+}
+
+// encodeUnit encodes the rune (or invalid byte) at in[i:] returning
+// the encoded string and the number of bytes of in consumed
+func (mask MultiEncoder) encodeUnit(in string, i int) (string, int) {
+	r, size := utf8.DecodeRuneInString(in[i:])
+	first, last := i == 0, i+size == len(in)
+	switch {
+	case r == utf8.RuneError && size == 1:
+		if mask.Has(EncodeInvalidUtf8) {
+			return string(quoteRune) + string(upperHexDigits[in[i]>>4]) + string(upperHexDigits[in[i]&0x0F]), size
+		}
+		return in[i : i+size], size
+	case mask.encodeAt(r, first, last):
+		return string(toReplacement(r)), size
+	}
+	if _, ok := mask.decodeAt(r, first, last); ok {
+		// escape a literal replacement so it isn't decoded
+		return string(quoteRune) + string(r), size
+	}
+	return in[i : i+size], size
+}
+
+// needsQuote returns true if a literal quoteRune followed by in[i:]
+// would be misread by Decode
+func (mask MultiEncoder) needsQuote(in string, i int) bool {
+	if i >= len(in) {
+		return false
+	}
+	if mask.Has(EncodeInvalidUtf8) && isHexPair(in[i:]) {
+		return true
+	}
+	next, _ := mask.encodeUnit(in, i)
+	r, _ := utf8.DecodeRuneInString(next)
+	if r == quoteRune {
+		return true
+	}
+	_, isReplacement := fromReplacement(r)
+	return isReplacement
+}
+
+// Encode takes a name in the standard encoding and returns it with
+// the characters in mask replaced
+func (mask MultiEncoder) Encode(in string) string {
+	if mask == EncodeRaw || in == "" {
+		return in
+	}
+	if mask.Has(EncodeDot) {
+		switch in {
+		case ".":
+			return encodedDot
+		case "..":
+			return encodedDotDot
+		case encodedDot:
+			return quotedDot
+		case encodedDotDot:
+			return quotedDotDot
+		}
+	}
+	var out bytes.Buffer
+	out.Grow(len(in))
+	for i := 0; i < len(in); {
+		unit, size := mask.encodeUnit(in, i)
+		if unit == string(quoteRune) && mask.needsQuote(in, i+size) {
+			out.WriteRune(quoteRune)
+		}
+		out.WriteString(unit)
+		i += size
+	}
+	if mask.Has(EncodeDot) && out.String() == encodedDotDot {
+		// A period encoded by EncodeLeftPeriod or
+		// EncodeRightPeriod next to a literal replacement would
+		// decode as "..", so quote the literal one
+		out.Reset()
+		for _, r := range in {
+			if r == '.' {
+				out.WriteString(encodedDot)
+			} else {
+				out.WriteString(quotedDot)
+			}
+		}
+	}
+	return out.String()
+}
+
+// Decode takes a name encoded with mask and returns it in the
+// standard encoding
+func (mask MultiEncoder) Decode(in string) string {
+	if mask == EncodeRaw || in == "" {
+		return in
+	}
+	if mask.Has(EncodeDot) {
+		switch in {
+		case encodedDot:
+			return "."
+		case encodedDotDot:
+			return ".."
+		case quotedDot:
+			return encodedDot
+		case quotedDotDot:
+			return encodedDotDot
+		}
+	}
+	var out bytes.Buffer
+	out.Grow(len(in))
+	for i := 0; i < len(in); {
+		r, size := utf8.DecodeRuneInString(in[i:])
+		if r == quoteRune {
+			rest := in[i+size:]
+			if mask.Has(EncodeInvalidUtf8) && isHexPair(rest) {
+				hi := strings.IndexByte(upperHexDigits, rest[0])
+				lo := strings.IndexByte(upperHexDigits, rest[1])
+				out.WriteByte(byte(hi<<4 | lo))
+				i += size + 2
+				continue
+			}
+			next, nextSize := utf8.DecodeRuneInString(rest)
+			if _, isReplacement := fromReplacement(next); next == quoteRune || isReplacement {
+				out.WriteRune(next)
+				i += size + nextSize
+				continue
+			}
+		} else if orig, ok := mask.decodeAt(r, out.Len() == 0, i+size == len(in)); ok {
+			out.WriteRune(orig)
+			i += size
+			continue
+		}
+		out.WriteString(in[i : i+size])
+		i += size
+	}
+	return out.String()
+}
+
+// FromStandardName takes a name in the standard encoding and
+// converts it to this encoding.
+func (mask MultiEncoder) FromStandardName(s string) string {
+	return mask.Encode(s)
+}
+
+// ToStandardName takes a name in this encoding and converts it to
+// the standard encoding.
+func (mask MultiEncoder) ToStandardName(s string) string {
+	return mask.Decode(s)
+}
+
+// FromStandardPath takes a / separated path in the standard encoding
+// and converts each element to this encoding.
+func (mask MultiEncoder) FromStandardPath(s string) string {
+	return mask.mapPath(s, mask.Encode)
+}
+
+// ToStandardPath takes a / separated path in this encoding and
+// converts each element to the standard encoding.
+func (mask MultiEncoder) ToStandardPath(s string) string {
+	return mask.mapPath(s, mask.Decode)
+}
+
+// mapPath applies fn to every element of the / separated path s
+func (mask MultiEncoder) mapPath(s string, fn func(string) string) string {
+	if mask == EncodeRaw || s == "" {
+		return s
+	}
+	parts := strings.Split(s, "/")
+	for i := range parts {
+		parts[i] = fn(parts[i])
+	}
+	return strings.Join(parts, "/")
+}
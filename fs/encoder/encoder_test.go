@@ -0,0 +1,130 @@
+package encoder
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	win := EncodeWin | EncodeBackSlash | EncodeCtl | EncodeDel | EncodeRightSpace | EncodeRightPeriod
+	for _, test := range []struct {
+		mask MultiEncoder
+		in   string
+		out  string
+	}{
+		{EncodeRaw, `\*<>?:|"`, `\*<>?:|"`},
+		{win, "", ""},
+		{win, "abc 123", "abc 123"},
+		{win, `\*<>?:|#%".~`, `＼＊＜＞？：｜#%＂.~`},
+		{win, "trailing space ", "trailing space␠"},
+		{win, " leading space", " leading space"},
+		{win, "trailing period.", "trailing period．"},
+		{win, "a\x01b\x7f", "a␁b␡"},
+		{win, "already＊encoded", "already‛＊encoded"},
+		{win, "not encoded．middle", "not encoded．middle"},
+		{win, "trailing replacement␠", "trailing replacement‛␠"},
+		{win, "quote‛", "quote‛"},
+		{win, "quote‛＊", "quote‛‛‛＊"},
+		{win, "quote‛*", "quote‛‛＊"},
+		{EncodeLeftSpace | EncodeLeftTilde, " ~", "␠~"},
+		{EncodeLeftSpace | EncodeLeftTilde, "~ ", "～ "},
+		{EncodeLeftCrLfHtVt | EncodeRightCrLfHtVt, "\tab\n", "␉ab␊"},
+		{EncodeCrLf, "a\r\nb\t", "a␍␊b\t"},
+		{EncodeHash | EncodePercent, "50% #1", "50％ ＃1"},
+		{EncodeDot, ".", "．"},
+		{EncodeDot, "..", "．．"},
+		{EncodeDot, "...", "..."},
+		{EncodeDot, "．", "‛．"},
+		{EncodeDot, "．．", "‛．‛．"},
+		{EncodeDot | EncodeRightPeriod, "．.", "‛．．"},
+		{EncodeDot | EncodeRightPeriod, ".．", ".‛．"},
+		{EncodeDot | EncodeRightPeriod, "．．", "‛．‛．"},
+		{EncodeDot | EncodeLeftPeriod, ".．", "．‛．"},
+		{EncodeDot | EncodeLeftPeriod, "．.", "‛．."},
+		{EncodeInvalidUtf8, "bad\xffbyte", "bad‛FFbyte"},
+		{EncodeInvalidUtf8, "quote‛AB", "quote‛‛AB"},
+		{EncodeInvalidUtf8, "quote‛\xfe", "quote‛‛‛FE"},
+		{EncodeZero, "nul\x00", "nul␀"},
+	} {
+		got := test.mask.Encode(test.in)
+		assert.Equal(t, test.out, got, "Encode(%q) with %v", test.in, test.mask)
+		got2 := test.mask.Decode(got)
+		assert.Equal(t, test.in, got2, "Decode(%q) with %v", got, test.mask)
+	}
+}
+
+func TestDecodeNative(t *testing.T) {
+	// names created directly on the remote should be left alone
+	// if they don't look like they were encoded
+	mask := EncodeWin | EncodeRightSpace
+	for _, in := range []string{
+		"plain",
+		"middle␠space",
+		"quote‛here",
+	} {
+		got := mask.Decode(in)
+		assert.Equal(t, in, got)
+		assert.Equal(t, in, mask.Encode(got))
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	alphabet := []string{
+		"a", "b", " ", ".", "~", "\x00", "\x01", "\r", "\n", "\t", "\v", "\x7f",
+		"\\", "*", "<", ">", "?", ":", "|", "\"", "'", "`", "$", "#", "%",
+		"\xff", "\xc3", "A", "F", "0",
+		"‛", "＊", "：", "．", "～", "␠", "␀", "␡", "␍", "é",
+	}
+	var all MultiEncoder
+	for _, item := range maskNames {
+		all |= item.mask
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		mask := MultiEncoder(r.Int63()) & all
+		in := ""
+		for j := r.Intn(6); j >= 0; j-- {
+			in += alphabet[r.Intn(len(alphabet))]
+		}
+		encoded := mask.Encode(in)
+		require.Equal(t, in, mask.Decode(encoded), "mask %v in %q encoded %q", mask, in, encoded)
+	}
+}
+
+func TestPath(t *testing.T) {
+	mask := EncodeWin | EncodeRightSpace
+	assert.Equal(t, "dir␠/file？", mask.FromStandardPath("dir /file?"))
+	assert.Equal(t, "dir /file?", mask.ToStandardPath("dir␠/file？"))
+	assert.Equal(t, "", mask.FromStandardPath(""))
+	assert.Equal(t, "a?b", EncodeRaw.FromStandardPath("a?b"))
+}
+
+func TestStringSet(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want MultiEncoder
+		str  string
+		err  bool
+	}{
+		{"None", EncodeRaw, "None", false},
+		{"", EncodeRaw, "None", false},
+		{"Colon", EncodeColon, "Colon", false},
+		{"colon, backslash", EncodeColon | EncodeBackSlash, "Colon,BackSlash", false},
+		{"BackSlash,Colon", EncodeColon | EncodeBackSlash, "Colon,BackSlash", false},
+		{"Potato", EncodeRaw, "", true},
+	} {
+		var mask MultiEncoder
+		err := mask.Set(test.in)
+		if test.err {
+			assert.Error(t, err, test.in)
+			continue
+		}
+		require.NoError(t, err, test.in)
+		assert.Equal(t, test.want, mask, test.in)
+		assert.Equal(t, test.str, mask.String(), test.in)
+	}
+	assert.Equal(t, "Encoding", new(MultiEncoder).Type())
+}
@@ -3,6 +3,7 @@ package filter
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -352,6 +353,17 @@ func (f *Filter) includeRemote(remote string) bool {
 	return true
 }
 
+// IncludeRemote returns whether this remote passes the filter rules
+// and --files-from.  Unlike Include it doesn't need the size or
+// modification time so it ignores the filters which use them.
+func (f *Filter) IncludeRemote(remote string) bool {
+	if f.files != nil {
+		_, include := f.files[remote]
+		return include
+	}
+	return f.includeRemote(remote)
+}
+
 // ListContainsExcludeFile checks if exclude file is present in the list.
 func (f *Filter) ListContainsExcludeFile(entries fs.DirEntries) bool {
 	if len(f.Opt.ExcludeFile) == 0 {
@@ -371,12 +383,12 @@ func (f *Filter) ListContainsExcludeFile(entries fs.DirEntries) bool {
 
 // IncludeDirectory returns a function which checks whether this
 // directory should be included in the sync or not.
-func (f *Filter) IncludeDirectory(fs fs.Fs) func(string) (bool, error) {
+func (f *Filter) IncludeDirectory(ctx context.Context, fs fs.Fs) func(string) (bool, error) {
 	return func(remote string) (bool, error) {
 		remote = strings.Trim(remote, "/")
 		// first check if we need to remove directory based on
 		// the exclude file
-		excl, err := f.DirContainsExcludeFile(fs, remote)
+		excl, err := f.DirContainsExcludeFile(ctx, fs, remote)
 		if err != nil {
 			return false, err
 		}
@@ -403,9 +415,9 @@ func (f *Filter) IncludeDirectory(fs fs.Fs) func(string) (bool, error) {
 // DirContainsExcludeFile checks if exclude file is present in a
 // directroy. If fs is nil, it works properly if ExcludeFile is an
 // empty string (for testing).
-func (f *Filter) DirContainsExcludeFile(fremote fs.Fs, remote string) (bool, error) {
+func (f *Filter) DirContainsExcludeFile(ctx context.Context, fremote fs.Fs, remote string) (bool, error) {
 	if len(f.Opt.ExcludeFile) > 0 {
-		exists, err := fs.FileExists(fremote, path.Join(remote, f.Opt.ExcludeFile))
+		exists, err := fs.FileExists(ctx, fremote, path.Join(remote, f.Opt.ExcludeFile))
 		if err != nil {
 			return false, err
 		}
@@ -506,14 +518,14 @@ func (f *Filter) HaveFilesFrom() bool {
 var errFilesFromNotSet = errors.New("--files-from not set so can't use Filter.ListR")
 
 // MakeListR makes function to return all the files set using --files-from
-func (f *Filter) MakeListR(NewObject func(remote string) (fs.Object, error)) fs.ListRFn {
-	return func(dir string, callback fs.ListRCallback) error {
+func (f *Filter) MakeListR(ctx context.Context, NewObject func(ctx context.Context, remote string) (fs.Object, error)) fs.ListRFn {
+	return func(ctx context.Context, dir string, callback fs.ListRCallback) error {
 		if !f.HaveFilesFrom() {
 			return errFilesFromNotSet
 		}
 		var entries fs.DirEntries
 		for remote := range f.files {
-			entry, err := NewObject(remote)
+			entry, err := NewObject(ctx, remote)
 			if err == fs.ErrorObjectNotFound {
 				// Skip files that are not found
 			} else if err != nil {
@@ -1,6 +1,7 @@
 package filter
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -124,8 +125,9 @@ type includeDirTest struct {
 }
 
 func testDirInclude(t *testing.T, f *Filter, tests []includeDirTest) {
+	ctx := context.Background()
 	for _, test := range tests {
-		got, err := f.IncludeDirectory(nil)(test.in)
+		got, err := f.IncludeDirectory(ctx, nil)(test.in)
 		require.NoError(t, err)
 		assert.Equal(t, test.want, got, test.in)
 	}
@@ -196,12 +198,13 @@ func TestNewFilterHaveFilesFrom(t *testing.T) {
 }
 
 func TestNewFilterMakeListR(t *testing.T) {
+	ctx := context.Background()
 	f, err := NewFilter(nil)
 	require.NoError(t, err)
 
 	// Check error if no files
-	listR := f.MakeListR(nil)
-	err = listR("", nil)
+	listR := f.MakeListR(ctx, nil)
+	err = listR(ctx, "", nil)
 	assert.EqualError(t, err, errFilesFromNotSet.Error())
 
 	// Add some files
@@ -220,7 +223,7 @@ func TestNewFilterMakeListR(t *testing.T) {
 
 	// NewObject function for MakeListR
 	newObjects := FilesMap{}
-	NewObject := func(remote string) (fs.Object, error) {
+	NewObject := func(ctx context.Context, remote string) (fs.Object, error) {
 		if remote == "notfound" {
 			return nil, fs.ErrorObjectNotFound
 		} else if remote == "error" {
@@ -241,8 +244,8 @@ func TestNewFilterMakeListR(t *testing.T) {
 	}
 
 	// Make the listR and call it
-	listR = f.MakeListR(NewObject)
-	err = listR("", listRcallback)
+	listR = f.MakeListR(ctx, NewObject)
+	err = listR(ctx, "", listRcallback)
 	require.NoError(t, err)
 
 	// Check that the correct objects were created and listed
@@ -257,7 +260,7 @@ func TestNewFilterMakeListR(t *testing.T) {
 
 	// Now check an error is returned from NewObject
 	require.NoError(t, f.AddFile("error"))
-	err = listR("", listRcallback)
+	err = listR(ctx, "", listRcallback)
 	require.EqualError(t, err, assert.AnError.Error())
 }
 
@@ -2,6 +2,8 @@
 package fs
 
 import (
+	"context"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -12,6 +14,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ncw/rclone/fs/config/configmap"
@@ -62,6 +65,7 @@ var (
 	ErrorCantMoveOverlapping         = errors.New("can't move files on overlapping remotes")
 	ErrorDirectoryNotEmpty           = errors.New("directory not empty")
 	ErrorImmutableModified           = errors.New("immutable file modified")
+	ErrorCommandNotFound             = errors.New("command not found")
 	ErrorPermissionDenied            = errors.New("permission denied")
 )
 
@@ -79,8 +83,40 @@ type RegInfo struct {
 	NewFs func(name string, root string, config configmap.Mapper) (Fs, error) `json:"-"`
 	// Function to call to help with config
 	Config func(name string, config configmap.Mapper) `json:"-"`
+	// Function to call to do the config a step at a time without
+	// interacting with the user.  It is passed the state and the
+	// answer to the last question and returns the next question
+	// or nil when the config is complete.
+	ConfigState func(name string, config configmap.Mapper, in ConfigIn) (*ConfigOut, error) `json:"-"`
 	// Options for the Fs configuration
 	Options Options
+	// The command help, if any
+	CommandHelp []CommandHelp
+}
+
+// CommandHelp describes a single backend Command
+//
+// These are automatically inserted in the docs
+type CommandHelp struct {
+	Name  string            // Name of the command, eg "link"
+	Short string            // Single line description
+	Long  string            // Long multi-line description
+	Opts  map[string]string // maps option name to a single line help
+}
+
+// ConfigIn is passed to the ConfigState function of a backend
+type ConfigIn struct {
+	State  string // state from the last ConfigOut or "" to start
+	Result string // the answer to the question in the last ConfigOut
+}
+
+// ConfigOut is returned by the ConfigState function of a backend to
+// ask the next question
+type ConfigOut struct {
+	State   string  `json:"state"`             // state to pass back in the next ConfigIn
+	Option  *Option `json:"option"`            // the question to ask
+	AuthURL string  `json:"authURL,omitempty"` // URL to visit to authorize rclone if set
+	Error   string  `json:"error,omitempty"`   // why the last answer wasn't accepted if set
 }
 
 // FileName returns the on disk file name for this backend
@@ -219,28 +255,28 @@ type Fs interface {
 	//
 	// This should return ErrDirNotFound if the directory isn't
 	// found.
-	List(dir string) (entries DirEntries, err error)
+	List(ctx context.Context, dir string) (entries DirEntries, err error)
 
 	// NewObject finds the Object at remote.  If it can't be found
 	// it returns the error ErrorObjectNotFound.
-	NewObject(remote string) (Object, error)
+	NewObject(ctx context.Context, remote string) (Object, error)
 
 	// Put in to the remote path with the modTime given of the given size
 	//
 	// May create the object even if it returns an error - if so
 	// will return the object and the error, otherwise will return
 	// nil and the error
-	Put(in io.Reader, src ObjectInfo, options ...OpenOption) (Object, error)
+	Put(ctx context.Context, in io.Reader, src ObjectInfo, options ...OpenOption) (Object, error)
 
 	// Mkdir makes the directory (container, bucket)
 	//
 	// Shouldn't return an error if it already exists
-	Mkdir(dir string) error
+	Mkdir(ctx context.Context, dir string) error
 
 	// Rmdir removes the directory (container, bucket) if empty
 	//
 	// Return an error if it doesn't exist or isn't empty
-	Rmdir(dir string) error
+	Rmdir(ctx context.Context, dir string) error
 }
 
 // Info provides a read only interface to information about a filesystem.
@@ -269,16 +305,16 @@ type Object interface {
 	ObjectInfo
 
 	// SetModTime sets the metadata on the object to set the modification date
-	SetModTime(time.Time) error
+	SetModTime(ctx context.Context, t time.Time) error
 
 	// Open opens the file for read.  Call Close() on the returned io.ReadCloser
-	Open(options ...OpenOption) (io.ReadCloser, error)
+	Open(ctx context.Context, options ...OpenOption) (io.ReadCloser, error)
 
 	// Update in to the object with the modTime given of the given size
-	Update(in io.Reader, src ObjectInfo, options ...OpenOption) error
+	Update(ctx context.Context, in io.Reader, src ObjectInfo, options ...OpenOption) error
 
 	// Removes this object
-	Remove() error
+	Remove(ctx context.Context) error
 }
 
 // ObjectInfo provides read only information about an object.
@@ -290,7 +326,7 @@ type ObjectInfo interface {
 
 	// Hash returns the selected checksum of the file
 	// If no checksum is available it returns ""
-	Hash(hash.Type) (string, error)
+	Hash(ctx context.Context, ty hash.Type) (string, error)
 
 	// Storable says whether this object can be stored
 	Storable() bool
@@ -334,6 +370,14 @@ type MimeTyper interface {
 	MimeType() string
 }
 
+// Metadataer is an optional interface for Object
+type Metadataer interface {
+	// Metadata returns metadata for an object
+	//
+	// It should return nil if there is no Metadata
+	Metadata(ctx context.Context) (Metadata, error)
+}
+
 // IDer is an optional interface for Object
 type IDer interface {
 	// ID returns the ID of the Object if known, or "" if not
@@ -351,7 +395,7 @@ type ObjectUnWrapper interface {
 type SetTierer interface {
 	// SetTier performs changing storage tier of the Object if
 	// multiple storage classes supported
-	SetTier(tier string) error
+	SetTier(ctx context.Context, tier string) error
 }
 
 // GetTierer is an optional interface for Object
@@ -367,7 +411,7 @@ type GetTierer interface {
 type ListRCallback func(entries DirEntries) error
 
 // ListRFn is defines the call used to recursively list a directory
-type ListRFn func(dir string, callback ListRCallback) error
+type ListRFn func(ctx context.Context, dir string, callback ListRCallback) error
 
 // NewUsageValue makes a valid value
 func NewUsageValue(value int64) *int64 {
@@ -395,6 +439,8 @@ type Features struct {
 	DuplicateFiles          bool // allows duplicate files
 	ReadMimeType            bool // can read the mime type of objects
 	WriteMimeType           bool // can set the mime type of objects
+	ReadMetadata            bool // can read the metadata of objects
+	WriteMetadata           bool // can write the metadata of objects
 	CanHaveEmptyDirectories bool // can have empty directories
 	BucketBased             bool // is bucket based (like s3, swift etc)
 	SetTier                 bool // allows set tier functionality on objects
@@ -406,7 +452,7 @@ type Features struct {
 	// quicker than just running Remove() on the result of List()
 	//
 	// Return an error if it doesn't exist
-	Purge func() error
+	Purge func(ctx context.Context) error
 
 	// Copy src to this remote using server side copy operations.
 	//
@@ -417,7 +463,7 @@ type Features struct {
 	// Will only be called if src.Fs().Name() == f.Name()
 	//
 	// If it isn't possible then return fs.ErrorCantCopy
-	Copy func(src Object, remote string) (Object, error)
+	Copy func(ctx context.Context, src Object, remote string) (Object, error)
 
 	// Move src to this remote using server side move operations.
 	//
@@ -428,7 +474,7 @@ type Features struct {
 	// Will only be called if src.Fs().Name() == f.Name()
 	//
 	// If it isn't possible then return fs.ErrorCantMove
-	Move func(src Object, remote string) (Object, error)
+	Move func(ctx context.Context, src Object, remote string) (Object, error)
 
 	// DirMove moves src, srcRemote to this remote at dstRemote
 	// using server side move operations.
@@ -438,12 +484,12 @@ type Features struct {
 	// If it isn't possible then return fs.ErrorCantDirMove
 	//
 	// If destination exists then return fs.ErrorDirExists
-	DirMove func(src Fs, srcRemote, dstRemote string) error
+	DirMove func(ctx context.Context, src Fs, srcRemote, dstRemote string) error
 
 	// ChangeNotify calls the passed function with a path
 	// that has had changes. If the implementation
 	// uses polling, it should adhere to the given interval.
-	ChangeNotify func(func(string, EntryType), <-chan time.Duration)
+	ChangeNotify func(context.Context, func(string, EntryType), <-chan time.Duration)
 
 	// UnWrap returns the Fs that this Fs is wrapping
 	UnWrap func() Fs
@@ -459,7 +505,7 @@ type Features struct {
 	DirCacheFlush func()
 
 	// PublicLink generates a public link to the remote path (usually readable by anyone)
-	PublicLink func(remote string) (string, error)
+	PublicLink func(ctx context.Context, remote string) (string, error)
 
 	// Put in to the remote path with the modTime given of the given size
 	//
@@ -469,24 +515,24 @@ type Features struct {
 	//
 	// May create duplicates or return errors if src already
 	// exists.
-	PutUnchecked func(in io.Reader, src ObjectInfo, options ...OpenOption) (Object, error)
+	PutUnchecked func(ctx context.Context, in io.Reader, src ObjectInfo, options ...OpenOption) (Object, error)
 
 	// PutStream uploads to the remote path with the modTime given of indeterminate size
 	//
 	// May create the object even if it returns an error - if so
 	// will return the object and the error, otherwise will return
 	// nil and the error
-	PutStream func(in io.Reader, src ObjectInfo, options ...OpenOption) (Object, error)
+	PutStream func(ctx context.Context, in io.Reader, src ObjectInfo, options ...OpenOption) (Object, error)
 
 	// MergeDirs merges the contents of all the directories passed
 	// in into the first one and rmdirs the other directories.
-	MergeDirs func([]Directory) error
+	MergeDirs func(ctx context.Context, dirs []Directory) error
 
 	// CleanUp the trash in the Fs
 	//
 	// Implement this if you have a way of emptying the trash or
 	// otherwise cleaning up old versions of files.
-	CleanUp func() error
+	CleanUp func(ctx context.Context) error
 
 	// ListR lists the objects and directories of the Fs starting
 	// from dir recursively into out.
@@ -507,7 +553,25 @@ type Features struct {
 	ListR ListRFn
 
 	// About gets quota information from the Fs
-	About func() (*Usage, error)
+	About func(ctx context.Context) (*Usage, error)
+
+	// OpenWriterAt opens with a handle for random access writes
+	//
+	// Pass in the remote desired and the size if known.
+	//
+	// It truncates any existing object
+	OpenWriterAt func(ctx context.Context, remote string, size int64) (WriterAtCloser, error)
+
+	// Command the backend to run a named command
+	//
+	// The command run is name
+	// args may be used to read arguments from
+	// opts may be used to read optional arguments from
+	//
+	// The result should be capable of being JSON encoded
+	// If it is a string or a []string it will be shown to the user
+	// otherwise it will be JSON encoded and shown to the user like that
+	Command func(ctx context.Context, name string, arg []string, opt map[string]string) (interface{}, error)
 }
 
 // Disable nil's out the named feature.  If it isn't found then it
@@ -600,6 +664,12 @@ func (ft *Features) Fill(f Fs) *Features {
 	if do, ok := f.(Abouter); ok {
 		ft.About = do.About
 	}
+	if do, ok := f.(OpenWriterAter); ok {
+		ft.OpenWriterAt = do.OpenWriterAt
+	}
+	if do, ok := f.(Commander); ok {
+		ft.Command = do.Command
+	}
 	return ft.DisableList(Config.DisableFeatures)
 }
 
@@ -615,6 +685,8 @@ func (ft *Features) Mask(f Fs) *Features {
 	ft.DuplicateFiles = ft.DuplicateFiles && mask.DuplicateFiles
 	ft.ReadMimeType = ft.ReadMimeType && mask.ReadMimeType
 	ft.WriteMimeType = ft.WriteMimeType && mask.WriteMimeType
+	ft.ReadMetadata = ft.ReadMetadata && mask.ReadMetadata
+	ft.WriteMetadata = ft.WriteMetadata && mask.WriteMetadata
 	ft.CanHaveEmptyDirectories = ft.CanHaveEmptyDirectories && mask.CanHaveEmptyDirectories
 	ft.BucketBased = ft.BucketBased && mask.BucketBased
 	ft.SetTier = ft.SetTier && mask.SetTier
@@ -659,6 +731,11 @@ func (ft *Features) Mask(f Fs) *Features {
 	if mask.About == nil {
 		ft.About = nil
 	}
+	if mask.OpenWriterAt == nil {
+		ft.OpenWriterAt = nil
+	}
+	// Command is left untouched as the commands belong to the
+	// backend which implements them rather than the one wrapped
 	return ft.DisableList(Config.DisableFeatures)
 }
 
@@ -694,7 +771,7 @@ type Purger interface {
 	// quicker than just running Remove() on the result of List()
 	//
 	// Return an error if it doesn't exist
-	Purge() error
+	Purge(ctx context.Context) error
 }
 
 // Copier is an optional interface for Fs
@@ -708,7 +785,7 @@ type Copier interface {
 	// Will only be called if src.Fs().Name() == f.Name()
 	//
 	// If it isn't possible then return fs.ErrorCantCopy
-	Copy(src Object, remote string) (Object, error)
+	Copy(ctx context.Context, src Object, remote string) (Object, error)
 }
 
 // Mover is an optional interface for Fs
@@ -722,7 +799,7 @@ type Mover interface {
 	// Will only be called if src.Fs().Name() == f.Name()
 	//
 	// If it isn't possible then return fs.ErrorCantMove
-	Move(src Object, remote string) (Object, error)
+	Move(ctx context.Context, src Object, remote string) (Object, error)
 }
 
 // DirMover is an optional interface for Fs
@@ -735,7 +812,7 @@ type DirMover interface {
 	// If it isn't possible then return fs.ErrorCantDirMove
 	//
 	// If destination exists then return fs.ErrorDirExists
-	DirMove(src Fs, srcRemote, dstRemote string) error
+	DirMove(ctx context.Context, src Fs, srcRemote, dstRemote string) error
 }
 
 // ChangeNotifier is an optional interface for Fs
@@ -749,7 +826,7 @@ type ChangeNotifier interface {
 	// The ChangeNotify implemantion must empty the channel
 	// regulary. When the channel gets closed, the implemantion
 	// should stop polling and release resources.
-	ChangeNotify(func(string, EntryType), <-chan time.Duration)
+	ChangeNotify(context.Context, func(string, EntryType), <-chan time.Duration)
 }
 
 // UnWrapper is an optional interfaces for Fs
@@ -783,7 +860,7 @@ type PutUncheckeder interface {
 	//
 	// May create duplicates or return errors if src already
 	// exists.
-	PutUnchecked(in io.Reader, src ObjectInfo, options ...OpenOption) (Object, error)
+	PutUnchecked(ctx context.Context, in io.Reader, src ObjectInfo, options ...OpenOption) (Object, error)
 }
 
 // PutStreamer is an optional interface for Fs
@@ -793,20 +870,20 @@ type PutStreamer interface {
 	// May create the object even if it returns an error - if so
 	// will return the object and the error, otherwise will return
 	// nil and the error
-	PutStream(in io.Reader, src ObjectInfo, options ...OpenOption) (Object, error)
+	PutStream(ctx context.Context, in io.Reader, src ObjectInfo, options ...OpenOption) (Object, error)
 }
 
 // PublicLinker is an optional interface for Fs
 type PublicLinker interface {
 	// PublicLink generates a public link to the remote path (usually readable by anyone)
-	PublicLink(remote string) (string, error)
+	PublicLink(ctx context.Context, remote string) (string, error)
 }
 
 // MergeDirser is an option interface for Fs
 type MergeDirser interface {
 	// MergeDirs merges the contents of all the directories passed
 	// in into the first one and rmdirs the other directories.
-	MergeDirs([]Directory) error
+	MergeDirs(ctx context.Context, dirs []Directory) error
 }
 
 // CleanUpper is an optional interfaces for Fs
@@ -815,7 +892,7 @@ type CleanUpper interface {
 	//
 	// Implement this if you have a way of emptying the trash or
 	// otherwise cleaning up old versions of files.
-	CleanUp() error
+	CleanUp(ctx context.Context) error
 }
 
 // ListRer is an optional interfaces for Fs
@@ -836,7 +913,7 @@ type ListRer interface {
 	//
 	// Don't implement this unless you have a more efficient way
 	// of listing recursively that doing a directory traversal.
-	ListR(dir string, callback ListRCallback) error
+	ListR(ctx context.Context, dir string, callback ListRCallback) error
 }
 
 // RangeSeeker is the interface that wraps the RangeSeek method.
@@ -855,7 +932,37 @@ type RangeSeeker interface {
 // Abouter is an optional interface for Fs
 type Abouter interface {
 	// About gets quota information from the Fs
-	About() (*Usage, error)
+	About(ctx context.Context) (*Usage, error)
+}
+
+// OpenWriterAter is an optional interface for Fs
+type OpenWriterAter interface {
+	// OpenWriterAt opens with a handle for random access writes
+	//
+	// Pass in the remote desired and the size if known.
+	//
+	// It truncates any existing object
+	OpenWriterAt(ctx context.Context, remote string, size int64) (WriterAtCloser, error)
+}
+
+// Commander is an interface to wrap the Command function
+type Commander interface {
+	// Command the backend to run a named command
+	//
+	// The command run is name
+	// args may be used to read arguments from
+	// opts may be used to read optional arguments from
+	//
+	// The result should be capable of being JSON encoded
+	// If it is a string or a []string it will be shown to the user
+	// otherwise it will be JSON encoded and shown to the user like that
+	Command(ctx context.Context, name string, arg []string, opt map[string]string) (interface{}, error)
+}
+
+// WriterAtCloser wraps io.WriterAt and io.Closer
+type WriterAtCloser interface {
+	io.WriterAt
+	io.Closer
 }
 
 // ObjectsChan is a channel of Objects
@@ -903,8 +1010,12 @@ func ParseRemote(path string) (fsInfo *RegInfo, configName, fsPath string, err e
 	var fsName string
 	var ok bool
 	if configName != "" {
-		if strings.HasPrefix(configName, ":") {
-			fsName = configName[1:]
+		name, _, err := fspath.SplitConfigName(expandConfigName(configName))
+		if err != nil {
+			return nil, "", "", err
+		}
+		if strings.HasPrefix(name, ":") {
+			fsName = name[1:]
 		} else {
 			m := ConfigMap(nil, configName)
 			fsName, ok = m.Get("type")
@@ -983,14 +1094,30 @@ func (section getConfigFile) Get(key string) (value string, ok bool) {
 // ConfigMap creates a configmap.Map from the *RegInfo and the
 // configName passed in.
 //
+// The configName may have a connection string, eg
+// "remote,opt=value", whose options override all the other config.
+// It may also be a name with a hash of the connection string as
+// returned by ConfigFs.
+//
 // If fsInfo is nil then the returned configmap.Map should only be
 // used for reading non backend specific parameters, such as "type".
 func ConfigMap(fsInfo *RegInfo, configName string) (config *configmap.Map) {
 	// Create the config
 	config = configmap.New()
 
+	// Split the connection string from the name
+	name, options, err := fspath.SplitConfigName(expandConfigName(configName))
+	if err == nil {
+		configName = name
+	}
+
 	// Read the config, more specific to least specific
 
+	// connection string values
+	if len(options) > 0 {
+		config.AddGetter(options)
+	}
+
 	// flag values
 	if fsInfo != nil {
 		config.AddGetter(&regInfoValues{fsInfo, false})
@@ -1017,12 +1144,53 @@ func ConfigMap(fsInfo *RegInfo, configName string) (config *configmap.Map) {
 	return config
 }
 
+// Remotes with connection strings are given to NewFs under a name
+// with a hash of the connection string, eg "remote{1a2b3c4d}", so that
+// the option values, which may be secret, don't end up in logs or
+// file paths made from the name of the Fs.  The connection strings
+// are remembered here so the config can be made from the name again.
+var (
+	connectionStringsMu sync.Mutex
+	connectionStrings   = map[string]string{} // name with hash to configName
+)
+
+// hashConfigName returns the name to make the Fs for configName with,
+// replacing any connection string with a hash of it.
+func hashConfigName(configName string) string {
+	name, options, err := fspath.SplitConfigName(configName)
+	if err != nil || len(options) == 0 {
+		return configName
+	}
+	sum := sha256.Sum256([]byte(configName))
+	hashed := fmt.Sprintf("%s{%x}", name, sum[:4])
+	connectionStringsMu.Lock()
+	connectionStrings[hashed] = configName
+	connectionStringsMu.Unlock()
+	return hashed
+}
+
+// expandConfigName returns the configName with the connection string
+// that configName was made from by hashConfigName, or configName if
+// it wasn't.
+func expandConfigName(configName string) string {
+	connectionStringsMu.Lock()
+	defer connectionStringsMu.Unlock()
+	if expanded, ok := connectionStrings[configName]; ok {
+		return expanded
+	}
+	return configName
+}
+
 // ConfigFs makes the config for calling NewFs with.
 //
 // It parses the path which is of the form remote:path
 //
 // Remotes are looked up in the config file.  If the remote isn't
 // found then NotFoundInConfigFile will be returned.
+//
+// If the remote has a connection string then configName is the name
+// of the remote with a hash of the connection string, eg
+// "remote{1a2b3c4d}", and the options are only in config.
 func ConfigFs(path string) (fsInfo *RegInfo, configName, fsPath string, config *configmap.Map, err error) {
 	// Parse the remote path
 	fsInfo, configName, fsPath, err = ParseRemote(path)
@@ -1030,6 +1198,7 @@ func ConfigFs(path string) (fsInfo *RegInfo, configName, fsPath string, config *
 		return
 	}
 	config = ConfigMap(fsInfo, configName)
+	configName = hashConfigName(configName)
 	return
 }
 
@@ -1076,8 +1245,8 @@ func CheckClose(c io.Closer, err *error) {
 
 // FileExists returns true if a file remote exists.
 // If remote is a directory, FileExists returns false.
-func FileExists(fs Fs, remote string) (bool, error) {
-	_, err := fs.NewObject(remote)
+func FileExists(ctx context.Context, fs Fs, remote string) (bool, error) {
+	_, err := fs.NewObject(ctx, remote)
 	if err != nil {
 		if err == ErrorObjectNotFound || err == ErrorNotAFile || err == ErrorPermissionDenied {
 			return false, nil
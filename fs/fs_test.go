@@ -1,16 +1,19 @@
 package fs
 
 import (
+	"context"
 	"strings"
 	"testing"
 
+	"github.com/ncw/rclone/fs/config/configmap"
 	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFeaturesDisable(t *testing.T) {
 	ft := new(Features)
-	ft.Copy = func(src Object, remote string) (Object, error) {
+	ft.Copy = func(ctx context.Context, src Object, remote string) (Object, error) {
 		return nil, nil
 	}
 	ft.CaseInsensitive = true
@@ -36,7 +39,7 @@ func TestFeaturesList(t *testing.T) {
 
 func TestFeaturesDisableList(t *testing.T) {
 	ft := new(Features)
-	ft.Copy = func(src Object, remote string) (Object, error) {
+	ft.Copy = func(ctx context.Context, src Object, remote string) (Object, error) {
 		return nil, nil
 	}
 	ft.CaseInsensitive = true
@@ -70,3 +73,125 @@ func TestOption(t *testing.T) {
 	err = d.Set("sdfsdf")
 	assert.Error(t, err)
 }
+
+func TestConnectionString(t *testing.T) {
+	fsInfo := &RegInfo{
+		Name: "connectionstringtest",
+		Options: Options{{
+			Name:    "potato",
+			Default: "raw",
+		}, {
+			Name:    "sausage",
+			Default: "",
+		}},
+	}
+	Register(fsInfo)
+	defer func() {
+		Registry = Registry[:len(Registry)-1]
+	}()
+	oldConfigFileGet := ConfigFileGet
+	defer func() {
+		ConfigFileGet = oldConfigFileGet
+	}()
+	ConfigFileGet = func(section, key string) (string, bool) {
+		if section != "myremote" {
+			return "", false
+		}
+		switch key {
+		case "type":
+			return "connectionstringtest", true
+		case "potato":
+			return "mashed", true
+		case "sausage":
+			return "pork", true
+		}
+		return "", false
+	}
+
+	for _, test := range []struct {
+		in                       string
+		wantConfigName, wantPath string
+		wantPotato, wantSausage  string
+		wantErr                  string
+	}{
+		{":connectionstringtest:path", ":connectionstringtest", "path", "raw", "", ""},
+		{":connectionstringtest,potato=baked,sausage='a:b':path", ":connectionstringtest,potato=baked,sausage='a:b'", "path", "baked", "a:b", ""},
+		{"myremote:path", "myremote", "path", "mashed", "pork", ""},
+		{"myremote,potato=chips:path", "myremote,potato=chips", "path", "chips", "pork", ""},
+		{"myremote,type=connectionstringtest,potato:", "myremote,type=connectionstringtest,potato", "", "true", "pork", ""},
+		{"notmyremote,potato=chips:path", "", "", "", "", "didn't find section in config file"},
+		{"myremote,potato='chips:path", "local", "myremote,potato='chips:path", "", "", ""},
+	} {
+		fsInfo, configName, fsPath, err := ParseRemote(test.in)
+		if test.wantErr != "" {
+			assert.Error(t, err, test.in)
+			assert.Contains(t, err.Error(), test.wantErr, test.in)
+			continue
+		}
+		if configName == "local" {
+			// badly formed connection strings are local paths
+			assert.Equal(t, test.wantConfigName, configName, test.in)
+			assert.Equal(t, test.wantPath, fsPath, test.in)
+			continue
+		}
+		assert.NoError(t, err, test.in)
+		assert.Equal(t, "connectionstringtest", fsInfo.Name, test.in)
+		assert.Equal(t, test.wantConfigName, configName, test.in)
+		assert.Equal(t, test.wantPath, fsPath, test.in)
+		m := ConfigMap(fsInfo, configName)
+		potato, _ := m.Get("potato")
+		assert.Equal(t, test.wantPotato, potato, test.in)
+		sausage, _ := m.Get("sausage")
+		assert.Equal(t, test.wantSausage, sausage, test.in)
+	}
+}
+
+func TestConnectionStringName(t *testing.T) {
+	var gotName string
+	fsInfo := &RegInfo{
+		Name: "connectionstringnametest",
+		NewFs: func(name, root string, m configmap.Mapper) (Fs, error) {
+			gotName = name
+			return nil, nil
+		},
+		Options: Options{{
+			Name:    "secret",
+			Default: "",
+		}},
+	}
+	Register(fsInfo)
+	defer func() {
+		Registry = Registry[:len(Registry)-1]
+	}()
+
+	_, err := NewFs(":connectionstringnametest,secret=potato:path")
+	require.NoError(t, err)
+	assert.NotContains(t, gotName, "potato")
+	assert.NotContains(t, gotName, ",")
+	assert.True(t, strings.HasPrefix(gotName, ":connectionstringnametest{"), gotName)
+
+	// The same connection string gets the same name
+	firstName := gotName
+	_, err = NewFs(":connectionstringnametest,secret=potato:other/path")
+	require.NoError(t, err)
+	assert.Equal(t, firstName, gotName)
+
+	// A different one gets a different name
+	_, err = NewFs(":connectionstringnametest,secret=sausage:path")
+	require.NoError(t, err)
+	assert.NotEqual(t, firstName, gotName)
+
+	// The config can be found again from the name
+	gotInfo, configName, fsPath, config, err := ConfigFs(firstName + ":path")
+	require.NoError(t, err)
+	assert.Equal(t, fsInfo, gotInfo)
+	assert.Equal(t, firstName, configName)
+	assert.Equal(t, "path", fsPath)
+	secret, _ := config.Get("secret")
+	assert.Equal(t, "potato", secret)
+
+	// Names without a connection string are unchanged
+	_, err = NewFs(":connectionstringnametest:path")
+	require.NoError(t, err)
+	assert.Equal(t, ":connectionstringnametest", gotName)
+}
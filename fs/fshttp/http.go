@@ -25,8 +25,33 @@ var (
 	transport   http.RoundTripper
 	noTransport sync.Once
 	tpsBucket   *rate.Limiter // for limiting number of http transactions per second
+
+	requestsMu sync.Mutex
+	requests   = map[string]int64{} // number of requests made by each backend type
 )
 
+// countRequest increments the number of requests made by backend
+func countRequest(backend string) {
+	requestsMu.Lock()
+	requests[backend]++
+	requestsMu.Unlock()
+}
+
+// RequestCounts returns the number of HTTP requests made by each
+// backend type since the start of the process.
+//
+// Only requests made with a client or transport from
+// NewClientForBackend or NewTransportForBackend are counted.
+func RequestCounts() map[string]int64 {
+	requestsMu.Lock()
+	defer requestsMu.Unlock()
+	counts := make(map[string]int64, len(requests))
+	for backend, n := range requests {
+		counts[backend] = n
+	}
+	return counts
+}
+
 // StartHTTPTokenBucket starts the token bucket if necessary
 func StartHTTPTokenBucket() {
 	if fs.Config.TPSLimit > 0 {
@@ -147,6 +172,37 @@ func NewClient(ci *fs.ConfigInfo) *http.Client {
 	}
 }
 
+// countingTransport counts the requests made through it by a backend
+type countingTransport struct {
+	http.RoundTripper
+	backend string
+}
+
+// RoundTrip implements the RoundTripper interface.
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	countRequest(t.backend)
+	return t.RoundTripper.RoundTrip(req)
+}
+
+// NewTransportForBackend returns an http.RoundTripper like
+// NewTransport which counts its requests in RequestCounts under the
+// backend type passed in
+func NewTransportForBackend(ci *fs.ConfigInfo, backend string) http.RoundTripper {
+	return &countingTransport{
+		RoundTripper: NewTransport(ci),
+		backend:      backend,
+	}
+}
+
+// NewClientForBackend returns an http.Client like NewClient which
+// counts its requests in RequestCounts under the backend type passed
+// in
+func NewClientForBackend(ci *fs.ConfigInfo, backend string) *http.Client {
+	return &http.Client{
+		Transport: NewTransportForBackend(ci, backend),
+	}
+}
+
 // Transport is a our http Transport which wraps an http.Transport
 // * Sets the User Agent
 // * Does logging
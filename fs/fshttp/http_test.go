@@ -3,9 +3,12 @@ package fshttp
 import (
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/ncw/rclone/fs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // returns the "%p" reprentation of the thing passed in
@@ -76,3 +79,27 @@ func TestCleanAuths(t *testing.T) {
 		assert.Equal(t, test.want, got, test.in)
 	}
 }
+
+func TestRequestCounts(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	const backend = "TestRequestCounts"
+	old := RequestCounts()
+	for _, client := range []*http.Client{
+		NewClientForBackend(fs.Config, backend),
+		NewClient(fs.Config),
+	} {
+		for i := 0; i < 3; i++ {
+			resp, err := client.Get(ts.URL)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+		}
+	}
+	counts := RequestCounts()
+	assert.Equal(t, old[backend]+3, counts[backend])
+	// the plain client isn't counted
+	delete(counts, backend)
+	delete(old, backend)
+	assert.Equal(t, old, counts)
+}
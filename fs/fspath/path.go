@@ -2,17 +2,125 @@
 package fspath
 
 import (
+	"bytes"
 	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
 
+	"github.com/ncw/rclone/fs/config/configmap"
 	"github.com/ncw/rclone/fs/driveletter"
+	"github.com/pkg/errors"
 )
 
 // Matcher is a pattern to match an rclone URL
 var Matcher = regexp.MustCompile(`^(:?[\w_ -]+):(.*)$`)
 
+// isNameChar returns whether c may be used in a remote name
+func isNameChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' || c == ' ' || c == '-'
+}
+
+// isKeyChar returns whether c may be used in an option name in a
+// connection string
+func isKeyChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' || c == '-'
+}
+
+// parseValue reads an option value from the start of s which is
+// either quoted with ' or " or runs up to the next , or :
+//
+// Inside a quoted value the quote character is written twice to
+// include it in the value.
+func parseValue(s string) (value, rest string, err error) {
+	if s == "" || (s[0] != '\'' && s[0] != '"') {
+		i := strings.IndexAny(s, ",:")
+		if i < 0 {
+			i = len(s)
+		}
+		return s[:i], s[i:], nil
+	}
+	quote := s[0]
+	var buf bytes.Buffer
+	for i := 1; i < len(s); i++ {
+		if s[i] == quote {
+			if i+1 < len(s) && s[i+1] == quote {
+				buf.WriteByte(quote)
+				i++
+				continue
+			}
+			return buf.String(), s[i+1:], nil
+		}
+		buf.WriteByte(s[i])
+	}
+	return "", "", errors.Errorf("unterminated %c quote in connection string", quote)
+}
+
+// isHexChar returns whether c is a lower case hex digit
+func isHexChar(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f')
+}
+
+// hashSuffixLength returns the length of the {hash} suffix at the
+// start of s or 0 if there isn't one
+func hashSuffixLength(s string) int {
+	if s == "" || s[0] != '{' {
+		return 0
+	}
+	i := 1
+	for i < len(s) && isHexChar(s[i]) {
+		i++
+	}
+	if i == 1 || i >= len(s) || s[i] != '}' {
+		return 0
+	}
+	return i + 1
+}
+
+// parseConfigName reads a remote name, optionally prefixed with : and
+// followed by a connection string of options, from the start of s.
+//
+// The name may end with a hash of the options in {}, as given to the
+// Fs made from a remote with a connection string, eg "remote{1a2b3c4d}".
+//
+// It returns the name, the options and the rest of s.
+func parseConfigName(s string) (name string, options configmap.Simple, rest string, err error) {
+	i := 0
+	if s != "" && s[0] == ':' {
+		i++
+	}
+	start := i
+	for i < len(s) && isNameChar(s[i]) {
+		i++
+	}
+	if i == start {
+		return "", nil, s, errors.New("remote name missing")
+	}
+	i += hashSuffixLength(s[i:])
+	name, rest = s[:i], s[i:]
+	options = configmap.Simple{}
+	for rest != "" && rest[0] == ',' {
+		rest = rest[1:]
+		i = 0
+		for i < len(rest) && isKeyChar(rest[i]) {
+			i++
+		}
+		if i == 0 {
+			return "", nil, rest, errors.Errorf("option name missing in connection string of %q", name)
+		}
+		key, value := strings.Replace(rest[:i], "-", "_", -1), "true"
+		rest = rest[i:]
+		if rest != "" && rest[0] == '=' {
+			value, rest, err = parseValue(rest[1:])
+			if err != nil {
+				return "", nil, rest, err
+			}
+		}
+		options[key] = value
+	}
+	return name, options, rest, nil
+}
+
 // Parse deconstructs a remote path into configName and fsPath
 //
 // If the path is a local path then configName will be returned as "".
@@ -20,18 +128,47 @@ var Matcher = regexp.MustCompile(`^(:?[\w_ -]+):(.*)$`)
 // So "remote:path/to/dir" will return "remote", "path/to/dir"
 // and "/path/to/local" will return ("", "/path/to/local")
 //
+// The configName includes any connection string, so
+// "remote,opt=value:path" will return "remote,opt=value", "path".  Use
+// SplitConfigName to separate the options from the name.
+//
+// This means that a relative local path whose first part looks like a
+// remote name followed by a connection string, eg "file,v2:x", is
+// parsed as a remote.  Prefix it with "./" to use it as a local path.
+//
 // Note that this will turn \ into / in the fsPath on Windows
 func Parse(path string) (configName, fsPath string) {
-	parts := Matcher.FindStringSubmatch(path)
 	configName, fsPath = "", path
-	if parts != nil && !driveletter.IsDriveLetter(parts[1]) {
-		configName, fsPath = parts[1], parts[2]
+	_, _, rest, err := parseConfigName(path)
+	if err == nil && rest != "" && rest[0] == ':' {
+		name := path[:len(path)-len(rest)]
+		if !driveletter.IsDriveLetter(name) {
+			configName, fsPath = name, rest[1:]
+		}
 	}
 	// change native directory separators to / if there are any
 	fsPath = filepath.ToSlash(fsPath)
 	return configName, fsPath
 }
 
+// SplitConfigName splits a configName as returned by Parse into the
+// name of the remote and the options set in its connection string.
+//
+// So "remote,opt1=value1,opt2='quoted:value'" will return "remote",
+// {"opt1": "value1", "opt2": "quoted:value"}.  An option without a
+// value, eg "remote,opt", is set to "true" and "-" in option names is
+// changed to "_".
+func SplitConfigName(configName string) (name string, options configmap.Simple, err error) {
+	name, options, rest, err := parseConfigName(configName)
+	if err != nil {
+		return "", nil, err
+	}
+	if rest != "" {
+		return "", nil, errors.Errorf("unexpected %q in connection string of %q", rest, name)
+	}
+	return name, options, nil
+}
+
 // Split splits a remote into a parent and a leaf
 //
 // if it returns leaf as an empty string then remote is a directory
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/ncw/rclone/fs/config/configmap"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParse(t *testing.T) {
@@ -17,6 +19,16 @@ func TestParse(t *testing.T) {
 		{"remote:path/to/file", "remote", "path/to/file"},
 		{"remote:/path/to/file", "remote", "/path/to/file"},
 		{":backend:/path/to/file", ":backend", "/path/to/file"},
+		{"remote,opt=value:path/to/file", "remote,opt=value", "path/to/file"},
+		{"remote,opt='quoted:value',flag:/path", "remote,opt='quoted:value',flag", "/path"},
+		{`:backend,url="http://host:80/":path`, `:backend,url="http://host:80/"`, "path"},
+		{"remote,opt='unterminated:path", "", "remote,opt='unterminated:path"},
+		{"remote,=value:path", "", "remote,=value:path"},
+		{"remote{1a2b3c4d}:path", "remote{1a2b3c4d}", "path"},
+		{":backend{1a2b3c4d}:path", ":backend{1a2b3c4d}", "path"},
+		{"remote{potato}:path", "", "remote{potato}:path"},
+		{"file,v2:x", "file,v2", "x"},
+		{"./file,v2:x", "", "./file,v2:x"},
 	} {
 		gotConfigName, gotFsPath := Parse(test.in)
 		assert.Equal(t, test.wantConfigName, gotConfigName)
@@ -24,6 +36,39 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestSplitConfigName(t *testing.T) {
+	for _, test := range []struct {
+		in          string
+		wantName    string
+		wantOptions configmap.Simple
+		wantErr     string
+	}{
+		{"remote", "remote", configmap.Simple{}, ""},
+		{":backend", ":backend", configmap.Simple{}, ""},
+		{"remote,opt=value", "remote", configmap.Simple{"opt": "value"}, ""},
+		{"remote,opt=", "remote", configmap.Simple{"opt": ""}, ""},
+		{"remote,flag,upload-cutoff=5M", "remote", configmap.Simple{"flag": "true", "upload_cutoff": "5M"}, ""},
+		{`:s3,provider=Minio,endpoint='http://localhost:9000'`, ":s3", configmap.Simple{"provider": "Minio", "endpoint": "http://localhost:9000"}, ""},
+		{`remote,a='it''s',b="say ""hi"", ok"`, "remote", configmap.Simple{"a": "it's", "b": `say "hi", ok`}, ""},
+		{"remote{1a2b3c4d}", "remote{1a2b3c4d}", configmap.Simple{}, ""},
+		{"remote{}", "", nil, `unexpected "{}" in connection string of "remote"`},
+		{"", "", nil, "remote name missing"},
+		{"remote,", "", nil, `option name missing in connection string of "remote"`},
+		{"remote,opt='value", "", nil, "unterminated ' quote in connection string"},
+		{"remote,opt='value'x", "", nil, `unexpected "x" in connection string of "remote"`},
+	} {
+		gotName, gotOptions, err := SplitConfigName(test.in)
+		if test.wantErr != "" {
+			require.Error(t, err, test.in)
+			assert.Equal(t, test.wantErr, err.Error(), test.in)
+			continue
+		}
+		require.NoError(t, err, test.in)
+		assert.Equal(t, test.wantName, gotName, test.in)
+		assert.Equal(t, test.wantOptions, gotOptions, test.in)
+	}
+}
+
 func TestSplit(t *testing.T) {
 	for _, test := range []struct {
 		remote, wantParent, wantLeaf string
@@ -44,6 +89,8 @@ func TestSplit(t *testing.T) {
 		{":remote:/potato/potato", ":remote:/potato/", "potato"},
 		{":remote:potato/sausage", ":remote:potato/", "sausage"},
 
+		{"remote,opt='a:b':potato/sausage", "remote,opt='a:b':potato/", "sausage"},
+
 		{"/", "/", ""},
 		{"/root", "/", "root"},
 		{"/a/b", "/a/", "b"},
@@ -2,6 +2,7 @@
 package list
 
 import (
+	"context"
 	"sort"
 	"strings"
 
@@ -18,9 +19,9 @@ import (
 // files and directories passing the filter will be added.
 //
 // Files will be returned in sorted order
-func DirSorted(f fs.Fs, includeAll bool, dir string) (entries fs.DirEntries, err error) {
+func DirSorted(ctx context.Context, f fs.Fs, includeAll bool, dir string) (entries fs.DirEntries, err error) {
 	// Get unfiltered entries from the fs
-	entries, err = f.List(dir)
+	entries, err = f.List(ctx, dir)
 	if err != nil {
 		return nil, err
 	}
@@ -31,7 +32,7 @@ func DirSorted(f fs.Fs, includeAll bool, dir string) (entries fs.DirEntries, err
 		fs.Debugf(dir, "Excluded")
 		return nil, nil
 	}
-	return filterAndSortDir(entries, includeAll, dir, filter.Active.IncludeObject, filter.Active.IncludeDirectory(f))
+	return filterAndSortDir(entries, includeAll, dir, filter.Active.IncludeObject, filter.Active.IncludeDirectory(ctx, f))
 }
 
 // filter (if required) and check the entries, then sort them
@@ -1,8 +1,11 @@
 package fs
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -68,15 +71,91 @@ func (l *LogLevel) Type() string {
 }
 
 // LogPrint sends the text to the logger of level
+//
+// JSON log entries have their own level so it isn't added to them.
 var LogPrint = func(level LogLevel, text string) {
-	text = fmt.Sprintf("%-6s: %s", level, text)
+	if !Config.UseJSONLog {
+		text = fmt.Sprintf("%-6s: %s", level, text)
+	}
 	_ = log.Output(4, text)
 }
 
+// LogValueItem is a keyed value which adds a field to the JSON log
+// entry when it is passed as an argument to a logging function.
+type LogValueItem struct {
+	key    string
+	value  interface{}
+	render bool
+}
+
+// LogValue returns an argument for the logging functions which adds
+// key: value to the JSON log entry.  In text logs it is shown as
+// value.
+func LogValue(key string, value interface{}) LogValueItem {
+	return LogValueItem{key: key, value: value, render: true}
+}
+
+// LogValueHide returns an argument for the logging functions which
+// adds key: value to the JSON log entry.  In text logs it is shown
+// as nothing so use it with a %v that isn't otherwise needed.
+func LogValueHide(key string, value interface{}) LogValueItem {
+	return LogValueItem{key: key, value: value, render: false}
+}
+
+// String returns how the item is shown in text logs
+func (j LogValueItem) String() string {
+	if !j.render {
+		return ""
+	}
+	if s, ok := j.value.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprint(j.value)
+}
+
+// logJSON returns the log entry as a line of JSON
+//
+// The fields are level, time, msg, object and objectType for the
+// object logged about if any, error for the first error in args and
+// any LogValueItem in args.
+func logJSON(level LogLevel, o interface{}, text string, args []interface{}) string {
+	entry := map[string]interface{}{
+		"level": strings.ToLower(level.String()),
+		"time":  time.Now().Format(time.RFC3339Nano),
+		"msg":   strings.TrimSpace(text),
+	}
+	if o != nil {
+		entry["object"] = fmt.Sprintf("%v", o)
+		entry["objectType"] = fmt.Sprintf("%T", o)
+	}
+	for _, arg := range args {
+		switch x := arg.(type) {
+		case LogValueItem:
+			entry[x.key] = x.value
+		case error:
+			if _, found := entry["error"]; !found {
+				entry["error"] = x.Error()
+			}
+		}
+	}
+	out, err := json.Marshal(entry)
+	if err != nil {
+		out, _ = json.Marshal(map[string]interface{}{
+			"level": entry["level"],
+			"time":  entry["time"],
+			"msg":   entry["msg"],
+			"error": fmt.Sprintf("failed to marshal log entry: %v", err),
+		})
+	}
+	return string(out)
+}
+
 // LogPrintf produces a log string from the arguments passed in
 func LogPrintf(level LogLevel, o interface{}, text string, args ...interface{}) {
 	out := fmt.Sprintf(text, args...)
-	if o != nil {
+	if Config.UseJSONLog {
+		out = logJSON(level, o, out, args)
+	} else if o != nil {
 		out = fmt.Sprintf("%v: %s", o, out)
 	}
 	LogPrint(level, out)
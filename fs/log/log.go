@@ -89,6 +89,11 @@ func InitLogging() {
 	}
 	log.SetFlags(flags)
 
+	// JSON output - the entries have their own time
+	if fs.Config.UseJSONLog {
+		log.SetFlags(0)
+	}
+
 	// Log file output
 	if *logFile != "" {
 		f, err := os.OpenFile(*logFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0640)
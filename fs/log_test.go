@@ -1,6 +1,64 @@
 package fs
 
-import "github.com/spf13/pflag"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
 
 // Check it satisfies the interface
 var _ pflag.Value = (*LogLevel)(nil)
+
+func TestLogValue(t *testing.T) {
+	assert.Equal(t, "potato 3", fmt.Sprintf("potato %v", LogValue("size", 3)))
+	assert.Equal(t, "potato 3M", fmt.Sprintf("potato %v", LogValue("size", SizeSuffix(3<<20))))
+	assert.Equal(t, "potato ", fmt.Sprintf("potato %v", LogValueHide("size", 3)))
+}
+
+func TestLogPrintfJSON(t *testing.T) {
+	oldLogPrint, oldUseJSONLog := LogPrint, Config.UseJSONLog
+	defer func() {
+		LogPrint, Config.UseJSONLog = oldLogPrint, oldUseJSONLog
+	}()
+	var gotLevel LogLevel
+	var gotText string
+	LogPrint = func(level LogLevel, text string) {
+		gotLevel, gotText = level, text
+	}
+
+	Config.UseJSONLog = false
+	LogPrintf(LogLevelInfo, "file.txt", "Copied%v", LogValueHide("operation", "copy"))
+	assert.Equal(t, LogLevelInfo, gotLevel)
+	assert.Equal(t, "file.txt: Copied", gotText)
+
+	Config.UseJSONLog = true
+	LogPrintf(LogLevelError, "file.txt", "Failed to copy: %v%v", errors.New("boom"), LogValueHide("operation", "copy"))
+	assert.Equal(t, LogLevelError, gotLevel)
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(gotText), &entry))
+	assert.NotEmpty(t, entry["time"])
+	delete(entry, "time")
+	assert.Equal(t, map[string]interface{}{
+		"level":      "error",
+		"msg":        "Failed to copy: boom",
+		"object":     "file.txt",
+		"objectType": "string",
+		"error":      "boom",
+		"operation":  "copy",
+	}, entry)
+
+	LogPrintf(LogLevelNotice, nil, "Stats\n%v", LogValue("size", 3))
+	entry = nil
+	require.NoError(t, json.Unmarshal([]byte(gotText), &entry))
+	delete(entry, "time")
+	assert.Equal(t, map[string]interface{}{
+		"level": "notice",
+		"msg":   "Stats\n3",
+		"size":  float64(3),
+	}, entry)
+}
@@ -0,0 +1,65 @@
+package fs
+
+import "context"
+
+// Metadata represents Object metadata in a standardised form
+//
+// Keys are lower case strings.  Backends translate these to and from
+// their native form, eg the key "foo" is stored as the header
+// X-Amz-Meta-Foo on S3 and as the xattr "user.foo" on the local
+// filesystem.
+//
+// Some keys have a standard meaning across backends
+//
+//     mtime               - modification time in RFC 3339 format
+//     content-type        - the MIME type of the object
+//     cache-control       - the Cache-Control header
+//     content-disposition - the Content-Disposition header
+//     content-encoding    - the Content-Encoding header
+//     content-language    - the Content-Language header
+//
+// Backends ignore any standard keys they can't store.
+type Metadata map[string]string
+
+// Set k to v on m
+//
+// If m is nil, then it will get made
+func (m *Metadata) Set(k, v string) {
+	if *m == nil {
+		*m = make(Metadata, 1)
+	}
+	(*m)[k] = v
+}
+
+// Merge other into m
+//
+// If m is nil, then it will get made
+func (m *Metadata) Merge(other Metadata) {
+	for k, v := range other {
+		m.Set(k, v)
+	}
+}
+
+// GetMetadata from an ObjectInfo
+//
+// If the object has no metadata then metadata will be nil
+func GetMetadata(ctx context.Context, o ObjectInfo) (metadata Metadata, err error) {
+	do, ok := o.(Metadataer)
+	if !ok {
+		return nil, nil
+	}
+	return do.Metadata(ctx)
+}
+
+// MetadataFromOptions returns the metadata passed in any
+// MetadataOption in options.
+//
+// If there are no MetadataOptions then it returns nil
+func MetadataFromOptions(options []OpenOption) (metadata Metadata) {
+	for _, option := range options {
+		if x, ok := option.(*MetadataOption); ok {
+			metadata.Merge(x.Metadata)
+		}
+	}
+	return metadata
+}
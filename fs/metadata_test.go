@@ -0,0 +1,52 @@
+package fs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadataSetMerge(t *testing.T) {
+	var m Metadata
+	m.Set("a", "1")
+	assert.Equal(t, Metadata{"a": "1"}, m)
+	m.Merge(Metadata{"a": "2", "b": "3"})
+	assert.Equal(t, Metadata{"a": "2", "b": "3"}, m)
+
+	var empty Metadata
+	empty.Merge(nil)
+	assert.Nil(t, empty)
+}
+
+type metadataObject struct {
+	ObjectInfo
+	metadata Metadata
+}
+
+func (o metadataObject) Metadata(ctx context.Context) (Metadata, error) {
+	return o.metadata, nil
+}
+
+func TestGetMetadata(t *testing.T) {
+	ctx := context.Background()
+	m, err := GetMetadata(ctx, metadataObject{metadata: Metadata{"a": "1"}})
+	require.NoError(t, err)
+	assert.Equal(t, Metadata{"a": "1"}, m)
+
+	m, err = GetMetadata(ctx, struct{ ObjectInfo }{})
+	require.NoError(t, err)
+	assert.Nil(t, m)
+}
+
+func TestMetadataFromOptions(t *testing.T) {
+	assert.Nil(t, MetadataFromOptions(nil))
+	assert.Nil(t, MetadataFromOptions([]OpenOption{&HashesOption{}}))
+	got := MetadataFromOptions([]OpenOption{
+		&SeekOption{Offset: 1},
+		&MetadataOption{Metadata: Metadata{"a": "1", "b": "2"}},
+		&MetadataOption{Metadata: Metadata{"b": "3"}},
+	})
+	assert.Equal(t, Metadata{"a": "1", "b": "3"}, got)
+}
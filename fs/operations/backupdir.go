@@ -0,0 +1,256 @@
+package operations
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/accounting"
+	"github.com/pkg/errors"
+)
+
+// joinBackupDir adds the snapshot name to the --backup-dir remote
+func joinBackupDir(backupDir, name string) string {
+	if name == "" || strings.HasSuffix(backupDir, ":") || strings.HasSuffix(backupDir, "/") {
+		return backupDir + name
+	}
+	return backupDir + "/" + name
+}
+
+// BackupDir makes the Fs for --backup-dir for a sync of fsrc to fdst
+// started at when, checking it can be used.
+//
+// If --backup-dir-format is set this is a snapshot subdirectory of
+// --backup-dir named with when in that format.
+func BackupDir(fdst, fsrc fs.Fs, when time.Time) (backupDir fs.Fs, err error) {
+	root, err := fs.NewFs(fs.Config.BackupDir)
+	if err != nil {
+		return nil, errors.Errorf("failed to make fs for --backup-dir %q: %v", fs.Config.BackupDir, err)
+	}
+	if !CanServerSideMove(root) {
+		return nil, errors.New("can't use --backup-dir on a remote which doesn't support server side move or copy")
+	}
+	if !SameConfig(fdst, root) {
+		return nil, errors.New("parameter to --backup-dir has to be on the same remote as destination")
+	}
+	if Overlapping(fdst, root) {
+		return nil, errors.New("destination and parameter to --backup-dir mustn't overlap")
+	}
+	if fsrc != nil && Overlapping(fsrc, root) {
+		return nil, errors.New("source and parameter to --backup-dir mustn't overlap")
+	}
+	if fs.Config.BackupDirFormat == "" {
+		return root, nil
+	}
+	snapshot := joinBackupDir(fs.Config.BackupDir, when.Format(fs.Config.BackupDirFormat))
+	backupDir, err = fs.NewFs(snapshot)
+	if err != nil {
+		return nil, errors.Errorf("failed to make fs for --backup-dir snapshot %q: %v", snapshot, err)
+	}
+	return backupDir, nil
+}
+
+// SuffixName adds --suffix to remote, before the extension if
+// --suffix-keep-extension is set
+func SuffixName(remote string) string {
+	if fs.Config.Suffix == "" {
+		return remote
+	}
+	if fs.Config.SuffixKeepExtension {
+		ext := path.Ext(remote)
+		return remote[:len(remote)-len(ext)] + fs.Config.Suffix + ext
+	}
+	return remote + fs.Config.Suffix
+}
+
+// unSuffixName removes --suffix from a remote made by SuffixName,
+// returning false if it isn't there
+func unSuffixName(remote string) (string, bool) {
+	suffix := fs.Config.Suffix
+	if suffix == "" {
+		return remote, true
+	}
+	if fs.Config.SuffixKeepExtension {
+		ext := path.Ext(remote)
+		base := remote[:len(remote)-len(ext)]
+		if strings.HasSuffix(base, suffix) {
+			return base[:len(base)-len(suffix)] + ext, true
+		}
+	}
+	if strings.HasSuffix(remote, suffix) {
+		return remote[:len(remote)-len(suffix)], true
+	}
+	return "", false
+}
+
+// backupSnapshot is a snapshot directory in --backup-dir
+type backupSnapshot struct {
+	name string    // name of the directory
+	when time.Time // time the sync which made it started
+}
+
+// listBackupSnapshots returns the snapshots in --backup-dir oldest
+// first.  Directories which don't match --backup-dir-format are
+// ignored.
+func listBackupSnapshots(ctx context.Context) (snapshots []backupSnapshot, err error) {
+	if fs.Config.BackupDir == "" || fs.Config.BackupDirFormat == "" {
+		return nil, errors.New("need --backup-dir and --backup-dir-format to find snapshots")
+	}
+	root, err := fs.NewFs(fs.Config.BackupDir)
+	if err != nil {
+		return nil, errors.Errorf("failed to make fs for --backup-dir %q: %v", fs.Config.BackupDir, err)
+	}
+	entries, err := root.List(ctx, "")
+	if err == fs.ErrorDirNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if _, ok := entry.(fs.Directory); !ok {
+			continue
+		}
+		when, err := time.ParseInLocation(fs.Config.BackupDirFormat, entry.Remote(), time.Local)
+		if err != nil {
+			fs.Debugf(entry, "Ignoring directory in --backup-dir: %v", err)
+			continue
+		}
+		snapshots = append(snapshots, backupSnapshot{name: entry.Remote(), when: when})
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].when.Before(snapshots[j].when)
+	})
+	return snapshots, nil
+}
+
+// PruneBackupDir removes the snapshots in --backup-dir older than
+// --backup-dir-max-age before now.  It does nothing unless both
+// --backup-dir-format and --backup-dir-max-age are set.
+func PruneBackupDir(ctx context.Context, now time.Time) error {
+	if fs.Config.BackupDirFormat == "" || !fs.Config.BackupDirMaxAge.IsSet() {
+		return nil
+	}
+	snapshots, err := listBackupSnapshots(ctx)
+	if err != nil {
+		return err
+	}
+	cutoff := now.Add(-time.Duration(fs.Config.BackupDirMaxAge))
+	for _, snapshot := range snapshots {
+		if !snapshot.when.Before(cutoff) {
+			break
+		}
+		f, err := fs.NewFs(joinBackupDir(fs.Config.BackupDir, snapshot.name))
+		if err != nil {
+			return err
+		}
+		fs.Infof(f, "Removing --backup-dir snapshot older than %v", fs.Config.BackupDirMaxAge)
+		err = Purge(ctx, f, "")
+		if err != nil {
+			return errors.Wrapf(err, "failed to remove --backup-dir snapshot %q", snapshot.name)
+		}
+	}
+	return nil
+}
+
+// Restore copies fsrc, the destination of syncs made with
+// --backup-dir-format, into fdst as it was at the time given.
+//
+// A file is restored from the earliest snapshot made at or after at
+// which has it, otherwise the current version in fsrc is used.  This
+// means files created after at are restored too as the snapshots
+// don't record when files were created.
+func Restore(ctx context.Context, fdst, fsrc fs.Fs, at time.Time) error {
+	if Overlapping(fdst, fsrc) {
+		return errors.New("can't restore into a directory which overlaps the source")
+	}
+	snapshots, err := listBackupSnapshots(ctx)
+	if err != nil {
+		return err
+	}
+	var (
+		mu    sync.Mutex
+		files = make(map[string]fs.Object)
+	)
+	err = ListFn(ctx, fsrc, func(o fs.Object) {
+		mu.Lock()
+		files[o.Remote()] = o
+		mu.Unlock()
+	})
+	if err != nil {
+		return err
+	}
+	// Go newest first so the earliest snapshot after at wins
+	for i := len(snapshots) - 1; i >= 0 && !snapshots[i].when.Before(at); i-- {
+		f, err := fs.NewFs(joinBackupDir(fs.Config.BackupDir, snapshots[i].name))
+		if err != nil {
+			return err
+		}
+		if Overlapping(fdst, f) {
+			return errors.New("can't restore into --backup-dir")
+		}
+		err = ListFn(ctx, f, func(o fs.Object) {
+			remote, ok := unSuffixName(o.Remote())
+			if !ok {
+				fs.Debugf(o, "Ignoring file without --suffix")
+				return
+			}
+			mu.Lock()
+			files[remote] = o
+			mu.Unlock()
+		})
+		if err != nil {
+			return err
+		}
+	}
+	remotes := make(chan string, fs.Config.Transfers)
+	var (
+		wg      sync.WaitGroup
+		errorMu sync.Mutex
+		lastErr error
+	)
+	wg.Add(fs.Config.Transfers)
+	for i := 0; i < fs.Config.Transfers; i++ {
+		go func() {
+			defer wg.Done()
+			for remote := range remotes {
+				err := restoreFile(ctx, fdst, remote, files[remote])
+				if err != nil {
+					fs.CountError(ctx, err)
+					fs.Errorf(remote, "Failed to restore: %v", err)
+					errorMu.Lock()
+					lastErr = err
+					errorMu.Unlock()
+				}
+			}
+		}()
+	}
+	for remote := range files {
+		remotes <- remote
+	}
+	close(remotes)
+	wg.Wait()
+	return lastErr
+}
+
+// restoreFile copies src to remote in fdst if needed
+func restoreFile(ctx context.Context, fdst fs.Fs, remote string, src fs.Object) (err error) {
+	accounting.GetStats(ctx).Checking(remote)
+	dst, err := fdst.NewObject(ctx, remote)
+	accounting.GetStats(ctx).DoneChecking(remote)
+	if err == fs.ErrorObjectNotFound {
+		dst = nil
+	} else if err != nil {
+		return err
+	}
+	if !NeedTransfer(ctx, dst, src) {
+		return nil
+	}
+	accounting.GetStats(ctx).Transferring(remote)
+	_, err = Copy(ctx, fdst, dst, remote, src)
+	accounting.GetStats(ctx).DoneTransferring(remote, err == nil)
+	return err
+}
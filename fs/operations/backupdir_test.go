@@ -0,0 +1,66 @@
+package operations_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/operations"
+	"github.com/ncw/rclone/fstest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestore(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	fs.Config.BackupDir = r.FremoteName + "/old"
+	fs.Config.BackupDirFormat = "2006-01-02"
+	defer func() {
+		fs.Config.BackupDir = ""
+		fs.Config.BackupDirFormat = ""
+	}()
+
+	// a was replaced on the 2nd and 3rd, b never changed and c
+	// was deleted on the 2nd
+	a3 := r.WriteObject("cur/a", "a3", t3)
+	b1 := r.WriteObject("cur/b", "b1", t1)
+	a1 := r.WriteObject("old/2018-01-02/a", "a1", t1)
+	c1 := r.WriteObject("old/2018-01-02/c", "c1", t1)
+	a2 := r.WriteObject("old/2018-01-03/a", "a2", t2)
+	r.WriteObject("old/not a snapshot/a", "potato", t1)
+	fstest.CheckListingWithPrecision(t, r.Fremote, []fstest.Item{a3, b1, a1, c1, a2, fstest.NewItem("old/not a snapshot/a", "potato", t1)}, nil, fs.GetModifyWindow(r.Fremote))
+
+	fcur, err := fs.NewFs(r.FremoteName + "/cur")
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		at   string
+		want []fstest.Item
+	}{
+		{"2018-01-01", []fstest.Item{a1, b1, c1}},
+		{"2018-01-02", []fstest.Item{a1, b1, c1}},
+		{"2018-01-03", []fstest.Item{a2, b1}},
+		{"2018-01-04", []fstest.Item{a3, b1}},
+	} {
+		at, err := time.ParseInLocation(fs.Config.BackupDirFormat, test.at, time.Local)
+		require.NoError(t, err)
+		fdst, err := fs.NewFs(r.FremoteName + "/restore-" + test.at)
+		require.NoError(t, err)
+		err = operations.Restore(ctx, fdst, fcur, at)
+		require.NoError(t, err)
+		var want []fstest.Item
+		for _, item := range test.want {
+			item.Path = item.Path[len(item.Path)-1:]
+			want = append(want, item)
+		}
+		fstest.CheckItems(t, fdst, want...)
+	}
+
+	// Can't restore into the source
+	fsub, err := fs.NewFs(r.FremoteName + "/cur/sub")
+	require.NoError(t, err)
+	require.Error(t, operations.Restore(ctx, fsub, fcur, time.Now()))
+}
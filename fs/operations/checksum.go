@@ -0,0 +1,264 @@
+package operations
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/accounting"
+	"github.com/ncw/rclone/fs/filter"
+	"github.com/ncw/rclone/fs/hash"
+	"github.com/pkg/errors"
+)
+
+// HashSums is the contents of a SUM file as a map of file name to
+// hash
+type HashSums map[string]string
+
+// matches a line of md5sum or sha1sum output in text or binary mode
+var sumLineRe = regexp.MustCompile(`^([0-9a-fA-F]+) [ *](.+)$`)
+
+// ParseSums reads a SUM file in the format produced by md5sum,
+// sha1sum and rclone hashsum.
+//
+// Blank lines and lines starting with # or ; are ignored, as are the
+// UNSUPPORTED and ERROR lines from rclone hashsum.  It returns the
+// hash type the sums were made with which is guessed from their
+// width if ht is hash.None.
+func ParseSums(in io.Reader, ht hash.Type) (sums HashSums, _ hash.Type, err error) {
+	sums = make(HashSums)
+	scanner := bufio.NewScanner(in)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed[0] == '#' || trimmed[0] == ';' {
+			continue
+		}
+		match := sumLineRe.FindStringSubmatch(line)
+		if match == nil {
+			if first := strings.Fields(trimmed)[0]; first == "UNSUPPORTED" || first == "ERROR" || line[0] == ' ' {
+				fs.Debugf(nil, "Ignoring line %d of SUM file without a hash: %q", lineNumber, line)
+				continue
+			}
+			return nil, ht, errors.Errorf("line %d of SUM file is badly formatted: %q", lineNumber, line)
+		}
+		sum, remote := strings.ToLower(match[1]), strings.TrimPrefix(match[2], "./")
+		if ht == hash.None {
+			ht, err = guessHashType(sum)
+			if err != nil {
+				return nil, ht, errors.Wrapf(err, "line %d of SUM file", lineNumber)
+			}
+		}
+		if len(sum) != hash.Width[ht] {
+			return nil, ht, errors.Errorf("line %d of SUM file: hash %q is the wrong length for %v", lineNumber, sum, ht)
+		}
+		if _, found := sums[remote]; found {
+			fs.Logf(remote, "Duplicate entry in SUM file - using the last one")
+		}
+		sums[remote] = sum
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, ht, errors.Wrap(err, "failed to read SUM file")
+	}
+	return sums, ht, nil
+}
+
+// guessHashType works out whether sum is from md5sum or sha1sum
+func guessHashType(sum string) (hash.Type, error) {
+	for _, ht := range []hash.Type{hash.MD5, hash.SHA1} {
+		if len(sum) == hash.Width[ht] {
+			return ht, nil
+		}
+	}
+	return hash.None, errors.Errorf("can't work out the hash type of %q - use --hash", sum)
+}
+
+// ParseSumFile reads the SUM file sumFile from f with ParseSums
+func ParseSumFile(ctx context.Context, f fs.Fs, sumFile string, ht hash.Type) (sums HashSums, _ hash.Type, err error) {
+	o, err := f.NewObject(ctx, sumFile)
+	if err != nil {
+		return nil, ht, errors.Wrap(err, "failed to find SUM file")
+	}
+	in, err := o.Open(ctx)
+	if err != nil {
+		return nil, ht, errors.Wrap(err, "failed to open SUM file")
+	}
+	defer fs.CheckClose(in, &err)
+	return ParseSums(in, ht)
+}
+
+// sumRemote returns the name of sumFile in fsum relative to f or ""
+// if it isn't inside f
+func sumRemote(f, fsum fs.Fs, sumFile string) string {
+	if !SameConfig(f, fsum) {
+		return ""
+	}
+	root := strings.Trim(f.Root(), "/")
+	sumPath := path.Join(strings.Trim(fsum.Root(), "/"), sumFile)
+	if root == "" {
+		return sumPath
+	}
+	if strings.HasPrefix(sumPath, root+"/") {
+		return sumPath[len(root)+1:]
+	}
+	return ""
+}
+
+// CheckSum checks the files in f against the SUM file sumFile in
+// fsum in the format produced by md5sum or sha1sum.  If ht is
+// hash.None the hash type is guessed from the SUM file.
+//
+// Files in f which don't support the hash, or all of them if
+// download is set, are read to calculate it.
+//
+// It reports files which are missing from f or have different
+// hashes and, unless oneway is set, files in f which aren't in the
+// SUM file.  If ctx was made with WithReports the SUM file is
+// treated as the source for them.
+func CheckSum(ctx context.Context, f, fsum fs.Fs, sumFile string, ht hash.Type, oneway, download bool) error {
+	reports := GetReports(ctx)
+	sums, ht, err := ParseSumFile(ctx, fsum, sumFile, ht)
+	if err != nil {
+		return err
+	}
+	if !download && !f.Hashes().Contains(ht) {
+		fs.Infof(f, "%v isn't supported so downloading files to check them", ht)
+		download = true
+	}
+	ignore := sumRemote(f, fsum, sumFile)
+
+	var (
+		mu              sync.Mutex
+		objects         []fs.Object
+		seen            = make(map[string]struct{}, len(sums))
+		differences     int32
+		noHashes        int32
+		sumFilesMissing int32
+		dstFilesMissing int32
+	)
+	err = ListFn(ctx, f, func(o fs.Object) {
+		remote := o.Remote()
+		if remote == ignore {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if _, found := sums[remote]; !found {
+			if oneway {
+				return
+			}
+			err := errors.New("File not in SUM file")
+			fs.Errorf(o, "%v", err)
+			fs.CountError(ctx, err)
+			differences++
+			sumFilesMissing++
+			reports.Report(ReportMissingOnSrc, remote)
+			return
+		}
+		seen[remote] = struct{}{}
+		objects = append(objects, o)
+	})
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for remote := range sums {
+		if _, found := seen[remote]; !found && remote != ignore && filter.Active.IncludeRemote(remote) {
+			missing = append(missing, remote)
+		}
+	}
+	sort.Strings(missing)
+	for _, remote := range missing {
+		err := errors.Errorf("File not in %v", f)
+		fs.Errorf(remote, "%v", err)
+		fs.CountError(ctx, err)
+		differences++
+		dstFilesMissing++
+		reports.Report(ReportMissingOnDst, remote)
+	}
+
+	fs.Infof(f, "Waiting for checks to finish")
+	in := make(chan fs.Object, fs.Config.Checkers)
+	var wg sync.WaitGroup
+	wg.Add(fs.Config.Checkers)
+	for i := 0; i < fs.Config.Checkers; i++ {
+		go func() {
+			defer wg.Done()
+			for o := range in {
+				differ, noHash, err := checkSumObject(ctx, ht, download, o, sums[o.Remote()])
+				if differ {
+					atomic.AddInt32(&differences, 1)
+				}
+				if noHash {
+					atomic.AddInt32(&noHashes, 1)
+				}
+				switch {
+				case err != nil:
+					reports.Report(ReportError, o.Remote())
+				case differ:
+					reports.Report(ReportDiffer, o.Remote())
+				default:
+					reports.Report(ReportMatch, o.Remote())
+				}
+			}
+		}()
+	}
+	for _, o := range objects {
+		in <- o
+	}
+	close(in)
+	wg.Wait()
+
+	if dstFilesMissing > 0 {
+		fs.Logf(f, "%d files missing", dstFilesMissing)
+	}
+	if sumFilesMissing > 0 {
+		fs.Logf(fsum, "%d files missing from %q", sumFilesMissing, sumFile)
+	}
+	fs.Logf(f, "%d differences found", differences)
+	if noHashes > 0 {
+		fs.Logf(f, "%d hashes could not be checked", noHashes)
+	}
+	if differences > 0 {
+		return errors.Errorf("%d differences found", differences)
+	}
+	return nil
+}
+
+// checkSumObject checks the hash of o against sum
+//
+// it returns true if differences were found
+// it also returns whether it couldn't be hashed
+// and any error reading the hash which has already been logged and counted
+func checkSumObject(ctx context.Context, ht hash.Type, download bool, o fs.Object, sum string) (differ bool, noHash bool, err error) {
+	accounting.GetStats(ctx).Checking(o.Remote())
+	defer accounting.GetStats(ctx).DoneChecking(o.Remote())
+	got, err := objectHash(ctx, ht, download, o)
+	if err != nil {
+		fs.Errorf(o, "Failed to read %v: %v", ht, err)
+		fs.CountError(ctx, err)
+		return true, false, err
+	}
+	if got == "" {
+		fs.Debugf(o, "%v not available", ht)
+		return false, true, nil
+	}
+	if !strings.EqualFold(got, sum) {
+		err = errors.Errorf("%v differ", ht)
+		fs.Errorf(o, "%v", err)
+		fs.CountError(ctx, err)
+		return true, false, nil
+	}
+	fs.Debugf(o, "OK")
+	return false, false, nil
+}
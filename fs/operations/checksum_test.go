@@ -0,0 +1,133 @@
+package operations_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/accounting"
+	"github.com/ncw/rclone/fs/hash"
+	"github.com/ncw/rclone/fs/operations"
+	"github.com/ncw/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSums(t *testing.T) {
+	for _, test := range []struct {
+		in      string
+		ht      hash.Type
+		want    operations.HashSums
+		wantHt  hash.Type
+		wantErr string
+	}{
+		{
+			in:     "",
+			want:   operations.HashSums{},
+			wantHt: hash.None,
+		},
+		{
+			in:     "d41d8cd98f00b204e9800998ecf8427e  empty space\nD6548B156EA68A4E003E786DF99EEE76 *dir/potato2\r\n\n# comment\n",
+			want:   operations.HashSums{"empty space": "d41d8cd98f00b204e9800998ecf8427e", "dir/potato2": "d6548b156ea68a4e003e786df99eee76"},
+			wantHt: hash.MD5,
+		},
+		{
+			in:     "da39a3ee5e6b4b0d3255bfef95601890afd80709  ./empty space\n                             UNSUPPORTED  potato2\n                                          potato3\n",
+			want:   operations.HashSums{"empty space": "da39a3ee5e6b4b0d3255bfef95601890afd80709"},
+			wantHt: hash.SHA1,
+		},
+		{
+			in:     "da39a3ee5e6b4b0d3255bfef95601890afd80709  file\n",
+			ht:     hash.QuickXorHash,
+			want:   operations.HashSums{"file": "da39a3ee5e6b4b0d3255bfef95601890afd80709"},
+			wantHt: hash.QuickXorHash,
+		},
+		{
+			in:      "d41d8cd98f00b204e9800998ecf8427e  file1\nda39a3ee5e6b4b0d3255bfef95601890afd80709  file2\n",
+			wantErr: "line 2 of SUM file: hash \"da39a3ee5e6b4b0d3255bfef95601890afd80709\" is the wrong length for MD5",
+		},
+		{
+			in:      "d41d8cd98f  file1\n",
+			wantErr: "line 1 of SUM file: can't work out the hash type of \"d41d8cd98f\" - use --hash",
+		},
+		{
+			in:      "potato\n",
+			wantErr: "line 1 of SUM file is badly formatted: \"potato\"",
+		},
+	} {
+		got, gotHt, err := operations.ParseSums(strings.NewReader(test.in), test.ht)
+		if test.wantErr != "" {
+			require.Error(t, err, test.in)
+			assert.Equal(t, test.wantErr, err.Error(), test.in)
+			continue
+		}
+		require.NoError(t, err, test.in)
+		assert.Equal(t, test.want, got, test.in)
+		assert.Equal(t, test.wantHt, gotHt, test.in)
+	}
+}
+
+func TestHashListerDownload(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	file1 := r.WriteObject("potato2", "------------------------------------------------------------", t1)
+	fstest.CheckItems(t, r.Fremote, file1)
+
+	var buf bytes.Buffer
+	err := operations.HashLister(ctx, hash.MD5, true, r.Fremote, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, "d6548b156ea68a4e003e786df99eee76  potato2\n", buf.String())
+}
+
+func testCheckSum(t *testing.T, download bool) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	check := func(i int, sums string, wantErrors int64, oneway bool) {
+		fs.Debugf(r.Fremote, "%d: Starting checksum test", i)
+		r.WriteFile("SUMS", sums, t1)
+		oldErrors := accounting.Stats.GetErrors()
+		err := operations.CheckSum(ctx, r.Fremote, r.Flocal, "SUMS", hash.None, oneway, download)
+		gotErrors := accounting.Stats.GetErrors() - oldErrors
+		if wantErrors == 0 && err != nil {
+			t.Errorf("%d: Got error when not expecting one: %v", i, err)
+		}
+		if wantErrors != 0 && err == nil {
+			t.Errorf("%d: No error when expecting one", i)
+		}
+		if wantErrors != gotErrors {
+			t.Errorf("%d: Expecting %d errors but got %d", i, wantErrors, gotErrors)
+		}
+		fs.Debugf(r.Fremote, "%d: Ending checksum test", i)
+	}
+
+	file1 := r.WriteObject("potato2", "------------------------------------------------------------", t1)
+	file2 := r.WriteObject("empty space", "", t2)
+	fstest.CheckItems(t, r.Fremote, file1, file2)
+
+	const (
+		md5Potato = "d6548b156ea68a4e003e786df99eee76  potato2\n"
+		md5Empty  = "d41d8cd98f00b204e9800998ecf8427e  empty space\n"
+		sha1Both  = "9dc7f7d3279715991a22853f5981df582b7f9f6d  potato2\nda39a3ee5e6b4b0d3255bfef95601890afd80709  empty space\n"
+	)
+
+	check(1, md5Potato+md5Empty, 0, false)
+	check(2, sha1Both, 0, false)
+	check(3, md5Potato, 1, false)
+	check(4, md5Potato, 0, true)
+	check(5, md5Potato+md5Empty+"d41d8cd98f00b204e9800998ecf8427e  missing\n", 1, false)
+	check(6, "d41d8cd98f00b204e9800998ecf8427e  potato2\n"+md5Empty, 1, false)
+	check(7, "d41d8cd98f00b204e9800998ecf8427e  potato2\n", 2, false)
+}
+
+func TestCheckSum(t *testing.T) {
+	testCheckSum(t, false)
+}
+
+func TestCheckSumDownload(t *testing.T) {
+	testCheckSum(t, true)
+}
@@ -1,6 +1,7 @@
 package operations_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -32,6 +33,7 @@ func skipIfNoHash(t *testing.T, f fs.Fs) {
 }
 
 func TestDeduplicateInteractive(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	skipIfCantDedupe(t, r.Fremote)
@@ -42,13 +44,14 @@ func TestDeduplicateInteractive(t *testing.T) {
 	file3 := r.WriteUncheckedObject("one", "This is one", t1)
 	r.CheckWithDuplicates(t, file1, file2, file3)
 
-	err := operations.Deduplicate(r.Fremote, operations.DeduplicateInteractive)
+	err := operations.Deduplicate(ctx, r.Fremote, operations.DeduplicateInteractive)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Fremote, file1)
 }
 
 func TestDeduplicateSkip(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	skipIfCantDedupe(t, r.Fremote)
@@ -64,13 +67,14 @@ func TestDeduplicateSkip(t *testing.T) {
 	files = append(files, file3)
 	r.CheckWithDuplicates(t, files...)
 
-	err := operations.Deduplicate(r.Fremote, operations.DeduplicateSkip)
+	err := operations.Deduplicate(ctx, r.Fremote, operations.DeduplicateSkip)
 	require.NoError(t, err)
 
 	r.CheckWithDuplicates(t, file1, file3)
 }
 
 func TestDeduplicateFirst(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	skipIfCantDedupe(t, r.Fremote)
@@ -80,13 +84,13 @@ func TestDeduplicateFirst(t *testing.T) {
 	file3 := r.WriteUncheckedObject("one", "This is one BB", t1)
 	r.CheckWithDuplicates(t, file1, file2, file3)
 
-	err := operations.Deduplicate(r.Fremote, operations.DeduplicateFirst)
+	err := operations.Deduplicate(ctx, r.Fremote, operations.DeduplicateFirst)
 	require.NoError(t, err)
 
 	// list until we get one object
 	var objects, size int64
 	for try := 1; try <= *fstest.ListRetries; try++ {
-		objects, size, err = operations.Count(r.Fremote)
+		objects, size, err = operations.Count(ctx, r.Fremote)
 		require.NoError(t, err)
 		if objects == 1 {
 			break
@@ -100,6 +104,7 @@ func TestDeduplicateFirst(t *testing.T) {
 }
 
 func TestDeduplicateNewest(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	skipIfCantDedupe(t, r.Fremote)
@@ -109,13 +114,14 @@ func TestDeduplicateNewest(t *testing.T) {
 	file3 := r.WriteUncheckedObject("one", "This is another one", t3)
 	r.CheckWithDuplicates(t, file1, file2, file3)
 
-	err := operations.Deduplicate(r.Fremote, operations.DeduplicateNewest)
+	err := operations.Deduplicate(ctx, r.Fremote, operations.DeduplicateNewest)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Fremote, file3)
 }
 
 func TestDeduplicateOldest(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	skipIfCantDedupe(t, r.Fremote)
@@ -125,13 +131,14 @@ func TestDeduplicateOldest(t *testing.T) {
 	file3 := r.WriteUncheckedObject("one", "This is another one", t3)
 	r.CheckWithDuplicates(t, file1, file2, file3)
 
-	err := operations.Deduplicate(r.Fremote, operations.DeduplicateOldest)
+	err := operations.Deduplicate(ctx, r.Fremote, operations.DeduplicateOldest)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Fremote, file1)
 }
 
 func TestDeduplicateLargest(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	skipIfCantDedupe(t, r.Fremote)
@@ -141,13 +148,14 @@ func TestDeduplicateLargest(t *testing.T) {
 	file3 := r.WriteUncheckedObject("one", "This is another one", t3)
 	r.CheckWithDuplicates(t, file1, file2, file3)
 
-	err := operations.Deduplicate(r.Fremote, operations.DeduplicateLargest)
+	err := operations.Deduplicate(ctx, r.Fremote, operations.DeduplicateLargest)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Fremote, file3)
 }
 
 func TestDeduplicateRename(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	skipIfCantDedupe(t, r.Fremote)
@@ -158,10 +166,10 @@ func TestDeduplicateRename(t *testing.T) {
 	file4 := r.WriteUncheckedObject("one-1.txt", "This is not a duplicate", t1)
 	r.CheckWithDuplicates(t, file1, file2, file3, file4)
 
-	err := operations.Deduplicate(r.Fremote, operations.DeduplicateRename)
+	err := operations.Deduplicate(ctx, r.Fremote, operations.DeduplicateRename)
 	require.NoError(t, err)
 
-	require.NoError(t, walk.Walk(r.Fremote, "", true, -1, func(dirPath string, entries fs.DirEntries, err error) error {
+	require.NoError(t, walk.Walk(ctx, r.Fremote, "", true, -1, func(dirPath string, entries fs.DirEntries, err error) error {
 		if err != nil {
 			return err
 		}
@@ -191,6 +199,7 @@ func TestDeduplicateRename(t *testing.T) {
 // This should really be a unit test, but the test framework there
 // doesn't have enough tools to make it easy
 func TestMergeDirs(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
@@ -203,19 +212,19 @@ func TestMergeDirs(t *testing.T) {
 	file2 := r.WriteObject("dupe2/two.txt", "This is one too", t2)
 	file3 := r.WriteObject("dupe3/three.txt", "This is another one", t3)
 
-	objs, dirs, err := walk.GetAll(r.Fremote, "", true, 1)
+	objs, dirs, err := walk.GetAll(ctx, r.Fremote, "", true, 1)
 	require.NoError(t, err)
 	assert.Equal(t, 3, len(dirs))
 	assert.Equal(t, 0, len(objs))
 
-	err = mergeDirs(dirs)
+	err = mergeDirs(ctx, dirs)
 	require.NoError(t, err)
 
 	file2.Path = "dupe1/two.txt"
 	file3.Path = "dupe1/three.txt"
 	fstest.CheckItems(t, r.Fremote, file1, file2, file3)
 
-	objs, dirs, err = walk.GetAll(r.Fremote, "", true, 1)
+	objs, dirs, err = walk.GetAll(ctx, r.Fremote, "", true, 1)
 	require.NoError(t, err)
 	assert.Equal(t, 1, len(dirs))
 	assert.Equal(t, 0, len(objs))
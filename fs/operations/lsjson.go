@@ -1,6 +1,7 @@
 package operations
 
 import (
+	"context"
 	"path"
 	"time"
 
@@ -46,7 +47,7 @@ type ListJSONOpt struct {
 }
 
 // ListJSON lists fsrc using the options in opt calling callback for each item
-func ListJSON(fsrc fs.Fs, remote string, opt *ListJSONOpt, callback func(*ListJSONItem) error) error {
+func ListJSON(ctx context.Context, fsrc fs.Fs, remote string, opt *ListJSONOpt, callback func(*ListJSONItem) error) error {
 	var cipher crypt.Cipher
 	if opt.ShowEncrypted {
 		fsInfo, _, _, config, err := fs.ConfigFs(fsrc.Name() + ":" + fsrc.Root())
@@ -61,9 +62,9 @@ func ListJSON(fsrc fs.Fs, remote string, opt *ListJSONOpt, callback func(*ListJS
 			return errors.Wrap(err, "ListJSON failed to make new crypt remote")
 		}
 	}
-	err := walk.Walk(fsrc, remote, false, ConfigMaxDepth(opt.Recurse), func(dirPath string, entries fs.DirEntries, err error) error {
+	err := walk.Walk(ctx, fsrc, remote, false, ConfigMaxDepth(opt.Recurse), func(dirPath string, entries fs.DirEntries, err error) error {
 		if err != nil {
-			fs.CountError(err)
+			fs.CountError(ctx, err)
 			fs.Errorf(dirPath, "error listing: %v", err)
 			return nil
 		}
@@ -115,7 +116,7 @@ func ListJSON(fsrc fs.Fs, remote string, opt *ListJSONOpt, callback func(*ListJS
 				if opt.ShowHash {
 					item.Hashes = make(map[string]string)
 					for _, hashType := range x.Fs().Hashes().Array() {
-						hash, err := x.Hash(hashType)
+						hash, err := x.Hash(ctx, hashType)
 						if err != nil {
 							fs.Errorf(x, "Failed to read hash: %v", err)
 						} else if hash != "" {
@@ -0,0 +1,237 @@
+package operations
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/accounting"
+	"github.com/ncw/rclone/fs/fserrors"
+	"github.com/pkg/errors"
+)
+
+const (
+	multithreadChunkSize     = 64 << 10 // streams are a multiple of this size
+	multithreadChunkSizeMask = multithreadChunkSize - 1
+	multithreadBufferSize    = 32 * 1024
+)
+
+// state for a multi-thread copy
+type multiThreadCopyState struct {
+	ctx      context.Context
+	cancel   func()
+	partSize int64
+	size     int64
+	wc       fs.WriterAtCloser
+	src      fs.Object
+	acc      *accounting.Account
+	streams  int
+
+	mu  sync.Mutex // protects err
+	err error      // first error from a stream
+}
+
+// multiThreadStreams returns the number of streams to use to copy src
+// or 0 if it shouldn't be copied with multiple streams into f
+func multiThreadStreams(f fs.Fs, src fs.Object) int {
+	if f.Features().OpenWriterAt == nil || fs.Config.MultiThreadStreams < 2 {
+		return 0
+	}
+	size := src.Size()
+	cutoff := int64(fs.Config.MultiThreadCutoff)
+	if size <= 0 || size < cutoff {
+		return 0
+	}
+	// Number of streams proportional to size rounded up
+	streams := int64(fs.Config.MultiThreadStreams)
+	if cutoff > 0 {
+		if n := (size + cutoff - 1) / cutoff; n < streams {
+			streams = n
+		}
+	}
+	if streams < 2 {
+		streams = 2
+	}
+	return int(streams)
+}
+
+// multiThreadCopy copies src to remote in f by reading it in streams
+// concurrent ranges and writing each in place with OpenWriterAt
+func multiThreadCopy(ctx context.Context, f fs.Fs, remote string, src fs.Object, streams int) (newDst fs.Object, err error) {
+	openWriterAt := f.Features().OpenWriterAt
+	if openWriterAt == nil {
+		return nil, errors.New("multi-thread copy: OpenWriterAt not supported")
+	}
+	if src.Size() <= 0 {
+		return nil, errors.New("multi-thread copy: can't copy unknown or zero sized file")
+	}
+
+	mc := &multiThreadCopyState{
+		size:    src.Size(),
+		src:     src,
+		streams: streams,
+	}
+	mc.ctx, mc.cancel = context.WithCancel(ctx)
+	defer mc.cancel()
+	mc.calculateChunks()
+
+	// Make accounting
+	mc.acc = accounting.GetStats(ctx).NewAccount(nil, src)
+	defer fs.CheckClose(mc.acc, &err)
+
+	// create write file handle
+	mc.wc, err = openWriterAt(mc.ctx, remote, mc.size)
+	if err != nil {
+		return nil, errors.Wrap(err, "multi-thread copy: failed to open destination")
+	}
+
+	fs.Debugf(src, "Starting multi-thread copy with %d parts of size %v", mc.streams, fs.SizeSuffix(mc.partSize))
+	var wg sync.WaitGroup
+	wg.Add(mc.streams)
+	for stream := 0; stream < mc.streams; stream++ {
+		go func(stream int) {
+			defer wg.Done()
+			mc.setError(mc.copyStream(stream))
+		}(stream)
+	}
+	wg.Wait()
+	err = mc.err
+	closeErr := mc.wc.Close()
+	if err == nil && closeErr != nil {
+		err = errors.Wrap(closeErr, "multi-thread copy: failed to close destination")
+	}
+	if err != nil {
+		// Don't leave a partially written file behind
+		if o, findErr := f.NewObject(ctx, remote); findErr == nil {
+			removeFailedCopy(ctx, o)
+		}
+		return nil, err
+	}
+
+	obj, err := f.NewObject(ctx, remote)
+	if err != nil {
+		return nil, errors.Wrap(err, "multi-thread copy: failed to find object after copy")
+	}
+
+	err = obj.SetModTime(ctx, src.ModTime())
+	switch err {
+	case nil, fs.ErrorCantSetModTime, fs.ErrorCantSetModTimeWithoutDelete:
+	default:
+		return nil, errors.Wrap(err, "multi-thread copy: failed to set modification time")
+	}
+
+	fs.Debugf(src, "Finished multi-thread copy with %d parts of size %v", mc.streams, fs.SizeSuffix(mc.partSize))
+	return obj, nil
+}
+
+// setError records the first error from a stream and stops the others
+func (mc *multiThreadCopyState) setError(err error) {
+	if err == nil {
+		return
+	}
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	if mc.err == nil {
+		mc.err = err
+		mc.cancel()
+	}
+}
+
+// copyStream copies a single stream into place, reopening the source
+// at the current offset on retryable errors
+func (mc *multiThreadCopyState) copyStream(stream int) (err error) {
+	start := int64(stream) * mc.partSize
+	if start >= mc.size {
+		return nil
+	}
+	end := start + mc.partSize
+	if end > mc.size {
+		end = mc.size
+	}
+
+	fs.Debugf(mc.src, "multi-thread copy: stream %d/%d (%d-%d) size %v starting", stream+1, mc.streams, start, end, fs.SizeSuffix(end-start))
+
+	buf := make([]byte, multithreadBufferSize)
+	offset := start
+	for tries := 1; offset < end; tries++ {
+		var n int64
+		n, err = mc.copyRange(buf, offset, end)
+		offset += n
+		if err == nil {
+			break
+		}
+		if mc.ctx.Err() != nil || tries >= fs.Config.LowLevelRetries || !(fserrors.IsRetryError(err) || fserrors.ShouldRetry(err)) {
+			fs.Debugf(mc.src, "multi-thread copy: stream %d/%d failed: %v", stream+1, mc.streams, err)
+			return err
+		}
+		fs.Debugf(mc.src, "multi-thread copy: stream %d/%d received error: %v - low level retry %d/%d", stream+1, mc.streams, err, tries, fs.Config.LowLevelRetries)
+	}
+
+	fs.Debugf(mc.src, "multi-thread copy: stream %d/%d (%d-%d) size %v finished", stream+1, mc.streams, start, end, fs.SizeSuffix(end-start))
+	return nil
+}
+
+// copyRange copies the source from offset to end into place
+// returning the number of bytes written
+func (mc *multiThreadCopyState) copyRange(buf []byte, offset, end int64) (written int64, err error) {
+	in, err := mc.src.Open(mc.ctx, &fs.RangeOption{Start: offset, End: end - 1})
+	if err != nil {
+		return 0, errors.Wrap(err, "multi-thread copy: failed to open source")
+	}
+	defer fs.CheckClose(in, &err)
+
+	for offset < end {
+		// Check if context cancelled and exit if so
+		if mc.ctx.Err() != nil {
+			return written, mc.ctx.Err()
+		}
+		chunk := buf
+		if remaining := end - offset; remaining < int64(len(chunk)) {
+			chunk = chunk[:remaining]
+		}
+		nr, er := in.Read(chunk)
+		if nr > 0 {
+			err = mc.acc.AccountRead(nr)
+			if err != nil {
+				return written, err
+			}
+			nw, ew := mc.wc.WriteAt(chunk[:nr], offset)
+			offset += int64(nw)
+			written += int64(nw)
+			if ew != nil {
+				return written, errors.Wrap(ew, "multi-thread copy: write failed")
+			}
+			if nw != nr {
+				return written, errors.Wrap(io.ErrShortWrite, "multi-thread copy")
+			}
+		}
+		if er == io.EOF {
+			break
+		} else if er != nil {
+			return written, errors.Wrap(er, "multi-thread copy: read failed")
+		}
+	}
+	if offset != end {
+		return written, errors.Errorf("multi-thread copy: source ended %d bytes early", end-offset)
+	}
+	return written, nil
+}
+
+// calculateChunks works out the part size and the number of streams
+// actually needed
+func (mc *multiThreadCopyState) calculateChunks() {
+	partSize := mc.size / int64(mc.streams)
+	// Round partition size up so partSize * streams >= size
+	if (mc.size % int64(mc.streams)) != 0 {
+		partSize++
+	}
+	// round partSize up to nearest multithreadChunkSize boundary
+	mc.partSize = (partSize + multithreadChunkSizeMask) &^ multithreadChunkSizeMask
+	// recalculate number of streams
+	mc.streams = int(mc.size / mc.partSize)
+	// round streams up so partSize * streams >= size
+	if (mc.size % mc.partSize) != 0 {
+		mc.streams++
+	}
+}
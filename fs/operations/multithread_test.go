@@ -0,0 +1,120 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	_ "github.com/ncw/rclone/backend/local"
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultithreadStreams(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	oldStreams, oldCutoff := fs.Config.MultiThreadStreams, fs.Config.MultiThreadCutoff
+	defer func() {
+		fs.Config.MultiThreadStreams, fs.Config.MultiThreadCutoff = oldStreams, oldCutoff
+	}()
+
+	for _, test := range []struct {
+		size    int64
+		streams int
+		cutoff  fs.SizeSuffix
+		want    int
+	}{
+		{size: 0, streams: 4, cutoff: 100, want: 0},
+		{size: -1, streams: 4, cutoff: 100, want: 0},
+		{size: 99, streams: 4, cutoff: 100, want: 0},
+		{size: 100, streams: 4, cutoff: 100, want: 2},
+		{size: 200, streams: 4, cutoff: 100, want: 2},
+		{size: 201, streams: 4, cutoff: 100, want: 3},
+		{size: 301, streams: 4, cutoff: 100, want: 4},
+		{size: 10000, streams: 4, cutoff: 100, want: 4},
+		{size: 10000, streams: 1, cutoff: 100, want: 0},
+		{size: 10000, streams: 0, cutoff: 100, want: 0},
+		{size: 10, streams: 4, cutoff: 0, want: 4},
+	} {
+		fs.Config.MultiThreadStreams = test.streams
+		fs.Config.MultiThreadCutoff = test.cutoff
+		obj := &sizedObject{remote: "file.txt", size: test.size}
+		got := multiThreadStreams(r.Flocal, obj)
+		assert.Equal(t, test.want, got, fmt.Sprintf("%+v", test))
+	}
+}
+
+// sizedObject is an fs.Object which only knows its name and size
+type sizedObject struct {
+	fs.Object
+	remote string
+	size   int64
+}
+
+func (o *sizedObject) Remote() string { return o.remote }
+func (o *sizedObject) Size() int64    { return o.size }
+
+func TestMultithreadCalculateChunks(t *testing.T) {
+	for _, test := range []struct {
+		size         int64
+		streams      int
+		wantPartSize int64
+		wantStreams  int
+	}{
+		{size: 1, streams: 10, wantPartSize: multithreadChunkSize, wantStreams: 1},
+		{size: 1 << 20, streams: 1, wantPartSize: 1 << 20, wantStreams: 1},
+		{size: 1 << 20, streams: 2, wantPartSize: 1 << 19, wantStreams: 2},
+		{size: (1 << 20) + 1, streams: 2, wantPartSize: (1 << 19) + multithreadChunkSize, wantStreams: 2},
+		{size: (1 << 20) - 1, streams: 2, wantPartSize: (1 << 19), wantStreams: 2},
+		{size: 3 * multithreadChunkSize, streams: 4, wantPartSize: multithreadChunkSize, wantStreams: 3},
+	} {
+		t.Run(fmt.Sprintf("%+v", test), func(t *testing.T) {
+			mc := &multiThreadCopyState{
+				size:    test.size,
+				streams: test.streams,
+			}
+			mc.calculateChunks()
+			assert.Equal(t, test.wantPartSize, mc.partSize)
+			assert.Equal(t, test.wantStreams, mc.streams)
+		})
+	}
+}
+
+func TestMultithreadCopy(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	for _, test := range []struct {
+		size    int
+		streams int
+	}{
+		{size: multithreadChunkSize*2 - 1, streams: 2},
+		{size: multithreadChunkSize * 2, streams: 2},
+		{size: multithreadChunkSize*2 + 1, streams: 2},
+		{size: multithreadChunkSize*5 + 123, streams: 4},
+	} {
+		t.Run(fmt.Sprintf("%+v", test), func(t *testing.T) {
+			contents := fstest.RandomString(test.size)
+			t1 := fstest.Time("2001-02-03T04:05:06.499999999Z")
+			file1 := r.WriteObject("file1", contents, t1)
+			fstest.CheckItems(t, r.Fremote, file1)
+			fstest.CheckItems(t, r.Flocal)
+
+			src, err := r.Fremote.NewObject(context.Background(), "file1")
+			require.NoError(t, err)
+
+			dst, err := multiThreadCopy(context.Background(), r.Flocal, "file1", src, test.streams)
+			require.NoError(t, err)
+			assert.Equal(t, src.Size(), dst.Size())
+			assert.Equal(t, "file1", dst.Remote())
+
+			fstest.CheckListingWithPrecision(t, r.Fremote, []fstest.Item{file1}, nil, fs.GetModifyWindow(r.Flocal, r.Fremote))
+			fstest.CheckListingWithPrecision(t, r.Flocal, []fstest.Item{file1}, nil, fs.GetModifyWindow(r.Flocal, r.Fremote))
+			require.NoError(t, dst.Remove(context.Background()))
+			require.NoError(t, src.Remove(context.Background()))
+		})
+	}
+}
@@ -41,25 +41,25 @@ import (
 // err - may return an error which will already have been logged
 //
 // If an error is returned it will return equal as false
-func CheckHashes(src fs.ObjectInfo, dst fs.Object) (equal bool, ht hash.Type, err error) {
+func CheckHashes(ctx context.Context, src fs.ObjectInfo, dst fs.Object) (equal bool, ht hash.Type, err error) {
 	common := src.Fs().Hashes().Overlap(dst.Fs().Hashes())
 	// fs.Debugf(nil, "Shared hashes: %v", common)
 	if common.Count() == 0 {
 		return true, hash.None, nil
 	}
 	ht = common.GetOne()
-	srcHash, err := src.Hash(ht)
+	srcHash, err := src.Hash(ctx, ht)
 	if err != nil {
-		fs.CountError(err)
+		fs.CountError(ctx, err)
 		fs.Errorf(src, "Failed to calculate src hash: %v", err)
 		return false, ht, err
 	}
 	if srcHash == "" {
 		return true, hash.None, nil
 	}
-	dstHash, err := dst.Hash(ht)
+	dstHash, err := dst.Hash(ctx, ht)
 	if err != nil {
-		fs.CountError(err)
+		fs.CountError(ctx, err)
 		fs.Errorf(dst, "Failed to calculate dst hash: %v", err)
 		return false, ht, err
 	}
@@ -91,8 +91,8 @@ func CheckHashes(src fs.ObjectInfo, dst fs.Object) (equal bool, ht hash.Type, er
 //
 // Otherwise the file is considered to be not equal including if there
 // were errors reading info.
-func Equal(src fs.ObjectInfo, dst fs.Object) bool {
-	return equal(src, dst, fs.Config.SizeOnly, fs.Config.CheckSum)
+func Equal(ctx context.Context, src fs.ObjectInfo, dst fs.Object) bool {
+	return equal(ctx, src, dst, fs.Config.SizeOnly, fs.Config.CheckSum, !fs.Config.NoUpdateModTime)
 }
 
 // sizeDiffers compare the size of src and dst taking into account the
@@ -104,7 +104,7 @@ func sizeDiffers(src, dst fs.ObjectInfo) bool {
 	return src.Size() != dst.Size()
 }
 
-func equal(src fs.ObjectInfo, dst fs.Object, sizeOnly, checkSum bool) bool {
+func equal(ctx context.Context, src fs.ObjectInfo, dst fs.Object, sizeOnly, checkSum, updateModTime bool) bool {
 	if sizeDiffers(src, dst) {
 		fs.Debugf(src, "Sizes differ (src %d vs dst %d)", src.Size(), dst.Size())
 		return false
@@ -119,7 +119,7 @@ func equal(src fs.ObjectInfo, dst fs.Object, sizeOnly, checkSum bool) bool {
 	// If checking checksum and not modtime
 	if checkSum {
 		// Check the hash
-		same, ht, _ := CheckHashes(src, dst)
+		same, ht, _ := CheckHashes(ctx, src, dst)
 		if !same {
 			fs.Debugf(src, "%v differ", ht)
 			return false
@@ -149,7 +149,7 @@ func equal(src fs.ObjectInfo, dst fs.Object, sizeOnly, checkSum bool) bool {
 	fs.Debugf(src, "Modification times differ by %s: %v, %v", dt, srcModTime, dstModTime)
 
 	// Check if the hashes are the same
-	same, ht, _ := CheckHashes(src, dst)
+	same, ht, _ := CheckHashes(ctx, src, dst)
 	if !same {
 		fs.Debugf(src, "%v differ", ht)
 		return false
@@ -160,7 +160,7 @@ func equal(src fs.ObjectInfo, dst fs.Object, sizeOnly, checkSum bool) bool {
 	}
 
 	// mod time differs but hash is the same to reset mod time if required
-	if !fs.Config.NoUpdateModTime {
+	if updateModTime {
 		if fs.Config.DryRun {
 			fs.Logf(src, "Not updating modification time as --dry-run")
 		} else {
@@ -171,7 +171,7 @@ func equal(src fs.ObjectInfo, dst fs.Object, sizeOnly, checkSum bool) bool {
 				return false
 			}
 			// Update the mtime of the dst object here
-			err := dst.SetModTime(srcModTime)
+			err := dst.SetModTime(ctx, srcModTime)
 			if err == fs.ErrorCantSetModTime {
 				fs.Debugf(dst, "src and dst identical but can't set mod time without re-uploading")
 				return false
@@ -180,17 +180,17 @@ func equal(src fs.ObjectInfo, dst fs.Object, sizeOnly, checkSum bool) bool {
 				// Remove the file if BackupDir isn't set.  If BackupDir is set we would rather have the old file
 				// put in the BackupDir than deleted which is what will happen if we don't delete it.
 				if fs.Config.BackupDir == "" {
-					err = dst.Remove()
+					err = dst.Remove(ctx)
 					if err != nil {
 						fs.Errorf(dst, "failed to delete before re-upload: %v", err)
 					}
 				}
 				return false
 			} else if err != nil {
-				fs.CountError(err)
+				fs.CountError(ctx, err)
 				fs.Errorf(dst, "Failed to set modification time: %v", err)
 			} else {
-				fs.Infof(src, "Updated modification time in destination")
+				fs.Infof(src, "Updated modification time in destination%v", fs.LogValueHide("operation", "setmodtime"))
 			}
 		}
 	}
@@ -200,12 +200,12 @@ func equal(src fs.ObjectInfo, dst fs.Object, sizeOnly, checkSum bool) bool {
 // Used to remove a failed copy
 //
 // Returns whether the file was succesfully removed or not
-func removeFailedCopy(dst fs.Object) bool {
+func removeFailedCopy(ctx context.Context, dst fs.Object) bool {
 	if dst == nil {
 		return false
 	}
 	fs.Infof(dst, "Removing failed copy")
-	removeErr := dst.Remove()
+	removeErr := dst.Remove(ctx)
 	if removeErr != nil {
 		fs.Infof(dst, "Failed to remove failed copy: %s", removeErr)
 		return false
@@ -233,15 +233,24 @@ func (o *overrideRemoteObject) MimeType() string {
 	return ""
 }
 
+// Metadata returns the metadata of the underlying object or nil if it
+// has none
+func (o *overrideRemoteObject) Metadata(ctx context.Context) (fs.Metadata, error) {
+	return fs.GetMetadata(ctx, o.Object)
+}
+
 // Check interface is satisfied
-var _ fs.MimeTyper = (*overrideRemoteObject)(nil)
+var (
+	_ fs.MimeTyper  = (*overrideRemoteObject)(nil)
+	_ fs.Metadataer = (*overrideRemoteObject)(nil)
+)
 
 // Copy src object to dst or f if nil.  If dst is nil then it uses
 // remote as the name of the new object.
 //
 // It returns the destination object if possible.  Note that this may
 // be nil.
-func Copy(f fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Object, err error) {
+func Copy(ctx context.Context, f fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Object, err error) {
 	newDst = dst
 	if fs.Config.DryRun {
 		fs.Logf(src, "Not copying as --dry-run")
@@ -261,27 +270,54 @@ func Copy(f fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Objec
 		}
 	}
 	hashOption := &fs.HashesOption{Hashes: common}
+	options := []fs.OpenOption{hashOption}
+	// read the source metadata to pass on if required
+	useMetadata := fs.Config.Metadata && f.Features().WriteMetadata
+	if useMetadata {
+		metadata, err := fs.GetMetadata(ctx, src)
+		if err != nil {
+			fs.Errorf(src, "Failed to read metadata: %v", err)
+		} else if metadata != nil {
+			options = append(options, &fs.MetadataOption{Metadata: metadata})
+		}
+	}
 	var actionTaken string
 	for {
 		// Try server side copy first - if has optional interface and
 		// is same underlying remote
 		actionTaken = "Copied (server side copy)"
 		if doCopy := f.Features().Copy; doCopy != nil && SameConfig(src.Fs(), f) {
-			newDst, err = doCopy(src, remote)
+			newDst, err = doCopy(ctx, src, remote)
 			if err == nil {
 				dst = newDst
 			}
 		} else {
 			err = fs.ErrorCantCopy
 		}
-		// If can't server side copy, do it manually
-		if err == fs.ErrorCantCopy {
+		// If can't server side copy, do it manually.  Large files
+		// are read in multiple streams if the destination can write
+		// them in place, unless the metadata needs setting as well.
+		streams := 0
+		if !useMetadata {
+			streams = multiThreadStreams(f, src)
+		}
+		if err == fs.ErrorCantCopy && streams > 0 {
+			dst, err = multiThreadCopy(ctx, f, remote, src, streams)
+			if doUpdate {
+				actionTaken = "Multi-thread Copied (replaced existing)"
+			} else {
+				actionTaken = "Multi-thread Copied (new)"
+			}
+			if err == nil {
+				newDst = dst
+			}
+		} else if err == fs.ErrorCantCopy {
 			var in0 io.ReadCloser
-			in0, err = src.Open(hashOption)
+			in0, err = src.Open(ctx, hashOption)
 			if err != nil {
 				err = errors.Wrap(err, "failed to open source object")
 			} else {
-				in := accounting.NewAccount(in0, src).WithBuffer() // account and buffer the transfer
+				in := accounting.GetStats(ctx).NewAccount(in0, src).WithBuffer() // account and buffer the transfer
 				var wrappedSrc fs.ObjectInfo = src
 				// We try to pass the original object if possible
 				if src.Remote() != remote {
@@ -289,10 +325,10 @@ func Copy(f fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Objec
 				}
 				if doUpdate {
 					actionTaken = "Copied (replaced existing)"
-					err = dst.Update(in, wrappedSrc, hashOption)
+					err = dst.Update(ctx, in, wrappedSrc, options...)
 				} else {
 					actionTaken = "Copied (new)"
-					dst, err = f.Put(in, wrappedSrc, hashOption)
+					dst, err = f.Put(ctx, in, wrappedSrc, options...)
 				}
 				closeErr := in.Close()
 				if err == nil {
@@ -314,8 +350,8 @@ func Copy(f fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Objec
 		break
 	}
 	if err != nil {
-		fs.CountError(err)
-		fs.Errorf(src, "Failed to copy: %v", err)
+		fs.CountError(ctx, err)
+		fs.Errorf(src, "Failed to copy: %v%v", err, fs.LogValueHide("operation", "copy"))
 		return newDst, err
 	}
 
@@ -323,8 +359,8 @@ func Copy(f fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Objec
 	if sizeDiffers(src, dst) {
 		err = errors.Errorf("corrupted on transfer: sizes differ %d vs %d", src.Size(), dst.Size())
 		fs.Errorf(dst, "%v", err)
-		fs.CountError(err)
-		removeFailedCopy(dst)
+		fs.CountError(ctx, err)
+		removeFailedCopy(ctx, dst)
 		return newDst, err
 	}
 
@@ -333,27 +369,27 @@ func Copy(f fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Objec
 	// the destination, and calculate it while sending.
 	if hashType != hash.None {
 		var srcSum string
-		srcSum, err = src.Hash(hashType)
+		srcSum, err = src.Hash(ctx, hashType)
 		if err != nil {
-			fs.CountError(err)
+			fs.CountError(ctx, err)
 			fs.Errorf(src, "Failed to read src hash: %v", err)
 		} else if srcSum != "" {
 			var dstSum string
-			dstSum, err = dst.Hash(hashType)
+			dstSum, err = dst.Hash(ctx, hashType)
 			if err != nil {
-				fs.CountError(err)
+				fs.CountError(ctx, err)
 				fs.Errorf(dst, "Failed to read hash: %v", err)
 			} else if !fs.Config.IgnoreChecksum && !hash.Equals(srcSum, dstSum) {
 				err = errors.Errorf("corrupted on transfer: %v hash differ %q vs %q", hashType, srcSum, dstSum)
 				fs.Errorf(dst, "%v", err)
-				fs.CountError(err)
-				removeFailedCopy(dst)
+				fs.CountError(ctx, err)
+				removeFailedCopy(ctx, dst)
 				return newDst, err
 			}
 		}
 	}
 
-	fs.Infof(src, actionTaken)
+	fs.Infof(src, "%s%v%v", actionTaken, fs.LogValueHide("operation", "copy"), fs.LogValueHide("size", src.Size()))
 	return newDst, err
 }
 
@@ -367,7 +403,7 @@ func Copy(f fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Objec
 //
 // It returns the destination object if possible.  Note that this may
 // be nil.
-func Move(fdst fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Object, err error) {
+func Move(ctx context.Context, fdst fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Object, err error) {
 	newDst = dst
 	if fs.Config.DryRun {
 		fs.Logf(src, "Not moving as --dry-run")
@@ -377,33 +413,33 @@ func Move(fdst fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Ob
 	if doMove := fdst.Features().Move; doMove != nil && SameConfig(src.Fs(), fdst) {
 		// Delete destination if it exists
 		if dst != nil {
-			err = DeleteFile(dst)
+			err = DeleteFile(ctx, dst)
 			if err != nil {
 				return newDst, err
 			}
 		}
 		// Move dst <- src
-		newDst, err = doMove(src, remote)
+		newDst, err = doMove(ctx, src, remote)
 		switch err {
 		case nil:
-			fs.Infof(src, "Moved (server side)")
+			fs.Infof(src, "Moved (server side)%v%v", fs.LogValueHide("operation", "move"), fs.LogValueHide("size", src.Size()))
 			return newDst, nil
 		case fs.ErrorCantMove:
 			fs.Debugf(src, "Can't move, switching to copy")
 		default:
-			fs.CountError(err)
-			fs.Errorf(src, "Couldn't move: %v", err)
+			fs.CountError(ctx, err)
+			fs.Errorf(src, "Couldn't move: %v%v", err, fs.LogValueHide("operation", "move"))
 			return newDst, err
 		}
 	}
 	// Move not found or didn't work so copy dst <- src
-	newDst, err = Copy(fdst, dst, remote, src)
+	newDst, err = Copy(ctx, fdst, dst, remote, src)
 	if err != nil {
 		fs.Errorf(src, "Not deleting source as copy failed: %v", err)
 		return newDst, err
 	}
 	// Delete src if no error on copy
-	return newDst, DeleteFile(src)
+	return newDst, DeleteFile(ctx, src)
 }
 
 // CanServerSideMove returns true if fdst support server side moves or
@@ -422,15 +458,15 @@ func CanServerSideMove(fdst fs.Fs) bool {
 //
 // If backupDir is set then it moves the file to there instead of
 // deleting
-func DeleteFileWithBackupDir(dst fs.Object, backupDir fs.Fs) (err error) {
-	accounting.Stats.Checking(dst.Remote())
-	numDeletes := accounting.Stats.Deletes(1)
+func DeleteFileWithBackupDir(ctx context.Context, dst fs.Object, backupDir fs.Fs) (err error) {
+	accounting.GetStats(ctx).Checking(dst.Remote())
+	numDeletes := accounting.GetStats(ctx).Deletes(1)
 	if fs.Config.MaxDelete != -1 && numDeletes > fs.Config.MaxDelete {
 		return fserrors.FatalError(errors.New("--max-delete threshold reached"))
 	}
-	action, actioned, actioning := "delete", "Deleted", "deleting"
+	operation, action, actioned, actioning := "delete", "delete", "Deleted", "deleting"
 	if backupDir != nil {
-		action, actioned, actioning = "move into backup dir", "Moved into backup dir", "moving into backup dir"
+		operation, action, actioned, actioning = "backup", "move into backup dir", "Moved into backup dir", "moving into backup dir"
 	}
 	if fs.Config.DryRun {
 		fs.Logf(dst, "Not %s as --dry-run", actioning)
@@ -438,20 +474,26 @@ func DeleteFileWithBackupDir(dst fs.Object, backupDir fs.Fs) (err error) {
 		if !SameConfig(dst.Fs(), backupDir) {
 			err = errors.New("parameter to --backup-dir has to be on the same remote as destination")
 		} else {
-			remoteWithSuffix := dst.Remote() + fs.Config.Suffix
-			overwritten, _ := backupDir.NewObject(remoteWithSuffix)
-			_, err = Move(backupDir, overwritten, remoteWithSuffix, dst)
+			err = MoveBackupDir(ctx, backupDir, dst)
 		}
 	} else {
-		err = dst.Remove()
+		err = dst.Remove(ctx)
 	}
 	if err != nil {
-		fs.CountError(err)
-		fs.Errorf(dst, "Couldn't %s: %v", action, err)
+		fs.CountError(ctx, err)
+		fs.Errorf(dst, "Couldn't %s: %v%v", action, err, fs.LogValueHide("operation", operation))
 	} else if !fs.Config.DryRun {
-		fs.Infof(dst, actioned)
+		fs.Infof(dst, "%s%v", actioned, fs.LogValueHide("operation", operation))
 	}
-	accounting.Stats.DoneChecking(dst.Remote())
+	accounting.GetStats(ctx).DoneChecking(dst.Remote())
+	return err
+}
+
+// MoveBackupDir moves dst into backupDir adding the --suffix
+func MoveBackupDir(ctx context.Context, backupDir fs.Fs, dst fs.Object) (err error) {
+	remoteWithSuffix := SuffixName(dst.Remote())
+	overwritten, _ := backupDir.NewObject(ctx, remoteWithSuffix)
+	_, err = Move(ctx, backupDir, overwritten, remoteWithSuffix, dst)
 	return err
 }
 
@@ -459,8 +501,8 @@ func DeleteFileWithBackupDir(dst fs.Object, backupDir fs.Fs) (err error) {
 //
 // If useBackupDir is set and --backup-dir is in effect then it moves
 // the file to there instead of deleting
-func DeleteFile(dst fs.Object) (err error) {
-	return DeleteFileWithBackupDir(dst, nil)
+func DeleteFile(ctx context.Context, dst fs.Object) (err error) {
+	return DeleteFileWithBackupDir(ctx, dst, nil)
 }
 
 // DeleteFilesWithBackupDir removes all the files passed in the
@@ -468,7 +510,7 @@ func DeleteFile(dst fs.Object) (err error) {
 //
 // If backupDir is set the files will be placed into that directory
 // instead of being deleted.
-func DeleteFilesWithBackupDir(toBeDeleted fs.ObjectsChan, backupDir fs.Fs) error {
+func DeleteFilesWithBackupDir(ctx context.Context, toBeDeleted fs.ObjectsChan, backupDir fs.Fs) error {
 	var wg sync.WaitGroup
 	wg.Add(fs.Config.Transfers)
 	var errorCount int32
@@ -478,7 +520,7 @@ func DeleteFilesWithBackupDir(toBeDeleted fs.ObjectsChan, backupDir fs.Fs) error
 		go func() {
 			defer wg.Done()
 			for dst := range toBeDeleted {
-				err := DeleteFileWithBackupDir(dst, backupDir)
+				err := DeleteFileWithBackupDir(ctx, dst, backupDir)
 				if err != nil {
 					atomic.AddInt32(&errorCount, 1)
 					if fserrors.IsFatalError(err) {
@@ -503,8 +545,8 @@ func DeleteFilesWithBackupDir(toBeDeleted fs.ObjectsChan, backupDir fs.Fs) error
 }
 
 // DeleteFiles removes all the files passed in the channel
-func DeleteFiles(toBeDeleted fs.ObjectsChan) error {
-	return DeleteFilesWithBackupDir(toBeDeleted, nil)
+func DeleteFiles(ctx context.Context, toBeDeleted fs.ObjectsChan) error {
+	return DeleteFilesWithBackupDir(ctx, toBeDeleted, nil)
 }
 
 // SameConfig returns true if fdst and fsrc are using the same config
@@ -541,33 +583,38 @@ func Overlapping(fdst, fsrc fs.Info) bool {
 //
 // it returns true if differences were found
 // it also returns whether it couldn't be hashed
-func checkIdentical(dst, src fs.Object) (differ bool, noHash bool) {
-	same, ht, err := CheckHashes(src, dst)
+// and any error checking which has already been logged and counted
+func checkIdentical(ctx context.Context, dst, src fs.Object) (differ bool, noHash bool, err error) {
+	same, ht, err := CheckHashes(ctx, src, dst)
 	if err != nil {
 		// CheckHashes will log and count errors
-		return true, false
+		return true, false, err
 	}
 	if ht == hash.None {
-		return false, true
+		return false, true, nil
 	}
 	if !same {
 		err = errors.Errorf("%v differ", ht)
 		fs.Errorf(src, "%v", err)
-		fs.CountError(err)
-		return true, false
+		fs.CountError(ctx, err)
+		return true, false, nil
 	}
-	return false, false
+	return false, false, nil
 }
 
 // checkFn is the the type of the checking function used in CheckFn()
-type checkFn func(a, b fs.Object) (differ bool, noHash bool)
+//
+// If it returns an error it must have logged and counted it already.
+type checkFn func(ctx context.Context, a, b fs.Object) (differ bool, noHash bool, err error)
 
 // checkMarch is used to march over two Fses in the same way as
 // sync/copy
 type checkMarch struct {
+	ctx             context.Context
 	fdst, fsrc      fs.Fs
 	check           checkFn
 	oneway          bool
+	reports         *Reports
 	differences     int32
 	noHashes        int32
 	srcFilesMissing int32
@@ -583,9 +630,10 @@ func (c *checkMarch) DstOnly(dst fs.DirEntry) (recurse bool) {
 		}
 		err := errors.Errorf("File not in %v", c.fsrc)
 		fs.Errorf(dst, "%v", err)
-		fs.CountError(err)
+		fs.CountError(c.ctx, err)
 		atomic.AddInt32(&c.differences, 1)
 		atomic.AddInt32(&c.srcFilesMissing, 1)
+		c.reports.Report(ReportMissingOnSrc, dst.Remote())
 	case fs.Directory:
 		// Do the same thing to the entire contents of the directory
 		return true
@@ -601,9 +649,10 @@ func (c *checkMarch) SrcOnly(src fs.DirEntry) (recurse bool) {
 	case fs.Object:
 		err := errors.Errorf("File not in %v", c.fdst)
 		fs.Errorf(src, "%v", err)
-		fs.CountError(err)
+		fs.CountError(c.ctx, err)
 		atomic.AddInt32(&c.differences, 1)
 		atomic.AddInt32(&c.dstFilesMissing, 1)
+		c.reports.Report(ReportMissingOnDst, src.Remote())
 	case fs.Directory:
 		// Do the same thing to the entire contents of the directory
 		return true
@@ -614,28 +663,28 @@ func (c *checkMarch) SrcOnly(src fs.DirEntry) (recurse bool) {
 }
 
 // check to see if two objects are identical using the check function
-func (c *checkMarch) checkIdentical(dst, src fs.Object) (differ bool, noHash bool) {
-	accounting.Stats.Checking(src.Remote())
-	defer accounting.Stats.DoneChecking(src.Remote())
+func (c *checkMarch) checkIdentical(ctx context.Context, dst, src fs.Object) (differ bool, noHash bool, err error) {
+	accounting.GetStats(ctx).Checking(src.Remote())
+	defer accounting.GetStats(ctx).DoneChecking(src.Remote())
 	if sizeDiffers(src, dst) {
 		err := errors.Errorf("Sizes differ")
 		fs.Errorf(src, "%v", err)
-		fs.CountError(err)
-		return true, false
+		fs.CountError(ctx, err)
+		return true, false, nil
 	}
 	if fs.Config.SizeOnly {
-		return false, false
+		return false, false, nil
 	}
-	return c.check(dst, src)
+	return c.check(ctx, dst, src)
 }
 
 // Match is called when src and dst are present, so sync src to dst
-func (c *checkMarch) Match(dst, src fs.DirEntry) (recurse bool) {
+func (c *checkMarch) Match(ctx context.Context, dst, src fs.DirEntry) (recurse bool) {
 	switch srcX := src.(type) {
 	case fs.Object:
 		dstX, ok := dst.(fs.Object)
 		if ok {
-			differ, noHash := c.checkIdentical(dstX, srcX)
+			differ, noHash, err := c.checkIdentical(ctx, dstX, srcX)
 			if differ {
 				atomic.AddInt32(&c.differences, 1)
 			} else {
@@ -644,12 +693,21 @@ func (c *checkMarch) Match(dst, src fs.DirEntry) (recurse bool) {
 			if noHash {
 				atomic.AddInt32(&c.noHashes, 1)
 			}
+			switch {
+			case err != nil:
+				c.reports.Report(ReportError, src.Remote())
+			case differ:
+				c.reports.Report(ReportDiffer, src.Remote())
+			default:
+				c.reports.Report(ReportMatch, src.Remote())
+			}
 		} else {
 			err := errors.Errorf("is file on %v but directory on %v", c.fsrc, c.fdst)
 			fs.Errorf(src, "%v", err)
-			fs.CountError(err)
+			fs.CountError(ctx, err)
 			atomic.AddInt32(&c.differences, 1)
 			atomic.AddInt32(&c.dstFilesMissing, 1)
+			c.reports.Report(ReportError, src.Remote())
 		}
 	case fs.Directory:
 		// Do the same thing to the entire contents of the directory
@@ -659,9 +717,10 @@ func (c *checkMarch) Match(dst, src fs.DirEntry) (recurse bool) {
 		}
 		err := errors.Errorf("is file on %v but directory on %v", c.fdst, c.fsrc)
 		fs.Errorf(dst, "%v", err)
-		fs.CountError(err)
+		fs.CountError(ctx, err)
 		atomic.AddInt32(&c.differences, 1)
 		atomic.AddInt32(&c.srcFilesMissing, 1)
+		c.reports.Report(ReportError, dst.Remote())
 
 	default:
 		panic("Bad object in DirEntries")
@@ -676,17 +735,22 @@ func (c *checkMarch) Match(dst, src fs.DirEntry) (recurse bool) {
 //
 // it returns true if differences were found
 // it also returns whether it couldn't be hashed
-func CheckFn(fdst, fsrc fs.Fs, check checkFn, oneway bool) error {
+//
+// If ctx was made with WithReports the files are written to the
+// reports as they are checked.
+func CheckFn(ctx context.Context, fdst, fsrc fs.Fs, check checkFn, oneway bool) error {
 	c := &checkMarch{
-		fdst:   fdst,
-		fsrc:   fsrc,
-		check:  check,
-		oneway: oneway,
+		ctx:     ctx,
+		fdst:    fdst,
+		fsrc:    fsrc,
+		check:   check,
+		oneway:  oneway,
+		reports: GetReports(ctx),
 	}
 
 	// set up a march over fdst and fsrc
 	m := &march.March{
-		Ctx:      context.Background(),
+		Ctx:      ctx,
 		Fdst:     fdst,
 		Fsrc:     fsrc,
 		Dir:      "",
@@ -702,7 +766,7 @@ func CheckFn(fdst, fsrc fs.Fs, check checkFn, oneway bool) error {
 		fs.Logf(fsrc, "%d files missing", c.srcFilesMissing)
 	}
 
-	fs.Logf(fdst, "%d differences found", accounting.Stats.GetErrors())
+	fs.Logf(fdst, "%d differences found", accounting.GetStats(ctx).GetErrors())
 	if c.noHashes > 0 {
 		fs.Logf(fdst, "%d hashes could not be checked", c.noHashes)
 	}
@@ -713,8 +777,8 @@ func CheckFn(fdst, fsrc fs.Fs, check checkFn, oneway bool) error {
 }
 
 // Check the files in fsrc and fdst according to Size and hash
-func Check(fdst, fsrc fs.Fs, oneway bool) error {
-	return CheckFn(fdst, fsrc, checkIdentical, oneway)
+func Check(ctx context.Context, fdst, fsrc fs.Fs, oneway bool) error {
+	return CheckFn(ctx, fdst, fsrc, checkIdentical, oneway)
 }
 
 // CheckEqualReaders checks to see if in1 and in2 have the same
@@ -751,19 +815,19 @@ func CheckEqualReaders(in1, in2 io.Reader) (differ bool, err error) {
 // reading all their bytes if necessary.
 //
 // it returns true if differences were found
-func CheckIdentical(dst, src fs.Object) (differ bool, err error) {
-	in1, err := dst.Open()
+func CheckIdentical(ctx context.Context, dst, src fs.Object) (differ bool, err error) {
+	in1, err := dst.Open(ctx)
 	if err != nil {
 		return true, errors.Wrapf(err, "failed to open %q", dst)
 	}
-	in1 = accounting.NewAccount(in1, dst).WithBuffer() // account and buffer the transfer
+	in1 = accounting.GetStats(ctx).NewAccount(in1, dst).WithBuffer() // account and buffer the transfer
 	defer fs.CheckClose(in1, &err)
 
-	in2, err := src.Open()
+	in2, err := src.Open(ctx)
 	if err != nil {
 		return true, errors.Wrapf(err, "failed to open %q", src)
 	}
-	in2 = accounting.NewAccount(in2, src).WithBuffer() // account and buffer the transfer
+	in2 = accounting.GetStats(ctx).NewAccount(in2, src).WithBuffer() // account and buffer the transfer
 	defer fs.CheckClose(in2, &err)
 
 	return CheckEqualReaders(in1, in2)
@@ -771,24 +835,24 @@ func CheckIdentical(dst, src fs.Object) (differ bool, err error) {
 
 // CheckDownload checks the files in fsrc and fdst according to Size
 // and the actual contents of the files.
-func CheckDownload(fdst, fsrc fs.Fs, oneway bool) error {
-	check := func(a, b fs.Object) (differ bool, noHash bool) {
-		differ, err := CheckIdentical(a, b)
+func CheckDownload(ctx context.Context, fdst, fsrc fs.Fs, oneway bool) error {
+	check := func(ctx context.Context, a, b fs.Object) (differ bool, noHash bool, err error) {
+		differ, err = CheckIdentical(ctx, a, b)
 		if err != nil {
-			fs.CountError(err)
+			fs.CountError(ctx, err)
 			fs.Errorf(a, "Failed to download: %v", err)
-			return true, true
+			return true, true, err
 		}
-		return differ, false
+		return differ, false, nil
 	}
-	return CheckFn(fdst, fsrc, check, oneway)
+	return CheckFn(ctx, fdst, fsrc, check, oneway)
 }
 
 // ListFn lists the Fs to the supplied function
 //
 // Lists in parallel which may get them out of order
-func ListFn(f fs.Fs, fn func(fs.Object)) error {
-	return walk.Walk(f, "", false, fs.Config.MaxDepth, func(dirPath string, entries fs.DirEntries, err error) error {
+func ListFn(ctx context.Context, f fs.Fs, fn func(fs.Object)) error {
+	return walk.Walk(ctx, f, "", false, fs.Config.MaxDepth, func(dirPath string, entries fs.DirEntries, err error) error {
 		if err != nil {
 			// FIXME count errors and carry on for listing
 			return err
@@ -815,8 +879,8 @@ func syncFprintf(w io.Writer, format string, a ...interface{}) {
 // Shows size and path - obeys includes and excludes
 //
 // Lists in parallel which may get them out of order
-func List(f fs.Fs, w io.Writer) error {
-	return ListFn(f, func(o fs.Object) {
+func List(ctx context.Context, f fs.Fs, w io.Writer) error {
+	return ListFn(ctx, f, func(o fs.Object) {
 		syncFprintf(w, "%9d %s\n", o.Size(), o.Remote())
 	})
 }
@@ -826,11 +890,11 @@ func List(f fs.Fs, w io.Writer) error {
 // Shows size, mod time and path - obeys includes and excludes
 //
 // Lists in parallel which may get them out of order
-func ListLong(f fs.Fs, w io.Writer) error {
-	return ListFn(f, func(o fs.Object) {
-		accounting.Stats.Checking(o.Remote())
+func ListLong(ctx context.Context, f fs.Fs, w io.Writer) error {
+	return ListFn(ctx, f, func(o fs.Object) {
+		accounting.GetStats(ctx).Checking(o.Remote())
 		modTime := o.ModTime()
-		accounting.Stats.DoneChecking(o.Remote())
+		accounting.GetStats(ctx).DoneChecking(o.Remote())
 		syncFprintf(w, "%9d %s %s\n", o.Size(), modTime.Local().Format("2006-01-02 15:04:05.000000000"), o.Remote())
 	})
 }
@@ -841,8 +905,8 @@ func ListLong(f fs.Fs, w io.Writer) error {
 // excludes
 //
 // Lists in parallel which may get them out of order
-func Md5sum(f fs.Fs, w io.Writer) error {
-	return HashLister(hash.MD5, f, w)
+func Md5sum(ctx context.Context, f fs.Fs, w io.Writer) error {
+	return HashLister(ctx, hash.MD5, false, f, w)
 }
 
 // Sha1sum list the Fs to the supplied writer
@@ -850,8 +914,8 @@ func Md5sum(f fs.Fs, w io.Writer) error {
 // Obeys includes and excludes
 //
 // Lists in parallel which may get them out of order
-func Sha1sum(f fs.Fs, w io.Writer) error {
-	return HashLister(hash.SHA1, f, w)
+func Sha1sum(ctx context.Context, f fs.Fs, w io.Writer) error {
+	return HashLister(ctx, hash.SHA1, false, f, w)
 }
 
 // DropboxHashSum list the Fs to the supplied writer
@@ -859,16 +923,36 @@ func Sha1sum(f fs.Fs, w io.Writer) error {
 // Obeys includes and excludes
 //
 // Lists in parallel which may get them out of order
-func DropboxHashSum(f fs.Fs, w io.Writer) error {
-	return HashLister(hash.Dropbox, f, w)
+func DropboxHashSum(ctx context.Context, f fs.Fs, w io.Writer) error {
+	return HashLister(ctx, hash.Dropbox, false, f, w)
+}
+
+// objectHash returns the hash of type ht for o.  If download is set
+// it reads the contents of o to calculate it rather than asking the
+// remote.
+func objectHash(ctx context.Context, ht hash.Type, download bool, o fs.Object) (sum string, err error) {
+	if !download {
+		return o.Hash(ctx, ht)
+	}
+	in, err := o.Open(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to open")
+	}
+	in = accounting.GetStats(ctx).NewAccount(in, o).WithBuffer() // account and buffer the transfer
+	defer fs.CheckClose(in, &err)
+	sums, err := hash.StreamTypes(in, hash.NewHashSet(ht))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read")
+	}
+	return sums[ht], nil
 }
 
 // hashSum returns the human readable hash for ht passed in.  This may
 // be UNSUPPORTED or ERROR.
-func hashSum(ht hash.Type, o fs.Object) string {
-	accounting.Stats.Checking(o.Remote())
-	sum, err := o.Hash(ht)
-	accounting.Stats.DoneChecking(o.Remote())
+func hashSum(ctx context.Context, ht hash.Type, download bool, o fs.Object) string {
+	accounting.GetStats(ctx).Checking(o.Remote())
+	sum, err := objectHash(ctx, ht, download, o)
+	accounting.GetStats(ctx).DoneChecking(o.Remote())
 	if err == hash.ErrUnsupported {
 		sum = "UNSUPPORTED"
 	} else if err != nil {
@@ -878,10 +962,12 @@ func hashSum(ht hash.Type, o fs.Object) string {
 	return sum
 }
 
-// HashLister does a md5sum equivalent for the hash type passed in
-func HashLister(ht hash.Type, f fs.Fs, w io.Writer) error {
-	return ListFn(f, func(o fs.Object) {
-		sum := hashSum(ht, o)
+// HashLister does a md5sum equivalent for the hash type passed in.
+// If download is set the hashes are calculated from the contents of
+// the files rather than read from the remote.
+func HashLister(ctx context.Context, ht hash.Type, download bool, f fs.Fs, w io.Writer) error {
+	return ListFn(ctx, f, func(o fs.Object) {
+		sum := hashSum(ctx, ht, download, o)
 		syncFprintf(w, "%*s  %s\n", hash.Width[ht], sum, o.Remote())
 	})
 }
@@ -889,8 +975,8 @@ func HashLister(ht hash.Type, f fs.Fs, w io.Writer) error {
 // Count counts the objects and their sizes in the Fs
 //
 // Obeys includes and excludes
-func Count(f fs.Fs) (objects int64, size int64, err error) {
-	err = ListFn(f, func(o fs.Object) {
+func Count(ctx context.Context, f fs.Fs) (objects int64, size int64, err error) {
+	err = ListFn(ctx, f, func(o fs.Object) {
 		atomic.AddInt64(&objects, 1)
 		atomic.AddInt64(&size, o.Size())
 	})
@@ -907,8 +993,8 @@ func ConfigMaxDepth(recursive bool) int {
 }
 
 // ListDir lists the directories/buckets/containers in the Fs to the supplied writer
-func ListDir(f fs.Fs, w io.Writer) error {
-	return walk.Walk(f, "", false, ConfigMaxDepth(false), func(dirPath string, entries fs.DirEntries, err error) error {
+func ListDir(ctx context.Context, f fs.Fs, w io.Writer) error {
+	return walk.Walk(ctx, f, "", false, ConfigMaxDepth(false), func(dirPath string, entries fs.DirEntries, err error) error {
 		if err != nil {
 			// FIXME count errors and carry on for listing
 			return err
@@ -923,15 +1009,15 @@ func ListDir(f fs.Fs, w io.Writer) error {
 }
 
 // Mkdir makes a destination directory or container
-func Mkdir(f fs.Fs, dir string) error {
+func Mkdir(ctx context.Context, f fs.Fs, dir string) error {
 	if fs.Config.DryRun {
 		fs.Logf(fs.LogDirName(f, dir), "Not making directory as dry run is set")
 		return nil
 	}
-	fs.Debugf(fs.LogDirName(f, dir), "Making directory")
-	err := f.Mkdir(dir)
+	fs.Debugf(fs.LogDirName(f, dir), "Making directory%v", fs.LogValueHide("operation", "mkdir"))
+	err := f.Mkdir(ctx, dir)
 	if err != nil {
-		fs.CountError(err)
+		fs.CountError(ctx, err)
 		return err
 	}
 	return nil
@@ -939,27 +1025,27 @@ func Mkdir(f fs.Fs, dir string) error {
 
 // TryRmdir removes a container but not if not empty.  It doesn't
 // count errors but may return one.
-func TryRmdir(f fs.Fs, dir string) error {
+func TryRmdir(ctx context.Context, f fs.Fs, dir string) error {
 	if fs.Config.DryRun {
 		fs.Logf(fs.LogDirName(f, dir), "Not deleting as dry run is set")
 		return nil
 	}
-	fs.Debugf(fs.LogDirName(f, dir), "Removing directory")
-	return f.Rmdir(dir)
+	fs.Debugf(fs.LogDirName(f, dir), "Removing directory%v", fs.LogValueHide("operation", "rmdir"))
+	return f.Rmdir(ctx, dir)
 }
 
 // Rmdir removes a container but not if not empty
-func Rmdir(f fs.Fs, dir string) error {
-	err := TryRmdir(f, dir)
+func Rmdir(ctx context.Context, f fs.Fs, dir string) error {
+	err := TryRmdir(ctx, f, dir)
 	if err != nil {
-		fs.CountError(err)
+		fs.CountError(ctx, err)
 		return err
 	}
 	return err
 }
 
 // Purge removes a directory and all of its contents
-func Purge(f fs.Fs, dir string) error {
+func Purge(ctx context.Context, f fs.Fs, dir string) error {
 	doFallbackPurge := true
 	var err error
 	if dir == "" {
@@ -969,7 +1055,7 @@ func Purge(f fs.Fs, dir string) error {
 			if fs.Config.DryRun {
 				fs.Logf(f, "Not purging as --dry-run set")
 			} else {
-				err = doPurge()
+				err = doPurge(ctx)
 				if err == fs.ErrorCantPurge {
 					doFallbackPurge = true
 				}
@@ -978,14 +1064,14 @@ func Purge(f fs.Fs, dir string) error {
 	}
 	if doFallbackPurge {
 		// DeleteFiles and Rmdir observe --dry-run
-		err = DeleteFiles(listToChan(f, dir))
+		err = DeleteFiles(ctx, listToChan(ctx, f, dir))
 		if err != nil {
 			return err
 		}
-		err = Rmdirs(f, dir, false)
+		err = Rmdirs(ctx, f, dir, false)
 	}
 	if err != nil {
-		fs.CountError(err)
+		fs.CountError(ctx, err)
 		return err
 	}
 	return nil
@@ -993,13 +1079,13 @@ func Purge(f fs.Fs, dir string) error {
 
 // Delete removes all the contents of a container.  Unlike Purge, it
 // obeys includes and excludes.
-func Delete(f fs.Fs) error {
+func Delete(ctx context.Context, f fs.Fs) error {
 	delChan := make(fs.ObjectsChan, fs.Config.Transfers)
 	delErr := make(chan error, 1)
 	go func() {
-		delErr <- DeleteFiles(delChan)
+		delErr <- DeleteFiles(ctx, delChan)
 	}()
-	err := ListFn(f, func(o fs.Object) {
+	err := ListFn(ctx, f, func(o fs.Object) {
 		delChan <- o
 	})
 	close(delChan)
@@ -1016,17 +1102,17 @@ func Delete(f fs.Fs) error {
 // channel.
 //
 // If the error was ErrorDirNotFound then it will be ignored
-func listToChan(f fs.Fs, dir string) fs.ObjectsChan {
+func listToChan(ctx context.Context, f fs.Fs, dir string) fs.ObjectsChan {
 	o := make(fs.ObjectsChan, fs.Config.Checkers)
 	go func() {
 		defer close(o)
-		_ = walk.Walk(f, dir, true, fs.Config.MaxDepth, func(dirPath string, entries fs.DirEntries, err error) error {
+		_ = walk.Walk(ctx, f, dir, true, fs.Config.MaxDepth, func(dirPath string, entries fs.DirEntries, err error) error {
 			if err != nil {
 				if err == fs.ErrorDirNotFound {
 					return nil
 				}
 				err = errors.Errorf("Failed to list: %v", err)
-				fs.CountError(err)
+				fs.CountError(ctx, err)
 				fs.Errorf(nil, "%v", err)
 				return nil
 			}
@@ -1040,7 +1126,7 @@ func listToChan(f fs.Fs, dir string) fs.ObjectsChan {
 }
 
 // CleanUp removes the trash for the Fs
-func CleanUp(f fs.Fs) error {
+func CleanUp(ctx context.Context, f fs.Fs) error {
 	doCleanUp := f.Features().CleanUp
 	if doCleanUp == nil {
 		return errors.Errorf("%v doesn't support cleanup", f)
@@ -1049,7 +1135,7 @@ func CleanUp(f fs.Fs) error {
 		fs.Logf(f, "Not running cleanup as --dry-run set")
 		return nil
 	}
-	return doCleanUp()
+	return doCleanUp(ctx)
 }
 
 // wrap a Reader and a Closer together into a ReadCloser
@@ -1066,13 +1152,13 @@ type readCloser struct {
 //
 // if count < 0 then it will be ignored
 // if count >= 0 then only that many characters will be output
-func Cat(f fs.Fs, w io.Writer, offset, count int64) error {
+func Cat(ctx context.Context, f fs.Fs, w io.Writer, offset, count int64) error {
 	var mu sync.Mutex
-	return ListFn(f, func(o fs.Object) {
+	return ListFn(ctx, f, func(o fs.Object) {
 		var err error
-		accounting.Stats.Transferring(o.Remote())
+		accounting.GetStats(ctx).Transferring(o.Remote())
 		defer func() {
-			accounting.Stats.DoneTransferring(o.Remote(), err == nil)
+			accounting.GetStats(ctx).DoneTransferring(o.Remote(), err == nil)
 		}()
 		opt := fs.RangeOption{Start: offset, End: -1}
 		size := o.Size()
@@ -1086,9 +1172,9 @@ func Cat(f fs.Fs, w io.Writer, offset, count int64) error {
 		if opt.Start > 0 || opt.End >= 0 {
 			options = append(options, &opt)
 		}
-		in, err := o.Open(options...)
+		in, err := o.Open(ctx, options...)
 		if err != nil {
-			fs.CountError(err)
+			fs.CountError(ctx, err)
 			fs.Errorf(o, "Failed to open: %v", err)
 			return
 		}
@@ -1099,11 +1185,11 @@ func Cat(f fs.Fs, w io.Writer, offset, count int64) error {
 				size = count
 			}
 		}
-		in = accounting.NewAccountSizeName(in, size, o.Remote()).WithBuffer() // account and buffer the transfer
+		in = accounting.GetStats(ctx).NewAccountSizeName(in, size, o.Remote()).WithBuffer() // account and buffer the transfer
 		defer func() {
 			err = in.Close()
 			if err != nil {
-				fs.CountError(err)
+				fs.CountError(ctx, err)
 				fs.Errorf(o, "Failed to close: %v", err)
 			}
 		}()
@@ -1112,18 +1198,18 @@ func Cat(f fs.Fs, w io.Writer, offset, count int64) error {
 		defer mu.Unlock()
 		_, err = io.Copy(w, in)
 		if err != nil {
-			fs.CountError(err)
+			fs.CountError(ctx, err)
 			fs.Errorf(o, "Failed to send to output: %v", err)
 		}
 	})
 }
 
 // Rcat reads data from the Reader until EOF and uploads it to a file on remote
-func Rcat(fdst fs.Fs, dstFileName string, in io.ReadCloser, modTime time.Time) (dst fs.Object, err error) {
-	accounting.Stats.Transferring(dstFileName)
-	in = accounting.NewAccountSizeName(in, -1, dstFileName).WithBuffer()
+func Rcat(ctx context.Context, fdst fs.Fs, dstFileName string, in io.ReadCloser, modTime time.Time) (dst fs.Object, err error) {
+	accounting.GetStats(ctx).Transferring(dstFileName)
+	in = accounting.GetStats(ctx).NewAccountSizeName(in, -1, dstFileName).WithBuffer()
 	defer func() {
-		accounting.Stats.DoneTransferring(dstFileName, err == nil)
+		accounting.GetStats(ctx).DoneTransferring(dstFileName, err == nil)
 		if otherErr := in.Close(); otherErr != nil {
 			fs.Debugf(fdst, "Rcat: failed to close source: %v", err)
 		}
@@ -1139,9 +1225,9 @@ func Rcat(fdst fs.Fs, dstFileName string, in io.ReadCloser, modTime time.Time) (
 
 	compare := func(dst fs.Object) error {
 		src := object.NewStaticObjectInfo(dstFileName, modTime, int64(readCounter.BytesRead()), false, hash.Sums(), fdst)
-		if !Equal(src, dst) {
+		if !Equal(ctx, src, dst) {
 			err = errors.Errorf("corrupted on transfer")
-			fs.CountError(err)
+			fs.CountError(ctx, err)
 			fs.Errorf(dst, "%v", err)
 			return err
 		}
@@ -1153,7 +1239,7 @@ func Rcat(fdst fs.Fs, dstFileName string, in io.ReadCloser, modTime time.Time) (
 	if n, err := io.ReadFull(trackingIn, buf); err == io.EOF || err == io.ErrUnexpectedEOF {
 		fs.Debugf(fdst, "File to upload is small (%d bytes), uploading instead of streaming", n)
 		src := object.NewMemoryObject(dstFileName, modTime, buf[:n])
-		return Copy(fdst, nil, dstFileName, src)
+		return Copy(ctx, fdst, nil, dstFileName, src)
 	}
 
 	// Make a new ReadCloser with the bits we've already read
@@ -1171,7 +1257,7 @@ func Rcat(fdst fs.Fs, dstFileName string, in io.ReadCloser, modTime time.Time) (
 			return nil, errors.Wrap(err, "Failed to create temporary local FS to spool file")
 		}
 		defer func() {
-			err := Purge(tmpLocalFs, "")
+			err := Purge(ctx, tmpLocalFs, "")
 			if err != nil {
 				fs.Infof(tmpLocalFs, "Failed to cleanup temporary FS: %v", err)
 			}
@@ -1187,7 +1273,7 @@ func Rcat(fdst fs.Fs, dstFileName string, in io.ReadCloser, modTime time.Time) (
 	}
 
 	objInfo := object.NewStaticObjectInfo(dstFileName, modTime, -1, false, nil, nil)
-	if dst, err = fStreamTo.Features().PutStream(in, objInfo, hashOption); err != nil {
+	if dst, err = fStreamTo.Features().PutStream(ctx, in, objInfo, hashOption); err != nil {
 		return dst, err
 	}
 	if err = compare(dst); err != nil {
@@ -1195,28 +1281,28 @@ func Rcat(fdst fs.Fs, dstFileName string, in io.ReadCloser, modTime time.Time) (
 	}
 	if !canStream {
 		// copy dst (which is the local object we have just streamed to) to the remote
-		return Copy(fdst, nil, dstFileName, dst)
+		return Copy(ctx, fdst, nil, dstFileName, dst)
 	}
 	return dst, nil
 }
 
 // PublicLink adds a "readable by anyone with link" permission on the given file or folder.
-func PublicLink(f fs.Fs, remote string) (string, error) {
+func PublicLink(ctx context.Context, f fs.Fs, remote string) (string, error) {
 	doPublicLink := f.Features().PublicLink
 	if doPublicLink == nil {
 		return "", errors.Errorf("%v doesn't support public links", f)
 	}
-	return doPublicLink(remote)
+	return doPublicLink(ctx, remote)
 }
 
 // Rmdirs removes any empty directories (or directories only
 // containing empty directories) under f, including f.
-func Rmdirs(f fs.Fs, dir string, leaveRoot bool) error {
+func Rmdirs(ctx context.Context, f fs.Fs, dir string, leaveRoot bool) error {
 	dirEmpty := make(map[string]bool)
 	dirEmpty[dir] = !leaveRoot
-	err := walk.Walk(f, dir, true, fs.Config.MaxDepth, func(dirPath string, entries fs.DirEntries, err error) error {
+	err := walk.Walk(ctx, f, dir, true, fs.Config.MaxDepth, func(dirPath string, entries fs.DirEntries, err error) error {
 		if err != nil {
-			fs.CountError(err)
+			fs.CountError(ctx, err)
 			fs.Errorf(f, "Failed to list %q: %v", dirPath, err)
 			return nil
 		}
@@ -1261,9 +1347,9 @@ func Rmdirs(f fs.Fs, dir string, leaveRoot bool) error {
 	sort.Strings(toDelete)
 	for i := len(toDelete) - 1; i >= 0; i-- {
 		dir := toDelete[i]
-		err := TryRmdir(f, dir)
+		err := TryRmdir(ctx, f, dir)
 		if err != nil {
-			fs.CountError(err)
+			fs.CountError(ctx, err)
 			fs.Errorf(dir, "Failed to rmdir: %v", err)
 			return err
 		}
@@ -1276,7 +1362,7 @@ func Rmdirs(f fs.Fs, dir string, leaveRoot bool) error {
 //
 // Returns a flag which indicates whether the file needs to be
 // transferred or not.
-func NeedTransfer(dst, src fs.Object) bool {
+func NeedTransfer(ctx context.Context, dst, src fs.Object) bool {
 	if dst == nil {
 		fs.Debugf(src, "Couldn't find file - need to transfer")
 		return true
@@ -1319,7 +1405,7 @@ func NeedTransfer(dst, src fs.Object) bool {
 		}
 	} else {
 		// Check to see if changed or not
-		if Equal(src, dst) {
+		if Equal(ctx, src, dst) {
 			fs.Debugf(src, "Unchanged skipping")
 			return false
 		}
@@ -1327,27 +1413,117 @@ func NeedTransfer(dst, src fs.Object) bool {
 	return true
 }
 
+// GetCompareOrCopyDest makes the Fs for each --compare-dest or
+// --copy-dest directory, checking that files can be server side
+// copied from them into fdst if using --copy-dest.
+//
+// It returns nil if neither flag is in use.
+func GetCompareOrCopyDest(fdst fs.Fs) ([]fs.Fs, error) {
+	if len(fs.Config.CopyDest) > 0 {
+		return makeDestDirs(fdst, "--copy-dest", fs.Config.CopyDest, true)
+	}
+	return makeDestDirs(fdst, "--compare-dest", fs.Config.CompareDest, false)
+}
+
+// makeDestDirs makes the Fs for the directories passed to flag,
+// checking they don't overlap fdst and that they are on the same
+// remote if sameConfig is set
+func makeDestDirs(fdst fs.Fs, flag string, dirs []string, sameConfig bool) (out []fs.Fs, err error) {
+	for _, dir := range dirs {
+		f, err := fs.NewFs(dir)
+		if err != nil {
+			return nil, errors.Errorf("failed to make fs for %s %q: %v", flag, dir, err)
+		}
+		if sameConfig && !SameConfig(fdst, f) {
+			return nil, errors.Errorf("parameter to %s %q has to be on the same remote as destination", flag, dir)
+		}
+		if Overlapping(fdst, f) {
+			return nil, errors.Errorf("destination and parameter to %s %q mustn't overlap", flag, dir)
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+// CompareOrCopyDest looks for src in the --compare-dest or
+// --copy-dest directories passed in as compareOrCopyDest.
+//
+// With --compare-dest src doesn't need transferring if it is the same
+// as the file in any of them.  With --copy-dest the first matching
+// file is server side copied into fdst instead, moving dst into
+// backupDir first if set.
+//
+// Returns true if src doesn't need to be transferred.
+func CompareOrCopyDest(ctx context.Context, fdst fs.Fs, dst, src fs.Object, compareOrCopyDest []fs.Fs, backupDir fs.Fs) (noNeedTransfer bool, err error) {
+	remote := src.Remote()
+	if dst != nil {
+		remote = dst.Remote()
+	}
+	for _, dir := range compareOrCopyDest {
+		refObj, err := dir.NewObject(ctx, remote)
+		if err == fs.ErrorObjectNotFound {
+			continue
+		} else if err != nil {
+			return false, err
+		}
+		// Don't change the reference file if only the mod time differs
+		if !equal(ctx, src, refObj, fs.Config.SizeOnly, fs.Config.CheckSum, false) {
+			continue
+		}
+		if len(fs.Config.CopyDest) == 0 {
+			fs.Debugf(src, "Destination found in --compare-dest %v, skipping", dir)
+			return true, nil
+		}
+		return true, copyDest(ctx, fdst, dst, remote, refObj, backupDir)
+	}
+	return false, nil
+}
+
+// copyDest server side copies refObj from a --copy-dest directory to
+// remote in fdst unless dst is the same already
+func copyDest(ctx context.Context, fdst fs.Fs, dst fs.Object, remote string, refObj fs.Object, backupDir fs.Fs) (err error) {
+	if dst != nil && Equal(ctx, refObj, dst) {
+		fs.Debugf(dst, "Unchanged skipping")
+		return nil
+	}
+	if dst != nil && backupDir != nil {
+		err = MoveBackupDir(ctx, backupDir, dst)
+		if err != nil {
+			return errors.Wrap(err, "moving to --backup-dir failed")
+		}
+		dst = nil
+	}
+	accounting.GetStats(ctx).Transferring(remote)
+	_, err = Copy(ctx, fdst, dst, remote, refObj)
+	accounting.GetStats(ctx).DoneTransferring(remote, err == nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to copy from --copy-dest %v", refObj.Fs())
+	}
+	fs.Debugf(refObj, "Destination found in --copy-dest, copied")
+	return nil
+}
+
 // RcatSize reads data from the Reader until EOF and uploads it to a file on remote.
 // Pass in size >=0 if known, <0 if not known
-func RcatSize(fdst fs.Fs, dstFileName string, in io.ReadCloser, size int64, modTime time.Time) (dst fs.Object, err error) {
+func RcatSize(ctx context.Context, fdst fs.Fs, dstFileName string, in io.ReadCloser, size int64, modTime time.Time) (dst fs.Object, err error) {
 	var obj fs.Object
 
 	if size >= 0 {
 		// Size known use Put
-		accounting.Stats.Transferring(dstFileName)
-		body := ioutil.NopCloser(in)                                 // we let the server close the body
-		in := accounting.NewAccountSizeName(body, size, dstFileName) // account the transfer (no buffering)
+		accounting.GetStats(ctx).Transferring(dstFileName)
+		body := ioutil.NopCloser(in)                                               // we let the server close the body
+		in := accounting.GetStats(ctx).NewAccountSizeName(body, size, dstFileName) // account the transfer (no buffering)
 		var err error
 		defer func() {
 			closeErr := in.Close()
 			if closeErr != nil {
-				accounting.Stats.Error(closeErr)
+				accounting.GetStats(ctx).Error(closeErr)
 				fs.Errorf(dstFileName, "Post request: close failed: %v", closeErr)
 			}
-			accounting.Stats.DoneTransferring(dstFileName, err == nil)
+			accounting.GetStats(ctx).DoneTransferring(dstFileName, err == nil)
 		}()
 		info := object.NewStaticObjectInfo(dstFileName, modTime, size, true, nil, fdst)
-		obj, err = fdst.Put(in, info)
+		obj, err = fdst.Put(ctx, in, info)
 		if err != nil {
 			fs.Errorf(dstFileName, "Post request put error: %v", err)
 
@@ -1355,7 +1531,7 @@ func RcatSize(fdst fs.Fs, dstFileName string, in io.ReadCloser, size int64, modT
 		}
 	} else {
 		// Size unknown use Rcat
-		obj, err = Rcat(fdst, dstFileName, in, modTime)
+		obj, err = Rcat(ctx, fdst, dstFileName, in, modTime)
 		if err != nil {
 			fs.Errorf(dstFileName, "Post request rcat error: %v", err)
 
@@ -1367,17 +1543,17 @@ func RcatSize(fdst fs.Fs, dstFileName string, in io.ReadCloser, size int64, modT
 }
 
 // CopyURL copies the data from the url to (fdst, dstFileName)
-func CopyURL(fdst fs.Fs, dstFileName string, url string) (dst fs.Object, err error) {
+func CopyURL(ctx context.Context, fdst fs.Fs, dstFileName string, url string) (dst fs.Object, err error) {
 	resp, err := http.Get(url)
 	if err != nil {
 		return nil, err
 	}
 	defer fs.CheckClose(resp.Body, &err)
-	return RcatSize(fdst, dstFileName, resp.Body, resp.ContentLength, time.Now())
+	return RcatSize(ctx, fdst, dstFileName, resp.Body, resp.ContentLength, time.Now())
 }
 
 // moveOrCopyFile moves or copies a single file possibly to a new name
-func moveOrCopyFile(fdst fs.Fs, fsrc fs.Fs, dstFileName string, srcFileName string, cp bool) (err error) {
+func moveOrCopyFile(ctx context.Context, fdst fs.Fs, fsrc fs.Fs, dstFileName string, srcFileName string, cp bool) (err error) {
 	dstFilePath := path.Join(fdst.Root(), dstFileName)
 	srcFilePath := path.Join(fsrc.Root(), srcFileName)
 	if fdst.Name() == fsrc.Name() && dstFilePath == srcFilePath {
@@ -1392,52 +1568,61 @@ func moveOrCopyFile(fdst fs.Fs, fsrc fs.Fs, dstFileName string, srcFileName stri
 	}
 
 	// Find src object
-	srcObj, err := fsrc.NewObject(srcFileName)
+	srcObj, err := fsrc.NewObject(ctx, srcFileName)
 	if err != nil {
 		return err
 	}
 
 	// Find dst object if it exists
-	dstObj, err := fdst.NewObject(dstFileName)
+	dstObj, err := fdst.NewObject(ctx, dstFileName)
 	if err == fs.ErrorObjectNotFound {
 		dstObj = nil
 	} else if err != nil {
 		return err
 	}
 
-	if NeedTransfer(dstObj, srcObj) {
-		accounting.Stats.Transferring(srcFileName)
-		_, err = Op(fdst, dstObj, dstFileName, srcObj)
-		accounting.Stats.DoneTransferring(srcFileName, err == nil)
+	compareOrCopyDest, err := GetCompareOrCopyDest(fdst)
+	if err != nil {
+		return err
+	}
+	noNeedTransfer, err := CompareOrCopyDest(ctx, fdst, dstObj, srcObj, compareOrCopyDest, nil)
+	if err != nil {
+		return err
+	}
+
+	if !noNeedTransfer && NeedTransfer(ctx, dstObj, srcObj) {
+		accounting.GetStats(ctx).Transferring(srcFileName)
+		_, err = Op(ctx, fdst, dstObj, dstFileName, srcObj)
+		accounting.GetStats(ctx).DoneTransferring(srcFileName, err == nil)
 	} else {
-		accounting.Stats.Checking(srcFileName)
+		accounting.GetStats(ctx).Checking(srcFileName)
 		if !cp {
-			err = DeleteFile(srcObj)
+			err = DeleteFile(ctx, srcObj)
 		}
-		defer accounting.Stats.DoneChecking(srcFileName)
+		defer accounting.GetStats(ctx).DoneChecking(srcFileName)
 	}
 	return err
 }
 
 // MoveFile moves a single file possibly to a new name
-func MoveFile(fdst fs.Fs, fsrc fs.Fs, dstFileName string, srcFileName string) (err error) {
-	return moveOrCopyFile(fdst, fsrc, dstFileName, srcFileName, false)
+func MoveFile(ctx context.Context, fdst fs.Fs, fsrc fs.Fs, dstFileName string, srcFileName string) (err error) {
+	return moveOrCopyFile(ctx, fdst, fsrc, dstFileName, srcFileName, false)
 }
 
 // CopyFile moves a single file possibly to a new name
-func CopyFile(fdst fs.Fs, fsrc fs.Fs, dstFileName string, srcFileName string) (err error) {
-	return moveOrCopyFile(fdst, fsrc, dstFileName, srcFileName, true)
+func CopyFile(ctx context.Context, fdst fs.Fs, fsrc fs.Fs, dstFileName string, srcFileName string) (err error) {
+	return moveOrCopyFile(ctx, fdst, fsrc, dstFileName, srcFileName, true)
 }
 
 // SetTier changes tier of object in remote
-func SetTier(fsrc fs.Fs, tier string) error {
-	return ListFn(fsrc, func(o fs.Object) {
+func SetTier(ctx context.Context, fsrc fs.Fs, tier string) error {
+	return ListFn(ctx, fsrc, func(o fs.Object) {
 		objImpl, ok := o.(fs.SetTierer)
 		if !ok {
 			fs.Errorf(fsrc, "Remote object does not implement SetTier")
 			return
 		}
-		err := objImpl.SetTier(tier)
+		err := objImpl.SetTier(ctx, tier)
 		if err != nil {
 			fs.Errorf(fsrc, "Failed to do SetTier, %v", err)
 		}
@@ -1518,13 +1703,13 @@ func (l *ListFormat) AddPath() {
 }
 
 // AddHash adds the hash of the type given to the output
-func (l *ListFormat) AddHash(ht hash.Type) {
+func (l *ListFormat) AddHash(ctx context.Context, ht hash.Type) {
 	l.AppendOutput(func() string {
 		o, ok := l.entry.(fs.Object)
 		if !ok {
 			return ""
 		}
-		return hashSum(ht, o)
+		return hashSum(ctx, ht, false, o)
 	})
 }
 
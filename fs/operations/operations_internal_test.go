@@ -38,3 +38,38 @@ func TestSizeDiffers(t *testing.T) {
 		assert.Equal(t, test.want, got, fmt.Sprintf("ignoreSize=%v, srcSize=%v, dstSize=%v", test.ignoreSize, test.srcSize, test.dstSize))
 	}
 }
+
+func TestSuffixName(t *testing.T) {
+	defer func() {
+		fs.Config.Suffix = ""
+		fs.Config.SuffixKeepExtension = false
+	}()
+	for _, test := range []struct {
+		remote        string
+		suffix        string
+		keepExtension bool
+		want          string
+	}{
+		{"test.txt", "", false, "test.txt"},
+		{"test.txt", "", true, "test.txt"},
+		{"test.txt", "-suffix", false, "test.txt-suffix"},
+		{"test.txt", "-suffix", true, "test-suffix.txt"},
+		{"test.txt.csv", "-suffix", false, "test.txt.csv-suffix"},
+		{"test.txt.csv", "-suffix", true, "test.txt-suffix.csv"},
+		{"test", "-suffix", false, "test-suffix"},
+		{"test", "-suffix", true, "test-suffix"},
+		{"dir/test", ".bak", true, "dir/test.bak"},
+		{"dir/test.txt", ".bak", true, "dir/test.bak.txt"},
+	} {
+		fs.Config.Suffix = test.suffix
+		fs.Config.SuffixKeepExtension = test.keepExtension
+		got := SuffixName(test.remote)
+		assert.Equal(t, test.want, got, fmt.Sprintf("%+v", test))
+		back, ok := unSuffixName(got)
+		assert.True(t, ok, fmt.Sprintf("%+v", test))
+		assert.Equal(t, test.remote, back, fmt.Sprintf("%+v", test))
+	}
+	fs.Config.Suffix = ".bak"
+	_, ok := unSuffixName("test.txt")
+	assert.False(t, ok)
+}
@@ -21,13 +21,17 @@ package operations_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"regexp"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -57,18 +61,20 @@ func TestMain(m *testing.M) {
 }
 
 func TestMkdir(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
-	err := operations.Mkdir(r.Fremote, "")
+	err := operations.Mkdir(ctx, r.Fremote, "")
 	require.NoError(t, err)
 	fstest.CheckListing(t, r.Fremote, []fstest.Item{})
 
-	err = operations.Mkdir(r.Fremote, "")
+	err = operations.Mkdir(ctx, r.Fremote, "")
 	require.NoError(t, err)
 }
 
 func TestLsd(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteObject("sub dir/hello world", "hello world", t1)
@@ -76,13 +82,14 @@ func TestLsd(t *testing.T) {
 	fstest.CheckItems(t, r.Fremote, file1)
 
 	var buf bytes.Buffer
-	err := operations.ListDir(r.Fremote, &buf)
+	err := operations.ListDir(ctx, r.Fremote, &buf)
 	require.NoError(t, err)
 	res := buf.String()
 	assert.Contains(t, res, "sub dir\n")
 }
 
 func TestLs(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteBoth("potato2", "------------------------------------------------------------", t1)
@@ -91,7 +98,7 @@ func TestLs(t *testing.T) {
 	fstest.CheckItems(t, r.Fremote, file1, file2)
 
 	var buf bytes.Buffer
-	err := operations.List(r.Fremote, &buf)
+	err := operations.List(ctx, r.Fremote, &buf)
 	require.NoError(t, err)
 	res := buf.String()
 	assert.Contains(t, res, "        0 empty space\n")
@@ -99,6 +106,7 @@ func TestLs(t *testing.T) {
 }
 
 func TestLsWithFilesFrom(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteBoth("potato2", "------------------------------------------------------------", t1)
@@ -120,12 +128,13 @@ func TestLsWithFilesFrom(t *testing.T) {
 	}()
 
 	var buf bytes.Buffer
-	err = operations.List(r.Fremote, &buf)
+	err = operations.List(ctx, r.Fremote, &buf)
 	require.NoError(t, err)
 	assert.Equal(t, "       60 potato2\n", buf.String())
 }
 
 func TestLsLong(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteBoth("potato2", "------------------------------------------------------------", t1)
@@ -134,7 +143,7 @@ func TestLsLong(t *testing.T) {
 	fstest.CheckItems(t, r.Fremote, file1, file2)
 
 	var buf bytes.Buffer
-	err := operations.ListLong(r.Fremote, &buf)
+	err := operations.ListLong(ctx, r.Fremote, &buf)
 	require.NoError(t, err)
 	res := buf.String()
 	lines := strings.Split(strings.Trim(res, "\n"), "\n")
@@ -171,6 +180,7 @@ func TestLsLong(t *testing.T) {
 }
 
 func TestHashSums(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteBoth("potato2", "------------------------------------------------------------", t1)
@@ -181,7 +191,7 @@ func TestHashSums(t *testing.T) {
 	// MD5 Sum
 
 	var buf bytes.Buffer
-	err := operations.Md5sum(r.Fremote, &buf)
+	err := operations.Md5sum(ctx, r.Fremote, &buf)
 	require.NoError(t, err)
 	res := buf.String()
 	if !strings.Contains(res, "d41d8cd98f00b204e9800998ecf8427e  empty space\n") &&
@@ -198,7 +208,7 @@ func TestHashSums(t *testing.T) {
 	// SHA1 Sum
 
 	buf.Reset()
-	err = operations.Sha1sum(r.Fremote, &buf)
+	err = operations.Sha1sum(ctx, r.Fremote, &buf)
 	require.NoError(t, err)
 	res = buf.String()
 	if !strings.Contains(res, "da39a3ee5e6b4b0d3255bfef95601890afd80709  empty space\n") &&
@@ -215,7 +225,7 @@ func TestHashSums(t *testing.T) {
 	// Dropbox Hash Sum
 
 	buf.Reset()
-	err = operations.DropboxHashSum(r.Fremote, &buf)
+	err = operations.DropboxHashSum(ctx, r.Fremote, &buf)
 	require.NoError(t, err)
 	res = buf.String()
 	if !strings.Contains(res, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  empty space\n") &&
@@ -231,6 +241,7 @@ func TestHashSums(t *testing.T) {
 }
 
 func TestCount(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteBoth("potato2", "------------------------------------------------------------", t1)
@@ -243,13 +254,14 @@ func TestCount(t *testing.T) {
 	fs.Config.MaxDepth = 1
 	defer func() { fs.Config.MaxDepth = -1 }()
 
-	objects, size, err := operations.Count(r.Fremote)
+	objects, size, err := operations.Count(ctx, r.Fremote)
 	require.NoError(t, err)
 	assert.Equal(t, int64(2), objects)
 	assert.Equal(t, int64(60), size)
 }
 
 func TestDelete(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteObject("small", "1234567890", t2)                                                                                           // 10 bytes
@@ -262,19 +274,20 @@ func TestDelete(t *testing.T) {
 		filter.Active.Opt.MaxSize = -1
 	}()
 
-	err := operations.Delete(r.Fremote)
+	err := operations.Delete(ctx, r.Fremote)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Fremote, file3)
 }
 
-func testCheck(t *testing.T, checkFunction func(fdst, fsrc fs.Fs, oneway bool) error) {
+func testCheck(t *testing.T, checkFunction func(ctx context.Context, fdst, fsrc fs.Fs, oneway bool) error) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
 	check := func(i int, wantErrors int64, oneway bool) {
 		fs.Debugf(r.Fremote, "%d: Starting check test", i)
 		oldErrors := accounting.Stats.GetErrors()
-		err := checkFunction(r.Fremote, r.Flocal, oneway)
+		err := checkFunction(ctx, r.Fremote, r.Flocal, oneway)
 		gotErrors := accounting.Stats.GetErrors() - oldErrors
 		if wantErrors == 0 && err != nil {
 			t.Errorf("%d: Got error when not expecting one: %v", i, err)
@@ -335,6 +348,7 @@ func TestCheckSizeOnly(t *testing.T) {
 }
 
 func TestCat(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteBoth("file1", "ABCDEFGHIJ", t1)
@@ -354,7 +368,7 @@ func TestCat(t *testing.T) {
 		{1, 3, "BCD", "123"},
 	} {
 		var buf bytes.Buffer
-		err := operations.Cat(r.Fremote, &buf, test.offset, test.count)
+		err := operations.Cat(ctx, r.Fremote, &buf, test.offset, test.count)
 		require.NoError(t, err)
 		res := buf.String()
 
@@ -365,6 +379,7 @@ func TestCat(t *testing.T) {
 }
 
 func TestRcat(t *testing.T) {
+	ctx := context.Background()
 	checkSumBefore := fs.Config.CheckSum
 	defer func() { fs.Config.CheckSum = checkSumBefore }()
 
@@ -387,11 +402,11 @@ func TestRcat(t *testing.T) {
 		path2 := prefix + "big_file_from_pipe"
 
 		in := ioutil.NopCloser(strings.NewReader(data1))
-		_, err := operations.Rcat(r.Fremote, path1, in, t1)
+		_, err := operations.Rcat(ctx, r.Fremote, path1, in, t1)
 		require.NoError(t, err)
 
 		in = ioutil.NopCloser(strings.NewReader(data2))
-		_, err = operations.Rcat(r.Fremote, path2, in, t2)
+		_, err = operations.Rcat(ctx, r.Fremote, path2, in, t2)
 		require.NoError(t, err)
 
 		file1 := fstest.NewItem(path1, data1, t1)
@@ -404,6 +419,7 @@ func TestRcat(t *testing.T) {
 }
 
 func TestPurge(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRunIndividual(t) // make new container (azureblob has delayed mkdir after rmdir)
 	defer r.Finalise()
 	r.Mkdir(r.Fremote)
@@ -412,13 +428,13 @@ func TestPurge(t *testing.T) {
 	r.ForceMkdir(r.Fremote)
 	file1 := r.WriteObject("A1/B1/C1/one", "aaa", t1)
 	//..and dirs we expect to delete
-	require.NoError(t, operations.Mkdir(r.Fremote, "A2"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A1/B2"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A1/B2/C2"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A1/B1/C3"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A3"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A3/B3"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A3/B3/C4"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A2"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A1/B2"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A1/B2/C2"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A1/B1/C3"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A3"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A3/B3"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A3/B3/C4"))
 	//..and one more file at the end
 	file2 := r.WriteObject("A1/two", "bbb", t2)
 
@@ -443,7 +459,7 @@ func TestPurge(t *testing.T) {
 		fs.GetModifyWindow(r.Fremote),
 	)
 
-	require.NoError(t, operations.Purge(r.Fremote, "A1/B1"))
+	require.NoError(t, operations.Purge(ctx, r.Fremote, "A1/B1"))
 
 	fstest.CheckListingWithPrecision(
 		t,
@@ -463,7 +479,7 @@ func TestPurge(t *testing.T) {
 		fs.GetModifyWindow(r.Fremote),
 	)
 
-	require.NoError(t, operations.Purge(r.Fremote, ""))
+	require.NoError(t, operations.Purge(ctx, r.Fremote, ""))
 
 	fstest.CheckListingWithPrecision(
 		t,
@@ -476,6 +492,7 @@ func TestPurge(t *testing.T) {
 }
 
 func TestRmdirsNoLeaveRoot(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	r.Mkdir(r.Fremote)
@@ -484,13 +501,13 @@ func TestRmdirsNoLeaveRoot(t *testing.T) {
 	r.ForceMkdir(r.Fremote)
 	file1 := r.WriteObject("A1/B1/C1/one", "aaa", t1)
 	//..and dirs we expect to delete
-	require.NoError(t, operations.Mkdir(r.Fremote, "A2"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A1/B2"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A1/B2/C2"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A1/B1/C3"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A3"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A3/B3"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A3/B3/C4"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A2"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A1/B2"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A1/B2/C2"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A1/B1/C3"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A3"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A3/B3"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A3/B3/C4"))
 	//..and one more file at the end
 	file2 := r.WriteObject("A1/two", "bbb", t2)
 
@@ -515,7 +532,7 @@ func TestRmdirsNoLeaveRoot(t *testing.T) {
 		fs.GetModifyWindow(r.Fremote),
 	)
 
-	require.NoError(t, operations.Rmdirs(r.Fremote, "A3/B3/C4", false))
+	require.NoError(t, operations.Rmdirs(ctx, r.Fremote, "A3/B3/C4", false))
 
 	fstest.CheckListingWithPrecision(
 		t,
@@ -537,7 +554,7 @@ func TestRmdirsNoLeaveRoot(t *testing.T) {
 		fs.GetModifyWindow(r.Fremote),
 	)
 
-	require.NoError(t, operations.Rmdirs(r.Fremote, "", false))
+	require.NoError(t, operations.Rmdirs(ctx, r.Fremote, "", false))
 
 	fstest.CheckListingWithPrecision(
 		t,
@@ -556,15 +573,16 @@ func TestRmdirsNoLeaveRoot(t *testing.T) {
 }
 
 func TestRmdirsLeaveRoot(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	r.Mkdir(r.Fremote)
 
 	r.ForceMkdir(r.Fremote)
 
-	require.NoError(t, operations.Mkdir(r.Fremote, "A1"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A1/B1"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "A1/B1/C1"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A1"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A1/B1"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "A1/B1/C1"))
 
 	fstest.CheckListingWithPrecision(
 		t,
@@ -578,7 +596,7 @@ func TestRmdirsLeaveRoot(t *testing.T) {
 		fs.GetModifyWindow(r.Fremote),
 	)
 
-	require.NoError(t, operations.Rmdirs(r.Fremote, "A1", true))
+	require.NoError(t, operations.Rmdirs(ctx, r.Fremote, "A1", true))
 
 	fstest.CheckListingWithPrecision(
 		t,
@@ -592,6 +610,7 @@ func TestRmdirsLeaveRoot(t *testing.T) {
 }
 
 func TestRcatSize(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
@@ -600,7 +619,7 @@ func TestRcatSize(t *testing.T) {
 	file2 := r.WriteFile("potato2", body, t2)
 	// Test with known length
 	bodyReader := ioutil.NopCloser(strings.NewReader(body))
-	obj, err := operations.RcatSize(r.Fremote, file1.Path, bodyReader, int64(len(body)), file1.ModTime)
+	obj, err := operations.RcatSize(ctx, r.Fremote, file1.Path, bodyReader, int64(len(body)), file1.ModTime)
 	require.NoError(t, err)
 	assert.Equal(t, int64(len(body)), obj.Size())
 	assert.Equal(t, file1.Path, obj.Remote())
@@ -608,7 +627,7 @@ func TestRcatSize(t *testing.T) {
 	// Test with unknown length
 	bodyReader = ioutil.NopCloser(strings.NewReader(body)) // reset Reader
 	ioutil.NopCloser(strings.NewReader(body))
-	obj, err = operations.RcatSize(r.Fremote, file2.Path, bodyReader, -1, file2.ModTime)
+	obj, err = operations.RcatSize(ctx, r.Fremote, file2.Path, bodyReader, -1, file2.ModTime)
 	require.NoError(t, err)
 	assert.Equal(t, int64(len(body)), obj.Size())
 	assert.Equal(t, file2.Path, obj.Remote())
@@ -618,6 +637,7 @@ func TestRcatSize(t *testing.T) {
 }
 
 func TestCopyURL(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
@@ -632,7 +652,7 @@ func TestCopyURL(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	o, err := operations.CopyURL(r.Fremote, "file1", ts.URL)
+	o, err := operations.CopyURL(ctx, r.Fremote, "file1", ts.URL)
 	require.NoError(t, err)
 	assert.Equal(t, int64(len(contents)), o.Size())
 
@@ -640,6 +660,7 @@ func TestCopyURL(t *testing.T) {
 }
 
 func TestMoveFile(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
@@ -649,7 +670,7 @@ func TestMoveFile(t *testing.T) {
 	file2 := file1
 	file2.Path = "sub/file2"
 
-	err := operations.MoveFile(r.Fremote, r.Flocal, file2.Path, file1.Path)
+	err := operations.MoveFile(ctx, r.Fremote, r.Flocal, file2.Path, file1.Path)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal)
 	fstest.CheckItems(t, r.Fremote, file2)
@@ -657,18 +678,19 @@ func TestMoveFile(t *testing.T) {
 	r.WriteFile("file1", "file1 contents", t1)
 	fstest.CheckItems(t, r.Flocal, file1)
 
-	err = operations.MoveFile(r.Fremote, r.Flocal, file2.Path, file1.Path)
+	err = operations.MoveFile(ctx, r.Fremote, r.Flocal, file2.Path, file1.Path)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal)
 	fstest.CheckItems(t, r.Fremote, file2)
 
-	err = operations.MoveFile(r.Fremote, r.Fremote, file2.Path, file2.Path)
+	err = operations.MoveFile(ctx, r.Fremote, r.Fremote, file2.Path, file2.Path)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal)
 	fstest.CheckItems(t, r.Fremote, file2)
 }
 
 func TestCopyFile(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
@@ -678,22 +700,96 @@ func TestCopyFile(t *testing.T) {
 	file2 := file1
 	file2.Path = "sub/file2"
 
-	err := operations.CopyFile(r.Fremote, r.Flocal, file2.Path, file1.Path)
+	err := operations.CopyFile(ctx, r.Fremote, r.Flocal, file2.Path, file1.Path)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file1)
 	fstest.CheckItems(t, r.Fremote, file2)
 
-	err = operations.CopyFile(r.Fremote, r.Flocal, file2.Path, file1.Path)
+	err = operations.CopyFile(ctx, r.Fremote, r.Flocal, file2.Path, file1.Path)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file1)
 	fstest.CheckItems(t, r.Fremote, file2)
 
-	err = operations.CopyFile(r.Fremote, r.Fremote, file2.Path, file2.Path)
+	err = operations.CopyFile(ctx, r.Fremote, r.Fremote, file2.Path, file2.Path)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file1)
 	fstest.CheckItems(t, r.Fremote, file2)
 }
 
+func TestCopyFileCompareOrCopyDest(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	defer func() {
+		fs.Config.CompareDest = nil
+		fs.Config.CopyDest = nil
+	}()
+
+	file1 := r.WriteFile("file1", "file1 contents", t1)
+	fstest.CheckItems(t, r.Flocal, file1)
+	ref := r.WriteObject("ref/file1", "file1 contents", t1)
+	fstest.CheckItems(t, r.Fremote, ref)
+
+	fdst, err := fs.NewFs(r.FremoteName + "/dst")
+	require.NoError(t, err)
+
+	// With --compare-dest the file isn't copied
+	fs.Config.CompareDest = []string{r.FremoteName + "/ref"}
+	err = operations.CopyFile(ctx, fdst, r.Flocal, file1.Path, file1.Path)
+	require.NoError(t, err)
+	fstest.CheckItems(t, r.Fremote, ref)
+
+	// With --copy-dest it is copied from the reference
+	fs.Config.CompareDest = nil
+	fs.Config.CopyDest = []string{r.FremoteName + "/ref"}
+	err = operations.CopyFile(ctx, fdst, r.Flocal, file1.Path, file1.Path)
+	require.NoError(t, err)
+	file2 := ref
+	file2.Path = "dst/file1"
+	fstest.CheckItems(t, r.Fremote, ref, file2)
+
+	// Overlapping the destination is an error
+	fs.Config.CopyDest = []string{r.FremoteName + "/dst/ref"}
+	err = operations.CopyFile(ctx, fdst, r.Flocal, file1.Path, file1.Path)
+	assert.Error(t, err)
+}
+
+func TestCopyFileMetadata(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	if !r.Fremote.Features().WriteMetadata {
+		t.Skip("Can't write metadata")
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("Can't set file mode on windows")
+	}
+	defer func() { fs.Config.Metadata = false }()
+
+	file1 := r.WriteFile("file1", "file1 contents", t1)
+	err := os.Chmod(filepath.Join(r.LocalName, file1.Path), 0600)
+	require.NoError(t, err)
+
+	// Without --metadata the mode isn't preserved
+	err = operations.CopyFile(ctx, r.Fremote, r.Flocal, "file2", file1.Path)
+	require.NoError(t, err)
+	o, err := r.Fremote.NewObject(ctx, "file2")
+	require.NoError(t, err)
+	metadata, err := fs.GetMetadata(ctx, o)
+	require.NoError(t, err)
+	assert.NotEqual(t, "600", metadata["mode"])
+
+	// With --metadata it is
+	fs.Config.Metadata = true
+	err = operations.CopyFile(ctx, r.Fremote, r.Flocal, "file3", file1.Path)
+	require.NoError(t, err)
+	o, err = r.Fremote.NewObject(ctx, "file3")
+	require.NoError(t, err)
+	metadata, err = fs.GetMetadata(ctx, o)
+	require.NoError(t, err)
+	assert.Equal(t, "600", metadata["mode"])
+}
+
 // testFsInfo is for unit testing fs.Info
 type testFsInfo struct {
 	name      string
@@ -859,6 +955,7 @@ func TestCheckEqualReaders(t *testing.T) {
 }
 
 func TestListFormat(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteObject("a", "a", t1)
@@ -866,7 +963,7 @@ func TestListFormat(t *testing.T) {
 
 	fstest.CheckItems(t, r.Fremote, file1, file2)
 
-	items, _ := list.DirSorted(r.Fremote, true, "")
+	items, _ := list.DirSorted(ctx, r.Fremote, true, "")
 	var list operations.ListFormat
 	list.AddPath()
 	list.SetDirSlash(false)
@@ -924,7 +1021,7 @@ func TestListFormat(t *testing.T) {
 		{hash.Dropbox, "bf5d3affb73efd2ec6c36ad3112dd933efed63c4e1cbffcfa88e2759c144f2d8"},
 	} {
 		list.SetOutput(nil)
-		list.AddHash(test.ht)
+		list.AddHash(ctx, test.ht)
 		got := list.Format(items[0])
 		if got != "UNSUPPORTED" && got != "" {
 			assert.Equal(t, test.want, got)
@@ -1,6 +1,7 @@
 package operations
 
 import (
+	"context"
 	"strings"
 
 	"github.com/ncw/rclone/fs"
@@ -36,7 +37,7 @@ See the lsjson command for more information on the above and examples.
 }
 
 // List the directory
-func rcList(in rc.Params) (out rc.Params, err error) {
+func rcList(ctx context.Context, in rc.Params) (out rc.Params, err error) {
 	f, remote, err := rc.GetFsAndRemote(in)
 	if err != nil {
 		return nil, err
@@ -47,7 +48,7 @@ func rcList(in rc.Params) (out rc.Params, err error) {
 		return nil, err
 	}
 	var list = []*ListJSONItem{}
-	err = ListJSON(f, remote, &opt, func(item *ListJSONItem) error {
+	err = ListJSON(ctx, f, remote, &opt, func(item *ListJSONItem) error {
 		list = append(list, item)
 		return nil
 	})
@@ -76,7 +77,7 @@ The result is as returned from rclone about --json
 }
 
 // About the remote
-func rcAbout(in rc.Params) (out rc.Params, err error) {
+func rcAbout(ctx context.Context, in rc.Params) (out rc.Params, err error) {
 	f, err := rc.GetFs(in)
 	if err != nil {
 		return nil, err
@@ -85,7 +86,7 @@ func rcAbout(in rc.Params) (out rc.Params, err error) {
 	if doAbout == nil {
 		return nil, errors.Errorf("%v doesn't support about", f)
 	}
-	u, err := doAbout()
+	u, err := doAbout(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "about call failed")
 	}
@@ -106,8 +107,8 @@ func init() {
 		rc.Add(rc.Call{
 			Path:         "operations/" + strings.ToLower(name) + "file",
 			AuthRequired: true,
-			Fn: func(in rc.Params) (rc.Params, error) {
-				return rcMoveOrCopyFile(in, copy)
+			Fn: func(ctx context.Context, in rc.Params) (rc.Params, error) {
+				return rcMoveOrCopyFile(ctx, in, copy)
 			},
 			Title: name + " a file from source remote to destination remote",
 			Help: `This takes the following parameters
@@ -122,7 +123,7 @@ func init() {
 }
 
 // Copy a file
-func rcMoveOrCopyFile(in rc.Params, cp bool) (out rc.Params, err error) {
+func rcMoveOrCopyFile(ctx context.Context, in rc.Params, cp bool) (out rc.Params, err error) {
 	srcFs, srcRemote, err := rc.GetFsAndRemoteNamed(in, "srcFs", "srcRemote")
 	if err != nil {
 		return nil, err
@@ -131,7 +132,7 @@ func rcMoveOrCopyFile(in rc.Params, cp bool) (out rc.Params, err error) {
 	if err != nil {
 		return nil, err
 	}
-	return nil, moveOrCopyFile(dstFs, srcFs, dstRemote, srcRemote, cp)
+	return nil, moveOrCopyFile(ctx, dstFs, srcFs, dstRemote, srcRemote, cp)
 }
 
 func init() {
@@ -158,8 +159,8 @@ func init() {
 		rc.Add(rc.Call{
 			Path:         "operations/" + op.name,
 			AuthRequired: true,
-			Fn: func(in rc.Params) (rc.Params, error) {
-				return rcSingleCommand(in, op.name, op.noRemote)
+			Fn: func(ctx context.Context, in rc.Params) (rc.Params, error) {
+				return rcSingleCommand(ctx, in, op.name, op.noRemote)
 			},
 			Title: op.title,
 			Help: `This takes the following parameters
@@ -173,7 +174,7 @@ See the [` + op.name + ` command](/commands/rclone_` + op.name + `/) command for
 }
 
 // Mkdir a directory
-func rcSingleCommand(in rc.Params, name string, noRemote bool) (out rc.Params, err error) {
+func rcSingleCommand(ctx context.Context, in rc.Params, name string, noRemote bool) (out rc.Params, err error) {
 	var (
 		f      fs.Fs
 		remote string
@@ -188,34 +189,34 @@ func rcSingleCommand(in rc.Params, name string, noRemote bool) (out rc.Params, e
 	}
 	switch name {
 	case "mkdir":
-		return nil, Mkdir(f, remote)
+		return nil, Mkdir(ctx, f, remote)
 	case "rmdir":
-		return nil, Rmdir(f, remote)
+		return nil, Rmdir(ctx, f, remote)
 	case "purge":
-		return nil, Purge(f, remote)
+		return nil, Purge(ctx, f, remote)
 	case "rmdirs":
 		leaveRoot, err := in.GetBool("leaveRoot")
 		if rc.NotErrParamNotFound(err) {
 			return nil, err
 		}
-		return nil, Rmdirs(f, remote, leaveRoot)
+		return nil, Rmdirs(ctx, f, remote, leaveRoot)
 	case "delete":
-		return nil, Delete(f)
+		return nil, Delete(ctx, f)
 	case "deletefile":
-		o, err := f.NewObject(remote)
+		o, err := f.NewObject(ctx, remote)
 		if err != nil {
 			return nil, err
 		}
-		return nil, DeleteFile(o)
+		return nil, DeleteFile(ctx, o)
 	case "copyurl":
 		url, err := in.GetString("url")
 		if err != nil {
 			return nil, err
 		}
-		_, err = CopyURL(f, remote, url)
+		_, err = CopyURL(ctx, f, remote, url)
 		return nil, err
 	case "cleanup":
-		return nil, CleanUp(f)
+		return nil, CleanUp(ctx, f)
 	}
 	panic("unknown rcSingleCommand type")
 }
@@ -241,12 +242,12 @@ See the [size command](/commands/rclone_size/) command for more information on t
 }
 
 // Mkdir a directory
-func rcSize(in rc.Params) (out rc.Params, err error) {
+func rcSize(ctx context.Context, in rc.Params) (out rc.Params, err error) {
 	f, err := rc.GetFs(in)
 	if err != nil {
 		return nil, err
 	}
-	count, bytes, err := Count(f)
+	count, bytes, err := Count(ctx, f)
 	if err != nil {
 		return nil, err
 	}
@@ -255,3 +256,87 @@ func rcSize(in rc.Params) (out rc.Params, err error) {
 	out["bytes"] = bytes
 	return out, nil
 }
+
+func init() {
+	rc.Add(rc.Call{
+		Path:         "backend/command",
+		AuthRequired: true,
+		Fn:           rcBackend,
+		Title:        "Runs a backend command.",
+		Help: `This takes the following parameters
+
+- command - a string with the command name
+- fs - a remote name string eg "drive:"
+- arg - a list of arguments for the backend command
+- opt - a map of string to string of options
+
+Returns
+
+- result - result from the backend command
+
+For example
+
+    rclone rc backend/command command=noop fs=. -o echo=yes -o blue -a path1 -a path2
+
+Returns
+
+` + "```" + `
+{
+	"result": {
+		"arg": [
+			"path1",
+			"path2"
+		],
+		"name": "noop",
+		"opt": {
+			"blue": "",
+			"echo": "yes"
+		}
+	}
+}
+` + "```" + `
+
+Note that this is the direct equivalent of using this "backend"
+command:
+
+    rclone backend noop . -o echo=yes -o blue path1 path2
+
+Note that arguments must be preceded by the "-a" flag
+
+See the [backend](/commands/rclone_backend/) command for more information.
+`,
+	})
+}
+
+// Run a backend command
+func rcBackend(ctx context.Context, in rc.Params) (out rc.Params, err error) {
+	f, err := rc.GetFs(in)
+	if err != nil {
+		return nil, err
+	}
+	doCommand := f.Features().Command
+	if doCommand == nil {
+		return nil, errors.Errorf("%v: doesn't support backend commands", f)
+	}
+	command, err := in.GetString("command")
+	if err != nil {
+		return nil, err
+	}
+	var opt = map[string]string{}
+	err = in.GetStruct("opt", &opt)
+	if rc.NotErrParamNotFound(err) {
+		return nil, err
+	}
+	var arg = []string{}
+	err = in.GetStruct("arg", &arg)
+	if rc.NotErrParamNotFound(err) {
+		return nil, err
+	}
+	result, err := doCommand(ctx, command, arg, opt)
+	if err != nil {
+		return nil, errors.Wrapf(err, "command %q failed", command)
+	}
+	out = make(rc.Params)
+	out["result"] = result
+	return out, nil
+}
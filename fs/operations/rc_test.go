@@ -1,6 +1,7 @@
 package operations_test
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -28,6 +29,7 @@ func rcNewRun(t *testing.T, method string) (*fstest.Run, *rc.Call) {
 
 // operations/about: Return the space used on the remote
 func TestRcAbout(t *testing.T) {
+	ctx := context.Background()
 	r, call := rcNewRun(t, "operations/about")
 	defer r.Finalise()
 	r.Mkdir(r.Fremote)
@@ -38,7 +40,7 @@ func TestRcAbout(t *testing.T) {
 	in := rc.Params{
 		"fs": r.FremoteName,
 	}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	if expectedErr {
 		assert.Error(t, err)
 		return
@@ -51,13 +53,14 @@ func TestRcAbout(t *testing.T) {
 
 // operations/cleanup: Remove trashed files in the remote or path
 func TestRcCleanup(t *testing.T) {
+	ctx := context.Background()
 	r, call := rcNewRun(t, "operations/cleanup")
 	defer r.Finalise()
 
 	in := rc.Params{
 		"fs": r.LocalName,
 	}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.Error(t, err)
 	assert.Equal(t, rc.Params(nil), out)
 	assert.Contains(t, err.Error(), "doesn't support cleanup")
@@ -65,6 +68,7 @@ func TestRcCleanup(t *testing.T) {
 
 // operations/copyfile: Copy a file from source remote to destination remote
 func TestRcCopyfile(t *testing.T) {
+	ctx := context.Background()
 	r, call := rcNewRun(t, "operations/copyfile")
 	defer r.Finalise()
 	file1 := r.WriteFile("file1", "file1 contents", t1)
@@ -78,7 +82,7 @@ func TestRcCopyfile(t *testing.T) {
 		"dstFs":     r.FremoteName,
 		"dstRemote": "file1-renamed",
 	}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	assert.Equal(t, rc.Params(nil), out)
 
@@ -89,6 +93,7 @@ func TestRcCopyfile(t *testing.T) {
 
 // operations/copyurl: Copy the URL to the object
 func TestRcCopyurl(t *testing.T) {
+	ctx := context.Background()
 	r, call := rcNewRun(t, "operations/copyurl")
 	defer r.Finalise()
 	contents := "file1 contents\n"
@@ -107,7 +112,7 @@ func TestRcCopyurl(t *testing.T) {
 		"remote": "file1",
 		"url":    ts.URL,
 	}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	assert.Equal(t, rc.Params(nil), out)
 
@@ -116,6 +121,7 @@ func TestRcCopyurl(t *testing.T) {
 
 // operations/delete: Remove files in the path
 func TestRcDelete(t *testing.T) {
+	ctx := context.Background()
 	r, call := rcNewRun(t, "operations/delete")
 	defer r.Finalise()
 
@@ -127,7 +133,7 @@ func TestRcDelete(t *testing.T) {
 	in := rc.Params{
 		"fs": r.FremoteName,
 	}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	assert.Equal(t, rc.Params(nil), out)
 
@@ -136,6 +142,7 @@ func TestRcDelete(t *testing.T) {
 
 // operations/deletefile: Remove the single file pointed to
 func TestRcDeletefile(t *testing.T) {
+	ctx := context.Background()
 	r, call := rcNewRun(t, "operations/deletefile")
 	defer r.Finalise()
 
@@ -147,7 +154,7 @@ func TestRcDeletefile(t *testing.T) {
 		"fs":     r.FremoteName,
 		"remote": "small",
 	}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	assert.Equal(t, rc.Params(nil), out)
 
@@ -156,6 +163,7 @@ func TestRcDeletefile(t *testing.T) {
 
 // operations/list: List the given remote and path in JSON format
 func TestRcList(t *testing.T) {
+	ctx := context.Background()
 	r, call := rcNewRun(t, "operations/list")
 	defer r.Finalise()
 
@@ -168,7 +176,7 @@ func TestRcList(t *testing.T) {
 		"fs":     r.FremoteName,
 		"remote": "",
 	}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 
 	list := out["list"].([]*operations.ListJSONItem)
@@ -200,7 +208,7 @@ func TestRcList(t *testing.T) {
 			"recurse": true,
 		},
 	}
-	out, err = call.Fn(in)
+	out, err = call.Fn(ctx, in)
 	require.NoError(t, err)
 
 	list = out["list"].([]*operations.ListJSONItem)
@@ -221,6 +229,7 @@ func TestRcList(t *testing.T) {
 
 // operations/mkdir: Make a destination directory or container
 func TestRcMkdir(t *testing.T) {
+	ctx := context.Background()
 	r, call := rcNewRun(t, "operations/mkdir")
 	defer r.Finalise()
 	r.Mkdir(r.Fremote)
@@ -231,7 +240,7 @@ func TestRcMkdir(t *testing.T) {
 		"fs":     r.FremoteName,
 		"remote": "subdir",
 	}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	assert.Equal(t, rc.Params(nil), out)
 
@@ -240,6 +249,7 @@ func TestRcMkdir(t *testing.T) {
 
 // operations/movefile: Move a file from source remote to destination remote
 func TestRcMovefile(t *testing.T) {
+	ctx := context.Background()
 	r, call := rcNewRun(t, "operations/movefile")
 	defer r.Finalise()
 	file1 := r.WriteFile("file1", "file1 contents", t1)
@@ -253,7 +263,7 @@ func TestRcMovefile(t *testing.T) {
 		"dstFs":     r.FremoteName,
 		"dstRemote": "file1-renamed",
 	}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	assert.Equal(t, rc.Params(nil), out)
 
@@ -264,6 +274,7 @@ func TestRcMovefile(t *testing.T) {
 
 // operations/purge: Remove a directory or container and all of its contents
 func TestRcPurge(t *testing.T) {
+	ctx := context.Background()
 	r, call := rcNewRun(t, "operations/purge")
 	defer r.Finalise()
 	file1 := r.WriteObject("subdir/file1", "subdir/file1 contents", t1)
@@ -274,7 +285,7 @@ func TestRcPurge(t *testing.T) {
 		"fs":     r.FremoteName,
 		"remote": "subdir",
 	}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	assert.Equal(t, rc.Params(nil), out)
 
@@ -283,10 +294,11 @@ func TestRcPurge(t *testing.T) {
 
 // operations/rmdir: Remove an empty directory or container
 func TestRcRmdir(t *testing.T) {
+	ctx := context.Background()
 	r, call := rcNewRun(t, "operations/rmdir")
 	defer r.Finalise()
 	r.Mkdir(r.Fremote)
-	assert.NoError(t, r.Fremote.Mkdir("subdir"))
+	assert.NoError(t, r.Fremote.Mkdir(ctx, "subdir"))
 
 	fstest.CheckListingWithPrecision(t, r.Fremote, []fstest.Item{}, []string{"subdir"}, fs.GetModifyWindow(r.Fremote))
 
@@ -294,7 +306,7 @@ func TestRcRmdir(t *testing.T) {
 		"fs":     r.FremoteName,
 		"remote": "subdir",
 	}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	assert.Equal(t, rc.Params(nil), out)
 
@@ -303,11 +315,12 @@ func TestRcRmdir(t *testing.T) {
 
 // operations/rmdirs: Remove all the empty directories in the path
 func TestRcRmdirs(t *testing.T) {
+	ctx := context.Background()
 	r, call := rcNewRun(t, "operations/rmdirs")
 	defer r.Finalise()
 	r.Mkdir(r.Fremote)
-	assert.NoError(t, r.Fremote.Mkdir("subdir"))
-	assert.NoError(t, r.Fremote.Mkdir("subdir/subsubdir"))
+	assert.NoError(t, r.Fremote.Mkdir(ctx, "subdir"))
+	assert.NoError(t, r.Fremote.Mkdir(ctx, "subdir/subsubdir"))
 
 	fstest.CheckListingWithPrecision(t, r.Fremote, []fstest.Item{}, []string{"subdir", "subdir/subsubdir"}, fs.GetModifyWindow(r.Fremote))
 
@@ -315,21 +328,21 @@ func TestRcRmdirs(t *testing.T) {
 		"fs":     r.FremoteName,
 		"remote": "subdir",
 	}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	assert.Equal(t, rc.Params(nil), out)
 
 	fstest.CheckListingWithPrecision(t, r.Fremote, []fstest.Item{}, []string{}, fs.GetModifyWindow(r.Fremote))
 
-	assert.NoError(t, r.Fremote.Mkdir("subdir"))
-	assert.NoError(t, r.Fremote.Mkdir("subdir/subsubdir"))
+	assert.NoError(t, r.Fremote.Mkdir(ctx, "subdir"))
+	assert.NoError(t, r.Fremote.Mkdir(ctx, "subdir/subsubdir"))
 
 	in = rc.Params{
 		"fs":        r.FremoteName,
 		"remote":    "subdir",
 		"leaveRoot": true,
 	}
-	out, err = call.Fn(in)
+	out, err = call.Fn(ctx, in)
 	require.NoError(t, err)
 	assert.Equal(t, rc.Params(nil), out)
 
@@ -339,6 +352,7 @@ func TestRcRmdirs(t *testing.T) {
 
 // operations/size: Count the number of bytes and files in remote
 func TestRcSize(t *testing.T) {
+	ctx := context.Background()
 	r, call := rcNewRun(t, "operations/size")
 	defer r.Finalise()
 	file1 := r.WriteObject("small", "1234567890", t2)                                                           // 10 bytes
@@ -349,10 +363,49 @@ func TestRcSize(t *testing.T) {
 	in := rc.Params{
 		"fs": r.FremoteName,
 	}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	assert.Equal(t, rc.Params{
 		"count": int64(3),
 		"bytes": int64(120),
 	}, out)
 }
+
+// backend/command: Runs a backend command
+func TestRcBackend(t *testing.T) {
+	ctx := context.Background()
+	r, call := rcNewRun(t, "backend/command")
+	defer r.Finalise()
+
+	in := rc.Params{
+		"fs":      r.LocalName,
+		"command": "noop",
+		"arg":     []string{"path1", "path2"},
+		"opt": map[string]string{
+			"echo": "yes",
+			"blue": "",
+		},
+	}
+	out, err := call.Fn(ctx, in)
+	require.NoError(t, err)
+	assert.Equal(t, rc.Params{
+		"result": map[string]interface{}{
+			"name": "noop",
+			"arg":  []string{"path1", "path2"},
+			"opt": map[string]string{
+				"echo": "yes",
+				"blue": "",
+			},
+		},
+	}, out)
+
+	in["opt"] = map[string]string{"error": "potato"}
+	_, err = call.Fn(ctx, in)
+	require.Error(t, err)
+	assert.Equal(t, `command "noop" failed: potato`, err.Error())
+
+	in["command"] = "potato"
+	_, err = call.Fn(ctx, in)
+	require.Error(t, err)
+	assert.Equal(t, `command "potato" failed: command not found`, err.Error())
+}
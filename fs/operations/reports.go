@@ -0,0 +1,104 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ReportKind is the category of a file in a difference report.  Its
+// value is the marker character used in the combined report.
+type ReportKind byte
+
+// Kinds of report
+const (
+	ReportMatch        ReportKind = '=' // file is identical in source and destination
+	ReportMissingOnSrc ReportKind = '-' // file is only in the destination
+	ReportMissingOnDst ReportKind = '+' // file is only in the source
+	ReportDiffer       ReportKind = '*' // file is in both but differs
+	ReportError        ReportKind = '!' // there was an error checking or transferring the file
+)
+
+// Reports are the optional files that check and sync write the
+// paths of the files they looked at into, one per line, according
+// to what they found.  Any of the writers may be nil.
+type Reports struct {
+	Combined     io.Writer // all files with the ReportKind marker and a space before them
+	Match        io.Writer // ReportMatch files
+	MissingOnSrc io.Writer // ReportMissingOnSrc files
+	MissingOnDst io.Writer // ReportMissingOnDst files
+	Differ       io.Writer // ReportDiffer files
+	Error        io.Writer // ReportError files
+
+	mu  sync.Mutex // serialise writes
+	err error      // first error writing a report
+}
+
+// Report writes remote to the reports for kind.  It is safe to call
+// on a nil *Reports and from multiple go routines.
+func (r *Reports) Report(kind ReportKind, remote string) {
+	if r == nil {
+		return
+	}
+	var w io.Writer
+	switch kind {
+	case ReportMatch:
+		w = r.Match
+	case ReportMissingOnSrc:
+		w = r.MissingOnSrc
+	case ReportMissingOnDst:
+		w = r.MissingOnDst
+	case ReportDiffer:
+		w = r.Differ
+	case ReportError:
+		w = r.Error
+	default:
+		panic(fmt.Sprintf("unknown report kind %q", kind))
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if w != nil {
+		_, err := fmt.Fprintln(w, remote)
+		r.setErr(err)
+	}
+	if r.Combined != nil {
+		_, err := fmt.Fprintf(r.Combined, "%c %s\n", kind, remote)
+		r.setErr(err)
+	}
+}
+
+// setErr records err if it is the first error - call with mu held
+func (r *Reports) setErr(err error) {
+	if err != nil && r.err == nil {
+		r.err = errors.Wrap(err, "failed to write report")
+	}
+}
+
+// Err returns the first error writing the reports, if any.  It is
+// safe to call on a nil *Reports.
+func (r *Reports) Err() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// reportsKey is the context key for Reports
+type reportsKey struct{}
+
+// WithReports returns a copy of ctx which makes Check and sync write
+// to r as they go
+func WithReports(ctx context.Context, r *Reports) context.Context {
+	return context.WithValue(ctx, reportsKey{}, r)
+}
+
+// GetReports returns the Reports set in ctx by WithReports or nil
+func GetReports(ctx context.Context) *Reports {
+	r, _ := ctx.Value(reportsKey{}).(*Reports)
+	return r
+}
@@ -0,0 +1,112 @@
+package operations_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ncw/rclone/fs/operations"
+	"github.com/ncw/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sortedLines returns the lines in buf sorted
+func sortedLines(buf *bytes.Buffer) []string {
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return nil
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func TestReports(t *testing.T) {
+	var reports *operations.Reports
+	// Check nil is OK
+	reports.Report(operations.ReportMatch, "potato")
+	assert.Nil(t, operations.GetReports(context.Background()))
+
+	var combined, match, differ bytes.Buffer
+	reports = &operations.Reports{
+		Combined: &combined,
+		Match:    &match,
+		Differ:   &differ,
+		Error:    &differ,
+	}
+	ctx := operations.WithReports(context.Background(), reports)
+	assert.Equal(t, reports, operations.GetReports(ctx))
+
+	reports.Report(operations.ReportMatch, "a")
+	reports.Report(operations.ReportDiffer, "b")
+	reports.Report(operations.ReportError, "c")
+	reports.Report(operations.ReportMissingOnSrc, "d")
+	reports.Report(operations.ReportMissingOnDst, "e")
+
+	assert.Equal(t, "= a\n* b\n! c\n- d\n+ e\n", combined.String())
+	assert.Equal(t, "a\n", match.String())
+	assert.Equal(t, "b\nc\n", differ.String())
+	assert.NoError(t, reports.Err())
+}
+
+// errorWriter is an io.Writer which always fails
+type errorWriter struct{}
+
+func (errorWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestReportsError(t *testing.T) {
+	var reports *operations.Reports
+	assert.NoError(t, reports.Err())
+
+	var combined bytes.Buffer
+	reports = &operations.Reports{
+		Combined: &combined,
+		Match:    errorWriter{},
+	}
+	reports.Report(operations.ReportMatch, "a")
+	reports.Report(operations.ReportDiffer, "b")
+
+	assert.Equal(t, "= a\n* b\n", combined.String())
+	err := reports.Err()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "write failed")
+}
+
+func TestCheckReports(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	file1 := r.WriteBoth("same", "same contents", t1)
+	file2 := r.WriteFile("differ", "source contents", t1)
+	file3 := r.WriteObject("differ", "destin contents", t1)
+	file4 := r.WriteFile("srconly", "only in the source", t2)
+	file5 := r.WriteObject("dstonly", "only in the destination", t2)
+	fstest.CheckItems(t, r.Flocal, file1, file2, file4)
+	fstest.CheckItems(t, r.Fremote, file1, file3, file5)
+
+	var combined, match, differ, missingOnSrc, missingOnDst, errors bytes.Buffer
+	ctx := operations.WithReports(context.Background(), &operations.Reports{
+		Combined:     &combined,
+		Match:        &match,
+		Differ:       &differ,
+		MissingOnSrc: &missingOnSrc,
+		MissingOnDst: &missingOnDst,
+		Error:        &errors,
+	})
+	err := operations.Check(ctx, r.Fremote, r.Flocal, false)
+	require.Error(t, err)
+
+	if r.Fremote.Hashes().Overlap(r.Flocal.Hashes()).Count() > 0 {
+		assert.Equal(t, []string{"* differ", "+ srconly", "- dstonly", "= same"}, sortedLines(&combined))
+		assert.Equal(t, []string{"same"}, sortedLines(&match))
+		assert.Equal(t, []string{"differ"}, sortedLines(&differ))
+	}
+	assert.Equal(t, []string{"dstonly"}, sortedLines(&missingOnSrc))
+	assert.Equal(t, []string{"srconly"}, sortedLines(&missingOnDst))
+	assert.Equal(t, []string(nil), sortedLines(&errors))
+}
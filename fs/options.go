@@ -219,6 +219,28 @@ func (o *HashesOption) Mandatory() bool {
 	return false
 }
 
+// MetadataOption defines an option used to pass the metadata of the
+// source object to Put and Update.  It is only supplied when
+// --metadata is in use.
+type MetadataOption struct {
+	Metadata Metadata
+}
+
+// Header formats the option as an http header
+func (o *MetadataOption) Header() (key string, value string) {
+	return "", ""
+}
+
+// String formats the option into human readable form
+func (o *MetadataOption) String() string {
+	return fmt.Sprintf("MetadataOption(%v)", o.Metadata)
+}
+
+// Mandatory returns whether the option must be parsed or can be ignored
+func (o *MetadataOption) Mandatory() bool {
+	return false
+}
+
 // OpenOptionAddHeaders adds each header found in options to the
 // headers map provided the key was non empty.
 func OpenOptionAddHeaders(options []OpenOption, headers map[string]string) {
@@ -259,4 +281,6 @@ var (
 	_ OpenOption = (*RangeOption)(nil)
 	_ OpenOption = (*SeekOption)(nil)
 	_ OpenOption = (*HTTPOption)(nil)
+	_ OpenOption = (*HashesOption)(nil)
+	_ OpenOption = (*MetadataOption)(nil)
 )
@@ -1,6 +1,7 @@
 package rc
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,30 +29,33 @@ func TestAddOption(t *testing.T) {
 }
 
 func TestOptionsBlocks(t *testing.T) {
+	ctx := context.Background()
 	defer clearOptionBlock()
 	AddOption("potato", &testOptions)
 	call := Calls.Get("options/blocks")
 	require.NotNil(t, call)
 	in := Params{}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	require.NotNil(t, out)
 	assert.Equal(t, Params{"options": []string{"potato"}}, out)
 }
 
 func TestOptionsGet(t *testing.T) {
+	ctx := context.Background()
 	defer clearOptionBlock()
 	AddOption("potato", &testOptions)
 	call := Calls.Get("options/get")
 	require.NotNil(t, call)
 	in := Params{}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	require.NotNil(t, out)
 	assert.Equal(t, Params{"potato": &testOptions}, out)
 }
 
 func TestOptionsSet(t *testing.T) {
+	ctx := context.Background()
 	defer clearOptionBlock()
 	AddOption("potato", &testOptions)
 	call := Calls.Get("options/set")
@@ -62,7 +66,7 @@ func TestOptionsSet(t *testing.T) {
 			"Int": 50,
 		},
 	}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	require.Nil(t, out)
 	assert.Equal(t, 50, testOptions.Int)
@@ -74,7 +78,7 @@ func TestOptionsSet(t *testing.T) {
 			"Int": 50,
 		},
 	}
-	out, err = call.Fn(in)
+	out, err = call.Fn(ctx, in)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unknown option block")
 
@@ -82,7 +86,7 @@ func TestOptionsSet(t *testing.T) {
 	in = Params{
 		"potato": []string{"a", "b"},
 	}
-	out, err = call.Fn(in)
+	out, err = call.Fn(ctx, in)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to write options")
 }
@@ -1,6 +1,7 @@
 package rc
 
 import (
+	"context"
 	"runtime"
 	"testing"
 
@@ -12,42 +13,46 @@ import (
 )
 
 func TestInternalNoop(t *testing.T) {
+	ctx := context.Background()
 	call := Calls.Get("rc/noop")
 	assert.NotNil(t, call)
 	in := Params{
 		"String": "hello",
 		"Int":    42,
 	}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	require.NotNil(t, out)
 	assert.Equal(t, in, out)
 }
 
 func TestInternalError(t *testing.T) {
+	ctx := context.Background()
 	call := Calls.Get("rc/error")
 	assert.NotNil(t, call)
 	in := Params{}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.Error(t, err)
 	require.Nil(t, out)
 }
 
 func TestInternalList(t *testing.T) {
+	ctx := context.Background()
 	call := Calls.Get("rc/list")
 	assert.NotNil(t, call)
 	in := Params{}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	require.NotNil(t, out)
 	assert.Equal(t, Params{"commands": Calls.List()}, out)
 }
 
 func TestCorePid(t *testing.T) {
+	ctx := context.Background()
 	call := Calls.Get("core/pid")
 	assert.NotNil(t, call)
 	in := Params{}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	require.NotNil(t, out)
 	pid := out["pid"]
@@ -57,10 +62,11 @@ func TestCorePid(t *testing.T) {
 }
 
 func TestCoreMemstats(t *testing.T) {
+	ctx := context.Background()
 	call := Calls.Get("core/memstats")
 	assert.NotNil(t, call)
 	in := Params{}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	require.NotNil(t, out)
 	sys := out["Sys"]
@@ -70,20 +76,22 @@ func TestCoreMemstats(t *testing.T) {
 }
 
 func TestCoreGC(t *testing.T) {
+	ctx := context.Background()
 	call := Calls.Get("core/gc")
 	assert.NotNil(t, call)
 	in := Params{}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	require.Nil(t, out)
 	assert.Equal(t, Params(nil), out)
 }
 
 func TestCoreVersion(t *testing.T) {
+	ctx := context.Background()
 	call := Calls.Get("core/version")
 	assert.NotNil(t, call)
 	in := Params{}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	require.NotNil(t, out)
 	assert.Equal(t, fs.Version, out["version"])
@@ -96,12 +104,13 @@ func TestCoreVersion(t *testing.T) {
 }
 
 func TestCoreObscure(t *testing.T) {
+	ctx := context.Background()
 	call := Calls.Get("core/obscure")
 	assert.NotNil(t, call)
 	in := Params{
 		"clear": "potato",
 	}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	require.NotNil(t, out)
 	assert.Equal(t, in["clear"], obscure.MustReveal(out["obscured"].(string)))
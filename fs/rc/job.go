@@ -3,6 +3,8 @@
 package rc
 
 import (
+	"context"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -28,6 +30,8 @@ type Job struct {
 	Success   bool      `json:"success"`
 	Duration  float64   `json:"duration"`
 	Output    Params    `json:"output"`
+	Group     string    `json:"group"`
+	cancel    context.CancelFunc
 }
 
 // Jobs describes a collection of running tasks
@@ -41,6 +45,13 @@ type Jobs struct {
 var (
 	running = newJobs()
 	jobID   = int64(0)
+
+	// GroupContext returns a copy of ctx which accounts the
+	// transfers made with it in the stats group called group.
+	//
+	// This is a function pointer to decouple the accounting
+	// implementation from the rc
+	GroupContext = func(ctx context.Context, group string) context.Context { return ctx }
 )
 
 // newJobs makes a new Jobs structure
@@ -117,26 +128,43 @@ func (job *Job) finish(out Params, err error) {
 	}
 	job.Finished = true
 	job.mu.Unlock()
+	job.cancel()         // release resources held by the job context
 	running.kickExpire() // make sure this job gets expired
 }
 
 // run the job until completion writing the return status
-func (job *Job) run(fn Func, in Params) {
+func (job *Job) run(ctx context.Context, fn Func, in Params) {
 	defer func() {
 		if r := recover(); r != nil {
 			job.finish(nil, errors.Errorf("panic received: %v", r))
 		}
 	}()
-	job.finish(fn(in))
+	job.finish(fn(ctx, in))
+}
+
+// Stop the job by cancelling its context
+func (job *Job) Stop() {
+	job.cancel()
 }
 
 // NewJob start a new Job off
+//
+// The transfers it makes are accounted in the stats group named by
+// the _group parameter, or "job/ID" if that isn't set.
 func (jobs *Jobs) NewJob(fn Func, in Params) *Job {
+	ctx, cancel := context.WithCancel(context.Background())
 	job := &Job{
 		ID:        atomic.AddInt64(&jobID, 1),
 		StartTime: time.Now(),
+		cancel:    cancel,
 	}
-	go job.run(fn, in)
+	group, err := in.GetString("_group")
+	if err != nil {
+		group = fmt.Sprintf("job/%d", job.ID)
+	}
+	job.Group = group
+	ctx = GroupContext(ctx, group)
+	go job.run(ctx, fn, in)
 	jobs.mu.Lock()
 	jobs.jobs[job.ID] = job
 	jobs.mu.Unlock()
@@ -170,12 +198,13 @@ Results
 - startTime - time the job started (eg "2018-10-26T18:50:20.528336039+01:00")
 - success - boolean - true for success false otherwise
 - output - output of the job as would have been returned if called synchronously
+- group - the stats group the job's transfers are accounted in (eg "job/42")
 `,
 	})
 }
 
 // Returns the status of a job
-func rcJobStatus(in Params) (out Params, err error) {
+func rcJobStatus(ctx context.Context, in Params) (out Params, err error) {
 	jobID, err := in.GetInt64("jobid")
 	if err != nil {
 		return nil, err
@@ -208,8 +237,36 @@ Results
 }
 
 // Returns the status of a job
-func rcJobList(in Params) (out Params, err error) {
+func rcJobList(ctx context.Context, in Params) (out Params, err error) {
 	out = make(Params)
 	out["jobids"] = running.IDs()
 	return out, nil
 }
+
+func init() {
+	Add(Call{
+		Path:  "job/stop",
+		Fn:    rcJobStop,
+		Title: "Stop the running job",
+		Help: `Parameters
+- jobid - id of the job (integer)
+
+This cancels the job which will finish with an error as soon as it
+notices.  Use job/status to see when it has finished.
+`,
+	})
+}
+
+// Stops a job
+func rcJobStop(ctx context.Context, in Params) (out Params, err error) {
+	jobID, err := in.GetInt64("jobid")
+	if err != nil {
+		return nil, err
+	}
+	job := running.Get(jobID)
+	if job == nil {
+		return nil, errors.New("job not found")
+	}
+	job.Stop()
+	return nil, nil
+}
@@ -1,6 +1,7 @@
 package rc
 
 import (
+	"context"
 	"runtime"
 	"testing"
 	"time"
@@ -34,7 +35,7 @@ func TestJobsExpire(t *testing.T) {
 	jobs := newJobs()
 	jobs.expireInterval = time.Millisecond
 	assert.Equal(t, false, jobs.expireRunning)
-	job := jobs.NewJob(func(in Params) (Params, error) {
+	job := jobs.NewJob(func(ctx context.Context, in Params) (Params, error) {
 		defer close(wait)
 		return in, nil
 	}, Params{})
@@ -53,7 +54,7 @@ func TestJobsExpire(t *testing.T) {
 	jobs.mu.Unlock()
 }
 
-var noopFn = func(in Params) (Params, error) {
+var noopFn = func(ctx context.Context, in Params) (Params, error) {
 	return nil, nil
 }
 
@@ -77,11 +78,16 @@ func TestJobsGet(t *testing.T) {
 	assert.Nil(t, jobs.Get(123123123123))
 }
 
-var longFn = func(in Params) (Params, error) {
+var longFn = func(ctx context.Context, in Params) (Params, error) {
 	time.Sleep(1 * time.Hour)
 	return nil, nil
 }
 
+var ctxFn = func(ctx context.Context, in Params) (Params, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
 const (
 	sleepTime      = 100 * time.Millisecond
 	floatSleepTime = float64(sleepTime) / 1E9 / 2
@@ -141,7 +147,7 @@ func TestJobFinish(t *testing.T) {
 // part of NewJob, now just test the panic catching
 func TestJobRunPanic(t *testing.T) {
 	wait := make(chan struct{})
-	boom := func(in Params) (Params, error) {
+	boom := func(ctx context.Context, in Params) (Params, error) {
 		sleepJob()
 		defer close(wait)
 		panic("boom")
@@ -178,8 +184,12 @@ func TestJobsNewJob(t *testing.T) {
 	jobs := newJobs()
 	job := jobs.NewJob(noopFn, Params{})
 	assert.Equal(t, int64(1), job.ID)
+	assert.Equal(t, "job/1", job.Group)
 	assert.Equal(t, job, jobs.Get(1))
 
+	job = jobs.NewJob(noopFn, Params{"_group": "potato"})
+	assert.Equal(t, int64(2), job.ID)
+	assert.Equal(t, "potato", job.Group)
 }
 
 func TestStartJob(t *testing.T) {
@@ -190,6 +200,7 @@ func TestStartJob(t *testing.T) {
 }
 
 func TestRcJobStatus(t *testing.T) {
+	ctx := context.Background()
 	jobID = 0
 	_, err := StartJob(longFn, Params{})
 	assert.NoError(t, err)
@@ -197,7 +208,7 @@ func TestRcJobStatus(t *testing.T) {
 	call := Calls.Get("job/status")
 	assert.NotNil(t, call)
 	in := Params{"jobid": 1}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	require.NotNil(t, out)
 	assert.Equal(t, float64(1), out["id"])
@@ -206,17 +217,18 @@ func TestRcJobStatus(t *testing.T) {
 	assert.Equal(t, false, out["success"])
 
 	in = Params{"jobid": 123123123}
-	_, err = call.Fn(in)
+	_, err = call.Fn(ctx, in)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "job not found")
 
 	in = Params{"jobidx": 123123123}
-	_, err = call.Fn(in)
+	_, err = call.Fn(ctx, in)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "Didn't find key")
 }
 
 func TestRcJobList(t *testing.T) {
+	ctx := context.Background()
 	jobID = 0
 	_, err := StartJob(longFn, Params{})
 	assert.NoError(t, err)
@@ -224,8 +236,44 @@ func TestRcJobList(t *testing.T) {
 	call := Calls.Get("job/list")
 	assert.NotNil(t, call)
 	in := Params{}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	require.NotNil(t, out)
 	assert.Equal(t, Params{"jobids": []int64{1}}, out)
 }
+
+func TestRcJobStop(t *testing.T) {
+	ctx := context.Background()
+	jobID = 0
+	_, err := StartJob(ctxFn, Params{})
+	assert.NoError(t, err)
+
+	call := Calls.Get("job/stop")
+	assert.NotNil(t, call)
+	in := Params{"jobid": 1}
+	out, err := call.Fn(ctx, in)
+	require.NoError(t, err)
+	assert.Nil(t, out)
+
+	job := running.Get(1)
+	require.NotNil(t, job)
+	for i := 0; i < 100; i++ {
+		job.mu.Lock()
+		finished := job.Finished
+		job.mu.Unlock()
+		if finished {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	job.mu.Lock()
+	assert.Equal(t, true, job.Finished)
+	assert.Equal(t, false, job.Success)
+	assert.Equal(t, "context canceled", job.Error)
+	job.mu.Unlock()
+
+	in = Params{"jobid": 123123123}
+	_, err = call.Fn(ctx, in)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "job not found")
+}
@@ -17,11 +17,12 @@ import (
 
 // Options contains options for the remote control server
 type Options struct {
-	HTTPOptions httplib.Options
-	Enabled     bool   // set to enable the server
-	Serve       bool   // set to serve files from remotes
-	Files       string // set to enable serving files locally
-	NoAuth      bool   // set to disable auth checks on AuthRequired methods
+	HTTPOptions   httplib.Options
+	Enabled       bool   // set to enable the server
+	Serve         bool   // set to serve files from remotes
+	Files         string // set to enable serving files locally
+	NoAuth        bool   // set to disable auth checks on AuthRequired methods
+	EnableMetrics bool   // set to serve Prometheus metrics on /metrics
 }
 
 // DefaultOpt is the default values used for Options
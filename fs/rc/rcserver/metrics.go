@@ -0,0 +1,84 @@
+package rcserver
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/accounting"
+	"github.com/ncw/rclone/fs/fshttp"
+	"github.com/ncw/rclone/lib/pacer"
+)
+
+// metricsContentType is the content type of the prometheus text
+// exposition format
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// escapes a label value for the prometheus text format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// metricsWriter writes metrics in the prometheus text exposition
+// format
+type metricsWriter struct {
+	buf bytes.Buffer
+}
+
+// header writes the HELP and TYPE lines for the metric name
+func (m *metricsWriter) header(name, kind, help string) {
+	_, _ = fmt.Fprintf(&m.buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// value writes a single sample of the metric name
+func (m *metricsWriter) value(name string, value float64) {
+	_, _ = fmt.Fprintf(&m.buf, "%s %s\n", name, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// metric writes a metric with a single unlabelled value
+func (m *metricsWriter) metric(name, kind, help string, value float64) {
+	m.header(name, kind, help)
+	m.value(name, value)
+}
+
+// writeMetrics writes all the rclone metrics to m
+func (m *metricsWriter) writeMetrics() {
+	stats := accounting.Stats
+	m.metric("rclone_bytes_transferred_total", "counter", "Total bytes transferred.", float64(stats.GetBytes()))
+	m.metric("rclone_transfers_total", "counter", "Total number of files transferred.", float64(stats.GetTransfers()))
+	m.metric("rclone_checks_total", "counter", "Total number of files checked.", float64(stats.GetChecks()))
+	m.metric("rclone_deletes_total", "counter", "Total number of files deleted.", float64(stats.GetDeletes()))
+	m.metric("rclone_errors_total", "counter", "Total number of errors.", float64(stats.GetErrors()))
+	m.metric("rclone_retries_total", "counter", "Total number of low level retries.", float64(pacer.TotalRetries()))
+	m.metric("rclone_speed_bytes_per_second", "gauge", "Current transfer speed in bytes per second.", stats.CurrentSpeed())
+	m.metric("rclone_pacer_sleep_seconds_total", "counter", "Total time spent waiting for the pacers in seconds.", pacer.TotalSleep().Seconds())
+
+	const requests = "rclone_http_requests_total"
+	m.header(requests, "counter", "Total number of HTTP requests made by each backend type.")
+	counts := fshttp.RequestCounts()
+	backends := make([]string, 0, len(counts))
+	for backend := range counts {
+		backends = append(backends, backend)
+	}
+	sort.Strings(backends)
+	for _, backend := range backends {
+		m.value(requests+`{backend="`+labelEscaper.Replace(backend)+`"}`, float64(counts[backend]))
+	}
+}
+
+// serveMetrics serves the prometheus metrics
+func (s *Server) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	var m metricsWriter
+	m.writeMetrics()
+	w.Header().Set("Content-Type", metricsContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(m.buf.Len()))
+	if r.Method == "HEAD" {
+		return
+	}
+	_, err := m.buf.WriteTo(w)
+	if err != nil {
+		fs.Errorf(nil, "rc: failed to write metrics: %v", err)
+	}
+}
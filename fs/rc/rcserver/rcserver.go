@@ -186,7 +186,11 @@ func (s *Server) handlePost(w http.ResponseWriter, r *http.Request, path string)
 	if isAsync {
 		out, err = rc.StartJob(call.Fn, in)
 	} else {
-		out, err = call.Fn(in)
+		ctx := r.Context()
+		if group, err := in.GetString("_group"); err == nil {
+			ctx = rc.GroupContext(ctx, group)
+		}
+		out, err = call.Fn(ctx, in)
 	}
 	if err != nil {
 		writeError(path, in, w, err, http.StatusInternalServerError)
@@ -229,7 +233,7 @@ func (s *Server) serveRemote(w http.ResponseWriter, r *http.Request, path string
 	}
 	if path == "" || strings.HasSuffix(path, "/") {
 		path = strings.Trim(path, "/")
-		entries, err := list.DirSorted(f, false, path)
+		entries, err := list.DirSorted(r.Context(), f, false, path)
 		if err != nil {
 			writeError(path, nil, w, errors.Wrap(err, "failed to list directory"), http.StatusInternalServerError)
 			return
@@ -242,7 +246,7 @@ func (s *Server) serveRemote(w http.ResponseWriter, r *http.Request, path string
 		}
 		directory.Serve(w, r)
 	} else {
-		o, err := f.NewObject(path)
+		o, err := f.NewObject(r.Context(), path)
 		if err != nil {
 			writeError(path, nil, w, errors.Wrap(err, "failed to find object"), http.StatusInternalServerError)
 			return
@@ -258,6 +262,10 @@ func (s *Server) handleGet(w http.ResponseWriter, r *http.Request, path string)
 	// Look to see if this has an fs in the path
 	match := fsMatch.FindStringSubmatch(path)
 	switch {
+	case path == "metrics" && s.opt.EnableMetrics:
+		// Serve the prometheus metrics
+		s.serveMetrics(w, r)
+		return
 	case match != nil && s.opt.Serve:
 		// Serve /[fs]/remote files
 		s.serveRemote(w, r, match[2], match[1])
@@ -630,3 +630,45 @@ func TestRCAsync(t *testing.T) {
 	opt.Files = ""
 	testServer(t, tests, &opt)
 }
+
+var matchMetrics = regexp.MustCompile(`(?s)^# HELP rclone_bytes_transferred_total Total bytes transferred.
+# TYPE rclone_bytes_transferred_total counter
+rclone_bytes_transferred_total \d+
+.*# TYPE rclone_speed_bytes_per_second gauge
+.*# TYPE rclone_http_requests_total counter
+`)
+
+func TestMetrics(t *testing.T) {
+	tests := []testRun{{
+		Name:     "metrics",
+		URL:      "metrics",
+		Status:   http.StatusOK,
+		Contains: matchMetrics,
+		Headers: map[string]string{
+			"Content-Type": "text/plain; version=0.0.4; charset=utf-8",
+		},
+	}, {
+		Name:     "head",
+		URL:      "metrics",
+		Method:   "HEAD",
+		Status:   http.StatusOK,
+		Expected: "",
+	}}
+	opt := newTestOpt()
+	opt.Serve = true
+	opt.Files = testFs
+	opt.EnableMetrics = true
+	testServer(t, tests, &opt)
+}
+
+func TestMetricsDisabled(t *testing.T) {
+	tests := []testRun{{
+		Name:     "metrics",
+		URL:      "metrics",
+		Status:   http.StatusNotFound,
+		Expected: "Not Found\n",
+	}}
+	opt := newTestOpt()
+	opt.Files = ""
+	testServer(t, tests, &opt)
+}
@@ -1,6 +1,7 @@
 package sync
 
 import (
+	"context"
 	"testing"
 
 	"github.com/ncw/rclone/fs/rc"
@@ -23,6 +24,7 @@ func rcNewRun(t *testing.T, method string) (*fstest.Run, *rc.Call) {
 
 // sync/copy: copy a directory from source remote to destination remote
 func TestRcCopy(t *testing.T) {
+	ctx := context.Background()
 	r, call := rcNewRun(t, "sync/copy")
 	defer r.Finalise()
 	r.Mkdir(r.Fremote)
@@ -38,7 +40,7 @@ func TestRcCopy(t *testing.T) {
 		"srcFs": r.LocalName,
 		"dstFs": r.FremoteName,
 	}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	assert.Equal(t, rc.Params(nil), out)
 
@@ -48,6 +50,7 @@ func TestRcCopy(t *testing.T) {
 
 // sync/move: move a directory from source remote to destination remote
 func TestRcMove(t *testing.T) {
+	ctx := context.Background()
 	r, call := rcNewRun(t, "sync/move")
 	defer r.Finalise()
 	r.Mkdir(r.Fremote)
@@ -63,7 +66,7 @@ func TestRcMove(t *testing.T) {
 		"srcFs": r.LocalName,
 		"dstFs": r.FremoteName,
 	}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	assert.Equal(t, rc.Params(nil), out)
 
@@ -73,6 +76,7 @@ func TestRcMove(t *testing.T) {
 
 // sync/sync: sync a directory from source remote to destination remote
 func TestRcSync(t *testing.T) {
+	ctx := context.Background()
 	r, call := rcNewRun(t, "sync/sync")
 	defer r.Finalise()
 	r.Mkdir(r.Fremote)
@@ -88,7 +92,7 @@ func TestRcSync(t *testing.T) {
 		"srcFs": r.LocalName,
 		"dstFs": r.FremoteName,
 	}
-	out, err := call.Fn(in)
+	out, err := call.Fn(ctx, in)
 	require.NoError(t, err)
 	assert.Equal(t, rc.Params(nil), out)
 
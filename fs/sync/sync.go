@@ -7,6 +7,7 @@ import (
 	"path"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/ncw/rclone/fs"
 	"github.com/ncw/rclone/fs/accounting"
@@ -60,10 +61,15 @@ type syncCopyMove struct {
 	trackRenamesCh chan fs.Object         // objects are pumped in here
 	renameCheck    []fs.Object            // accumulate files to check for rename here
 	backupDir      fs.Fs                  // place to store overwrites/deletes
-	suffix         string                 // suffix to add to files placed in backupDir
+	compareOrCopy  []fs.Fs                // --compare-dest or --copy-dest directories to check first
+	reports        *operations.Reports    // difference reports to write if set
+	stats          *accounting.StatsInfo  // stats to account the sync in
+	replacedMu     sync.Mutex             // protect replaced
+	replaced       map[string]struct{}    // dst files moved to --backup-dir before being replaced - only used by reports
 }
 
-func newSyncCopyMove(fdst, fsrc fs.Fs, deleteMode fs.DeleteMode, DoMove bool, deleteEmptySrcDirs bool) (*syncCopyMove, error) {
+func newSyncCopyMove(ctx context.Context, fdst, fsrc fs.Fs, deleteMode fs.DeleteMode, DoMove bool, deleteEmptySrcDirs bool, backupTime time.Time) (*syncCopyMove, error) {
+	stats := accounting.GetStats(ctx)
 	s := &syncCopyMove{
 		fdst:               fdst,
 		fsrc:               fsrc,
@@ -77,15 +83,18 @@ func newSyncCopyMove(fdst, fsrc fs.Fs, deleteMode fs.DeleteMode, DoMove bool, de
 		dstEmptyDirs:       make(map[string]fs.DirEntry),
 		srcEmptyDirs:       make(map[string]fs.DirEntry),
 		noTraverse:         fs.Config.NoTraverse,
-		toBeChecked:        newPipe(accounting.Stats.SetCheckQueue, fs.Config.MaxBacklog),
-		toBeUploaded:       newPipe(accounting.Stats.SetTransferQueue, fs.Config.MaxBacklog),
+		toBeChecked:        newPipe(stats.SetCheckQueue, fs.Config.MaxBacklog),
+		toBeUploaded:       newPipe(stats.SetTransferQueue, fs.Config.MaxBacklog),
 		deleteFilesCh:      make(chan fs.Object, fs.Config.Checkers),
 		trackRenames:       fs.Config.TrackRenames,
 		commonHash:         fsrc.Hashes().Overlap(fdst.Hashes()).GetOne(),
-		toBeRenamed:        newPipe(accounting.Stats.SetRenameQueue, fs.Config.MaxBacklog),
+		toBeRenamed:        newPipe(stats.SetRenameQueue, fs.Config.MaxBacklog),
 		trackRenamesCh:     make(chan fs.Object, fs.Config.Checkers),
+		reports:            operations.GetReports(ctx),
+		stats:              stats,
+		replaced:           make(map[string]struct{}),
 	}
-	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.ctx, s.cancel = context.WithCancel(ctx)
 	if s.noTraverse && s.deleteMode != fs.DeleteModeOff {
 		fs.Errorf(nil, "Ignoring --no-traverse with sync")
 		s.noTraverse = false
@@ -118,23 +127,21 @@ func newSyncCopyMove(fdst, fsrc fs.Fs, deleteMode fs.DeleteMode, DoMove bool, de
 	// Make Fs for --backup-dir if required
 	if fs.Config.BackupDir != "" {
 		var err error
-		s.backupDir, err = fs.NewFs(fs.Config.BackupDir)
+		s.backupDir, err = operations.BackupDir(fdst, fsrc, backupTime)
 		if err != nil {
-			return nil, fserrors.FatalError(errors.Errorf("Failed to make fs for --backup-dir %q: %v", fs.Config.BackupDir, err))
+			return nil, fserrors.FatalError(err)
 		}
-		if !operations.CanServerSideMove(s.backupDir) {
-			return nil, fserrors.FatalError(errors.New("can't use --backup-dir on a remote which doesn't support server side move or copy"))
-		}
-		if !operations.SameConfig(fdst, s.backupDir) {
-			return nil, fserrors.FatalError(errors.New("parameter to --backup-dir has to be on the same remote as destination"))
-		}
-		if operations.Overlapping(fdst, s.backupDir) {
-			return nil, fserrors.FatalError(errors.New("destination and parameter to --backup-dir mustn't overlap"))
-		}
-		if operations.Overlapping(fsrc, s.backupDir) {
-			return nil, fserrors.FatalError(errors.New("source and parameter to --backup-dir mustn't overlap"))
+	}
+	// Make Fs for --compare-dest or --copy-dest if required
+	var err error
+	s.compareOrCopy, err = operations.GetCompareOrCopyDest(fdst)
+	if err != nil {
+		return nil, fserrors.FatalError(err)
+	}
+	for _, f := range s.compareOrCopy {
+		if operations.Overlapping(fsrc, f) {
+			return nil, fserrors.FatalError(errors.Errorf("source and reference directory %v mustn't overlap", f))
 		}
-		s.suffix = fs.Config.Suffix
 	}
 	return s, nil
 }
@@ -210,24 +217,29 @@ func (s *syncCopyMove) pairChecker(in *pipe, out *pipe, wg *sync.WaitGroup) {
 			return
 		}
 		src := pair.Src
-		accounting.Stats.Checking(src.Remote())
+		s.stats.Checking(src.Remote())
 		// Check to see if can store this
 		if src.Storable() {
-			if operations.NeedTransfer(pair.Dst, pair.Src) {
+			noNeedTransfer, err := operations.CompareOrCopyDest(s.ctx, s.fdst, pair.Dst, pair.Src, s.compareOrCopy, s.backupDir)
+			if err != nil {
+				s.processError(err)
+				s.reports.Report(operations.ReportError, src.Remote())
+			} else if !noNeedTransfer && operations.NeedTransfer(s.ctx, pair.Dst, pair.Src) {
 				// If files are treated as immutable, fail if destination exists and does not match
 				if fs.Config.Immutable && pair.Dst != nil {
 					fs.Errorf(pair.Dst, "Source and destination exist but do not match: immutable file modified")
 					s.processError(fs.ErrorImmutableModified)
+					s.reports.Report(operations.ReportError, src.Remote())
 				} else {
 					// If destination already exists, then we must move it into --backup-dir if required
 					if pair.Dst != nil && s.backupDir != nil {
-						remoteWithSuffix := pair.Dst.Remote() + s.suffix
-						overwritten, _ := s.backupDir.NewObject(remoteWithSuffix)
-						_, err := operations.Move(s.backupDir, overwritten, remoteWithSuffix, pair.Dst)
+						err := operations.MoveBackupDir(s.ctx, s.backupDir, pair.Dst)
 						if err != nil {
 							s.processError(err)
+							s.reports.Report(operations.ReportError, src.Remote())
 						} else {
 							// If successful zero out the dst as it is no longer there and copy the file
+							s.setReplaced(src.Remote())
 							pair.Dst = nil
 							ok = out.Put(s.ctx, pair)
 							if !ok {
@@ -243,16 +255,42 @@ func (s *syncCopyMove) pairChecker(in *pipe, out *pipe, wg *sync.WaitGroup) {
 				}
 			} else {
 				// If moving need to delete the files we don't need to copy
+				kind := operations.ReportMatch
 				if s.DoMove {
 					// Delete src if no error on copy
-					s.processError(operations.DeleteFile(src))
+					err := operations.DeleteFile(s.ctx, src)
+					s.processError(err)
+					if err != nil {
+						kind = operations.ReportError
+					}
 				}
+				s.reports.Report(kind, src.Remote())
 			}
 		}
-		accounting.Stats.DoneChecking(src.Remote())
+		s.stats.DoneChecking(src.Remote())
 	}
 }
 
+// setReplaced records that the dst for remote was moved to
+// --backup-dir so it can be reported as different rather than
+// missing when it is copied
+func (s *syncCopyMove) setReplaced(remote string) {
+	if s.reports == nil {
+		return
+	}
+	s.replacedMu.Lock()
+	s.replaced[remote] = struct{}{}
+	s.replacedMu.Unlock()
+}
+
+// wasReplaced returns whether setReplaced was called for remote
+func (s *syncCopyMove) wasReplaced(remote string) bool {
+	s.replacedMu.Lock()
+	defer s.replacedMu.Unlock()
+	_, found := s.replaced[remote]
+	return found
+}
+
 // pairRenamer reads Objects~s on in and attempts to rename them,
 // otherwise it sends them out if they need transferring.
 func (s *syncCopyMove) pairRenamer(in *pipe, out *pipe, wg *sync.WaitGroup) {
@@ -263,7 +301,9 @@ func (s *syncCopyMove) pairRenamer(in *pipe, out *pipe, wg *sync.WaitGroup) {
 			return
 		}
 		src := pair.Src
-		if !s.tryRename(src) {
+		if s.tryRename(src) {
+			s.reports.Report(operations.ReportMissingOnDst, src.Remote())
+		} else {
 			// pass on if not renamed
 			ok = out.Put(s.ctx, pair)
 			if !ok {
@@ -283,14 +323,22 @@ func (s *syncCopyMove) pairCopyOrMove(in *pipe, fdst fs.Fs, wg *sync.WaitGroup)
 			return
 		}
 		src := pair.Src
-		accounting.Stats.Transferring(src.Remote())
+		kind := operations.ReportDiffer
+		if pair.Dst == nil && !s.wasReplaced(src.Remote()) {
+			kind = operations.ReportMissingOnDst
+		}
+		s.stats.Transferring(src.Remote())
 		if s.DoMove {
-			_, err = operations.Move(fdst, pair.Dst, src.Remote(), src)
+			_, err = operations.Move(s.ctx, fdst, pair.Dst, src.Remote(), src)
 		} else {
-			_, err = operations.Copy(fdst, pair.Dst, src.Remote(), src)
+			_, err = operations.Copy(s.ctx, fdst, pair.Dst, src.Remote(), src)
 		}
 		s.processError(err)
-		accounting.Stats.DoneTransferring(src.Remote(), err == nil)
+		s.stats.DoneTransferring(src.Remote(), err == nil)
+		if err != nil {
+			kind = operations.ReportError
+		}
+		s.reports.Report(kind, src.Remote())
 	}
 }
 
@@ -376,7 +424,7 @@ func (s *syncCopyMove) startDeleters() {
 	s.deletersWg.Add(1)
 	go func() {
 		defer s.deletersWg.Done()
-		err := operations.DeleteFilesWithBackupDir(s.deleteFilesCh, s.backupDir)
+		err := operations.DeleteFilesWithBackupDir(s.ctx, s.deleteFilesCh, s.backupDir)
 		s.processError(err)
 	}()
 }
@@ -396,7 +444,7 @@ func (s *syncCopyMove) stopDeleters() {
 // checkSrcMap is clear then it assumes that the any source files that
 // have been found have been removed from dstFiles already.
 func (s *syncCopyMove) deleteFiles(checkSrcMap bool) error {
-	if accounting.Stats.Errored() && !fs.Config.IgnoreErrors {
+	if s.stats.Errored() && !fs.Config.IgnoreErrors {
 		fs.Errorf(s.fdst, "%v", fs.ErrorNotDeleting)
 		return fs.ErrorNotDeleting
 	}
@@ -423,16 +471,16 @@ func (s *syncCopyMove) deleteFiles(checkSrcMap bool) error {
 		}
 		close(toDelete)
 	}()
-	return operations.DeleteFilesWithBackupDir(toDelete, s.backupDir)
+	return operations.DeleteFilesWithBackupDir(s.ctx, toDelete, s.backupDir)
 }
 
 // This deletes the empty directories in the slice passed in.  It
 // ignores any errors deleting directories
-func deleteEmptyDirectories(f fs.Fs, entriesMap map[string]fs.DirEntry) error {
+func deleteEmptyDirectories(ctx context.Context, f fs.Fs, entriesMap map[string]fs.DirEntry) error {
 	if len(entriesMap) == 0 {
 		return nil
 	}
-	if accounting.Stats.Errored() && !fs.Config.IgnoreErrors {
+	if accounting.GetStats(ctx).Errored() && !fs.Config.IgnoreErrors {
 		fs.Errorf(f, "%v", fs.ErrorNotDeletingDirs)
 		return fs.ErrorNotDeletingDirs
 	}
@@ -450,7 +498,7 @@ func deleteEmptyDirectories(f fs.Fs, entriesMap map[string]fs.DirEntry) error {
 		dir, ok := entry.(fs.Directory)
 		if ok {
 			// TryRmdir only deletes empty directories
-			err := operations.TryRmdir(f, dir.Remote())
+			err := operations.TryRmdir(ctx, f, dir.Remote())
 			if err != nil {
 				fs.Debugf(fs.LogDirName(f, dir.Remote()), "Failed to Rmdir: %v", err)
 				errorCount++
@@ -472,7 +520,7 @@ func deleteEmptyDirectories(f fs.Fs, entriesMap map[string]fs.DirEntry) error {
 
 // This copies the empty directories in the slice passed in and logs
 // any errors copying the directories
-func copyEmptyDirectories(f fs.Fs, entries map[string]fs.DirEntry) error {
+func copyEmptyDirectories(ctx context.Context, f fs.Fs, entries map[string]fs.DirEntry) error {
 	if len(entries) == 0 {
 		return nil
 	}
@@ -481,7 +529,7 @@ func copyEmptyDirectories(f fs.Fs, entries map[string]fs.DirEntry) error {
 	for _, entry := range entries {
 		dir, ok := entry.(fs.Directory)
 		if ok {
-			err := operations.Mkdir(f, dir.Remote())
+			err := operations.Mkdir(ctx, f, dir.Remote())
 			if err != nil {
 				fs.Errorf(fs.LogDirName(f, dir.Remote()), "Failed to Mkdir: %v", err)
 			} else {
@@ -492,8 +540,8 @@ func copyEmptyDirectories(f fs.Fs, entries map[string]fs.DirEntry) error {
 		}
 	}
 
-	if accounting.Stats.Errored() {
-		fs.Debugf(f, "failed to copy %d directories", accounting.Stats.GetErrors())
+	if accounting.GetStats(ctx).Errored() {
+		fs.Debugf(f, "failed to copy %d directories", accounting.GetStats(ctx).GetErrors())
 	}
 
 	if okCount > 0 {
@@ -522,7 +570,7 @@ func (s *syncCopyMove) srcParentDirCheck(entry fs.DirEntry) {
 // it may return an empty string in which case no hash could be made
 func (s *syncCopyMove) renameHash(obj fs.Object) (hash string) {
 	var err error
-	hash, err = obj.Hash(s.commonHash)
+	hash, err = obj.Hash(s.ctx, s.commonHash)
 	if err != nil {
 		fs.Debugf(obj, "Hash failed: %v", err)
 		return ""
@@ -582,12 +630,12 @@ func (s *syncCopyMove) makeRenameMap() {
 			for obj := range in {
 				// only create hash for dst fs.Object if its size could match
 				if _, found := possibleSizes[obj.Size()]; found {
-					accounting.Stats.Checking(obj.Remote())
+					s.stats.Checking(obj.Remote())
 					hash := s.renameHash(obj)
 					if hash != "" {
 						s.pushRenameMap(hash, obj)
 					}
-					accounting.Stats.DoneChecking(obj.Remote())
+					s.stats.DoneChecking(obj.Remote())
 				}
 			}
 		}()
@@ -599,8 +647,8 @@ func (s *syncCopyMove) makeRenameMap() {
 // tryRename renames a src object when doing track renames if
 // possible, it returns true if the object was renamed.
 func (s *syncCopyMove) tryRename(src fs.Object) bool {
-	accounting.Stats.Checking(src.Remote())
-	defer accounting.Stats.DoneChecking(src.Remote())
+	s.stats.Checking(src.Remote())
+	defer s.stats.DoneChecking(src.Remote())
 
 	// Calculate the hash of the src object
 	hash := s.renameHash(src)
@@ -615,10 +663,10 @@ func (s *syncCopyMove) tryRename(src fs.Object) bool {
 	}
 
 	// Find dst object we are about to overwrite if it exists
-	dstOverwritten, _ := s.fdst.NewObject(src.Remote())
+	dstOverwritten, _ := s.fdst.NewObject(s.ctx, src.Remote())
 
 	// Rename dst to have name src.Remote()
-	_, err := operations.Move(s.fdst, dstOverwritten, src.Remote(), dst)
+	_, err := operations.Move(s.ctx, s.fdst, dstOverwritten, src.Remote(), dst)
 	if err != nil {
 		fs.Debugf(src, "Failed to rename to %q: %v", dst.Remote(), err)
 		return false
@@ -686,10 +734,15 @@ func (s *syncCopyMove) run() error {
 	s.stopTransfers()
 	s.stopDeleters()
 
-	s.processError(copyEmptyDirectories(s.fdst, s.srcEmptyDirs))
+	s.processError(copyEmptyDirectories(s.ctx, s.fdst, s.srcEmptyDirs))
 
 	// Delete files after
 	if s.deleteMode == fs.DeleteModeAfter {
+		if s.reports != nil {
+			for remote := range s.dstFiles {
+				s.reports.Report(operations.ReportMissingOnSrc, remote)
+			}
+		}
 		if s.currentError() != nil && !fs.Config.IgnoreErrors {
 			fs.Errorf(s.fdst, "%v", fs.ErrorNotDeleting)
 		} else {
@@ -702,7 +755,7 @@ func (s *syncCopyMove) run() error {
 		if s.currentError() != nil && !fs.Config.IgnoreErrors {
 			fs.Errorf(s.fdst, "%v", fs.ErrorNotDeletingDirs)
 		} else {
-			s.processError(deleteEmptyDirectories(s.fdst, s.dstEmptyDirs))
+			s.processError(deleteEmptyDirectories(s.ctx, s.fdst, s.dstEmptyDirs))
 		}
 	}
 
@@ -710,7 +763,7 @@ func (s *syncCopyMove) run() error {
 	// if DoMove and --delete-empty-src-dirs flag is set
 	if s.DoMove && s.deleteEmptySrcDirs {
 		//delete empty subdirectories that were part of the move
-		s.processError(deleteEmptyDirectories(s.fsrc, s.srcEmptyDirs))
+		s.processError(deleteEmptyDirectories(s.ctx, s.fsrc, s.srcEmptyDirs))
 	}
 
 	// cancel the context to free resources
@@ -732,6 +785,7 @@ func (s *syncCopyMove) DstOnly(dst fs.DirEntry) (recurse bool) {
 			s.dstFiles[x.Remote()] = x
 			s.dstFilesMu.Unlock()
 		case fs.DeleteModeDuring, fs.DeleteModeOnly:
+			s.reports.Report(operations.ReportMissingOnSrc, x.Remote())
 			select {
 			case <-s.ctx.Done():
 				return
@@ -777,6 +831,12 @@ func (s *syncCopyMove) SrcOnly(src fs.DirEntry) (recurse bool) {
 				return
 			case s.trackRenamesCh <- x:
 			}
+		} else if len(s.compareOrCopy) > 0 {
+			// Check against --compare-dest or --copy-dest
+			ok := s.toBeChecked.Put(s.ctx, fs.ObjectPair{Src: x, Dst: nil})
+			if !ok {
+				return
+			}
 		} else {
 			// No need to check since doesn't exist
 			ok := s.toBeUploaded.Put(s.ctx, fs.ObjectPair{Src: x, Dst: nil})
@@ -799,7 +859,7 @@ func (s *syncCopyMove) SrcOnly(src fs.DirEntry) (recurse bool) {
 }
 
 // Match is called when src and dst are present, so sync src to dst
-func (s *syncCopyMove) Match(dst, src fs.DirEntry) (recurse bool) {
+func (s *syncCopyMove) Match(ctx context.Context, dst, src fs.DirEntry) (recurse bool) {
 	switch srcX := src.(type) {
 	case fs.Object:
 		s.srcEmptyDirsMu.Lock()
@@ -820,6 +880,7 @@ func (s *syncCopyMove) Match(dst, src fs.DirEntry) (recurse bool) {
 			err := errors.New("can't overwrite directory with file")
 			fs.Errorf(dst, "%v", err)
 			s.processError(err)
+			s.reports.Report(operations.ReportError, src.Remote())
 		}
 	case fs.Directory:
 		// Do the same thing to the entire contents of the directory
@@ -836,6 +897,7 @@ func (s *syncCopyMove) Match(dst, src fs.DirEntry) (recurse bool) {
 		err := errors.New("can't overwrite file with directory")
 		fs.Errorf(dst, "%v", err)
 		s.processError(err)
+		s.reports.Report(operations.ReportError, src.Remote())
 	default:
 		panic("Bad object in DirEntries")
 	}
@@ -849,17 +911,19 @@ func (s *syncCopyMove) Match(dst, src fs.DirEntry) (recurse bool) {
 // If DoMove is true then files will be moved instead of copied
 //
 // dir is the start directory, "" for root
-func runSyncCopyMove(fdst, fsrc fs.Fs, deleteMode fs.DeleteMode, DoMove bool, deleteEmptySrcDirs bool) error {
+func runSyncCopyMove(ctx context.Context, fdst, fsrc fs.Fs, deleteMode fs.DeleteMode, DoMove bool, deleteEmptySrcDirs bool) error {
 	if deleteMode != fs.DeleteModeOff && DoMove {
 		return fserrors.FatalError(errors.New("can't delete and move at the same time"))
 	}
+	// Both passes put backups in the same --backup-dir snapshot
+	backupTime := time.Now()
 	// Run an extra pass to delete only
 	if deleteMode == fs.DeleteModeBefore {
 		if fs.Config.TrackRenames {
 			return fserrors.FatalError(errors.New("can't use --delete-before with --track-renames"))
 		}
 		// only delete stuff during in this pass
-		do, err := newSyncCopyMove(fdst, fsrc, fs.DeleteModeOnly, false, deleteEmptySrcDirs)
+		do, err := newSyncCopyMove(ctx, fdst, fsrc, fs.DeleteModeOnly, false, deleteEmptySrcDirs, backupTime)
 		if err != nil {
 			return err
 		}
@@ -870,30 +934,35 @@ func runSyncCopyMove(fdst, fsrc fs.Fs, deleteMode fs.DeleteMode, DoMove bool, de
 		// Next pass does a copy only
 		deleteMode = fs.DeleteModeOff
 	}
-	do, err := newSyncCopyMove(fdst, fsrc, deleteMode, DoMove, deleteEmptySrcDirs)
+	do, err := newSyncCopyMove(ctx, fdst, fsrc, deleteMode, DoMove, deleteEmptySrcDirs, backupTime)
+	if err != nil {
+		return err
+	}
+	err = do.run()
 	if err != nil {
 		return err
 	}
-	return do.run()
+	// Remove old --backup-dir snapshots if required
+	return operations.PruneBackupDir(ctx, backupTime)
 }
 
 // Sync fsrc into fdst
-func Sync(fdst, fsrc fs.Fs) error {
-	return runSyncCopyMove(fdst, fsrc, fs.Config.DeleteMode, false, false)
+func Sync(ctx context.Context, fdst, fsrc fs.Fs) error {
+	return runSyncCopyMove(ctx, fdst, fsrc, fs.Config.DeleteMode, false, false)
 }
 
 // CopyDir copies fsrc into fdst
-func CopyDir(fdst, fsrc fs.Fs) error {
-	return runSyncCopyMove(fdst, fsrc, fs.DeleteModeOff, false, false)
+func CopyDir(ctx context.Context, fdst, fsrc fs.Fs) error {
+	return runSyncCopyMove(ctx, fdst, fsrc, fs.DeleteModeOff, false, false)
 }
 
 // moveDir moves fsrc into fdst
-func moveDir(fdst, fsrc fs.Fs, deleteEmptySrcDirs bool) error {
-	return runSyncCopyMove(fdst, fsrc, fs.DeleteModeOff, true, deleteEmptySrcDirs)
+func moveDir(ctx context.Context, fdst, fsrc fs.Fs, deleteEmptySrcDirs bool) error {
+	return runSyncCopyMove(ctx, fdst, fsrc, fs.DeleteModeOff, true, deleteEmptySrcDirs)
 }
 
 // MoveDir moves fsrc into fdst
-func MoveDir(fdst, fsrc fs.Fs, deleteEmptySrcDirs bool) error {
+func MoveDir(ctx context.Context, fdst, fsrc fs.Fs, deleteEmptySrcDirs bool) error {
 	if operations.Same(fdst, fsrc) {
 		fs.Errorf(fdst, "Nothing to do as source and destination are the same")
 		return nil
@@ -906,7 +975,7 @@ func MoveDir(fdst, fsrc fs.Fs, deleteEmptySrcDirs bool) error {
 			return nil
 		}
 		fs.Debugf(fdst, "Using server side directory move")
-		err := fdstDirMove(fsrc, "", "")
+		err := fdstDirMove(ctx, fsrc, "", "")
 		switch err {
 		case fs.ErrorCantDirMove, fs.ErrorDirExists:
 			fs.Infof(fdst, "Server side directory move failed - fallback to file moves: %v", err)
@@ -914,7 +983,7 @@ func MoveDir(fdst, fsrc fs.Fs, deleteEmptySrcDirs bool) error {
 			fs.Infof(fdst, "Server side directory move succeeded")
 			return nil
 		default:
-			fs.CountError(err)
+			fs.CountError(ctx, err)
 			fs.Errorf(fdst, "Server side directory move failed: %v", err)
 			return err
 		}
@@ -928,5 +997,5 @@ func MoveDir(fdst, fsrc fs.Fs, deleteEmptySrcDirs bool) error {
 	}
 
 	// Otherwise move the files one by one
-	return moveDir(fdst, fsrc, deleteEmptySrcDirs)
+	return moveDir(ctx, fdst, fsrc, deleteEmptySrcDirs)
 }
@@ -3,7 +3,11 @@
 package sync
 
 import (
+	"bytes"
+	"context"
 	"runtime"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -33,13 +37,14 @@ func TestMain(m *testing.M) {
 
 // Check dry run is working
 func TestCopyWithDryRun(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteFile("sub dir/hello world", "hello world", t1)
 	r.Mkdir(r.Fremote)
 
 	fs.Config.DryRun = true
-	err := CopyDir(r.Fremote, r.Flocal)
+	err := CopyDir(ctx, r.Fremote, r.Flocal)
 	fs.Config.DryRun = false
 	require.NoError(t, err)
 
@@ -49,20 +54,45 @@ func TestCopyWithDryRun(t *testing.T) {
 
 // Now without dry run
 func TestCopy(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteFile("sub dir/hello world", "hello world", t1)
 	r.Mkdir(r.Fremote)
 
-	err := CopyDir(r.Fremote, r.Flocal)
+	err := CopyDir(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Flocal, file1)
 	fstest.CheckItems(t, r.Fremote, file1)
 }
 
+// Copy in a stats group
+func TestCopyStatsGroup(t *testing.T) {
+	ctx := accounting.WithStatsGroup(context.Background(), "test/sync")
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	file1 := r.WriteFile("sub dir/hello world", "hello world", t1)
+	r.Mkdir(r.Fremote)
+
+	stats := accounting.GetStats(ctx)
+	oldTransfers := accounting.Stats.GetTransfers()
+	err := CopyDir(ctx, r.Fremote, r.Flocal)
+	require.NoError(t, err)
+
+	fstest.CheckItems(t, r.Fremote, file1)
+	assert.Equal(t, int64(1), stats.GetTransfers())
+	assert.Equal(t, int64(11), stats.GetBytes())
+	assert.Equal(t, oldTransfers+1, accounting.Stats.GetTransfers())
+	transferred := stats.Transferred()
+	require.Equal(t, 1, len(transferred))
+	assert.Equal(t, "sub dir/hello world", transferred[0].Name)
+	assert.Equal(t, int64(11), transferred[0].Bytes)
+}
+
 // Now with --no-traverse
 func TestCopyNoTraverse(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
@@ -71,7 +101,7 @@ func TestCopyNoTraverse(t *testing.T) {
 
 	file1 := r.WriteFile("sub dir/hello world", "hello world", t1)
 
-	err := CopyDir(r.Fremote, r.Flocal)
+	err := CopyDir(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Flocal, file1)
@@ -80,6 +110,7 @@ func TestCopyNoTraverse(t *testing.T) {
 
 // Now with --no-traverse
 func TestSyncNoTraverse(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
@@ -89,7 +120,7 @@ func TestSyncNoTraverse(t *testing.T) {
 	file1 := r.WriteFile("sub dir/hello world", "hello world", t1)
 
 	accounting.Stats.ResetCounters()
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Flocal, file1)
@@ -98,6 +129,7 @@ func TestSyncNoTraverse(t *testing.T) {
 
 // Test copy with depth
 func TestCopyWithDepth(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteFile("sub dir/hello world", "hello world", t1)
@@ -107,7 +139,7 @@ func TestCopyWithDepth(t *testing.T) {
 	fs.Config.MaxDepth = 1
 	defer func() { fs.Config.MaxDepth = -1 }()
 
-	err := CopyDir(r.Fremote, r.Flocal)
+	err := CopyDir(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Flocal, file1, file2)
@@ -116,6 +148,7 @@ func TestCopyWithDepth(t *testing.T) {
 
 // Test copy with files from
 func TestCopyWithFilesFrom(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteFile("potato2", "hello world", t1)
@@ -135,7 +168,7 @@ func TestCopyWithFilesFrom(t *testing.T) {
 	}
 	defer unpatch()
 
-	err = CopyDir(r.Fremote, r.Flocal)
+	err = CopyDir(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	unpatch()
 
@@ -145,14 +178,15 @@ func TestCopyWithFilesFrom(t *testing.T) {
 
 // Test copy empty directories
 func TestCopyEmptyDirectories(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteFile("sub dir/hello world", "hello world", t1)
-	err := operations.Mkdir(r.Flocal, "sub dir2")
+	err := operations.Mkdir(ctx, r.Flocal, "sub dir2")
 	require.NoError(t, err)
 	r.Mkdir(r.Fremote)
 
-	err = CopyDir(r.Fremote, r.Flocal)
+	err = CopyDir(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckListingWithPrecision(
@@ -171,6 +205,7 @@ func TestCopyEmptyDirectories(t *testing.T) {
 
 // Test a server side copy if possible, or the backup path if not
 func TestServerSideCopy(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteObject("sub dir/hello world", "hello world", t1)
@@ -181,7 +216,7 @@ func TestServerSideCopy(t *testing.T) {
 	defer finaliseCopy()
 	t.Logf("Server side copy (if possible) %v -> %v", r.Fremote, FremoteCopy)
 
-	err = CopyDir(FremoteCopy, r.Fremote)
+	err = CopyDir(ctx, FremoteCopy, r.Fremote)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, FremoteCopy, file1)
@@ -190,16 +225,17 @@ func TestServerSideCopy(t *testing.T) {
 // Check that if the local file doesn't exist when we copy it up,
 // nothing happens to the remote file
 func TestCopyAfterDelete(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteObject("sub dir/hello world", "hello world", t1)
 	fstest.CheckItems(t, r.Flocal)
 	fstest.CheckItems(t, r.Fremote, file1)
 
-	err := operations.Mkdir(r.Flocal, "")
+	err := operations.Mkdir(ctx, r.Flocal, "")
 	require.NoError(t, err)
 
-	err = CopyDir(r.Fremote, r.Flocal)
+	err = CopyDir(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Flocal)
@@ -208,12 +244,13 @@ func TestCopyAfterDelete(t *testing.T) {
 
 // Check the copy downloading a file
 func TestCopyRedownload(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteObject("sub dir/hello world", "hello world", t1)
 	fstest.CheckItems(t, r.Fremote, file1)
 
-	err := CopyDir(r.Flocal, r.Fremote)
+	err := CopyDir(ctx, r.Flocal, r.Fremote)
 	require.NoError(t, err)
 
 	// Test with combined precision of local and remote as we copied it there and back
@@ -224,6 +261,7 @@ func TestCopyRedownload(t *testing.T) {
 // If we're only doing sync by size and checksum, we expect nothing to
 // to be transferred on the second sync.
 func TestSyncBasedOnCheckSum(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	fs.Config.CheckSum = true
@@ -233,7 +271,7 @@ func TestSyncBasedOnCheckSum(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file1)
 
 	accounting.Stats.ResetCounters()
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	// We should have transferred exactly one file.
@@ -245,7 +283,7 @@ func TestSyncBasedOnCheckSum(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file2)
 
 	accounting.Stats.ResetCounters()
-	err = Sync(r.Fremote, r.Flocal)
+	err = Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	// We should have transferred no files
@@ -258,6 +296,7 @@ func TestSyncBasedOnCheckSum(t *testing.T) {
 // file contents but not the size.  If we're only doing sync by size
 // only, we expect nothing to to be transferred on the second sync.
 func TestSyncSizeOnly(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	fs.Config.SizeOnly = true
@@ -267,7 +306,7 @@ func TestSyncSizeOnly(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file1)
 
 	accounting.Stats.ResetCounters()
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	// We should have transferred exactly one file.
@@ -279,7 +318,7 @@ func TestSyncSizeOnly(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file2)
 
 	accounting.Stats.ResetCounters()
-	err = Sync(r.Fremote, r.Flocal)
+	err = Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	// We should have transferred no files
@@ -292,6 +331,7 @@ func TestSyncSizeOnly(t *testing.T) {
 // the size.  With --ignore-size we expect nothing to to be
 // transferred on the second sync.
 func TestSyncIgnoreSize(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	fs.Config.IgnoreSize = true
@@ -301,7 +341,7 @@ func TestSyncIgnoreSize(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file1)
 
 	accounting.Stats.ResetCounters()
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	// We should have transferred exactly one file.
@@ -313,7 +353,7 @@ func TestSyncIgnoreSize(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file2)
 
 	accounting.Stats.ResetCounters()
-	err = Sync(r.Fremote, r.Flocal)
+	err = Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	// We should have transferred no files
@@ -323,13 +363,14 @@ func TestSyncIgnoreSize(t *testing.T) {
 }
 
 func TestSyncIgnoreTimes(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteBoth("existing", "potato", t1)
 	fstest.CheckItems(t, r.Fremote, file1)
 
 	accounting.Stats.ResetCounters()
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	// We should have transferred exactly 0 files because the
@@ -340,7 +381,7 @@ func TestSyncIgnoreTimes(t *testing.T) {
 	defer func() { fs.Config.IgnoreTimes = false }()
 
 	accounting.Stats.ResetCounters()
-	err = Sync(r.Fremote, r.Flocal)
+	err = Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	// We should have transferred exactly one file even though the
@@ -352,6 +393,7 @@ func TestSyncIgnoreTimes(t *testing.T) {
 }
 
 func TestSyncIgnoreExisting(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteFile("existing", "potato", t1)
@@ -360,7 +402,7 @@ func TestSyncIgnoreExisting(t *testing.T) {
 	defer func() { fs.Config.IgnoreExisting = false }()
 
 	accounting.Stats.ResetCounters()
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file1)
 	fstest.CheckItems(t, r.Fremote, file1)
@@ -368,13 +410,14 @@ func TestSyncIgnoreExisting(t *testing.T) {
 	// Change everything
 	r.WriteFile("existing", "newpotatoes", t2)
 	accounting.Stats.ResetCounters()
-	err = Sync(r.Fremote, r.Flocal)
+	err = Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	// Items should not change
 	fstest.CheckItems(t, r.Fremote, file1)
 }
 
 func TestSyncIgnoreErrors(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	fs.Config.IgnoreErrors = true
 	defer func() {
@@ -384,7 +427,7 @@ func TestSyncIgnoreErrors(t *testing.T) {
 	file1 := r.WriteFile("a/potato2", "------------------------------------------------------------", t1)
 	file2 := r.WriteObject("b/potato", "SMALLER BUT SAME DATE", t2)
 	file3 := r.WriteBoth("c/non empty space", "AhHa!", t2)
-	require.NoError(t, operations.Mkdir(r.Fremote, "d"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "d"))
 
 	fstest.CheckListingWithPrecision(
 		t,
@@ -415,8 +458,8 @@ func TestSyncIgnoreErrors(t *testing.T) {
 	)
 
 	accounting.Stats.ResetCounters()
-	fs.CountError(nil)
-	assert.NoError(t, Sync(r.Fremote, r.Flocal))
+	fs.CountError(ctx, nil)
+	assert.NoError(t, Sync(ctx, r.Fremote, r.Flocal))
 
 	fstest.CheckListingWithPrecision(
 		t,
@@ -447,6 +490,7 @@ func TestSyncIgnoreErrors(t *testing.T) {
 }
 
 func TestSyncAfterChangingModtimeOnly(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteFile("empty space", "", t2)
@@ -459,7 +503,7 @@ func TestSyncAfterChangingModtimeOnly(t *testing.T) {
 	defer func() { fs.Config.DryRun = false }()
 
 	accounting.Stats.ResetCounters()
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Flocal, file1)
@@ -468,7 +512,7 @@ func TestSyncAfterChangingModtimeOnly(t *testing.T) {
 	fs.Config.DryRun = false
 
 	accounting.Stats.ResetCounters()
-	err = Sync(r.Fremote, r.Flocal)
+	err = Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Flocal, file1)
@@ -476,6 +520,7 @@ func TestSyncAfterChangingModtimeOnly(t *testing.T) {
 }
 
 func TestSyncAfterChangingModtimeOnlyWithNoUpdateModTime(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
@@ -496,7 +541,7 @@ func TestSyncAfterChangingModtimeOnlyWithNoUpdateModTime(t *testing.T) {
 	fstest.CheckItems(t, r.Fremote, file2)
 
 	accounting.Stats.ResetCounters()
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Flocal, file1)
@@ -504,6 +549,7 @@ func TestSyncAfterChangingModtimeOnlyWithNoUpdateModTime(t *testing.T) {
 }
 
 func TestSyncDoesntUpdateModtime(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	if fs.GetModifyWindow(r.Fremote) == fs.ModTimeNotSupported {
@@ -517,7 +563,7 @@ func TestSyncDoesntUpdateModtime(t *testing.T) {
 	fstest.CheckItems(t, r.Fremote, file2)
 
 	accounting.Stats.ResetCounters()
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Flocal, file1)
@@ -528,6 +574,7 @@ func TestSyncDoesntUpdateModtime(t *testing.T) {
 }
 
 func TestSyncAfterAddingAFile(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteBoth("empty space", "", t2)
@@ -537,13 +584,14 @@ func TestSyncAfterAddingAFile(t *testing.T) {
 	fstest.CheckItems(t, r.Fremote, file1)
 
 	accounting.Stats.ResetCounters()
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file1, file2)
 	fstest.CheckItems(t, r.Fremote, file1, file2)
 }
 
 func TestSyncAfterChangingFilesSizeOnly(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteObject("potato", "------------------------------------------------------------", t3)
@@ -552,7 +600,7 @@ func TestSyncAfterChangingFilesSizeOnly(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file2)
 
 	accounting.Stats.ResetCounters()
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file2)
 	fstest.CheckItems(t, r.Fremote, file2)
@@ -561,6 +609,7 @@ func TestSyncAfterChangingFilesSizeOnly(t *testing.T) {
 // Sync after changing a file's contents, changing modtime but length
 // remaining the same
 func TestSyncAfterChangingContentsOnly(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	var file1 fstest.Item
@@ -575,7 +624,7 @@ func TestSyncAfterChangingContentsOnly(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file2)
 
 	accounting.Stats.ResetCounters()
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file2)
 	fstest.CheckItems(t, r.Fremote, file2)
@@ -583,6 +632,7 @@ func TestSyncAfterChangingContentsOnly(t *testing.T) {
 
 // Sync after removing a file and adding a file --dry-run
 func TestSyncAfterRemovingAFileAndAddingAFileDryRun(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteFile("potato2", "------------------------------------------------------------", t1)
@@ -591,7 +641,7 @@ func TestSyncAfterRemovingAFileAndAddingAFileDryRun(t *testing.T) {
 
 	fs.Config.DryRun = true
 	accounting.Stats.ResetCounters()
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	fs.Config.DryRun = false
 	require.NoError(t, err)
 
@@ -601,6 +651,7 @@ func TestSyncAfterRemovingAFileAndAddingAFileDryRun(t *testing.T) {
 
 // Sync after removing a file and adding a file
 func TestSyncAfterRemovingAFileAndAddingAFile(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteFile("potato2", "------------------------------------------------------------", t1)
@@ -610,7 +661,7 @@ func TestSyncAfterRemovingAFileAndAddingAFile(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file1, file3)
 
 	accounting.Stats.ResetCounters()
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file1, file3)
 	fstest.CheckItems(t, r.Fremote, file1, file3)
@@ -618,13 +669,14 @@ func TestSyncAfterRemovingAFileAndAddingAFile(t *testing.T) {
 
 // Sync after removing a file and adding a file
 func TestSyncAfterRemovingAFileAndAddingAFileSubDir(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteFile("a/potato2", "------------------------------------------------------------", t1)
 	file2 := r.WriteObject("b/potato", "SMALLER BUT SAME DATE", t2)
 	file3 := r.WriteBoth("c/non empty space", "AhHa!", t2)
-	require.NoError(t, operations.Mkdir(r.Fremote, "d"))
-	require.NoError(t, operations.Mkdir(r.Fremote, "d/e"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "d"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "d/e"))
 
 	fstest.CheckListingWithPrecision(
 		t,
@@ -656,7 +708,7 @@ func TestSyncAfterRemovingAFileAndAddingAFileSubDir(t *testing.T) {
 	)
 
 	accounting.Stats.ResetCounters()
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckListingWithPrecision(
@@ -689,12 +741,13 @@ func TestSyncAfterRemovingAFileAndAddingAFileSubDir(t *testing.T) {
 
 // Sync after removing a file and adding a file with IO Errors
 func TestSyncAfterRemovingAFileAndAddingAFileSubDirWithErrors(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteFile("a/potato2", "------------------------------------------------------------", t1)
 	file2 := r.WriteObject("b/potato", "SMALLER BUT SAME DATE", t2)
 	file3 := r.WriteBoth("c/non empty space", "AhHa!", t2)
-	require.NoError(t, operations.Mkdir(r.Fremote, "d"))
+	require.NoError(t, operations.Mkdir(ctx, r.Fremote, "d"))
 
 	fstest.CheckListingWithPrecision(
 		t,
@@ -725,8 +778,8 @@ func TestSyncAfterRemovingAFileAndAddingAFileSubDirWithErrors(t *testing.T) {
 	)
 
 	accounting.Stats.ResetCounters()
-	fs.CountError(nil)
-	err := Sync(r.Fremote, r.Flocal)
+	fs.CountError(ctx, nil)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	assert.Equal(t, fs.ErrorNotDeleting, err)
 
 	fstest.CheckListingWithPrecision(
@@ -789,6 +842,7 @@ func TestSyncDeleteBefore(t *testing.T) {
 
 // Copy test delete before - shouldn't delete anything
 func TestCopyDeleteBefore(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
@@ -803,7 +857,7 @@ func TestCopyDeleteBefore(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file2)
 
 	accounting.Stats.ResetCounters()
-	err := CopyDir(r.Fremote, r.Flocal)
+	err := CopyDir(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	fstest.CheckItems(t, r.Fremote, file1, file2)
@@ -812,6 +866,7 @@ func TestCopyDeleteBefore(t *testing.T) {
 
 // Test with exclude
 func TestSyncWithExclude(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteBoth("potato2", "------------------------------------------------------------", t1)
@@ -826,20 +881,21 @@ func TestSyncWithExclude(t *testing.T) {
 	}()
 
 	accounting.Stats.ResetCounters()
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Fremote, file2, file1)
 
 	// Now sync the other way round and check enormous doesn't get
 	// deleted as it is excluded from the sync
 	accounting.Stats.ResetCounters()
-	err = Sync(r.Flocal, r.Fremote)
+	err = Sync(ctx, r.Flocal, r.Fremote)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file2, file1, file3)
 }
 
 // Test with exclude and delete excluded
 func TestSyncWithExcludeAndDeleteExcluded(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteBoth("potato2", "------------------------------------------------------------", t1) // 60 bytes
@@ -856,20 +912,21 @@ func TestSyncWithExcludeAndDeleteExcluded(t *testing.T) {
 	}()
 
 	accounting.Stats.ResetCounters()
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Fremote, file2)
 
 	// Check sync the other way round to make sure enormous gets
 	// deleted even though it is excluded
 	accounting.Stats.ResetCounters()
-	err = Sync(r.Flocal, r.Fremote)
+	err = Sync(ctx, r.Flocal, r.Fremote)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file2)
 }
 
 // Test with UpdateOlder set
 func TestSyncWithUpdateOlder(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	if fs.GetModifyWindow(r.Fremote) == fs.ModTimeNotSupported {
@@ -898,13 +955,14 @@ func TestSyncWithUpdateOlder(t *testing.T) {
 	}()
 
 	accounting.Stats.ResetCounters()
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Fremote, oneO, twoF, threeO, fourF, fiveF)
 }
 
 // Test with TrackRenames set
 func TestSyncWithTrackRenames(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
@@ -922,7 +980,7 @@ func TestSyncWithTrackRenames(t *testing.T) {
 	f2 := r.WriteFile("yam", "Yam Content", t2)
 
 	accounting.Stats.ResetCounters()
-	require.NoError(t, Sync(r.Fremote, r.Flocal))
+	require.NoError(t, Sync(ctx, r.Fremote, r.Flocal))
 
 	fstest.CheckItems(t, r.Fremote, f1, f2)
 	fstest.CheckItems(t, r.Flocal, f1, f2)
@@ -931,7 +989,7 @@ func TestSyncWithTrackRenames(t *testing.T) {
 	f2 = r.RenameFile(f2, "yaml")
 
 	accounting.Stats.ResetCounters()
-	require.NoError(t, Sync(r.Fremote, r.Flocal))
+	require.NoError(t, Sync(ctx, r.Fremote, r.Flocal))
 
 	fstest.CheckItems(t, r.Fremote, f1, f2)
 
@@ -944,6 +1002,7 @@ func TestSyncWithTrackRenames(t *testing.T) {
 
 // Test a server side move if possible, or the backup path if not
 func testServerSideMove(t *testing.T, r *fstest.Run, withFilter, testDeleteEmptyDirs bool) {
+	ctx := context.Background()
 	FremoteMove, _, finaliseMove, err := fstest.RandomRemote(*fstest.RemoteName, *fstest.SubDir)
 	require.NoError(t, err)
 	defer finaliseMove()
@@ -953,7 +1012,7 @@ func testServerSideMove(t *testing.T, r *fstest.Run, withFilter, testDeleteEmpty
 	file3u := r.WriteBoth("potato3", "------------------------------------------------------------ UPDATED", t2)
 
 	if testDeleteEmptyDirs {
-		err := operations.Mkdir(r.Fremote, "tomatoDir")
+		err := operations.Mkdir(ctx, r.Fremote, "tomatoDir")
 		require.NoError(t, err)
 	}
 
@@ -968,7 +1027,7 @@ func testServerSideMove(t *testing.T, r *fstest.Run, withFilter, testDeleteEmpty
 
 	// Do server side move
 	accounting.Stats.ResetCounters()
-	err = MoveDir(FremoteMove, r.Fremote, testDeleteEmptyDirs)
+	err = MoveDir(ctx, FremoteMove, r.Fremote, testDeleteEmptyDirs)
 	require.NoError(t, err)
 
 	if withFilter {
@@ -989,13 +1048,13 @@ func testServerSideMove(t *testing.T, r *fstest.Run, withFilter, testDeleteEmpty
 	defer finaliseMove2()
 
 	if testDeleteEmptyDirs {
-		err := operations.Mkdir(FremoteMove, "tomatoDir")
+		err := operations.Mkdir(ctx, FremoteMove, "tomatoDir")
 		require.NoError(t, err)
 	}
 
 	// Move it back to a new empty remote, dst does not exist this time
 	accounting.Stats.ResetCounters()
-	err = MoveDir(FremoteMove2, FremoteMove, testDeleteEmptyDirs)
+	err = MoveDir(ctx, FremoteMove2, FremoteMove, testDeleteEmptyDirs)
 	require.NoError(t, err)
 
 	if withFilter {
@@ -1013,6 +1072,7 @@ func testServerSideMove(t *testing.T, r *fstest.Run, withFilter, testDeleteEmpty
 
 // Test move
 func TestMoveWithDeleteEmptySrcDirs(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteFile("sub dir/hello world", "hello world", t1)
@@ -1020,7 +1080,7 @@ func TestMoveWithDeleteEmptySrcDirs(t *testing.T) {
 	r.Mkdir(r.Fremote)
 
 	// run move with --delete-empty-src-dirs
-	err := MoveDir(r.Fremote, r.Flocal, true)
+	err := MoveDir(ctx, r.Fremote, r.Flocal, true)
 	require.NoError(t, err)
 
 	fstest.CheckListingWithPrecision(
@@ -1034,13 +1094,14 @@ func TestMoveWithDeleteEmptySrcDirs(t *testing.T) {
 }
 
 func TestMoveWithoutDeleteEmptySrcDirs(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 	file1 := r.WriteFile("sub dir/hello world", "hello world", t1)
 	file2 := r.WriteFile("nested/sub dir/file", "nested", t1)
 	r.Mkdir(r.Fremote)
 
-	err := MoveDir(r.Fremote, r.Flocal, false)
+	err := MoveDir(ctx, r.Fremote, r.Flocal, false)
 	require.NoError(t, err)
 
 	fstest.CheckListingWithPrecision(
@@ -1086,6 +1147,7 @@ func TestServerSideMoveDeleteEmptySourceDirs(t *testing.T) {
 
 // Test a server side move with overlap
 func TestServerSideMoveOverlap(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
@@ -1101,7 +1163,7 @@ func TestServerSideMoveOverlap(t *testing.T) {
 	fstest.CheckItems(t, r.Fremote, file1)
 
 	// Subdir move with no filters should return ErrorCantMoveOverlapping
-	err = MoveDir(FremoteMove, r.Fremote, false)
+	err = MoveDir(ctx, FremoteMove, r.Fremote, false)
 	assert.EqualError(t, err, fs.ErrorCantMoveOverlapping.Error())
 
 	// Now try with a filter which should also fail with ErrorCantMoveOverlapping
@@ -1109,12 +1171,13 @@ func TestServerSideMoveOverlap(t *testing.T) {
 	defer func() {
 		filter.Active.Opt.MinSize = -1
 	}()
-	err = MoveDir(FremoteMove, r.Fremote, false)
+	err = MoveDir(ctx, FremoteMove, r.Fremote, false)
 	assert.EqualError(t, err, fs.ErrorCantMoveOverlapping.Error())
 }
 
 // Test with BackupDir set
 func testSyncBackupDir(t *testing.T, suffix string) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
@@ -1145,7 +1208,7 @@ func testSyncBackupDir(t *testing.T, suffix string) {
 	require.NoError(t, err)
 
 	accounting.Stats.ResetCounters()
-	err = Sync(fdst, r.Flocal)
+	err = Sync(ctx, fdst, r.Flocal)
 	require.NoError(t, err)
 
 	// one should be moved to the backup dir and the new one installed
@@ -1166,7 +1229,7 @@ func testSyncBackupDir(t *testing.T, suffix string) {
 	// This should delete three and overwrite one again, checking
 	// the files got overwritten correctly in backup-dir
 	accounting.Stats.ResetCounters()
-	err = Sync(fdst, r.Flocal)
+	err = Sync(ctx, fdst, r.Flocal)
 	require.NoError(t, err)
 
 	// one should be moved to the backup dir and the new one installed
@@ -1181,8 +1244,162 @@ func testSyncBackupDir(t *testing.T, suffix string) {
 func TestSyncBackupDir(t *testing.T)           { testSyncBackupDir(t, "") }
 func TestSyncBackupDirWithSuffix(t *testing.T) { testSyncBackupDir(t, ".bak") }
 
+// Test with --backup-dir-format and --backup-dir-max-age set
+func TestSyncBackupDirSnapshots(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	if !operations.CanServerSideMove(r.Fremote) {
+		t.Skip("Skipping test as remote does not support server side move")
+	}
+	r.Mkdir(r.Fremote)
+
+	fs.Config.BackupDir = r.FremoteName + "/backup"
+	fs.Config.BackupDirFormat = "2006-01-02-150405.000000000"
+	fs.Config.Suffix = "-old"
+	fs.Config.SuffixKeepExtension = true
+	defer func() {
+		fs.Config.BackupDir = ""
+		fs.Config.BackupDirFormat = ""
+		fs.Config.BackupDirMaxAge = fs.DurationOff
+		fs.Config.Suffix = ""
+		fs.Config.SuffixKeepExtension = false
+	}()
+
+	fdst, err := fs.NewFs(r.FremoteName + "/dst")
+	require.NoError(t, err)
+
+	// snapshots returns the names of the snapshot directories
+	snapshots := func() (names []string) {
+		entries, err := r.Fremote.List(ctx, "backup")
+		require.NoError(t, err)
+		for _, entry := range entries {
+			names = append(names, entry.Remote())
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	// Each sync which replaces one.txt should make a new snapshot
+	var items []fstest.Item
+	old := r.WriteObject("dst/one.txt", "one", t1)
+	for i, contents := range []string{"oneA", "oneBB"} {
+		file1 := r.WriteFile("one.txt", contents, t2.Add(time.Duration(i)*time.Hour))
+		accounting.Stats.ResetCounters()
+		err = Sync(ctx, fdst, r.Flocal)
+		require.NoError(t, err)
+		names := snapshots()
+		require.Equal(t, i+1, len(names))
+		old.Path = names[i] + "/one-old.txt"
+		items = append(items, old)
+		old = file1
+		old.Path = "dst/one.txt"
+		fstest.CheckItems(t, r.Fremote, append([]fstest.Item{old}, items...)...)
+	}
+
+	// With --backup-dir-max-age the older snapshots are removed
+	fs.Config.BackupDirMaxAge = fs.Duration(time.Nanosecond)
+	file1c := r.WriteFile("one.txt", "oneCCC", t3)
+	accounting.Stats.ResetCounters()
+	err = Sync(ctx, fdst, r.Flocal)
+	require.NoError(t, err)
+	names := snapshots()
+	require.Equal(t, 1, len(names))
+	file1c.Path = "dst/one.txt"
+	fstest.CheckItems(t, r.Fremote, file1c, fstest.NewItem(names[0]+"/one-old.txt", "oneBB", t2.Add(time.Hour)))
+}
+
+// Test with --compare-dest set
+func TestSyncCompareDest(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	fs.Config.CompareDest = []string{r.FremoteName + "/cmp", r.FremoteName + "/cmp2"}
+	defer func() {
+		fs.Config.CompareDest = nil
+	}()
+
+	fdst, err := fs.NewFs(r.FremoteName + "/dst")
+	require.NoError(t, err)
+
+	// one and two are in the compare dirs so shouldn't be copied
+	file1 := r.WriteObject("cmp/one", "one", t1)
+	file2 := r.WriteObject("cmp2/two", "two", t1)
+	file1a := r.WriteFile("one", "one", t1)
+	file2a := r.WriteFile("two", "two", t1)
+	file3a := r.WriteFile("three", "three", t1)
+	fstest.CheckItems(t, r.Flocal, file1a, file2a, file3a)
+
+	accounting.Stats.ResetCounters()
+	err = Sync(ctx, fdst, r.Flocal)
+	require.NoError(t, err)
+
+	file3dst := file3a
+	file3dst.Path = "dst/three"
+	fstest.CheckItems(t, r.Fremote, file1, file2, file3dst)
+
+	// one changed in the source so should be copied now
+	file1b := r.WriteFile("one", "oneB", t2)
+	fstest.CheckItems(t, r.Flocal, file1b, file2a, file3a)
+
+	accounting.Stats.ResetCounters()
+	err = Sync(ctx, fdst, r.Flocal)
+	require.NoError(t, err)
+
+	file1dst := file1b
+	file1dst.Path = "dst/one"
+	fstest.CheckItems(t, r.Fremote, file1, file2, file1dst, file3dst)
+}
+
+// Test with --copy-dest set
+func TestSyncCopyDest(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	fs.Config.CopyDest = []string{r.FremoteName + "/cpy", r.FremoteName + "/cpy2"}
+	defer func() {
+		fs.Config.CopyDest = nil
+	}()
+
+	fdst, err := fs.NewFs(r.FremoteName + "/dst")
+	require.NoError(t, err)
+
+	// one and two are in the copy dirs so should be copied from
+	// there, two overwriting the different file in the dest
+	file1 := r.WriteObject("cpy/one", "one", t1)
+	file2 := r.WriteObject("cpy2/two", "two", t1)
+	file2dst := r.WriteObject("dst/two", "twoOld", t2)
+	file1a := r.WriteFile("one", "one", t1)
+	file2a := r.WriteFile("two", "two", t1)
+	file3a := r.WriteFile("three", "three", t1)
+	fstest.CheckItems(t, r.Fremote, file1, file2, file2dst)
+	fstest.CheckItems(t, r.Flocal, file1a, file2a, file3a)
+
+	accounting.Stats.ResetCounters()
+	err = Sync(ctx, fdst, r.Flocal)
+	require.NoError(t, err)
+
+	file1dst := file1
+	file1dst.Path = "dst/one"
+	file2dst = file2
+	file2dst.Path = "dst/two"
+	file3dst := file3a
+	file3dst.Path = "dst/three"
+	fstest.CheckItems(t, r.Fremote, file1, file2, file1dst, file2dst, file3dst)
+
+	// Syncing again shouldn't change anything
+	accounting.Stats.ResetCounters()
+	err = Sync(ctx, fdst, r.Flocal)
+	require.NoError(t, err)
+	fstest.CheckItems(t, r.Fremote, file1, file2, file1dst, file2dst, file3dst)
+}
+
 // Check we can sync two files with differing UTF-8 representations
 func TestSyncUTFNorm(t *testing.T) {
+	ctx := context.Background()
 	if runtime.GOOS == "darwin" {
 		t.Skip("Can't test UTF normalization on OS X")
 	}
@@ -1203,7 +1420,7 @@ func TestSyncUTFNorm(t *testing.T) {
 	fstest.CheckItems(t, r.Fremote, file2)
 
 	accounting.Stats.ResetCounters()
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 
 	// We should have transferred exactly one file, but kept the
@@ -1216,6 +1433,7 @@ func TestSyncUTFNorm(t *testing.T) {
 
 // Test --immutable
 func TestSyncImmutable(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
@@ -1229,7 +1447,7 @@ func TestSyncImmutable(t *testing.T) {
 
 	// Should succeed
 	accounting.Stats.ResetCounters()
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	require.NoError(t, err)
 	fstest.CheckItems(t, r.Flocal, file1)
 	fstest.CheckItems(t, r.Fremote, file1)
@@ -1241,14 +1459,60 @@ func TestSyncImmutable(t *testing.T) {
 
 	// Should fail with ErrorImmutableModified and not modify local or remote files
 	accounting.Stats.ResetCounters()
-	err = Sync(r.Fremote, r.Flocal)
+	err = Sync(ctx, r.Fremote, r.Flocal)
 	assert.EqualError(t, err, fs.ErrorImmutableModified.Error())
 	fstest.CheckItems(t, r.Flocal, file2)
 	fstest.CheckItems(t, r.Fremote, file1)
 }
 
+// testSyncReports checks sync writes the difference reports
+func testSyncReports(t *testing.T, backupDir bool) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	if backupDir {
+		if !operations.CanServerSideMove(r.Fremote) {
+			t.Skip("Skipping test as remote does not support server side move")
+		}
+		r.Mkdir(r.Fremote)
+		fs.Config.BackupDir = r.FremoteName + "/backup"
+		defer func() {
+			fs.Config.BackupDir = ""
+		}()
+	}
+
+	file1 := r.WriteFile("same", "same", t1)
+	file2 := r.WriteFile("differ", "differ new", t2)
+	file3 := r.WriteFile("srconly", "srconly", t1)
+	r.WriteObject("dst/same", "same", t1)
+	r.WriteObject("dst/differ", "differ old", t1)
+	r.WriteObject("dst/dstonly", "dstonly", t1)
+
+	fdst, err := fs.NewFs(r.FremoteName + "/dst")
+	require.NoError(t, err)
+
+	var combined bytes.Buffer
+	ctx := operations.WithReports(context.Background(), &operations.Reports{
+		Combined: &combined,
+	})
+	accounting.Stats.ResetCounters()
+	err = Sync(ctx, fdst, r.Flocal)
+	require.NoError(t, err)
+
+	fstest.CheckItems(t, r.Flocal, file1, file2, file3)
+	fstest.CheckItems(t, fdst, file1, file2, file3)
+
+	got := strings.Split(strings.TrimSpace(combined.String()), "\n")
+	sort.Strings(got)
+	assert.Equal(t, []string{"* differ", "+ srconly", "- dstonly", "= same"}, got)
+}
+
+func TestSyncReports(t *testing.T)          { testSyncReports(t, false) }
+func TestSyncReportsBackupDir(t *testing.T) { testSyncReports(t, true) }
+
 // Test that aborting on max upload works
 func TestAbort(t *testing.T) {
+	ctx := context.Background()
 	r := fstest.NewRun(t)
 	defer r.Finalise()
 
@@ -1277,6 +1541,6 @@ func TestAbort(t *testing.T) {
 
 	accounting.Stats.ResetCounters()
 
-	err := Sync(r.Fremote, r.Flocal)
+	err := Sync(ctx, r.Fremote, r.Flocal)
 	assert.Equal(t, accounting.ErrorMaxTransferLimitReached, err)
 }
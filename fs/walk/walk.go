@@ -3,6 +3,7 @@ package walk
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"path"
 	"sort"
@@ -58,38 +59,38 @@ type Func func(path string, entries fs.DirEntries, err error) error
 // those files in and then walked with WalkR
 //
 // NB (f, path) to be replaced by fs.Dir at some point
-func Walk(f fs.Fs, path string, includeAll bool, maxLevel int, fn Func) error {
+func Walk(ctx context.Context, f fs.Fs, path string, includeAll bool, maxLevel int, fn Func) error {
 	if filter.Active.HaveFilesFrom() {
-		return walkR(f, path, includeAll, maxLevel, fn, filter.Active.MakeListR(f.NewObject))
+		return walkR(ctx, f, path, includeAll, maxLevel, fn, filter.Active.MakeListR(ctx, f.NewObject))
 	}
 	if (maxLevel < 0 || maxLevel > 1) && fs.Config.UseListR && f.Features().ListR != nil {
-		return walkListR(f, path, includeAll, maxLevel, fn)
+		return walkListR(ctx, f, path, includeAll, maxLevel, fn)
 	}
-	return walkListDirSorted(f, path, includeAll, maxLevel, fn)
+	return walkListDirSorted(ctx, f, path, includeAll, maxLevel, fn)
 }
 
 // walkListDirSorted lists the directory.
 //
 // It implements Walk using non recursive directory listing.
-func walkListDirSorted(f fs.Fs, path string, includeAll bool, maxLevel int, fn Func) error {
-	return walk(f, path, includeAll, maxLevel, fn, list.DirSorted)
+func walkListDirSorted(ctx context.Context, f fs.Fs, path string, includeAll bool, maxLevel int, fn Func) error {
+	return walk(ctx, f, path, includeAll, maxLevel, fn, list.DirSorted)
 }
 
 // walkListR lists the directory.
 //
 // It implements Walk using recursive directory listing if
 // available, or returns ErrorCantListR if not.
-func walkListR(f fs.Fs, path string, includeAll bool, maxLevel int, fn Func) error {
+func walkListR(ctx context.Context, f fs.Fs, path string, includeAll bool, maxLevel int, fn Func) error {
 	listR := f.Features().ListR
 	if listR == nil {
 		return ErrorCantListR
 	}
-	return walkR(f, path, includeAll, maxLevel, fn, listR)
+	return walkR(ctx, f, path, includeAll, maxLevel, fn, listR)
 }
 
-type listDirFunc func(fs fs.Fs, includeAll bool, dir string) (entries fs.DirEntries, err error)
+type listDirFunc func(ctx context.Context, fs fs.Fs, includeAll bool, dir string) (entries fs.DirEntries, err error)
 
-func walk(f fs.Fs, path string, includeAll bool, maxLevel int, fn Func, listDir listDirFunc) error {
+func walk(ctx context.Context, f fs.Fs, path string, includeAll bool, maxLevel int, fn Func, listDir listDirFunc) error {
 	var (
 		wg         sync.WaitGroup // sync closing of go routines
 		traversing sync.WaitGroup // running directory traversals
@@ -125,7 +126,7 @@ func walk(f fs.Fs, path string, includeAll bool, maxLevel int, fn Func, listDir
 					if !ok {
 						return
 					}
-					entries, err := listDir(f, includeAll, job.remote)
+					entries, err := listDir(ctx, f, includeAll, job.remote)
 					var jobs []listJob
 					if err == nil && job.depth != 0 {
 						entries.ForDir(func(dir fs.Directory) {
@@ -142,7 +143,7 @@ func walk(f fs.Fs, path string, includeAll bool, maxLevel int, fn Func, listDir
 					// NB once we have passed entries to fn we mustn't touch it again
 					if err != nil && err != ErrorSkipDir {
 						traversing.Done()
-						fs.CountError(err)
+						fs.CountError(ctx, err)
 						fs.Errorf(job.remote, "error listing: %v", err)
 						closeQuit()
 						// Send error to error channel if space
@@ -355,14 +356,14 @@ func (dt DirTree) String() string {
 	return out.String()
 }
 
-func walkRDirTree(f fs.Fs, startPath string, includeAll bool, maxLevel int, listR fs.ListRFn) (DirTree, error) {
+func walkRDirTree(ctx context.Context, f fs.Fs, startPath string, includeAll bool, maxLevel int, listR fs.ListRFn) (DirTree, error) {
 	dirs := make(DirTree)
 	// Entries can come in arbitrary order. We use toPrune to keep
 	// all directories to exclude later.
 	toPrune := make(map[string]bool)
-	includeDirectory := filter.Active.IncludeDirectory(f)
+	includeDirectory := filter.Active.IncludeDirectory(ctx, f)
 	var mu sync.Mutex
-	err := listR(startPath, func(entries fs.DirEntries) error {
+	err := listR(ctx, startPath, func(entries fs.DirEntries) error {
 		mu.Lock()
 		defer mu.Unlock()
 		for _, entry := range entries {
@@ -432,7 +433,7 @@ func walkRDirTree(f fs.Fs, startPath string, includeAll bool, maxLevel int, list
 }
 
 // Create a DirTree using List
-func walkNDirTree(f fs.Fs, path string, includeAll bool, maxLevel int, listDir listDirFunc) (DirTree, error) {
+func walkNDirTree(ctx context.Context, f fs.Fs, path string, includeAll bool, maxLevel int, listDir listDirFunc) (DirTree, error) {
 	dirs := make(DirTree)
 	fn := func(dirPath string, entries fs.DirEntries, err error) error {
 		if err == nil {
@@ -440,7 +441,7 @@ func walkNDirTree(f fs.Fs, path string, includeAll bool, maxLevel int, listDir l
 		}
 		return err
 	}
-	err := walk(f, path, includeAll, maxLevel, fn, listDir)
+	err := walk(ctx, f, path, includeAll, maxLevel, fn, listDir)
 	if err != nil {
 		return nil, err
 	}
@@ -462,18 +463,18 @@ func walkNDirTree(f fs.Fs, path string, includeAll bool, maxLevel int, listDir l
 // those files in.
 //
 // NB (f, path) to be replaced by fs.Dir at some point
-func NewDirTree(f fs.Fs, path string, includeAll bool, maxLevel int) (DirTree, error) {
+func NewDirTree(ctx context.Context, f fs.Fs, path string, includeAll bool, maxLevel int) (DirTree, error) {
 	if filter.Active.HaveFilesFrom() {
-		return walkRDirTree(f, path, includeAll, maxLevel, filter.Active.MakeListR(f.NewObject))
+		return walkRDirTree(ctx, f, path, includeAll, maxLevel, filter.Active.MakeListR(ctx, f.NewObject))
 	}
 	if ListR := f.Features().ListR; (maxLevel < 0 || maxLevel > 1) && fs.Config.UseListR && ListR != nil {
-		return walkRDirTree(f, path, includeAll, maxLevel, ListR)
+		return walkRDirTree(ctx, f, path, includeAll, maxLevel, ListR)
 	}
-	return walkNDirTree(f, path, includeAll, maxLevel, list.DirSorted)
+	return walkNDirTree(ctx, f, path, includeAll, maxLevel, list.DirSorted)
 }
 
-func walkR(f fs.Fs, path string, includeAll bool, maxLevel int, fn Func, listR fs.ListRFn) error {
-	dirs, err := walkRDirTree(f, path, includeAll, maxLevel, listR)
+func walkR(ctx context.Context, f fs.Fs, path string, includeAll bool, maxLevel int, fn Func, listR fs.ListRFn) error {
+	dirs, err := walkRDirTree(ctx, f, path, includeAll, maxLevel, listR)
 	if err != nil {
 		return err
 	}
@@ -507,8 +508,8 @@ func walkR(f fs.Fs, path string, includeAll bool, maxLevel int, fn Func, listR f
 }
 
 // GetAll runs Walk getting all the results
-func GetAll(f fs.Fs, path string, includeAll bool, maxLevel int) (objs []fs.Object, dirs []fs.Directory, err error) {
-	err = Walk(f, path, includeAll, maxLevel, func(dirPath string, entries fs.DirEntries, err error) error {
+func GetAll(ctx context.Context, f fs.Fs, path string, includeAll bool, maxLevel int) (objs []fs.Object, dirs []fs.Directory, err error) {
+	err = Walk(ctx, f, path, includeAll, maxLevel, func(dirPath string, entries fs.DirEntries, err error) error {
 		if err != nil {
 			return err
 		}
@@ -1,6 +1,7 @@
 package walk
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"testing"
@@ -65,7 +66,7 @@ func (ls *listDirs) SetLevel(maxLevel int) *listDirs {
 }
 
 // ListDir returns the expected listing for the directory
-func (ls *listDirs) ListDir(f fs.Fs, includeAll bool, dir string) (entries fs.DirEntries, err error) {
+func (ls *listDirs) ListDir(ctx context.Context, f fs.Fs, includeAll bool, dir string) (entries fs.DirEntries, err error) {
 	ls.mu.Lock()
 	defer ls.mu.Unlock()
 	assert.Equal(ls.t, ls.fs, f)
@@ -86,7 +87,7 @@ func (ls *listDirs) ListDir(f fs.Fs, includeAll bool, dir string) (entries fs.Di
 }
 
 // ListR returns the expected listing for the directory using ListR
-func (ls *listDirs) ListR(dir string, callback fs.ListRCallback) (err error) {
+func (ls *listDirs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
 	ls.mu.Lock()
 	defer ls.mu.Unlock()
 
@@ -147,14 +148,16 @@ func (ls *listDirs) WalkFn(dir string, entries fs.DirEntries, err error) error {
 
 // Walk does the walk and tests the expectations
 func (ls *listDirs) Walk() {
-	err := walk(nil, "", ls.includeAll, ls.maxLevel, ls.WalkFn, ls.ListDir)
+	ctx := context.Background()
+	err := walk(ctx, nil, "", ls.includeAll, ls.maxLevel, ls.WalkFn, ls.ListDir)
 	assert.Equal(ls.t, ls.finalError, err)
 	ls.IsFinished()
 }
 
 // WalkR does the walkR and tests the expectations
 func (ls *listDirs) WalkR() {
-	err := walkR(nil, "", ls.includeAll, ls.maxLevel, ls.WalkFn, ls.ListR)
+	ctx := context.Background()
+	err := walkR(ctx, nil, "", ls.includeAll, ls.maxLevel, ls.WalkFn, ls.ListR)
 	assert.Equal(ls.t, ls.finalError, err)
 	if ls.finalError == nil {
 		ls.IsFinished()
@@ -262,8 +265,9 @@ func TestWalkLevelsNoRecursive10(t *testing.T)  { testWalkLevels(t, 10).Walk() }
 func TestWalkRLevelsNoRecursive10(t *testing.T) { testWalkLevels(t, 10).WalkR() }
 
 func TestWalkNDirTree(t *testing.T) {
+	ctx := context.Background()
 	ls := testWalkLevels(t, -1)
-	entries, err := walkNDirTree(nil, "", ls.includeAll, ls.maxLevel, ls.ListDir)
+	entries, err := walkNDirTree(ctx, nil, "", ls.includeAll, ls.maxLevel, ls.ListDir)
 	require.NoError(t, err)
 	assert.Equal(t, `/
   A
@@ -343,7 +347,7 @@ func testWalkErrors(t *testing.T) *listDirs {
 	em := errorMap{}
 	de := make(fs.DirEntries, 10)
 	for i := range de {
-		path := string('0' + i)
+		path := string(rune('0' + i))
 		de[i] = mockdir.New(path)
 		lr[path] = listResult{entries: nil, err: fs.ErrorDirNotFound}
 		em[path] = fs.ErrorDirNotFound
@@ -411,7 +415,7 @@ func TestWalkRMultiErrors(t *testing.T) { testWalkMultiErrors(t).Walk() }
 
 // a very simple listRcallback function
 func makeListRCallback(entries fs.DirEntries, err error) fs.ListRFn {
-	return func(dir string, callback fs.ListRCallback) error {
+	return func(ctx context.Context, dir string, callback fs.ListRCallback) error {
 		if err == nil {
 			err = callback(entries)
 		}
@@ -420,6 +424,7 @@ func makeListRCallback(entries fs.DirEntries, err error) fs.ListRFn {
 }
 
 func TestWalkRDirTree(t *testing.T) {
+	ctx := context.Background()
 	for _, test := range []struct {
 		entries fs.DirEntries
 		want    string
@@ -556,13 +561,14 @@ a/
   b/
 `, nil, "", 2},
 	} {
-		r, err := walkRDirTree(nil, test.root, true, test.level, makeListRCallback(test.entries, test.err))
+		r, err := walkRDirTree(ctx, nil, test.root, true, test.level, makeListRCallback(test.entries, test.err))
 		assert.Equal(t, test.err, err, fmt.Sprintf("%+v", test))
 		assert.Equal(t, test.want, r.String(), fmt.Sprintf("%+v", test))
 	}
 }
 
 func TestWalkRDirTreeExclude(t *testing.T) {
+	ctx := context.Background()
 	for _, test := range []struct {
 		entries     fs.DirEntries
 		want        string
@@ -627,7 +633,7 @@ b/c/d/
 `, nil, "", -1, "ign", true},
 	} {
 		filter.Active.Opt.ExcludeFile = test.excludeFile
-		r, err := walkRDirTree(nil, test.root, test.includeAll, test.level, makeListRCallback(test.entries, test.err))
+		r, err := walkRDirTree(ctx, nil, test.root, test.includeAll, test.level, makeListRCallback(test.entries, test.err))
 		assert.Equal(t, test.err, err, fmt.Sprintf("%+v", test))
 		assert.Equal(t, test.want, r.String(), fmt.Sprintf("%+v", test))
 	}
@@ -5,6 +5,7 @@ package fstest
 
 import (
 	"bytes"
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -123,11 +124,12 @@ func (i *Item) CheckModTime(t *testing.T, obj fs.Object, modTime time.Time, prec
 
 // CheckHashes checks all the hashes the object supports are correct
 func (i *Item) CheckHashes(t *testing.T, obj fs.Object) {
+	ctx := context.Background()
 	require.NotNil(t, obj)
 	types := obj.Fs().Hashes().Array()
 	for _, Hash := range types {
 		// Check attributes
-		sum, err := obj.Hash(Hash)
+		sum, err := obj.Hash(ctx, Hash)
 		require.NoError(t, err)
 		assert.True(t, hash.Equals(i.Hashes[Hash], sum), fmt.Sprintf("%s/%s: %v hash incorrect - expecting %q got %q", obj.Fs().String(), obj.Remote(), Hash, i.Hashes[Hash], sum))
 	}
@@ -269,6 +271,7 @@ func filterEmptyDirs(t *testing.T, items []Item, expectedDirs []string) (newExpe
 // directories returned is also OK as some remotes don't return
 // directories.
 func CheckListingWithPrecision(t *testing.T, f fs.Fs, items []Item, expectedDirs []string, precision time.Duration) {
+	ctx := context.Background()
 	if expectedDirs != nil && !f.Features().CanHaveEmptyDirectories {
 		expectedDirs = filterEmptyDirs(t, items, expectedDirs)
 	}
@@ -283,7 +286,7 @@ func CheckListingWithPrecision(t *testing.T, f fs.Fs, items []Item, expectedDirs
 	gotListing := "<unset>"
 	listingOK := false
 	for i := 1; i <= retries; i++ {
-		objs, dirs, err = walk.GetAll(f, "", true, -1)
+		objs, dirs, err = walk.GetAll(ctx, f, "", true, -1)
 		if err != nil && err != fs.ErrorDirNotFound {
 			t.Fatalf("Error listing: %v", err)
 		}
@@ -456,26 +459,27 @@ func RandomRemote(remoteName string, subdir bool) (fs.Fs, string, func(), error)
 //
 // It logs errors rather than returning them
 func Purge(f fs.Fs) {
+	ctx := context.Background()
 	var err error
 	doFallbackPurge := true
 	if doPurge := f.Features().Purge; doPurge != nil {
 		doFallbackPurge = false
 		fs.Debugf(f, "Purge remote")
-		err = doPurge()
+		err = doPurge(ctx)
 		if err == fs.ErrorCantPurge {
 			doFallbackPurge = true
 		}
 	}
 	if doFallbackPurge {
 		dirs := []string{""}
-		err = walk.Walk(f, "", true, -1, func(dirPath string, entries fs.DirEntries, err error) error {
+		err = walk.Walk(ctx, f, "", true, -1, func(dirPath string, entries fs.DirEntries, err error) error {
 			if err != nil {
 				log.Printf("purge walk returned error: %v", err)
 				return nil
 			}
 			entries.ForObject(func(obj fs.Object) {
 				fs.Debugf(f, "Purge object %q", obj.Remote())
-				err = obj.Remove()
+				err = obj.Remove(ctx)
 				if err != nil {
 					log.Printf("purge failed to remove %q: %v", obj.Remote(), err)
 				}
@@ -489,7 +493,7 @@ func Purge(f fs.Fs) {
 		for i := len(dirs) - 1; i >= 0; i-- {
 			dir := dirs[i]
 			fs.Debugf(f, "Purge dir %q", dir)
-			err := f.Rmdir(dir)
+			err := f.Rmdir(ctx, dir)
 			if err != nil {
 				log.Printf("purge failed to rmdir %q: %v", dir, err)
 			}
@@ -8,6 +8,7 @@ package fstests
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -113,11 +114,12 @@ func objsToNames(objs []fs.Object) []string {
 
 // findObject finds the object on the remote
 func findObject(t *testing.T, f fs.Fs, Name string) fs.Object {
+	ctx := context.Background()
 	var obj fs.Object
 	var err error
 	sleepTime := 1 * time.Second
 	for i := 1; i <= *fstest.ListRetries; i++ {
-		obj, err = f.NewObject(Name)
+		obj, err = f.NewObject(ctx, Name)
 		if err == nil {
 			break
 		}
@@ -147,6 +149,7 @@ func retry(t *testing.T, what string, f func() error) {
 
 // testPut puts file to the remote
 func testPut(t *testing.T, f fs.Fs, file *fstest.Item) (string, fs.Object) {
+	ctx := context.Background()
 	var (
 		err        error
 		obj        fs.Object
@@ -161,7 +164,7 @@ func testPut(t *testing.T, f fs.Fs, file *fstest.Item) (string, fs.Object) {
 
 		file.Size = int64(buf.Len())
 		obji := object.NewStaticObjectInfo(file.Path, file.ModTime, file.Size, true, nil, nil)
-		obj, err = f.Put(in, obji)
+		obj, err = f.Put(ctx, in, obji)
 		return err
 	})
 	file.Hashes = uploadHash.Sums()
@@ -174,6 +177,7 @@ func testPut(t *testing.T, f fs.Fs, file *fstest.Item) (string, fs.Object) {
 
 // testPutLarge puts file to the remote, checks it and removes it on success.
 func testPutLarge(t *testing.T, f fs.Fs, file *fstest.Item) {
+	ctx := context.Background()
 	var (
 		err        error
 		obj        fs.Object
@@ -185,7 +189,7 @@ func testPutLarge(t *testing.T, f fs.Fs, file *fstest.Item) {
 		in := io.TeeReader(r, uploadHash)
 
 		obji := object.NewStaticObjectInfo(file.Path, file.ModTime, file.Size, true, nil, nil)
-		obj, err = f.Put(in, obji)
+		obj, err = f.Put(ctx, in, obji)
 		return err
 	})
 	file.Hashes = uploadHash.Sums()
@@ -197,7 +201,7 @@ func testPutLarge(t *testing.T, f fs.Fs, file *fstest.Item) {
 
 	// Download the object and check it is OK
 	downloadHash := hash.NewMultiHasher()
-	download, err := obj.Open()
+	download, err := obj.Open(ctx)
 	require.NoError(t, err)
 	n, err := io.Copy(downloadHash, download)
 	require.NoError(t, err)
@@ -206,7 +210,7 @@ func testPutLarge(t *testing.T, f fs.Fs, file *fstest.Item) {
 	assert.Equal(t, file.Hashes, downloadHash.Sums())
 
 	// Remove the object
-	require.NoError(t, obj.Remove())
+	require.NoError(t, obj.Remove(ctx))
 }
 
 // errorReader just returne an error on Read
@@ -221,8 +225,9 @@ func (er errorReader) Read(p []byte) (n int, err error) {
 
 // read the contents of an object as a string
 func readObject(t *testing.T, obj fs.Object, limit int64, options ...fs.OpenOption) string {
+	ctx := context.Background()
 	what := fmt.Sprintf("readObject(%q) limit=%d, options=%+v", obj, limit, options)
-	in, err := obj.Open(options...)
+	in, err := obj.Open(ctx, options...)
 	require.NoError(t, err, what)
 	var r io.Reader = in
 	if limit >= 0 {
@@ -257,6 +262,7 @@ type Opt struct {
 // are inside the "FsMkdir" test.  Some tests require some tests files
 // - these are inside the "FsPutFiles" test.
 func Run(t *testing.T, opt *Opt) {
+	ctx := context.Background()
 	var (
 		remote        fs.Fs
 		remoteName    = opt.RemoteName
@@ -365,12 +371,12 @@ func Run(t *testing.T, opt *Opt) {
 		if isBucketBasedButNotRoot(remote) {
 			t.Skip("Skipping test as non root bucket based remote")
 		}
-		err := remote.Rmdir("")
+		err := remote.Rmdir(ctx, "")
 		assert.Error(t, err, "Expecting error on Rmdir non existent")
 	})
 
 	// Make the directory
-	err = remote.Mkdir("")
+	err = remote.Mkdir(ctx, "")
 	require.NoError(t, err)
 	fstest.CheckListing(t, remote, []fstest.Item{})
 
@@ -408,7 +414,7 @@ func Run(t *testing.T, opt *Opt) {
 	// TestFsRmdirEmpty tests deleting an empty directory
 	t.Run("FsRmdirEmpty", func(t *testing.T) {
 		skipIfNotOk(t)
-		err := remote.Rmdir("")
+		err := remote.Rmdir(ctx, "")
 		require.NoError(t, err)
 	})
 
@@ -418,26 +424,26 @@ func Run(t *testing.T, opt *Opt) {
 	t.Run("FsMkdir", func(t *testing.T) {
 		skipIfNotOk(t)
 
-		err := remote.Mkdir("")
+		err := remote.Mkdir(ctx, "")
 		require.NoError(t, err)
 		fstest.CheckListing(t, remote, []fstest.Item{})
 
-		err = remote.Mkdir("")
+		err = remote.Mkdir(ctx, "")
 		require.NoError(t, err)
 
 		// TestFsMkdirRmdirSubdir tests making and removing a sub directory
 		t.Run("FsMkdirRmdirSubdir", func(t *testing.T) {
 			skipIfNotOk(t)
 			dir := "dir/subdir"
-			err := operations.Mkdir(remote, dir)
+			err := operations.Mkdir(ctx, remote, dir)
 			require.NoError(t, err)
 			fstest.CheckListingWithPrecision(t, remote, []fstest.Item{}, []string{"dir", "dir/subdir"}, fs.GetModifyWindow(remote))
 
-			err = operations.Rmdir(remote, dir)
+			err = operations.Rmdir(ctx, remote, dir)
 			require.NoError(t, err)
 			fstest.CheckListingWithPrecision(t, remote, []fstest.Item{}, []string{"dir"}, fs.GetModifyWindow(remote))
 
-			err = operations.Rmdir(remote, "dir")
+			err = operations.Rmdir(ctx, remote, "dir")
 			require.NoError(t, err)
 			fstest.CheckListingWithPrecision(t, remote, []fstest.Item{}, []string{}, fs.GetModifyWindow(remote))
 		})
@@ -451,7 +457,7 @@ func Run(t *testing.T, opt *Opt) {
 		// TestFsListDirEmpty tests listing the directories from an empty directory
 		TestFsListDirEmpty := func(t *testing.T) {
 			skipIfNotOk(t)
-			objs, dirs, err := walk.GetAll(remote, "", true, 1)
+			objs, dirs, err := walk.GetAll(ctx, remote, "", true, 1)
 			require.NoError(t, err)
 			assert.Equal(t, []string{}, objsToNames(objs))
 			assert.Equal(t, []string{}, dirsToNames(dirs))
@@ -467,7 +473,7 @@ func Run(t *testing.T, opt *Opt) {
 		// TestFsListDirNotFound tests listing the directories from an empty directory
 		TestFsListDirNotFound := func(t *testing.T) {
 			skipIfNotOk(t)
-			objs, dirs, err := walk.GetAll(remote, "does not exist", true, 1)
+			objs, dirs, err := walk.GetAll(ctx, remote, "does not exist", true, 1)
 			if !remote.Features().CanHaveEmptyDirectories {
 				if err != fs.ErrorDirNotFound {
 					assert.NoError(t, err)
@@ -489,11 +495,11 @@ func Run(t *testing.T, opt *Opt) {
 		t.Run("FsNewObjectNotFound", func(t *testing.T) {
 			skipIfNotOk(t)
 			// Object in an existing directory
-			o, err := remote.NewObject("potato")
+			o, err := remote.NewObject(ctx, "potato")
 			assert.Nil(t, o)
 			assert.Equal(t, fs.ErrorObjectNotFound, err)
 			// Now try an object in a non existing directory
-			o, err = remote.NewObject("directory/not/found/potato")
+			o, err = remote.NewObject(ctx, "directory/not/found/potato")
 			assert.Nil(t, o)
 			assert.Equal(t, fs.ErrorObjectNotFound, err)
 		})
@@ -515,11 +521,11 @@ func Run(t *testing.T, opt *Opt) {
 			in := io.MultiReader(buf, er)
 
 			obji := object.NewStaticObjectInfo(file2.Path, file2.ModTime, 2*N, true, nil, nil)
-			_, err := remote.Put(in, obji)
+			_, err := remote.Put(ctx, in, obji)
 			// assert.Nil(t, obj) - FIXME some remotes return the object even on nil
 			assert.NotNil(t, err)
 
-			obj, err := remote.NewObject(file2.Path)
+			obj, err := remote.NewObject(ctx, file2.Path)
 			assert.Nil(t, obj)
 			assert.Equal(t, fs.ErrorObjectNotFound, err)
 		})
@@ -640,13 +646,13 @@ func Run(t *testing.T, opt *Opt) {
 				t.Skip("FS has no ChangeNotify interface")
 			}
 
-			err := operations.Mkdir(remote, "dir")
+			err := operations.Mkdir(ctx, remote, "dir")
 			require.NoError(t, err)
 
 			pollInterval := make(chan time.Duration)
 			dirChanges := map[string]struct{}{}
 			objChanges := map[string]struct{}{}
-			doChangeNotify(func(x string, e fs.EntryType) {
+			doChangeNotify(ctx, func(x string, e fs.EntryType) {
 				fs.Debugf(nil, "doChangeNotify(%q, %+v)", x, e)
 				if strings.HasPrefix(x, file1.Path[:5]) || strings.HasPrefix(x, file2.Path[:5]) {
 					fs.Debugf(nil, "Ignoring notify for file1 or file2: %q, %v", x, e)
@@ -664,7 +670,7 @@ func Run(t *testing.T, opt *Opt) {
 			var dirs []string
 			for _, idx := range []int{1, 3, 2} {
 				dir := fmt.Sprintf("dir/subdir%d", idx)
-				err = operations.Mkdir(remote, dir)
+				err = operations.Mkdir(ctx, remote, dir)
 				require.NoError(t, err)
 				dirs = append(dirs, dir)
 			}
@@ -709,11 +715,11 @@ func Run(t *testing.T, opt *Opt) {
 
 			// tidy up afterwards
 			for _, o := range objs {
-				assert.NoError(t, o.Remove())
+				assert.NoError(t, o.Remove(ctx))
 			}
 			dirs = append(dirs, "dir")
 			for _, dir := range dirs {
-				assert.NoError(t, remote.Rmdir(dir))
+				assert.NoError(t, remote.Rmdir(ctx, dir))
 			}
 		})
 
@@ -734,9 +740,9 @@ func Run(t *testing.T, opt *Opt) {
 				list := func(dir string, expectedDirNames, expectedObjNames []string) {
 					var objNames, dirNames []string
 					for i := 1; i <= *fstest.ListRetries; i++ {
-						objs, dirs, err := walk.GetAll(remote, dir, true, 1)
+						objs, dirs, err := walk.GetAll(ctx, remote, dir, true, 1)
 						if errors.Cause(err) == fs.ErrorDirNotFound {
-							objs, dirs, err = walk.GetAll(remote, fstest.WinPath(dir), true, 1)
+							objs, dirs, err = walk.GetAll(ctx, remote, fstest.WinPath(dir), true, 1)
 						}
 						require.NoError(t, err)
 						objNames = objsToNames(objs)
@@ -784,7 +790,7 @@ func Run(t *testing.T, opt *Opt) {
 				skipIfNotOk(t)
 				rootRemote, err := fs.NewFs(remoteName)
 				require.NoError(t, err)
-				_, dirs, err := walk.GetAll(rootRemote, "", true, 1)
+				_, dirs, err := walk.GetAll(ctx, rootRemote, "", true, 1)
 				require.NoError(t, err)
 				assert.Contains(t, dirsToNames(dirs), subRemoteLeaf, "Remote leaf not found")
 			}
@@ -806,7 +812,7 @@ func Run(t *testing.T, opt *Opt) {
 				for i := 0; i < 2; i++ {
 					dir, _ := path.Split(fileName)
 					dir = dir[:len(dir)-1]
-					objs, dirs, err = walk.GetAll(remote, dir, true, -1)
+					objs, dirs, err = walk.GetAll(ctx, remote, dir, true, -1)
 					if err != fs.ErrorDirNotFound {
 						break
 					}
@@ -828,7 +834,7 @@ func Run(t *testing.T, opt *Opt) {
 			// TestFsListLevel2 tests List works for 2 levels
 			TestFsListLevel2 := func(t *testing.T) {
 				skipIfNotOk(t)
-				objs, dirs, err := walk.GetAll(remote, "", true, 2)
+				objs, dirs, err := walk.GetAll(ctx, remote, "", true, 2)
 				if err == fs.ErrorLevelNotSupported {
 					return
 				}
@@ -867,7 +873,7 @@ func Run(t *testing.T, opt *Opt) {
 			t.Run("FsNewObjectDir", func(t *testing.T) {
 				skipIfNotOk(t)
 				dir := path.Dir(file2.Path)
-				obj, err := remote.NewObject(dir)
+				obj, err := remote.NewObject(ctx, dir)
 				assert.Nil(t, obj)
 				assert.NotNil(t, err)
 			})
@@ -888,7 +894,7 @@ func Run(t *testing.T, opt *Opt) {
 
 				// do the copy
 				src := findObject(t, remote, file2.Path)
-				dst, err := doCopy(src, file2Copy.Path)
+				dst, err := doCopy(ctx, src, file2Copy.Path)
 				if err == fs.ErrorCantCopy {
 					t.Skip("FS can't copy")
 				}
@@ -901,7 +907,7 @@ func Run(t *testing.T, opt *Opt) {
 				assert.Equal(t, file2Copy.Path, dst.Remote())
 
 				// Delete copy
-				err = dst.Remove()
+				err = dst.Remove(ctx)
 				require.NoError(t, err)
 
 			})
@@ -928,7 +934,7 @@ func Run(t *testing.T, opt *Opt) {
 				file2Move.Path = "other.txt"
 				file2Move.WinPath = ""
 				src := findObject(t, remote, file2.Path)
-				dst, err := doMove(src, file2Move.Path)
+				dst, err := doMove(ctx, src, file2Move.Path)
 				if err == fs.ErrorCantMove {
 					t.Skip("FS can't move")
 				}
@@ -943,7 +949,7 @@ func Run(t *testing.T, opt *Opt) {
 				// Check conflict on "rename, then move"
 				file1Move.Path = "moveTest/other.txt"
 				src = findObject(t, remote, file1.Path)
-				_, err = doMove(src, file1Move.Path)
+				_, err = doMove(ctx, src, file1Move.Path)
 				require.NoError(t, err)
 				fstest.CheckListing(t, remote, []fstest.Item{file1Move, file2Move})
 				// 1: moveTest/other.txt
@@ -951,21 +957,21 @@ func Run(t *testing.T, opt *Opt) {
 
 				// Check conflict on "move, then rename"
 				src = findObject(t, remote, file1Move.Path)
-				_, err = doMove(src, file1.Path)
+				_, err = doMove(ctx, src, file1.Path)
 				require.NoError(t, err)
 				fstest.CheckListing(t, remote, []fstest.Item{file1, file2Move})
 				// 1: file name.txt
 				// 2: other.txt
 
 				src = findObject(t, remote, file2Move.Path)
-				_, err = doMove(src, file2.Path)
+				_, err = doMove(ctx, src, file2.Path)
 				require.NoError(t, err)
 				fstest.CheckListing(t, remote, []fstest.Item{file1, file2})
 				// 1: file name.txt
 				// 2: hello sausage?/../z.txt
 
 				// Tidy up moveTest directory
-				require.NoError(t, remote.Rmdir("moveTest"))
+				require.NoError(t, remote.Rmdir(ctx, "moveTest"))
 			})
 
 			// Move src to this remote using server side move operations.
@@ -989,7 +995,7 @@ func Run(t *testing.T, opt *Opt) {
 				}
 
 				// Check it can't move onto itself
-				err := doDirMove(remote, "", "")
+				err := doDirMove(ctx, remote, "", "")
 				require.Equal(t, fs.ErrorDirExists, err)
 
 				// new remote
@@ -999,12 +1005,12 @@ func Run(t *testing.T, opt *Opt) {
 
 				const newName = "new_name/sub_new_name"
 				// try the move
-				err = newRemote.Features().DirMove(remote, "", newName)
+				err = newRemote.Features().DirMove(ctx, remote, "", newName)
 				require.NoError(t, err)
 
 				// check remotes
 				// remote should not exist here
-				_, err = remote.List("")
+				_, err = remote.List(ctx, "")
 				assert.Equal(t, fs.ErrorDirNotFound, errors.Cause(err))
 				//fstest.CheckListingWithPrecision(t, remote, []fstest.Item{}, []string{}, remote.Precision())
 				file1Copy := file1
@@ -1022,7 +1028,7 @@ func Run(t *testing.T, opt *Opt) {
 				}, newRemote.Precision())
 
 				// move it back
-				err = doDirMove(newRemote, newName, "")
+				err = doDirMove(ctx, newRemote, newName, "")
 				require.NoError(t, err)
 
 				// check remotes
@@ -1043,7 +1049,7 @@ func Run(t *testing.T, opt *Opt) {
 				if isBucketBasedButNotRoot(remote) {
 					t.Skip("Skipping test as non root bucket based remote")
 				}
-				err := remote.Rmdir("")
+				err := remote.Rmdir(ctx, "")
 				require.Error(t, err, "Expecting error on RMdir on non empty remote")
 			})
 
@@ -1132,7 +1138,7 @@ func Run(t *testing.T, opt *Opt) {
 				skipIfNotOk(t)
 				newModTime := fstest.Time("2011-12-13T14:15:16.999999999Z")
 				obj := findObject(t, remote, file1.Path)
-				err := obj.SetModTime(newModTime)
+				err := obj.SetModTime(ctx, newModTime)
 				if err == fs.ErrorCantSetModTime || err == fs.ErrorCantSetModTimeWithoutDelete {
 					t.Log(err)
 					return
@@ -1206,7 +1212,7 @@ func Run(t *testing.T, opt *Opt) {
 				file1.Size = int64(buf.Len())
 				obj := findObject(t, remote, file1.Path)
 				obji := object.NewStaticObjectInfo(file1.Path, file1.ModTime, int64(len(contents)), true, nil, obj.Fs())
-				err := obj.Update(in, obji)
+				err := obj.Update(ctx, in, obji)
 				require.NoError(t, err)
 				file1.Hashes = hash.Sums()
 
@@ -1263,34 +1269,34 @@ func Run(t *testing.T, opt *Opt) {
 				}
 
 				// if object not found
-				link, err := doPublicLink(file1.Path + "_does_not_exist")
+				link, err := doPublicLink(ctx, file1.Path+"_does_not_exist")
 				require.Error(t, err, "Expected to get error when file doesn't exist")
 				require.Equal(t, "", link, "Expected link to be empty on error")
 
 				// sharing file for the first time
-				link1, err := doPublicLink(file1.Path)
+				link1, err := doPublicLink(ctx, file1.Path)
 				require.NoError(t, err)
 				require.NotEqual(t, "", link1, "Link should not be empty")
 
-				link2, err := doPublicLink(file2.Path)
+				link2, err := doPublicLink(ctx, file2.Path)
 				require.NoError(t, err)
 				require.NotEqual(t, "", link2, "Link should not be empty")
 
 				require.NotEqual(t, link1, link2, "Links to different files should differ")
 
 				// sharing file for the 2nd time
-				link1, err = doPublicLink(file1.Path)
+				link1, err = doPublicLink(ctx, file1.Path)
 				require.NoError(t, err)
 				require.NotEqual(t, "", link1, "Link should not be empty")
 
 				// sharing directory for the first time
 				path := path.Dir(file2.Path)
-				link3, err := doPublicLink(path)
+				link3, err := doPublicLink(ctx, path)
 				require.NoError(t, err)
 				require.NotEqual(t, "", link3, "Link should not be empty")
 
 				// sharing directory for the second time
-				link3, err = doPublicLink(path)
+				link3, err = doPublicLink(ctx, path)
 				require.NoError(t, err)
 				require.NotEqual(t, "", link3, "Link should not be empty")
 
@@ -1301,10 +1307,10 @@ func Run(t *testing.T, opt *Opt) {
 				// ensure sub remote isn't empty
 				buf := bytes.NewBufferString("somecontent")
 				obji := object.NewStaticObjectInfo("somefile", time.Now(), int64(buf.Len()), true, nil, nil)
-				_, err = subRemote.Put(buf, obji)
+				_, err = subRemote.Put(ctx, buf, obji)
 				require.NoError(t, err)
 
-				link4, err := subRemote.Features().PublicLink("")
+				link4, err := subRemote.Features().PublicLink(ctx, "")
 				require.NoError(t, err, "Sharing root in a sub-remote should work")
 				require.NotEqual(t, "", link4, "Link should not be empty")
 			})
@@ -1323,7 +1329,7 @@ func Run(t *testing.T, opt *Opt) {
 				assert.NotEmpty(t, supportedTiers)
 				// test set tier changes on supported storage classes or tiers
 				for _, tier := range supportedTiers {
-					err := setter.SetTier(tier)
+					err := setter.SetTier(ctx, tier)
 					assert.Nil(t, err)
 					got := getter.GetTier()
 					assert.Equal(t, tier, got)
@@ -1334,7 +1340,7 @@ func Run(t *testing.T, opt *Opt) {
 			t.Run("ObjectRemove", func(t *testing.T) {
 				skipIfNotOk(t)
 				obj := findObject(t, remote, file1.Path)
-				err := obj.Remove()
+				err := obj.Remove(ctx)
 				require.NoError(t, err)
 				// check listing without modtime as TestPublicLink may change the modtime
 				fstest.CheckListingWithPrecision(t, remote, []fstest.Item{file2}, nil, fs.ModTimeNotSupported)
@@ -1367,7 +1373,7 @@ func Run(t *testing.T, opt *Opt) {
 
 					file.Size = -1
 					obji := object.NewStaticObjectInfo(file.Path, file.ModTime, file.Size, true, nil, nil)
-					obj, err = remote.Features().PutStream(in, obji)
+					obj, err = remote.Features().PutStream(ctx, in, obji)
 					return err
 				})
 				file.Hashes = uploadHash.Sums()
@@ -1389,7 +1395,7 @@ func Run(t *testing.T, opt *Opt) {
 				}
 
 				// Can't really check the output much!
-				usage, err := doAbout()
+				usage, err := doAbout(ctx)
 				require.NoError(t, err)
 				require.NotNil(t, usage)
 				assert.NotEqual(t, int64(0), usage.Total)
@@ -1408,14 +1414,14 @@ func Run(t *testing.T, opt *Opt) {
 		})
 
 		// Purge the folder
-		err = operations.Purge(remote, "")
+		err = operations.Purge(ctx, remote, "")
 		require.NoError(t, err)
 		purged = true
 		fstest.CheckListing(t, remote, []fstest.Item{})
 
 		// Check purging again if not bucket based
 		if !isBucketBasedButNotRoot(remote) {
-			err = operations.Purge(remote, "")
+			err = operations.Purge(ctx, remote, "")
 			assert.Error(t, err, "Expecting error after on second purge")
 		}
 
@@ -1423,7 +1429,7 @@ func Run(t *testing.T, opt *Opt) {
 
 	// Check directory is purged
 	if !purged {
-		_ = operations.Purge(remote, "")
+		_ = operations.Purge(ctx, remote, "")
 	}
 
 	// Remove the local directory so we don't clutter up /tmp
@@ -26,6 +26,7 @@ package fstest
 
 import (
 	"bytes"
+	"context"
 	"flag"
 	"fmt"
 	"io/ioutil"
@@ -130,6 +131,7 @@ func retry(t *testing.T, what string, f func() error) {
 //
 // Finalise() will tidy them away when done.
 func newRunIndividual(t *testing.T, individual bool) *Run {
+	ctx := context.Background()
 	var r *Run
 	if individual {
 		r = newRun()
@@ -139,7 +141,7 @@ func newRunIndividual(t *testing.T, individual bool) *Run {
 		*r = *oneRun
 		r.cleanRemote = func() {
 			var toDelete []string
-			err := walk.Walk(r.Fremote, "", true, -1, func(dirPath string, entries fs.DirEntries, err error) error {
+			err := walk.Walk(ctx, r.Fremote, "", true, -1, func(dirPath string, entries fs.DirEntries, err error) error {
 				if err != nil {
 					if err == fs.ErrorDirNotFound {
 						return nil
@@ -149,7 +151,9 @@ func newRunIndividual(t *testing.T, individual bool) *Run {
 				for _, entry := range entries {
 					switch x := entry.(type) {
 					case fs.Object:
-						retry(t, fmt.Sprintf("removing file %q", x.Remote()), x.Remove)
+						retry(t, fmt.Sprintf("removing file %q", x.Remote()), func() error {
+							return x.Remove(ctx)
+						})
 					case fs.Directory:
 						toDelete = append(toDelete, x.Remote())
 					}
@@ -164,7 +168,7 @@ func newRunIndividual(t *testing.T, individual bool) *Run {
 			for i := len(toDelete) - 1; i >= 0; i-- {
 				dir := toDelete[i]
 				retry(t, fmt.Sprintf("removing dir %q", dir), func() error {
-					return r.Fremote.Rmdir(dir)
+					return r.Fremote.Rmdir(ctx, dir)
 				})
 			}
 			// Check remote is empty
@@ -229,7 +233,8 @@ func (r *Run) WriteFile(filePath, content string, t time.Time) Item {
 
 // ForceMkdir creates the remote
 func (r *Run) ForceMkdir(f fs.Fs) {
-	err := f.Mkdir("")
+	ctx := context.Background()
+	err := f.Mkdir(ctx, "")
 	if err != nil {
 		r.Fatalf("Failed to mkdir %q: %v", f, err)
 	}
@@ -245,6 +250,7 @@ func (r *Run) Mkdir(f fs.Fs) {
 
 // WriteObjectTo writes an object to the fs, remote passed in
 func (r *Run) WriteObjectTo(f fs.Fs, remote, content string, modTime time.Time, useUnchecked bool) Item {
+	ctx := context.Background()
 	put := f.Put
 	if useUnchecked {
 		put = f.Features().PutUnchecked
@@ -257,7 +263,7 @@ func (r *Run) WriteObjectTo(f fs.Fs, remote, content string, modTime time.Time,
 	for tries := 1; ; tries++ {
 		in := bytes.NewBufferString(content)
 		objinfo := object.NewStaticObjectInfo(remote, modTime, int64(len(content)), true, nil, nil)
-		_, err := put(in, objinfo)
+		_, err := put(ctx, in, objinfo)
 		if err == nil {
 			break
 		}
@@ -290,6 +296,7 @@ func (r *Run) WriteBoth(remote, content string, modTime time.Time) Item {
 
 // CheckWithDuplicates does a test but allows duplicates
 func (r *Run) CheckWithDuplicates(t *testing.T, items ...Item) {
+	ctx := context.Background()
 	var want, got []string
 
 	// construct a []string of desired items
@@ -299,7 +306,7 @@ func (r *Run) CheckWithDuplicates(t *testing.T, items ...Item) {
 	sort.Strings(want)
 
 	// do the listing
-	objs, _, err := walk.GetAll(r.Fremote, "", true, -1)
+	objs, _, err := walk.GetAll(ctx, r.Fremote, "", true, -1)
 	if err != nil && err != fs.ErrorDirNotFound {
 		t.Fatalf("Error listing: %v", err)
 	}
@@ -5,6 +5,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"regexp"
 
@@ -20,11 +21,12 @@ var MatchTestRemote = regexp.MustCompile(`^rclone-test-[abcdefghijklmnopqrstuvwx
 
 // cleanFs runs a single clean fs for left over directories
 func cleanFs(remote string) error {
+	ctx := context.Background()
 	f, err := fs.NewFs(remote)
 	if err != nil {
 		return err
 	}
-	entries, err := list.DirSorted(f, true, "")
+	entries, err := list.DirSorted(ctx, f, true, "")
 	if err != nil {
 		return err
 	}
@@ -41,7 +43,7 @@ func cleanFs(remote string) error {
 			if err != nil {
 				return err
 			}
-			return operations.Purge(dir, "")
+			return operations.Purge(ctx, dir, "")
 		}
 		return nil
 	})
@@ -0,0 +1,166 @@
+// Package base32768 implements the base32768 encoding
+//
+// This encodes 15 bits of binary data into each character using a
+// repertoire of 32768 Unicode characters which are all in the Basic
+// Multilingual Plane and are safe to use in file names - they are
+// not whitespace, punctuation, control characters or combining
+// characters and they survive Unicode normalization.
+//
+// It is compatible with the base32768 encoding described at
+// https://github.com/qntm/base32768
+//
+// On remotes which count the length of a file name in characters
+// rather than bytes this is the densest encoding of binary data.
+package base32768
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+)
+
+// Errors DecodeString can return
+var (
+	ErrorBadCharacter = errors.New("bad base32768 encoding - unrecognised character")
+	ErrorBadPosition  = errors.New("bad base32768 encoding - short character before end of input")
+	ErrorBadPadding   = errors.New("bad base32768 encoding - padding mismatch")
+)
+
+const (
+	bitsPerByte      = 8
+	bitsPerChar      = 15 // bits encoded in a normal character
+	bitsPerFinalChar = 7  // bits encoded in a short final character
+	blockSize        = 32 // size of each range of characters
+)
+
+// The repertoire of characters as pairs of first and last
+// characters of ranges of blockSize characters.
+//
+// repertoire15 has the 32768 characters which encode 15 bits and
+// repertoire7 the 128 characters which encode the last 7 or fewer
+// bits of the input.
+const (
+	repertoire15 = "ҠҿԀԟڀڿݠޟ߀ߟကဟႠႿᄀᅟᆀᆟᇠሿበቿዠዿጠጿᎠᏟᐠᙟᚠᛟកសᠠᡟᣀᣟᦀᦟ᧠᧿ᨠᨿᯀᯟᰀᰟᴀᴟ⇠⇿⋀⋟⍀⏟␀␟─❟➀➿⠀⥿⦠⦿⨠⩟⪀⪿⫠⭟ⰀⰟⲀⳟⴀⴟⵀⵟ⺠⻟㇀㇟㐀䶟䷀龿ꀀꑿ꒠꒿ꔀꗿꙀꙟꚠꛟ꜀ꝟꞀꞟꡀꡟ"
+	repertoire7  = "ƀƟɀʟ"
+)
+
+// Encoding is a base32768 encoding
+//
+// It has the same EncodeToString and DecodeString methods as the
+// encodings in encoding/base32 and encoding/base64.
+type Encoding struct {
+	encode15 []rune         // 15 bit value to character
+	encode7  []rune         // 7 bit value to character
+	decode   map[rune]int32 // character to value, with the bits in the top 16 bits
+}
+
+// SafeEncoding is the standard base32768 encoding
+var SafeEncoding = newEncoding(repertoire15, repertoire7)
+
+// expand turns a repertoire of pairs of characters into the
+// characters in the ranges
+func expand(repertoire string) (out []rune) {
+	pairs := []rune(repertoire)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		for r := pairs[i]; r <= pairs[i+1]; r++ {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// newEncoding makes an Encoding from the repertoires
+func newEncoding(r15, r7 string) *Encoding {
+	enc := &Encoding{
+		encode15: expand(r15),
+		encode7:  expand(r7),
+	}
+	if len(enc.encode15) != 1<<bitsPerChar || len(enc.encode7) != 1<<bitsPerFinalChar {
+		panic("bad base32768 repertoire")
+	}
+	enc.decode = make(map[rune]int32, len(enc.encode15)+len(enc.encode7))
+	for z, r := range enc.encode15 {
+		enc.decode[r] = bitsPerChar<<16 | int32(z)
+	}
+	for z, r := range enc.encode7 {
+		enc.decode[r] = bitsPerFinalChar<<16 | int32(z)
+	}
+	return enc
+}
+
+// EncodedLen returns the length in characters of the encoding of
+// n bytes of input
+func (enc *Encoding) EncodedLen(n int) int {
+	return (n*bitsPerByte + bitsPerChar - 1) / bitsPerChar
+}
+
+// EncodeToString returns the base32768 encoding of src
+//
+// The bits are taken most significant first.  The last character
+// is padded with 1 bits and encodes 7 bits if that is enough,
+// otherwise 15 bits.
+func (enc *Encoding) EncodeToString(src []byte) string {
+	var (
+		out   bytes.Buffer
+		z     int
+		zBits uint
+	)
+	out.Grow(enc.EncodedLen(len(src)) * 3)
+	for _, b := range src {
+		for i := bitsPerByte - 1; i >= 0; i-- {
+			z = z<<1 | int(b>>uint(i))&1
+			zBits++
+			if zBits == bitsPerChar {
+				_, _ = out.WriteRune(enc.encode15[z])
+				z, zBits = 0, 0
+			}
+		}
+	}
+	if zBits != 0 {
+		final := enc.encode15
+		padTo := uint(bitsPerChar)
+		if zBits <= bitsPerFinalChar {
+			final = enc.encode7
+			padTo = bitsPerFinalChar
+		}
+		for ; zBits < padTo; zBits++ {
+			z = z<<1 | 1
+		}
+		_, _ = out.WriteRune(final[z])
+	}
+	return out.String()
+}
+
+// DecodeString returns the bytes represented by the base32768
+// string s
+func (enc *Encoding) DecodeString(s string) ([]byte, error) {
+	chars := []rune(s)
+	out := make([]byte, 0, len(chars)*bitsPerChar/bitsPerByte)
+	var (
+		b     int
+		bBits uint
+	)
+	for i, r := range chars {
+		value, ok := enc.decode[r]
+		if !ok {
+			return nil, ErrorBadCharacter
+		}
+		zBits, z := uint(value>>16), int(value&0xFFFF)
+		if zBits != bitsPerChar && i != len(chars)-1 {
+			return nil, ErrorBadPosition
+		}
+		for j := int(zBits) - 1; j >= 0; j-- {
+			b = b<<1 | (z>>uint(j))&1
+			bBits++
+			if bBits == bitsPerByte {
+				out = append(out, byte(b))
+				b, bBits = 0, 0
+			}
+		}
+	}
+	// Any bits left over must be the padding of 1s
+	if b != 1<<bBits-1 {
+		return nil, ErrorBadPadding
+	}
+	return out, nil
+}
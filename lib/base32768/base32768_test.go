@@ -0,0 +1,59 @@
+package base32768
+
+import (
+	"crypto/rand"
+	"fmt"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	for _, test := range []struct {
+		in       string
+		expected string
+	}{
+		{"", ""},
+		{"\x00", "ڿ"},
+		{"\xFF", "ꡟ"},
+		{"\x00\x00", "Ҡɟ"},
+		{"\xFF\xFF", "ꡟʟ"},
+	} {
+		actual := SafeEncoding.EncodeToString([]byte(test.in))
+		assert.Equal(t, test.expected, actual, fmt.Sprintf("in=%q", test.in))
+		recovered, err := SafeEncoding.DecodeString(test.expected)
+		require.NoError(t, err)
+		assert.Equal(t, test.in, string(recovered), fmt.Sprintf("reverse=%q", test.expected))
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for n := 0; n < 100; n++ {
+		in := make([]byte, n)
+		_, err := rand.Read(in)
+		require.NoError(t, err)
+		encoded := SafeEncoding.EncodeToString(in)
+		assert.Equal(t, SafeEncoding.EncodedLen(n), utf8.RuneCountInString(encoded), fmt.Sprintf("n=%d", n))
+		decoded, err := SafeEncoding.DecodeString(encoded)
+		require.NoError(t, err, fmt.Sprintf("n=%d", n))
+		assert.Equal(t, in, decoded, fmt.Sprintf("n=%d", n))
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, test := range []struct {
+		in          string
+		expectedErr error
+	}{
+		{"a", ErrorBadCharacter},
+		{"ڿa", ErrorBadCharacter},
+		{"ɟҠ", ErrorBadPosition},
+		{"Ҡ", ErrorBadPadding},
+		{"Ҡƀ", ErrorBadPadding},
+	} {
+		_, err := SafeEncoding.DecodeString(test.in)
+		assert.Equal(t, test.expectedErr, err, fmt.Sprintf("in=%q", test.in))
+	}
+}
@@ -0,0 +1,26 @@
+// Package env contains functions for dealing with the user's
+// environment
+package env
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+// ExpandHome expands a leading ~ in path to the current user's home
+// directory
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home := os.Getenv("HOME")
+	if usr, err := user.Current(); err == nil && usr.HomeDir != "" {
+		home = usr.HomeDir
+	}
+	if home == "" {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
@@ -0,0 +1,30 @@
+package env
+
+import (
+	"os/user"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandHome(t *testing.T) {
+	usr, err := user.Current()
+	require.NoError(t, err)
+	home := usr.HomeDir
+	for _, test := range []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"potato", "potato"},
+		{"/etc/ssh/id_rsa", "/etc/ssh/id_rsa"},
+		{"~potato/id_rsa", "~potato/id_rsa"},
+		{"dir/~/id_rsa", "dir/~/id_rsa"},
+		{"~", home},
+		{"~/.ssh/id_rsa", filepath.Join(home, ".ssh", "id_rsa")},
+	} {
+		assert.Equal(t, test.want, ExpandHome(test.in), test.in)
+	}
+}
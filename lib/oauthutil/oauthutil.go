@@ -9,6 +9,7 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -377,12 +378,10 @@ func doConfig(id, name string, m configmap.Mapper, errorHandler func(*http.Reque
 	}
 
 	// Make random state
-	stateBytes := make([]byte, 16)
-	_, err := rand.Read(stateBytes)
+	state, err := randomState()
 	if err != nil {
 		return err
 	}
-	state := fmt.Sprintf("%x", stateBytes)
 	if offline {
 		opts = append(opts, oauth2.AccessTypeOffline)
 	}
@@ -443,6 +442,146 @@ func doConfig(id, name string, m configmap.Mapper, errorHandler func(*http.Reque
 	return PutToken(name, m, token, true)
 }
 
+// randomState makes a random state string for the auth URL
+func randomState() (string, error) {
+	stateBytes := make([]byte, 16)
+	_, err := rand.Read(stateBytes)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", stateBytes), nil
+}
+
+// States used by ConfigState - these all start with "oauth_" so
+// backends which ask further questions can tell them apart
+const (
+	stateRefresh = "oauth_refresh"
+	stateToken   = "oauth_token"
+)
+
+// ConfigState does the initial creation of the token a step at a
+// time without interacting with the user - see fs.RegInfo.ConfigState.
+//
+// It returns a question with the URL to visit to authorize rclone and
+// takes as the answer either the code that gives or the result of
+// "rclone authorize".  It returns nil once the token has been saved.
+func ConfigState(id, name string, m configmap.Mapper, in fs.ConfigIn, config *oauth2.Config, opts ...oauth2.AuthCodeOption) (*fs.ConfigOut, error) {
+	return doConfigState(id, name, m, in, config, true, opts)
+}
+
+// ConfigStateNoOffline does the same as ConfigState but does not pass
+// the "access_type=offline" parameter.
+func ConfigStateNoOffline(id, name string, m configmap.Mapper, in fs.ConfigIn, config *oauth2.Config, opts ...oauth2.AuthCodeOption) (*fs.ConfigOut, error) {
+	return doConfigState(id, name, m, in, config, false, opts)
+}
+
+func doConfigState(id, name string, m configmap.Mapper, in fs.ConfigIn, oauthConfig *oauth2.Config, offline bool, opts []oauth2.AuthCodeOption) (*fs.ConfigOut, error) {
+	oauthConfig, changed := overrideCredentials(name, m, oauthConfig)
+	if offline {
+		opts = append(opts, oauth2.AccessTypeOffline)
+	}
+	switch in.State {
+	case "":
+		// See if already have a token
+		tokenString, ok := m.Get(config.ConfigToken)
+		if ok && tokenString != "" {
+			return refreshQuestion(""), nil
+		}
+	case stateRefresh:
+		refresh, err := strconv.ParseBool(strings.TrimSpace(in.Result))
+		if err != nil {
+			return refreshQuestion(fmt.Sprintf("%q is not true or false", in.Result)), nil
+		}
+		if !refresh {
+			return nil, nil
+		}
+	case stateToken:
+		token, err := resultToToken(oauthConfig, in.Result)
+		if err != nil {
+			out, err2 := tokenQuestion(id, oauthConfig, changed, opts)
+			if err2 != nil {
+				return nil, err2
+			}
+			out.Error = err.Error()
+			return out, nil
+		}
+		return nil, PutToken(name, m, token, true)
+	default:
+		return nil, errors.Errorf("unknown config state %q", in.State)
+	}
+	return tokenQuestion(id, oauthConfig, changed, opts)
+}
+
+// refreshQuestion asks whether to refresh an existing token
+func refreshQuestion(errString string) *fs.ConfigOut {
+	return &fs.ConfigOut{
+		State: stateRefresh,
+		Option: &fs.Option{
+			Name:    "config_refresh_token",
+			Help:    "Already have a token - refresh?",
+			Default: false,
+			Examples: fs.OptionExamples{
+				{Value: "true", Help: "Get a new token"},
+				{Value: "false", Help: "Keep the existing token"},
+			},
+			Required: true,
+		},
+		Error: errString,
+	}
+}
+
+// tokenQuestion asks for the code from the auth URL or the result of
+// rclone authorize
+func tokenQuestion(id string, oauthConfig *oauth2.Config, changed bool, opts []oauth2.AuthCodeOption) (*fs.ConfigOut, error) {
+	state, err := randomState()
+	if err != nil {
+		return nil, err
+	}
+	authURL := oauthConfig.AuthCodeURL(state, opts...)
+	authorize := fmt.Sprintf("rclone authorize %q", id)
+	if changed {
+		authorize = fmt.Sprintf("rclone authorize %q %q %q", id, oauthConfig.ClientID, oauthConfig.ClientSecret)
+	}
+	help := fmt.Sprintf("Visit the following link, log in and authorize rclone for access\n%s\n\nThen paste the verification code here.", authURL)
+	if strings.HasPrefix(oauthConfig.RedirectURL, "http") {
+		help += fmt.Sprintf("  The browser will be redirected to\n%s - paste the value of the code parameter in that URL.", oauthConfig.RedirectURL)
+	}
+	help += fmt.Sprintf("\n\nAlternatively execute the following on a machine with a web browser\n\t%s\nand paste the result here.", authorize)
+	return &fs.ConfigOut{
+		State: stateToken,
+		Option: &fs.Option{
+			Name:     config.ConfigToken,
+			Help:     help,
+			Required: true,
+		},
+		AuthURL: authURL,
+	}, nil
+}
+
+// resultToToken turns the answer to tokenQuestion into a token
+func resultToToken(oauthConfig *oauth2.Config, result string) (*oauth2.Token, error) {
+	result = strings.TrimSpace(result)
+	if result == "" {
+		return nil, errors.New("no code or token supplied")
+	}
+	if strings.HasPrefix(result, "{") {
+		token := &oauth2.Token{}
+		err := json.Unmarshal([]byte(result), token)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse token")
+		}
+		if token.AccessToken == "" {
+			return nil, errors.New("token has no access_token")
+		}
+		return token, nil
+	}
+	token, err := oauthConfig.Exchange(oauth2.NoContext, result)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get token")
+	}
+	return token, nil
+}
+
 // Local web server for collecting auth
 type authServer struct {
 	state        string
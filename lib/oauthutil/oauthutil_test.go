@@ -0,0 +1,111 @@
+package oauthutil
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/config"
+	"github.com/ncw/rclone/fs/config/configmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestConfigState(t *testing.T) {
+	// Use a temporary config file
+	dir, err := ioutil.TempDir("", "rclone-oauthutil")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(dir))
+	}()
+	oldConfigPath := config.ConfigPath
+	config.ConfigPath = filepath.Join(dir, "rclone.conf")
+	defer func() { config.ConfigPath = oldConfigPath }()
+
+	// Token endpoint which only accepts the code "CODE"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "CODE" {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"access_token":"ACCESS","token_type":"bearer","refresh_token":"REFRESH","expires_in":3600}`)
+	}))
+	defer server.Close()
+	oauthConfig := &oauth2.Config{
+		ClientID:     "id",
+		ClientSecret: "secret",
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  "https://example.com/auth",
+			TokenURL: server.URL,
+		},
+		RedirectURL: TitleBarRedirectURL,
+	}
+	const name = "oauthutil_test"
+	m := configmap.Simple{}
+
+	// First we get asked for the code
+	out, err := ConfigState("test", name, m, fs.ConfigIn{}, oauthConfig)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	assert.Equal(t, stateToken, out.State)
+	assert.Equal(t, config.ConfigToken, out.Option.Name)
+	assert.Contains(t, out.AuthURL, "https://example.com/auth?")
+	assert.Contains(t, out.AuthURL, "client_id=id")
+	assert.Contains(t, out.AuthURL, "access_type=offline")
+	assert.Contains(t, out.Option.Help, out.AuthURL)
+	assert.Contains(t, out.Option.Help, `rclone authorize "test"`)
+	assert.Equal(t, "", out.Error)
+
+	// A bad code asks again
+	out, err = ConfigState("test", name, m, fs.ConfigIn{State: out.State, Result: "POTATO"}, oauthConfig)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	assert.Equal(t, stateToken, out.State)
+	assert.Contains(t, out.Error, "failed to get token")
+
+	// A good code saves the token
+	out, err = ConfigState("test", name, m, fs.ConfigIn{State: out.State, Result: "CODE"}, oauthConfig)
+	require.NoError(t, err)
+	assert.Nil(t, out)
+	assert.Contains(t, config.FileGet(name, config.ConfigToken), `"access_token":"ACCESS"`)
+
+	// With a token we get asked whether to refresh it
+	m.Set(config.ConfigToken, config.FileGet(name, config.ConfigToken))
+	out, err = ConfigStateNoOffline("test", name, m, fs.ConfigIn{}, oauthConfig)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	assert.Equal(t, stateRefresh, out.State)
+
+	out, err = ConfigStateNoOffline("test", name, m, fs.ConfigIn{State: out.State, Result: "potato"}, oauthConfig)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	assert.Equal(t, stateRefresh, out.State)
+	assert.Equal(t, `"potato" is not true or false`, out.Error)
+
+	out, err = ConfigStateNoOffline("test", name, m, fs.ConfigIn{State: out.State, Result: "true"}, oauthConfig)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	assert.Equal(t, stateToken, out.State)
+	assert.NotContains(t, out.AuthURL, "access_type=offline")
+
+	// The result of rclone authorize is accepted too
+	out, err = ConfigState("test", name, m, fs.ConfigIn{State: out.State, Result: `{"access_token":"ACCESS2","token_type":"bearer"}`}, oauthConfig)
+	require.NoError(t, err)
+	assert.Nil(t, out)
+	assert.Contains(t, config.FileGet(name, config.ConfigToken), `"access_token":"ACCESS2"`)
+
+	out, err = ConfigState("test", name, m, fs.ConfigIn{State: stateRefresh, Result: "false"}, oauthConfig)
+	require.NoError(t, err)
+	assert.Nil(t, out)
+
+	_, err = ConfigState("test", name, m, fs.ConfigIn{State: "potato"}, oauthConfig)
+	require.Error(t, err)
+	assert.Equal(t, `unknown config state "potato"`, err.Error())
+}
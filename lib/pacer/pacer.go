@@ -4,6 +4,7 @@ package pacer
 import (
 	"math/rand"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ncw/rclone/fs"
@@ -26,6 +27,24 @@ type Pacer struct {
 	consecutiveRetries int           // number of consecutive retries
 }
 
+// Totals for all the pacers in the process, read with atomic
+var (
+	totalSleep   int64 // time spent waiting for the pacer in ns
+	totalRetries int64 // number of low level retries
+)
+
+// TotalSleep returns the total time calls have spent waiting for
+// all the pacers
+func TotalSleep() time.Duration {
+	return time.Duration(atomic.LoadInt64(&totalSleep))
+}
+
+// TotalRetries returns the total number of low level retries made by
+// all the pacers
+func TotalRetries() int64 {
+	return atomic.LoadInt64(&totalRetries)
+}
+
 // Type is for selecting different pacing algorithms
 type Type int
 
@@ -209,7 +228,9 @@ func (p *Pacer) beginCall() {
 	// XXX ms later we put another in.  We could do this with a
 	// Ticker more accurately, but then we'd have to work out how
 	// not to run it when it wasn't needed
+	start := time.Now()
 	<-p.pacer
+	atomic.AddInt64(&totalSleep, int64(time.Since(start)))
 	if p.maxConnections > 0 {
 		<-p.connTokens
 	}
@@ -385,6 +406,7 @@ func (p *Pacer) call(fn Paced, retries int) (err error) {
 		if !retry {
 			break
 		}
+		atomic.AddInt64(&totalRetries, 1)
 		fs.Debugf("pacer", "low level retry %d/%d (error %v)", i, retries, err)
 	}
 	if retry {
@@ -202,6 +202,7 @@ func waitForPace(p *Pacer, duration time.Duration) (when time.Time) {
 func TestBeginCall(t *testing.T) {
 	p := New().SetMaxConnections(10).SetMinSleep(1 * time.Millisecond)
 	emptyTokens(p)
+	oldSleep := TotalSleep()
 	go p.beginCall()
 	if !waitForPace(p, 10*time.Millisecond).IsZero() {
 		t.Errorf("beginSleep fired too early #1")
@@ -220,6 +221,9 @@ func TestBeginCall(t *testing.T) {
 	} else if paceTime.Sub(connTime) < 0 {
 		t.Errorf("pace arrived before sending conn token")
 	}
+	if got := TotalSleep() - oldSleep; got < 10*time.Millisecond {
+		t.Errorf("total sleep want >= %v got %v", 10*time.Millisecond, got)
+	}
 }
 
 func TestBeginCallZeroConnections(t *testing.T) {
@@ -421,10 +425,14 @@ func Test_callRetry(t *testing.T) {
 	p := New().SetMinSleep(time.Millisecond).SetMaxSleep(2 * time.Millisecond)
 
 	dp := &dummyPaced{retry: true}
+	oldRetries := TotalRetries()
 	err := p.call(dp.fn, 10)
 	if dp.called != 10 {
 		t.Errorf("called want %d got %d", 10, dp.called)
 	}
+	if got := TotalRetries() - oldRetries; got != 10 {
+		t.Errorf("retries want %d got %d", 10, got)
+	}
 	if err == errFoo {
 		t.Errorf("err didn't want %v got %v", errFoo, err)
 	}
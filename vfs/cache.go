@@ -64,18 +64,25 @@ func (l *CacheMode) Type() string {
 
 // cache opened files
 type cache struct {
-	f      fs.Fs                 // fs for the cache directory
-	opt    *Options              // vfs Options
-	root   string                // root of the cache directory
-	itemMu sync.Mutex            // protects the next two maps
-	item   map[string]*cacheItem // files/directories in the cache
+	f         fs.Fs                 // fs for the cache directory
+	fremote   fs.Fs                 // fs the cache is for
+	opt       *Options              // vfs Options
+	root      string                // root of the cache directory
+	metaRoot  string                // root of the cache metadata directory
+	itemMu    sync.Mutex            // protects the next two maps
+	item      map[string]*cacheItem // files/directories in the cache
+	writeBack *writeBack            // files waiting to be uploaded
+	ctx       context.Context       // cancelled when the cache is shut down
 }
 
 // cacheItem is stored in the item map
 type cacheItem struct {
-	opens  int       // number of times file is open
-	atime  time.Time // last time file was accessed
-	isFile bool      // if this is a file or a directory
+	opens  int        // number of times file is open
+	atime  time.Time  // last time file was accessed
+	isFile bool       // if this is a file or a directory
+	size   int64      // bytes of data in the cache - set by the cleaner
+	mu     sync.Mutex // protects info and serialises downloads
+	info   *itemInfo  // what is in the cache file - nil if not known
 }
 
 // newCacheItem returns an item for the cache
@@ -97,18 +104,26 @@ func newCache(ctx context.Context, f fs.Fs, opt *Options) (*cache, error) {
 	}
 	root := filepath.Join(config.CacheDir, "vfs", f.Name(), fRoot)
 	fs.Debugf(nil, "vfs cache root is %q", root)
+	metaRoot := filepath.Join(config.CacheDir, "vfsMeta", f.Name(), fRoot)
+	fs.Debugf(nil, "vfs metadata cache root is %q", metaRoot)
 
+	fremote := f
 	f, err := fs.NewFs(root)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create cache remote")
 	}
 
 	c := &cache{
-		f:    f,
-		opt:  opt,
-		root: root,
-		item: make(map[string]*cacheItem),
+		f:        f,
+		fremote:  fremote,
+		opt:      opt,
+		root:     root,
+		metaRoot: metaRoot,
+		item:     make(map[string]*cacheItem),
+		ctx:      ctx,
 	}
+	c.writeBack = newWriteBack(ctx, c)
+	c.resumeUploads()
 
 	go c.cleaner(ctx)
 
@@ -139,6 +154,12 @@ func (c *cache) toOSPath(name string) string {
 	return filepath.Join(c.root, filepath.FromSlash(name))
 }
 
+// toOSPathMeta turns a remote relative name into an OS path for its
+// metadata in the cache
+func (c *cache) toOSPathMeta(name string) string {
+	return filepath.Join(c.metaRoot, filepath.FromSlash(name))
+}
+
 // mkdir makes the directory for name in the cache and returns an os
 // path for the file
 func (c *cache) mkdir(name string) (string, error) {
@@ -274,18 +295,41 @@ func (c *cache) _close(isFile bool, name string) {
 	}
 }
 
-// close marks name as closed
+// close marks name as closed and saves what is known about its data
 //
 // name should be a remote path not an osPath
 func (c *cache) close(name string) {
 	name = clean(name)
 	c.itemMu.Lock()
 	c._close(true, name)
+	item := c.item[name]
 	c.itemMu.Unlock()
+	item.mu.Lock()
+	defer item.mu.Unlock()
+	if item.info != nil {
+		if err := c.saveInfo(name, item.info); err != nil {
+			fs.Errorf(name, "Failed to save cache metadata: %v", err)
+		}
+	}
 }
 
 // remove should be called if name is deleted
 func (c *cache) remove(name string) {
+	name = clean(name)
+	c.writeBack.remove(name)
+	c.itemMu.Lock()
+	item := c.item[name]
+	c.itemMu.Unlock()
+	if item != nil {
+		item.mu.Lock()
+		item.info = nil
+		item.mu.Unlock()
+	}
+	c._remove(name)
+}
+
+// _remove removes the data and metadata for name from the cache
+func (c *cache) _remove(name string) {
 	osPath := c.toOSPath(name)
 	err := os.Remove(osPath)
 	if err != nil && !os.IsNotExist(err) {
@@ -293,6 +337,10 @@ func (c *cache) remove(name string) {
 	} else {
 		fs.Debugf(name, "Removed from cache")
 	}
+	err = os.Remove(c.toOSPathMeta(name))
+	if err != nil && !os.IsNotExist(err) {
+		fs.Errorf(name, "Failed to remove metadata from cache: %v", err)
+	}
 }
 
 // removeDir should be called if dir is deleted and returns true if
@@ -314,7 +362,13 @@ func (c *cache) removeDir(dir string) bool {
 
 // cleanUp empties the cache of everything
 func (c *cache) cleanUp() error {
-	return os.RemoveAll(c.root)
+	c.writeBack.stop()
+	err := os.RemoveAll(c.root)
+	metaErr := os.RemoveAll(c.metaRoot)
+	if err == nil {
+		err = metaErr
+	}
+	return err
 }
 
 // walk walks the cache calling the function
@@ -345,6 +399,7 @@ func (c *cache) updateAtimes() error {
 			// Update the atime with that of the file
 			atime := times.Get(fi).AccessTime()
 			c.updateTime(name, atime)
+			c.updateSize(name, fi.Size())
 		} else {
 			c.cacheDir(name)
 		}
@@ -354,7 +409,7 @@ func (c *cache) updateAtimes() error {
 
 // purgeOld gets rid of any files that are over age
 func (c *cache) purgeOld(maxAge time.Duration) {
-	c._purgeOld(maxAge, c.remove, c.removeDir)
+	c._purgeOld(maxAge, c._remove, c.removeDir)
 }
 
 func (c *cache) _purgeOld(maxAge time.Duration, remove func(name string), removeDir func(name string) bool) {
@@ -391,6 +446,46 @@ func (c *cache) _purgeOld(maxAge time.Duration, remove func(name string), remove
 	}
 }
 
+// purgeOverQuota removes the least recently used files until the
+// cache is no bigger than maxSize
+func (c *cache) purgeOverQuota(maxSize int64) {
+	c._purgeOverQuota(maxSize, c._remove)
+}
+
+func (c *cache) _purgeOverQuota(maxSize int64, remove func(name string)) {
+	c.itemMu.Lock()
+	defer c.itemMu.Unlock()
+	var (
+		total int64
+		names []string
+	)
+	for name, item := range c.item {
+		if !item.isFile {
+			continue
+		}
+		total += item.size
+		if item.opens == 0 {
+			names = append(names, name)
+		}
+	}
+	if total <= maxSize {
+		return
+	}
+	// remove the least recently accessed files first
+	sort.Slice(names, func(i, j int) bool {
+		return c.item[names[i]].atime.Before(c.item[names[j]].atime)
+	})
+	for _, name := range names {
+		if total <= maxSize {
+			break
+		}
+		fs.Debugf(name, "Removing from cache as over quota (%d > %d)", total, maxSize)
+		total -= c.item[name].size
+		remove(name)
+		delete(c.item, name)
+	}
+}
+
 // clean empties the cache of stuff if it can
 func (c *cache) clean() {
 	// Cache may be empty so end
@@ -407,6 +502,11 @@ func (c *cache) clean() {
 		fs.Errorf(nil, "Error traversing cache %q: %v", c.root, err)
 	}
 
+	// Remove the least recently used files if the cache is too big
+	if c.opt.CacheMaxSize >= 0 {
+		c.purgeOverQuota(int64(c.opt.CacheMaxSize))
+	}
+
 	// Now remove any files that are over age and any empty
 	// directories
 	c.purgeOld(c.opt.CacheMaxAge)
@@ -0,0 +1,338 @@
+// This deals with the data stored for each file in the cache
+
+package vfs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fs/accounting"
+	"github.com/pkg/errors"
+)
+
+// cacheBlockSize is the unit data is fetched into the cache in
+const cacheBlockSize = 1024 * 1024
+
+// itemInfo describes the data in a cache file and is persisted in
+// the metadata directory of the cache
+type itemInfo struct {
+	Size        int64  // size of the file
+	Rs          ranges // parts of the file present in the cache file
+	Fingerprint string // the remote object the data came from - "" if modified locally
+	Dirty       bool   // set if the file needs uploading to the remote
+}
+
+// fingerprint returns a string which changes if o changes
+func fingerprint(o fs.Object) string {
+	return fmt.Sprintf("%d,%d", o.Size(), o.ModTime().UnixNano())
+}
+
+// loadInfo reads the metadata for name
+func (c *cache) loadInfo(name string) (*itemInfo, error) {
+	data, err := ioutil.ReadFile(c.toOSPathMeta(name))
+	if err != nil {
+		return nil, err
+	}
+	info := new(itemInfo)
+	err = json.Unmarshal(data, info)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode cache metadata")
+	}
+	return info, nil
+}
+
+// saveInfo writes the metadata for name
+func (c *cache) saveInfo(name string, info *itemInfo) error {
+	osPath := c.toOSPathMeta(name)
+	err := os.MkdirAll(filepath.Dir(osPath), 0700)
+	if err != nil {
+		return errors.Wrap(err, "make cache metadata directory failed")
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode cache metadata")
+	}
+	return ioutil.WriteFile(osPath, data, 0600)
+}
+
+// _loadInfo loads the info for item if it isn't loaded already
+//
+// call with item.mu held
+func (c *cache) _loadInfo(name string, item *cacheItem) {
+	if item.info != nil {
+		return
+	}
+	info, err := c.loadInfo(name)
+	if err == nil {
+		item.info = info
+	} else if !os.IsNotExist(err) {
+		fs.Errorf(name, "Ignoring cache metadata: %v", err)
+	}
+}
+
+// updateSize sets the number of bytes name uses in the cache.
+// osSize is the size of the cache file.
+//
+// name should be a remote path not an osPath
+func (c *cache) updateSize(name string, osSize int64) {
+	name = clean(name)
+	item := c.get(name)
+	item.mu.Lock()
+	c._loadInfo(name, item)
+	size := osSize
+	if item.info != nil {
+		size = item.info.Rs.size()
+	}
+	item.mu.Unlock()
+	c.itemMu.Lock()
+	item.size = size
+	c.itemMu.Unlock()
+}
+
+// prepare makes the cache file for name ready to be opened.
+//
+// If o has changed since the data in the cache file was fetched then
+// the cache file is emptied and made the size of o, ready to be
+// filled in by fetch.  o may be nil if the file doesn't exist on the
+// remote.
+//
+// name should be a remote path not an osPath
+func (c *cache) prepare(name string, o fs.Object) error {
+	name = clean(name)
+	item := c.get(name)
+	item.mu.Lock()
+	defer item.mu.Unlock()
+	c._loadInfo(name, item)
+	osPath := c.toOSPath(name)
+	fi, err := os.Stat(osPath)
+	if err == nil && item.info != nil && item.info.Dirty {
+		fs.Debugf(name, "vfs cache: using cache file waiting to be uploaded")
+		return nil
+	}
+	if o == nil {
+		// The cache file is all there is of the file
+		if err == nil {
+			item.info = &itemInfo{Size: fi.Size()}
+			item.info.Rs = item.info.Rs.insert(byteRange{Pos: 0, Size: fi.Size()})
+		} else {
+			item.info = nil
+		}
+		return nil
+	}
+	fp := fingerprint(o)
+	if err == nil && item.info != nil && item.info.Fingerprint == fp && item.info.Size == fi.Size() {
+		fs.Debugf(name, "vfs cache: %d of %d bytes already cached", item.info.Rs.size(), item.info.Size)
+		return nil
+	}
+	fs.Debugf(name, "vfs cache: starting new sparse cache file")
+	fd, err := os.OpenFile(osPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to create cache file")
+	}
+	item.info = &itemInfo{Fingerprint: fp}
+	if o.Size() >= 0 {
+		item.info.Size = o.Size()
+		err = fd.Truncate(o.Size())
+	} else {
+		// Files of unknown size are fetched in their entirety
+		var n int64
+		n, err = c.download(c.ctx, fd, o, byteRange{Pos: 0, Size: -1})
+		item.info.Size = n
+		item.info.Rs = item.info.Rs.insert(byteRange{Pos: 0, Size: n})
+	}
+	closeErr := fd.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		item.info = nil
+		return errors.Wrap(err, "failed to prepare cache file")
+	}
+	return nil
+}
+
+// fetch makes sure the part r of name is in the cache file, reading
+// it from o if necessary.
+//
+// The data fetched is rounded up to whole blocks and extended by the
+// read ahead.
+//
+// name should be a remote path not an osPath
+func (c *cache) fetch(name string, o fs.Object, r byteRange) error {
+	name = clean(name)
+	item := c.get(name)
+	item.mu.Lock()
+	defer item.mu.Unlock()
+	info := item.info
+	if info == nil || o == nil {
+		return nil
+	}
+	r = r.clip(info.Size)
+	if info.Rs.present(r) {
+		return nil
+	}
+	start := r.Pos / cacheBlockSize * cacheBlockSize
+	end := r.end() + int64(c.opt.ReadAhead)
+	end = (end + cacheBlockSize - 1) / cacheBlockSize * cacheBlockSize
+	window := byteRange{Pos: start, Size: end - start}.clip(info.Size)
+	fd, err := os.OpenFile(c.toOSPath(name), os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to open cache file for fetch")
+	}
+	for _, missing := range info.Rs.findMissing(window) {
+		var n int64
+		n, err = c.download(c.ctx, fd, o, missing)
+		info.Rs = info.Rs.insert(byteRange{Pos: missing.Pos, Size: n})
+		if err != nil {
+			break
+		}
+	}
+	closeErr := fd.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch data into cache")
+	}
+	return nil
+}
+
+// download reads r from o writing it to the same place in fd,
+// returning the number of bytes written.  If r.Size is negative all
+// of o is read.
+func (c *cache) download(ctx context.Context, fd *os.File, o fs.Object, r byteRange) (n int64, err error) {
+	var options []fs.OpenOption
+	if r.Size >= 0 {
+		options = append(options, &fs.RangeOption{Start: r.Pos, End: r.end() - 1})
+		fs.Debugf(o, "vfs cache: fetching %d bytes at offset %d", r.Size, r.Pos)
+	}
+	in, err := o.Open(ctx, options...)
+	if err != nil {
+		return 0, err
+	}
+	accounting.Stats.Transferring(o.Remote())
+	acc := accounting.NewAccount(in, o)
+	defer func() {
+		closeErr := acc.Close()
+		if err == nil {
+			err = closeErr
+		}
+		accounting.Stats.DoneTransferring(o.Remote(), err == nil)
+	}()
+	var src io.Reader = acc
+	if r.Size >= 0 {
+		src = io.LimitReader(acc, r.Size)
+	}
+	buf := make([]byte, 64*1024)
+	for {
+		nr, readErr := src.Read(buf)
+		if nr > 0 {
+			nw, writeErr := fd.WriteAt(buf[:nr], r.Pos+n)
+			n += int64(nw)
+			if writeErr != nil {
+				return n, writeErr
+			}
+		}
+		if readErr == io.EOF {
+			break
+		} else if readErr != nil {
+			return n, readErr
+		}
+	}
+	if r.Size >= 0 && n != r.Size {
+		return n, io.ErrUnexpectedEOF
+	}
+	return n, nil
+}
+
+// written marks the part r of name as written locally.  size is the
+// new size of the cache file.
+//
+// name should be a remote path not an osPath
+func (c *cache) written(name string, r byteRange, size int64) {
+	name = clean(name)
+	item := c.get(name)
+	item.mu.Lock()
+	defer item.mu.Unlock()
+	if item.info == nil {
+		item.info = &itemInfo{}
+	}
+	info := item.info
+	if r.Pos > info.Size {
+		// Writing beyond the end leaves a gap of zeros
+		info.Rs = info.Rs.insert(byteRange{Pos: info.Size, Size: r.Pos - info.Size})
+	}
+	info.Size = size
+	info.Rs = info.Rs.insert(r)
+	info.Fingerprint = ""
+}
+
+// truncated records name has been truncated to size
+//
+// name should be a remote path not an osPath
+func (c *cache) truncated(name string, size int64) {
+	name = clean(name)
+	item := c.get(name)
+	item.mu.Lock()
+	defer item.mu.Unlock()
+	if item.info == nil {
+		item.info = &itemInfo{}
+	}
+	info := item.info
+	info.Rs = info.Rs.truncate(size)
+	if size > info.Size {
+		// The extension is zeros which are all present
+		info.Rs = info.Rs.insert(byteRange{Pos: info.Size, Size: size - info.Size})
+	}
+	info.Size = size
+	info.Fingerprint = ""
+}
+
+// dirty records that the cache file for name needs uploading and
+// saves that so the upload can be resumed if rclone is restarted
+//
+// name should be a remote path not an osPath
+func (c *cache) dirty(name string) {
+	name = clean(name)
+	item := c.get(name)
+	item.mu.Lock()
+	defer item.mu.Unlock()
+	if item.info == nil {
+		item.info = &itemInfo{}
+		fi, err := os.Stat(c.toOSPath(name))
+		if err == nil {
+			item.info.Size = fi.Size()
+			item.info.Rs = item.info.Rs.insert(byteRange{Pos: 0, Size: fi.Size()})
+		}
+	}
+	item.info.Dirty = true
+	if err := c.saveInfo(name, item.info); err != nil {
+		fs.Errorf(name, "Failed to save cache metadata: %v", err)
+	}
+}
+
+// setObject records that the cache file for name now has the same
+// contents as o
+//
+// name should be a remote path not an osPath
+func (c *cache) setObject(name string, o fs.Object) {
+	name = clean(name)
+	item := c.get(name)
+	item.mu.Lock()
+	defer item.mu.Unlock()
+	if item.info == nil {
+		return
+	}
+	item.info.Size = o.Size()
+	item.info.Fingerprint = fingerprint(o)
+	item.info.Dirty = false
+	if err := c.saveInfo(name, item.info); err != nil {
+		fs.Errorf(name, "Failed to save cache metadata: %v", err)
+	}
+}
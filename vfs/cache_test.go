@@ -460,3 +460,69 @@ func TestCachePurgeOld(t *testing.T) {
 
 	assert.Equal(t, []string(nil), itemAsString(c))
 }
+
+func TestCachePurgeOverQuota(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, err := newCache(ctx, r.Fremote, &DefaultOpt)
+	require.NoError(t, err)
+
+	// Test funcs
+	var removed []string
+	remove := func(name string) {
+		removed = append(removed, name)
+	}
+
+	removed = nil
+	c._purgeOverQuota(-1, remove)
+	assert.Equal(t, []string(nil), removed)
+
+	c.open("sub/dir/potato")
+	c.open("sub/dir2/potato2")
+	c.open("sub/dir2/potato3")
+	c.close("sub/dir2/potato2")
+	c.close("sub/dir2/potato3")
+
+	now := time.Now()
+	setItem := func(name string, size int64, age time.Duration) {
+		item := c.get(name)
+		c.itemMu.Lock()
+		item.size = size
+		item.atime = now.Add(-age)
+		c.itemMu.Unlock()
+	}
+	setItem("sub/dir/potato", 100, 3*time.Hour)
+	setItem("sub/dir2/potato2", 100, 2*time.Hour)
+	setItem("sub/dir2/potato3", 100, time.Hour)
+
+	// Under quota - nothing removed
+	removed = nil
+	c._purgeOverQuota(300, remove)
+	assert.Equal(t, []string(nil), removed)
+
+	// Over quota - oldest unopened file removed first
+	removed = nil
+	c._purgeOverQuota(250, remove)
+	assert.Equal(t, []string{
+		"sub/dir2/potato2",
+	}, removed)
+
+	// Open files are never removed
+	removed = nil
+	c._purgeOverQuota(0, remove)
+	assert.Equal(t, []string{
+		"sub/dir2/potato3",
+	}, removed)
+
+	assert.Equal(t, []string{
+		`name="" isFile=false opens=1`,
+		`name="sub" isFile=false opens=1`,
+		`name="sub/dir" isFile=false opens=1`,
+		`name="sub/dir/potato" isFile=true opens=1`,
+		`name="sub/dir2" isFile=false opens=0`,
+	}, itemAsString(c))
+}
@@ -206,7 +206,7 @@ func (d *Dir) _readDir() error {
 	} else {
 		return nil
 	}
-	entries, err := list.DirSorted(d.f, false, d.path)
+	entries, err := list.DirSorted(d.vfs.ctx, d.f, false, d.path)
 	if err == fs.ErrorDirNotFound {
 		// We treat directory not found as empty because we
 		// create directories on the fly
@@ -293,7 +293,7 @@ func (d *Dir) readDirTree() error {
 	when := time.Now()
 	d.read = time.Time{}
 	fs.Debugf(d.path, "Reading directory tree")
-	dt, err := walk.NewDirTree(d.f, d.path, false, -1)
+	dt, err := walk.NewDirTree(d.vfs.ctx, d.f, d.path, false, -1)
 	if err != nil {
 		return err
 	}
@@ -458,7 +458,7 @@ func (d *Dir) Mkdir(name string) (*Dir, error) {
 	}
 	path := path.Join(d.path, name)
 	// fs.Debugf(path, "Dir.Mkdir")
-	err := d.f.Mkdir(path)
+	err := d.f.Mkdir(d.vfs.ctx, path)
 	if err != nil {
 		fs.Errorf(d, "Dir.Mkdir failed to create directory: %v", err)
 		return nil, err
@@ -486,7 +486,7 @@ func (d *Dir) Remove() error {
 		return ENOTEMPTY
 	}
 	// remove directory
-	err = d.f.Rmdir(d.path)
+	err = d.f.Rmdir(d.vfs.ctx, d.path)
 	if err != nil {
 		fs.Errorf(d, "Dir.Remove failed to remove directory: %v", err)
 		return err
@@ -584,7 +584,7 @@ func (d *Dir) Rename(oldName, newName string, destDir *Dir) error {
 		}
 		srcRemote := x.Remote()
 		dstRemote := newPath
-		err = doDirMove(d.f, srcRemote, dstRemote)
+		err = doDirMove(d.vfs.ctx, d.f, srcRemote, dstRemote)
 		if err != nil {
 			fs.Errorf(oldPath, "Dir.Rename error: %v", err)
 			return err
@@ -28,6 +28,7 @@ type File struct {
 	readWriters       int          // how many RWFileHandle are open for writing
 	readWriterClosing bool         // is a RWFileHandle currently cosing?
 	modified          bool         // has the cache file be modified by a RWFileHandle?
+	writeBackPending  bool         // is the cache file waiting to be uploaded?
 	pendingModTime    time.Time    // will be applied once o becomes available, i.e. after file was written
 	pendingRenameFun  func() error // will be run/renamed after all writers close
 
@@ -117,8 +118,8 @@ func (f *File) rename(destDir *Dir, newName string) error {
 
 	renameCall := func() error {
 		newPath := path.Join(destDir.path, newName)
-		dstOverwritten, _ := f.d.f.NewObject(newPath)
-		newObject, err := operations.Move(f.d.f, dstOverwritten, newPath, f.o)
+		dstOverwritten, _ := f.d.f.NewObject(f.d.vfs.ctx, newPath)
+		newObject, err := operations.Move(f.d.vfs.ctx, f.d.f, dstOverwritten, newPath, f.o)
 		if err != nil {
 			fs.Errorf(f.Path(), "File.Rename error: %v", err)
 			return err
@@ -241,10 +242,16 @@ func (f *File) ModTime() (modTime time.Time) {
 
 	if !f.d.vfs.Opt.NoModTime {
 		// if o is nil it isn't valid yet or there are writers, so return the size so far
-		if f.o == nil || len(f.writers) != 0 || f.readWriterClosing {
+		if f.o == nil || len(f.writers) != 0 || f.readWriterClosing || f.writeBackPending {
 			if !f.pendingModTime.IsZero() {
 				return f.pendingModTime
 			}
+			// if waiting to be uploaded use the time the cache file was written
+			if f.writeBackPending {
+				if fi, err := os.Stat(f.d.vfs.cache.toOSPath(f.Path())); err == nil {
+					return fi.ModTime()
+				}
+			}
 		} else {
 			return f.o.ModTime()
 		}
@@ -304,7 +311,7 @@ func (f *File) applyPendingModTime() error {
 		return errors.New("Cannot apply ModTime, file object is not available")
 	}
 
-	err := f.o.SetModTime(f.pendingModTime)
+	err := f.o.SetModTime(f.d.vfs.ctx, f.pendingModTime)
 	switch err {
 	case nil:
 		fs.Debugf(f.o, "File.applyPendingModTime OK")
@@ -318,9 +325,18 @@ func (f *File) applyPendingModTime() error {
 	return nil
 }
 
-// writingInProgress returns true of there are any open writers
+// writingInProgress returns true of there are any open writers or
+// the file is waiting to be uploaded
 func (f *File) writingInProgress() bool {
-	return f.o == nil || len(f.writers) != 0 || f.readWriterClosing
+	return f.o == nil || len(f.writers) != 0 || f.readWriterClosing || f.writeBackPending
+}
+
+// setWriteBackPending sets whether the cache file is waiting to be
+// uploaded
+func (f *File) setWriteBackPending(pending bool) {
+	f.mu.Lock()
+	f.writeBackPending = pending
+	f.mu.Unlock()
 }
 
 // Update the size while writing
@@ -448,12 +464,13 @@ func (f *File) Remove() error {
 		return EROFS
 	}
 	if f.o != nil {
-		err := f.o.Remove()
+		err := f.o.Remove(f.d.vfs.ctx)
 		if err != nil {
 			fs.Errorf(f, "File.Remove file error: %v", err)
 			return err
 		}
 	}
+	f.writeBackPending = false
 	// Remove the item from the directory listing
 	f.d.delObject(f.Name())
 	// Remove the object from the cache
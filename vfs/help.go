@@ -58,8 +58,11 @@ may find that you need one or the other or both.
 
     --cache-dir string                   Directory rclone will use for caching.
     --vfs-cache-max-age duration         Max age of objects in the cache. (default 1h0m0s)
+    --vfs-cache-max-size int             Max total size of objects in the cache. (default off)
     --vfs-cache-mode string              Cache mode off|minimal|writes|full (default "off")
     --vfs-cache-poll-interval duration   Interval to poll the cache for stale objects. (default 1m0s)
+    --vfs-read-ahead int                 Extra data to fetch into the cache after each read. (default 4M)
+    --vfs-write-back duration            Time to wait after a file is closed before uploading it. 0 uploads on close.
 
 If run with ` + "`-vv`" + ` rclone will print the location of the file cache.  The
 files are stored in the user cache file area which is OS dependent but
@@ -75,6 +78,26 @@ closed so if rclone is quit or dies with open files then these won't
 get written back to the remote.  However they will still be in the on
 disk cache.
 
+#### Write back
+
+By default closing a file which has been written waits until it has
+been uploaded.  If ` + "`--vfs-write-back`" + ` is set, for example to
+` + "`5s`" + `, then closing the file returns immediately and the file is
+uploaded in the background after that delay.  If the file is opened
+for write again before then the delay starts again, so files which are
+written repeatedly are only uploaded once.  Until they are uploaded
+the files are read from the cache.
+
+If an upload fails it is retried with increasing delays up to
+` + "`--low-level-retries`" + ` times.
+
+The files waiting to be uploaded are recorded in the cache, so if
+rclone is quit or dies before uploading them they are uploaded when
+rclone is next started with the same remote and cache directory.
+
+The queue can be inspected with ` + "`rclone rc vfs/queue`" + ` and the files in
+it uploaded immediately with ` + "`rclone rc vfs/flush`" + `.
+
 #### --vfs-cache-mode off
 
 In this mode the cache will read directly from the remote and write
@@ -116,16 +139,28 @@ If an upload fails it will be retried up to --low-level-retries times.
 #### --vfs-cache-mode full
 
 In this mode all reads and writes are buffered to and from disk.  When
-a file is opened for read it will be downloaded in its entirety first.
-
-This may be appropriate for your needs, or you may prefer to look at
-the cache backend which does a much more sophisticated job of caching,
-including caching directory hierarchies and chunks of files.
+a file is read only the parts read are downloaded into a sparse file in
+the cache, in blocks of 1M plus ` + "`--vfs-read-ahead`" + ` extra bytes
+after each read.  Reading parts of large files, such as seeking in a
+video, doesn't need the whole file to be downloaded first.
+
+A record of which parts of each file are in the cache is kept
+alongside it, so data fetched once is read from disk when the file is
+opened again, even after rclone restarts.  If the file changes on the
+remote the cached data is discarded.  When a file which was only
+partly cached is written to, the rest of it is downloaded before it is
+uploaded.
 
 In this mode, unlike the others, when a file is written to the disk,
 it will be kept on the disk after it is written to the remote.  It
 will be purged on a schedule according to ` + "`--vfs-cache-max-age`" + `.
 
+If ` + "`--vfs-cache-max-size`" + ` is set then when the cache grows
+larger than that the least recently used files are removed from it
+until it is small enough again.  This is checked every
+` + "`--vfs-cache-poll-interval`" + `, and files which are open are
+never removed, so the cache may exceed the limit for a while.
+
 This mode should support all normal file system operations.
 
 If an upload or download fails it will be retried up to
@@ -0,0 +1,105 @@
+// This keeps track of which parts of a file are in the cache
+
+package vfs
+
+// byteRange is a range of bytes in a file
+type byteRange struct {
+	Pos  int64 // start of the range
+	Size int64 // length of the range
+}
+
+// end returns the offset just after the range
+func (r byteRange) end() int64 {
+	return r.Pos + r.Size
+}
+
+// clip returns r clipped to the range [0, size)
+func (r byteRange) clip(size int64) byteRange {
+	if r.Pos < 0 {
+		r.Size += r.Pos
+		r.Pos = 0
+	}
+	if r.end() > size {
+		r.Size = size - r.Pos
+	}
+	if r.Size < 0 {
+		r.Size = 0
+	}
+	return r
+}
+
+// ranges is a sorted list of non overlapping, non adjacent byteRanges
+type ranges []byteRange
+
+// insert adds r to the ranges merging it with any it overlaps or
+// touches
+func (rs ranges) insert(r byteRange) ranges {
+	if r.Size <= 0 {
+		return rs
+	}
+	var out ranges
+	i := 0
+	// copy the ranges entirely before r
+	for ; i < len(rs) && rs[i].end() < r.Pos; i++ {
+		out = append(out, rs[i])
+	}
+	// merge the ranges overlapping or touching r
+	for ; i < len(rs) && rs[i].Pos <= r.end(); i++ {
+		if rs[i].Pos < r.Pos {
+			r.Size += r.Pos - rs[i].Pos
+			r.Pos = rs[i].Pos
+		}
+		if rs[i].end() > r.end() {
+			r.Size = rs[i].end() - r.Pos
+		}
+	}
+	out = append(out, r)
+	// copy the ranges entirely after r
+	return append(out, rs[i:]...)
+}
+
+// truncate removes any parts of the ranges at or beyond size
+func (rs ranges) truncate(size int64) ranges {
+	var out ranges
+	for _, r := range rs {
+		r = r.clip(size)
+		if r.Size > 0 {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// findMissing returns the parts of r which aren't in the ranges
+func (rs ranges) findMissing(r byteRange) (missing ranges) {
+	pos := r.Pos
+	for _, present := range rs {
+		if present.end() <= pos {
+			continue
+		}
+		if present.Pos >= r.end() {
+			break
+		}
+		if present.Pos > pos {
+			missing = append(missing, byteRange{Pos: pos, Size: present.Pos - pos})
+		}
+		pos = present.end()
+	}
+	if pos < r.end() {
+		missing = append(missing, byteRange{Pos: pos, Size: r.end() - pos})
+	}
+	return missing
+}
+
+// present returns whether all of r is in the ranges
+func (rs ranges) present(r byteRange) bool {
+	return r.Size <= 0 || len(rs.findMissing(r)) == 0
+}
+
+// size returns the total number of bytes in the ranges
+func (rs ranges) size() (total int64) {
+	for _, r := range rs {
+		total += r.Size
+	}
+	return total
+}
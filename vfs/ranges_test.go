@@ -0,0 +1,65 @@
+package vfs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRangesInsert(t *testing.T) {
+	for _, test := range []struct {
+		rs   ranges
+		r    byteRange
+		want ranges
+	}{
+		{nil, byteRange{0, 0}, nil},
+		{nil, byteRange{1, 2}, ranges{{1, 2}}},
+		{ranges{{10, 5}}, byteRange{1, 2}, ranges{{1, 2}, {10, 5}}},
+		{ranges{{10, 5}}, byteRange{20, 2}, ranges{{10, 5}, {20, 2}}},
+		{ranges{{10, 5}}, byteRange{5, 5}, ranges{{5, 10}}},
+		{ranges{{10, 5}}, byteRange{15, 5}, ranges{{10, 10}}},
+		{ranges{{10, 5}}, byteRange{12, 1}, ranges{{10, 5}}},
+		{ranges{{10, 5}}, byteRange{8, 10}, ranges{{8, 10}}},
+		{ranges{{0, 1}, {2, 1}, {4, 1}, {10, 1}}, byteRange{1, 3}, ranges{{0, 5}, {10, 1}}},
+		{ranges{{0, 1}, {2, 1}, {4, 1}, {10, 1}}, byteRange{3, 10}, ranges{{0, 1}, {2, 11}}},
+	} {
+		got := test.rs.insert(test.r)
+		assert.Equal(t, test.want, got, "insert %v into %v", test.r, test.rs)
+	}
+}
+
+func TestRangesTruncate(t *testing.T) {
+	rs := ranges{{0, 5}, {10, 5}, {20, 5}}
+	assert.Equal(t, ranges{{0, 5}, {10, 5}, {20, 5}}, rs.truncate(100))
+	assert.Equal(t, ranges{{0, 5}, {10, 2}}, rs.truncate(12))
+	assert.Equal(t, ranges{{0, 5}}, rs.truncate(10))
+	assert.Equal(t, ranges(nil), rs.truncate(0))
+}
+
+func TestRangesFindMissing(t *testing.T) {
+	rs := ranges{{10, 5}, {20, 5}}
+	assert.Equal(t, ranges{{0, 10}}, rs.findMissing(byteRange{0, 10}))
+	assert.Equal(t, ranges{{0, 10}, {15, 5}, {25, 5}}, rs.findMissing(byteRange{0, 30}))
+	assert.Equal(t, ranges{{15, 2}}, rs.findMissing(byteRange{12, 5}))
+	assert.Equal(t, ranges(nil), rs.findMissing(byteRange{11, 3}))
+	assert.Equal(t, ranges(nil), rs.findMissing(byteRange{20, 5}))
+	assert.Equal(t, ranges{{30, 10}}, rs.findMissing(byteRange{30, 10}))
+	assert.Equal(t, ranges{{0, 5}}, ranges(nil).findMissing(byteRange{0, 5}))
+}
+
+func TestRangesPresent(t *testing.T) {
+	rs := ranges{{10, 5}, {20, 5}}
+	assert.True(t, rs.present(byteRange{10, 5}))
+	assert.True(t, rs.present(byteRange{21, 2}))
+	assert.True(t, rs.present(byteRange{100, 0}))
+	assert.False(t, rs.present(byteRange{10, 6}))
+	assert.False(t, rs.present(byteRange{0, 1}))
+	assert.Equal(t, int64(10), rs.size())
+}
+
+func TestByteRangeClip(t *testing.T) {
+	assert.Equal(t, byteRange{0, 5}, byteRange{-5, 10}.clip(100))
+	assert.Equal(t, byteRange{95, 5}, byteRange{95, 10}.clip(100))
+	assert.Equal(t, byteRange{110, 0}, byteRange{110, 10}.clip(100))
+	assert.Equal(t, byteRange{5, 5}, byteRange{5, 5}.clip(100))
+}
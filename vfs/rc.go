@@ -1,6 +1,7 @@
 package vfs
 
 import (
+	"context"
 	"strconv"
 	"strings"
 	"time"
@@ -14,7 +15,7 @@ import (
 func (vfs *VFS) addRC() {
 	rc.Add(rc.Call{
 		Path: "vfs/forget",
-		Fn: func(in rc.Params) (out rc.Params, err error) {
+		Fn: func(ctx context.Context, in rc.Params) (out rc.Params, err error) {
 			root, err := vfs.Root()
 			if err != nil {
 				return nil, err
@@ -65,7 +66,7 @@ starting with dir will forget that dir, eg
 	})
 	rc.Add(rc.Call{
 		Path: "vfs/refresh",
-		Fn: func(in rc.Params) (out rc.Params, err error) {
+		Fn: func(ctx context.Context, in rc.Params) (out rc.Params, err error) {
 			root, err := vfs.Root()
 			if err != nil {
 				return nil, err
@@ -167,6 +168,64 @@ starting with dir will refresh that directory, eg
 If the parameter recursive=true is given the whole directory tree
 will get refreshed. This refresh will use --fast-list if enabled.
 
+`,
+	})
+	rc.Add(rc.Call{
+		Path: "vfs/queue",
+		Fn: func(ctx context.Context, in rc.Params) (out rc.Params, err error) {
+			if vfs.cache == nil {
+				return nil, errors.New("vfs cache is not in use")
+			}
+			out = rc.Params{
+				"queue": vfs.cache.writeBack.queue(),
+			}
+			return out, nil
+		},
+		Title: "List the files waiting to be uploaded from the cache.",
+		Help: `
+This returns the files in the VFS cache which are waiting to be
+uploaded to the remote, eg
+
+    rclone rc vfs/queue
+
+For each file it returns
+
+- name - path of the file
+- size - size of the file in the cache
+- expiry - seconds until the upload is due - 0 if not scheduled
+- tries - number of failed uploads
+- uploading - true if the file is being uploaded now
+- error - the error from the last failed upload if any
+
+Files which have failed to upload --low-level-retries times stay in
+the queue without being scheduled until they are flushed with
+vfs/flush.
+`,
+	})
+	rc.Add(rc.Call{
+		Path: "vfs/flush",
+		Fn: func(ctx context.Context, in rc.Params) (out rc.Params, err error) {
+			if vfs.cache == nil {
+				return nil, errors.New("vfs cache is not in use")
+			}
+			name, err := in.GetString("file")
+			if rc.NotErrParamNotFound(err) {
+				return nil, err
+			}
+			return nil, vfs.cache.writeBack.flush(strings.Trim(name, "/"))
+		},
+		Title: "Upload files waiting in the cache now.",
+		Help: `
+This uploads the files in the VFS cache which are waiting to be
+uploaded to the remote without waiting for their delay to expire,
+returning an error if any of the uploads fail.
+
+    rclone rc vfs/flush
+
+Pass file=path to upload just that file, eg
+
+    rclone rc vfs/flush file=home/junk/file.txt
+
 `,
 	})
 	rc.Add(rc.Call{
@@ -239,7 +298,7 @@ func rcPollFunc(vfs *VFS) (rcPollFunc rc.Func) {
 		return timeout, nil
 	}
 
-	_status := func(in rc.Params) (out rc.Params, err error) {
+	_status := func(ctx context.Context, in rc.Params) (out rc.Params, err error) {
 		for k, v := range in {
 			return nil, errors.Errorf("invalid parameter: %s=%s", k, v)
 		}
@@ -253,7 +312,7 @@ func rcPollFunc(vfs *VFS) (rcPollFunc rc.Func) {
 			},
 		}, nil
 	}
-	return func(in rc.Params) (out rc.Params, err error) {
+	return func(ctx context.Context, in rc.Params) (out rc.Params, err error) {
 		interval, intervalPresent, err := getInterval(in)
 		if err != nil {
 			return nil, err
@@ -270,7 +329,7 @@ func rcPollFunc(vfs *VFS) (rcPollFunc rc.Func) {
 		}
 
 		if !intervalPresent {
-			return _status(in)
+			return _status(ctx, in)
 		}
 		var timeoutHit bool
 		var timeoutChan <-chan time.Time
@@ -285,7 +344,7 @@ func rcPollFunc(vfs *VFS) (rcPollFunc rc.Func) {
 		case <-timeoutChan:
 			timeoutHit = true
 		}
-		out, err = _status(in)
+		out, err = _status(ctx, in)
 		if out != nil {
 			out["timeout"] = timeoutHit
 		}
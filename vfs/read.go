@@ -1,6 +1,7 @@
 package vfs
 
 import (
+	"context"
 	"io"
 	"os"
 	"sync"
@@ -16,7 +17,9 @@ import (
 type ReadFileHandle struct {
 	baseHandle
 	mu         sync.Mutex
-	closed     bool // set if handle has been closed
+	ctx        context.Context    // cancelled when the handle is closed
+	cancel     context.CancelFunc // cancels ctx
+	closed     bool               // set if handle has been closed
 	r          *accounting.Account
 	readCalled bool  // set if read has been called
 	size       int64 // size of the object
@@ -55,6 +58,7 @@ func newReadFileHandle(f *File) (*ReadFileHandle, error) {
 		hash:   mhash,
 		size:   nonNegative(o.Size()),
 	}
+	fh.ctx, fh.cancel = context.WithCancel(f.d.vfs.ctx)
 	return fh, nil
 }
 
@@ -65,7 +69,7 @@ func (fh *ReadFileHandle) openPending() (err error) {
 		return nil
 	}
 	o := fh.file.getObject()
-	r, err := chunkedreader.New(o, int64(fh.file.d.vfs.Opt.ChunkSize), int64(fh.file.d.vfs.Opt.ChunkSizeLimit)).Open()
+	r, err := chunkedreader.New(fh.ctx, o, int64(fh.file.d.vfs.Opt.ChunkSize), int64(fh.file.d.vfs.Opt.ChunkSizeLimit)).Open()
 	if err != nil {
 		return err
 	}
@@ -136,7 +140,7 @@ func (fh *ReadFileHandle) seek(offset int64, reopen bool) (err error) {
 		}
 		// re-open with a seek
 		o := fh.file.getObject()
-		r = chunkedreader.New(o, int64(fh.file.d.vfs.Opt.ChunkSize), int64(fh.file.d.vfs.Opt.ChunkSizeLimit))
+		r = chunkedreader.New(fh.ctx, o, int64(fh.file.d.vfs.Opt.ChunkSize), int64(fh.file.d.vfs.Opt.ChunkSizeLimit))
 		_, err := r.Seek(offset, 0)
 		if err != nil {
 			fs.Debugf(fh.remote, "ReadFileHandle.Read seek failed: %v", err)
@@ -289,7 +293,7 @@ func (fh *ReadFileHandle) checkHash() error {
 
 	o := fh.file.getObject()
 	for hashType, dstSum := range fh.hash.Sums() {
-		srcSum, err := o.Hash(hashType)
+		srcSum, err := o.Hash(fh.ctx, hashType)
 		if err != nil {
 			return err
 		}
@@ -344,6 +348,7 @@ func (fh *ReadFileHandle) close() error {
 		return ECLOSED
 	}
 	fh.closed = true
+	defer fh.cancel()
 
 	if fh.opened {
 		accounting.Stats.DoneTransferring(fh.remote, true)
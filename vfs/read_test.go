@@ -25,6 +25,25 @@ func readHandleCreate(t *testing.T, r *fstest.Run) (*VFS, *ReadFileHandle) {
 	return vfs, fh
 }
 
+func TestReadFileHandleContext(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	vfs, fh := readHandleCreate(t, r)
+
+	assert.NoError(t, fh.ctx.Err())
+	assert.Equal(t, "0123", readString(t, fh, 4))
+	require.NoError(t, fh.Close())
+	assert.Error(t, fh.ctx.Err(), "closing the handle should cancel its context")
+
+	// Shutting down the VFS cancels the handles still open
+	h, err := vfs.OpenFile("dir/file1", os.O_RDONLY, 0777)
+	require.NoError(t, err)
+	fh = h.(*ReadFileHandle)
+	vfs.Shutdown()
+	assert.Error(t, fh.ctx.Err(), "shutting down the VFS should cancel the handle context")
+	assert.Error(t, vfs.ctx.Err())
+}
+
 // read data from the string
 func readString(t *testing.T, fh *ReadFileHandle, n int) string {
 	buf := make([]byte, n)
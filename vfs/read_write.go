@@ -1,6 +1,7 @@
 package vfs
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -83,10 +84,10 @@ func newRWFileHandle(d *Dir, f *File, remote string, flags int) (fh *RWFileHandl
 }
 
 // copy an object to or from the remote while accounting for it
-func copyObj(f fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Object, err error) {
-	if operations.NeedTransfer(dst, src) {
+func copyObj(ctx context.Context, f fs.Fs, dst fs.Object, remote string, src fs.Object) (newDst fs.Object, err error) {
+	if operations.NeedTransfer(ctx, dst, src) {
 		accounting.Stats.Transferring(src.Remote())
-		newDst, err = operations.Copy(f, dst, remote, src)
+		newDst, err = operations.Copy(ctx, f, dst, remote, src)
 		accounting.Stats.DoneTransferring(src.Remote(), err == nil)
 	} else {
 		newDst = dst
@@ -111,46 +112,26 @@ func (fh *RWFileHandle) openPending(truncate bool) (err error) {
 	cacheFileOpenFlags := fh.flags
 	// if not truncating the file, need to read it first
 	if fh.flags&os.O_TRUNC == 0 && !truncate {
-		// If the remote object exists AND its cached file exists locally AND there are no
-		// other RW handles with it open, then attempt to update it.
-		if o != nil && fh.file.rwOpens() == 0 {
-			cacheObj, err := fh.d.vfs.cache.f.NewObject(fh.remote)
-			if err == nil && cacheObj != nil {
-				_, err = copyObj(fh.d.vfs.cache.f, cacheObj, fh.remote, o)
-				if err != nil {
-					return errors.Wrap(err, "open RW handle failed to update cached file")
-				}
+		// If there are no other RW handles with it open, then
+		// make sure the cache file matches the remote object.
+		// Its data is fetched as it is read.
+		if fh.file.rwOpens() == 0 {
+			err = fh.d.vfs.cache.prepare(fh.remote, o)
+			if err != nil {
+				return errors.Wrap(err, "open RW handle failed to prepare cache file")
 			}
 		}
 
 		// try to open a exising cache file
 		fd, err = os.OpenFile(fh.osPath, cacheFileOpenFlags&^os.O_CREATE, 0600)
 		if os.IsNotExist(err) {
-			// cache file does not exist, so need to fetch it if we have an object to fetch
-			// it from
-			if o != nil {
-				_, err = copyObj(fh.d.vfs.cache.f, nil, fh.remote, o)
-				if err != nil {
-					cause := errors.Cause(err)
-					if cause != fs.ErrorObjectNotFound && cause != fs.ErrorDirNotFound {
-						// return any non NotFound errors
-						return errors.Wrap(err, "open RW handle failed to cache file")
-					}
-					// continue here with err=fs.Error{Object,Dir}NotFound
-				}
-			}
-			// if err == nil, then we have cached the file successfully, otherwise err is
-			// indicating some kind of non existent file/directory either
-			// os.IsNotExist(err) or fs.Error{Object,Dir}NotFound
-			if err != nil {
-				if fh.flags&os.O_CREATE != 0 {
-					// if the object wasn't found AND O_CREATE is set then
-					// ignore error as we are about to create the file
-					fh.file.setSize(0)
-					fh.changed = true
-				} else {
-					return errors.Wrap(err, "open RW handle failed to cache file")
-				}
+			if fh.flags&os.O_CREATE != 0 {
+				// if the object wasn't found AND O_CREATE is set then
+				// ignore error as we are about to create the file
+				fh.file.setSize(0)
+				fh.changed = true
+			} else {
+				return errors.Wrap(err, "open RW handle failed to cache file")
 			}
 		} else if err != nil {
 			return errors.Wrap(err, "cache open file failed")
@@ -161,6 +142,7 @@ func (fh *RWFileHandle) openPending(truncate bool) (err error) {
 		// Set the size to 0 since we are truncating and flag we need to write it back
 		fh.file.setSize(0)
 		fh.changed = true
+		fh.d.vfs.cache.truncated(fh.remote, 0)
 		if fh.flags&os.O_CREATE == 0 && fh.file.exists() {
 			// create an empty file if it exists on the source
 			err = ioutil.WriteFile(fh.osPath, []byte{}, 0600)
@@ -294,22 +276,24 @@ func (fh *RWFileHandle) close() (err error) {
 	}
 
 	if isCopied {
-		// Transfer the temp file to the remote
-		cacheObj, err := fh.d.vfs.cache.f.NewObject(fh.remote)
+		// Make sure all of the file is in the cache
+		err = fh.d.vfs.cache.fetch(fh.remote, fh.file.getObject(), byteRange{Pos: 0, Size: fh.file.Size()})
 		if err != nil {
-			err = errors.Wrap(err, "failed to find cache file")
+			err = errors.Wrap(err, "failed to fetch file into cache")
 			fs.Errorf(fh.logPrefix(), "%v", err)
 			return err
 		}
 
-		o, err := copyObj(fh.d.vfs.f, fh.file.getObject(), fh.remote, cacheObj)
-		if err != nil {
-			err = errors.Wrap(err, "failed to transfer file from cache to remote")
-			fs.Errorf(fh.logPrefix(), "%v", err)
-			return err
+		// Queue the cache file for upload to the remote
+		delay := fh.d.vfs.Opt.WriteBack
+		item := fh.d.vfs.cache.writeBack.add(fh.remote, fh.file, delay)
+		if delay <= 0 {
+			err = fh.d.vfs.cache.writeBack.upload(item)
+			if err != nil {
+				fs.Errorf(fh.logPrefix(), "%v", err)
+				return err
+			}
 		}
-		fh.file.setObject(o)
-		fs.Debugf(o, "transferred to remote")
 	}
 
 	return nil
@@ -415,9 +399,23 @@ func (fh *RWFileHandle) readFn(read func() (int, error)) (n int, err error) {
 	return read()
 }
 
+// fetch makes sure size bytes at off are in the cache file
+//
+// call with the lock held
+func (fh *RWFileHandle) fetch(off int64, size int) error {
+	return fh.d.vfs.cache.fetch(fh.remote, fh.file.getObject(), byteRange{Pos: off, Size: int64(size)})
+}
+
 // Read bytes from the file
 func (fh *RWFileHandle) Read(b []byte) (n int, err error) {
 	return fh.readFn(func() (int, error) {
+		off, err := fh.File.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, err
+		}
+		if err = fh.fetch(off, len(b)); err != nil {
+			return 0, err
+		}
 		return fh.File.Read(b)
 	})
 }
@@ -425,6 +423,9 @@ func (fh *RWFileHandle) Read(b []byte) (n int, err error) {
 // ReadAt bytes from the file at off
 func (fh *RWFileHandle) ReadAt(b []byte, off int64) (n int, err error) {
 	return fh.readFn(func() (int, error) {
+		if err := fh.fetch(off, len(b)); err != nil {
+			return 0, err
+		}
 		return fh.File.ReadAt(b, off)
 	})
 }
@@ -445,10 +446,25 @@ func (fh *RWFileHandle) Seek(offset int64, whence int) (ret int64, err error) {
 	return fh.File.Seek(offset, whence)
 }
 
+// writeOffset returns the offset the next Write will be at
+//
+// call with the lock held
+func (fh *RWFileHandle) writeOffset() (int64, error) {
+	if fh.flags&os.O_APPEND != 0 {
+		fi, err := fh.File.Stat()
+		if err != nil {
+			return 0, err
+		}
+		return fi.Size(), nil
+	}
+	return fh.File.Seek(0, io.SeekCurrent)
+}
+
 // writeFn general purpose write call
 //
-// Pass a closure to do the actual write
-func (fh *RWFileHandle) writeFn(write func() error) (err error) {
+// Pass a closure to do the actual write which returns the offset
+// and the number of bytes written
+func (fh *RWFileHandle) writeFn(write func() (int64, int, error)) (err error) {
 	fh.mu.Lock()
 	defer fh.mu.Unlock()
 	if fh.closed {
@@ -461,41 +477,49 @@ func (fh *RWFileHandle) writeFn(write func() error) (err error) {
 		return err
 	}
 	fh.writeCalled = true
-	err = write()
-	if err != nil {
-		return err
-	}
-	fi, err := fh.File.Stat()
-	if err != nil {
-		return errors.Wrap(err, "failed to stat cache file")
+	off, n, err := write()
+	if n > 0 {
+		fi, statErr := fh.File.Stat()
+		if statErr != nil {
+			return errors.Wrap(statErr, "failed to stat cache file")
+		}
+		fh.d.vfs.cache.written(fh.remote, byteRange{Pos: off, Size: int64(n)}, fi.Size())
+		fh.file.setSize(fi.Size())
 	}
-	fh.file.setSize(fi.Size())
-	return nil
+	return err
 }
 
 // Write bytes to the file
 func (fh *RWFileHandle) Write(b []byte) (n int, err error) {
-	err = fh.writeFn(func() error {
+	err = fh.writeFn(func() (int64, int, error) {
+		off, err := fh.writeOffset()
+		if err != nil {
+			return 0, 0, err
+		}
 		n, err = fh.File.Write(b)
-		return err
+		return off, n, err
 	})
 	return n, err
 }
 
 // WriteAt bytes to the file at off
 func (fh *RWFileHandle) WriteAt(b []byte, off int64) (n int, err error) {
-	err = fh.writeFn(func() error {
+	err = fh.writeFn(func() (int64, int, error) {
 		n, err = fh.File.WriteAt(b, off)
-		return err
+		return off, n, err
 	})
 	return n, err
 }
 
 // WriteString a string to the file
 func (fh *RWFileHandle) WriteString(s string) (n int, err error) {
-	err = fh.writeFn(func() error {
+	err = fh.writeFn(func() (int64, int, error) {
+		off, err := fh.writeOffset()
+		if err != nil {
+			return 0, 0, err
+		}
 		n, err = fh.File.WriteString(s)
-		return err
+		return off, n, err
 	})
 	return n, err
 }
@@ -512,7 +536,12 @@ func (fh *RWFileHandle) Truncate(size int64) (err error) {
 	}
 	fh.changed = true
 	fh.file.setSize(size)
-	return fh.File.Truncate(size)
+	err = fh.File.Truncate(size)
+	if err != nil {
+		return err
+	}
+	fh.d.vfs.cache.truncated(fh.remote, size)
+	return nil
 }
 
 // Sync commits the current contents of the file to stable storage. Typically,
@@ -1,9 +1,11 @@
 package vfs
 
 import (
+	"context"
 	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -593,3 +595,104 @@ func TestRWFileModTimeWithOpenWriters(t *testing.T) {
 	// avoid errors because of timezone differences
 	assert.Equal(t, info.ModTime().Unix(), mtime.Unix())
 }
+
+// tests only the parts of the file read are fetched into the cache
+func TestRWFileHandleSparse(t *testing.T) {
+	r := fstest.NewRun(t)
+	opt := DefaultOpt
+	opt.CacheMode = CacheModeFull
+	opt.ReadAhead = 0
+	vfs := New(r.Fremote, &opt)
+	defer cleanup(t, r, vfs)
+
+	contents := strings.Repeat("0123456789abcdef", 3*cacheBlockSize/16)
+	file1 := r.WriteObject("dir/file1", contents, t1)
+	fstest.CheckItems(t, r.Fremote, file1)
+
+	cacheInfo := func() *itemInfo {
+		item := vfs.cache.get("dir/file1")
+		item.mu.Lock()
+		defer item.mu.Unlock()
+		return item.info
+	}
+
+	h, err := vfs.OpenFile("dir/file1", os.O_RDONLY, 0777)
+	require.NoError(t, err)
+	fh, ok := h.(*RWFileHandle)
+	require.True(t, ok)
+
+	// Read from the middle of the last block
+	buf := make([]byte, 16)
+	n, err := fh.ReadAt(buf, 2*cacheBlockSize+32)
+	require.NoError(t, err)
+	assert.Equal(t, 16, n)
+	assert.Equal(t, "0123456789abcdef", string(buf))
+
+	info := cacheInfo()
+	require.NotNil(t, info)
+	assert.Equal(t, int64(3*cacheBlockSize), info.Size)
+	assert.Equal(t, ranges{{Pos: 2 * cacheBlockSize, Size: cacheBlockSize}}, info.Rs)
+
+	// Read across the first block boundary
+	n, err = fh.ReadAt(buf, cacheBlockSize-8)
+	require.NoError(t, err)
+	assert.Equal(t, 16, n)
+	assert.Equal(t, "89abcdef01234567", string(buf))
+	assert.Equal(t, ranges{{Pos: 0, Size: 3 * cacheBlockSize}}, cacheInfo().Rs)
+
+	require.NoError(t, fh.Close())
+
+	// Check the metadata was saved
+	saved, err := vfs.cache.loadInfo("dir/file1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3*cacheBlockSize), saved.Size)
+	assert.Equal(t, ranges{{Pos: 0, Size: 3 * cacheBlockSize}}, saved.Rs)
+	assert.Equal(t, fingerprint(remoteObject(t, r, "dir/file1")), saved.Fingerprint)
+
+	// Changing the object on the remote invalidates the cache
+	file1 = r.WriteObject("dir/file1", "changed", t2)
+	fstest.CheckItems(t, r.Fremote, file1)
+	require.NoError(t, vfs.cache.prepare("dir/file1", remoteObject(t, r, "dir/file1")))
+	info = cacheInfo()
+	assert.Equal(t, int64(7), info.Size)
+	assert.Equal(t, ranges(nil), info.Rs)
+}
+
+// tests a partially cached file is uploaded in full
+func TestRWFileHandleSparseWrite(t *testing.T) {
+	r := fstest.NewRun(t)
+	opt := DefaultOpt
+	opt.CacheMode = CacheModeFull
+	opt.ReadAhead = 0
+	vfs := New(r.Fremote, &opt)
+	defer cleanup(t, r, vfs)
+
+	contents := strings.Repeat("0123456789abcdef", 2*cacheBlockSize/16)
+	file1 := r.WriteObject("file1", contents, t1)
+	fstest.CheckItems(t, r.Fremote, file1)
+
+	h, err := vfs.OpenFile("file1", os.O_RDWR, 0777)
+	require.NoError(t, err)
+	fh, ok := h.(*RWFileHandle)
+	require.True(t, ok)
+
+	n, err := fh.WriteAt([]byte("HELLO"), cacheBlockSize+3)
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+	require.NoError(t, fh.Close())
+
+	want := contents[:cacheBlockSize+3] + "HELLO" + contents[cacheBlockSize+8:]
+	in, err := remoteObject(t, r, "file1").Open(context.Background())
+	require.NoError(t, err)
+	got, err := ioutil.ReadAll(in)
+	require.NoError(t, err)
+	require.NoError(t, in.Close())
+	assert.Equal(t, want, string(got))
+}
+
+// remoteObject returns the object at remote
+func remoteObject(t *testing.T, r *fstest.Run, remote string) fs.Object {
+	o, err := r.Fremote.NewObject(context.Background(), remote)
+	require.NoError(t, err)
+	return o
+}
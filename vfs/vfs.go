@@ -47,9 +47,12 @@ var DefaultOpt = Options{
 	FilePerms:         os.FileMode(0666),
 	CacheMode:         CacheModeOff,
 	CacheMaxAge:       3600 * time.Second,
+	CacheMaxSize:      -1,
 	CachePollInterval: 60 * time.Second,
 	ChunkSize:         128 * fs.MebiByte,
 	ChunkSizeLimit:    -1,
+	ReadAhead:         4 * fs.MebiByte,
+	WriteBack:         0,
 }
 
 // Node represents either a directory (*Dir) or a file (*File)
@@ -168,15 +171,17 @@ var (
 
 // VFS represents the top level filing system
 type VFS struct {
-	f         fs.Fs
-	root      *Dir
-	Opt       Options
-	cache     *cache
-	cancel    context.CancelFunc
-	usageMu   sync.Mutex
-	usageTime time.Time
-	usage     *fs.Usage
-	pollChan  chan time.Duration
+	f           fs.Fs
+	root        *Dir
+	Opt         Options
+	ctx         context.Context    // cancelled when the VFS is shut down
+	cancel      context.CancelFunc // cancels ctx
+	cache       *cache
+	cacheCancel context.CancelFunc // stops the cache
+	usageMu     sync.Mutex
+	usageTime   time.Time
+	usage       *fs.Usage
+	pollChan    chan time.Duration
 }
 
 // Options is options for creating the vfs
@@ -196,7 +201,10 @@ type Options struct {
 	ChunkSizeLimit    fs.SizeSuffix // if > ChunkSize double the chunk size after each chunk until reached
 	CacheMode         CacheMode
 	CacheMaxAge       time.Duration
+	CacheMaxSize      fs.SizeSuffix // if >= 0 the max total size of the files in the cache
 	CachePollInterval time.Duration
+	ReadAhead         fs.SizeSuffix // extra data to fetch into the cache after each read
+	WriteBack         time.Duration // time to wait after a file is closed before uploading it - 0 to upload on close
 }
 
 // New creates a new VFS and root directory.  If opt is nil, then
@@ -206,6 +214,7 @@ func New(f fs.Fs, opt *Options) *VFS {
 	vfs := &VFS{
 		f: f,
 	}
+	vfs.ctx, vfs.cancel = context.WithCancel(context.Background())
 
 	// Make a copy of the options
 	if opt != nil {
@@ -227,7 +236,7 @@ func New(f fs.Fs, opt *Options) *VFS {
 	// Start polling function
 	if do := vfs.f.Features().ChangeNotify; do != nil {
 		vfs.pollChan = make(chan time.Duration)
-		do(vfs.root.ForgetPath, vfs.pollChan)
+		do(vfs.ctx, vfs.root.ForgetPath, vfs.pollChan)
 		vfs.pollChan <- vfs.Opt.PollInterval
 	} else {
 		fs.Infof(f, "poll-interval is not supported by this remote")
@@ -242,10 +251,10 @@ func New(f fs.Fs, opt *Options) *VFS {
 
 // SetCacheMode change the cache mode
 func (vfs *VFS) SetCacheMode(cacheMode CacheMode) {
-	vfs.Shutdown()
+	vfs.stopCache()
 	vfs.cache = nil
 	if vfs.Opt.CacheMode > CacheModeOff {
-		ctx, cancel := context.WithCancel(context.Background())
+		ctx, cancel := context.WithCancel(vfs.ctx)
 		cache, err := newCache(ctx, vfs.f, &vfs.Opt) // FIXME pass on context or get from Opt?
 		if err != nil {
 			fs.Errorf(nil, "Failed to create vfs cache - disabling: %v", err)
@@ -253,19 +262,26 @@ func (vfs *VFS) SetCacheMode(cacheMode CacheMode) {
 			cancel()
 			return
 		}
-		vfs.cancel = cancel
+		vfs.cacheCancel = cancel
 		vfs.cache = cache
 	}
 }
 
-// Shutdown stops any background go-routines
-func (vfs *VFS) Shutdown() {
-	if vfs.cancel != nil {
-		vfs.cancel()
-		vfs.cancel = nil
+// stopCache stops the background go-routines of the cache
+func (vfs *VFS) stopCache() {
+	if vfs.cacheCancel != nil {
+		vfs.cacheCancel()
+		vfs.cacheCancel = nil
 	}
 }
 
+// Shutdown stops any background go-routines and cancels any
+// operations on the remote in progress
+func (vfs *VFS) Shutdown() {
+	vfs.stopCache()
+	vfs.cancel()
+}
+
 // CleanUp deletes the contents of the on disk cache
 func (vfs *VFS) CleanUp() error {
 	if vfs.Opt.CacheMode == CacheModeOff {
@@ -291,6 +307,10 @@ func (vfs *VFS) WaitForWriters(timeout time.Duration) {
 	tick.Stop()
 	for {
 		writers := 0
+		uploads := 0
+		if vfs.cache != nil {
+			uploads = vfs.cache.writeBack.len()
+		}
 		vfs.root.walk(func(d *Dir) {
 			fs.Debugf(d.path, "Looking for writers")
 			// NB d.mu is held by walk() here
@@ -305,16 +325,21 @@ func (vfs *VFS) WaitForWriters(timeout time.Duration) {
 				}
 			}
 		})
-		if writers == 0 {
+		if writers == 0 && uploads == 0 {
 			return
 		}
-		fs.Debugf(nil, "Still %d writers active, waiting %v", writers, tickTime)
+		fs.Debugf(nil, "Still %d writers active and %d files waiting to be uploaded, waiting %v", writers, uploads, tickTime)
 		tick.Reset(tickTime)
 		select {
 		case <-tick.C:
 			break
 		case <-deadline.C:
-			fs.Errorf(nil, "Exiting even though %d writers are active after %v", writers, timeout)
+			if uploads != 0 {
+				fs.Errorf(nil, "Exiting even though %d files are waiting to be uploaded after %v - they will be uploaded when the cache is next used", uploads, timeout)
+			}
+			if writers != 0 {
+				fs.Errorf(nil, "Exiting even though %d writers are active after %v", writers, timeout)
+			}
 			return
 		}
 	}
@@ -478,7 +503,7 @@ func (vfs *VFS) Statfs() (total, used, free int64) {
 	}
 	if vfs.usageTime.IsZero() || time.Since(vfs.usageTime) >= vfs.Opt.DirCacheTime {
 		var err error
-		vfs.usage, err = doAbout()
+		vfs.usage, err = doAbout(vfs.ctx)
 		vfs.usageTime = time.Now()
 		if err != nil {
 			fs.Errorf(vfs.f, "Statfs failed: %v", err)
@@ -1,6 +1,7 @@
 package vfs
 
 import (
+	"context"
 	"io"
 	"os"
 	"sync"
@@ -10,11 +11,20 @@ import (
 	"github.com/ncw/rclone/fs/operations"
 )
 
+// writeSequentialWait is how long WriteAt will wait for earlier
+// writes to arrive if it is called with an offset beyond the current
+// one.  This lets clients which issue their writes in parallel, like
+// sftp, work without --vfs-cache-mode writes.
+var writeSequentialWait = time.Second
+
 // WriteFileHandle is an open for write handle on a File
 type WriteFileHandle struct {
 	baseHandle
 	mu          sync.Mutex
-	closed      bool // set if handle has been closed
+	cond        *sync.Cond         // cond lock for out of sequence writes
+	ctx         context.Context    // cancelled when the handle is closed
+	cancel      context.CancelFunc // cancels ctx
+	closed      bool               // set if handle has been closed
 	remote      string
 	pipeWriter  *io.PipeWriter
 	o           fs.Object
@@ -41,6 +51,8 @@ func newWriteFileHandle(d *Dir, f *File, remote string, flags int) (*WriteFileHa
 		result: make(chan error, 1),
 		file:   f,
 	}
+	fh.cond = sync.NewCond(&fh.mu)
+	fh.ctx, fh.cancel = context.WithCancel(d.vfs.ctx)
 	fh.file.addWriter(fh)
 	return fh, nil
 }
@@ -65,7 +77,7 @@ func (fh *WriteFileHandle) openPending() (err error) {
 	pipeReader, fh.pipeWriter = io.Pipe()
 	go func() {
 		// NB Rcat deals with Stats.Transferring etc
-		o, err := operations.Rcat(fh.file.d.f, fh.remote, pipeReader, time.Now())
+		o, err := operations.Rcat(fh.ctx, fh.file.d.f, fh.remote, pipeReader, time.Now())
 		if err != nil {
 			fs.Errorf(fh.remote, "WriteFileHandle.New Rcat failed: %v", err)
 		}
@@ -122,6 +134,9 @@ func (fh *WriteFileHandle) WriteAt(p []byte, off int64) (n int, err error) {
 // Implementatino of WriteAt - call with lock held
 func (fh *WriteFileHandle) writeAt(p []byte, off int64) (n int, err error) {
 	// fs.Debugf(fh.remote, "WriteFileHandle.Write len=%d", len(p))
+	if fh.offset != off {
+		fh.waitSequential(off)
+	}
 	if fh.closed {
 		fs.Errorf(fh.remote, "WriteFileHandle.Write: error: %v", EBADF)
 		return 0, ECLOSED
@@ -137,6 +152,7 @@ func (fh *WriteFileHandle) writeAt(p []byte, off int64) (n int, err error) {
 	n, err = fh.pipeWriter.Write(p)
 	fh.offset += int64(n)
 	fh.file.setSize(fh.offset)
+	fh.cond.Broadcast()
 	if err != nil {
 		fs.Errorf(fh.remote, "WriteFileHandle.Write error: %v", err)
 		return 0, err
@@ -145,6 +161,26 @@ func (fh *WriteFileHandle) writeAt(p []byte, off int64) (n int, err error) {
 	return n, nil
 }
 
+// waitSequential waits for up to writeSequentialWait for the writes
+// before off to arrive.
+//
+// Call with the lock held
+func (fh *WriteFileHandle) waitSequential(off int64) {
+	if off < fh.offset {
+		return
+	}
+	timeout := time.Now().Add(writeSequentialWait)
+	timer := time.AfterFunc(writeSequentialWait, func() {
+		fh.mu.Lock()
+		fh.cond.Broadcast()
+		fh.mu.Unlock()
+	})
+	defer timer.Stop()
+	for !fh.closed && fh.offset < off && time.Now().Before(timeout) {
+		fh.cond.Wait()
+	}
+}
+
 // Write writes len(p) bytes from p to the underlying data stream. It returns
 // the number of bytes written from p (0 <= n <= len(p)) and any error
 // encountered that caused the write to stop early. Write must return a non-nil
@@ -180,8 +216,10 @@ func (fh *WriteFileHandle) close() (err error) {
 		return ECLOSED
 	}
 	fh.closed = true
+	fh.cond.Broadcast()
 	// leave writer open until file is transferred
 	defer func() {
+		fh.cancel()
 		fh.file.delWriter(fh, false)
 		fh.file.finishWriterClose()
 	}()
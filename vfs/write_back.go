@@ -0,0 +1,348 @@
+// This deals with uploading files from the cache to the remote
+
+package vfs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/pkg/errors"
+)
+
+const (
+	writeBackMinRetryDelay = time.Second     // delay before the first retry of a failed upload
+	writeBackMaxRetryDelay = 5 * time.Minute // the retry delay doubles up to this
+	writeBackBusyDelay     = time.Second     // delay before trying again if the file is open for write
+)
+
+// writeBack is the queue of files in the cache waiting to be
+// uploaded to the remote
+type writeBack struct {
+	ctx   context.Context
+	c     *cache
+	mu    sync.Mutex                // protects items
+	items map[string]*writeBackItem // files waiting to be uploaded by name
+}
+
+// writeBackItem is a file waiting to be uploaded
+type writeBackItem struct {
+	name      string      // name of the file in the cache
+	file      *File       // the file being uploaded - may be nil
+	gen       int         // incremented each time the file is queued
+	expiry    time.Time   // when the upload is due
+	tries     int         // number of failed uploads
+	uploading bool        // set while the upload is in progress
+	err       error       // the error from the last upload
+	timer     *time.Timer // fires when the upload is due
+}
+
+// writeBackEntry describes an item in the queue for the rc
+type writeBackEntry struct {
+	Name      string  `json:"name"`
+	Size      int64   `json:"size"`
+	Expiry    float64 `json:"expiry"` // seconds until the upload is due
+	Tries     int     `json:"tries"`
+	Uploading bool    `json:"uploading"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// newWriteBack makes a new write back queue for c
+//
+// The uploads are stopped when the context is cancelled.
+func newWriteBack(ctx context.Context, c *cache) *writeBack {
+	wb := &writeBack{
+		ctx:   ctx,
+		c:     c,
+		items: make(map[string]*writeBackItem),
+	}
+	go func() {
+		<-ctx.Done()
+		wb.stop()
+	}()
+	return wb
+}
+
+// writeBackRetryDelay returns how long to wait after tries failed
+// uploads
+func writeBackRetryDelay(tries int) time.Duration {
+	delay := writeBackMinRetryDelay
+	for i := 1; i < tries && delay < writeBackMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > writeBackMaxRetryDelay {
+		delay = writeBackMaxRetryDelay
+	}
+	return delay
+}
+
+// add queues name to be uploaded after delay, returning the queued
+// item.  If delay is 0 the upload isn't scheduled and the caller
+// should call upload.
+//
+// file is the node being uploaded and may be nil.
+//
+// name should be a remote path not an osPath
+func (wb *writeBack) add(name string, file *File, delay time.Duration) *writeBackItem {
+	name = clean(name)
+	wb.c.dirty(name)
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	item := wb.items[name]
+	if item == nil {
+		item = &writeBackItem{name: name}
+		wb.items[name] = item
+		// keep the cache file until it is uploaded
+		wb.c.open(name)
+	}
+	if file != nil {
+		item.file = file
+		file.setWriteBackPending(true)
+	}
+	item.gen++
+	item.tries = 0
+	item.err = nil
+	if delay > 0 {
+		fs.Debugf(name, "vfs cache: queuing for upload in %v", delay)
+		wb._schedule(item, delay)
+	} else if item.timer != nil {
+		item.timer.Stop()
+	}
+	return item
+}
+
+// _schedule sets the upload of item to happen after delay
+//
+// call with wb.mu held
+func (wb *writeBack) _schedule(item *writeBackItem, delay time.Duration) {
+	item.expiry = time.Now().Add(delay)
+	if item.timer == nil {
+		item.timer = time.AfterFunc(delay, func() {
+			_ = wb.upload(item)
+		})
+	} else {
+		item.timer.Reset(delay)
+	}
+}
+
+// upload uploads item now, returning any error.
+//
+// If the upload fails it is retried with increasing delays up to
+// --low-level-retries times.
+func (wb *writeBack) upload(item *writeBackItem) (err error) {
+	wb.mu.Lock()
+	if wb.items[item.name] != item || item.uploading {
+		// removed from the queue or already uploading
+		wb.mu.Unlock()
+		return nil
+	}
+	if err = wb.ctx.Err(); err != nil {
+		wb.mu.Unlock()
+		return err
+	}
+	file := item.file
+	if file != nil && file.activeWriters() > 0 {
+		// The file will be queued again when the writers close
+		fs.Debugf(item.name, "vfs cache: delaying upload as file is open for write")
+		wb._schedule(item, writeBackBusyDelay)
+		wb.mu.Unlock()
+		return nil
+	}
+	item.uploading = true
+	gen := item.gen
+	wb.mu.Unlock()
+
+	o, err := wb.c.upload(wb.ctx, item.name, file)
+
+	wb.mu.Lock()
+	item.uploading = false
+	if wb.items[item.name] != item {
+		// removed while uploading
+		wb.mu.Unlock()
+		return err
+	}
+	if err == nil {
+		if item.gen != gen {
+			// queued again while uploading so leave it for
+			// the next upload
+			wb.mu.Unlock()
+			return nil
+		}
+		delete(wb.items, item.name)
+		if item.timer != nil {
+			item.timer.Stop()
+		}
+		wb.mu.Unlock()
+		if file != nil {
+			file.setWriteBackPending(false)
+			file.setObject(o)
+			file.applyPendingRename()
+		}
+		wb.c.close(item.name)
+		fs.Debugf(o, "transferred to remote")
+		return nil
+	}
+	item.tries++
+	item.err = err
+	if item.tries < fs.Config.LowLevelRetries {
+		delay := writeBackRetryDelay(item.tries)
+		fs.Errorf(item.name, "vfs cache: failed to upload try #%d, will retry in %v: %v", item.tries, delay, err)
+		wb._schedule(item, delay)
+	} else {
+		fs.Errorf(item.name, "vfs cache: failed to upload try #%d, giving up until flushed: %v", item.tries, err)
+	}
+	wb.mu.Unlock()
+	return err
+}
+
+// flush uploads name now, or all the queued files if name is "",
+// returning the last error
+func (wb *writeBack) flush(name string) (err error) {
+	var items []*writeBackItem
+	wb.mu.Lock()
+	if name == "" {
+		for _, item := range wb.items {
+			items = append(items, item)
+		}
+	} else if item := wb.items[clean(name)]; item != nil {
+		items = append(items, item)
+	}
+	wb.mu.Unlock()
+	if name != "" && len(items) == 0 {
+		return errors.Errorf("%q is not waiting to be uploaded", name)
+	}
+	for _, item := range items {
+		uploadErr := wb.upload(item)
+		if uploadErr != nil {
+			err = uploadErr
+		}
+	}
+	return err
+}
+
+// remove takes name out of the queue without uploading it
+//
+// name should be a remote path not an osPath
+func (wb *writeBack) remove(name string) {
+	name = clean(name)
+	wb.mu.Lock()
+	item := wb.items[name]
+	if item == nil {
+		wb.mu.Unlock()
+		return
+	}
+	delete(wb.items, name)
+	if item.timer != nil {
+		item.timer.Stop()
+	}
+	wb.mu.Unlock()
+	fs.Debugf(name, "vfs cache: removed from upload queue")
+	wb.c.close(name)
+}
+
+// stop stops all the scheduled uploads
+func (wb *writeBack) stop() {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	for _, item := range wb.items {
+		if item.timer != nil {
+			item.timer.Stop()
+		}
+	}
+}
+
+// len returns the number of files waiting to be uploaded
+func (wb *writeBack) len() int {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	return len(wb.items)
+}
+
+// queue returns a description of the files waiting to be uploaded
+// sorted by name
+func (wb *writeBack) queue() []writeBackEntry {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	now := time.Now()
+	entries := []writeBackEntry{}
+	for _, item := range wb.items {
+		entry := writeBackEntry{
+			Name:      item.name,
+			Size:      -1,
+			Tries:     item.tries,
+			Uploading: item.uploading,
+		}
+		if item.timer != nil {
+			entry.Expiry = item.expiry.Sub(now).Seconds()
+		}
+		if fi, err := os.Stat(wb.c.toOSPath(item.name)); err == nil {
+			entry.Size = fi.Size()
+		}
+		if item.err != nil {
+			entry.Error = item.err.Error()
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// upload copies the cache file for name to the remote, returning the
+// new object.  file is the node being uploaded and may be nil.
+//
+// name should be a remote path not an osPath
+func (c *cache) upload(ctx context.Context, name string, file *File) (fs.Object, error) {
+	cacheObj, err := c.f.NewObject(ctx, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find cache file")
+	}
+	var dst fs.Object
+	if file != nil {
+		dst = file.getObject()
+	} else {
+		dst, _ = c.fremote.NewObject(ctx, name)
+	}
+	o, err := copyObj(ctx, c.fremote, dst, name, cacheObj)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to transfer file from cache to remote")
+	}
+	c.setObject(name, o)
+	return o, nil
+}
+
+// resumeUploads queues the files which were waiting to be uploaded
+// when the cache was last used
+func (c *cache) resumeUploads() {
+	err := filepath.Walk(c.metaRoot, func(osPath string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+		name, err := filepath.Rel(c.metaRoot, osPath)
+		if err != nil {
+			return errors.Wrap(err, "filepath.Rel failed in resumeUploads")
+		}
+		name = filepath.ToSlash(name)
+		info, err := c.loadInfo(name)
+		if err != nil {
+			fs.Errorf(name, "Ignoring cache metadata: %v", err)
+			return nil
+		}
+		if info.Dirty {
+			fs.Infof(name, "vfs cache: queuing upload left over from a previous run")
+			c.writeBack.add(name, nil, writeBackMinRetryDelay)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		fs.Errorf(nil, "vfs cache: failed to look for uploads to resume: %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package vfs
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ncw/rclone/fs"
+	"github.com/ncw/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Make a VFS which uploads files an hour after they are closed
+func newWriteBackVFS(t *testing.T, r *fstest.Run) *VFS {
+	opt := DefaultOpt
+	opt.CacheMode = CacheModeWrites
+	opt.WriteBack = time.Hour
+	return New(r.Fremote, &opt)
+}
+
+// write contents to name in the vfs
+func writeBackWriteFile(t *testing.T, vfs *VFS, name, contents string) {
+	h, err := vfs.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0777)
+	require.NoError(t, err)
+	_, err = h.WriteString(contents)
+	require.NoError(t, err)
+	require.NoError(t, h.Close())
+}
+
+// read name from the remote returning an error if not found
+func writeBackReadRemote(t *testing.T, r *fstest.Run, name string) (string, error) {
+	o, err := r.Fremote.NewObject(context.Background(), name)
+	if err != nil {
+		return "", err
+	}
+	in, err := o.Open(context.Background())
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(in)
+	require.NoError(t, err)
+	require.NoError(t, in.Close())
+	return string(data), nil
+}
+
+func TestWriteBackRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Second, writeBackRetryDelay(0))
+	assert.Equal(t, time.Second, writeBackRetryDelay(1))
+	assert.Equal(t, 2*time.Second, writeBackRetryDelay(2))
+	assert.Equal(t, 4*time.Second, writeBackRetryDelay(3))
+	assert.Equal(t, 256*time.Second, writeBackRetryDelay(9))
+	assert.Equal(t, writeBackMaxRetryDelay, writeBackRetryDelay(10))
+	assert.Equal(t, writeBackMaxRetryDelay, writeBackRetryDelay(1000))
+}
+
+func TestWriteBackDelayed(t *testing.T) {
+	r := fstest.NewRun(t)
+	vfs := newWriteBackVFS(t, r)
+	defer cleanup(t, r, vfs)
+
+	writeBackWriteFile(t, vfs, "file1", "hello")
+
+	// Not uploaded yet
+	_, err := writeBackReadRemote(t, r, "file1")
+	assert.Equal(t, fs.ErrorObjectNotFound, err)
+	queue := vfs.cache.writeBack.queue()
+	require.Equal(t, 1, len(queue))
+	assert.Equal(t, "file1", queue[0].Name)
+	assert.Equal(t, int64(5), queue[0].Size)
+	assert.True(t, queue[0].Expiry > 3500)
+	assert.Equal(t, 1, vfs.cache.opens("file1"))
+
+	// But visible in the VFS and read from the cache
+	node, err := vfs.Stat("file1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), node.Size())
+	h, err := vfs.OpenFile("file1", os.O_RDONLY, 0)
+	require.NoError(t, err)
+	_, ok := h.(*RWFileHandle)
+	assert.True(t, ok)
+	data, err := ioutil.ReadAll(h)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	require.NoError(t, h.Close())
+
+	// The pending state is saved
+	info, err := vfs.cache.loadInfo("file1")
+	require.NoError(t, err)
+	assert.True(t, info.Dirty)
+
+	// Writing again resets the upload
+	writeBackWriteFile(t, vfs, "file1", "hello again")
+	assert.Equal(t, 1, vfs.cache.writeBack.len())
+
+	// Flush uploads it
+	assert.Error(t, vfs.cache.writeBack.flush("potato"))
+	require.NoError(t, vfs.cache.writeBack.flush("file1"))
+	got, err := writeBackReadRemote(t, r, "file1")
+	require.NoError(t, err)
+	assert.Equal(t, "hello again", got)
+	assert.Equal(t, 0, vfs.cache.writeBack.len())
+	assert.Equal(t, 0, vfs.cache.opens("file1"))
+	info, err = vfs.cache.loadInfo("file1")
+	require.NoError(t, err)
+	assert.False(t, info.Dirty)
+
+	node, err = vfs.Stat("file1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), node.Size())
+}
+
+func TestWriteBackRemove(t *testing.T) {
+	r := fstest.NewRun(t)
+	vfs := newWriteBackVFS(t, r)
+	defer cleanup(t, r, vfs)
+
+	writeBackWriteFile(t, vfs, "file1", "hello")
+	assert.Equal(t, 1, vfs.cache.writeBack.len())
+
+	node, err := vfs.Stat("file1")
+	require.NoError(t, err)
+	require.NoError(t, node.Remove())
+	assert.Equal(t, 0, vfs.cache.writeBack.len())
+	assert.Equal(t, 0, vfs.cache.opens("file1"))
+
+	require.NoError(t, vfs.cache.writeBack.flush(""))
+	_, err = writeBackReadRemote(t, r, "file1")
+	assert.Equal(t, fs.ErrorObjectNotFound, err)
+}
+
+func TestWriteBackResume(t *testing.T) {
+	r := fstest.NewRun(t)
+	vfs := newWriteBackVFS(t, r)
+
+	writeBackWriteFile(t, vfs, "file1", "hello")
+	assert.Equal(t, 1, vfs.cache.writeBack.len())
+
+	// Stop without uploading
+	vfs.Shutdown()
+	_, err := writeBackReadRemote(t, r, "file1")
+	assert.Equal(t, fs.ErrorObjectNotFound, err)
+
+	// Starting again picks up the pending upload
+	vfs = newWriteBackVFS(t, r)
+	defer cleanup(t, r, vfs)
+	queue := vfs.cache.writeBack.queue()
+	require.Equal(t, 1, len(queue))
+	assert.Equal(t, "file1", queue[0].Name)
+	assert.True(t, queue[0].Expiry <= writeBackMinRetryDelay.Seconds())
+
+	require.NoError(t, vfs.cache.writeBack.flush(""))
+	got, err := writeBackReadRemote(t, r, "file1")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", got)
+	assert.Equal(t, 0, vfs.cache.writeBack.len())
+}
+
+func TestWriteBackTimer(t *testing.T) {
+	r := fstest.NewRun(t)
+	opt := DefaultOpt
+	opt.CacheMode = CacheModeWrites
+	opt.WriteBack = 10 * time.Millisecond
+	vfs := New(r.Fremote, &opt)
+	defer cleanup(t, r, vfs)
+
+	writeBackWriteFile(t, vfs, "file1", "hello")
+
+	for i := 0; i < 100 && vfs.cache.writeBack.len() != 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 0, vfs.cache.writeBack.len())
+	got, err := writeBackReadRemote(t, r, "file1")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", got)
+}
@@ -170,6 +170,39 @@ func TestWriteFileHandleWriteAt(t *testing.T) {
 	fstest.CheckListingWithPrecision(t, r.Fremote, []fstest.Item{file1}, []string{}, fs.ModTimeNotSupported)
 }
 
+func TestWriteFileHandleWriteAtOutOfOrder(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	vfs, fh := writeHandleCreate(t, r)
+
+	// Write the second block first - it should wait for the first
+	done := make(chan error)
+	go func() {
+		_, err := fh.WriteAt([]byte(" world"), 5)
+		done <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+	n, err := fh.WriteAt([]byte("hello"), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.NoError(t, <-done)
+	assert.Equal(t, int64(11), fh.offset)
+
+	// Check a write which never gets filled in times out
+	oldWait := writeSequentialWait
+	writeSequentialWait = 10 * time.Millisecond
+	defer func() { writeSequentialWait = oldWait }()
+	n, err = fh.WriteAt([]byte("hello"), 100)
+	assert.Equal(t, ESPIPE, err)
+	assert.Equal(t, 0, n)
+
+	assert.NoError(t, fh.Close())
+
+	root, err := vfs.Root()
+	require.NoError(t, err)
+	checkListing(t, root, []string{"file1,11,false"})
+}
+
 func TestWriteFileHandleFlush(t *testing.T) {
 	r := fstest.NewRun(t)
 	defer r.Finalise()